@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"sync"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+// Common interface for the streaming agent RPCs running a command for a segment
+type segmentCommandSender interface {
+	Send(*idl.SegmentCommandReply) error
+}
+
+/*
+commandStream forwards the output of the command run by a streaming RPC to the
+hub as it is written. The command writes its stdout and stderr concurrently,
+so the replies are serialised. A nil commandStream, as used by the unary RPCs,
+drops everything.
+*/
+type commandStream struct {
+	mutex  sync.Mutex
+	sender segmentCommandSender
+}
+
+func newCommandStream(sender segmentCommandSender) *commandStream {
+	return &commandStream{sender: sender}
+}
+
+func (c *commandStream) send(reply *idl.SegmentCommandReply) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	err := c.sender.Send(reply)
+	if err != nil {
+		gplog.Debug("unable to stream reply %q: %s", reply, err)
+	}
+}
+
+// output forwards a line written by the command, tagged with the stream it was written to
+func (c *commandStream) output(stderr bool, line string) {
+	stream := idl.OutputStream_STDOUT
+	if stderr {
+		stream = idl.OutputStream_STDERR
+	}
+
+	c.send(&idl.SegmentCommandReply{
+		Message: &idl.SegmentCommandReply_Output{
+			Output: &idl.OutputLine{Stream: stream, Line: line},
+		},
+	})
+}
+
+// progress forwards the amount of data copied so far by the command, in bytes
+func (c *commandStream) progress(done int64, total int64) {
+	c.send(&idl.SegmentCommandReply{
+		Message: &idl.SegmentCommandReply_Progress{
+			Progress: &idl.TransferProgress{Done: done, Total: total},
+		},
+	})
+}
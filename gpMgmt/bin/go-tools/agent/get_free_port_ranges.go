@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+/*
+GetFreePortRanges returns the ranges of the ports between the min and max port
+of the request which are free on all the addresses of the host, the same way
+ValidatePortsFn checks the ports of the segments before creating them.
+*/
+func (s *Server) GetFreePortRanges(ctx context.Context, req *idl.GetFreePortRangesRequest) (*idl.GetFreePortRangesReply, error) {
+	if req.MinPort < 1 || req.MaxPort > 65535 || req.MinPort > req.MaxPort {
+		return &idl.GetFreePortRangesReply{}, utils.LogAndReturnError(fmt.Errorf("invalid port range %d-%d", req.MinPort, req.MaxPort))
+	}
+
+	ipList, err := utils.GetAllAddresses()
+	if err != nil {
+		return &idl.GetFreePortRangesReply{}, utils.LogAndReturnError(err)
+	}
+
+	var ranges []*idl.PortRange
+	for port := req.MinPort; port <= req.MaxPort; port++ {
+		if !isPortFree(ipList, port) {
+			continue
+		}
+
+		if len(ranges) > 0 && ranges[len(ranges)-1].End == port-1 {
+			ranges[len(ranges)-1].End = port
+		} else {
+			ranges = append(ranges, &idl.PortRange{Start: port, End: port})
+		}
+	}
+
+	return &idl.GetFreePortRangesReply{Ranges: ranges}, nil
+}
+
+func isPortFree(ipList []string, port int32) bool {
+	for _, ip := range ipList {
+		free, _ := utils.CheckIfPortFree(ip, strconv.Itoa(int(port)))
+		if !free {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,55 @@
+package agent_test
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/agent"
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+func TestGetFreePortRanges(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	agentServer := agent.New(agent.Config{
+		GpHome: "gpHome",
+	})
+
+	t.Run("returns the ports free on all the addresses of the host", func(t *testing.T) {
+		listener, err := net.Listen("tcp", ":0")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		port := int32(listener.Addr().(*net.TCPAddr).Port)
+
+		reply, err := agentServer.GetFreePortRanges(context.Background(), &idl.GetFreePortRangesRequest{MinPort: port, MaxPort: port})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(reply.Ranges) != 0 {
+			t.Fatalf("got %v, want no free port", reply.Ranges)
+		}
+
+		listener.Close()
+		reply, err = agentServer.GetFreePortRanges(context.Background(), &idl.GetFreePortRangesRequest{MinPort: port, MaxPort: port})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []*idl.PortRange{{Start: port, End: port}}
+		if len(reply.Ranges) != 1 || !reflect.DeepEqual([]int32{reply.Ranges[0].Start, reply.Ranges[0].End}, []int32{expected[0].Start, expected[0].End}) {
+			t.Fatalf("got %v, want %v", reply.Ranges, expected)
+		}
+	})
+
+	t.Run("errors out on an invalid port range", func(t *testing.T) {
+		_, err := agentServer.GetFreePortRanges(context.Background(), &idl.GetFreePortRangesRequest{MinPort: 7000, MaxPort: 6000})
+		expected := "invalid port range 7000-6000"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
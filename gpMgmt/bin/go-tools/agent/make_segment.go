@@ -17,6 +17,15 @@ import (
 // configuration from the MakeSegmentRequest. It calls initdb and updates the
 // necessary configuration files.
 func (s *Server) MakeSegment(ctx context.Context, request *idl.MakeSegmentRequest) (*idl.MakeSegmentReply, error) {
+	return &idl.MakeSegmentReply{}, s.makeSegment(ctx, request, nil)
+}
+
+// MakeSegmentStream is the variant of MakeSegment which streams the output of initdb to the hub as it runs.
+func (s *Server) MakeSegmentStream(request *idl.MakeSegmentRequest, stream idl.Agent_MakeSegmentStreamServer) error {
+	return s.makeSegment(stream.Context(), request, newCommandStream(stream))
+}
+
+func (s *Server) makeSegment(ctx context.Context, request *idl.MakeSegmentRequest, stream *commandStream) error {
 	dataDirectory := request.Segment.DataDirectory
 	locale := request.Locale
 
@@ -34,9 +43,9 @@ func (s *Server) MakeSegment(ctx context.Context, request *idl.MakeSegmentReques
 		LcTime:        locale.LcTime,
 		DataChecksums: request.DataChecksums,
 	}
-	out, err := utils.RunGpCommand(&initdbOptions, s.GpHome)
+	out, err := utils.RunGpCommandAndStreamOutput(ctx, &initdbOptions, s.GpHome, stream.output)
 	if err != nil {
-		return &idl.MakeSegmentReply{}, utils.LogAndReturnError(fmt.Errorf("executing initdb: %s, %w", out, err))
+		return utils.LogAndReturnError(fmt.Errorf("executing initdb: %s, %w", out, err))
 	}
 
 	configParams := make(map[string]string)
@@ -50,12 +59,12 @@ func (s *Server) MakeSegment(ctx context.Context, request *idl.MakeSegmentReques
 
 	err = postgres.UpdatePostgresqlConf(dataDirectory, configParams, false)
 	if err != nil {
-		return &idl.MakeSegmentReply{}, utils.LogAndReturnError(fmt.Errorf("updating postgresql.conf: %w", err))
+		return utils.LogAndReturnError(fmt.Errorf("updating postgresql.conf: %w", err))
 	}
 
 	err = postgres.UpdatePostgresInternalConf(dataDirectory, int(request.Segment.Dbid))
 	if err != nil {
-		return &idl.MakeSegmentReply{}, utils.LogAndReturnError(fmt.Errorf("creating internal.auto.conf: %w", err))
+		return utils.LogAndReturnError(fmt.Errorf("creating internal.auto.conf: %w", err))
 	}
 
 	var addrs []string
@@ -64,7 +73,7 @@ func (s *Server) MakeSegment(ctx context.Context, request *idl.MakeSegmentReques
 	} else {
 		hostAddrs, err := utils.GetHostAddrsNoLoopback()
 		if err != nil {
-			return &idl.MakeSegmentReply{}, utils.LogAndReturnError(err)
+			return utils.LogAndReturnError(err)
 		}
 
 		addrs = append(addrs, hostAddrs...)
@@ -76,10 +85,10 @@ func (s *Server) MakeSegment(ctx context.Context, request *idl.MakeSegmentReques
 		err = postgres.UpdateSegmentPgHbaConf(dataDirectory, addrs, false, request.CoordinatorAddrs...)
 	}
 	if err != nil {
-		return &idl.MakeSegmentReply{}, utils.LogAndReturnError(fmt.Errorf("updating pg_hba.conf: %w", err))
+		return utils.LogAndReturnError(fmt.Errorf("updating pg_hba.conf: %w", err))
 	}
 
 	gplog.Debug("Successfully created segment with data directory %q", dataDirectory)
 
-	return &idl.MakeSegmentReply{}, nil
+	return nil
 }
@@ -16,6 +16,15 @@ import (
 // It redirects its output to a file, which is cleaned up if the command executes successfully.
 // Additionally, it manages the removal of any previously created replication slots if a new one needs to be created.
 func (s *Server) PgBasebackup(ctx context.Context, req *idl.PgBasebackupRequest) (*idl.PgBasebackupResponse, error) {
+	return &idl.PgBasebackupResponse{}, s.pgBasebackup(ctx, req, nil)
+}
+
+// PgBasebackupStream is the variant of PgBasebackup which streams the output and the progress of pg_basebackup to the hub as it runs.
+func (s *Server) PgBasebackupStream(req *idl.PgBasebackupRequest, stream idl.Agent_PgBasebackupStreamServer) error {
+	return s.pgBasebackup(stream.Context(), req, newCommandStream(stream))
+}
+
+func (s *Server) pgBasebackup(ctx context.Context, req *idl.PgBasebackupRequest, stream *commandStream) error {
 	pgBasebackupCmd := &postgres.PgBasebackup{
 		TargetDir:           req.TargetDir,
 		SourceHost:          req.SourceHost,
@@ -32,18 +41,27 @@ func (s *Server) PgBasebackup(ctx context.Context, req *idl.PgBasebackupRequest)
 		// Drop any previously created slot to avoid error when creating a new slot with the same name.
 		err := postgres.DropSlotIfExists(pgBasebackupCmd.SourceHost, pgBasebackupCmd.SourcePort, pgBasebackupCmd.ReplicationSlotName)
 		if err != nil {
-			return &idl.PgBasebackupResponse{}, fmt.Errorf("failed to drop replication slot %s: %w", pgBasebackupCmd.ReplicationSlotName, err)
+			return fmt.Errorf("failed to drop replication slot %s: %w", pgBasebackupCmd.ReplicationSlotName, err)
 		}
 	}
 
 	// TODO Check if the directory is empty if ForceOverwrite is false
 
 	pgBasebackupLog := filepath.Join(s.LogDir, fmt.Sprintf("pg_basebackup.%s.dbid%d.out", time.Now().Format("20060102_150405"), req.TargetDbid))
-	out, err := utils.RunGpCommandAndRedirectOutput(pgBasebackupCmd, s.GpHome, pgBasebackupLog)
+	output := func(stderr bool, line string) {
+		// the progress is reported on stderr, once per second
+		if done, total, ok := postgres.ParsePgBasebackupProgress(line); ok {
+			stream.progress(done, total)
+			return
+		}
+
+		stream.output(stderr, line)
+	}
+	out, err := utils.RunGpCommandAndStreamOutput(ctx, pgBasebackupCmd, s.GpHome, output, pgBasebackupLog)
 	if err != nil {
-		return &idl.PgBasebackupResponse{}, fmt.Errorf("executing pg_basebackup: %s, logfile: %s, %w", out, pgBasebackupLog, err)
+		return fmt.Errorf("executing pg_basebackup: %s, logfile: %s, %w", out, pgBasebackupLog, err)
 	}
 	os.Remove(pgBasebackupLog)
 
-	return &idl.PgBasebackupResponse{}, nil
+	return nil
 }
@@ -20,10 +20,34 @@ import (
 	"github.com/greenplum-db/gpdb/gp/testutils/exectest"
 	"github.com/greenplum-db/gpdb/gp/utils"
 	"github.com/greenplum-db/gpdb/gp/utils/postgres"
+	"google.golang.org/grpc"
 )
 
 func init() {
-	exectest.RegisterMains()
+	exectest.RegisterMains(PgBasebackupWithProgress)
+}
+
+// PgBasebackupWithProgress writes its output like pg_basebackup --progress --verbose does
+func PgBasebackupWithProgress() {
+	os.Stderr.WriteString("pg_basebackup: initiating base backup, waiting for checkpoint to complete\n")
+	os.Stderr.WriteString("  1024/4096 kB (25%), 0/1 tablespace (/mirror/gpseg0/base/1/1249   )\r")
+	os.Stderr.WriteString("  4096/4096 kB (100%), 1/1 tablespace                                \n")
+	os.Stdout.WriteString("done")
+}
+
+type segmentCommandStream struct {
+	grpc.ServerStream
+	replies []*idl.SegmentCommandReply
+}
+
+func (s *segmentCommandStream) Send(reply *idl.SegmentCommandReply) error {
+	s.replies = append(s.replies, reply)
+
+	return nil
+}
+
+func (s *segmentCommandStream) Context() context.Context {
+	return context.Background()
 }
 
 func TestPgBasebackup(t *testing.T) {
@@ -165,3 +189,75 @@ func TestPgBasebackup(t *testing.T) {
 		}
 	})
 }
+
+func TestPgBasebackupStream(t *testing.T) {
+	testhelper.SetupTestLogger()
+	tempDir := t.TempDir()
+
+	agentServer := agent.New(agent.Config{
+		GpHome: "gpHome",
+		LogDir: tempDir,
+	})
+
+	request := &idl.PgBasebackupRequest{
+		TargetDir:  "/mirror/gpseg0",
+		SourceHost: "sdw1",
+		SourcePort: 1234,
+		TargetDbid: 1,
+	}
+
+	t.Run("streams the output and the progress of pg_basebackup", func(t *testing.T) {
+		utils.System.ExecCommand = exectest.NewCommand(PgBasebackupWithProgress)
+		defer utils.ResetSystemFunctions()
+
+		stream := &segmentCommandStream{}
+		err := agentServer.PgBasebackupStream(request, stream)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// the output of stdout and stderr may be interleaved
+		var output []*idl.OutputLine
+		var progress []*idl.TransferProgress
+		for _, reply := range stream.replies {
+			switch msg := reply.Message.(type) {
+			case *idl.SegmentCommandReply_Output:
+				output = append(output, msg.Output)
+			case *idl.SegmentCommandReply_Progress:
+				progress = append(progress, msg.Progress)
+			}
+		}
+
+		expectedProgress := []*idl.TransferProgress{
+			{Done: 1024 * 1024, Total: 4096 * 1024},
+			{Done: 4096 * 1024, Total: 4096 * 1024},
+		}
+		if !reflect.DeepEqual(progress, expectedProgress) {
+			t.Fatalf("got %v, want %v", progress, expectedProgress)
+		}
+
+		expectedOutput := map[idl.OutputStream]string{
+			idl.OutputStream_STDERR: "pg_basebackup: initiating base backup, waiting for checkpoint to complete",
+			idl.OutputStream_STDOUT: "done",
+		}
+		if len(output) != len(expectedOutput) {
+			t.Fatalf("got %v, want %v", output, expectedOutput)
+		}
+		for _, line := range output {
+			if line.Line != expectedOutput[line.Stream] {
+				t.Fatalf("got %q on %s, want %q", line.Line, line.Stream, expectedOutput[line.Stream])
+			}
+		}
+	})
+
+	t.Run("errors out when fails to execute pg_basebackup", func(t *testing.T) {
+		utils.System.ExecCommand = exectest.NewCommand(exectest.Failure)
+		defer utils.ResetSystemFunctions()
+
+		err := agentServer.PgBasebackupStream(request, &segmentCommandStream{})
+		var expectedErr *exec.ExitError
+		if !errors.As(err, &expectedErr) {
+			t.Fatalf("got %T, want %T", err, expectedErr)
+		}
+	})
+}
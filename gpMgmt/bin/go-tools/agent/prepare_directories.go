@@ -0,0 +1,180 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+var (
+	GetMountPoint = GetMountPointFn
+)
+
+/*
+PrepareDirectories creates the missing parents of the data directories with
+mode 0700, and checks that each parent is owned by the user or its group, is
+writable and keeps the 0700 permissions postgres needs on a data directory.
+It returns the mount point and filesystem type of each data directory, and
+refuses the ones on a filesystem of the refused list of the request.
+*/
+func (s *Server) PrepareDirectories(ctx context.Context, request *idl.PrepareDirectoriesRequest) (*idl.PrepareDirectoriesReply, error) {
+	gplog.Debug("Starting PrepareDirectories for request:%v", request)
+
+	var directories []*idl.DirectoryInfo
+	for _, dir := range request.DataDirectories {
+		parent := filepath.Dir(filepath.Clean(dir))
+		err := prepareParentDirectory(parent)
+		if err != nil {
+			return &idl.PrepareDirectoriesReply{}, utils.LogAndReturnError(fmt.Errorf("preparing the parent of data directory %s: %w", dir, err))
+		}
+
+		mountPoint, filesystem, err := GetMountPoint(parent)
+		if err != nil {
+			return &idl.PrepareDirectoriesReply{}, utils.LogAndReturnError(fmt.Errorf("getting the mount point of data directory %s: %w", dir, err))
+		}
+		if isRefusedFilesystem(filesystem, request.RefusedFilesystems) {
+			return &idl.PrepareDirectoriesReply{}, utils.LogAndReturnError(fmt.Errorf("data directory %s is on a refused %s filesystem mounted on %s", dir, filesystem, mountPoint))
+		}
+
+		gplog.Debug("Data directory %s is on the %s filesystem mounted on %s", dir, filesystem, mountPoint)
+		directories = append(directories, &idl.DirectoryInfo{
+			DataDirectory: dir,
+			MountPoint:    mountPoint,
+			Filesystem:    filesystem,
+		})
+	}
+
+	return &idl.PrepareDirectoriesReply{Directories: directories}, nil
+}
+
+func prepareParentDirectory(parent string) error {
+	err := utils.System.MkdirAll(parent, 0700)
+	if err != nil {
+		return err
+	}
+
+	fileInfo, err := utils.System.Stat(parent)
+	if err != nil {
+		return err
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("%s is not a directory", parent)
+	}
+
+	err = CheckFileOwnerGroup(parent, fileInfo)
+	if err != nil {
+		return err
+	}
+
+	// Create a directory the way initdb does to check that the parent is
+	// writable and that its filesystem keeps the permissions
+	probe, err := utils.System.MkdirTemp(parent, ".gp-prepare-")
+	if err != nil {
+		return fmt.Errorf("directory %s is not writable: %w", parent, err)
+	}
+	defer utils.System.RemoveAll(probe)
+
+	err = utils.System.Chmod(probe, 0700)
+	if err != nil {
+		return fmt.Errorf("setting the permissions of %s: %w", probe, err)
+	}
+	probeInfo, err := utils.System.Stat(probe)
+	if err != nil {
+		return err
+	}
+	if probeInfo.Mode().Perm() != 0700 {
+		return fmt.Errorf("the filesystem of %s does not support the permissions of a data directory, got %s instead of %s", parent, probeInfo.Mode().Perm(), os.FileMode(0700))
+	}
+
+	return nil
+}
+
+/*
+GetMountPointFn returns the mount point and filesystem type of the path, once
+its symlinks are resolved, from the mounts of the host.
+*/
+func GetMountPointFn(path string) (string, string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	contents, err := utils.System.ReadFile(constants.MountsFilepath)
+	if err != nil {
+		return "", "", err
+	}
+
+	mountPoint, filesystem, ok := FindMountPoint(string(contents), resolved)
+	if !ok {
+		return "", "", fmt.Errorf("no mount point found for %s in %s", resolved, constants.MountsFilepath)
+	}
+
+	return mountPoint, filesystem, nil
+}
+
+/*
+FindMountPoint returns the longest mount point of the mounts, in the format of
+/proc/self/mounts, which contains the absolute path, and its filesystem type.
+*/
+func FindMountPoint(mounts string, path string) (string, string, bool) {
+	var mountPoint, filesystem string
+	for _, line := range strings.Split(mounts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		mount := unescapeMountField(fields[1])
+		if !isWithin(path, mount) || len(mount) < len(mountPoint) {
+			continue
+		}
+		// Later mounts on the same mount point hide the earlier ones
+		mountPoint, filesystem = mount, fields[2]
+	}
+
+	return mountPoint, filesystem, mountPoint != ""
+}
+
+func isWithin(path string, mountPoint string) bool {
+	if mountPoint == "/" {
+		return strings.HasPrefix(path, "/")
+	}
+
+	return path == mountPoint || strings.HasPrefix(path, mountPoint+"/")
+}
+
+// unescapeMountField decodes the octal escapes of the spaces, tabs, newlines and backslashes of a mounts field
+func unescapeMountField(field string) string {
+	var builder strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if code, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				builder.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		builder.WriteByte(field[i])
+	}
+
+	return builder.String()
+}
+
+// isRefusedFilesystem tells if the filesystem is refused, nfs covering all its versions
+func isRefusedFilesystem(filesystem string, refused []string) bool {
+	for _, fs := range refused {
+		if filesystem == fs || (fs == "nfs" && strings.HasPrefix(filesystem, "nfs")) {
+			return true
+		}
+	}
+
+	return false
+}
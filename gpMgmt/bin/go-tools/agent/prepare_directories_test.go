@@ -0,0 +1,243 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/agent"
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+func TestPrepareDirectories(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	agentServer := agent.New(agent.Config{
+		GpHome: "gpHome",
+	})
+
+	t.Run("creates the missing parents and returns the mount points", func(t *testing.T) {
+		defer resetAgentFunctions()
+
+		var mountPaths []string
+		agent.GetMountPoint = func(path string) (string, string, error) {
+			mountPaths = append(mountPaths, path)
+			return "/data", "xfs", nil
+		}
+
+		root := t.TempDir()
+		dataDir := filepath.Join(root, "primary", "gpseg0")
+		reply, err := agentServer.PrepareDirectories(context.Background(), &idl.PrepareDirectoriesRequest{
+			DataDirectories:    []string{dataDir},
+			RefusedFilesystems: []string{"nfs", "tmpfs"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		parent := filepath.Join(root, "primary")
+		info, err := os.Stat(parent)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Mode().Perm() != 0700 {
+			t.Fatalf("got %s, want %s", info.Mode().Perm(), os.FileMode(0700))
+		}
+
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Fatalf("got %v, want the parent to be left empty", entries)
+		}
+
+		if !reflect.DeepEqual(mountPaths, []string{parent}) {
+			t.Fatalf("got %v, want %v", mountPaths, []string{parent})
+		}
+
+		expected := []*idl.DirectoryInfo{{DataDirectory: dataDir, MountPoint: "/data", Filesystem: "xfs"}}
+		if len(reply.Directories) != 1 || reply.Directories[0].DataDirectory != expected[0].DataDirectory ||
+			reply.Directories[0].MountPoint != expected[0].MountPoint || reply.Directories[0].Filesystem != expected[0].Filesystem {
+			t.Fatalf("got %v, want %v", reply.Directories, expected)
+		}
+	})
+
+	t.Run("errors out when the parent is not owned by the user", func(t *testing.T) {
+		defer resetAgentFunctions()
+
+		expectedErr := errors.New("not owned")
+		agent.CheckFileOwnerGroup = func(filePath string, fileInfo os.FileInfo) error {
+			return expectedErr
+		}
+
+		dataDir := filepath.Join(t.TempDir(), "gpseg0")
+		_, err := agentServer.PrepareDirectories(context.Background(), &idl.PrepareDirectoriesRequest{DataDirectories: []string{dataDir}})
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+	})
+
+	t.Run("errors out when the parent is not a directory", func(t *testing.T) {
+		defer resetAgentFunctions()
+
+		parent := filepath.Join(t.TempDir(), "file")
+		err := os.WriteFile(parent, nil, 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = agentServer.PrepareDirectories(context.Background(), &idl.PrepareDirectoriesRequest{DataDirectories: []string{filepath.Join(parent, "gpseg0")}})
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("errors out when the parent is not writable", func(t *testing.T) {
+		defer resetAgentFunctions()
+
+		expectedErr := errors.New("permission denied")
+		utils.System.MkdirTemp = func(dir string, pattern string) (string, error) {
+			return "", expectedErr
+		}
+
+		dataDir := filepath.Join(t.TempDir(), "gpseg0")
+		_, err := agentServer.PrepareDirectories(context.Background(), &idl.PrepareDirectoriesRequest{DataDirectories: []string{dataDir}})
+		expected := "directory " + filepath.Dir(dataDir) + " is not writable"
+		if !errors.Is(err, expectedErr) || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when the filesystem does not keep the permissions", func(t *testing.T) {
+		defer resetAgentFunctions()
+
+		var removed []string
+		utils.System.MkdirTemp = func(dir string, pattern string) (string, error) {
+			probe := filepath.Join(dir, pattern+"probe")
+			return probe, os.Mkdir(probe, 0755)
+		}
+		utils.System.Chmod = func(name string, mode os.FileMode) error {
+			return nil
+		}
+		utils.System.RemoveAll = func(path string) error {
+			removed = append(removed, path)
+			return os.RemoveAll(path)
+		}
+
+		dataDir := filepath.Join(t.TempDir(), "gpseg0")
+		_, err := agentServer.PrepareDirectories(context.Background(), &idl.PrepareDirectoriesRequest{DataDirectories: []string{dataDir}})
+		expected := "the filesystem of " + filepath.Dir(dataDir) + " does not support the permissions of a data directory"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		if len(removed) != 1 {
+			t.Fatalf("got %v, want the probe directory to be removed", removed)
+		}
+	})
+
+	t.Run("errors out when the data directory is on a refused filesystem", func(t *testing.T) {
+		defer resetAgentFunctions()
+
+		agent.GetMountPoint = func(path string) (string, string, error) {
+			return "/data", "nfs4", nil
+		}
+
+		dataDir := filepath.Join(t.TempDir(), "gpseg0")
+		_, err := agentServer.PrepareDirectories(context.Background(), &idl.PrepareDirectoriesRequest{
+			DataDirectories:    []string{dataDir},
+			RefusedFilesystems: []string{"nfs"},
+		})
+		expected := "data directory " + dataDir + " is on a refused nfs4 filesystem mounted on /data"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
+
+func TestFindMountPoint(t *testing.T) {
+	mounts := `/dev/sda1 / ext4 rw,relatime 0 0
+tmpfs /tmp tmpfs rw,nosuid 0 0
+/dev/sdb1 /data xfs rw,relatime 0 0
+/dev/sdc1 /data/my\040disk ext4 rw,relatime 0 0
+/dev/sdd1 /data2 ext4 rw,relatime 0 0
+server:/export /data2 nfs4 rw,relatime 0 0
+`
+
+	cases := []struct {
+		path       string
+		mountPoint string
+		filesystem string
+	}{
+		{"/", "/", "ext4"},
+		{"/data", "/data", "xfs"},
+		{"/data/primary", "/data", "xfs"},
+		{"/data/my disk/primary", "/data/my disk", "ext4"},
+		{"/data2/primary", "/data2", "nfs4"},
+		{"/data22", "/", "ext4"},
+	}
+
+	for _, tc := range cases {
+		t.Run("returns the mount point of "+tc.path, func(t *testing.T) {
+			mountPoint, filesystem, ok := agent.FindMountPoint(mounts, tc.path)
+			if !ok {
+				t.Fatalf("expected a mount point for %s", tc.path)
+			}
+			if mountPoint != tc.mountPoint || filesystem != tc.filesystem {
+				t.Fatalf("got %s %s, want %s %s", mountPoint, filesystem, tc.mountPoint, tc.filesystem)
+			}
+		})
+	}
+
+	t.Run("returns false when no mount point contains the path", func(t *testing.T) {
+		_, _, ok := agent.FindMountPoint("tmpfs /tmp tmpfs rw 0 0\n", "/data")
+		if ok {
+			t.Fatalf("expected no mount point")
+		}
+	})
+}
+
+func TestGetMountPoint(t *testing.T) {
+	t.Run("reads the mounts of the host", func(t *testing.T) {
+		defer resetAgentFunctions()
+
+		var readPath string
+		utils.System.ReadFile = func(name string) ([]byte, error) {
+			readPath = name
+			return []byte("/dev/sda1 / ext4 rw,relatime 0 0\n"), nil
+		}
+
+		mountPoint, filesystem, err := agent.GetMountPoint(t.TempDir())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mountPoint != "/" || filesystem != "ext4" {
+			t.Fatalf("got %s %s, want / ext4", mountPoint, filesystem)
+		}
+		if readPath != constants.MountsFilepath {
+			t.Fatalf("got %s, want %s", readPath, constants.MountsFilepath)
+		}
+	})
+
+	t.Run("errors out when the mounts can not be read", func(t *testing.T) {
+		defer resetAgentFunctions()
+
+		expectedErr := errors.New("error")
+		utils.System.ReadFile = func(name string) ([]byte, error) {
+			return nil, expectedErr
+		}
+
+		_, _, err := agent.GetMountPoint(t.TempDir())
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+	})
+}
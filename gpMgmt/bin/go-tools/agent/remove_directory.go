@@ -25,13 +25,13 @@ func (s *Server) RemoveDirectory(ctx context.Context, req *idl.RemoveDirectoryRe
 		PgData: req.DataDirectory,
 	}
 
-	_, err = utils.RunGpCommand(&pgCtlStatusOptions, s.GpHome)
+	_, err = utils.RunGpCommand(ctx, &pgCtlStatusOptions, s.GpHome)
 	if err == nil {
 		pgCtlStopOptions := postgres.PgCtlStop{
 			PgData: req.DataDirectory,
 			Mode:   "immediate",
 		}
-		out, err := utils.RunGpCommand(&pgCtlStopOptions, s.GpHome)
+		out, err := utils.RunGpCommand(ctx, &pgCtlStopOptions, s.GpHome)
 		if err != nil {
 			gplog.Error("executing pg_ctl stop: %s, %v", out, err)
 		}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"path/filepath"
 	"sync"
 
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpdb/gp/constants"
 	"github.com/greenplum-db/gpdb/gp/idl"
 	"github.com/greenplum-db/gpdb/gp/utils"
 	"google.golang.org/grpc"
@@ -14,6 +17,17 @@ import (
 
 var (
 	platform = utils.GetPlatform()
+
+	// methods which do not change the state of the host and are not audited
+	readOnlyMethods = []string{
+		"/idl.Agent/Status",
+		"/idl.Agent/ValidateHostEnv",
+		"/idl.Agent/GetInterfaceAddrs",
+		"/idl.Agent/GetHostName",
+		"/idl.Agent/VerifyDataDirectories",
+		"/idl.Agent/GetFreePortRanges",
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+	}
 )
 
 type Config struct {
@@ -22,6 +36,18 @@ type Config struct {
 	GpHome      string
 	LogDir      string
 
+	// certificate common names or SANs of the hubs allowed to call the agent
+	AllowedClients []string
+
+	// JSON file listing the methods each client is allowed to call
+	AuthorizationPolicyFile string
+
+	// port on which the Prometheus metrics are served; disabled if 0
+	MetricsPort int
+
+	// where the traces are exported; disabled if nil
+	Tracing *utils.TracingConfig
+
 	Credentials utils.Credentials
 }
 
@@ -50,11 +76,46 @@ func (s *Server) Start() error {
 		return fmt.Errorf("could not listen on port %d: %w", s.Port, err)
 	}
 
-	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-		// handle stuff here if needed
-		return handler(ctx, req)
+	var policy *utils.AuthorizationPolicy
+	if s.AuthorizationPolicyFile != "" {
+		policy, err = utils.LoadAuthorizationPolicy(s.AuthorizationPolicyFile)
+		if err != nil {
+			listener.Close()
+			return err
+		}
 	}
 
+	shutdownTracing, err := utils.InitTracing(s.Tracing, "agent", filepath.Join(s.LogDir, constants.AgentTracesFileName))
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	defer shutdownTracing()
+
+	auditLogger := utils.NewAuditLogger(filepath.Join(s.LogDir, constants.AgentAuditLogFileName))
+	defer auditLogger.Close()
+
+	if s.MetricsPort != 0 {
+		registry, err := utils.NewMetricsRegistry()
+		if err != nil {
+			listener.Close()
+			return err
+		}
+
+		metricsServer, err := utils.StartMetricsServer(s.MetricsPort, registry)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		defer metricsServer.Close()
+	}
+
+	unaryTracing, streamTracing := utils.NewTracingInterceptors()
+	unaryMetrics, streamMetrics := utils.NewMetricsInterceptors("agent")
+	unaryAuditor, streamAuditor := utils.NewAuditInterceptors("agent", auditLogger, readOnlyMethods)
+	unaryAuthorizer, streamAuthorizer := utils.NewClientAuthorizer("agent", s.AllowedClients)
+	unaryPolicy, streamPolicy := utils.NewPolicyAuthorizer("agent", policy)
+
 	credentials, err := s.Credentials.LoadServerCredentials()
 	if err != nil {
 		listener.Close()
@@ -63,7 +124,8 @@ func (s *Server) Start() error {
 
 	grpcServer := grpc.NewServer(
 		grpc.Creds(credentials),
-		grpc.UnaryInterceptor(interceptor),
+		grpc.ChainUnaryInterceptor(unaryTracing, unaryMetrics, unaryAuditor, unaryAuthorizer, unaryPolicy),
+		grpc.ChainStreamInterceptor(streamTracing, streamMetrics, streamAuditor, streamAuthorizer, streamPolicy),
 	)
 
 	s.mutex.Lock()
@@ -97,7 +159,17 @@ func (s *Server) Status(ctx context.Context, in *idl.StatusAgentRequest) (*idl.S
 		return &idl.StatusAgentReply{}, utils.LogAndReturnError(fmt.Errorf("could not get agent status: %w", err))
 	}
 
-	return &idl.StatusAgentReply{Status: status.Status, Uptime: status.Uptime, Pid: uint32(status.Pid)}, nil
+	reply := &idl.StatusAgentReply{Status: status.Status, Uptime: status.Uptime, Pid: uint32(status.Pid)}
+	if reporter, ok := s.Credentials.(utils.CertificateExpiryReporter); ok {
+		expiry, err := reporter.GetCertificateExpiry()
+		if err != nil {
+			gplog.Warn("could not get the certificate expiry: %v", err)
+		} else {
+			reply.CertificateExpiry = expiry.Unix()
+		}
+	}
+
+	return reply, nil
 }
 
 func (s *Server) GetStatus() (*idl.ServiceStatus, error) {
@@ -1,9 +1,12 @@
 package agent_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -70,6 +73,30 @@ func TestStartServer(t *testing.T) {
 		}
 	})
 
+	t.Run("failed to start if the authorization policy can not be loaded", func(t *testing.T) {
+		agentServer := agent.New(agent.Config{
+			Port:                    constants.DefaultAgentPort,
+			ServiceName:             constants.DefaultServiceName,
+			Credentials:             &testutils.MockCredentials{},
+			AuthorizationPolicyFile: filepath.Join(t.TempDir(), "policy.json"),
+		})
+		errChan := make(chan error, 1)
+
+		go func() {
+			errChan <- agentServer.Start()
+		}()
+		defer agentServer.Shutdown()
+
+		select {
+		case err := <-errChan:
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("got %v, want %v", err, os.ErrNotExist)
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatalf("expected server start to fail")
+		}
+	})
+
 	t.Run("listen fails when starting the server", func(t *testing.T) {
 		credentials := &testutils.MockCredentials{}
 
@@ -187,3 +214,51 @@ func TestGetStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestStatus(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	platform := &testutils.MockPlatform{
+		RetStatus: &idl.ServiceStatus{Status: "Running", Uptime: "10ms", Pid: uint32(1234)},
+	}
+	agent.SetPlatform(platform)
+	defer agent.ResetPlatform()
+
+	t.Run("reports the expiry of the agent certificate", func(t *testing.T) {
+		expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		agentServer := agent.New(agent.Config{
+			ServiceName: constants.DefaultServiceName,
+			Credentials: &testutils.MockExpiringCredentials{Expiry: expiry},
+		})
+
+		result, err := agentServer.Status(context.Background(), &idl.StatusAgentRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := &idl.StatusAgentReply{Status: "Running", Uptime: "10ms", Pid: 1234, CertificateExpiry: expiry.Unix()}
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("got %+v, want %+v", result, expected)
+		}
+	})
+
+	t.Run("reports the status when not able to get the certificate expiry", func(t *testing.T) {
+		_, _, logfile := testhelper.SetupTestLogger()
+
+		agentServer := agent.New(agent.Config{
+			ServiceName: constants.DefaultServiceName,
+			Credentials: &testutils.MockExpiringCredentials{ExpiryErr: errors.New("error")},
+		})
+
+		result, err := agentServer.Status(context.Background(), &idl.StatusAgentRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := &idl.StatusAgentReply{Status: "Running", Uptime: "10ms", Pid: 1234}
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("got %+v, want %+v", result, expected)
+		}
+		testutils.AssertLogMessage(t, logfile, `\[WARNING\]:-could not get the certificate expiry: error`)
+	})
+}
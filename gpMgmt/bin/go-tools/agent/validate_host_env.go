@@ -57,7 +57,7 @@ func (s *Server) ValidateHostEnv(ctx context.Context, request *idl.ValidateHostE
 	}
 
 	//Check for GP Version
-	gpVersionErr := VerifyPgVersion(request.GpVersion, s.GpHome)
+	gpVersionErr := VerifyPgVersion(ctx, request.GpVersion, s.GpHome)
 	if gpVersionErr != nil {
 		return &idl.ValidateHostEnvReply{}, utils.LogAndReturnError(fmt.Errorf("Postgres gp-version validation failed:%v", gpVersionErr))
 	}
@@ -115,8 +115,8 @@ func (s *Server) ValidateHostEnv(ctx context.Context, request *idl.ValidateHostE
 ValidatePgVersionFn gets current version of gpdb and compares with version from coordinator
 returns error if version do not match.
 */
-func ValidatePgVersionFn(expectedVersion string, gpHome string) error {
-	localPgVersion, err := greenplum.GetPostgresGpVersion(gpHome)
+func ValidatePgVersionFn(ctx context.Context, expectedVersion string, gpHome string) error {
+	localPgVersion, err := greenplum.GetPostgresGpVersion(ctx, gpHome)
 	if err != nil {
 		return err
 	}
@@ -50,6 +50,7 @@ func resetAgentFunctions() {
 	agent.VerifyPgVersion = agent.ValidatePgVersionFn
 	agent.OsIsNotExist = os.IsNotExist
 	agent.GetAllAvailableLocales = agent.GetAllAvailableLocalesFn
+	agent.GetMountPoint = agent.GetMountPointFn
 	utils.ResetSystemFunctions()
 
 }
@@ -166,7 +167,7 @@ func TestValidatePgVersionFn(t *testing.T) {
 		utils.System.ExecCommand = exectest.NewCommand(exectest.Failure)
 		defer utils.ResetSystemFunctions()
 
-		err := agent.ValidatePgVersionFn("expected-version", "gpHome")
+		err := agent.ValidatePgVersionFn(context.Background(), "expected-version", "gpHome")
 		expectedStr := "fetching postgres gp-version: exit status 1"
 		if err == nil || !strings.Contains(err.Error(), expectedStr) {
 			t.Fatalf("expected error:`%s`, got error:`%s`", expectedStr, err)
@@ -176,7 +177,7 @@ func TestValidatePgVersionFn(t *testing.T) {
 		utils.System.ExecCommand = exectest.NewCommand(exectest.Success)
 		defer utils.ResetSystemFunctions()
 
-		err := agent.ValidatePgVersionFn("", "gpHome")
+		err := agent.ValidatePgVersionFn(context.Background(), "", "gpHome")
 		if err != nil {
 			t.Fatalf("expected no error, got error:`%s`", err)
 		}
@@ -186,7 +187,7 @@ func TestValidatePgVersionFn(t *testing.T) {
 		utils.System.ExecCommand = exectest.NewCommand(PgVersionCmd)
 		defer utils.ResetSystemFunctions()
 
-		err := agent.ValidatePgVersionFn(expectedVersion, "gpHome")
+		err := agent.ValidatePgVersionFn(context.Background(), expectedVersion, "gpHome")
 		if err != nil {
 			t.Fatalf("expected no error, got error:`%s`", err)
 		}
@@ -196,7 +197,7 @@ func TestValidatePgVersionFn(t *testing.T) {
 		utils.System.ExecCommand = exectest.NewCommand(PgVersionCmd)
 		defer utils.ResetSystemFunctions()
 
-		err := agent.ValidatePgVersionFn(expectedVersion, "gpHome")
+		err := agent.ValidatePgVersionFn(context.Background(), expectedVersion, "gpHome")
 		expectedStr := "postgres gp-version does not matches with coordinator postgres gp-version."
 		if err == nil || !strings.Contains(err.Error(), expectedStr) {
 			t.Fatalf("expected error: `%s`, got error:`%s`", expectedStr, err)
@@ -304,7 +305,7 @@ func TestValidateHostEnv(t *testing.T) {
 	t.Run("return error when gp version is not matching", func(t *testing.T) {
 		testStr := "gpversion does not match"
 		defer resetAgentFunctions()
-		agent.VerifyPgVersion = func(expectedVersion string, gpHome string) error {
+		agent.VerifyPgVersion = func(ctx context.Context, expectedVersion string, gpHome string) error {
 			return fmt.Errorf(testStr)
 		}
 
@@ -321,7 +322,7 @@ func TestValidateHostEnv(t *testing.T) {
 	t.Run("return error when non-empty directories are found and force is not set", func(t *testing.T) {
 		testStr := "directory not empty:"
 		defer resetAgentFunctions()
-		agent.VerifyPgVersion = func(expectedVersion string, gpHome string) error {
+		agent.VerifyPgVersion = func(ctx context.Context, expectedVersion string, gpHome string) error {
 			return nil
 		}
 		agent.GetAllNonEmptyDir = func(dirList []string) ([]string, error) {
@@ -341,7 +342,7 @@ func TestValidateHostEnv(t *testing.T) {
 	t.Run("return error when force is set and error deleting files", func(t *testing.T) {
 		testStr := "Error deleting directory"
 		defer resetAgentFunctions()
-		agent.VerifyPgVersion = func(expectedVersion string, gpHome string) error {
+		agent.VerifyPgVersion = func(ctx context.Context, expectedVersion string, gpHome string) error {
 			return nil
 		}
 		agent.GetAllNonEmptyDir = func(dirList []string) ([]string, error) {
@@ -364,7 +365,7 @@ func TestValidateHostEnv(t *testing.T) {
 	t.Run("return error when initdb file does not have the correct permissions", func(t *testing.T) {
 		testStr := "file does not have enough permission"
 		defer resetAgentFunctions()
-		agent.VerifyPgVersion = func(expectedVersion string, gpHome string) error {
+		agent.VerifyPgVersion = func(ctx context.Context, expectedVersion string, gpHome string) error {
 			return nil
 		}
 		agent.GetAllNonEmptyDir = func(dirList []string) ([]string, error) {
@@ -387,7 +388,7 @@ func TestValidateHostEnv(t *testing.T) {
 	t.Run("return error when locale validation fails", func(t *testing.T) {
 		testStr := "invalid local provided"
 		defer resetAgentFunctions()
-		agent.VerifyPgVersion = func(expectedVersion string, gpHome string) error {
+		agent.VerifyPgVersion = func(ctx context.Context, expectedVersion string, gpHome string) error {
 			return nil
 		}
 		agent.GetAllNonEmptyDir = func(dirList []string) ([]string, error) {
@@ -413,7 +414,7 @@ func TestValidateHostEnv(t *testing.T) {
 	t.Run("return error when port validation fails", func(t *testing.T) {
 		testStr := "ports already in use"
 		defer resetAgentFunctions()
-		agent.VerifyPgVersion = func(expectedVersion string, gpHome string) error {
+		agent.VerifyPgVersion = func(ctx context.Context, expectedVersion string, gpHome string) error {
 			return nil
 		}
 		agent.GetAllNonEmptyDir = func(dirList []string) ([]string, error) {
@@ -441,7 +442,7 @@ func TestValidateHostEnv(t *testing.T) {
 	})
 	t.Run("return success when no errors, no force", func(t *testing.T) {
 		defer resetAgentFunctions()
-		agent.VerifyPgVersion = func(expectedVersion string, gpHome string) error {
+		agent.VerifyPgVersion = func(ctx context.Context, expectedVersion string, gpHome string) error {
 			return nil
 		}
 		agent.GetAllNonEmptyDir = func(dirList []string) ([]string, error) {
@@ -469,7 +470,7 @@ func TestValidateHostEnv(t *testing.T) {
 	})
 	t.Run("return success when no errors, force is true", func(t *testing.T) {
 		defer resetAgentFunctions()
-		agent.VerifyPgVersion = func(expectedVersion string, gpHome string) error {
+		agent.VerifyPgVersion = func(ctx context.Context, expectedVersion string, gpHome string) error {
 			return nil
 		}
 		agent.GetAllNonEmptyDir = func(dirList []string) ([]string, error) {
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+/*
+VerifyDataDirectories checks that each of the given data directories exists on the
+host and contains an initialized postgres data directory.
+*/
+func (s *Server) VerifyDataDirectories(ctx context.Context, req *idl.VerifyDataDirectoriesRequest) (*idl.VerifyDataDirectoriesReply, error) {
+	var err error
+	for _, dataDir := range req.DataDirectories {
+		err = errors.Join(err, verifyDataDirectory(dataDir))
+	}
+
+	if err != nil {
+		return &idl.VerifyDataDirectoriesReply{}, utils.LogAndReturnError(err)
+	}
+
+	return &idl.VerifyDataDirectoriesReply{}, nil
+}
+
+func verifyDataDirectory(dataDir string) error {
+	info, err := utils.System.Stat(dataDir)
+	if err != nil {
+		return fmt.Errorf("data directory %s: %w", dataDir, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("data directory %s is not a directory", dataDir)
+	}
+
+	_, err = utils.System.Stat(filepath.Join(dataDir, "PG_VERSION"))
+	if err != nil {
+		return fmt.Errorf("data directory %s is not a valid postgres data directory: %w", dataDir, err)
+	}
+
+	return nil
+}
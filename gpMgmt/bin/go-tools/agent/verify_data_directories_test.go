@@ -0,0 +1,96 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/agent"
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+func TestVerifyDataDirectories(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	agentServer := agent.New(agent.Config{
+		GpHome: "gpHome",
+	})
+
+	createDataDir := func(t *testing.T) string {
+		t.Helper()
+
+		dataDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dataDir, "PG_VERSION"), []byte("12\n"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return dataDir
+	}
+
+	t.Run("succeeds when all the data directories are valid", func(t *testing.T) {
+		req := &idl.VerifyDataDirectoriesRequest{
+			DataDirectories: []string{createDataDir(t), createDataDir(t)},
+		}
+
+		_, err := agentServer.VerifyDataDirectories(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("errors out when the data directory does not exist", func(t *testing.T) {
+		dataDir := filepath.Join(t.TempDir(), "gpseg0")
+		req := &idl.VerifyDataDirectoriesRequest{
+			DataDirectories: []string{createDataDir(t), dataDir},
+		}
+
+		_, err := agentServer.VerifyDataDirectories(context.Background(), req)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want %v", err, os.ErrNotExist)
+		}
+
+		expected := "data directory " + dataDir
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %q, want %q", err, expected)
+		}
+	})
+
+	t.Run("errors out when the data directory is not a directory", func(t *testing.T) {
+		dataDir := filepath.Join(t.TempDir(), "file")
+		err := os.WriteFile(dataDir, nil, 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		req := &idl.VerifyDataDirectoriesRequest{
+			DataDirectories: []string{dataDir},
+		}
+
+		_, err = agentServer.VerifyDataDirectories(context.Background(), req)
+		expected := "data directory " + dataDir + " is not a directory"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("reports all the invalid data directories", func(t *testing.T) {
+		dataDir1 := t.TempDir()
+		dataDir2 := t.TempDir()
+		req := &idl.VerifyDataDirectoriesRequest{
+			DataDirectories: []string{dataDir1, createDataDir(t), dataDir2},
+		}
+
+		_, err := agentServer.VerifyDataDirectories(context.Background(), req)
+		for _, dataDir := range []string{dataDir1, dataDir2} {
+			expected := "data directory " + dataDir + " is not a valid postgres data directory"
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Fatalf("got %v, want %s", err, expected)
+			}
+		}
+	})
+}
@@ -11,7 +11,7 @@ func agentCmd() *cobra.Command {
 		Short:   "Start a gp process in agent mode",
 		Long:    "Start a gp process in agent mode",
 		Hidden:  true, // Should only be invoked by systemd
-		PreRunE: InitializeCommand,
+		PreRunE: InitializeService,
 		RunE:    RunAgent,
 	}
 
@@ -25,6 +25,11 @@ func RunAgent(cmd *cobra.Command, args []string) (err error) {
 		GpHome:      Conf.GpHome,
 		Credentials: Conf.Credentials,
 		LogDir:      Conf.LogDir,
+
+		AllowedClients:          Conf.AllowedHubClients,
+		AuthorizationPolicyFile: Conf.AuthorizationPolicyFile,
+		MetricsPort:             Conf.AgentMetricsPort,
+		Tracing:                 Conf.Tracing,
 	}
 	a := agent.New(agentConf)
 
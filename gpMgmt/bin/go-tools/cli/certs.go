@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+var (
+	certsDir         string
+	caCommonName     string
+	certValidityDays int
+	forceCreateCA    bool
+	DistributeCerts  = utils.DistributeCertificates
+	DefaultCertsDir  = filepath.Join(os.Getenv("GPHOME"), constants.DefaultCertsDirName)
+)
+
+// common name of the CA certificate created unless another one is given
+const defaultCACertName = "Greenplum CA"
+
+func certsCmd() *cobra.Command {
+	certsCmd := &cobra.Command{
+		Use:   "certs",
+		Short: "Manage the TLS certificates used by the hub and agents",
+		Long: `Manage the TLS certificates used by the hub and agents.
+
+The certificate directory holds the CA certificate and private key, along with a
+sub-directory per host containing the server and client certificates issued for
+that host. Distributing the certificates copies the CA certificate and the host
+certificates into the same certificate directory on each host.`,
+	}
+
+	certsCmd.PersistentFlags().StringVar(&certsDir, "certs-dir", DefaultCertsDir, `Path to the certificate directory`)
+
+	certsCmd.AddCommand(
+		certsCreateCACmd(),
+		certsGenerateCmd(),
+		certsDistributeCmd(),
+		certsRotateCmd(),
+		certsExpiryCmd(),
+	)
+
+	return certsCmd
+}
+
+func certsCreateCACmd() *cobra.Command {
+	createCACmd := &cobra.Command{
+		Use:     "create-ca",
+		Short:   "Create a certificate authority to sign the hub and agent certificates",
+		PreRunE: InitializeLogger,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CreateCA(certsDir, caCommonName, certValidityDays, forceCreateCA)
+		},
+	}
+
+	createCACmd.Flags().StringVar(&caCommonName, "common-name", defaultCACertName, `Common name of the CA certificate`)
+	createCACmd.Flags().IntVar(&certValidityDays, "days", constants.DefaultCAValidityDays, `Number of days the CA certificate is valid for`)
+	createCACmd.Flags().BoolVar(&forceCreateCA, "force", false, `Overwrite an existing CA`)
+
+	return createCACmd
+}
+
+func certsGenerateCmd() *cobra.Command {
+	generateCmd := &cobra.Command{
+		Use:     "generate",
+		Short:   "Issue server and client certificates for the hosts",
+		Long:    "Issue server and client certificates for the hosts, along with the host on which the hub runs, signed by the CA in the certificate directory",
+		PreRunE: InitializeLogger,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hosts, err := getCertsHostList(cmd)
+			if err != nil {
+				return err
+			}
+
+			return GenerateHostCertificates(certsDir, hosts, certValidityDays)
+		},
+	}
+
+	generateCmd.Flags().IntVar(&certValidityDays, "days", constants.DefaultCertValidityDays, `Number of days the certificates are valid for`)
+	addCertsHostFlags(generateCmd)
+
+	return generateCmd
+}
+
+func certsDistributeCmd() *cobra.Command {
+	distributeCmd := &cobra.Command{
+		Use:     "distribute",
+		Short:   "Copy the certificates issued for the hosts to the certificate directory on each host",
+		PreRunE: InitializeLogger,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hosts, err := getCertsHostList(cmd)
+			if err != nil {
+				return err
+			}
+
+			err = DistributeCerts(gpHome, certsDir, hosts)
+			if err != nil {
+				return err
+			}
+			gplog.Info("Copied the certificates to %s on all hosts", certsDir)
+
+			return nil
+		},
+	}
+
+	distributeCmd.Flags().StringVar(&gpHome, "gphome", os.Getenv("GPHOME"), `Path to GPDB installation`)
+	addCertsHostFlags(distributeCmd)
+
+	return distributeCmd
+}
+
+func certsRotateCmd() *cobra.Command {
+	rotateCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Issue new certificates for the hosts and distribute them",
+		Long: `Issue new server and client certificates for the hosts, signed by the CA in the
+certificate directory, and copy them to the certificate directory on each host.
+The hub and agents reload the certificates when the files change, so the
+services do not need to be restarted.`,
+		PreRunE: InitializeLogger,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hosts, err := getCertsHostList(cmd)
+			if err != nil {
+				return err
+			}
+
+			return RotateCertificates(certsDir, hosts, certValidityDays, gpHome)
+		},
+	}
+
+	rotateCmd.Flags().IntVar(&certValidityDays, "days", constants.DefaultCertValidityDays, `Number of days the certificates are valid for`)
+	rotateCmd.Flags().StringVar(&gpHome, "gphome", os.Getenv("GPHOME"), `Path to GPDB installation`)
+	addCertsHostFlags(rotateCmd)
+
+	return rotateCmd
+}
+
+func certsExpiryCmd() *cobra.Command {
+	expiryCmd := &cobra.Command{
+		Use:   "expiry",
+		Short: "Report the expiry dates of the certificates",
+		Long:  "Report the expiry dates of the certificates in the certificate directory, or of the certificates in the gp configuration file if no certificate directory is given",
+		RunE:  RunCertsExpiry,
+	}
+
+	return expiryCmd
+}
+
+func addCertsHostFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&hostnames, "host", []string{}, `Segment hostname`)
+	cmd.Flags().StringVar(&hostfilePath, "hostfile", "", `Path to file containing a list of segment hostnames`)
+	cmd.MarkFlagsMutuallyExclusive("host", "hostfile")
+}
+
+func getCertsHostList(cmd *cobra.Command) ([]string, error) {
+	if !cmd.Flags().Lookup("host").Changed && !cmd.Flags().Lookup("hostfile").Changed {
+		return nil, errors.New("at least one hostname must be provided using either --host or --hostfile")
+	}
+
+	if cmd.Flags().Lookup("hostfile").Changed {
+		return GetHostnames(hostfilePath)
+	}
+
+	return hostnames, nil
+}
+
+// CreateCA creates a new certificate authority and stores it in the certificate directory
+func CreateCA(certsDir, commonName string, validityDays int, force bool) error {
+	caCertPath := filepath.Join(certsDir, constants.CACertFileName)
+	if _, err := utils.System.Stat(caCertPath); err == nil && !force {
+		return fmt.Errorf("a CA already exists in %s, use --force to overwrite it", certsDir)
+	}
+
+	ca, err := utils.CreateCertificateAuthority(commonName, validityDays)
+	if err != nil {
+		return err
+	}
+
+	err = ca.Write(certsDir)
+	if err != nil {
+		return err
+	}
+	gplog.Info("Created CA %q in %s valid until %s", commonName, certsDir, ca.Cert.NotAfter.Format(time.RFC3339))
+
+	return nil
+}
+
+/*
+GenerateHostCertificates issues the server and client certificates for the given
+hosts using the CA in the certificate directory. The local host is always
+included since it runs the hub and the CLI, and only its certificates are valid
+for localhost.
+*/
+func GenerateHostCertificates(certsDir string, hosts []string, validityDays int) error {
+	ca, err := utils.LoadCertificateAuthority(filepath.Join(certsDir, constants.CACertFileName), filepath.Join(certsDir, constants.CAKeyFileName))
+	if err != nil {
+		return err
+	}
+
+	localHost, err := utils.System.GetHostName()
+	if err != nil {
+		return fmt.Errorf("failed to get the local hostname: %w", err)
+	}
+
+	hosts, err = withLocalHost(hosts)
+	if err != nil {
+		return err
+	}
+
+	for _, host := range hosts {
+		err = ca.IssueHostCertificates(host, host == localHost, validityDays, filepath.Join(certsDir, host))
+		if err != nil {
+			return err
+		}
+		gplog.Verbose("Issued certificates for host %s", host)
+	}
+	gplog.Info("Issued certificates for hosts %v in %s", hosts, certsDir)
+
+	return nil
+}
+
+/*
+SetupCertificates creates a CA if the certificate directory does not have one,
+issues the certificates for all the hosts and distributes them. It returns the
+credentials to be used in the gp configuration file.
+*/
+func SetupCertificates(certsDir string, hosts []string, gpHome string) (*utils.GpCredentials, error) {
+	var err error
+	if _, statErr := utils.System.Stat(filepath.Join(certsDir, constants.CACertFileName)); statErr == nil {
+		gplog.Info("Using the existing CA in %s", certsDir)
+	} else {
+		err = CreateCA(certsDir, defaultCACertName, constants.DefaultCAValidityDays, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = GenerateHostCertificates(certsDir, hosts, constants.DefaultCertValidityDays)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts, err = withLocalHost(hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	err = DistributeCerts(gpHome, certsDir, hosts)
+	if err != nil {
+		return nil, err
+	}
+	gplog.Info("Copied the certificates to %s on all hosts", certsDir)
+
+	return &utils.GpCredentials{
+		CACertPath:     filepath.Join(certsDir, constants.CACertFileName),
+		CAKeyPath:      filepath.Join(certsDir, constants.CAKeyFileName),
+		ServerCertPath: filepath.Join(certsDir, constants.ServerCertFileName),
+		ServerKeyPath:  filepath.Join(certsDir, constants.ServerKeyFileName),
+		ClientCertPath: filepath.Join(certsDir, constants.ClientCertFileName),
+		ClientKeyPath:  filepath.Join(certsDir, constants.ClientKeyFileName),
+	}, nil
+}
+
+/*
+RotateCertificates issues new certificates for the given hosts and the local
+host using the existing CA and replaces the certificates on all the hosts.
+*/
+func RotateCertificates(certsDir string, hosts []string, validityDays int, gpHome string) error {
+	err := GenerateHostCertificates(certsDir, hosts, validityDays)
+	if err != nil {
+		return err
+	}
+
+	hosts, err = withLocalHost(hosts)
+	if err != nil {
+		return err
+	}
+
+	err = DistributeCerts(gpHome, certsDir, hosts)
+	if err != nil {
+		return err
+	}
+	gplog.Info("Rotated the certificates on all hosts, the hub and agents will use them for new connections")
+
+	return nil
+}
+
+func RunCertsExpiry(cmd *cobra.Command, args []string) error {
+	var paths []string
+	if cmd.Flags().Lookup("certs-dir").Changed {
+		err := InitializeLogger(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		paths, err = findCertificates(certsDir)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := InitializeCommand(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		paths, err = getConfiguredCertificates(Conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	var certs []*utils.CertificateInfo
+	for _, path := range paths {
+		info, err := utils.GetCertificateInfo(path)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, info)
+	}
+
+	now := time.Now()
+	displayCertificateExpiry(certs, now)
+	for _, c := range certs {
+		if c.ExpiryStatus(now) != "valid" {
+			gplog.Warn("certificate %s expires on %s", c.Path, c.NotAfter.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+func getConfiguredCertificates(conf *hub.Config) ([]string, error) {
+	creds, ok := conf.Credentials.(*utils.GpCredentials)
+	if !ok {
+		return nil, fmt.Errorf("unable to read the certificate paths from the configuration")
+	}
+
+	paths := []string{creds.CACertPath, creds.ServerCertPath}
+	if creds.ClientCertPath != "" {
+		paths = append(paths, creds.ClientCertPath)
+	}
+
+	return paths, nil
+}
+
+func findCertificates(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && filepath.Ext(path) == ".pem" && !utils.IsPrivateKeyFile(path) {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the certificate directory %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func withLocalHost(hosts []string) ([]string, error) {
+	localHost, err := utils.System.GetHostName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the local hostname: %w", err)
+	}
+
+	if slices.Contains(hosts, localHost) {
+		return hosts, nil
+	}
+
+	return append([]string{localHost}, hosts...), nil
+}
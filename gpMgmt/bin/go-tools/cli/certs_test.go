@@ -0,0 +1,282 @@
+package cli_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/cli"
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+func TestCreateCA(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("creates the CA in the certificate directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "certs")
+
+		err := cli.CreateCA(dir, "test CA", 10, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = utils.LoadCertificateAuthority(filepath.Join(dir, constants.CACertFileName), filepath.Join(dir, constants.CAKeyFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("errors out when the CA already exists", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := cli.CreateCA(dir, "test CA", 10, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = cli.CreateCA(dir, "test CA", 10, false)
+		expected := "a CA already exists in " + dir + ", use --force to overwrite it"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("overwrites the existing CA when forced", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := cli.CreateCA(dir, "old CA", 10, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = cli.CreateCA(dir, "new CA", 10, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cert, err := utils.ReadCertificate(filepath.Join(dir, constants.CACertFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if cert.Subject.CommonName != "new CA" {
+			t.Fatalf("got %s, want new CA", cert.Subject.CommonName)
+		}
+	})
+}
+
+func TestGenerateHostCertificates(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	utils.System.GetHostName = func() (string, error) {
+		return "cdw", nil
+	}
+	defer utils.ResetSystemFunctions()
+
+	t.Run("issues the certificates for all the hosts along with the local host", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := cli.CreateCA(dir, "test CA", 10, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = cli.GenerateHostCertificates(dir, []string{"sdw1", "sdw2"}, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, host := range []string{"cdw", "sdw1", "sdw2"} {
+			for _, name := range []string{constants.ServerCertFileName, constants.ServerKeyFileName, constants.ClientCertFileName, constants.ClientKeyFileName} {
+				_, err = os.Stat(filepath.Join(dir, host, name))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+		}
+	})
+
+	t.Run("only issues the certificates of the local host for localhost", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := cli.CreateCA(dir, "test CA", 10, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = cli.GenerateHostCertificates(dir, []string{"sdw1"}, 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for host, expected := range map[string]bool{"cdw": true, "sdw1": false} {
+			cert, err := utils.ReadCertificate(filepath.Join(dir, host, constants.ServerCertFileName))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if slices.Contains(cert.DNSNames, "localhost") != expected {
+				t.Fatalf("got %v for %s, want localhost included %t", cert.DNSNames, host, expected)
+			}
+		}
+	})
+
+	t.Run("errors out when there is no CA", func(t *testing.T) {
+		err := cli.GenerateHostCertificates(t.TempDir(), []string{"sdw1"}, 5)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func TestSetupCertificates(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	utils.System.GetHostName = func() (string, error) {
+		return "cdw", nil
+	}
+	defer utils.ResetSystemFunctions()
+
+	t.Run("creates the CA, issues and distributes the certificates", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var distributedHosts []string
+		cli.DistributeCerts = func(gpHome string, certsDir string, hostnames []string) error {
+			if certsDir != dir {
+				t.Fatalf("got %s, want %s", certsDir, dir)
+			}
+			distributedHosts = hostnames
+
+			return nil
+		}
+		defer func() { cli.DistributeCerts = utils.DistributeCertificates }()
+
+		creds, err := cli.SetupCertificates(dir, []string{"sdw1"}, "gpHome")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectedHosts := []string{"cdw", "sdw1"}
+		if !reflect.DeepEqual(distributedHosts, expectedHosts) {
+			t.Fatalf("got %v, want %v", distributedHosts, expectedHosts)
+		}
+
+		expectedCreds := &utils.GpCredentials{
+			CACertPath:     filepath.Join(dir, constants.CACertFileName),
+			CAKeyPath:      filepath.Join(dir, constants.CAKeyFileName),
+			ServerCertPath: filepath.Join(dir, constants.ServerCertFileName),
+			ServerKeyPath:  filepath.Join(dir, constants.ServerKeyFileName),
+			ClientCertPath: filepath.Join(dir, constants.ClientCertFileName),
+			ClientKeyPath:  filepath.Join(dir, constants.ClientKeyFileName),
+		}
+		if !reflect.DeepEqual(creds, expectedCreds) {
+			t.Fatalf("got %+v, want %+v", creds, expectedCreds)
+		}
+	})
+
+	t.Run("reuses the existing CA", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := cli.CreateCA(dir, "existing CA", 10, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cli.DistributeCerts = func(gpHome string, certsDir string, hostnames []string) error {
+			return nil
+		}
+		defer func() { cli.DistributeCerts = utils.DistributeCertificates }()
+
+		_, err = cli.SetupCertificates(dir, []string{"sdw1"}, "gpHome")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cert, err := utils.ReadCertificate(filepath.Join(dir, constants.CACertFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if cert.Subject.CommonName != "existing CA" {
+			t.Fatalf("got %s, want existing CA", cert.Subject.CommonName)
+		}
+	})
+
+	t.Run("errors out when not able to distribute the certificates", func(t *testing.T) {
+		expectedErr := errors.New("error")
+		cli.DistributeCerts = func(gpHome string, certsDir string, hostnames []string) error {
+			return expectedErr
+		}
+		defer func() { cli.DistributeCerts = utils.DistributeCertificates }()
+
+		_, err := cli.SetupCertificates(t.TempDir(), []string{"sdw1"}, "gpHome")
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+	})
+}
+
+func TestRotateCertificates(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	utils.System.GetHostName = func() (string, error) {
+		return "cdw", nil
+	}
+	defer utils.ResetSystemFunctions()
+
+	t.Run("reissues and distributes the certificates using the existing CA", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := cli.CreateCA(dir, "existing CA", 10, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = cli.GenerateHostCertificates(dir, []string{"sdw1"}, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		oldCert, err := utils.ReadCertificate(filepath.Join(dir, "sdw1", constants.ServerCertFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var distributedHosts []string
+		cli.DistributeCerts = func(gpHome string, certsDir string, hostnames []string) error {
+			distributedHosts = hostnames
+			return nil
+		}
+		defer func() { cli.DistributeCerts = utils.DistributeCertificates }()
+
+		err = cli.RotateCertificates(dir, []string{"sdw1"}, 5, "gpHome")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectedHosts := []string{"cdw", "sdw1"}
+		if !reflect.DeepEqual(distributedHosts, expectedHosts) {
+			t.Fatalf("got %v, want %v", distributedHosts, expectedHosts)
+		}
+
+		newCert, err := utils.ReadCertificate(filepath.Join(dir, "sdw1", constants.ServerCertFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !newCert.NotAfter.After(oldCert.NotAfter) {
+			t.Fatalf("got expiry %s, want later than %s", newCert.NotAfter, oldCert.NotAfter)
+		}
+	})
+
+	t.Run("errors out when there is no CA", func(t *testing.T) {
+		err := cli.RotateCertificates(t.TempDir(), []string{"sdw1"}, 5, "gpHome")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+// options about the output of the commands the hub and the agents run
+var (
+	PrefixOutput       bool
+	OutputHostsFilter  []string
+	OutputStreamFilter string
+)
+
+// addCommandOutputFlags adds the options about the output of the commands the hub and the agents run, like initdb
+func addCommandOutputFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&PrefixOutput, "prefix-output", false, `Prefix each line of output of the commands run on the hosts with its host, segment and stream`)
+	cmd.PersistentFlags().StringSliceVar(&OutputHostsFilter, "output-host", nil, `Only show the output of the commands run on the given hosts`)
+	cmd.PersistentFlags().StringVar(&OutputStreamFilter, "output-stream", "", `Only show the output of the commands written to the given stream, "stdout" or "stderr"`)
+}
+
+func validateCommandOutputFlags() error {
+	switch OutputStreamFilter {
+	case "", "stdout", "stderr":
+		return nil
+	}
+
+	return &UsageError{fmt.Errorf("invalid --output-stream %q, expected %q or %q", OutputStreamFilter, "stdout", "stderr")}
+}
+
+// outputSource describes where a line of output comes from, like sdw1:/data/primary/gpseg0
+func outputSource(msg *idl.OutputMessage) string {
+	if msg.Segment != "" {
+		return fmt.Sprintf("%s:%s", msg.Host, msg.Segment)
+	}
+
+	return msg.Host
+}
+
+func outputStreamName(stream idl.OutputStream) string {
+	return strings.ToLower(stream.String())
+}
+
+// isOutputSelected tells whether the line of output is selected by --output-host and --output-stream
+func isOutputSelected(msg *idl.OutputMessage) bool {
+	if len(OutputHostsFilter) > 0 && !slices.Contains(OutputHostsFilter, msg.Host) {
+		return false
+	}
+
+	return OutputStreamFilter == "" || OutputStreamFilter == outputStreamName(msg.Stream)
+}
+
+/*
+printOutputMessage shows a line of output of a command run on a host, or writes
+it as an output event. The output of the commands run for each segment, like
+initdb and pg_basebackup, is only shown as text with --verbose, as there is a
+lot of it.
+*/
+func printOutputMessage(msg *idl.OutputMessage) {
+	if !isOutputSelected(msg) {
+		return
+	}
+
+	if IsMachineOutput() {
+		EmitEvent(Event{
+			Type:          EventOutput,
+			Time:          time.UnixMilli(msg.Timestamp).UTC(),
+			Host:          msg.Host,
+			DataDirectory: msg.Segment,
+			Dbid:          int(msg.Dbid),
+			Stream:        outputStreamName(msg.Stream),
+			Sequence:      msg.Sequence,
+			Message:       msg.Line,
+		})
+		return
+	}
+
+	if msg.Segment != "" && !Verbose {
+		return
+	}
+
+	if PrefixOutput {
+		fmt.Printf("[%s %s] %s\n", outputSource(msg), outputStreamName(msg.Stream), msg.Line)
+	} else {
+		fmt.Println(msg.Line)
+	}
+}
@@ -13,8 +13,11 @@ import (
 	"github.com/greenplum-db/gpdb/gp/constants"
 	"github.com/greenplum-db/gpdb/gp/hub"
 	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 var (
@@ -27,24 +30,41 @@ var (
 	Conf           *hub.Config
 
 	Verbose bool
+
+	// span of the whole CLI command, to which the calls to the hub belong
+	commandSpan     trace.Span
+	shutdownTracing = func() {}
+
+	stopInterruptNotification = func() {}
 )
 
 func RootCommand() *cobra.Command {
 	root := &cobra.Command{
 		Use: "gp",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return ValidateOutputFormat()
+		},
 	}
 
 	root.PersistentFlags().StringVar(&ConfigFilePath, "config-file", filepath.Join(os.Getenv("GPHOME"), constants.ConfigFileName), `Path to gp configuration file`)
 	root.PersistentFlags().BoolVar(&Verbose, "verbose", false, `Provide verbose output`)
+	root.PersistentFlags().StringVar(&OutputFormat, "output", OutputText, `Output format, one of "text", "json" or "yaml". The json output is a stream of newline-delimited events`)
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &UsageError{err}
+	})
 
 	root.AddCommand(
 		agentCmd(),
+		certsCmd(),
 		configureCmd(),
 		hubCmd(),
 		startCmd(),
 		statusCmd(),
 		stopCmd(),
 		initCmd(),
+		opsCmd(),
+		eventsCmd(),
+		topologyCmd(),
 	)
 
 	return root
@@ -53,6 +73,20 @@ func RootCommand() *cobra.Command {
 // Performs general setup needed for most commands
 // Public, so it can be mocked out in testing
 func InitializeCommand(cmd *cobra.Command, args []string) error {
+	err := InitializeService(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	initializeTracing(cmd)
+	stopInterruptNotification = NotifyInterrupt()
+
+	return nil
+}
+
+// InitializeService performs the setup needed by the commands running the hub and the agents,
+// which trace the calls they serve on their own
+func InitializeService(cmd *cobra.Command, args []string) error {
 	// TODO: Add a new constructor to gplog to allow initializing with a custom logfile path directly
 	Conf = &hub.Config{}
 	err := Conf.Load(ConfigFilePath)
@@ -73,7 +107,14 @@ func InitializeLogger(cmd *cobra.Command, args []string) error {
 	// CommandPath lists the names of the called command and all of its parent commands, so this
 	// turns e.g. "gp stop hub" into "gp_stop_hub" to generate a unique log file name for each command.
 	logName := strings.ReplaceAll(cmd.CommandPath(), " ", "_")
-	gplog.InitializeLogging(logName, hubLogDir)
+	if IsMachineOutput() {
+		err := initializeEventLogging(logName, hubLogDir)
+		if err != nil {
+			return err
+		}
+	} else {
+		gplog.InitializeLogging(logName, hubLogDir)
+	}
 
 	if Verbose {
 		gplog.SetVerbosity(gplog.LOGVERBOSE)
@@ -82,6 +123,41 @@ func InitializeLogger(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// initializeTracing starts the span of the CLI command; tracing is not essential, so failures are only logged
+func initializeTracing(cmd *cobra.Command) {
+	shutdown, err := utils.InitTracing(Conf.Tracing, "cli", filepath.Join(Conf.LogDir, constants.CliTracesFileName))
+	if err != nil {
+		gplog.Warn("not tracing the command: %v", err)
+		return
+	}
+
+	shutdownTracing = shutdown
+	_, commandSpan = utils.StartSpan(context.Background(), cmd.CommandPath())
+}
+
+// FinishCommand ends the span of the CLI command, exports the pending traces and stops handling interrupts
+func FinishCommand(err error) {
+	stopInterruptNotification()
+	stopInterruptNotification = func() {}
+
+	if commandSpan != nil {
+		utils.EndSpan(commandSpan, err)
+		commandSpan = nil
+	}
+
+	shutdownTracing()
+	shutdownTracing = func() {}
+}
+
+// withCommandSpan makes the calls to the hub part of the trace of the CLI command
+func withCommandSpan(ctx context.Context) context.Context {
+	if commandSpan == nil || trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	return trace.ContextWithSpan(ctx, commandSpan)
+}
+
 func ConnectToHubFunc(conf *hub.Config) (idl.HubClient, error) {
 	var conn *grpc.ClientConn
 
@@ -94,14 +170,23 @@ func ConnectToHubFunc(conf *hub.Config) (idl.HubClient, error) {
 	}
 
 	address := fmt.Sprintf("localhost:%d", conf.Port)
-	conn, err = DialContextFunc(ctx, address,
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(credentials),
 		grpc.WithBlock(),
 		grpc.FailOnNonTempDialError(true),
 		grpc.WithReturnConnectionError(),
-	)
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withCommandSpan(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withCommandSpan(ctx), desc, cc, method, opts...)
+		}),
+	}
+	opts = append(opts, utils.TracingDialOptions()...)
+	opts = append(opts, InterruptDialOptions()...)
+	conn, err = DialContextFunc(ctx, address, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("could not connect to hub on port %d: %w", conf.Port, err)
+		return nil, utils.NewGrpcError(codes.Unavailable, fmt.Errorf("could not connect to hub on port %d: %w", conf.Port, err))
 	}
 
 	return idl.NewHubClient(conn), nil
@@ -50,6 +50,17 @@ func resetCLIVars() {
 	cli.SetDefaultLocale = cli.SetDefaultLocaleFn
 	cli.ParseStreamResponse = cli.ParseStreamResponseFn
 	cli.IsGpServicesEnabled = cli.IsGpServicesEnabledFn
+	cli.AdoptCluster = cli.AdoptClusterFn
+	cli.GetAdoptedHostnames = cli.GetAdoptedHostnamesFn
+	cli.PrefixOutput, cli.OutputHostsFilter, cli.OutputStreamFilter = false, nil, ""
+	cli.EventsSince, cli.EventsOperation, cli.EventsHost = "", "", ""
+	cli.TopologyRestoreForce = false
+	cli.TopologyExportVersion = 0
+	cli.TopologyExportFile = ""
+	cli.TopologyExportFormat = ""
+	cli.TopologyValidateMirroringType = ""
+	cli.TopologyValidateBlockSize = 0
+	cli.TopologyValidateFailureDomains = nil
 }
 
 func funcNilError() func() error {
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+// filters of the events recorded by the hub, set by the options of gp events
+var (
+	EventsSince     string
+	EventsOperation string
+	EventsHost      string
+)
+
+func eventsCmd() *cobra.Command {
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Review the events of the operations recorded by the hub",
+		Long: `Review the events of the operations recorded by the hub.
+
+The hub records who started each operation, like gp init cluster, along with
+its log messages, output, stages, the state changes of the segments and its
+result, so that they can be reviewed after the operation completed.`,
+	}
+
+	eventsCmd.AddCommand(
+		eventsListCmd(),
+		eventsShowCmd(),
+	)
+
+	return eventsCmd
+}
+
+func eventsListCmd() *cobra.Command {
+	eventsListCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the recorded operations with who started them and their result",
+		Args:    cobra.NoArgs,
+		PreRunE: InitializeCommand,
+		RunE:    RunEventsList,
+	}
+	addEventsFlags(eventsListCmd)
+
+	return eventsListCmd
+}
+
+func eventsShowCmd() *cobra.Command {
+	eventsShowCmd := &cobra.Command{
+		Use:     "show",
+		Short:   "Show the recorded events of the operations",
+		Args:    cobra.NoArgs,
+		PreRunE: InitializeCommand,
+		RunE:    RunEventsShow,
+	}
+	addEventsFlags(eventsShowCmd)
+
+	return eventsShowCmd
+}
+
+func addEventsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&EventsSince, "since", "", `Only the events since the given time, either a duration like "2h" or a date like "2023-05-01" or "2023-05-01T10:00:00Z"`)
+	cmd.Flags().StringVar(&EventsOperation, "operation", "", `Only the events of the operation with the given ID`)
+	cmd.Flags().StringVar(&EventsHost, "host", "", `Only the events about the given host, or the operations with any, for list`)
+}
+
+func RunEventsList(cmd *cobra.Command, args []string) error {
+	events, err := listEvents(true)
+	if err != nil {
+		return err
+	}
+
+	displayOperationEvents(events)
+
+	return nil
+}
+
+func RunEventsShow(cmd *cobra.Command, args []string) error {
+	events, err := listEvents(false)
+	if err != nil {
+		return err
+	}
+
+	displayStoredEvents(events)
+
+	return nil
+}
+
+// listEvents fetches the events matching the filters from the hub
+func listEvents(operationsOnly bool) ([]*idl.StoredEvent, error) {
+	request := &idl.ListEventsRequest{
+		OperationId:    EventsOperation,
+		Host:           EventsHost,
+		OperationsOnly: operationsOnly,
+	}
+	if EventsSince != "" {
+		since, err := parseSince(EventsSince, time.Now())
+		if err != nil {
+			return nil, &UsageError{err}
+		}
+		request.Since = since.UnixMilli()
+	}
+
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return nil, err
+	}
+
+	// the hub returns the events a page at a time
+	var events []*idl.StoredEvent
+	for {
+		reply, err := client.ListEvents(context.Background(), request)
+		if err != nil {
+			return nil, utils.FormatGrpcError(err)
+		}
+
+		events = append(events, reply.Events...)
+		if reply.NextPageToken == "" {
+			return events, nil
+		}
+		request.PageToken = reply.NextPageToken
+	}
+}
+
+// parseSince returns the time given as a duration before now, a date or a RFC 3339 time
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if since, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return since, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since %q, expected a duration like 2h or a date like 2023-05-01", value)
+}
+
+/*
+displayOperationEvents shows the recorded operations as a table, one line per
+operation with its last state, or writes their events when the output is
+machine-readable.
+*/
+func displayOperationEvents(events []*idl.StoredEvent) {
+	if IsMachineOutput() {
+		displayStoredEvents(events)
+		return
+	}
+
+	var ids []string
+	started := make(map[string]*idl.StoredEvent)
+	last := make(map[string]*idl.StoredEvent)
+	for _, event := range events {
+		if _, ok := started[event.OperationId]; !ok {
+			ids = append(ids, event.OperationId)
+			started[event.OperationId] = event
+		}
+		last[event.OperationId] = event
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+
+	fmt.Fprintln(w, "ID\tMETHOD\tCALLER\tSTATE\tSTARTED\tENDED\tERROR")
+	for _, id := range ids {
+		start, end := started[id], last[id]
+		ended := "-"
+		if end != start {
+			ended = formatEventTime(end.Timestamp)
+		}
+		caller := hub.LockCaller{Holder: start.Caller, ClaimedBy: start.ClaimedBy}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", id, start.Method, caller, end.State, formatEventTime(start.Timestamp), ended, end.Error)
+	}
+	w.Flush()
+}
+
+// displayStoredEvents shows the recorded events as a table or as events of their own type
+func displayStoredEvents(events []*idl.StoredEvent) {
+	if !IsMachineOutput() {
+		w := new(tabwriter.Writer)
+		w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+
+		fmt.Fprintln(w, "TIME\tOPERATION\tTYPE\tSTAGE\tHOST\tSEGMENT\tSTATE\tMESSAGE")
+		for _, event := range events {
+			message := event.Message
+			if event.Error != "" {
+				message = event.Error
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", formatEventTime(event.Timestamp), event.OperationId, event.Type,
+				event.Stage, event.Host, event.Segment, event.State, message)
+		}
+		w.Flush()
+
+		return
+	}
+
+	for _, event := range events {
+		eventType := event.Type
+		if eventType == EventResult {
+			// the result of a recorded operation is its summary, not the result of this command
+			eventType = EventSummary
+		}
+
+		EmitEvent(Event{
+			Type:          eventType,
+			Time:          time.UnixMilli(event.Timestamp).UTC(),
+			OperationID:   event.OperationId,
+			Method:        event.Method,
+			Caller:        event.Caller,
+			ClaimedBy:     event.ClaimedBy,
+			State:         event.State,
+			Level:         event.Level,
+			Stage:         event.Stage,
+			Host:          event.Host,
+			DataDirectory: event.Segment,
+			Dbid:          int(event.Dbid),
+			Message:       event.Message,
+			Error:         event.Error,
+			Current:       int(event.Current),
+			Total:         int(event.Total),
+		})
+	}
+}
+
+func formatEventTime(timestamp int64) string {
+	return time.UnixMilli(timestamp).Format(time.RFC3339)
+}
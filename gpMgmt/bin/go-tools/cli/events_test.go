@@ -0,0 +1,220 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/cli"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/idl/mock_idl"
+)
+
+func TestRunEvents(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	startTime := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	operationEvents := []*idl.StoredEvent{
+		{Timestamp: startTime.UnixMilli(), OperationId: "1234", Method: "MakeCluster", Type: "operation", Caller: "gpadmin@cdw", State: "running"},
+		{Timestamp: startTime.Add(time.Minute).UnixMilli(), OperationId: "1234", Method: "MakeCluster", Type: "operation", Caller: "gpadmin@cdw", State: "failed", Error: "error"},
+	}
+
+	expectListEvents := func(expected *idl.ListEventsRequest, events []*idl.StoredEvent) {
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().ListEvents(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *idl.ListEventsRequest, opts ...grpc.CallOption) (*idl.ListEventsReply, error) {
+				if !reflect.DeepEqual(request, expected) {
+					t.Fatalf("got request %+v, want %+v", request, expected)
+				}
+
+				return &idl.ListEventsReply{Events: events}, nil
+			})
+			return hubClient, nil
+		}
+	}
+
+	t.Run("lists the operations matching the filters", func(t *testing.T) {
+		defer resetCLIVars()
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		cli.EventsSince = "2023-05-01T09:00:00Z"
+		cli.EventsHost = "sdw1"
+		expectListEvents(&idl.ListEventsRequest{
+			Since:          startTime.Add(-time.Hour).UnixMilli(),
+			Host:           "sdw1",
+			OperationsOnly: true,
+		}, operationEvents)
+
+		err := cli.RunEventsList(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := []cli.Event{
+			{Type: cli.EventOperation, OperationID: "1234", Method: "MakeCluster", Caller: "gpadmin@cdw", State: "running"},
+			{Type: cli.EventOperation, OperationID: "1234", Method: "MakeCluster", Caller: "gpadmin@cdw", State: "failed", Error: "error"},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("lists each operation once with its last state", func(t *testing.T) {
+		defer resetCLIVars()
+
+		expectListEvents(&idl.ListEventsRequest{OperationsOnly: true}, operationEvents)
+
+		oldStdout := os.Stdout
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		os.Stdout = writer
+		defer func() {
+			os.Stdout = oldStdout
+		}()
+
+		err = cli.RunEventsList(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		writer.Close()
+		out, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") {
+			t.Fatalf("got %q, want a header and one operation", out)
+		}
+		for _, field := range []string{"1234", "MakeCluster", "gpadmin@cdw", "failed", "error"} {
+			if !strings.Contains(lines[1], field) {
+				t.Fatalf("got %q, want it to contain %q", lines[1], field)
+			}
+		}
+	})
+
+	t.Run("shows the events of the operation", func(t *testing.T) {
+		defer resetCLIVars()
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		cli.EventsOperation = "1234"
+		expectListEvents(&idl.ListEventsRequest{OperationId: "1234"}, []*idl.StoredEvent{
+			{OperationId: "1234", Method: "MakeCluster", Type: "segment", State: "created", Host: "sdw1", Segment: "/data/primary/gpseg0", Dbid: 2},
+			{OperationId: "1234", Method: "MakeCluster", Type: "result", State: "failure", Error: "error"},
+		})
+
+		err := cli.RunEventsShow(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := []cli.Event{
+			{Type: cli.EventSegment, OperationID: "1234", Method: "MakeCluster", State: "created", Host: "sdw1", DataDirectory: "/data/primary/gpseg0", Dbid: 2},
+			{Type: cli.EventSummary, OperationID: "1234", Method: "MakeCluster", State: "failure", Error: "error"},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("fetches all the pages of events", func(t *testing.T) {
+		defer resetCLIVars()
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		var tokens []string
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().ListEvents(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *idl.ListEventsRequest, opts ...grpc.CallOption) (*idl.ListEventsReply, error) {
+				tokens = append(tokens, request.PageToken)
+				if request.PageToken == "" {
+					return &idl.ListEventsReply{Events: operationEvents[:1], NextPageToken: "1"}, nil
+				}
+
+				return &idl.ListEventsReply{Events: operationEvents[1:]}, nil
+			}).Times(2)
+			return hubClient, nil
+		}
+
+		err := cli.RunEventsShow(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		if !reflect.DeepEqual(tokens, []string{"", "1"}) {
+			t.Fatalf("got page tokens %q, want the first page and then page 1", tokens)
+		}
+		if events := readEvents(t, buffer); len(events) != len(operationEvents) {
+			t.Fatalf("got %+v, want the events of both pages", events)
+		}
+	})
+
+	t.Run("accepts a duration as the start of the events", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.EventsSince = "2h"
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().ListEvents(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *idl.ListEventsRequest, opts ...grpc.CallOption) (*idl.ListEventsReply, error) {
+				since := time.UnixMilli(request.Since)
+				if elapsed := time.Since(since); elapsed < 2*time.Hour || elapsed > 2*time.Hour+time.Minute {
+					t.Fatalf("got since %v, want 2 hours ago", since)
+				}
+
+				return &idl.ListEventsReply{}, nil
+			})
+			return hubClient, nil
+		}
+
+		err := cli.RunEventsShow(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("errors out when the start of the events is invalid", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.EventsSince = "yesterday"
+
+		err := cli.RunEventsShow(nil, nil)
+		var usageErr *cli.UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("got %#v, want a usage error", err)
+		}
+
+		expected := `invalid --since "yesterday", expected a duration like 2h or a date like 2023-05-01`
+		if err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("returns the error when not able to list the events", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().ListEvents(gomock.Any(), gomock.Any()).Return(nil, grpcStatus.Error(codes.PermissionDenied, "not allowed"))
+			return hubClient, nil
+		}
+
+		err := cli.RunEventsList(nil, nil)
+		if grpcStatus.Code(err) != codes.PermissionDenied || err.Error() != "not allowed" {
+			t.Fatalf("got %v, want not allowed", err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -15,28 +16,48 @@ import (
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpdb/gp/constants"
 	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
 	"github.com/greenplum-db/gpdb/gp/utils"
 	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
 )
 
 var (
-	Platform          = utils.GetPlatform()
-	DefaultServiceDir = Platform.GetDefaultServiceDir()
-	agentPort         int
-	caCertPath        string
-	caKeyPath         string
-	gpHome            string
-	hubLogDir         string
-	hubPort           int
-	hostnames         []string
-	hostfilePath      string
-	serverCertPath    string
-	serverKeyPath     string
-	serviceDir        string // Provide the service file's directory and name separately so users can name different files for different clusters
-	serviceName       string
-	serviceUser       string
-
-	GetUlimitSsh = GetUlimitSshFn
+	Platform           = utils.GetPlatform()
+	DefaultServiceDir  = Platform.GetDefaultServiceDir()
+	adopt              bool
+	agentPort          int
+	caCertPath         string
+	caKeyPath          string
+	clientCertPath     string
+	clientKeyPath      string
+	allowedCliClients  []string
+	allowedHubClients  []string
+	policyFilePath     string
+	configureCertsDir  string
+	generateCerts      bool
+	coordinatorDataDir string
+	gpHome             string
+	hubLogDir          string
+	hubPort            int
+	metricsPort        int
+	agentMetricsPort   int
+	parallel           int
+	parallelPerHost    int
+	hostnames          []string
+	hostfilePath       string
+	serverCertPath     string
+	serverKeyPath      string
+	serviceDir         string // Provide the service file's directory and name separately so users can name different files for different clusters
+	serviceName        string
+	serviceUser        string
+	traceExporter      string
+	traceEndpoint      string
+	traceInsecure      bool
+
+	GetUlimitSsh        = GetUlimitSshFn
+	certificateFlags    = []string{"ca-certificate", "ca-key", "server-certificate", "server-key"}
+	GetAdoptedHostnames = GetAdoptedHostnamesFn
+	AdoptCluster        = AdoptClusterFn
 )
 
 func hubCmd() *cobra.Command {
@@ -45,7 +66,7 @@ func hubCmd() *cobra.Command {
 		Short:   "Start a gp process in hub mode",
 		Long:    "Start a gp process in hub mode",
 		Hidden:  true, // Should only be invoked by systemd
-		PreRunE: InitializeCommand,
+		PreRunE: InitializeService,
 		RunE:    RunHub,
 	}
 
@@ -75,6 +96,13 @@ func configureCmd() *cobra.Command {
 	configureCmd.Flags().IntVar(&agentPort, "agent-port", constants.DefaultAgentPort, `Port on which the agents should listen`)
 	configureCmd.Flags().StringVar(&gpHome, "gphome", "/usr/local/greenplum-db", `Path to GPDB installation`)
 	configureCmd.Flags().IntVar(&hubPort, "hub-port", constants.DefaultHubPort, `Port on which the hub should listen`)
+	configureCmd.Flags().IntVar(&metricsPort, "metrics-port", 0, `Port on which the hub should serve the Prometheus metrics (default disabled)`)
+	configureCmd.Flags().IntVar(&agentMetricsPort, "agent-metrics-port", 0, `Port on which the agents should serve the Prometheus metrics (default disabled)`)
+	configureCmd.Flags().IntVar(&parallel, "parallel", hub.DefaultParallel, `Maximum number of requests the hub runs on the agents at the same time, like creating segments`)
+	configureCmd.Flags().IntVar(&parallelPerHost, "parallel-per-host", 0, `Maximum number of requests the hub runs on each agent at the same time (default no limit)`)
+	configureCmd.Flags().StringVar(&traceExporter, "trace-exporter", "", `Export the traces of the CLI, hub and agents using "otlp" or to a JSON "file" in the log directory (default disabled)`)
+	configureCmd.Flags().StringVar(&traceEndpoint, "trace-endpoint", "", `Host and port of the OTLP collector to export the traces to`)
+	configureCmd.Flags().BoolVar(&traceInsecure, "trace-insecure", false, `Connect to the OTLP collector without TLS`)
 	configureCmd.Flags().StringVar(&hubLogDir, "log-dir", greenplum.GetDefaultHubLogDir(), `Path to gp hub log directory`)
 	configureCmd.Flags().StringVar(&serviceName, "service-name", constants.DefaultServiceName, `Name for the generated systemd service file`)
 	configureCmd.Flags().StringVar(&serviceDir, "service-dir", fmt.Sprintf(DefaultServiceDir, os.Getenv("USER")), `Path to service file directory`)
@@ -84,19 +112,29 @@ func configureCmd() *cobra.Command {
 	configureCmd.Flags().StringVar(&caKeyPath, "ca-key", "", `Path to SSL/TLS CA private key`)
 	configureCmd.Flags().StringVar(&serverCertPath, "server-certificate", "", `Path to hub SSL/TLS server certificate`)
 	configureCmd.Flags().StringVar(&serverKeyPath, "server-key", "", `Path to hub SSL/TLS server private key`)
+	configureCmd.Flags().StringVar(&clientCertPath, "client-certificate", "", `Path to SSL/TLS client certificate used to connect to the hub and agents (default server certificate)`)
+	configureCmd.Flags().StringVar(&clientKeyPath, "client-key", "", `Path to SSL/TLS client private key used to connect to the hub and agents (default server key)`)
+	configureCmd.MarkFlagsRequiredTogether("client-certificate", "client-key")
+	// Optionally restrict the clients by the common name or SANs of their certificates
+	configureCmd.Flags().StringArrayVar(&allowedCliClients, "allowed-cli-client", []string{}, `Certificate common name or SAN of a client allowed to connect to the hub (default any client with a certificate signed by the CA)`)
+	configureCmd.Flags().StringArrayVar(&allowedHubClients, "allowed-hub-client", []string{}, `Certificate common name or SAN of a hub allowed to connect to the agents (default any client with a certificate signed by the CA)`)
+	configureCmd.Flags().StringVar(&policyFilePath, "authorization-policy", "", `Path to a JSON file listing the methods each client is allowed to call on the hub and agents (default all methods)`)
+	// Alternatively generate the certificates using a built-in CA and copy them to all the hosts
+	configureCmd.Flags().BoolVar(&generateCerts, "generate-certs", false, `Generate the SSL/TLS certificates for all the hosts using a built-in CA`)
+	configureCmd.Flags().StringVar(&configureCertsDir, "certs-dir", "", `Path to the directory in which the generated certificates are stored on all the hosts (default "<gphome>/certificates")`)
 	// Allow passing a hostfile for "real" use cases or a few host names for tests, but not both
 	configureCmd.Flags().StringArrayVar(&hostnames, "host", []string{}, `Segment hostname`)
 	configureCmd.Flags().StringVar(&hostfilePath, "hostfile", "", `Path to file containing a list of segment hostnames`)
 	configureCmd.MarkFlagsMutuallyExclusive("host", "hostfile")
-
-	requiredFlags := []string{
-		"ca-certificate",
-		"ca-key",
-		"server-certificate",
-		"server-key",
-	}
-	for _, flag := range requiredFlags {
-		configureCmd.MarkFlagRequired(flag) // nolint
+	// An existing cluster can be adopted instead, in which case the hostnames are read from its catalog
+	configureCmd.Flags().BoolVar(&adopt, "adopt", false, `Adopt an existing cluster, e.g. one created using gpinitsystem, into hub management`)
+	configureCmd.Flags().StringVar(&coordinatorDataDir, "coordinator-datadir", "", `Path to the coordinator data directory of the cluster to adopt`)
+	configureCmd.MarkFlagsMutuallyExclusive("adopt", "host")
+	configureCmd.MarkFlagsMutuallyExclusive("adopt", "hostfile")
+	configureCmd.MarkFlagsRequiredTogether("adopt", "coordinator-datadir")
+
+	for _, flag := range append(certificateFlags, "client-certificate", "client-key") {
+		configureCmd.MarkFlagsMutuallyExclusive("generate-certs", flag)
 	}
 
 	viper.BindPFlag("gphome", configureCmd.Flags().Lookup("gphome")) // nolint
@@ -110,6 +148,20 @@ func RunConfigure(cmd *cobra.Command, args []string) (err error) {
 		return fmt.Errorf("not a valid gpHome found\n")
 	}
 
+	if !generateCerts {
+		var missing []string
+		for _, flag := range certificateFlags {
+			if !cmd.Flags().Lookup(flag).Changed {
+				missing = append(missing, flag)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf(`required flag(s) "%s" not set, or use --generate-certs`, strings.Join(missing, `", "`))
+		}
+	} else if configureCertsDir == "" {
+		configureCertsDir = filepath.Join(gpHome, constants.DefaultCertsDirName)
+	}
+
 	// Regenerate default flag values if a custom GPHOME or username is passed
 	if !cmd.Flags().Lookup("config-file").Changed {
 		ConfigFilePath = filepath.Join(gpHome, constants.ConfigFileName)
@@ -119,26 +171,62 @@ func RunConfigure(cmd *cobra.Command, args []string) (err error) {
 		serviceDir = fmt.Sprintf(DefaultServiceDir, serviceUser)
 	}
 
-	if !cmd.Flags().Lookup("host").Changed && !cmd.Flags().Lookup("hostfile").Changed {
-		return errors.New("at least one hostname must be provided using either --host or --hostfile")
+	if !adopt && !cmd.Flags().Lookup("host").Changed && !cmd.Flags().Lookup("hostfile").Changed {
+		return errors.New("at least one hostname must be provided using either --host, --hostfile or --adopt")
 	}
 
 	if agentPort == hubPort {
 		return errors.New("hub port and agent port must be different")
 	}
 
+	if metricsPort != 0 && (metricsPort == hubPort || metricsPort == agentPort) {
+		return errors.New("metrics port must be different from the hub port and the agent port")
+	}
+
+	if agentMetricsPort != 0 && (agentMetricsPort == hubPort || agentMetricsPort == agentPort || agentMetricsPort == metricsPort) {
+		return errors.New("agent metrics port must be different from the hub port, the agent port and the metrics port")
+	}
+
+	if parallel <= 0 || parallelPerHost < 0 {
+		return errors.New("parallel must be a positive number and parallel per host must not be negative")
+	}
+
+	var tracing *utils.TracingConfig
+	if traceExporter != "" {
+		tracing = &utils.TracingConfig{Exporter: traceExporter, Endpoint: traceEndpoint, Insecure: traceInsecure}
+		err = tracing.Validate()
+		if err != nil {
+			return err
+		}
+	} else if traceEndpoint != "" {
+		return errors.New("trace endpoint can only be used along with a trace exporter")
+	}
+
 	// Convert file/directory paths to absolute path before writing to gp.Conf file
 	err = resolveAbsolutePaths()
 	if err != nil {
 		return err
 	}
 
+	if policyFilePath != "" {
+		_, err = utils.LoadAuthorizationPolicy(policyFilePath)
+		if err != nil {
+			return err
+		}
+	}
+
 	if cmd.Flags().Lookup("hostfile").Changed {
 		hostnames, err = GetHostnames(hostfilePath)
 		if err != nil {
 			return err
 		}
 	}
+	if adopt {
+		hostnames, err = GetAdoptedHostnames(coordinatorDataDir)
+		if err != nil {
+			return err
+		}
+	}
 	if len(hostnames) < 1 {
 		return fmt.Errorf("expected at least one host or hostlist specified")
 	}
@@ -147,19 +235,39 @@ func RunConfigure(cmd *cobra.Command, args []string) (err error) {
 			return fmt.Errorf("empty host name found -- please provide a valid input host name")
 		}
 	}
+	credentials := &utils.GpCredentials{
+		CACertPath:     caCertPath,
+		CAKeyPath:      caKeyPath,
+		ServerCertPath: serverCertPath,
+		ServerKeyPath:  serverKeyPath,
+		ClientCertPath: clientCertPath,
+		ClientKeyPath:  clientKeyPath,
+	}
+	if generateCerts {
+		credentials, err = SetupCertificates(configureCertsDir, hostnames, gpHome)
+		if err != nil {
+			return err
+		}
+	}
+
 	Conf = &hub.Config{
-		Port:        hubPort,
-		AgentPort:   agentPort,
-		Hostnames:   hostnames,
-		LogDir:      hubLogDir,
-		ServiceName: serviceName,
-		GpHome:      gpHome,
-		Credentials: &utils.GpCredentials{
-			CACertPath:     caCertPath,
-			CAKeyPath:      caKeyPath,
-			ServerCertPath: serverCertPath,
-			ServerKeyPath:  serverKeyPath,
-		},
+		Port:               hubPort,
+		AgentPort:          agentPort,
+		Hostnames:          hostnames,
+		LogDir:             hubLogDir,
+		ServiceName:        serviceName,
+		GpHome:             gpHome,
+		CoordinatorDataDir: coordinatorDataDir,
+		AllowedCliClients:  allowedCliClients,
+		AllowedHubClients:  allowedHubClients,
+		Credentials:        credentials,
+
+		AuthorizationPolicyFile: policyFilePath,
+		MetricsPort:             metricsPort,
+		AgentMetricsPort:        agentMetricsPort,
+		Tracing:                 tracing,
+		Parallel:                parallel,
+		ParallelPerHost:         parallelPerHost,
 	}
 	err = Conf.Write(ConfigFilePath)
 	if err != nil {
@@ -187,6 +295,62 @@ func RunConfigure(cmd *cobra.Command, args []string) (err error) {
 	}
 	CheckOpenFilesLimitOnHosts(hostnames)
 
+	if adopt {
+		return AdoptCluster(Conf)
+	}
+
+	return nil
+}
+
+/*
+GetAdoptedHostnamesFn returns the hostnames of all the segments of an existing
+cluster by reading the catalog of its coordinator. This function is expected to
+be called on the coordinator host only.
+*/
+func GetAdoptedHostnamesFn(coordinatorDataDir string) ([]string, error) {
+	conn, err := greenplum.GetCoordinatorConn(coordinatorDataDir, "", true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the coordinator of the cluster to adopt: %w", err)
+	}
+	defer conn.Close()
+
+	gparray, err := greenplum.NewGpArrayFromCatalog(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return gparray.GetHostnames(), nil
+}
+
+/*
+AdoptClusterFn starts the newly configured hub and agent services and asks the
+hub to adopt the cluster, which verifies the data directories on all the hosts
+and records the cluster topology in the hub state.
+*/
+func AdoptClusterFn(conf *hub.Config) error {
+	err := StartHubService(conf.ServiceName)
+	if err != nil {
+		return err
+	}
+
+	err = WaitAndRetryHubConnect()
+	if err != nil {
+		return err
+	}
+
+	client, err := StartAgentsAll(conf)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.AdoptCluster(withLockOptions(context.Background()), &idl.AdoptClusterRequest{
+		CoordinatorDataDir: conf.CoordinatorDataDir,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to adopt the cluster: %w", utils.FormatGrpcError(err))
+	}
+	gplog.Info("Successfully adopted the cluster with %d segments on hosts %s", reply.NumSegments, strings.Join(reply.Hostnames, ", "))
+
 	return nil
 }
 
@@ -259,6 +423,11 @@ type Response struct {
 
 func resolveAbsolutePaths() error {
 	paths := []*string{&caCertPath, &caKeyPath, &serverCertPath, &serverKeyPath, &hubLogDir, &gpHome}
+	for _, path := range []*string{&clientCertPath, &clientKeyPath, &coordinatorDataDir, &configureCertsDir, &policyFilePath} {
+		if *path != "" {
+			paths = append(paths, path)
+		}
+	}
 	for _, path := range paths {
 		p, err := filepath.Abs(*path)
 		if err != nil {
@@ -8,9 +8,13 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	"github.com/greenplum-db/gpdb/gp/cli"
 	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/idl/mock_idl"
 	"github.com/greenplum-db/gpdb/gp/testutils"
 	"github.com/greenplum-db/gpdb/gp/testutils/exectest"
 	"github.com/greenplum-db/gpdb/gp/utils"
@@ -174,3 +178,71 @@ func TestGetHostnames(t *testing.T) {
 		}
 	})
 }
+
+func TestAdoptCluster(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	t.Run("adopts the cluster using the hub", func(t *testing.T) {
+		defer resetCLIVars()
+		cli.StartHubService = func(serviceName string) error {
+			return nil
+		}
+		cli.WaitAndRetryHubConnect = func() error {
+			return nil
+		}
+
+		cli.Conf.CoordinatorDataDir = "/data/primary/gpseg-1"
+		defer func() { cli.Conf.CoordinatorDataDir = "" }()
+
+		cli.StartAgentsAll = func(hubConfig *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().AdoptCluster(gomock.Any(), &idl.AdoptClusterRequest{
+				CoordinatorDataDir: "/data/primary/gpseg-1",
+			}).Return(&idl.AdoptClusterReply{Hostnames: []string{"cdw", "sdw1"}, NumSegments: 2}, nil)
+
+			return hubClient, nil
+		}
+
+		err := cli.AdoptCluster(cli.Conf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("errors out when not able to start the hub", func(t *testing.T) {
+		defer resetCLIVars()
+		expectedErr := errors.New("error")
+		cli.StartHubService = func(serviceName string) error {
+			return expectedErr
+		}
+
+		err := cli.AdoptCluster(cli.Conf)
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %#v, want %#v", err, expectedErr)
+		}
+	})
+
+	t.Run("errors out when the hub fails to adopt the cluster", func(t *testing.T) {
+		defer resetCLIVars()
+		cli.StartHubService = func(serviceName string) error {
+			return nil
+		}
+		cli.WaitAndRetryHubConnect = func() error {
+			return nil
+		}
+
+		cli.StartAgentsAll = func(hubConfig *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().AdoptCluster(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+			return hubClient, nil
+		}
+
+		err := cli.AdoptCluster(cli.Conf)
+		expected := "failed to adopt the cluster: error"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
@@ -11,11 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpdb/gp/constants"
 	"github.com/greenplum-db/gpdb/gp/idl"
 	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
 )
 
 type Locale struct {
@@ -52,6 +55,8 @@ type InitConfig struct {
 	SegmentConfig     map[string]string `mapstructure:"segment-config"`
 	Coordinator       Segment           `mapstructure:"coordinator"`
 	SegmentArray      []SegmentPair     `mapstructure:"segment-array"`
+	// RefuseFilesystems lists the filesystem types, e.g. nfs or tmpfs, the data directories can not be on
+	RefuseFilesystems []string `mapstructure:"refuse-filesystems"`
 
 	//Expansion config parameters
 	PrimaryBasePort        int      `mapstructure:"primary-base-port"`
@@ -60,6 +65,13 @@ type InitConfig struct {
 	MirrorBasePort         int      `mapstructure:"mirror-base-port"`
 	MirrorDataDirectories  []string `mapstructure:"mirror-data-directories"`
 	MirroringType          string   `mapstructure:"mirroring-type"`
+	MirrorBlockSize        int      `mapstructure:"mirror-block-size"`
+	// FailureDomains maps the hostnames to their failure domain, e.g. their rack
+	FailureDomains map[string]string `mapstructure:"failure-domains"`
+
+	// the base ports set to auto in the config, allocated once the hosts are known
+	AutoPrimaryBasePort bool `mapstructure:"-"`
+	AutoMirrorBasePort  bool `mapstructure:"-"`
 }
 
 var (
@@ -106,12 +118,22 @@ func initClusterCmd() *cobra.Command {
 
 	initClusterCmd.PersistentFlags().BoolVar(&cliCleanFlag, "clean", false,
 		`cleans data directories created during GPDB cluster creation. To be called only upon failure`)
+	addLockFlags(initClusterCmd)
+	addParallelFlag(initClusterCmd)
+	addCommandOutputFlags(initClusterCmd)
 
 	return initClusterCmd
 }
 
 // RunInitClusterCmd driving function gets called from cobra on gp init cluster command
 func RunInitClusterCmd(cmd *cobra.Command, args []string) error {
+	if cliParallelFlag < 0 {
+		return fmt.Errorf("invalid --parallel %d, expected a positive number", cliParallelFlag)
+	}
+	if err := validateCommandOutputFlags(); err != nil {
+		return err
+	}
+
 	//Return error when gp init cluster --clean is passed with gp init cluster <config>.
 	//Example gp init cluster config --clean
 	if cliCleanFlag {
@@ -132,7 +154,7 @@ func RunInitClusterCmd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Call for further input config validation and cluster creation
-	err := InitClusterService(args[0], cliForceFlag, Verbose)
+	err := InitClusterService(cmd.Context(), args[0], cliForceFlag, Verbose)
 	if err != nil {
 		return err
 	}
@@ -152,8 +174,13 @@ func InitCleanFn(verbose bool) error {
 		return err
 	}
 
+	err = breakLockIfRequested(client)
+	if err != nil {
+		return err
+	}
+
 	// Call RPC on Hub to create the cluster
-	_, err = client.CleanInitCluster(context.Background(), &idl.CleanInitClusterRequest{})
+	_, err = client.CleanInitCluster(withParallelOption(withLockOptions(context.Background())), &idl.CleanInitClusterRequest{})
 	if err != nil {
 		return fmt.Errorf("clean cluster command failed: %w", err)
 	}
@@ -165,7 +192,7 @@ func InitCleanFn(verbose bool) error {
 /*
 InitClusterServiceFn does input config file validation followed by actual cluster creation
 */
-func InitClusterServiceFn(inputConfigFile string, force, verbose bool) error {
+func InitClusterServiceFn(ctx context.Context, inputConfigFile string, force, verbose bool) error {
 	_, err := utils.System.Stat(inputConfigFile)
 	if err != nil {
 		return err
@@ -180,7 +207,7 @@ func InitClusterServiceFn(inputConfigFile string, force, verbose bool) error {
 	}
 
 	// Load cluster-request from the config file
-	clusterReq, err := LoadInputConfigToIdl(inputConfigFile, cliHandler, force, verbose)
+	clusterReq, err := LoadInputConfigToIdl(ctx, inputConfigFile, cliHandler, force, verbose)
 	if err != nil {
 		return err
 	}
@@ -190,21 +217,39 @@ func InitClusterServiceFn(inputConfigFile string, force, verbose bool) error {
 		return err
 	}
 
+	if IsMachineOutput() {
+		emitClusterPlan(clusterReq)
+	}
+
+	err = breakLockIfRequested(HubClient)
+	if err != nil {
+		return err
+	}
+
 	// Call RPC on Hub to create the cluster
-	stream, err := HubClient.MakeCluster(context.Background(), clusterReq)
+	stream, err := HubClient.MakeCluster(withParallelOption(withLockOptions(ctx)), clusterReq)
 	if err != nil {
 		return utils.FormatGrpcError(err)
 	}
 
 	err = ParseStreamResponse(stream)
 
+	// the cluster is locked by another command, whose directories must not be rolled back
+	if grpcStatus.Code(err) == codes.FailedPrecondition {
+		return err
+	}
+
 	if err != nil {
 
 		//Call the cleanup routine only if the file exists
 		fileName := filepath.Join(Conf.LogDir, constants.CleanFileName)
 		_, statErr := utils.System.Stat(fileName)
 
-		if statErr == nil {
+		if statErr == nil && IsMachineOutput() {
+			// there is nobody to answer the prompt, leave the rollback to the caller
+			gplog.Info("Please run gp init cluster --clean to rollback")
+			return err
+		} else if statErr == nil {
 			//Log the error received from makeCluster
 			gplog.Error("Cluster creation failed, error %v ", err)
 			fmt.Println("Would you like to rollback?")
@@ -214,7 +259,7 @@ func InitClusterServiceFn(inputConfigFile string, force, verbose bool) error {
 			if input {
 				//The user has asked to delete the datadirectories
 				//Call Hub rpc for cleanup
-				_, err := HubClient.CleanInitCluster(context.Background(), &idl.CleanInitClusterRequest{})
+				_, err := HubClient.CleanInitCluster(withParallelOption(withLockOptions(ctx)), &idl.CleanInitClusterRequest{})
 				if err != nil {
 					return fmt.Errorf("clean cluster command failed: %v", err)
 				}
@@ -238,7 +283,7 @@ func InitClusterServiceFn(inputConfigFile string, force, verbose bool) error {
 /*
 LoadInputConfigToIdlFn reads config file and populates RPC IDL request structure
 */
-func LoadInputConfigToIdlFn(inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
+func LoadInputConfigToIdlFn(ctx context.Context, inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
 	cliHandler.SetConfigFile(inputConfigFile)
 
 	cliHandler.SetDefault("common-config", make(map[string]string))
@@ -250,10 +295,15 @@ func LoadInputConfigToIdlFn(inputConfigFile string, cliHandler *viper.Viper, for
 		return &idl.MakeClusterRequest{}, fmt.Errorf("while reading config file: %w", err)
 	}
 
+	// the base ports set to auto are unmarshalled as 0 and allocated once the hosts are known
+	autoPrimaryBasePort := setAutoBasePort(cliHandler, "primary-base-port")
+	autoMirrorBasePort := setAutoBasePort(cliHandler, "mirror-base-port")
+
 	var config InitConfig
 	if err := cliHandler.UnmarshalExact(&config); err != nil {
 		return &idl.MakeClusterRequest{}, fmt.Errorf("while unmarshaling config file: %w", err)
 	}
+	config.AutoPrimaryBasePort, config.AutoMirrorBasePort = autoPrimaryBasePort, autoMirrorBasePort
 
 	if AnyExpansionConfigPresent(cliHandler) {
 		// Validate expansion config
@@ -276,8 +326,16 @@ func LoadInputConfigToIdlFn(inputConfigFile string, cliHandler *viper.Viper, for
 			}
 		}
 
+		err = AllocateAutoBasePorts(ctx, &config)
+		if err != nil {
+			return &idl.MakeClusterRequest{}, err
+		}
+
 		//Expand details to config for primary
-		segmentPairArray := ExpandSegPairArray(config, isMultiHome, NameAddressMap, AddressNameMap)
+		segmentPairArray, err := ExpandSegPairArray(config, isMultiHome, NameAddressMap, AddressNameMap)
+		if err != nil {
+			return &idl.MakeClusterRequest{}, err
+		}
 		config.SegmentArray = segmentPairArray
 		// TODO to print expanded configuration here for user reference and print to file if required
 	}
@@ -325,7 +383,7 @@ func IsMultiHome(hostlist []string) (isMultiHome bool, NameAddress map[string][]
 	reply, err := HubClient.GetAllHostNames(context.Background(), &request)
 	if err != nil {
 
-		return false, nil, nil, utils.LogAndReturnError(fmt.Errorf("failed names of the host against address: %v", err))
+		return false, nil, nil, utils.LogAndReturnError(fmt.Errorf("failed names of the host against address: %w", err))
 	}
 	isMultiHome = false
 	NameAddressMap := make(map[string][]string)
@@ -400,18 +458,22 @@ func ValidateExpansionConfigAndSetDefault(config *InitConfig, cliHandle *viper.V
 		return fmt.Errorf("empty hostlist entry detected, Please provide valid hostlist")
 	}
 
-	if !cliHandle.IsSet("primary-base-port") {
-		defaultPrimaryBasePort := config.Coordinator.Port + 2
-		gplog.Warn("primary-base-port value not specified. Setting default to: %d", defaultPrimaryBasePort)
-		config.PrimaryBasePort = defaultPrimaryBasePort
-	}
+	if config.AutoPrimaryBasePort {
+		gplog.Info("primary-base-port set to auto, the lowest range of ports free on all the hosts will be used")
+	} else {
+		if !cliHandle.IsSet("primary-base-port") {
+			defaultPrimaryBasePort := config.Coordinator.Port + 2
+			gplog.Warn("primary-base-port value not specified. Setting default to: %d", defaultPrimaryBasePort)
+			config.PrimaryBasePort = defaultPrimaryBasePort
+		}
 
-	if cliHandle.IsSet("primary-base-port") && config.PrimaryBasePort < 1 {
-		return fmt.Errorf("invalid primary-base-port value provided: %d", config.PrimaryBasePort)
-	}
+		if cliHandle.IsSet("primary-base-port") && config.PrimaryBasePort < 1 {
+			return fmt.Errorf("invalid primary-base-port value provided: %d", config.PrimaryBasePort)
+		}
 
-	if config.PrimaryBasePort == config.Coordinator.Port {
-		return fmt.Errorf("coordinator port and primary-base-port value cannot be same. Please provide different values")
+		if config.PrimaryBasePort == config.Coordinator.Port {
+			return fmt.Errorf("coordinator port and primary-base-port value cannot be same. Please provide different values")
+		}
 	}
 
 	// Check if mandatory mirror expansion parameters are provided
@@ -426,22 +488,31 @@ func ValidateExpansionConfigAndSetDefault(config *InitConfig, cliHandle *viper.V
 		}
 
 		// validate mirror base port
-		if !cliHandle.IsSet("mirror-base-port") {
-			defaultMirrorBasePort := config.PrimaryBasePort + 1000
-			gplog.Warn("mirror-base-port value not specified. Setting default to: %d", defaultMirrorBasePort)
-			config.MirrorBasePort = defaultMirrorBasePort
+		if !config.AutoMirrorBasePort && !cliHandle.IsSet("mirror-base-port") && config.AutoPrimaryBasePort {
+			gplog.Warn("mirror-base-port value not specified. Setting it to auto like primary-base-port")
+			config.AutoMirrorBasePort = true
 		}
 
-		if config.MirrorBasePort < 1 {
-			return fmt.Errorf("invalid mirror-base-port value provided: %d", config.MirrorBasePort)
-		}
+		if config.AutoMirrorBasePort {
+			gplog.Info("mirror-base-port set to auto, the lowest range of ports free on all the hosts will be used")
+		} else {
+			if !cliHandle.IsSet("mirror-base-port") {
+				defaultMirrorBasePort := config.PrimaryBasePort + 1000
+				gplog.Warn("mirror-base-port value not specified. Setting default to: %d", defaultMirrorBasePort)
+				config.MirrorBasePort = defaultMirrorBasePort
+			}
 
-		if config.MirrorBasePort == config.Coordinator.Port {
-			return fmt.Errorf("coordinator port and mirror-base-port value cannot be same. Please provide different values")
-		}
+			if config.MirrorBasePort < 1 {
+				return fmt.Errorf("invalid mirror-base-port value provided: %d", config.MirrorBasePort)
+			}
+
+			if config.MirrorBasePort == config.Coordinator.Port {
+				return fmt.Errorf("coordinator port and mirror-base-port value cannot be same. Please provide different values")
+			}
 
-		if config.MirrorBasePort == config.PrimaryBasePort {
-			return fmt.Errorf("primary-base-port and mirror-base-port value cannot be same. Please provide different values")
+			if !config.AutoPrimaryBasePort && config.MirrorBasePort == config.PrimaryBasePort {
+				return fmt.Errorf("primary-base-port and mirror-base-port value cannot be same. Please provide different values")
+			}
 		}
 
 		//TODO Check if the primary and mirror range overlaps
@@ -453,8 +524,8 @@ func ValidateExpansionConfigAndSetDefault(config *InitConfig, cliHandle *viper.V
 		} else {
 			config.MirroringType = strings.ToLower(config.MirroringType)
 
-			if config.MirroringType != constants.SpreadMirroring && config.MirroringType != constants.GroupMirroring {
-				return fmt.Errorf("invalid mirroring-Type: %s. Valid options are 'group' and 'spread'", config.MirroringType)
+			if !slices.Contains([]string{constants.GroupMirroring, constants.SpreadMirroring, constants.BlockMirroring, constants.RackAwareMirroring}, config.MirroringType) {
+				return fmt.Errorf("invalid mirroring-Type: %s. Valid options are 'group', 'spread', 'block' and 'rack-aware'", config.MirroringType)
 			}
 		}
 
@@ -474,6 +545,77 @@ func ValidateExpansionConfigAndSetDefault(config *InitConfig, cliHandle *viper.V
 	return nil
 }
 
+// setAutoBasePort replaces the base port of the key with 0 when it is set to auto, returning whether it was
+func setAutoBasePort(cliHandle *viper.Viper, key string) bool {
+	if !strings.EqualFold(strings.TrimSpace(cliHandle.GetString(key)), constants.AutoBasePort) {
+		return false
+	}
+
+	cliHandle.Set(key, 0)
+	return true
+}
+
+/*
+AllocateAutoBasePorts sets the base ports set to auto to the lowest range of
+ports free on all the hosts, one port per data directory, as found by the hub.
+The primaries are allocated before the mirrors, and neither range contains the
+coordinator port or the ports of the other segments.
+*/
+func AllocateAutoBasePorts(ctx context.Context, config *InitConfig) error {
+	if config.AutoPrimaryBasePort {
+		excluded := []int32{int32(config.Coordinator.Port)}
+		if ContainsMirror && !config.AutoMirrorBasePort {
+			excluded = append(excluded, portRange(config.MirrorBasePort, len(config.MirrorDataDirectories))...)
+		}
+
+		basePort, err := findFreeBasePort(ctx, config.HostList, len(config.PrimaryDataDirectories), config.Coordinator.Port+2, excluded)
+		if err != nil {
+			return err
+		}
+		config.PrimaryBasePort = basePort
+		gplog.Info("Setting primary-base-port to %d, the lowest range of %d ports free on all the hosts", basePort, len(config.PrimaryDataDirectories))
+	}
+
+	if ContainsMirror && config.AutoMirrorBasePort {
+		excluded := append([]int32{int32(config.Coordinator.Port)}, portRange(config.PrimaryBasePort, len(config.PrimaryDataDirectories))...)
+
+		basePort, err := findFreeBasePort(ctx, config.HostList, len(config.MirrorDataDirectories), config.PrimaryBasePort+len(config.PrimaryDataDirectories), excluded)
+		if err != nil {
+			return err
+		}
+		config.MirrorBasePort = basePort
+		gplog.Info("Setting mirror-base-port to %d, the lowest range of %d ports free on all the hosts", basePort, len(config.MirrorDataDirectories))
+	}
+
+	return nil
+}
+
+// findFreeBasePort returns the lowest base port of count ports free on all the hosts, searched from the min port
+func findFreeBasePort(ctx context.Context, hostList []string, count int, minPort int, excluded []int32) (int, error) {
+	request := &idl.FindFreePortsRequest{
+		HostList:      hostList,
+		Count:         int32(count),
+		MinPort:       int32(minPort),
+		MaxPort:       int32(min(minPort+constants.AutoPortSearchSize-1, 65535)),
+		ExcludedPorts: excluded,
+	}
+	reply, err := HubClient.FindFreePorts(withParallelOption(ctx), request)
+	if err != nil {
+		return 0, utils.LogAndReturnError(fmt.Errorf("failed to find free ports on the hosts: %w", utils.FormatGrpcError(err)))
+	}
+
+	return int(reply.BasePort), nil
+}
+
+func portRange(basePort int, count int) []int32 {
+	var ports []int32
+	for i := 0; i < count; i++ {
+		ports = append(ports, int32(basePort+i))
+	}
+
+	return ports
+}
+
 func ExpandNonMultiHomePrimaryList(segPairList *[]SegmentPair, PrimaryBasePort int, PrimaryDataDirectories []string, hostList []string, addressNameMap map[string]string) *[]SegmentPair {
 	segNum := 0
 	for _, hostAddress := range hostList {
@@ -491,46 +633,11 @@ func ExpandNonMultiHomePrimaryList(segPairList *[]SegmentPair, PrimaryBasePort i
 	return segPairList
 }
 func ExpandNonMultiHomeGroupMirrorList(segPairList *[]SegmentPair, mirrorBasePort int, mirrorDataDirectories []string, hostList []string, addressNameMap map[string]string) *[]SegmentPair {
-	segNum := 0
-	hostListLen := len(hostList)
-	for hostIdx := range hostList {
-		for segIdx, directory := range mirrorDataDirectories {
-			hostAddress := hostList[(hostIdx+1)%hostListLen]
-			seg := Segment{
-				Hostname:      addressNameMap[hostAddress],
-				Address:       hostAddress,
-				Port:          mirrorBasePort + segIdx,
-				DataDirectory: filepath.Join(directory, fmt.Sprintf("%s%d", constants.DefaultSegName, segNum)),
-			}
-			(*segPairList)[segNum].Mirror = &seg
-			segNum++
-		}
-	}
-
-	return segPairList
+	return ExpandMirrorList(segPairList, greenplum.GroupPlacement{}, mirrorBasePort, mirrorDataDirectories, NonMultiHomePlacementHosts(hostList, addressNameMap, nil))
 }
 
 func ExpandNonMultiHomeSpreadMirroring(segPairList *[]SegmentPair, mirrorBasePort int, mirrorDataDirectories []string, hostList []string, addressNameMap map[string]string) *[]SegmentPair {
-	segmentsPerHost := len(mirrorDataDirectories)
-	hostListLen := len(hostList)
-	segNum := 0
-	for hostIndex := range hostList {
-		mirrorHostIndex := (hostIndex + 1) % hostListLen
-		for localSeg := 0; localSeg < segmentsPerHost; localSeg++ {
-			hostAddress := hostList[mirrorHostIndex]
-			seg := Segment{
-				Hostname:      addressNameMap[hostAddress],
-				Address:       hostAddress,
-				Port:          mirrorBasePort + localSeg,
-				DataDirectory: filepath.Join(mirrorDataDirectories[localSeg], fmt.Sprintf("%s%d", constants.DefaultSegName, segNum)),
-			}
-			(*segPairList)[segNum].Mirror = &seg
-			segNum++
-			mirrorHostIndex = (mirrorHostIndex + 1) % hostListLen
-		}
-	}
-
-	return segPairList
+	return ExpandMirrorList(segPairList, greenplum.SpreadPlacement{}, mirrorBasePort, mirrorDataDirectories, NonMultiHomePlacementHosts(hostList, addressNameMap, nil))
 }
 
 func ExpandMultiHomePrimaryArray(segPairList *[]SegmentPair, primaryBasePort int, primaryDataDirectories []string, hostnameArray []string, nameAddressMap map[string][]string) *[]SegmentPair {
@@ -552,18 +659,28 @@ func ExpandMultiHomePrimaryArray(segPairList *[]SegmentPair, primaryBasePort int
 }
 
 func ExpandMultiHomeGroupMirrorList(segPairList *[]SegmentPair, mirrorBasePort int, mirrorDataDirectories []string, hostnameArray []string, nameAddressMap map[string][]string) *[]SegmentPair {
-	uniqueHostCount := len(hostnameArray)
+	return ExpandMirrorList(segPairList, greenplum.GroupPlacement{}, mirrorBasePort, mirrorDataDirectories, MultiHomePlacementHosts(hostnameArray, nameAddressMap, nil))
+}
+
+func ExpandMultiHomeSpreadMirrorList(segPairList *[]SegmentPair, mirrorBasePort int, mirrorDataDirectories []string, hostnameArray []string, nameAddressMap map[string][]string) *[]SegmentPair {
+	return ExpandMirrorList(segPairList, greenplum.SpreadPlacement{}, mirrorBasePort, mirrorDataDirectories, MultiHomePlacementHosts(hostnameArray, nameAddressMap, nil))
+}
+
+/*
+ExpandMirrorList adds the mirrors of the primaries of the hosts, expanded in
+the same host order, placed with the given strategy. The mirror placed in slot
+k of a host uses the k-th mirror data directory and the mirror base port + k.
+*/
+func ExpandMirrorList(segPairList *[]SegmentPair, strategy greenplum.PlacementStrategy, mirrorBasePort int, mirrorDataDirectories []string, hosts []greenplum.PlacementHost) *[]SegmentPair {
 	segNum := 0
-	// Group mirroring
-	for idx := 0; idx < uniqueHostCount; idx++ {
-		hostname := hostnameArray[(idx+1)%uniqueHostCount]
-		addressList := nameAddressMap[hostname]
-		for segIdx, directory := range mirrorDataDirectories {
+	for _, placements := range strategy.PlaceMirrors(hosts, len(mirrorDataDirectories)) {
+		for _, placement := range placements {
+			host := hosts[placement.Host]
 			seg := Segment{
-				Hostname:      hostname,
-				Address:       addressList[segIdx%len(addressList)],
-				Port:          mirrorBasePort + segIdx,
-				DataDirectory: filepath.Join(directory, fmt.Sprintf("%s%d", constants.DefaultSegName, segNum)),
+				Hostname:      host.Hostname,
+				Address:       host.Addresses[placement.Address%len(host.Addresses)],
+				Port:          mirrorBasePort + placement.Slot,
+				DataDirectory: filepath.Join(mirrorDataDirectories[placement.Slot], fmt.Sprintf("%s%d", constants.DefaultSegName, segNum)),
 			}
 			(*segPairList)[segNum].Mirror = &seg
 			segNum++
@@ -573,39 +690,54 @@ func ExpandMultiHomeGroupMirrorList(segPairList *[]SegmentPair, mirrorBasePort i
 	return segPairList
 }
 
-func ExpandMultiHomeSpreadMirrorList(segPairList *[]SegmentPair, mirrorBasePort int, mirrorDataDirectories []string, hostnameArray []string, nameAddressMap map[string][]string) *[]SegmentPair {
-	segNum := 0
-	uniqueHostCount := len(hostnameArray)
-	for hostnameIdx := range hostnameArray {
-		for segIdx, directory := range mirrorDataDirectories {
-			nxtHostIdx := (hostnameIdx + segIdx + 1) % uniqueHostCount
-			// if the current hostname and mirror hostname is same, move to next
-			if nxtHostIdx == hostnameIdx {
-				nxtHostIdx = (nxtHostIdx + 1) % uniqueHostCount
-			}
-			nxtHostName := hostnameArray[nxtHostIdx]
-			addressList := nameAddressMap[nxtHostName]
-			addressCnt := len(addressList)
-			seg := Segment{
-				Address:       addressList[(hostnameIdx+segIdx)%addressCnt],
-				Hostname:      nxtHostName,
-				Port:          mirrorBasePort + segIdx,
-				DataDirectory: filepath.Join(directory, fmt.Sprintf("%s%d", constants.DefaultSegName, segNum)),
-			}
-			(*segPairList)[segNum].Mirror = &seg
-			segNum++
+// NonMultiHomePlacementHosts returns the hosts of the host list, each with its single address
+func NonMultiHomePlacementHosts(hostList []string, addressNameMap map[string]string, failureDomains map[string]string) []greenplum.PlacementHost {
+	var hosts []greenplum.PlacementHost
+	for _, hostAddress := range hostList {
+		hostname := addressNameMap[hostAddress]
+		hosts = append(hosts, greenplum.PlacementHost{
+			Hostname:      hostname,
+			Addresses:     []string{hostAddress},
+			FailureDomain: failureDomain(failureDomains, hostname, hostAddress),
+		})
+	}
+
+	return hosts
+}
+
+// MultiHomePlacementHosts returns the unique hosts of a multi-home setup with all their addresses
+func MultiHomePlacementHosts(hostnameArray []string, nameAddressMap map[string][]string, failureDomains map[string]string) []greenplum.PlacementHost {
+	var hosts []greenplum.PlacementHost
+	for _, hostname := range hostnameArray {
+		hosts = append(hosts, greenplum.PlacementHost{
+			Hostname:      hostname,
+			Addresses:     nameAddressMap[hostname],
+			FailureDomain: failureDomain(failureDomains, hostname, nameAddressMap[hostname]...),
+		})
+	}
+
+	return hosts
+}
+
+// failureDomain returns the failure domain of the host, declared for its hostname or one of its addresses
+func failureDomain(failureDomains map[string]string, hostname string, addresses ...string) string {
+	for _, name := range append([]string{hostname}, addresses...) {
+		if domain, ok := failureDomains[name]; ok {
+			return domain
 		}
 	}
 
-	return segPairList
+	return ""
 }
 
 /*
 ExpandSegPairArray expands primary and mirror configuration from the given configuration
-Returns an array of segmentPair to be updated in the MakeCluster request
+Returns an array of segmentPair to be updated in the MakeCluster request, or an error
+when the mirrors can not be placed with the mirroring type of the configuration
 */
-func ExpandSegPairArray(config InitConfig, multiHome bool, nameAddressMap map[string][]string, addressNameMap map[string]string) []SegmentPair {
+func ExpandSegPairArray(config InitConfig, multiHome bool, nameAddressMap map[string][]string, addressNameMap map[string]string) ([]SegmentPair, error) {
 	var segPairList []SegmentPair
+	var hosts []greenplum.PlacementHost
 
 	slices.Sort(config.HostList)
 	if multiHome {
@@ -618,35 +750,30 @@ func ExpandSegPairArray(config InitConfig, multiHome bool, nameAddressMap map[st
 
 		// Expand Primaries
 		segPairList = *ExpandMultiHomePrimaryArray(&segPairList, config.PrimaryBasePort, config.PrimaryDataDirectories, hostnameArray, nameAddressMap)
-
-		// Add mirrors to this expansion
-		if ContainsMirror {
-			if config.MirroringType == constants.GroupMirroring {
-				segPairList = *ExpandMultiHomeGroupMirrorList(&segPairList, config.MirrorBasePort, config.MirrorDataDirectories, hostnameArray, nameAddressMap)
-			} else {
-				// Spread mirroring
-				segPairList = *ExpandMultiHomeSpreadMirrorList(&segPairList, config.MirrorBasePort, config.MirrorDataDirectories, hostnameArray, nameAddressMap)
-			}
-		}
-		return segPairList
+		hosts = MultiHomePlacementHosts(hostnameArray, nameAddressMap, config.FailureDomains)
 	} else {
 		// non-multi-home setup,
 		// Create Primary segments
 		segPairList = *ExpandNonMultiHomePrimaryList(&segPairList, config.PrimaryBasePort, config.PrimaryDataDirectories, config.HostList, addressNameMap)
+		hosts = NonMultiHomePlacementHosts(config.HostList, addressNameMap, config.FailureDomains)
+	}
 
-		if ContainsMirror {
-			if config.MirroringType == constants.GroupMirroring {
-				// Perform group mirroring
-				segPairList = *ExpandNonMultiHomeGroupMirrorList(&segPairList, config.MirrorBasePort, config.MirrorDataDirectories, config.HostList, addressNameMap)
-			} else {
-				// Perform spread mirroring
-				segPairList = *ExpandNonMultiHomeSpreadMirroring(&segPairList, config.MirrorBasePort, config.MirrorDataDirectories, config.HostList, addressNameMap)
-			}
-		}
-		//End of non-multi-home expansion
+	if !ContainsMirror {
+		return segPairList, nil
 	}
 
-	return segPairList
+	// Add mirrors to this expansion
+	strategy, err := greenplum.NewPlacementStrategy(config.MirroringType, config.MirrorBlockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	err = strategy.Check(hosts, len(config.MirrorDataDirectories))
+	if err != nil {
+		return nil, err
+	}
+
+	return *ExpandMirrorList(&segPairList, strategy, config.MirrorBasePort, config.MirrorDataDirectories, hosts), nil
 }
 
 /*
@@ -663,9 +790,10 @@ func CreateMakeClusterReq(config *InitConfig, forceFlag bool, verbose bool) *idl
 			Coordinator:  SegmentToIdl(&config.Coordinator),
 			SegmentArray: segmentPairs,
 		},
-		ClusterParams: ClusterParamsToIdl(config),
-		ForceFlag:     forceFlag,
-		Verbose:       verbose,
+		ClusterParams:      ClusterParamsToIdl(config),
+		ForceFlag:          forceFlag,
+		Verbose:            verbose,
+		RefusedFilesystems: config.RefuseFilesystems,
 	}
 }
 
@@ -768,6 +896,22 @@ func ValidateInputConfigAndSetDefaultsFn(request *idl.MakeClusterRequest, cliHan
 		return err
 	}
 
+	// check the layout of the segments
+	opts := greenplum.ValidateOptions{
+		BlockSize:      cliHandler.GetInt("mirror-block-size"),
+		FailureDomains: cliHandler.GetStringMapString("failure-domains"),
+	}
+	if cliHandler.IsSet("mirroring-type") {
+		opts.MirroringType = strings.ToLower(cliHandler.GetString("mirroring-type"))
+	}
+	warnings, err := greenplum.NewGpArrayFromIdl(request.GpArray).Validate(opts)
+	for _, warning := range warnings {
+		gplog.Warn(warning)
+	}
+	if err != nil {
+		return err
+	}
+
 	// check if gp services enabled on hosts
 	err = IsGpServicesEnabled(request)
 	if err != nil {
@@ -823,32 +967,21 @@ func ValidateSegment(segment *idl.Segment) error {
 
 /*
 CheckForDuplicatePortAndDataDirectoryFn checks for duplicate data-directories and ports on host.
-In case of data-directories, look for unique host-names.
-For checking duplicate port, checking if address is unique. A host can use same the port for a different address.
+The segments listen on all the addresses of their host, so a port can not be used twice on a host
+even for different addresses.
 */
 func CheckForDuplicatePortAndDataDirectoryFn(segs []*idl.Segment) error {
-	hostToDataDirectory := make(map[string]map[string]bool)
-	hostToPort := make(map[string]map[int32]bool)
+	var greenplumSegs []greenplum.Segment
 	for _, seg := range segs {
-		//Check for data-directory
-		if _, ok := hostToDataDirectory[seg.HostName]; !ok {
-			hostToDataDirectory[seg.HostName] = make(map[string]bool)
-		}
-		if _, ok := hostToDataDirectory[seg.HostName][seg.DataDirectory]; ok {
-			return fmt.Errorf("duplicate data directory entry %v found for host %v", seg.DataDirectory, seg.HostAddress)
-		}
-		hostToDataDirectory[seg.HostName][seg.DataDirectory] = true
-
-		// Check for port
-		if _, ok := hostToPort[seg.HostAddress]; !ok {
-			hostToPort[seg.HostAddress] = make(map[int32]bool)
-		}
-		if _, ok := hostToPort[seg.HostName][seg.Port]; ok {
-			return fmt.Errorf("duplicate port entry %v found for host %v", seg.Port, seg.HostName)
-		}
-		hostToPort[seg.HostAddress][seg.Port] = true
+		greenplumSegs = append(greenplumSegs, greenplum.Segment{
+			Port:     int(seg.Port),
+			Hostname: seg.HostName,
+			Address:  seg.HostAddress,
+			DataDir:  seg.DataDirectory,
+		})
 	}
-	return nil
+
+	return greenplum.CheckForDuplicatePortAndDataDirectory(greenplumSegs)
 }
 
 /*
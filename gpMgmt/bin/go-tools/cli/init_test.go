@@ -1,9 +1,11 @@
 package cli_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -289,7 +291,7 @@ func TestValidateExpansionConfigAndSetDefault(t *testing.T) {
 	})
 
 	t.Run("returns error when unknown mirroring type provided", func(t *testing.T) {
-		testStr := "invalid mirroring-Type: unknown. Valid options are 'group' and 'spread'"
+		testStr := "invalid mirroring-Type: unknown. Valid options are 'group', 'spread', 'block' and 'rack-aware'"
 		cliHandle := viper.New()
 		basePort := 9000
 		config := &cli.InitConfig{PrimaryDataDirectories: []string{"/test"}, HostList: []string{"swd1"}, Coordinator: cli.Segment{Port: basePort},
@@ -305,6 +307,24 @@ func TestValidateExpansionConfigAndSetDefault(t *testing.T) {
 		}
 	})
 
+	t.Run("skips the checks of the base ports set to auto", func(t *testing.T) {
+		cliHandle := viper.New()
+		config := &cli.InitConfig{PrimaryDataDirectories: []string{"/test"}, HostList: []string{"swd1"}, Coordinator: cli.Segment{Port: 9000},
+			MirrorDataDirectories: []string{"/test1"}, MirroringType: constants.GroupMirroring, AutoPrimaryBasePort: true}
+		cliHandle.Set("mirroring-type", constants.GroupMirroring)
+		cliHandle.Set("primary-data-directories", []string{"/test"})
+		cliHandle.Set("primary-base-port", 0)
+		cliHandle.Set("hostlist", []string{"swd1"})
+
+		err := cli.ValidateExpansionConfigAndSetDefault(config, cliHandle)
+		if err != nil {
+			t.Fatalf("Got:%v, Expected no error", err)
+		}
+		if config.PrimaryBasePort != 0 || config.MirrorBasePort != 0 || !config.AutoMirrorBasePort {
+			t.Fatalf("got primary-base-port %d and mirror-base-port %d, want both set to auto", config.PrimaryBasePort, config.MirrorBasePort)
+		}
+	})
+
 	t.Run("sets default mirror port value properly", func(t *testing.T) {
 		cliHandle := viper.New()
 		basePort := 9000
@@ -389,6 +409,152 @@ func TestValidateExpansionConfigAndSetDefault(t *testing.T) {
 	})
 }
 
+func TestAllocateAutoBasePorts(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	t.Run("sets the base ports set to auto to the free ports found by the hub", func(t *testing.T) {
+		config := &cli.InitConfig{
+			Coordinator:            cli.Segment{Port: 7000},
+			HostList:               []string{"sdw1", "sdw2"},
+			PrimaryDataDirectories: []string{"/primary1", "/primary2"},
+			MirrorDataDirectories:  []string{"/mirror1", "/mirror2"},
+			AutoPrimaryBasePort:    true,
+			AutoMirrorBasePort:     true,
+		}
+		cli.ContainsMirror = true
+
+		hubClient := mock_idl.NewMockHubClient(ctrl)
+		hubClient.EXPECT().FindFreePorts(gomock.Any(), &idl.FindFreePortsRequest{
+			HostList: config.HostList, Count: 2, MinPort: 7002, MaxPort: 8001, ExcludedPorts: []int32{7000},
+		}).Return(&idl.FindFreePortsReply{BasePort: 7010}, nil)
+		hubClient.EXPECT().FindFreePorts(gomock.Any(), &idl.FindFreePortsRequest{
+			HostList: config.HostList, Count: 2, MinPort: 7012, MaxPort: 8011, ExcludedPorts: []int32{7000, 7010, 7011},
+		}).Return(&idl.FindFreePortsReply{BasePort: 7020}, nil)
+		oldHubClient := cli.HubClient
+		cli.HubClient = hubClient
+		defer func() { cli.HubClient = oldHubClient }()
+
+		err := cli.AllocateAutoBasePorts(context.Background(), config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.PrimaryBasePort != 7010 || config.MirrorBasePort != 7020 {
+			t.Fatalf("got primary-base-port %d and mirror-base-port %d, want 7010 and 7020", config.PrimaryBasePort, config.MirrorBasePort)
+		}
+	})
+
+	t.Run("keeps the primaries out of the ports of the mirrors with a fixed base port", func(t *testing.T) {
+		config := &cli.InitConfig{
+			Coordinator:            cli.Segment{Port: 7000},
+			HostList:               []string{"sdw1"},
+			PrimaryDataDirectories: []string{"/primary1"},
+			MirrorDataDirectories:  []string{"/mirror1"},
+			MirrorBasePort:         7002,
+			AutoPrimaryBasePort:    true,
+		}
+		cli.ContainsMirror = true
+
+		hubClient := mock_idl.NewMockHubClient(ctrl)
+		hubClient.EXPECT().FindFreePorts(gomock.Any(), &idl.FindFreePortsRequest{
+			HostList: config.HostList, Count: 1, MinPort: 7002, MaxPort: 8001, ExcludedPorts: []int32{7000, 7002},
+		}).Return(&idl.FindFreePortsReply{BasePort: 7003}, nil)
+		oldHubClient := cli.HubClient
+		cli.HubClient = hubClient
+		defer func() { cli.HubClient = oldHubClient }()
+
+		err := cli.AllocateAutoBasePorts(context.Background(), config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config.PrimaryBasePort != 7003 || config.MirrorBasePort != 7002 {
+			t.Fatalf("got primary-base-port %d and mirror-base-port %d, want 7003 and 7002", config.PrimaryBasePort, config.MirrorBasePort)
+		}
+	})
+
+	t.Run("returns error when the hub does not find free ports", func(t *testing.T) {
+		config := &cli.InitConfig{
+			Coordinator:            cli.Segment{Port: 7000},
+			HostList:               []string{"sdw1"},
+			PrimaryDataDirectories: []string{"/primary1"},
+			AutoPrimaryBasePort:    true,
+		}
+		cli.ContainsMirror = false
+
+		hubClient := mock_idl.NewMockHubClient(ctrl)
+		hubClient.EXPECT().FindFreePorts(gomock.Any(), gomock.Any()).Return(nil, errors.New("no range of 1 free ports found between 7002 and 8001 on all the hosts"))
+		oldHubClient := cli.HubClient
+		cli.HubClient = hubClient
+		defer func() { cli.HubClient = oldHubClient }()
+
+		err := cli.AllocateAutoBasePorts(context.Background(), config)
+		expected := "failed to find free ports on the hosts: no range of 1 free ports found between 7002 and 8001 on all the hosts"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
+
+func TestLoadInputConfigToIdlFn(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	t.Run("allocates the base ports set to auto", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.json")
+		err := os.WriteFile(configFile, []byte(`{
+			"coordinator": {"hostname": "cdw", "address": "cdw", "port": 7000, "data-directory": "/data/coordinator/gpseg-1"},
+			"hostlist": ["sdw1", "sdw2"],
+			"primary-base-port": "auto",
+			"primary-data-directories": ["/data/primary"],
+			"mirror-data-directories": ["/data/mirror"]
+		}`), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		hubClient := mock_idl.NewMockHubClient(ctrl)
+		hubClient.EXPECT().GetAllHostNames(gomock.Any(), gomock.Any()).Return(&idl.GetAllHostNamesReply{HostNameMap: map[string]string{"sdw1": "sdw1", "sdw2": "sdw2"}}, nil)
+		hubClient.EXPECT().FindFreePorts(gomock.Any(), gomock.Any()).Return(&idl.FindFreePortsReply{BasePort: 7002}, nil)
+		hubClient.EXPECT().FindFreePorts(gomock.Any(), gomock.Any()).Return(&idl.FindFreePortsReply{BasePort: 7003}, nil)
+		oldHubClient := cli.HubClient
+		cli.HubClient = hubClient
+		defer func() { cli.HubClient = oldHubClient }()
+
+		request, err := cli.LoadInputConfigToIdlFn(context.Background(), configFile, viper.New(), false, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, pair := range request.GpArray.SegmentArray {
+			if pair.Primary.Port != 7002 || pair.Mirror.Port != 7003 {
+				t.Fatalf("got primary port %d and mirror port %d, want 7002 and 7003", pair.Primary.Port, pair.Mirror.Port)
+			}
+		}
+	})
+
+	t.Run("passes the refused filesystems to the request", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "config.json")
+		err := os.WriteFile(configFile, []byte(`{
+			"coordinator": {"hostname": "cdw", "address": "cdw", "port": 7000, "data-directory": "/data/coordinator/gpseg-1"},
+			"segment-array": [{"primary": {"hostname": "sdw1", "address": "sdw1", "port": 7002, "data-directory": "/data/primary/gpseg0"}}],
+			"refuse-filesystems": ["nfs", "tmpfs"]
+		}`), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		request, err := cli.LoadInputConfigToIdlFn(context.Background(), configFile, viper.New(), false, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []string{"nfs", "tmpfs"}
+		if !reflect.DeepEqual(request.RefusedFilesystems, expected) {
+			t.Fatalf("got %v, want %v", request.RefusedFilesystems, expected)
+		}
+	})
+}
+
 func TestIsMultiHome(t *testing.T) {
 	setupTest(t)
 	defer teardownTest()
@@ -857,14 +1023,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = false
 
-		segPairList := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -889,14 +1058,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = false
 
-		segPairList := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -921,14 +1093,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = false
 
-		segPairList := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -953,14 +1128,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = false
 
-		segPairList := cli.ExpandSegPairArray(config, true, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, true, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -985,14 +1163,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = false
 
-		segPairList := cli.ExpandSegPairArray(config, true, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, true, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -1023,14 +1204,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = true
 
-		segPairList := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -1063,14 +1247,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = true
 
-		segPairList := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 6 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -1099,7 +1286,10 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = true
 
-		segPairList := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
@@ -1132,14 +1322,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = true
 
-		segPairList := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -1172,14 +1365,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = true
 
-		segPairList := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 6 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -1208,14 +1404,17 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = true
 
-		segPairList := cli.ExpandSegPairArray(config, true, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, true, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 4 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
@@ -1248,18 +1447,79 @@ func TestExpandSegPairArray(t *testing.T) {
 		}
 		cli.ContainsMirror = true
 
-		segPairList := cli.ExpandSegPairArray(config, true, nameAddressMap, addressNameMap)
+		segPairList, err := cli.ExpandSegPairArray(config, true, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 		if len(segPairList) != 6 {
 			t.Fatalf("Got segPairList length %d, expected length: 4", len(segPairList))
 		}
 		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
 			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
 		}
-		err := CheckIfPortConflict(&segPairList)
+		err = CheckIfPortConflict(&segPairList)
 		if err != nil {
 			t.Fatalf("Got:%v, expected no error", err)
 		}
 	})
+
+	t.Run("returns list of expansion for the given config contain primary and rack-aware mirrors non multi-home", func(t *testing.T) {
+		config := cli.InitConfig{
+			PrimaryBasePort:        9000,
+			PrimaryDataDirectories: []string{"/test"},
+			HostList:               []string{"sdw1", "sdw2", "sdw3", "sdw4"},
+			MirrorDataDirectories:  []string{"/mirror"},
+			MirrorBasePort:         10000,
+			MirroringType:          constants.RackAwareMirroring,
+			FailureDomains:         map[string]string{"sdw1": "rack1", "sdw2": "rack1", "sdw3": "rack2", "sdw4": "rack2"},
+		}
+		addressNameMap := map[string]string{"sdw1": "sdw1", "sdw2": "sdw2", "sdw3": "sdw3", "sdw4": "sdw4"}
+		nameAddressMap := map[string][]string{"sdw1": {"sdw1"}, "sdw2": {"sdw2"}, "sdw3": {"sdw3"}, "sdw4": {"sdw4"}}
+		expectedSegPairList := []cli.SegmentPair{
+			{Primary: &cli.Segment{Hostname: "sdw1", Address: "sdw1", Port: 9000, DataDirectory: "/test/gpseg0"},
+				Mirror: &cli.Segment{Hostname: "sdw3", Address: "sdw3", Port: 10000, DataDirectory: "/mirror/gpseg0"}},
+			{Primary: &cli.Segment{Hostname: "sdw2", Address: "sdw2", Port: 9000, DataDirectory: "/test/gpseg1"},
+				Mirror: &cli.Segment{Hostname: "sdw4", Address: "sdw4", Port: 10000, DataDirectory: "/mirror/gpseg1"}},
+			{Primary: &cli.Segment{Hostname: "sdw3", Address: "sdw3", Port: 9000, DataDirectory: "/test/gpseg2"},
+				Mirror: &cli.Segment{Hostname: "sdw1", Address: "sdw1", Port: 10000, DataDirectory: "/mirror/gpseg2"}},
+			{Primary: &cli.Segment{Hostname: "sdw4", Address: "sdw4", Port: 9000, DataDirectory: "/test/gpseg3"},
+				Mirror: &cli.Segment{Hostname: "sdw2", Address: "sdw2", Port: 10000, DataDirectory: "/mirror/gpseg3"}},
+		}
+		cli.ContainsMirror = true
+
+		segPairList, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(segPairList, expectedSegPairList) {
+			t.Fatalf("Got:%v, Want:%v", segPairList, expectedSegPairList)
+		}
+		err = CheckIfPortConflict(&segPairList)
+		if err != nil {
+			t.Fatalf("Got:%v, expected no error", err)
+		}
+	})
+
+	t.Run("returns error when the mirrors can not be placed with the mirroring type", func(t *testing.T) {
+		config := cli.InitConfig{
+			PrimaryBasePort:        9000,
+			PrimaryDataDirectories: []string{"/test"},
+			HostList:               []string{"sdw1", "sdw2", "sdw3"},
+			MirrorDataDirectories:  []string{"/mirror"},
+			MirrorBasePort:         10000,
+			MirroringType:          constants.BlockMirroring,
+			MirrorBlockSize:        2,
+		}
+		addressNameMap := map[string]string{"sdw1": "sdw1", "sdw2": "sdw2", "sdw3": "sdw3"}
+		nameAddressMap := map[string][]string{"sdw1": {"sdw1"}, "sdw2": {"sdw2"}, "sdw3": {"sdw3"}}
+		cli.ContainsMirror = true
+
+		_, err := cli.ExpandSegPairArray(config, false, nameAddressMap, addressNameMap)
+		expected := "block mirroring needs a number of hosts multiple of the block size 2, got 3 hosts"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Got:%v, Want:%s", err, expected)
+		}
+	})
 }
 func TestRunInitClusterCmd(t *testing.T) {
 	setupTest(t)
@@ -1285,7 +1545,7 @@ func TestRunInitClusterCmd(t *testing.T) {
 		testStr := "test-error"
 		cmd := cobra.Command{}
 		args := []string{"/tmp/1"}
-		cli.InitClusterService = func(inputConfigFile string, force, verbose bool) error {
+		cli.InitClusterService = func(ctx context.Context, inputConfigFile string, force, verbose bool) error {
 			return fmt.Errorf(testStr)
 		}
 		defer resetCLIVars()
@@ -1298,7 +1558,7 @@ func TestRunInitClusterCmd(t *testing.T) {
 
 		cmd := cobra.Command{}
 		args := []string{"/tmp/1"}
-		cli.InitClusterService = func(inputConfigFile string, force, verbose bool) error {
+		cli.InitClusterService = func(ctx context.Context, inputConfigFile string, force, verbose bool) error {
 			return nil
 		}
 		defer resetCLIVars()
@@ -1314,7 +1574,7 @@ func TestInitClusterService(t *testing.T) {
 
 	t.Run("fails if input config file does not exist", func(t *testing.T) {
 		defer resetCLIVars()
-		err := cli.InitClusterService("/tmp/invalid_file", false, false)
+		err := cli.InitClusterService(context.Background(), "/tmp/invalid_file", false, false)
 		if err == nil {
 			t.Fatalf("error was expected")
 		}
@@ -1327,13 +1587,13 @@ func TestInitClusterService(t *testing.T) {
 		utils.System.Stat = func(name string) (os.FileInfo, error) {
 			return nil, nil
 		}
-		cli.LoadInputConfigToIdl = func(inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
+		cli.LoadInputConfigToIdl = func(ctx context.Context, inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
 			return nil, fmt.Errorf(testStr)
 		}
 		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
 			return mock_idl.NewMockHubClient(ctrl), nil
 		}
-		err := cli.InitClusterService("/tmp/invalid_file", false, false)
+		err := cli.InitClusterService(context.Background(), "/tmp/invalid_file", false, false)
 		if err == nil || !strings.Contains(err.Error(), testStr) {
 			t.Fatalf("got %v, want %s", err, testStr)
 		}
@@ -1345,7 +1605,7 @@ func TestInitClusterService(t *testing.T) {
 		utils.System.Stat = func(name string) (os.FileInfo, error) {
 			return nil, nil
 		}
-		cli.LoadInputConfigToIdl = func(inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
+		cli.LoadInputConfigToIdl = func(ctx context.Context, inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
 			return nil, nil
 		}
 		cli.ValidateInputConfigAndSetDefaults = func(request *idl.MakeClusterRequest, cliHandler *viper.Viper) error {
@@ -1354,7 +1614,7 @@ func TestInitClusterService(t *testing.T) {
 		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
 			return mock_idl.NewMockHubClient(ctrl), nil
 		}
-		err := cli.InitClusterService("/tmp/invalid_file", false, false)
+		err := cli.InitClusterService(context.Background(), "/tmp/invalid_file", false, false)
 		if err == nil || !strings.Contains(err.Error(), testStr) {
 			t.Fatalf("got %v, want %s", err, testStr)
 		}
@@ -1366,7 +1626,7 @@ func TestInitClusterService(t *testing.T) {
 		utils.System.Stat = func(name string) (os.FileInfo, error) {
 			return nil, nil
 		}
-		cli.LoadInputConfigToIdl = func(inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
+		cli.LoadInputConfigToIdl = func(ctx context.Context, inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
 			return nil, nil
 		}
 		cli.ValidateInputConfigAndSetDefaults = func(request *idl.MakeClusterRequest, cliHandler *viper.Viper) error {
@@ -1375,7 +1635,7 @@ func TestInitClusterService(t *testing.T) {
 		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
 			return mock_idl.NewMockHubClient(ctrl), nil
 		}
-		err := cli.InitClusterService("/tmp/invalid_file", false, false)
+		err := cli.InitClusterService(context.Background(), "/tmp/invalid_file", false, false)
 		if err == nil || !strings.Contains(err.Error(), testStr) {
 			t.Fatalf("got %v, want %s", err, testStr)
 		}
@@ -1387,7 +1647,7 @@ func TestInitClusterService(t *testing.T) {
 		utils.System.Stat = func(name string) (os.FileInfo, error) {
 			return nil, nil
 		}
-		cli.LoadInputConfigToIdl = func(inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
+		cli.LoadInputConfigToIdl = func(ctx context.Context, inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
 			return nil, nil
 		}
 		cli.ValidateInputConfigAndSetDefaults = func(request *idl.MakeClusterRequest, cliHandler *viper.Viper) error {
@@ -1397,7 +1657,7 @@ func TestInitClusterService(t *testing.T) {
 			return nil, fmt.Errorf(testStr)
 		}
 
-		err := cli.InitClusterService("/tmp/invalid_file", false, false)
+		err := cli.InitClusterService(context.Background(), "/tmp/invalid_file", false, false)
 		if err == nil || !strings.Contains(err.Error(), testStr) {
 			t.Fatalf("got %v, want %v", err, testStr)
 		}
@@ -1409,7 +1669,7 @@ func TestInitClusterService(t *testing.T) {
 		utils.System.Stat = func(name string) (os.FileInfo, error) {
 			return nil, nil
 		}
-		cli.LoadInputConfigToIdl = func(inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
+		cli.LoadInputConfigToIdl = func(ctx context.Context, inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
 			return nil, nil
 		}
 		cli.ValidateInputConfigAndSetDefaults = func(request *idl.MakeClusterRequest, cliHandler *viper.Viper) error {
@@ -1421,7 +1681,7 @@ func TestInitClusterService(t *testing.T) {
 			return hubClient, nil
 		}
 
-		err := cli.InitClusterService("/tmp/invalid_file", false, false)
+		err := cli.InitClusterService(context.Background(), "/tmp/invalid_file", false, false)
 		if err == nil || !strings.Contains(err.Error(), testStr) {
 			t.Fatalf("got %v, want %v", err, testStr)
 		}
@@ -1435,7 +1695,7 @@ func TestInitClusterService(t *testing.T) {
 		utils.System.Stat = func(name string) (os.FileInfo, error) {
 			return nil, nil
 		}
-		cli.LoadInputConfigToIdl = func(inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
+		cli.LoadInputConfigToIdl = func(ctx context.Context, inputConfigFile string, cliHandler *viper.Viper, force bool, verbose bool) (*idl.MakeClusterRequest, error) {
 			return nil, nil
 		}
 		cli.ValidateInputConfigAndSetDefaults = func(request *idl.MakeClusterRequest, cliHandler *viper.Viper) error {
@@ -1449,7 +1709,7 @@ func TestInitClusterService(t *testing.T) {
 		cli.ParseStreamResponse = func(stream cli.StreamReceiver) error {
 			return fmt.Errorf(testStr)
 		}
-		err := cli.InitClusterService("/tmp/invalid_file", false, false)
+		err := cli.InitClusterService(context.Background(), "/tmp/invalid_file", false, false)
 		if err != nil {
 			t.Fatalf("unexpected error")
 		}
@@ -1690,6 +1950,53 @@ func TestValidateInputConfigAndSetDefaults(t *testing.T) {
 		expectedLogMsg := `shared_buffers is not set in CommonConfig, will set to default value 128000kB`
 		testutils.AssertLogMessage(t, logfile, expectedLogMsg)
 	})
+	t.Run("fails if a mirror is on the same host as its primary", func(t *testing.T) {
+		defer resetCLIVars()
+		defer initializeRequest()
+		defer resetConfHostnames()
+		for i, pair := range request.GpArray.SegmentArray {
+			pair.Mirror = &idl.Segment{
+				HostAddress:   pair.Primary.HostName,
+				HostName:      pair.Primary.HostName,
+				Port:          8002 + int32(i),
+				DataDirectory: fmt.Sprintf("/tmp/mirror/%d", i),
+			}
+		}
+		expectedError := "the mirror of content 0 is on the same host sdw1 as its primary"
+
+		err := cli.ValidateInputConfigAndSetDefaults(request, cliHandler)
+		if err == nil || !strings.Contains(err.Error(), expectedError) {
+			t.Fatalf("got %v, want %v", err, expectedError)
+		}
+	})
+	t.Run("fails if the mirrors are not placed as the mirroring type", func(t *testing.T) {
+		defer resetCLIVars()
+		defer initializeRequest()
+		defer resetConfHostnames()
+		for i, pair := range request.GpArray.SegmentArray {
+			host := "sdw2"
+			if pair.Primary.HostName == "sdw2" {
+				host = "sdw1"
+			}
+			pair.Mirror = &idl.Segment{
+				HostAddress:   host,
+				HostName:      host,
+				Port:          8002 + int32(i),
+				DataDirectory: fmt.Sprintf("/tmp/mirror/%d", i),
+			}
+		}
+		v := viper.New()
+		v.Set("coordinator", idl.Segment{})
+		v.Set("segment-array", []idl.Segment{})
+		v.Set("locale", idl.Locale{})
+		v.Set("mirroring-type", "Spread")
+		expectedError := "spread mirroring places the mirrors of the primaries of host sdw1 on different hosts, found 2 of them on host sdw2"
+
+		err := cli.ValidateInputConfigAndSetDefaults(request, v)
+		if err == nil || !strings.Contains(err.Error(), expectedError) {
+			t.Fatalf("got %v, want %v", err, expectedError)
+		}
+	})
 	t.Run("fails if port/directory duplicate check fails returns error", func(t *testing.T) {
 		defer resetCLIVars()
 		defer initializeRequest()
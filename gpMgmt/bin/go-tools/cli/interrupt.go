@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path"
+	"sync"
+	"syscall"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+// done once the command is interrupted, never if the interruption is not handled
+var interrupted = context.Background()
+
+/*
+NotifyInterrupt makes the command handle the first SIGINT or SIGTERM it gets,
+until the returned function is called:
+  - the unary calls to the hub are cancelled, which in turn cancels the calls
+    the hub makes to the agents and the commands they run
+  - the operations started by the command are cancelled on the hub, and their
+    replies are followed until they have cleaned up and stopped
+  - following an operation started by another command just stops following it
+
+Another interrupt then terminates the command right away, as usual.
+*/
+func NotifyInterrupt() func() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	interrupted = ctx
+
+	go func() {
+		<-ctx.Done()
+		// restore the default behaviour for the next interrupt
+		stop()
+	}()
+
+	return func() {
+		stop()
+		interrupted = context.Background()
+	}
+}
+
+// InterruptDialOptions make the calls to the hub handle the interruption of the command
+func InterruptDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			ctx, cancel := withInterrupt(ctx)
+			defer cancel()
+
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			if path.Base(method) == "AttachOperation" {
+				// the stream lasts as long as the command, so the context is not cancelled once it ends
+				ctx, cancel := withInterrupt(ctx)
+				stream, err := streamer(ctx, desc, cc, method, opts...)
+				if err != nil {
+					cancel()
+				}
+
+				return stream, err
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			stream, err := streamer(ctx, desc, cc, method, opts...)
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+
+			opStream := &operationClientStream{ClientStream: stream, client: idl.NewHubClient(cc), ctx: ctx, cancel: cancel}
+			go opStream.cancelOnInterrupt(interrupted)
+
+			return opStream, nil
+		}),
+	}
+}
+
+// marks the calls which still have to be made once the command is interrupted
+type uninterruptibleKey struct{}
+
+// withInterrupt returns a copy of the context which is also cancelled when the command is interrupted
+func withInterrupt(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx.Value(uninterruptibleKey{}) != nil {
+		return ctx, func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(interrupted, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+/*
+operationClientStream cancels the operation it streams the replies of when the
+command is interrupted, and keeps receiving its replies until it stops. If the
+command is interrupted before the operation has started, e.g. while waiting for
+the cluster lock, the stream is cancelled instead.
+*/
+type operationClientStream struct {
+	grpc.ClientStream
+	client idl.HubClient
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex       sync.Mutex
+	operationID string
+}
+
+func (s *operationClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		// the stream has ended
+		s.cancel()
+		return err
+	}
+
+	if reply, ok := m.(*idl.HubReply); ok && reply.GetOperationId() != "" {
+		s.mutex.Lock()
+		s.operationID = reply.GetOperationId()
+		s.mutex.Unlock()
+	}
+
+	return nil
+}
+
+func (s *operationClientStream) cancelOnInterrupt(interrupted context.Context) {
+	select {
+	case <-interrupted.Done():
+	case <-s.ctx.Done():
+		return
+	}
+
+	s.mutex.Lock()
+	id := s.operationID
+	s.mutex.Unlock()
+
+	if id == "" {
+		gplog.Warn("Interrupted before the operation has started")
+		s.cancel()
+		return
+	}
+
+	gplog.Warn("Interrupted, cancelling operation %s and waiting for it to clean up; interrupt again to exit right away", id)
+	ctx := context.WithValue(context.WithoutCancel(s.ctx), uninterruptibleKey{}, true)
+	_, err := s.client.CancelOperation(ctx, &idl.CancelOperationRequest{Id: id})
+	if err != nil {
+		gplog.Error("Not able to cancel operation %s: %v", id, err)
+	}
+}
@@ -0,0 +1,143 @@
+package cli_test
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/cli"
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+// interruptedHub blocks in its RPCs until they, or the operation they run, are cancelled
+type interruptedHub struct {
+	idl.UnimplementedHubServer
+	cancelled chan string
+}
+
+func (h *interruptedHub) StatusAgents(ctx context.Context, in *idl.StatusAgentsRequest) (*idl.StatusAgentsReply, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (h *interruptedHub) MakeCluster(in *idl.MakeClusterRequest, stream idl.Hub_MakeClusterServer) error {
+	err := stream.Send(&idl.HubReply{Message: &idl.HubReply_OperationId{OperationId: "1234"}})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-h.cancelled:
+	case <-time.After(30 * time.Second):
+	}
+
+	err = stream.Send(&idl.HubReply{Message: &idl.HubReply_LogMsg{LogMsg: &idl.LogMessage{Message: "cleaned up"}}})
+	if err != nil {
+		return err
+	}
+
+	return grpcStatus.Error(codes.Canceled, "context canceled")
+}
+
+func (h *interruptedHub) AddMirrors(in *idl.AddMirrorsRequest, stream idl.Hub_AddMirrorsServer) error {
+	// e.g. waiting for the cluster lock
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func (h *interruptedHub) CancelOperation(ctx context.Context, in *idl.CancelOperationRequest) (*idl.CancelOperationReply, error) {
+	h.cancelled <- in.Id
+	return &idl.CancelOperationReply{}, nil
+}
+
+func TestInterrupt(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	hubServer := &interruptedHub{cancelled: make(chan string, 1)}
+	server := grpc.NewServer()
+	idl.RegisterHubServer(server, hubServer)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	go server.Serve(listener) // nolint
+	defer server.Stop()
+
+	opts := append(cli.InterruptDialOptions(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(listener.Addr().String(), opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+	client := idl.NewHubClient(conn)
+
+	interrupt := func() {
+		time.Sleep(100 * time.Millisecond)
+		err := syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("cancels the calls to the hub", func(t *testing.T) {
+		stop := cli.NotifyInterrupt()
+		defer stop()
+
+		go interrupt()
+		_, err := client.StatusAgents(context.Background(), &idl.StatusAgentsRequest{})
+		if grpcStatus.Code(err) != codes.Canceled {
+			t.Fatalf("got %v, want %v", err, codes.Canceled)
+		}
+	})
+
+	t.Run("cancels the operation and follows it until it stops", func(t *testing.T) {
+		stop := cli.NotifyInterrupt()
+		defer stop()
+
+		stream, err := client.MakeCluster(context.Background(), &idl.MakeClusterRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		reply, err := stream.Recv()
+		if err != nil || reply.GetOperationId() != "1234" {
+			t.Fatalf("got %v, %v, want the operation id", reply, err)
+		}
+
+		go interrupt()
+		reply, err = stream.Recv()
+		if err != nil || reply.GetLogMsg().GetMessage() != "cleaned up" {
+			t.Fatalf("got %v, %v, want the remaining replies of the operation", reply, err)
+		}
+
+		_, err = stream.Recv()
+		if grpcStatus.Code(err) != codes.Canceled {
+			t.Fatalf("got %v, want %v", err, codes.Canceled)
+		}
+	})
+
+	t.Run("cancels the stream when the operation has not started yet", func(t *testing.T) {
+		stop := cli.NotifyInterrupt()
+		defer stop()
+
+		stream, err := client.AddMirrors(context.Background(), &idl.AddMirrorsRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		go interrupt()
+		_, err = stream.Recv()
+		if grpcStatus.Code(err) != codes.Canceled {
+			t.Fatalf("got %v, want %v", err, codes.Canceled)
+		}
+	})
+}
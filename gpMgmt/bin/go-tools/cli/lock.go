@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+var (
+	cliWaitFlag      bool
+	cliBreakLockFlag bool
+)
+
+// addLockFlags adds the options about the cluster lock to the commands changing the cluster
+func addLockFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&cliWaitFlag, "wait", false, `Wait for the cluster lock to be released if another command holds it, instead of failing`)
+	cmd.PersistentFlags().BoolVar(&cliBreakLockFlag, "break-lock", false, `Break the cluster lock held by another command before running, e.g. after the hub was restarted while the command was running`)
+}
+
+func statusLockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "lock",
+		Short:   "Display who holds the cluster lock",
+		Args:    cobra.NoArgs,
+		PreRunE: InitializeCommand,
+		RunE:    RunStatusLock,
+	}
+}
+
+func RunStatusLock(cmd *cobra.Command, args []string) error {
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.GetLockStatus(context.Background(), &idl.GetLockStatusRequest{})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	displayClusterLock(reply.Lock)
+
+	return nil
+}
+
+// withLockOptions tells the hub who is calling and whether to wait for the cluster lock
+func withLockOptions(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		hub.CallerMetadataKey, lockCaller(),
+		hub.LockWaitMetadataKey, strconv.FormatBool(cliWaitFlag))
+}
+
+// lockCaller identifies the user running the command as user@host, which the hub records along with the identity of the certificate
+func lockCaller() string {
+	username := "unknown"
+	if user, err := utils.System.CurrentUser(); err == nil {
+		username = user.Username
+	}
+
+	hostname, err := utils.System.GetHostName()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s@%s", username, hostname)
+}
+
+// breakLockIfRequested breaks the cluster lock when the command is run with --break-lock
+func breakLockIfRequested(client idl.HubClient) error {
+	if !cliBreakLockFlag {
+		return nil
+	}
+
+	reply, err := client.BreakLock(withLockOptions(context.Background()), &idl.BreakLockRequest{})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	if reply.Lock != nil {
+		gplog.Warn("Broke the cluster lock held by %s", describeLock(reply.Lock))
+	}
+
+	return nil
+}
+
+func describeLock(lock *idl.ClusterLock) string {
+	operation := lock.Operation
+	if lock.OperationId != "" {
+		operation = fmt.Sprintf("%s (operation %s)", lock.Operation, lock.OperationId)
+	}
+
+	holder := hub.LockCaller{Holder: lock.Holder, ClaimedBy: lock.ClaimedBy}
+
+	return fmt.Sprintf("%s for %s since %s", holder, operation, time.Unix(lock.StartTime, 0).Format(time.RFC3339))
+}
+
+// displayClusterLock shows who holds the cluster lock as a message or as a lock event
+func displayClusterLock(lock *idl.ClusterLock) {
+	if !IsMachineOutput() {
+		if lock == nil {
+			fmt.Println("The cluster is not locked")
+		} else {
+			fmt.Printf("The cluster is locked by %s\n", describeLock(lock))
+		}
+
+		return
+	}
+
+	if lock == nil {
+		EmitEvent(Event{Type: EventLock, State: "unlocked"})
+		return
+	}
+
+	startTime := time.Unix(lock.StartTime, 0).UTC()
+	EmitEvent(Event{
+		Type:        EventLock,
+		State:       "locked",
+		Holder:      lock.Holder,
+		ClaimedBy:   lock.ClaimedBy,
+		Method:      lock.Operation,
+		OperationID: lock.OperationId,
+		StartTime:   &startTime,
+	})
+}
@@ -0,0 +1,89 @@
+package cli_test
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpdb/gp/cli"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/idl/mock_idl"
+)
+
+func TestRunStatusLock(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	startTime := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	lock := &idl.ClusterLock{Holder: "cdw", ClaimedBy: "gpadmin@cdw", Operation: "MakeCluster", OperationId: "1234", StartTime: startTime.Unix()}
+
+	t.Run("displays who holds the lock", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().GetLockStatus(gomock.Any(), gomock.Any()).Return(&idl.GetLockStatusReply{Lock: lock}, nil)
+			return hubClient, nil
+		}
+
+		oldStdout := os.Stdout
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		os.Stdout = writer
+		defer func() {
+			os.Stdout = oldStdout
+		}()
+
+		err = cli.RunStatusLock(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		writer.Close()
+		out, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := "The cluster is locked by cdw (claiming to be gpadmin@cdw) for MakeCluster (operation 1234) since " + time.Unix(lock.StartTime, 0).Format(time.RFC3339) + "\n"
+		if string(out) != expected {
+			t.Fatalf("got %q, want %q", out, expected)
+		}
+	})
+
+	t.Run("writes the lock as an event when the output is json", func(t *testing.T) {
+		defer resetCLIVars()
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		hubClient := mock_idl.NewMockHubClient(ctrl)
+		gomock.InOrder(
+			hubClient.EXPECT().GetLockStatus(gomock.Any(), gomock.Any()).Return(&idl.GetLockStatusReply{Lock: lock}, nil),
+			hubClient.EXPECT().GetLockStatus(gomock.Any(), gomock.Any()).Return(&idl.GetLockStatusReply{}, nil),
+		)
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			return hubClient, nil
+		}
+
+		for i := 0; i < 2; i++ {
+			err := cli.RunStatusLock(nil, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		}
+
+		expected := []cli.Event{
+			{Type: cli.EventLock, State: "locked", Holder: "cdw", ClaimedBy: "gpadmin@cdw", Method: "MakeCluster", OperationID: "1234", StartTime: &startTime},
+			{Type: cli.EventLock, State: "unlocked"},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+func opsCmd() *cobra.Command {
+	opsCmd := &cobra.Command{
+		Use:   "ops",
+		Short: "Manage the long-running operations of the hub",
+		Long: `Manage the long-running operations of the hub.
+
+Commands like gp init cluster run as operations on the hub, which carry on even
+if the command is interrupted. Their output can be followed again by attaching
+to them, and they can be cancelled while running.`,
+	}
+
+	opsCmd.AddCommand(
+		opsListCmd(),
+		opsAttachCmd(),
+		opsCancelCmd(),
+	)
+
+	return opsCmd
+}
+
+func opsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the running and recently completed operations",
+		Args:    cobra.NoArgs,
+		PreRunE: InitializeCommand,
+		RunE:    RunOpsList,
+	}
+}
+
+func opsAttachCmd() *cobra.Command {
+	opsAttachCmd := &cobra.Command{
+		Use:     "attach <operation-id>",
+		Short:   "Follow the output of an operation, from its beginning until it completes",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: InitializeCommand,
+		RunE:    RunOpsAttach,
+	}
+	addCommandOutputFlags(opsAttachCmd)
+
+	return opsAttachCmd
+}
+
+func opsCancelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "cancel <operation-id>",
+		Short:   "Cancel a running operation",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: InitializeCommand,
+		RunE:    RunOpsCancel,
+	}
+}
+
+func RunOpsList(cmd *cobra.Command, args []string) error {
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.ListOperations(context.Background(), &idl.ListOperationsRequest{})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	displayOperations(reply.Operations)
+
+	return nil
+}
+
+func RunOpsAttach(cmd *cobra.Command, args []string) error {
+	if err := validateCommandOutputFlags(); err != nil {
+		return err
+	}
+
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.AttachOperation(context.Background(), &idl.AttachOperationRequest{Id: args[0]})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	return ParseStreamResponse(stream)
+}
+
+func RunOpsCancel(cmd *cobra.Command, args []string) error {
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CancelOperation(context.Background(), &idl.CancelOperationRequest{Id: args[0]})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	gplog.Info("Requested the cancellation of operation %s, run gp ops attach %s to follow it until it stops", args[0], args[0])
+
+	return nil
+}
+
+// displayOperations shows the operations as a table or as operation events
+func displayOperations(operations []*idl.Operation) {
+	formatTime := func(t int64) string {
+		if t == 0 {
+			return "-"
+		}
+
+		return time.Unix(t, 0).Format(time.RFC3339)
+	}
+
+	if !IsMachineOutput() {
+		w := new(tabwriter.Writer)
+		w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+
+		fmt.Fprintln(w, "ID\tMETHOD\tSTATE\tSTARTED\tENDED\tERROR")
+		for _, op := range operations {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", op.Id, op.Method, op.State, formatTime(op.StartTime), formatTime(op.EndTime), op.Error)
+		}
+		w.Flush()
+
+		return
+	}
+
+	for _, op := range operations {
+		event := Event{
+			Type:        EventOperation,
+			OperationID: op.Id,
+			Method:      op.Method,
+			State:       strings.ToLower(op.State.String()),
+			Error:       op.Error,
+		}
+		startTime := time.Unix(op.StartTime, 0).UTC()
+		event.StartTime = &startTime
+		if op.EndTime != 0 {
+			endTime := time.Unix(op.EndTime, 0).UTC()
+			event.EndTime = &endTime
+		}
+		EmitEvent(event)
+	}
+}
@@ -0,0 +1,237 @@
+package cli_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/cli"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/idl/mock_idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+)
+
+type attachOperationClient struct {
+	grpc.ClientStream
+	*msgStream
+}
+
+func TestRunOpsList(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	t.Run("writes the operations as events when the output is json", func(t *testing.T) {
+		defer resetCLIVars()
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		startTime := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+		endTime := startTime.Add(time.Minute)
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().ListOperations(gomock.Any(), gomock.Any()).Return(&idl.ListOperationsReply{
+				Operations: []*idl.Operation{
+					{Id: "1234", Method: "MakeCluster", State: idl.OperationState_FAILED, StartTime: startTime.Unix(), EndTime: endTime.Unix(), Error: "error"},
+					{Id: "5678", Method: "AddMirrors", State: idl.OperationState_RUNNING, StartTime: startTime.Unix()},
+				},
+			}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.RunOpsList(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := []cli.Event{
+			{Type: cli.EventOperation, OperationID: "1234", Method: "MakeCluster", State: "failed", StartTime: &startTime, EndTime: &endTime, Error: "error"},
+			{Type: cli.EventOperation, OperationID: "5678", Method: "AddMirrors", State: "running", StartTime: &startTime},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("returns the error when not able to list the operations", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().ListOperations(gomock.Any(), gomock.Any()).Return(nil, grpcStatus.Error(codes.PermissionDenied, "not allowed"))
+			return hubClient, nil
+		}
+
+		err := cli.RunOpsList(nil, nil)
+		if grpcStatus.Code(err) != codes.PermissionDenied || err.Error() != "not allowed" {
+			t.Fatalf("got %v, want not allowed", err)
+		}
+	})
+}
+
+func TestRunOpsAttach(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	t.Run("follows the replies of the operation", func(t *testing.T) {
+		defer resetCLIVars()
+		_, _, logfile := testhelper.SetupTestLogger()
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().AttachOperation(gomock.Any(), &idl.AttachOperationRequest{Id: "1234"}).Return(&attachOperationClient{
+				msgStream: &msgStream{msg: []*idl.HubReply{
+					{Message: &idl.HubReply_OperationId{OperationId: "1234"}},
+					{Message: &idl.HubReply_LogMsg{LogMsg: &idl.LogMessage{Message: "creating the cluster", Level: idl.LogLevel_INFO}}},
+				}},
+			}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.RunOpsAttach(nil, []string{"1234"})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		testutils.AssertLogMessage(t, logfile, "Running as operation 1234")
+		testutils.AssertLogMessage(t, logfile, "creating the cluster")
+	})
+
+	t.Run("returns the error of the operation", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().AttachOperation(gomock.Any(), gomock.Any()).Return(&attachOperationClient{
+				msgStream: &msgStream{err: grpcStatus.Error(codes.Canceled, "context canceled")},
+			}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.RunOpsAttach(nil, []string{"1234"})
+		expected := "context canceled"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+	t.Run("prefixes and filters the output of the commands as asked", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.PrefixOutput, cli.OutputHostsFilter, cli.OutputStreamFilter = true, []string{"cdw"}, "stderr"
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().AttachOperation(gomock.Any(), gomock.Any()).Return(&attachOperationClient{
+				msgStream: &msgStream{msg: []*idl.HubReply{
+					{Message: &idl.HubReply_OutputMsg{OutputMsg: &idl.OutputMessage{Host: "cdw", Stream: idl.OutputStream_STDOUT, Sequence: 1, Line: "starting the cluster"}}},
+					{Message: &idl.HubReply_OutputMsg{OutputMsg: &idl.OutputMessage{Host: "cdw", Stream: idl.OutputStream_STDERR, Sequence: 2, Line: "segment sdw1 failed to start"}}},
+					{Message: &idl.HubReply_OutputMsg{OutputMsg: &idl.OutputMessage{Host: "sdw1", Stream: idl.OutputStream_STDERR, Sequence: 3, Line: "not shown"}}},
+				}},
+			}, nil)
+			return hubClient, nil
+		}
+
+		oldStdout := os.Stdout
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		os.Stdout = writer
+		defer func() {
+			os.Stdout = oldStdout
+		}()
+
+		err = cli.RunOpsAttach(nil, []string{"1234"})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		writer.Close()
+		out, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := "[cdw stderr] segment sdw1 failed to start\n"
+		if string(out) != expected {
+			t.Fatalf("got %q, want %q", out, expected)
+		}
+	})
+
+	t.Run("errors out when the output stream is invalid", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.OutputStreamFilter = "stdin"
+
+		err := cli.RunOpsAttach(nil, []string{"1234"})
+		var usageErr *cli.UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("got %#v, want a usage error", err)
+		}
+
+		expected := `invalid --output-stream "stdin", expected "stdout" or "stderr"`
+		if err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
+
+func TestRunOpsCancel(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	t.Run("requests the cancellation of the operation", func(t *testing.T) {
+		defer resetCLIVars()
+		_, _, logfile := testhelper.SetupTestLogger()
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().CancelOperation(gomock.Any(), &idl.CancelOperationRequest{Id: "1234"}).Return(&idl.CancelOperationReply{}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.RunOpsCancel(nil, []string{"1234"})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		testutils.AssertLogMessage(t, logfile, "Requested the cancellation of operation 1234")
+	})
+
+	t.Run("returns the error when the operation has already completed", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().CancelOperation(gomock.Any(), gomock.Any()).Return(nil, grpcStatus.Error(codes.FailedPrecondition, "operation 1234 has already completed"))
+			return hubClient, nil
+		}
+
+		err := cli.RunOpsCancel(nil, []string{"1234"})
+		if cli.ExitCode(err) != cli.ExitPrecondition || err.Error() != "operation 1234 has already completed" {
+			t.Fatalf("got %v, want operation 1234 has already completed", err)
+		}
+	})
+
+	t.Run("returns the error when not able to connect to the hub", func(t *testing.T) {
+		defer resetCLIVars()
+
+		expectedErr := errors.New("error")
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			return nil, expectedErr
+		}
+
+		err := cli.RunOpsCancel(nil, []string{"1234"})
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %#v, want %#v", err, expectedErr)
+		}
+	})
+}
@@ -0,0 +1,455 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+)
+
+// Exit codes of the gp commands, one per category of error
+const (
+	ExitSuccess      = 0
+	ExitFailure      = 1
+	ExitUsage        = 2
+	ExitUnavailable  = 3
+	ExitPermission   = 4
+	ExitPrecondition = 5
+)
+
+// Types of the events written when the output is machine-readable
+const (
+	EventLog         = "log"
+	EventStdout      = "stdout"
+	EventOutput      = "output"
+	EventProgress    = "progress"
+	EventStatus      = "status"
+	EventCertificate = "certificate"
+	EventPlan        = "plan"
+	EventOperation   = "operation"
+	EventLock        = "lock"
+	EventResult      = "result"
+	EventStage       = "stage"
+	EventSegment     = "segment"
+	EventSummary     = "summary"
+	EventTopology    = "topology"
+	EventChange      = "change"
+)
+
+var (
+	OutputFormat string
+	OutputWriter io.Writer = os.Stdout
+
+	outputMutex sync.Mutex
+)
+
+/*
+Event is a single record of the json or yaml output of the commands. Which of
+the fields are set depends on the type of the event. The field names are relied
+upon by automation, so they must not be renamed; new fields may be added.
+*/
+type Event struct {
+	Type string    `json:"type" yaml:"type"`
+	Time time.Time `json:"time" yaml:"time"`
+
+	// log and stdout events
+	Level   string `json:"level,omitempty" yaml:"level,omitempty"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// progress events, along with the stage they are about, and stage events, with the name of the stage as message
+	Stage   string `json:"stage,omitempty" yaml:"stage,omitempty"`
+	Label   string `json:"label,omitempty" yaml:"label,omitempty"`
+	Current int    `json:"current,omitempty" yaml:"current,omitempty"`
+	Total   int    `json:"total,omitempty" yaml:"total,omitempty"`
+
+	// status, certificate and plan events
+	Service           string     `json:"service,omitempty" yaml:"service,omitempty"`
+	Role              string     `json:"role,omitempty" yaml:"role,omitempty"`
+	Host              string     `json:"host,omitempty" yaml:"host,omitempty"`
+	Address           string     `json:"address,omitempty" yaml:"address,omitempty"`
+	Port              int        `json:"port,omitempty" yaml:"port,omitempty"`
+	DataDirectory     string     `json:"dataDirectory,omitempty" yaml:"dataDirectory,omitempty"`
+	Status            string     `json:"status,omitempty" yaml:"status,omitempty"`
+	Pid               uint32     `json:"pid,omitempty" yaml:"pid,omitempty"`
+	Uptime            string     `json:"uptime,omitempty" yaml:"uptime,omitempty"`
+	Path              string     `json:"path,omitempty" yaml:"path,omitempty"`
+	Subject           string     `json:"subject,omitempty" yaml:"subject,omitempty"`
+	CertificateExpiry *time.Time `json:"certificateExpiry,omitempty" yaml:"certificateExpiry,omitempty"`
+
+	// output and segment events, with the host, dataDirectory and message above
+	Dbid     int    `json:"dbid,omitempty" yaml:"dbid,omitempty"`
+	Content  *int   `json:"content,omitempty" yaml:"content,omitempty"`
+	Stream   string `json:"stream,omitempty" yaml:"stream,omitempty"`
+	Sequence uint64 `json:"sequence,omitempty" yaml:"sequence,omitempty"`
+
+	// operation events, which are also sent once with only the operation ID when following an operation,
+	// and lock events, whose state is locked or unlocked
+	OperationID string     `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Holder      string     `json:"holder,omitempty" yaml:"holder,omitempty"`
+	Caller      string     `json:"caller,omitempty" yaml:"caller,omitempty"`
+	ClaimedBy   string     `json:"claimedBy,omitempty" yaml:"claimedBy,omitempty"`
+	Method      string     `json:"method,omitempty" yaml:"method,omitempty"`
+	State       string     `json:"state,omitempty" yaml:"state,omitempty"`
+	StartTime   *time.Time `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	EndTime     *time.Time `json:"endTime,omitempty" yaml:"endTime,omitempty"`
+
+	// result events, along with the error of operation events
+	ExitCode *int       `json:"exitCode,omitempty" yaml:"exitCode,omitempty"`
+	Category string     `json:"category,omitempty" yaml:"category,omitempty"`
+	Error    string     `json:"error,omitempty" yaml:"error,omitempty"`
+	Failures []*Failure `json:"failures,omitempty" yaml:"failures,omitempty"`
+
+	// summary events, along with the status, error and failures of result events
+	DurationMillis int64            `json:"durationMillis,omitempty" yaml:"durationMillis,omitempty"`
+	Stages         []*StageResult   `json:"stages,omitempty" yaml:"stages,omitempty"`
+	Segments       []*SegmentResult `json:"segments,omitempty" yaml:"segments,omitempty"`
+
+	// topology events, along with the operationId and method of operation events,
+	// and change events, along with the dbid and content of segment events
+	Version      int    `json:"version,omitempty" yaml:"version,omitempty"`
+	Phase        string `json:"phase,omitempty" yaml:"phase,omitempty"`
+	SegmentCount int    `json:"segmentCount,omitempty" yaml:"segmentCount,omitempty"`
+	Change       string `json:"change,omitempty" yaml:"change,omitempty"`
+	From         string `json:"from,omitempty" yaml:"from,omitempty"`
+	To           string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// StageResult is the final state of a stage of an operation, listed in the summary events
+type StageResult struct {
+	ID    string `json:"id" yaml:"id"`
+	Name  string `json:"name" yaml:"name"`
+	State string `json:"state" yaml:"state"`
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// SegmentResult is the final state of a segment changed by an operation, listed in the summary events
+type SegmentResult struct {
+	Dbid          int    `json:"dbid" yaml:"dbid"`
+	Content       int    `json:"content" yaml:"content"`
+	Host          string `json:"host" yaml:"host"`
+	DataDirectory string `json:"dataDirectory" yaml:"dataDirectory"`
+	State         string `json:"state" yaml:"state"`
+	Error         string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Failure is a failed request of the hub to the agent of a host, listed in the result events
+type Failure struct {
+	Host    string `json:"host" yaml:"host"`
+	Segment string `json:"segment,omitempty" yaml:"segment,omitempty"`
+	RPC     string `json:"rpc,omitempty" yaml:"rpc,omitempty"`
+	Code    string `json:"code" yaml:"code"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// UsageError is returned when the command is invoked with invalid flags or arguments
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func ValidateOutputFormat() error {
+	switch OutputFormat {
+	case OutputText, OutputJSON, OutputYAML:
+		return nil
+	}
+
+	return &UsageError{fmt.Errorf("invalid output format %q, expected %q, %q or %q", OutputFormat, OutputText, OutputJSON, OutputYAML)}
+}
+
+// IsMachineOutput tells whether the output is written as json or yaml events instead of text
+func IsMachineOutput() bool {
+	return OutputFormat == OutputJSON || OutputFormat == OutputYAML
+}
+
+/*
+EmitEvent writes the event to the output, as a single line of json or as a yaml
+document. It is safe to be called concurrently.
+*/
+func EmitEvent(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+
+	switch OutputFormat {
+	case OutputJSON:
+		encoder := json.NewEncoder(OutputWriter)
+		encoder.SetEscapeHTML(false)
+		_ = encoder.Encode(event)
+	case OutputYAML:
+		out, err := yaml.Marshal(event)
+		if err == nil {
+			fmt.Fprintf(OutputWriter, "---\n%s", out)
+		}
+	}
+}
+
+// printMessage prints a message meant for the user, which is not logged
+func printMessage(message string) {
+	if IsMachineOutput() {
+		EmitEvent(Event{Type: EventStdout, Message: strings.TrimRight(message, "\n")})
+		return
+	}
+
+	fmt.Print(message)
+}
+
+// displayServiceStatus shows the status of the hub or the agents as a table or as status events
+func displayServiceStatus(service string, statuses []*idl.ServiceStatus, skipHeader bool) {
+	if !IsMachineOutput() {
+		Platform.DisplayServiceStatus(os.Stdout, service, statuses, skipHeader)
+		return
+	}
+
+	for _, s := range statuses {
+		event := Event{
+			Type:    EventStatus,
+			Service: strings.ToLower(service),
+			Host:    s.Host,
+			Status:  s.Status,
+			Pid:     s.Pid,
+			Uptime:  s.Uptime,
+		}
+		if s.CertificateExpiry != 0 {
+			expiry := time.Unix(s.CertificateExpiry, 0).UTC()
+			event.CertificateExpiry = &expiry
+		}
+		EmitEvent(event)
+	}
+}
+
+// displayCertificateExpiry shows the expiry of the certificates as a table or as certificate events
+func displayCertificateExpiry(certs []*utils.CertificateInfo, now time.Time) {
+	if !IsMachineOutput() {
+		utils.DisplayCertificateExpiry(os.Stdout, certs, now)
+		return
+	}
+
+	for _, c := range certs {
+		expiry := c.NotAfter.UTC()
+		EmitEvent(Event{
+			Type:              EventCertificate,
+			Path:              c.Path,
+			Subject:           c.Subject,
+			Status:            c.ExpiryStatus(now),
+			CertificateExpiry: &expiry,
+		})
+	}
+}
+
+// emitClusterPlan writes the segments the cluster is going to be created with as plan events
+func emitClusterPlan(request *idl.MakeClusterRequest) {
+	planEvent := func(role string, seg *idl.Segment) Event {
+		return Event{
+			Type:          EventPlan,
+			Role:          role,
+			Host:          seg.HostName,
+			Address:       seg.HostAddress,
+			Port:          int(seg.Port),
+			DataDirectory: seg.DataDirectory,
+		}
+	}
+
+	EmitEvent(planEvent("coordinator", request.GpArray.Coordinator))
+	for _, pair := range request.GpArray.SegmentArray {
+		EmitEvent(planEvent("primary", pair.Primary))
+	}
+	for _, pair := range request.GpArray.SegmentArray {
+		if pair.Mirror != nil {
+			EmitEvent(planEvent("mirror", pair.Mirror))
+		}
+	}
+}
+
+/*
+ErrorCategory classifies the error by what the user is expected to do about it,
+and returns the category along with the exit code of the command.
+*/
+func ErrorCategory(err error) (string, int) {
+	if err == nil {
+		return "", ExitSuccess
+	}
+
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		return "usage", ExitUsage
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "unavailable", ExitUnavailable
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return "unavailable", ExitUnavailable
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return "permission", ExitPermission
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.AlreadyExists, codes.NotFound:
+		return "precondition", ExitPrecondition
+	}
+
+	return "failure", ExitFailure
+}
+
+// ExitCode returns the exit code of the command for the given error
+func ExitCode(err error) int {
+	_, code := ErrorCategory(err)
+
+	return code
+}
+
+// ReportResult writes the result event of the command when the output is machine-readable
+func ReportResult(err error) {
+	if !IsMachineOutput() {
+		return
+	}
+
+	category, code := ErrorCategory(err)
+	event := Event{Type: EventResult, Status: "success", ExitCode: &code, Category: category}
+	if err != nil {
+		event.Status = "failure"
+		event.Error = err.Error()
+		event.Failures = failuresOf(err)
+	}
+
+	EmitEvent(event)
+}
+
+// failuresOf returns the failed requests to the agents which caused the error, if any
+func failuresOf(err error) []*Failure {
+	return failuresFromIdl(utils.HostErrorsOf(err))
+}
+
+func failuresFromIdl(hostErrs []*idl.HostError) []*Failure {
+	var failures []*Failure
+	for _, hostErr := range hostErrs {
+		failures = append(failures, &Failure{
+			Host:    hostErr.Host,
+			Segment: hostErr.Segment,
+			RPC:     hostErr.Rpc,
+			Code:    codes.Code(hostErr.Code).String(),
+			Message: hostErr.Message,
+		})
+	}
+
+	return failures
+}
+
+/*
+DisplayFailures shows the failed requests to the agents which caused the error
+as a table grouped by host, so that all of them can be fixed at once.
+*/
+func DisplayFailures(outfile io.Writer, err error) {
+	failures := failuresOf(err)
+	if len(failures) == 0 {
+		return
+	}
+
+	// keep the order of the hosts while grouping their failures
+	var hosts []string
+	byHost := make(map[string][]*Failure)
+	for _, f := range failures {
+		if _, ok := byHost[f.Host]; !ok {
+			hosts = append(hosts, f.Host)
+		}
+		byHost[f.Host] = append(byHost[f.Host], f)
+	}
+
+	fmt.Fprintf(outfile, "\n%d failure(s) on %d host(s):\n", len(failures), len(hosts))
+	w := new(tabwriter.Writer)
+	w.Init(outfile, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "HOST\tSEGMENT\tRPC\tCODE\tMESSAGE")
+	for _, host := range hosts {
+		for i, f := range byHost[host] {
+			if i > 0 {
+				host = ""
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", host, f.Segment, f.RPC, f.Code, strings.ReplaceAll(f.Message, "\n", " "))
+		}
+	}
+	w.Flush()
+}
+
+var logLinePattern = regexp.MustCompile(`(?s)-\[(\w+)\]:-(.*)$`)
+
+// eventLogWriter turns the lines written to the shell by gplog into log events
+type eventLogWriter struct{}
+
+func (w eventLogWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		level, message := "info", line
+		if match := logLinePattern.FindStringSubmatch(line); match != nil {
+			level, message = strings.ToLower(match[1]), match[2]
+		}
+		if level == "critical" {
+			level = "fatal"
+		}
+
+		EmitEvent(Event{Type: EventLog, Level: level, Message: message})
+	}
+
+	return len(p), nil
+}
+
+/*
+initializeEventLogging sets up gplog like gplog.InitializeLogging does, except
+that the messages meant for the shell are written as log events.
+*/
+func initializeEventLogging(program string, logdir string) error {
+	if gplog.GetLogger() != nil {
+		return nil
+	}
+
+	if logdir == "" {
+		logdir = greenplum.GetDefaultHubLogDir()
+	}
+	err := os.MkdirAll(logdir, 0755)
+	if err != nil {
+		return fmt.Errorf("could not create log directory %s: %w", logdir, err)
+	}
+
+	logfile := gplog.GenerateLogFileName(program, logdir)
+	fileHandle, err := utils.System.OpenFile(logfile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open log file %s: %w", logfile, err)
+	}
+
+	gplog.SetLogger(gplog.NewLogger(eventLogWriter{}, eventLogWriter{}, fileHandle, logfile, gplog.LOGINFO, program))
+	gplog.SetExitFunc(func() {
+		os.Exit(ExitFailure)
+	})
+
+	return nil
+}
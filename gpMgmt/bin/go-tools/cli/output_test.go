@@ -0,0 +1,294 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+
+	"github.com/greenplum-db/gpdb/gp/cli"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+// setOutputFormat makes the events of the commands available in the returned buffer
+func setOutputFormat(t *testing.T, format string) *bytes.Buffer {
+	t.Helper()
+
+	buffer := new(bytes.Buffer)
+	oldFormat, oldWriter := cli.OutputFormat, cli.OutputWriter
+	cli.OutputFormat, cli.OutputWriter = format, buffer
+	t.Cleanup(func() {
+		cli.OutputFormat, cli.OutputWriter = oldFormat, oldWriter
+	})
+
+	return buffer
+}
+
+func readEvents(t *testing.T, buffer *bytes.Buffer) []cli.Event {
+	t.Helper()
+
+	var events []cli.Event
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var event cli.Event
+		err := json.Unmarshal([]byte(line), &event)
+		if err != nil {
+			t.Fatalf("unexpected error: %v, line %q", err, line)
+		}
+		if event.Time.IsZero() {
+			t.Fatalf("expected the event %q to have a time", line)
+		}
+
+		event.Time = time.Time{}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{cli.OutputText, cli.OutputJSON, cli.OutputYAML} {
+		t.Run(fmt.Sprintf("accepts %s", format), func(t *testing.T) {
+			setOutputFormat(t, format)
+
+			err := cli.ValidateOutputFormat()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("errors out on an unknown format", func(t *testing.T) {
+		setOutputFormat(t, "xml")
+
+		err := cli.ValidateOutputFormat()
+		var usageErr *cli.UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("got %T, want %T", err, usageErr)
+		}
+
+		expected := `invalid output format "xml", expected "text", "json" or "yaml"`
+		if err.Error() != expected {
+			t.Fatalf("got %v, want %v", err, expected)
+		}
+	})
+}
+
+func TestEmitEvent(t *testing.T) {
+	eventTime := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("writes an event per line as json", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		cli.EmitEvent(cli.Event{Type: cli.EventLog, Time: eventTime, Level: "info", Message: "<message>"})
+		cli.EmitEvent(cli.Event{Type: cli.EventProgress, Time: eventTime, Label: "label", Current: 1, Total: 2})
+
+		expected := `{"type":"log","time":"2023-05-01T10:00:00Z","level":"info","message":"<message>"}
+{"type":"progress","time":"2023-05-01T10:00:00Z","label":"label","current":1,"total":2}
+`
+		if buffer.String() != expected {
+			t.Fatalf("got %q, want %q", buffer.String(), expected)
+		}
+	})
+
+	t.Run("writes an event per document as yaml", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputYAML)
+
+		cli.EmitEvent(cli.Event{Type: cli.EventStdout, Time: eventTime, Message: "first"})
+		cli.EmitEvent(cli.Event{Type: cli.EventStdout, Time: eventTime, Message: "second"})
+
+		decoder := yaml.NewDecoder(buffer)
+		for _, expected := range []string{"first", "second"} {
+			var event cli.Event
+			err := decoder.Decode(&event)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if event.Type != cli.EventStdout || event.Message != expected || !event.Time.Equal(eventTime) {
+				t.Fatalf("got %+v, want a stdout event with message %s", event, expected)
+			}
+		}
+	})
+
+	t.Run("writes nothing when the output is text", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputText)
+
+		cli.EmitEvent(cli.Event{Type: cli.EventStdout, Message: "message"})
+		if buffer.Len() != 0 {
+			t.Fatalf("got %q, want no output", buffer.String())
+		}
+	})
+}
+
+func TestErrorCategory(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		category string
+		exitCode int
+	}{
+		{"no error", nil, "", cli.ExitSuccess},
+		{"usage error", &cli.UsageError{errors.New("unknown flag")}, "usage", cli.ExitUsage},
+		{"hub not reachable", utils.NewGrpcError(codes.Unavailable, errors.New("could not connect to hub")), "unavailable", cli.ExitUnavailable},
+		{"timed out", fmt.Errorf("host: sdw1, %w", context.DeadlineExceeded), "unavailable", cli.ExitUnavailable},
+		{"not authorized", status.Error(codes.PermissionDenied, "not allowed"), "permission", cli.ExitPermission},
+		{"invalid request", fmt.Errorf("wrapped: %w", utils.FormatGrpcError(status.Error(codes.InvalidArgument, "invalid"))), "precondition", cli.ExitPrecondition},
+		{"other error", errors.New("error"), "failure", cli.ExitFailure},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			category, exitCode := cli.ErrorCategory(tc.err)
+			if category != tc.category || exitCode != tc.exitCode {
+				t.Fatalf("got %s %d, want %s %d", category, exitCode, tc.category, tc.exitCode)
+			}
+
+			if cli.ExitCode(tc.err) != tc.exitCode {
+				t.Fatalf("got %d, want %d", cli.ExitCode(tc.err), tc.exitCode)
+			}
+		})
+	}
+}
+
+var hostErrors = utils.HostErrors{
+	{Host: "sdw1", Segment: "/data/primary/gpseg0", RPC: "MakeSegment", Err: status.Error(codes.Internal, "initdb failed")},
+	{Host: "sdw1", Segment: "/data/primary/gpseg1", RPC: "MakeSegment", Err: status.Error(codes.Internal, "disk full")},
+	{Host: "sdw2", RPC: "ValidateHostEnv", Err: errors.New("port 7000 in use")},
+}
+
+func TestReportResult(t *testing.T) {
+	t.Run("reports the success of the command", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		cli.ReportResult(nil)
+
+		exitCode := 0
+		expected := []cli.Event{{Type: cli.EventResult, Status: "success", ExitCode: &exitCode}}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("reports the error and its category", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		cli.ReportResult(status.Error(codes.Unauthenticated, "bad certificate"))
+
+		exitCode := cli.ExitPermission
+		expected := []cli.Event{{Type: cli.EventResult, Status: "failure", ExitCode: &exitCode, Category: "permission", Error: "rpc error: code = Unauthenticated desc = bad certificate"}}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("reports the failures of all the hosts", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		cli.ReportResult(hostErrors)
+
+		exitCode := cli.ExitFailure
+		expected := []cli.Event{{
+			Type:     cli.EventResult,
+			Status:   "failure",
+			ExitCode: &exitCode,
+			Category: "failure",
+			Error:    hostErrors.Error(),
+			Failures: []*cli.Failure{
+				{Host: "sdw1", Segment: "/data/primary/gpseg0", RPC: "MakeSegment", Code: "Internal", Message: "initdb failed"},
+				{Host: "sdw1", Segment: "/data/primary/gpseg1", RPC: "MakeSegment", Code: "Internal", Message: "disk full"},
+				{Host: "sdw2", RPC: "ValidateHostEnv", Code: "Unknown", Message: "port 7000 in use"},
+			},
+		}}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("reports nothing when the output is text", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputText)
+
+		cli.ReportResult(errors.New("error"))
+		if buffer.Len() != 0 {
+			t.Fatalf("got %q, want no output", buffer.String())
+		}
+	})
+}
+
+func TestInitializeLoggerWithMachineOutput(t *testing.T) {
+	t.Run("writes the log messages as events and to the log file", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		homeDir := t.TempDir()
+		utils.System.CurrentUser = func() (*user.User, error) {
+			return &user.User{HomeDir: homeDir}, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		gplog.SetLogger(nil)
+		defer testhelper.SetupTestLogger()
+
+		err := cli.InitializeLogger(&cobra.Command{Use: "gp"}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		gplog.Info("info message")
+		gplog.Warn("warning message")
+		gplog.Error("error message")
+
+		expected := []cli.Event{
+			{Type: cli.EventLog, Level: "info", Message: "info message"},
+			{Type: cli.EventLog, Level: "warning", Message: "warning message"},
+			{Type: cli.EventLog, Level: "error", Message: "error message"},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+
+		logfile := gplog.GenerateLogFileName("gp", filepath.Join(homeDir, "gpAdminLogs"))
+		if gplog.GetLogFilePath() != logfile {
+			t.Fatalf("got %s, want %s", gplog.GetLogFilePath(), logfile)
+		}
+	})
+}
+
+func TestDisplayFailures(t *testing.T) {
+	t.Run("displays the failures grouped by host", func(t *testing.T) {
+		var buffer bytes.Buffer
+		cli.DisplayFailures(&buffer, fmt.Errorf("wrapped: %w", hostErrors))
+
+		expected := `
+3 failure(s) on 2 host(s):
+HOST  SEGMENT               RPC              CODE      MESSAGE
+sdw1  /data/primary/gpseg0  MakeSegment      Internal  initdb failed
+      /data/primary/gpseg1  MakeSegment      Internal  disk full
+sdw2                        ValidateHostEnv  Unknown   port 7000 in use
+`
+		if buffer.String() != expected {
+			t.Fatalf("got %q, want %q", buffer.String(), expected)
+		}
+	})
+
+	t.Run("displays nothing for the other errors", func(t *testing.T) {
+		var buffer bytes.Buffer
+		cli.DisplayFailures(&buffer, errors.New("error"))
+
+		if buffer.Len() != 0 {
+			t.Fatalf("got %q, want no output", buffer.String())
+		}
+	})
+}
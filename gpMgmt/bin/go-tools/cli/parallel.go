@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+)
+
+var cliParallelFlag int
+
+// addParallelFlag adds the option limiting how many requests the hub runs on the agents at the same time
+func addParallelFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().IntVar(&cliParallelFlag, "parallel", 0, `Maximum number of segments the hub works on at the same time, like creating them, at most the "parallel" value of the configuration (default that value)`)
+}
+
+// withParallelOption asks the hub to use the limit given with --parallel, if any
+func withParallelOption(ctx context.Context) context.Context {
+	if cliParallelFlag <= 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, hub.ParallelMetadataKey, strconv.Itoa(cliParallelFlag))
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpdb/gp/hub"
 	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +28,7 @@ func statusCmd() *cobra.Command {
 	statusCmd.AddCommand(statusHubCmd())
 	statusCmd.AddCommand(statusAgentsCmd())
 	statusCmd.AddCommand(statusServicesCmd())
+	statusCmd.AddCommand(statusLockCmd())
 
 	return statusCmd
 }
@@ -87,7 +91,19 @@ func ShowHubStatusFunc(conf *hub.Config, skipHeader bool) (bool, error) {
 	}
 	status := Platform.ParseServiceStatusMessage(message)
 	status.Host, _ = os.Hostname()
-	Platform.DisplayServiceStatus(os.Stdout, "Hub", []*idl.ServiceStatus{&status}, skipHeader)
+	var expiry time.Time
+	if reporter, ok := conf.Credentials.(utils.CertificateExpiryReporter); ok {
+		expiry, err = reporter.GetCertificateExpiry()
+		if err != nil {
+			gplog.Warn("could not get the expiry of the hub certificate: %v", err)
+		} else {
+			status.CertificateExpiry = expiry.Unix()
+		}
+	}
+	displayServiceStatus("Hub", []*idl.ServiceStatus{&status}, skipHeader)
+	if status.CertificateExpiry != 0 {
+		warnCertificateExpiry("Hub", status.Host, expiry)
+	}
 	if status.Status == "Unknown" {
 		return false, nil
 	}
@@ -106,11 +122,29 @@ func ShowAgentsStatusFunc(conf *hub.Config, skipHeader bool) error {
 	if err != nil {
 		return err
 	}
-	Platform.DisplayServiceStatus(os.Stdout, "Agent", reply.Statuses, skipHeader)
+	displayServiceStatus("Agent", reply.Statuses, skipHeader)
+	for _, status := range reply.Statuses {
+		if status.CertificateExpiry != 0 {
+			warnCertificateExpiry("Agent", status.Host, time.Unix(status.CertificateExpiry, 0))
+		}
+	}
+
+	if len(reply.Unreachable) > 0 {
+		return fmt.Errorf("could not reach the agents on %d host(s): %w", len(reply.Unreachable), utils.HostErrorsFromIdl(reply.Unreachable))
+	}
 
 	return nil
 }
 
+func warnCertificateExpiry(service string, host string, expiry time.Time) {
+	switch utils.GetExpiryStatus(expiry, time.Now()) {
+	case "expired":
+		gplog.Warn("%s certificate on host %s expired on %s, rotate it using gp certs rotate", service, host, expiry.Format(time.RFC3339))
+	case "expiring soon":
+		gplog.Warn("%s certificate on host %s expires on %s, rotate it using gp certs rotate", service, host, expiry.Format(time.RFC3339))
+	}
+}
+
 func RunServiceStatus(cmd *cobra.Command, args []string) error {
 	err := PrintServicesStatus()
 	if err != nil {
@@ -127,7 +161,7 @@ func PrintServicesStatusFunc() error {
 		return err
 	}
 	if !hubRunning {
-		fmt.Println("Hub service not running, not able to fetch agent status.")
+		printMessage("Hub service not running, not able to fetch agent status.\n")
 		return nil
 	}
 	err = ShowAgentsStatus(Conf, true)
@@ -2,16 +2,21 @@ package cli_test
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	"github.com/greenplum-db/gpdb/gp/cli"
 	"github.com/greenplum-db/gpdb/gp/hub"
 	"github.com/greenplum-db/gpdb/gp/idl"
 	"github.com/greenplum-db/gpdb/gp/idl/mock_idl"
 	"github.com/greenplum-db/gpdb/gp/testutils"
 	"github.com/greenplum-db/gpdb/gp/utils"
+	"google.golang.org/grpc/codes"
 )
 
 func TestPrintServicesStatus(t *testing.T) {
@@ -117,6 +122,61 @@ func TestShowAgentStatus(t *testing.T) {
 			t.Fatalf("unexpected error: %#v", err)
 		}
 	})
+	t.Run("warns when the certificate of an agent is about to expire", func(t *testing.T) {
+		defer resetCLIVars()
+		_, _, logfile := testhelper.SetupTestLogger()
+
+		expiry := time.Now().AddDate(0, 0, 2)
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().StatusAgents(gomock.Any(), gomock.Any()).Return(&idl.StatusAgentsReply{
+				Statuses: []*idl.ServiceStatus{
+					{Host: "sdw1", Status: "running", CertificateExpiry: time.Now().AddDate(1, 0, 0).Unix()},
+					{Host: "sdw2", Status: "running", CertificateExpiry: expiry.Unix()},
+					{Host: "sdw3", Status: "running"},
+				},
+			}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.ShowAgentsStatus(cli.Conf, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		testutils.AssertLogMessage(t, logfile, fmt.Sprintf(`\[WARNING\]:-Agent certificate on host sdw2 expires on %s`, expiry.Format(time.RFC3339)))
+		for _, host := range []string{"sdw1", "sdw3"} {
+			if strings.Contains(string(logfile.Contents()), "host "+host) {
+				t.Fatalf("unexpected warning for host %s in log %s", host, logfile.Contents())
+			}
+		}
+	})
+	t.Run("displays the reachable agents and returns the unreachable ones", func(t *testing.T) {
+		defer resetCLIVars()
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().StatusAgents(gomock.Any(), gomock.Any()).Return(&idl.StatusAgentsReply{
+				Statuses: []*idl.ServiceStatus{
+					{Host: "sdw1", Status: "running"},
+					{Host: "sdw2", Status: "unreachable"},
+				},
+				Unreachable: []*idl.HostError{
+					{Host: "sdw2", Code: int32(codes.Unavailable), Message: "could not connect to agent"},
+				},
+			}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.ShowAgentsStatus(cli.Conf, true)
+		expected := "could not reach the agents on 1 host(s): host: sdw2, could not connect to agent"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		if cli.ExitCode(err) != cli.ExitUnavailable {
+			t.Fatalf("got %d, want %d", cli.ExitCode(err), cli.ExitUnavailable)
+		}
+	})
 	t.Run("returns error when there error connecting Hub", func(t *testing.T) {
 		defer resetCLIVars()
 		expectedStr := "TEST Error connecting Hub"
@@ -129,6 +189,35 @@ func TestShowAgentStatus(t *testing.T) {
 			t.Fatalf("got %v, want %v", err, expectedStr)
 		}
 	})
+	t.Run("writes the status of the agents as events when the output is json", func(t *testing.T) {
+		defer resetCLIVars()
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().StatusAgents(gomock.Any(), gomock.Any()).Return(&idl.StatusAgentsReply{
+				Statuses: []*idl.ServiceStatus{
+					{Host: "sdw1", Status: "running", Pid: 1234, Uptime: "5H", CertificateExpiry: expiry.Unix()},
+					{Host: "sdw2", Status: "not running"},
+				},
+			}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.ShowAgentsStatus(cli.Conf, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := []cli.Event{
+			{Type: cli.EventStatus, Service: "agent", Host: "sdw1", Status: "running", Pid: 1234, Uptime: "5H", CertificateExpiry: &expiry},
+			{Type: cli.EventStatus, Service: "agent", Host: "sdw2", Status: "not running"},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
 }
 
 func TestShowHubStatus(t *testing.T) {
@@ -146,6 +235,26 @@ func TestShowHubStatus(t *testing.T) {
 			t.Fatalf("unexpected error: %#v", err)
 		}
 	})
+	t.Run("warns when the hub certificate has expired", func(t *testing.T) {
+		_, _, logfile := testhelper.SetupTestLogger()
+
+		mockPlatform := &testutils.MockPlatform{Err: nil}
+		mockPlatform.RetStatus = &idl.ServiceStatus{Status: "Running", Uptime: "10ms", Pid: uint32(1234)}
+		cli.Platform = mockPlatform
+		defer func() { cli.Platform = utils.GetPlatform() }()
+
+		credentials := cli.Conf.Credentials
+		defer func() { cli.Conf.Credentials = credentials }()
+		expiry := time.Now().Add(-time.Hour)
+		cli.Conf.Credentials = &testutils.MockExpiringCredentials{Expiry: expiry}
+
+		_, err := cli.ShowHubStatus(cli.Conf, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		testutils.AssertLogMessage(t, logfile, fmt.Sprintf(`\[WARNING\]:-Hub certificate on host .* expired on %s`, expiry.Format(time.RFC3339)))
+	})
 	t.Run("returns error when error getting service status", func(t *testing.T) {
 		expectedStr := "TEST Error getting service status"
 		mockPlatform := &testutils.MockPlatform{Err: errors.New(expectedStr), ServiceStatusMessage: ""}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
@@ -17,6 +18,10 @@ type StreamReceiver interface {
 }
 
 func ParseStreamResponseFn(stream StreamReceiver) error {
+	if IsMachineOutput() {
+		return emitStreamResponse(stream)
+	}
+
 	progressBarMap := make(map[string]*mpb.Bar)
 	progressInstance := utils.NewProgressInstance(os.Stdout)
 
@@ -36,35 +41,60 @@ func ParseStreamResponseFn(stream StreamReceiver) error {
 		msg := resp.Message
 		switch msg.(type) {
 		case *idl.HubReply_LogMsg:
-			logMsg := resp.GetLogMsg()
-			switch logMsg.Level {
-			case idl.LogLevel_DEBUG:
-				gplog.Verbose(logMsg.Message)
-			case idl.LogLevel_WARNING:
-				gplog.Warn(logMsg.Message)
-			case idl.LogLevel_ERROR:
-				gplog.Error(logMsg.Message)
-			case idl.LogLevel_FATAL:
-				gplog.Fatal(nil, logMsg.Message)
-			default:
-				gplog.Info(logMsg.Message)
-			}
+			logHubMessage(resp.GetLogMsg())
 
 		case *idl.HubReply_StdoutMsg:
-			fmt.Print(resp.GetStdoutMsg())
+			printMessage(resp.GetStdoutMsg())
+
+		case *idl.HubReply_OutputMsg:
+			printOutputMessage(resp.GetOutputMsg())
+
+		case *idl.HubReply_OperationId:
+			gplog.Info("Running as operation %s, which can be followed with gp ops attach or stopped with gp ops cancel", resp.GetOperationId())
+
+		case *idl.HubReply_StageMsg:
+			stageMsg := resp.GetStageMsg()
+			logStageMessage(stageMsg)
+
+			// a stage started again, e.g. when retried, gets a new progress bar
+			if bar, ok := progressBarMap[stageMsg.Id]; ok && stageMsg.State != idl.StageState_STAGE_FINISHED {
+				bar.Abort(stageMsg.State == idl.StageState_STAGE_STARTED)
+				delete(progressBarMap, stageMsg.Id)
+			}
+
+		case *idl.HubReply_SegmentMsg:
+			logSegmentMessage(resp.GetSegmentMsg())
+
+		case *idl.HubReply_ResultMsg:
+			gplog.Info(summaryMessage(resp.GetResultMsg()))
 
 		case *idl.HubReply_ProgressMsg:
 			progressMsg := resp.GetProgressMsg()
-			if _, ok := progressBarMap[progressMsg.Label]; !ok {
-				progressBarMap[progressMsg.Label] = utils.NewProgressBar(progressInstance, progressMsg.Label, int(progressMsg.Total))
-			} else {
-				bar := progressBarMap[progressMsg.Label]
-				bar.Increment()
-
-				if bar.Current() == int64(progressMsg.Total) {
-					bar.Wait()
-					time.Sleep(500 * time.Millisecond)
-				}
+			key := progressKey(progressMsg)
+			bar, ok := progressBarMap[key]
+			if !ok {
+				bar = utils.NewProgressBar(progressInstance, progressMsg.Label, int(progressMsg.Total))
+				progressBarMap[key] = bar
+			}
+
+			bar.SetCurrent(int64(progressMsg.Current))
+			if progressMsg.Total > 0 && progressMsg.Current == progressMsg.Total {
+				bar.Wait()
+				time.Sleep(500 * time.Millisecond)
+			}
+
+		case *idl.HubReply_TransferMsg:
+			transferMsg := resp.GetTransferMsg()
+			bar, ok := progressBarMap[transferMsg.Label]
+			if !ok {
+				bar = utils.NewTransferBar(progressInstance, transferMsg.Label)
+				progressBarMap[transferMsg.Label] = bar
+			}
+
+			bar.SetTotal(transferMsg.Total, false)
+			bar.SetCurrent(transferMsg.Current)
+			if transferMsg.Completed {
+				bar.SetTotal(-1, true)
 			}
 		}
 	}
@@ -73,3 +103,192 @@ func ParseStreamResponseFn(stream StreamReceiver) error {
 
 	return nil
 }
+
+// emitStreamResponse writes the messages of the stream as events
+func emitStreamResponse(stream StreamReceiver) error {
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return utils.FormatGrpcError(err)
+		}
+
+		switch resp.Message.(type) {
+		case *idl.HubReply_LogMsg:
+			logHubMessage(resp.GetLogMsg())
+
+		case *idl.HubReply_StdoutMsg:
+			printMessage(resp.GetStdoutMsg())
+
+		case *idl.HubReply_OutputMsg:
+			printOutputMessage(resp.GetOutputMsg())
+
+		case *idl.HubReply_OperationId:
+			EmitEvent(Event{Type: EventOperation, OperationID: resp.GetOperationId()})
+
+		case *idl.HubReply_StageMsg:
+			stageMsg := resp.GetStageMsg()
+			EmitEvent(Event{
+				Type:    EventStage,
+				Time:    time.UnixMilli(stageMsg.Timestamp).UTC(),
+				Stage:   stageMsg.Id,
+				Message: stageMsg.Name,
+				State:   stageStateName(stageMsg.State),
+				Error:   stageMsg.Error,
+			})
+
+		case *idl.HubReply_SegmentMsg:
+			segmentMsg := resp.GetSegmentMsg()
+			content := int(segmentMsg.Contentid)
+			EmitEvent(Event{
+				Type:          EventSegment,
+				Time:          time.UnixMilli(segmentMsg.Timestamp).UTC(),
+				Host:          segmentMsg.Host,
+				DataDirectory: segmentMsg.DataDirectory,
+				Dbid:          int(segmentMsg.Dbid),
+				Content:       &content,
+				State:         segmentStateName(segmentMsg.State),
+				Error:         segmentMsg.Error,
+			})
+
+		case *idl.HubReply_ResultMsg:
+			EmitEvent(summaryEvent(resp.GetResultMsg()))
+
+		case *idl.HubReply_ProgressMsg:
+			progressMsg := resp.GetProgressMsg()
+			EmitEvent(Event{
+				Type:    EventProgress,
+				Stage:   progressMsg.Stage,
+				Label:   progressMsg.Label,
+				Current: int(progressMsg.Current),
+				Total:   int(progressMsg.Total),
+			})
+
+		case *idl.HubReply_TransferMsg:
+			// the progress of a transfer is in bytes, and its total is only an estimate until it completes
+			transferMsg := resp.GetTransferMsg()
+			total := transferMsg.Total
+			if transferMsg.Completed {
+				total = transferMsg.Current
+			}
+
+			EmitEvent(Event{Type: EventProgress, Label: transferMsg.Label, Current: int(transferMsg.Current), Total: int(total)})
+		}
+	}
+}
+
+func logHubMessage(logMsg *idl.LogMessage) {
+	switch logMsg.Level {
+	case idl.LogLevel_DEBUG:
+		gplog.Verbose(logMsg.Message)
+	case idl.LogLevel_WARNING:
+		gplog.Warn(logMsg.Message)
+	case idl.LogLevel_ERROR:
+		gplog.Error(logMsg.Message)
+	case idl.LogLevel_FATAL:
+		gplog.Fatal(nil, logMsg.Message)
+	default:
+		gplog.Info(logMsg.Message)
+	}
+}
+
+// progressKey returns the key of the progress bar of the message, which is the stage it is about if any
+func progressKey(progressMsg *idl.ProgressMessage) string {
+	if progressMsg.Stage != "" {
+		return progressMsg.Stage
+	}
+
+	return progressMsg.Label
+}
+
+func stageStateName(state idl.StageState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "STAGE_"))
+}
+
+func segmentStateName(state idl.SegmentState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "SEGMENT_"))
+}
+
+// logStageMessage logs the start and the end of a stage, which are only shown with --verbose
+func logStageMessage(stageMsg *idl.StageEvent) {
+	switch stageMsg.State {
+	case idl.StageState_STAGE_STARTED:
+		gplog.Verbose("Stage %s started: %s", stageMsg.Id, stageMsg.Name)
+	case idl.StageState_STAGE_FINISHED:
+		gplog.Verbose("Stage %s finished", stageMsg.Id)
+	case idl.StageState_STAGE_FAILED:
+		gplog.Verbose("Stage %s failed: %s", stageMsg.Id, stageMsg.Error)
+	}
+}
+
+// logSegmentMessage logs the state changes of a segment, which are only shown with --verbose
+func logSegmentMessage(segmentMsg *idl.SegmentEvent) {
+	message := fmt.Sprintf("Segment %s:%s (dbid %d) is %s", segmentMsg.Host, segmentMsg.DataDirectory, segmentMsg.Dbid, segmentStateName(segmentMsg.State))
+	if segmentMsg.Error != "" {
+		message = fmt.Sprintf("%s: %s", message, segmentMsg.Error)
+	}
+
+	gplog.Verbose(message)
+}
+
+// summaryMessage describes the result of an operation in a line, counting its stages and segments by final state
+func summaryMessage(summary *idl.ResultSummary) string {
+	stages := map[idl.StageState]int{}
+	for _, stage := range summary.Stages {
+		stages[stage.State]++
+	}
+
+	segments := map[idl.SegmentState]int{}
+	for _, segment := range summary.Segments {
+		segments[segment.State]++
+	}
+
+	result := "succeeded"
+	if !summary.Success {
+		result = "failed"
+	}
+
+	message := fmt.Sprintf("Operation %s in %s: %d stages finished, %d failed", result, time.Duration(summary.DurationMillis)*time.Millisecond, stages[idl.StageState_STAGE_FINISHED], stages[idl.StageState_STAGE_FAILED])
+	if len(summary.Segments) > 0 {
+		message = fmt.Sprintf("%s; %d segments started, %d created, %d failed", message, segments[idl.SegmentState_SEGMENT_STARTED], segments[idl.SegmentState_SEGMENT_CREATED], segments[idl.SegmentState_SEGMENT_FAILED])
+	}
+
+	return message
+}
+
+// summaryEvent returns the summary event of the result of an operation
+func summaryEvent(summary *idl.ResultSummary) Event {
+	event := Event{
+		Type:           EventSummary,
+		Status:         "success",
+		Error:          summary.Error,
+		DurationMillis: summary.DurationMillis,
+		Failures:       failuresFromIdl(summary.Failures),
+	}
+	if !summary.Success {
+		event.Status = "failure"
+	}
+
+	for _, stage := range summary.Stages {
+		event.Stages = append(event.Stages, &StageResult{
+			ID:    stage.Id,
+			Name:  stage.Name,
+			State: stageStateName(stage.State),
+			Error: stage.Error,
+		})
+	}
+
+	for _, segment := range summary.Segments {
+		event.Segments = append(event.Segments, &SegmentResult{
+			Dbid:          int(segment.Dbid),
+			Content:       int(segment.Contentid),
+			Host:          segment.Host,
+			DataDirectory: segment.DataDirectory,
+			State:         segmentStateName(segment.State),
+			Error:         segment.Error,
+		})
+	}
+
+	return event
+}
@@ -5,7 +5,12 @@ import (
 	"errors"
 	"io"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
 
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	"github.com/greenplum-db/gpdb/gp/cli"
@@ -70,6 +75,7 @@ func TestParseStreamResponse(t *testing.T) {
 			{
 				Message: &idl.HubReply_ProgressMsg{
 					ProgressMsg: &idl.ProgressMessage{
+						Stage: "stage",
 						Label: "progress message",
 						Total: 1,
 					},
@@ -78,8 +84,10 @@ func TestParseStreamResponse(t *testing.T) {
 			{
 				Message: &idl.HubReply_ProgressMsg{
 					ProgressMsg: &idl.ProgressMessage{
-						Label: "progress message",
-						Total: 1,
+						Stage:   "stage",
+						Label:   "progress message",
+						Current: 1,
+						Total:   1,
 					},
 				},
 			},
@@ -129,6 +137,7 @@ func TestParseStreamResponse(t *testing.T) {
 			{
 				Message: &idl.HubReply_ProgressMsg{
 					ProgressMsg: &idl.ProgressMessage{
+						Stage: "stage",
 						Label: "progress message",
 						Total: 5,
 					},
@@ -137,8 +146,10 @@ func TestParseStreamResponse(t *testing.T) {
 			{
 				Message: &idl.HubReply_ProgressMsg{
 					ProgressMsg: &idl.ProgressMessage{
-						Label: "progress message",
-						Total: 5,
+						Stage:   "stage",
+						Label:   "progress message",
+						Current: 1,
+						Total:   5,
 					},
 				},
 			},
@@ -176,4 +187,273 @@ func TestParseStreamResponse(t *testing.T) {
 			}
 		}
 	})
+	t.Run("writes the stream responses as events when the output is json", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		msg := []*idl.HubReply{
+			{Message: &idl.HubReply_LogMsg{LogMsg: &idl.LogMessage{Message: "info log message", Level: idl.LogLevel_INFO}}},
+			{Message: &idl.HubReply_StdoutMsg{StdoutMsg: "stdout message\n"}},
+			{Message: &idl.HubReply_ProgressMsg{ProgressMsg: &idl.ProgressMessage{Stage: "stage", Label: "progress message", Current: 0, Total: 2}}},
+			{Message: &idl.HubReply_ProgressMsg{ProgressMsg: &idl.ProgressMessage{Stage: "stage", Label: "progress message", Current: 1, Total: 2}}},
+			{Message: &idl.HubReply_ProgressMsg{ProgressMsg: &idl.ProgressMessage{Stage: "stage", Label: "progress message", Current: 2, Total: 2}}},
+		}
+
+		err := cli.ParseStreamResponse(&msgStream{msg: msg})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		// the log messages are written by the test logger, not as events
+		testutils.AssertLogMessage(t, logfile, "info log message")
+		expected := []cli.Event{
+			{Type: cli.EventStdout, Message: "stdout message"},
+			{Type: cli.EventProgress, Stage: "stage", Label: "progress message", Current: 0, Total: 2},
+			{Type: cli.EventProgress, Stage: "stage", Label: "progress message", Current: 1, Total: 2},
+			{Type: cli.EventProgress, Stage: "stage", Label: "progress message", Current: 2, Total: 2},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("starts the progress bar of a stage over when the stage is started again", func(t *testing.T) {
+		stage := func(state idl.StageState) *idl.HubReply {
+			return &idl.HubReply{Message: &idl.HubReply_StageMsg{StageMsg: &idl.StageEvent{Id: "create-mirrors", Name: "Creating the mirror segments", State: state}}}
+		}
+		progress := func(current int32) *idl.HubReply {
+			return &idl.HubReply{Message: &idl.HubReply_ProgressMsg{ProgressMsg: &idl.ProgressMessage{Stage: "create-mirrors", Label: "progress message", Current: current, Total: 2}}}
+		}
+		msg := []*idl.HubReply{
+			stage(idl.StageState_STAGE_STARTED), progress(0), progress(1), stage(idl.StageState_STAGE_FAILED),
+			stage(idl.StageState_STAGE_STARTED), progress(0), progress(1), progress(2), stage(idl.StageState_STAGE_FINISHED),
+		}
+
+		oldStdout := os.Stdout
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		os.Stdout = writer
+		defer func() {
+			os.Stdout = oldStdout
+		}()
+
+		err = cli.ParseStreamResponse(&msgStream{msg: msg})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		writer.Close()
+		out, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		// the bar of the failed attempt is aborted, the one of the second attempt completes
+		for _, expected := range []string{"error", "2/2", "done"} {
+			if !bytes.Contains(out, []byte(expected)) {
+				t.Fatalf("got %q, want %q", out, expected)
+			}
+		}
+		if bytes.Contains(out, []byte("3/2")) || bytes.Contains(out, []byte("4/2")) {
+			t.Fatalf("got %q, want the progress of the second attempt to start over", out)
+		}
+	})
+
+	t.Run("writes the stage, segment and summary events when the output is json", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		timestamp := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+		stage := &idl.StageEvent{Id: "create-mirrors", Name: "Creating the mirror segments", State: idl.StageState_STAGE_FAILED, Error: "error", Timestamp: timestamp.UnixMilli()}
+		segment := &idl.SegmentEvent{Dbid: 3, Contentid: 0, Host: "sdw2", DataDirectory: "/data/mirror/gpseg0", State: idl.SegmentState_SEGMENT_FAILED, Error: "error", Timestamp: timestamp.UnixMilli()}
+		msg := []*idl.HubReply{
+			{Message: &idl.HubReply_StageMsg{StageMsg: stage}},
+			{Message: &idl.HubReply_SegmentMsg{SegmentMsg: segment}},
+			{Message: &idl.HubReply_ResultMsg{ResultMsg: &idl.ResultSummary{
+				Error:          "error",
+				DurationMillis: 1500,
+				Stages:         []*idl.StageEvent{stage},
+				Segments:       []*idl.SegmentEvent{segment},
+				Failures:       []*idl.HostError{{Host: "sdw2", Segment: "/data/mirror/gpseg0", Rpc: "PgBasebackup", Code: int32(codes.Unknown), Message: "error"}},
+			}}},
+		}
+
+		err := cli.ParseStreamResponse(&msgStream{msg: msg})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		content := 0
+		expected := []cli.Event{
+			{Type: cli.EventStage, Stage: "create-mirrors", Message: "Creating the mirror segments", State: "failed", Error: "error"},
+			{Type: cli.EventSegment, Host: "sdw2", DataDirectory: "/data/mirror/gpseg0", Dbid: 3, Content: &content, State: "failed", Error: "error"},
+			{
+				Type:           cli.EventSummary,
+				Status:         "failure",
+				Error:          "error",
+				DurationMillis: 1500,
+				Stages:         []*cli.StageResult{{ID: "create-mirrors", Name: "Creating the mirror segments", State: "failed", Error: "error"}},
+				Segments:       []*cli.SegmentResult{{Dbid: 3, Content: 0, Host: "sdw2", DataDirectory: "/data/mirror/gpseg0", State: "failed", Error: "error"}},
+				Failures:       []*cli.Failure{{Host: "sdw2", Segment: "/data/mirror/gpseg0", RPC: "PgBasebackup", Code: "Unknown", Message: "error"}},
+			},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("logs the summary of the operation", func(t *testing.T) {
+		msg := []*idl.HubReply{
+			{Message: &idl.HubReply_ResultMsg{ResultMsg: &idl.ResultSummary{
+				Success:        true,
+				DurationMillis: 1500,
+				Stages: []*idl.StageEvent{
+					{Id: "create-primaries", State: idl.StageState_STAGE_FINISHED},
+					{Id: "start-cluster", State: idl.StageState_STAGE_FINISHED},
+				},
+				Segments: []*idl.SegmentEvent{
+					{Dbid: 2, Host: "sdw1", DataDirectory: "/data/primary/gpseg0", State: idl.SegmentState_SEGMENT_STARTED},
+				},
+			}}},
+		}
+
+		err := cli.ParseStreamResponse(&msgStream{msg: msg})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		testutils.AssertLogMessage(t, logfile, `Operation succeeded in 1.5s: 2 stages finished, 0 failed; 1 segments started, 0 created, 0 failed`)
+	})
+
+	t.Run("displays the progress of the transfers", func(t *testing.T) {
+		msg := []*idl.HubReply{
+			{Message: &idl.HubReply_TransferMsg{TransferMsg: &idl.TransferMessage{Label: "sdw1:/data/mirror/gpseg0", Current: 1024, Total: 4096}}},
+			{Message: &idl.HubReply_TransferMsg{TransferMsg: &idl.TransferMessage{Label: "sdw1:/data/mirror/gpseg0", Current: 5120, Total: 4096, Completed: true}}},
+		}
+
+		oldStdout := os.Stdout
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		os.Stdout = writer
+		defer func() {
+			os.Stdout = oldStdout
+		}()
+
+		err = cli.ParseStreamResponse(&msgStream{msg: msg})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		writer.Close()
+		out, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expectedProgressContents := []string{"sdw1:/data/mirror/gpseg0", "5.0 KiB / 5.0 KiB", "done"}
+		for _, expected := range expectedProgressContents {
+			if !bytes.Contains(out, []byte(expected)) {
+				t.Fatalf("got %q, want %q", out, expected)
+			}
+		}
+	})
+
+	t.Run("writes the progress of the transfers as events when the output is json", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		msg := []*idl.HubReply{
+			{Message: &idl.HubReply_TransferMsg{TransferMsg: &idl.TransferMessage{Label: "sdw1:/data/mirror/gpseg0", Current: 1024, Total: 4096}}},
+			{Message: &idl.HubReply_TransferMsg{TransferMsg: &idl.TransferMessage{Label: "sdw1:/data/mirror/gpseg0", Current: 5120, Total: 4096, Completed: true}}},
+		}
+
+		err := cli.ParseStreamResponse(&msgStream{msg: msg})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := []cli.Event{
+			{Type: cli.EventProgress, Label: "sdw1:/data/mirror/gpseg0", Current: 1024, Total: 4096},
+			{Type: cli.EventProgress, Label: "sdw1:/data/mirror/gpseg0", Current: 5120, Total: 5120},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("prints the output of the commands, the one of the segments only when verbose", func(t *testing.T) {
+		msg := []*idl.HubReply{
+			{Message: &idl.HubReply_OutputMsg{OutputMsg: &idl.OutputMessage{Host: "cdw", Stream: idl.OutputStream_STDOUT, Sequence: 1, Line: "starting the cluster"}}},
+			{Message: &idl.HubReply_OutputMsg{OutputMsg: &idl.OutputMessage{Host: "sdw1", Segment: "/data/primary/gpseg0", Dbid: 2, Stream: idl.OutputStream_STDOUT, Sequence: 2, Line: "initdb output"}}},
+		}
+
+		for _, verbose := range []bool{false, true} {
+			cli.Verbose = verbose
+
+			oldStdout := os.Stdout
+			reader, writer, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("unexpected error: %#v", err)
+			}
+			os.Stdout = writer
+
+			err = cli.ParseStreamResponse(&msgStream{msg: msg})
+			writer.Close()
+			os.Stdout = oldStdout
+			cli.Verbose = false
+			if err != nil {
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			out, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			expected := "starting the cluster\n"
+			if verbose {
+				expected += "initdb output\n"
+			}
+			if string(out) != expected {
+				t.Fatalf("got %q, want %q", out, expected)
+			}
+		}
+	})
+
+	t.Run("writes the output of the commands as events when the output is json", func(t *testing.T) {
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		timestamp := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+		msg := []*idl.HubReply{
+			{Message: &idl.HubReply_OutputMsg{OutputMsg: &idl.OutputMessage{Host: "sdw1", Segment: "/data/primary/gpseg0", Dbid: 2, Stream: idl.OutputStream_STDERR, Sequence: 7, Timestamp: timestamp.UnixMilli(), Line: "initdb output"}}},
+		}
+
+		err := cli.ParseStreamResponse(&msgStream{msg: msg})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		// the time of the event is the one of the output, which readEvents clears
+		if !strings.Contains(buffer.String(), `"time":"2023-05-01T10:00:00Z"`) {
+			t.Fatalf("got %s, want the time of the output", buffer)
+		}
+
+		expected := []cli.Event{
+			{Type: cli.EventOutput, Host: "sdw1", DataDirectory: "/data/primary/gpseg0", Dbid: 2, Stream: "stderr", Sequence: 7, Message: "initdb output"},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("returns the errors of the stream when the output is json", func(t *testing.T) {
+		setOutputFormat(t, cli.OutputJSON)
+
+		expectedErr := errors.New("error")
+		err := cli.ParseStreamResponse(&msgStream{err: expectedErr})
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %#v, want %#v", err, expectedErr)
+		}
+	})
 }
@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+var (
+	// TopologyRestoreForce confirms that gp topology restore may rewrite the segment configuration
+	TopologyRestoreForce bool
+
+	TopologyExportVersion int
+	TopologyExportFile    string
+	TopologyExportFormat  string
+
+	TopologyValidateMirroringType  string
+	TopologyValidateBlockSize      int
+	TopologyValidateFailureDomains map[string]string
+)
+
+func topologyCmd() *cobra.Command {
+	topologyCmd := &cobra.Command{
+		Use:   "topology",
+		Short: "Review the changes made to the segment configuration of the cluster",
+		Long: `Review the changes made to the segment configuration of the cluster.
+
+The hub records gp_segment_configuration before and after every operation which
+changes it, like creating the cluster or adding mirrors, as a new version. The
+versions can be compared to find which segments moved, changed role or changed
+port, and an earlier version can be applied again.
+
+A version, or the current segment configuration, can be exported as a topology
+file in YAML or JSON, to be reviewed and given as input to the operations which
+change the segments.`,
+	}
+
+	topologyCmd.AddCommand(
+		topologyHistoryCmd(),
+		topologyDiffCmd(),
+		topologyRestoreCmd(),
+		topologyExportCmd(),
+		topologyValidateCmd(),
+	)
+
+	return topologyCmd
+}
+
+func topologyHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "history",
+		Short:   "List the recorded versions of the segment configuration",
+		Args:    cobra.NoArgs,
+		PreRunE: InitializeCommand,
+		RunE:    RunTopologyHistory,
+	}
+}
+
+func topologyDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "diff <from-version> <to-version>",
+		Short:   "Show how the segments changed between two versions of the segment configuration",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: InitializeCommand,
+		RunE:    RunTopologyDiff,
+	}
+}
+
+func topologyRestoreCmd() *cobra.Command {
+	topologyRestoreCmd := &cobra.Command{
+		Use:   "restore <version>",
+		Short: "Expert only: write a recorded version of the segment configuration back to the catalog",
+		Long: `Expert only: write a recorded version of the segment configuration back to the catalog.
+
+The content of gp_segment_configuration is replaced with the segments of the
+given version, without checking them against the segments actually running.
+Only use it to undo a change which left the catalog wrong, then restart the
+cluster. It has to be run with --force.`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: InitializeCommand,
+		RunE:    RunTopologyRestore,
+	}
+	topologyRestoreCmd.Flags().BoolVar(&TopologyRestoreForce, "force", false, `Confirm that the segment configuration is to be rewritten`)
+	addLockFlags(topologyRestoreCmd)
+	addCommandOutputFlags(topologyRestoreCmd)
+
+	return topologyRestoreCmd
+}
+
+func topologyExportCmd() *cobra.Command {
+	topologyExportCmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Write the segment configuration of the cluster as a topology file",
+		Args:    cobra.NoArgs,
+		PreRunE: InitializeCommand,
+		RunE:    RunTopologyExport,
+	}
+	topologyExportCmd.Flags().IntVar(&TopologyExportVersion, "version", 0, `Version to export as listed by gp topology history, the current segment configuration by default`)
+	topologyExportCmd.Flags().StringVar(&TopologyExportFile, "file", "", `File to write the topology to instead of stdout`)
+	topologyExportCmd.Flags().StringVar(&TopologyExportFormat, "format", "", `Format of the topology file, yaml or json. Defaults to the extension of --file, else yaml`)
+
+	return topologyExportCmd
+}
+
+func topologyValidateCmd() *cobra.Command {
+	topologyValidateCmd := &cobra.Command{
+		Use:     "validate <file>",
+		Short:   "Check that a topology file describes a valid segment configuration",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: InitializeCommand,
+		RunE:    RunTopologyValidate,
+	}
+	topologyValidateCmd.Flags().StringVar(&TopologyValidateMirroringType, "mirroring-type", "", `Also check that the mirrors are placed with group, spread, block or rack-aware mirroring`)
+	topologyValidateCmd.Flags().IntVar(&TopologyValidateBlockSize, "block-size", 0, `Number of hosts of a block with block mirroring`)
+	topologyValidateCmd.Flags().StringToStringVar(&TopologyValidateFailureDomains, "failure-domain", nil, `Failure domain of a host as hostname=domain, checking that no mirror shares the domain of its primary. Can be repeated`)
+
+	return topologyValidateCmd
+}
+
+func RunTopologyHistory(cmd *cobra.Command, args []string) error {
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.ListTopology(context.Background(), &idl.ListTopologyRequest{})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	displayTopologySnapshots(reply.Snapshots)
+
+	return nil
+}
+
+func RunTopologyDiff(cmd *cobra.Command, args []string) error {
+	from, err := parseTopologyVersion(args[0])
+	if err != nil {
+		return err
+	}
+	to, err := parseTopologyVersion(args[1])
+	if err != nil {
+		return err
+	}
+
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.DiffTopology(context.Background(), &idl.DiffTopologyRequest{From: from, To: to})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	displayTopologyChanges(from, to, reply.Changes)
+
+	return nil
+}
+
+func RunTopologyRestore(cmd *cobra.Command, args []string) error {
+	version, err := parseTopologyVersion(args[0])
+	if err != nil {
+		return err
+	}
+	if !TopologyRestoreForce {
+		return &UsageError{fmt.Errorf("restoring the topology rewrites gp_segment_configuration, run it again with --force to confirm")}
+	}
+	if err := validateCommandOutputFlags(); err != nil {
+		return err
+	}
+
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return err
+	}
+
+	err = breakLockIfRequested(client)
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.RestoreTopology(withLockOptions(context.Background()), &idl.RestoreTopologyRequest{Version: version})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	err = ParseStreamResponse(stream)
+	if err != nil {
+		return err
+	}
+
+	gplog.Info("Restored the topology of version %d, restart the cluster for the change to take effect", version)
+
+	return nil
+}
+
+func RunTopologyExport(cmd *cobra.Command, args []string) error {
+	if TopologyExportVersion < 0 {
+		return &UsageError{fmt.Errorf("invalid --version %d, expected a version listed by gp topology history", TopologyExportVersion)}
+	}
+	format := TopologyExportFormat
+	if format == "" {
+		format = greenplum.TopologyFormatFromPath(TopologyExportFile)
+	}
+	if format != greenplum.TopologyFormatJSON && format != greenplum.TopologyFormatYAML {
+		return &UsageError{fmt.Errorf("invalid --format %q, expected %s or %s", format, greenplum.TopologyFormatYAML, greenplum.TopologyFormatJSON)}
+	}
+
+	client, err := ConnectToHub(Conf)
+	if err != nil {
+		return err
+	}
+
+	reply, err := client.ExportTopology(context.Background(), &idl.ExportTopologyRequest{Version: int32(TopologyExportVersion)})
+	if err != nil {
+		return utils.FormatGrpcError(err)
+	}
+
+	file, err := greenplum.ParseTopologyFile(reply.Topology)
+	if err != nil {
+		return err
+	}
+	contents, err := file.Marshal(format)
+	if err != nil {
+		return err
+	}
+
+	if TopologyExportFile == "" {
+		_, err = os.Stdout.Write(contents)
+		return err
+	}
+
+	err = utils.System.WriteFile(TopologyExportFile, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the topology file %s: %w", TopologyExportFile, err)
+	}
+	gplog.Info("Exported the topology to %s", TopologyExportFile)
+
+	return nil
+}
+
+func RunTopologyValidate(cmd *cobra.Command, args []string) error {
+	gparray, err := greenplum.ReadTopologyFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	warnings, err := gparray.Validate(greenplum.ValidateOptions{
+		MirroringType:  strings.ToLower(TopologyValidateMirroringType),
+		BlockSize:      TopologyValidateBlockSize,
+		FailureDomains: TopologyValidateFailureDomains,
+	})
+	for _, warning := range warnings {
+		gplog.Warn(warning)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid topology file %s:\n%w", args[0], err)
+	}
+
+	gplog.Info("The topology file %s is valid: %d primaries, %d mirrors and %d hosts", args[0],
+		len(gparray.GetPrimarySegments()), len(gparray.GetMirrorSegments()), len(gparray.GetHostnames()))
+
+	return nil
+}
+
+func parseTopologyVersion(value string) (int32, error) {
+	version, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || version < 1 {
+		return 0, &UsageError{fmt.Errorf("invalid topology version %q, expected a version listed by gp topology history", value)}
+	}
+
+	return int32(version), nil
+}
+
+// displayTopologySnapshots shows the recorded versions of the topology as a table or as topology events
+func displayTopologySnapshots(snapshots []*idl.TopologySnapshot) {
+	if !IsMachineOutput() {
+		w := new(tabwriter.Writer)
+		w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+
+		fmt.Fprintln(w, "VERSION\tTIME\tOPERATION\tMETHOD\tPHASE\tSEGMENTS")
+		for _, snapshot := range snapshots {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\n", snapshot.Version, time.Unix(snapshot.Timestamp, 0).Format(time.RFC3339),
+				snapshot.OperationId, snapshot.Method, snapshot.Phase, snapshot.Segments)
+		}
+		w.Flush()
+
+		return
+	}
+
+	for _, snapshot := range snapshots {
+		EmitEvent(Event{
+			Type:         EventTopology,
+			Time:         time.Unix(snapshot.Timestamp, 0).UTC(),
+			Version:      int(snapshot.Version),
+			OperationID:  snapshot.OperationId,
+			Method:       snapshot.Method,
+			Phase:        snapshot.Phase,
+			SegmentCount: int(snapshot.Segments),
+		})
+	}
+}
+
+// displayTopologyChanges shows the changes of the segments between two versions as a table or as change events
+func displayTopologyChanges(from int32, to int32, changes []*idl.TopologyChange) {
+	if !IsMachineOutput() {
+		if len(changes) == 0 {
+			gplog.Info("No segments changed between the versions %d and %d", from, to)
+			return
+		}
+
+		w := new(tabwriter.Writer)
+		w.Init(os.Stdout, 0, 8, 2, '\t', 0)
+
+		fmt.Fprintln(w, "DBID\tCONTENT\tCHANGE\tFROM\tTO")
+		for _, change := range changes {
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\n", change.Dbid, change.Contentid, change.Kind, change.From, change.To)
+		}
+		w.Flush()
+
+		return
+	}
+
+	for _, change := range changes {
+		content := int(change.Contentid)
+		EmitEvent(Event{
+			Type:    EventChange,
+			Dbid:    int(change.Dbid),
+			Content: &content,
+			Change:  change.Kind,
+			From:    change.From,
+			To:      change.To,
+		})
+	}
+}
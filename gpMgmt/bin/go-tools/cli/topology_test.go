@@ -0,0 +1,310 @@
+package cli_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/cli"
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/idl/mock_idl"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+func TestRunTopology(t *testing.T) {
+	setupTest(t)
+	defer teardownTest()
+
+	t.Run("lists the versions of the topology", func(t *testing.T) {
+		defer resetCLIVars()
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().ListTopology(gomock.Any(), gomock.Any()).Return(&idl.ListTopologyReply{
+				Snapshots: []*idl.TopologySnapshot{
+					{Version: 1, OperationId: "1234", Method: "AddMirrors", Phase: "before", Segments: 3},
+					{Version: 2, OperationId: "1234", Method: "AddMirrors", Phase: "after", Segments: 5},
+				},
+			}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.RunTopologyHistory(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := []cli.Event{
+			{Type: cli.EventTopology, Version: 1, OperationID: "1234", Method: "AddMirrors", Phase: "before", SegmentCount: 3},
+			{Type: cli.EventTopology, Version: 2, OperationID: "1234", Method: "AddMirrors", Phase: "after", SegmentCount: 5},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("shows the changes between two versions", func(t *testing.T) {
+		defer resetCLIVars()
+		buffer := setOutputFormat(t, cli.OutputJSON)
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().DiffTopology(gomock.Any(), &idl.DiffTopologyRequest{From: 1, To: 2}).Return(&idl.DiffTopologyReply{
+				Changes: []*idl.TopologyChange{
+					{Dbid: 4, Contentid: 0, Kind: "added", To: "sdw2:/data/mirror/gpseg0"},
+					{Dbid: 2, Contentid: 0, Kind: "port", From: "7002", To: "7004"},
+				},
+			}, nil)
+			return hubClient, nil
+		}
+
+		err := cli.RunTopologyDiff(nil, []string{"1", "2"})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		content := 0
+		expected := []cli.Event{
+			{Type: cli.EventChange, Dbid: 4, Content: &content, Change: "added", To: "sdw2:/data/mirror/gpseg0"},
+			{Type: cli.EventChange, Dbid: 2, Content: &content, Change: "port", From: "7002", To: "7004"},
+		}
+		if events := readEvents(t, buffer); !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("errors out when the version is invalid", func(t *testing.T) {
+		defer resetCLIVars()
+
+		err := cli.RunTopologyDiff(nil, []string{"1", "latest"})
+		var usageErr *cli.UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("got %#v, want a usage error", err)
+		}
+
+		expected := `invalid topology version "latest", expected a version listed by gp topology history`
+		if err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("returns the error when the version does not exist", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().DiffTopology(gomock.Any(), gomock.Any()).Return(nil, grpcStatus.Error(codes.NotFound, "topology version 3 not found"))
+			return hubClient, nil
+		}
+
+		err := cli.RunTopologyDiff(nil, []string{"1", "3"})
+		if grpcStatus.Code(err) != codes.NotFound || err.Error() != "topology version 3 not found" {
+			t.Fatalf("got %v, want topology version 3 not found", err)
+		}
+	})
+
+	t.Run("restores the topology holding the cluster lock", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.TopologyRestoreForce = true
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().RestoreTopology(gomock.Any(), &idl.RestoreTopologyRequest{Version: 2}).DoAndReturn(
+				func(ctx context.Context, request *idl.RestoreTopologyRequest, opts ...grpc.CallOption) (idl.Hub_RestoreTopologyClient, error) {
+					md, _ := metadata.FromOutgoingContext(ctx)
+					if len(md.Get(hub.CallerMetadataKey)) != 1 {
+						t.Fatalf("got metadata %v, want the caller of the command", md)
+					}
+
+					return nil, nil
+				})
+			return hubClient, nil
+		}
+
+		var parsed bool
+		cli.ParseStreamResponse = func(stream cli.StreamReceiver) error {
+			parsed = true
+			return nil
+		}
+
+		err := cli.RunTopologyRestore(nil, []string{"2"})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		if !parsed {
+			t.Fatalf("expected the output of the operation to be shown")
+		}
+	})
+
+	t.Run("errors out when restoring the topology without --force", func(t *testing.T) {
+		defer resetCLIVars()
+
+		err := cli.RunTopologyRestore(nil, []string{"2"})
+		var usageErr *cli.UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("got %#v, want a usage error", err)
+		}
+
+		expected := "restoring the topology rewrites gp_segment_configuration, run it again with --force to confirm"
+		if err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("exports the current topology to a file in the format of its extension", func(t *testing.T) {
+		defer resetCLIVars()
+
+		gparray := &greenplum.GpArray{
+			Coordinator: &greenplum.Segment{Dbid: 1, Content: -1, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7000, Hostname: "cdw", Address: "cdw", DataDir: "/data/coordinator/gpseg-1"},
+			SegmentPairs: []greenplum.SegmentPair{
+				{Primary: &greenplum.Segment{Dbid: 2, Content: 0, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7002, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/primary/gpseg0"}},
+			},
+		}
+		contents, err := greenplum.NewTopologyFile(gparray).Marshal(greenplum.TopologyFormatJSON)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cli.TopologyExportFile = filepath.Join(t.TempDir(), "topology.yaml")
+		cli.ConnectToHub = func(conf *hub.Config) (idl.HubClient, error) {
+			hubClient := mock_idl.NewMockHubClient(ctrl)
+			hubClient.EXPECT().ExportTopology(gomock.Any(), &idl.ExportTopologyRequest{}).Return(&idl.ExportTopologyReply{Topology: contents}, nil)
+			return hubClient, nil
+		}
+
+		err = cli.RunTopologyExport(nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		written, err := os.ReadFile(cli.TopologyExportFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected, err := greenplum.NewTopologyFile(gparray).Marshal(greenplum.TopologyFormatYAML)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(written) != string(expected) {
+			t.Fatalf("got %s, want %s", written, expected)
+		}
+
+		gparrayRead, err := greenplum.ReadTopologyFile(cli.TopologyExportFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(gparrayRead, gparray) {
+			t.Fatalf("got %+v, want %+v", gparrayRead, gparray)
+		}
+	})
+
+	t.Run("errors out when the export format is invalid", func(t *testing.T) {
+		defer resetCLIVars()
+
+		cli.TopologyExportFormat = "toml"
+		err := cli.RunTopologyExport(nil, nil)
+		var usageErr *cli.UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("got %#v, want a usage error", err)
+		}
+
+		expected := `invalid --format "toml", expected yaml or json`
+		if err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("validates a topology file", func(t *testing.T) {
+		defer resetCLIVars()
+
+		path := filepath.Join(t.TempDir(), "topology.yaml")
+		err := os.WriteFile(path, []byte(`
+version: 1
+coordinator: {dbid: 1, content: -1, role: primary, hostname: cdw, port: 7000, data-directory: /data/coordinator/gpseg-1}
+segments:
+  - primary: {dbid: 2, content: 0, role: primary, hostname: sdw1, port: 7002, data-directory: /data/primary/gpseg0}
+    mirror: {dbid: 3, content: 0, role: mirror, hostname: sdw2, port: 7003, data-directory: /data/mirror/gpseg0}
+`), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = cli.RunTopologyValidate(nil, []string{path})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("checks the placement of the mirrors of a topology file", func(t *testing.T) {
+		defer resetCLIVars()
+
+		path := filepath.Join(t.TempDir(), "topology.yaml")
+		err := os.WriteFile(path, []byte(`
+version: 1
+coordinator: {dbid: 1, content: -1, role: primary, hostname: cdw, port: 7000, data-directory: /data/coordinator/gpseg-1}
+segments:
+  - primary: {dbid: 2, content: 0, role: primary, hostname: sdw1, port: 7002, data-directory: /data/primary/gpseg0}
+    mirror: {dbid: 4, content: 0, role: mirror, hostname: sdw2, port: 7003, data-directory: /data/mirror/gpseg0}
+  - primary: {dbid: 3, content: 1, role: primary, hostname: sdw1, port: 7004, data-directory: /data/primary/gpseg1}
+    mirror: {dbid: 5, content: 1, role: mirror, hostname: sdw2, port: 7005, data-directory: /data/mirror/gpseg1}
+`), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cli.TopologyValidateMirroringType = "group"
+		err = cli.RunTopologyValidate(nil, []string{path})
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		cli.TopologyValidateMirroringType = "spread"
+		err = cli.RunTopologyValidate(nil, []string{path})
+		expected := "invalid topology file " + path + ":\nspread mirroring places the mirrors of the primaries of host sdw1 on different hosts, found 2 of them on host sdw2"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		cli.TopologyValidateMirroringType = "rack-aware"
+		cli.TopologyValidateFailureDomains = map[string]string{"sdw1": "rack1", "sdw2": "rack1"}
+		err = cli.RunTopologyValidate(nil, []string{path})
+		expected = "invalid topology file " + path + ":\nthe mirror of content 0 is in the same failure domain rack1 as its primary\nthe mirror of content 1 is in the same failure domain rack1 as its primary"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("returns the problems of an invalid topology file", func(t *testing.T) {
+		defer resetCLIVars()
+
+		path := filepath.Join(t.TempDir(), "topology.yaml")
+		err := os.WriteFile(path, []byte(`
+version: 1
+coordinator: {dbid: 1, content: -1, role: primary, hostname: cdw, port: 7000, data-directory: /data/coordinator/gpseg-1}
+segments:
+  - primary: {dbid: 2, content: 0, role: primary, hostname: sdw1, port: 7002, data-directory: /data/primary/gpseg0}
+    mirror: {dbid: 3, content: 0, role: mirror, hostname: sdw1, port: 7002, data-directory: /data/mirror/gpseg0}
+`), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = cli.RunTopologyValidate(nil, []string{path})
+		expected := "invalid topology file " + path + ":\nduplicate port entry 7002 found for host sdw1\nthe mirror of content 0 is on the same host sdw1 as its primary"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
@@ -7,6 +7,7 @@ const (
 	ConfigFileName        = "gp.conf"
 	ShellPath             = "/bin/bash"
 	GpSSH                 = "gpssh"
+	GpSync                = "gpsync"
 	MaxRetries            = 10
 	PlatformDarwin        = "darwin"
 	PlatformLinux         = "linux"
@@ -17,16 +18,44 @@ const (
 	DefaultDatabase       = "template1"
 	DefaultEncoding       = "UTF-8"
 	EtcHostsFilepath      = "/etc/hosts"
+	MountsFilepath        = "/proc/self/mounts"
 	CleanFileName         = "ClusterInitCLeanup.txt"
+	ClusterStateFileName  = "cluster_state.json"
+	ClusterLockFileName   = "cluster.lock"
+	HubAuditLogFileName   = "hub_audit.log"
+	AgentAuditLogFileName = "agent_audit.log"
+	HubEventsFileName     = "hub_events.log"
+	TopologyDirName       = "topology"
+	CliTracesFileName     = "cli_traces.json"
+	HubTracesFileName     = "hub_traces.json"
+	AgentTracesFileName   = "agent_traces.json"
 	ReplicationSlotName   = "internal_wal_replication_slot"
 	DefaultStartTimeout   = 600
 	DefaultPostgresLogDir = "log"
 	GroupMirroring        = "group"
 	SpreadMirroring       = "spread"
+	BlockMirroring        = "block"
+	RackAwareMirroring    = "rack-aware"
 	DefaultSegName        = "gpseg"
+	AutoBasePort          = "auto"
+	AutoPortSearchSize    = 1000
 	UserInputWaitDurtion  = 10
 )
 
+// TLS certificate file names
+const (
+	CACertFileName          = "ca-cert.pem"
+	CAKeyFileName           = "ca-key.pem"
+	ServerCertFileName      = "server-cert.pem"
+	ServerKeyFileName       = "server-key.pem"
+	ClientCertFileName      = "client-cert.pem"
+	ClientKeyFileName       = "client-key.pem"
+	DefaultCertsDirName     = "certificates"
+	DefaultCAValidityDays   = 3650
+	DefaultCertValidityDays = 365
+	CertExpiryWarningDays   = 30
+)
+
 // gp_segment_configuration specific constants
 const (
 	RolePrimary = "p"
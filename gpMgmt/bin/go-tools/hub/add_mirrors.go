@@ -2,21 +2,34 @@ package hub
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strconv"
-	"sync"
 
+	"golang.org/x/exp/slices"
+
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpdb/gp/constants"
 	"github.com/greenplum-db/gpdb/gp/idl"
 	"github.com/greenplum-db/gpdb/gp/utils"
 	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func (s *Server) AddMirrors(req *idl.AddMirrorsRequest, stream idl.Hub_AddMirrorsServer) error {
-	hubStream := NewHubStream(stream)
+	return s.runOperation(stream, "AddMirrors", func(ctx context.Context, hubStream *HubStream) error {
+		err := s.addMirrors(ctx, hubStream, req)
+		if err != nil && ctx.Err() != nil {
+			hubStream.StreamLogMsg(fmt.Sprintf("Adding the mirrors was interrupted: %v", ctx.Err()), idl.LogLevel_WARNING)
+		}
+
+		return err
+	})
+}
+
+func (s *Server) addMirrors(ctx context.Context, hubStream hubStreamer, req *idl.AddMirrorsRequest) error {
 	hubStream.StreamLogMsg("Starting to add mirrors to the cluster")
 
 	// Make sure all agents are up and listening for requests
@@ -36,10 +49,25 @@ func (s *Server) AddMirrors(req *idl.AddMirrorsRequest, stream idl.Hub_AddMirror
 		return utils.LogAndReturnError(err)
 	}
 
+	mirrors := req.Mirrors
+	if len(req.Topology) > 0 {
+		hubStream.StreamLogMsg("Reading the mirrors to add from the topology file")
+		mirrors, err = mirrorsFromTopology(req, gparray)
+		if err != nil {
+			return utils.LogAndReturnError(err)
+		}
+	} else if req.MirroringType != "" && len(req.Mirrors) == 0 {
+		hubStream.StreamLogMsg(fmt.Sprintf("Placing the mirrors with %s mirroring", req.MirroringType))
+		mirrors, err = placeMirrors(req, gparray)
+		if err != nil {
+			return utils.LogAndReturnError(err)
+		}
+	}
+
 	// Check if the number of primary and mirror segments are equal
 	hubStream.StreamLogMsg("Checking if the number of primary segments and the number of mirrors to add are equal")
-	if len(gparray.GetPrimarySegments()) != len(req.Mirrors) {
-		return utils.LogAndReturnError(fmt.Errorf("number of mirrors %d is not equal to the number of primaries %d present in the cluster", len(req.Mirrors), len(gparray.GetPrimarySegments())))
+	if len(gparray.GetPrimarySegments()) != len(mirrors) {
+		return utils.LogAndReturnError(fmt.Errorf("number of mirrors %d is not equal to the number of primaries %d present in the cluster", len(mirrors), len(gparray.GetPrimarySegments())))
 	}
 
 	// Check if the cluster already has mirrors, if yes error out
@@ -48,23 +76,40 @@ func (s *Server) AddMirrors(req *idl.AddMirrorsRequest, stream idl.Hub_AddMirror
 		return utils.LogAndReturnError(fmt.Errorf("cannot add mirrors, the cluster is already configured with mirrors"))
 	}
 
-	// Register the mirrors to the gp_segment_configuration
-	hubStream.StreamLogMsg("Starting to register mirror segments with the coordinator")
-	err = greenplum.RegisterMirrorSegments(req.Mirrors, conn)
+	hubStream.StreamLogMsg("Checking the topology of the cluster with the mirrors")
+	withMirrors, err := gpArrayWithMirrors(gparray, mirrors)
+	if err == nil {
+		err = validateTopology(hubStream, withMirrors, greenplum.ValidateOptions{
+			MirroringType:  req.MirroringType,
+			BlockSize:      int(req.BlockSize),
+			FailureDomains: req.FailureDomains,
+		})
+	}
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
-	hubStream.StreamLogMsg("Successfully registered the mirror segments with the coordinator")
 
-	// Build the new gparray and validate it
-	gparray, err = greenplum.NewGpArrayFromCatalog(conn)
+	hubStream.StreamLogMsg("Preparing the data directories of the mirrors")
+	err = s.prepareMirrorDirectories(ctx, mirrors, req.RefusedFilesystems)
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
 
+	// Register the mirrors to the gp_segment_configuration
+	s.snapshotTopology(ctx, hubStream, req.CoordinatorDataDir, TopologyBefore, gparray)
+	hubStream.StreamLogMsg("Starting to register mirror segments with the coordinator")
+	endStage := startStage(hubStream, StageRegisterMirrors, "Registering the mirror segments")
+	gparray, err = registerMirrorSegments(ctx, conn, mirrors)
+	if endStage(err) != nil {
+		return utils.LogAndReturnError(err)
+	}
+	s.snapshotTopology(ctx, hubStream, req.CoordinatorDataDir, TopologyAfter, gparray)
+	hubStream.StreamLogMsg("Successfully registered the mirror segments with the coordinator")
+
 	// Update the pg_hba.conf on the primary segments - Agent RPC
 	hubStream.StreamLogMsg("Starting to modify the pg_hba.conf on the primary segments to add mirror entries")
-	err = s.UpdatePgHbaConfWithMirrorEntries(gparray, req.Mirrors, req.HbaHostnames)
+	endStage = startStage(hubStream, StageUpdatePgHba, "Adding the mirrors to the pg_hba.conf of the primary segments")
+	err = endStage(s.UpdatePgHbaConfWithMirrorEntries(ctx, gparray, mirrors, req.HbaHostnames))
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
@@ -79,7 +124,8 @@ func (s *Server) AddMirrors(req *idl.AddMirrorsRequest, stream idl.Hub_AddMirror
 
 	// Run pg_basebackup aon the mirror hosts - Agent RPC
 	hubStream.StreamLogMsg("Creating mirror segments")
-	err = s.CreateMirrorSegments(&hubStream, gparray, req.Mirrors)
+	endStage = startStage(hubStream, StageCreateMirrors, "Creating the mirror segments")
+	err = endStage(s.CreateMirrorSegments(ctx, hubStream, gparray, mirrors))
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
@@ -87,7 +133,8 @@ func (s *Server) AddMirrors(req *idl.AddMirrorsRequest, stream idl.Hub_AddMirror
 
 	// Start the segment - Agent RPC
 	hubStream.StreamLogMsg("Starting up the mirror segments")
-	err = s.StartMirrorSegments(req.Mirrors)
+	endStage = startStage(hubStream, StageStartMirrors, "Starting the mirror segments")
+	err = endStage(s.StartMirrorSegments(ctx, hubStream, gparray, mirrors))
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
@@ -95,8 +142,11 @@ func (s *Server) AddMirrors(req *idl.AddMirrorsRequest, stream idl.Hub_AddMirror
 
 	// Run FTS
 	hubStream.StreamLogMsg("Triggering FTS probe")
+	endStage = startStage(hubStream, StageFtsProbe, "Triggering an FTS probe")
+	_, span := utils.StartSpan(ctx, "TriggerFtsProbe")
 	err = greenplum.TriggerFtsProbe(req.CoordinatorDataDir)
-	if err != nil {
+	utils.EndSpan(span, err)
+	if endStage(err) != nil {
 		return utils.LogAndReturnError(err)
 	}
 
@@ -107,7 +157,27 @@ func (s *Server) AddMirrors(req *idl.AddMirrorsRequest, stream idl.Hub_AddMirror
 	return nil
 }
 
-func (s *Server) CreateMirrorSegments(stream hubStreamer, gparray *greenplum.GpArray, mirrorSegs []*idl.Segment) error {
+// registerMirrorSegments registers the mirrors in the catalog and returns the resulting gparray
+func registerMirrorSegments(ctx context.Context, conn *dbconn.DBConn, mirrorSegs []*idl.Segment) (gparray *greenplum.GpArray, err error) {
+	_, span := utils.StartSpan(ctx, "RegisterMirrorSegments")
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
+	err = greenplum.RegisterMirrorSegments(mirrorSegs, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return greenplum.NewGpArrayFromCatalog(conn)
+}
+
+func (s *Server) CreateMirrorSegments(ctx context.Context, stream hubStreamer, gparray *greenplum.GpArray, mirrorSegs []*idl.Segment) (err error) {
+	ctx, span := utils.StartSpan(ctx, "CreateMirrorSegments", attribute.Int("segments", len(mirrorSegs)))
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
 	mirrorHostToSegPairMap := make(map[string][]*greenplum.SegmentPair)
 	for _, seg := range mirrorSegs {
 		pair, err := gparray.GetSegmentPairForContent(int(seg.Contentid))
@@ -118,111 +188,303 @@ func (s *Server) CreateMirrorSegments(stream hubStreamer, gparray *greenplum.GpA
 		mirrorHostToSegPairMap[pair.Mirror.Hostname] = append(mirrorHostToSegPairMap[pair.Mirror.Hostname], pair)
 	}
 
-	progressLabel := "Initializing mirror segments:"
-	progressTotal := len(mirrorSegs)
-	stream.StreamProgressMsg(progressLabel, progressTotal)
-
-	request := func(conn *Connection) error {
-		var wg sync.WaitGroup
+	progress := startProgress(stream, StageCreateMirrors, "Initializing mirror segments:", len(mirrorSegs))
+
+	request := func(ctx context.Context, conn *Connection, pair *greenplum.SegmentPair) (err error) {
+		gplog.Debug(fmt.Sprintf("Starting to create mirror segment: %v", *pair.Mirror))
+		mirror := mirrorSegment(pair)
+		stream.StreamSegmentMsg(mirror, idl.SegmentState_SEGMENT_CREATING, nil)
+		defer func() {
+			if err != nil {
+				stream.StreamSegmentMsg(mirror, idl.SegmentState_SEGMENT_FAILED, err)
+			}
+		}()
+
+		req := &idl.PgBasebackupRequest{
+			TargetDir:           pair.Mirror.DataDir,
+			SourceHost:          pair.Primary.Hostname,
+			SourcePort:          int32(pair.Primary.Port),
+			CreateSlot:          true,
+			TargetDbid:          int32(pair.Mirror.Dbid),
+			WriteRecoveryConf:   true,
+			ReplicationSlotName: constants.ReplicationSlotName,
+		}
+		err = s.retry(ctx, "PgBasebackup", conn.Hostname, func(attempt int) error {
+			if attempt > 1 {
+				// clean up the target of the failed attempt, the agent creates the replication slot again
+				_, err := conn.AgentClient.RemoveDirectory(ctx, &idl.RemoveDirectoryRequest{DataDirectory: pair.Mirror.DataDir})
+				if err != nil {
+					return err
+				}
+			}
 
-		pairs := mirrorHostToSegPairMap[conn.Hostname]
-		errs := make(chan error, len(pairs))
-		for _, pair := range pairs {
-			pair := pair
-			wg.Add(1)
+			backupStream, err := conn.AgentClient.PgBasebackupStream(ctx, req)
+			if err != nil {
+				return err
+			}
 
-			go func(pair *greenplum.SegmentPair) {
-				defer wg.Done()
+			source := OutputSource{Host: conn.Hostname, Segment: pair.Mirror.DataDir, Dbid: pair.Mirror.Dbid}
 
-				gplog.Debug(fmt.Sprintf("Starting to create mirror segment: %v", *pair.Mirror))
-				req := &idl.PgBasebackupRequest{
-					TargetDir:           pair.Mirror.DataDir,
-					SourceHost:          pair.Primary.Hostname,
-					SourcePort:          int32(pair.Primary.Port),
-					CreateSlot:          true,
-					TargetDbid:          int32(pair.Mirror.Dbid),
-					WriteRecoveryConf:   true,
-					ReplicationSlotName: constants.ReplicationSlotName,
-				}
-				_, err := conn.AgentClient.PgBasebackup(context.Background(), req)
-				if err != nil {
-					errs <- utils.FormatGrpcError(err)
-					return
-				}
-				gplog.Debug("Successfully ran pg_basebackup on segment with data directory %s on host %s", pair.Primary.DataDir, pair.Primary.Hostname)
-
-				gplog.Debug("Starting to modify the postgresql.conf for segment with data directory %s on host %s with port value %d", pair.Mirror.DataDir, pair.Mirror.Hostname, pair.Mirror.Port)
-				_, err = conn.AgentClient.UpdatePgConf(context.Background(), &idl.UpdatePgConfRequest{
-					Pgdata: pair.Mirror.DataDir,
-					Params: map[string]string{
-						"port": strconv.Itoa(pair.Mirror.Port),
-					},
-					Overwrite: true,
-				})
-				if err != nil {
-					errs <- err
-				} else {
-					gplog.Debug("Successfully modified the postgresql.conf for segment with data directory %s on host %s with port value %d", pair.Mirror.DataDir, pair.Mirror.Hostname, pair.Mirror.Port)
-					stream.StreamProgressMsg(progressLabel, progressTotal)
-					gplog.Debug(fmt.Sprintf("Successfully created mirror segment: %v", *pair.Mirror))
-				}
-			}(pair)
+			return relaySegmentCommand(stream, source, backupStream)
+		})
+		if err != nil {
+			return utils.NewHostError("PgBasebackup", pair.Mirror.DataDir, utils.FormatGrpcError(err))
 		}
+		gplog.Debug("Successfully ran pg_basebackup on segment with data directory %s on host %s", pair.Primary.DataDir, pair.Primary.Hostname)
+
+		gplog.Debug("Starting to modify the postgresql.conf for segment with data directory %s on host %s with port value %d", pair.Mirror.DataDir, pair.Mirror.Hostname, pair.Mirror.Port)
+		_, err = conn.AgentClient.UpdatePgConf(ctx, &idl.UpdatePgConfRequest{
+			Pgdata: pair.Mirror.DataDir,
+			Params: map[string]string{
+				"port": strconv.Itoa(pair.Mirror.Port),
+			},
+			Overwrite: true,
+		})
+		if err != nil {
+			return utils.NewHostError("UpdatePgConf", pair.Mirror.DataDir, utils.FormatGrpcError(err))
+		}
+
+		gplog.Debug("Successfully modified the postgresql.conf for segment with data directory %s on host %s with port value %d", pair.Mirror.DataDir, pair.Mirror.Hostname, pair.Mirror.Port)
+		stream.StreamSegmentMsg(mirror, idl.SegmentState_SEGMENT_CREATED, nil)
+		progress.increment()
+		gplog.Debug(fmt.Sprintf("Successfully created mirror segment: %v", *pair.Mirror))
+
+		return nil
+	}
 
-		wg.Wait()
-		close(errs)
+	conns, release := s.acquireConns()
+	defer release()
 
-		var err error
-		for e := range errs {
-			err = errors.Join(err, e)
+	return ExecuteRPCForEach(ctx, conns, mirrorHostToSegPairMap, request)
+}
+
+/*
+prepareMirrorDirectories creates and checks the parents of the data directories
+of the mirrors on their hosts, before the mirrors are registered.
+*/
+func (s *Server) prepareMirrorDirectories(ctx context.Context, mirrors []*idl.Segment, refusedFilesystems []string) error {
+	hostDirMap := make(map[string][]string)
+	for _, mirror := range mirrors {
+		hostDirMap[mirror.HostName] = append(hostDirMap[mirror.HostName], mirror.DataDirectory)
+	}
+
+	request := func(ctx context.Context, conn *Connection) error {
+		dataDirs, ok := hostDirMap[conn.Hostname]
+		if !ok {
+			return nil
 		}
 
-		return err
+		reply, err := conn.AgentClient.PrepareDirectories(ctx, &idl.PrepareDirectoriesRequest{
+			DataDirectories:    dataDirs,
+			RefusedFilesystems: refusedFilesystems,
+		})
+		if err != nil {
+			return utils.NewHostError("PrepareDirectories", "", utils.FormatGrpcError(err))
+		}
+		logMountPoints(reply.Directories)
+
+		return nil
 	}
 
-	return ExecuteRPC(s.Conns, request)
+	conns, release := s.acquireConns()
+	defer release()
+
+	return ExecuteRPC(ctx, conns, request)
 }
 
-func (s *Server) StartMirrorSegments(mirrorSegs []*idl.Segment) error {
+func (s *Server) StartMirrorSegments(ctx context.Context, stream hubStreamer, gparray *greenplum.GpArray, mirrorSegs []*idl.Segment) (err error) {
+	ctx, span := utils.StartSpan(ctx, "StartMirrorSegments", attribute.Int("segments", len(mirrorSegs)))
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
 	hostToSegMap := make(map[string][]*idl.Segment)
 	for _, seg := range mirrorSegs {
-		hostToSegMap[seg.HostName] = append(hostToSegMap[seg.HostName], seg)
+		pair, err := gparray.GetSegmentPairForContent(int(seg.Contentid))
+		if err != nil {
+			return err
+		}
+
+		// the segments of the request do not know their dbid, which is only assigned when they are registered
+		mirror := mirrorSegment(pair)
+		hostToSegMap[mirror.HostName] = append(hostToSegMap[mirror.HostName], mirror)
 	}
 
-	request := func(conn *Connection) error {
-		var wg sync.WaitGroup
+	request := func(ctx context.Context, conn *Connection, seg *idl.Segment) error {
+		req := &idl.StartSegmentRequest{
+			DataDir: seg.DataDirectory,
+			Wait:    true,
+			Options: "-c gp_role=execute",
+		}
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_STARTING, nil)
+		_, err := conn.AgentClient.StartSegment(ctx, req)
+		if err != nil {
+			err = utils.FormatGrpcError(err)
+			stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_FAILED, err)
+			return utils.NewHostError("StartSegment", seg.DataDirectory, err)
+		}
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_STARTED, nil)
 
-		segs := hostToSegMap[conn.Hostname]
-		errs := make(chan error, len(segs))
-		for _, seg := range segs {
-			seg := seg
-			wg.Add(1)
+		return nil
+	}
 
-			go func(seg *idl.Segment) {
-				defer wg.Done()
+	conns, release := s.acquireConns()
+	defer release()
 
-				req := &idl.StartSegmentRequest{
-					DataDir: seg.DataDirectory,
-					Wait:    true,
-					Options: "-c gp_role=execute",
-				}
-				_, err := conn.AgentClient.StartSegment(context.Background(), req)
-				if err != nil {
-					errs <- utils.FormatGrpcError(err)
-				}
-			}(seg)
+	return ExecuteRPCForEach(ctx, conns, hostToSegMap, request)
+}
+
+// gpArrayWithMirrors returns the gparray of the cluster once the mirrors are added to it
+func gpArrayWithMirrors(gparray *greenplum.GpArray, mirrors []*idl.Segment) (*greenplum.GpArray, error) {
+	result := &greenplum.GpArray{Coordinator: gparray.Coordinator, Standby: gparray.Standby}
+	contents := make(map[int]int)
+	for i, pair := range gparray.SegmentPairs {
+		result.SegmentPairs = append(result.SegmentPairs, greenplum.SegmentPair{Primary: pair.Primary, Mirror: pair.Mirror})
+		contents[pair.Primary.Content] = i
+	}
+
+	for _, mirror := range mirrors {
+		i, ok := contents[int(mirror.Contentid)]
+		if !ok {
+			return nil, fmt.Errorf("no primary segment found for the mirror of content %d", mirror.Contentid)
+		}
+		result.SegmentPairs[i].Mirror = &greenplum.Segment{
+			Dbid:          int(mirror.Dbid),
+			Content:       int(mirror.Contentid),
+			Role:          constants.RoleMirror,
+			PreferredRole: constants.RoleMirror,
+			Port:          int(mirror.Port),
+			Hostname:      mirror.HostName,
+			Address:       mirror.HostAddress,
+			DataDir:       mirror.DataDirectory,
 		}
+	}
 
-		wg.Wait()
-		close(errs)
+	return result, nil
+}
 
-		var err error
-		for e := range errs {
-			err = errors.Join(err, e)
+/*
+mirrorsFromTopology returns the mirrors of the topology file of the request,
+once its primaries are checked to be the ones of the cluster. The dbids of the
+mirrors are assigned when registering them, not taken from the file.
+*/
+func mirrorsFromTopology(req *idl.AddMirrorsRequest, gparray *greenplum.GpArray) ([]*idl.Segment, error) {
+	if len(req.Mirrors) > 0 {
+		return nil, fmt.Errorf("cannot add mirrors from both a list of mirrors and a topology file")
+	}
+
+	file, err := greenplum.ParseTopologyFile(req.Topology)
+	if err != nil {
+		return nil, err
+	}
+	topology, err := file.GpArray()
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology file:\n%w", err)
+	}
+	if !topology.HasMirrors() {
+		return nil, fmt.Errorf("the topology file has no mirrors to add")
+	}
+	if len(topology.SegmentPairs) != len(gparray.SegmentPairs) {
+		return nil, fmt.Errorf("number of primaries %d in the topology file is not equal to the number of primaries %d present in the cluster", len(topology.SegmentPairs), len(gparray.SegmentPairs))
+	}
+
+	for i, pair := range gparray.SegmentPairs {
+		want, got := pair.Primary, topology.SegmentPairs[i].Primary
+		if got.Dbid != want.Dbid || got.Content != want.Content || got.Port != want.Port || segmentLocation(got) != segmentLocation(want) {
+			return nil, fmt.Errorf("the primary of content %d in the topology file does not match the cluster, got dbid %d on %s port %d, want dbid %d on %s port %d",
+				want.Content, got.Dbid, segmentLocation(got), got.Port, want.Dbid, segmentLocation(want), want.Port)
 		}
+	}
 
-		return err
+	return topology.MirrorsToIdl(), nil
+}
+
+/*
+placeMirrors returns the mirrors of the primaries of the cluster placed with
+the mirroring type of the request. Every host has to run one primary per mirror
+data directory: the mirror of the k-th primary of a host, in content order, is
+placed by the strategy in a slot of another host, which picks its port from the
+mirror base port and its data directory from the mirror data directories.
+*/
+func placeMirrors(req *idl.AddMirrorsRequest, gparray *greenplum.GpArray) ([]*idl.Segment, error) {
+	if req.MirrorBasePort < 1 {
+		return nil, fmt.Errorf("invalid mirror base port %d", req.MirrorBasePort)
+	}
+	dirs := req.MirrorDataDirectories
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no mirror data directories provided")
+	}
+
+	strategy, err := greenplum.NewPlacementStrategy(req.MirroringType, int(req.BlockSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var hostnames []string
+	primaries := make(map[string][]*greenplum.Segment)
+	addresses := make(map[string][]string)
+	for _, pair := range gparray.SegmentPairs {
+		primary := pair.Primary
+		if _, ok := primaries[primary.Hostname]; !ok {
+			hostnames = append(hostnames, primary.Hostname)
+		}
+		primaries[primary.Hostname] = append(primaries[primary.Hostname], primary)
+		if !slices.Contains(addresses[primary.Hostname], primary.Address) {
+			addresses[primary.Hostname] = append(addresses[primary.Hostname], primary.Address)
+		}
+	}
+	slices.Sort(hostnames)
+
+	var hosts []greenplum.PlacementHost
+	for _, hostname := range hostnames {
+		if len(primaries[hostname]) != len(dirs) {
+			return nil, fmt.Errorf("every host has to run one primary per mirror data directory, found %d primaries on host %s for %d directories", len(primaries[hostname]), hostname, len(dirs))
+		}
+		sort.Slice(primaries[hostname], func(i, j int) bool {
+			return primaries[hostname][i].Content < primaries[hostname][j].Content
+		})
+		slices.Sort(addresses[hostname])
+		hosts = append(hosts, greenplum.PlacementHost{
+			Hostname:      hostname,
+			Addresses:     addresses[hostname],
+			FailureDomain: req.FailureDomains[hostname],
+		})
+	}
+
+	err = strategy.Check(hosts, len(dirs))
+	if err != nil {
+		return nil, err
 	}
 
-	return ExecuteRPC(s.Conns, request)
+	var mirrors []*idl.Segment
+	for i, placements := range strategy.PlaceMirrors(hosts, len(dirs)) {
+		for k, placement := range placements {
+			primary := primaries[hosts[i].Hostname][k]
+			host := hosts[placement.Host]
+			mirrors = append(mirrors, &idl.Segment{
+				Port:          req.MirrorBasePort + int32(placement.Slot),
+				DataDirectory: filepath.Join(dirs[placement.Slot], fmt.Sprintf("%s%d", constants.DefaultSegName, primary.Content)),
+				HostName:      host.Hostname,
+				HostAddress:   host.Addresses[placement.Address%len(host.Addresses)],
+				Contentid:     int32(primary.Content),
+			})
+		}
+	}
+	sort.Slice(mirrors, func(i, j int) bool {
+		return mirrors[i].Contentid < mirrors[j].Contentid
+	})
+
+	return mirrors, nil
+}
+
+// mirrorSegment returns the mirror of the pair as reported in the segment events
+func mirrorSegment(pair *greenplum.SegmentPair) *idl.Segment {
+	return &idl.Segment{
+		Port:          int32(pair.Mirror.Port),
+		DataDirectory: pair.Mirror.DataDir,
+		HostName:      pair.Mirror.Hostname,
+		HostAddress:   pair.Mirror.Address,
+		Contentid:     int32(pair.Mirror.Content),
+		Dbid:          int32(pair.Mirror.Dbid),
+	}
 }
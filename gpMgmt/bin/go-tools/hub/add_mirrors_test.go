@@ -1,6 +1,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -11,7 +12,9 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/greenplum-db/gp-common-go-libs/dbconn"
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
@@ -57,13 +60,13 @@ func initialize(t *testing.T) {
 
 	credentials := &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()}
 	hubConfig := &hub.Config{
-		1234,
-		5678,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        1234,
+		AgentPort:   5678,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      t.TempDir(),
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 	hubServer = hub.New(hubConfig, nil)
 }
@@ -94,10 +97,10 @@ func TestCreateMirrorSegments(t *testing.T) {
 		defer ctrl.Finish()
 
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
-		sdw1.EXPECT().PgBasebackup(
+		sdw1.EXPECT().PgBasebackupStream(
 			gomock.Any(),
 			gomock.Any(),
-		).Return(&idl.PgBasebackupResponse{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 		sdw1.EXPECT().UpdatePgConf(
 			gomock.Any(),
 			&idl.UpdatePgConfRequest{
@@ -110,10 +113,10 @@ func TestCreateMirrorSegments(t *testing.T) {
 		).Return(&idl.UpdatePgConfRespoonse{}, nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
-		sdw2.EXPECT().PgBasebackup(
+		sdw2.EXPECT().PgBasebackupStream(
 			gomock.Any(),
 			gomock.Any(),
-		).Return(&idl.PgBasebackupResponse{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 		sdw2.EXPECT().UpdatePgConf(
 			gomock.Any(),
 			&idl.UpdatePgConfRequest{
@@ -132,25 +135,30 @@ func TestCreateMirrorSegments(t *testing.T) {
 		hubServer.Conns = agentConns
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.CreateMirrorSegments(mock, gparray, mirrorSegs)
+		err := hubServer.CreateMirrorSegments(context.Background(), mock, gparray, mirrorSegs)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
 
-		expectedStreamResponse := make([]*idl.HubReply, len(mirrorSegs)+1)
-		for i := range expectedStreamResponse {
-			expectedStreamResponse[i] = &idl.HubReply{
-				Message: &idl.HubReply_ProgressMsg{
-					ProgressMsg: &idl.ProgressMessage{
-						Label: "Initializing mirror segments:",
-						Total: int32(len(mirrorSegs)),
-					},
-				},
-			}
+		var expectedProgress []*idl.ProgressMessage
+		for i := 0; i <= len(mirrorSegs); i++ {
+			expectedProgress = append(expectedProgress, &idl.ProgressMessage{
+				Stage:   hub.StageCreateMirrors,
+				Label:   "Initializing mirror segments:",
+				Current: int32(i),
+				Total:   int32(len(mirrorSegs)),
+			})
+		}
+		if !reflect.DeepEqual(progressMessages(stream.GetBuffer()), expectedProgress) {
+			t.Fatalf("got %+v, want %+v", progressMessages(stream.GetBuffer()), expectedProgress)
 		}
 
-		if !reflect.DeepEqual(stream.GetBuffer(), expectedStreamResponse) {
-			t.Fatalf("got %+v, want %+v", stream.GetBuffer(), expectedStreamResponse)
+		expectedStates := map[string][]idl.SegmentState{
+			mirror1.DataDir: {idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_CREATED},
+			mirror2.DataDir: {idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_CREATED},
+		}
+		if !reflect.DeepEqual(segmentStates(stream.GetBuffer()), expectedStates) {
+			t.Fatalf("got %+v, want %+v", segmentStates(stream.GetBuffer()), expectedStates)
 		}
 
 		testutils.AssertLogMessage(t, logfile, `\[DEBUG\]:-Starting to create mirror segment`)
@@ -160,22 +168,114 @@ func TestCreateMirrorSegments(t *testing.T) {
 		testutils.AssertLogMessage(t, logfile, `\[DEBUG\]:-Successfully created mirror segment`)
 	})
 
+	t.Run("relays the progress of pg_basebackup for each mirror", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		progress := func(done int64) *idl.SegmentCommandReply {
+			return &idl.SegmentCommandReply{
+				Message: &idl.SegmentCommandReply_Progress{
+					Progress: &idl.TransferProgress{Done: done, Total: 4096},
+				},
+			}
+		}
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		// the second progress is not relayed as it comes within TransferProgressInterval of the first one
+		sdw1.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(nil, progress(1024), progress(2048)), nil)
+		sdw1.EXPECT().UpdatePgConf(gomock.Any(), gomock.Any()).Return(&idl.UpdatePgConfRespoonse{}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(nil), nil)
+		sdw2.EXPECT().UpdatePgConf(gomock.Any(), gomock.Any()).Return(&idl.UpdatePgConfRespoonse{}, nil)
+
+		hubServer.Conns = []*hub.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		mock, stream := testutils.NewMockStream()
+		err := hubServer.CreateMirrorSegments(context.Background(), mock, gparray, mirrorSegs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var transfers []*idl.TransferMessage
+		for _, reply := range stream.GetBuffer() {
+			if msg, ok := reply.Message.(*idl.HubReply_TransferMsg); ok {
+				transfers = append(transfers, msg.TransferMsg)
+			}
+		}
+
+		label := fmt.Sprintf("sdw1:%s", mirror2.DataDir)
+		expected := []*idl.TransferMessage{
+			{Label: label, Current: 1024, Total: 4096},
+			{Label: label, Current: 2048, Total: 4096, Completed: true},
+		}
+		if !reflect.DeepEqual(transfers, expected) {
+			t.Fatalf("got %v, want %v", transfers, expected)
+		}
+	})
+
+	t.Run("cleans up the target and retries pg_basebackup when the agent is unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hubServer.Retries = map[string]hub.RetryPolicy{"PgBasebackup": {Attempts: 2}}
+		defer func() {
+			hubServer.Retries = nil
+		}()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		gomock.InOrder(
+			sdw1.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(status.Error(codes.Unavailable, "connection reset")), nil),
+			sdw1.EXPECT().RemoveDirectory(gomock.Any(), &idl.RemoveDirectoryRequest{DataDirectory: mirror2.DataDir}).Return(&idl.RemoveDirectoryReply{}, nil),
+			sdw1.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(nil), nil),
+		)
+		sdw1.EXPECT().UpdatePgConf(gomock.Any(), gomock.Any()).Return(&idl.UpdatePgConfRespoonse{}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(nil), nil)
+		sdw2.EXPECT().UpdatePgConf(gomock.Any(), gomock.Any()).Return(&idl.UpdatePgConfRespoonse{}, nil)
+
+		hubServer.Conns = []*hub.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		var retries []string
+		ctx := hub.WithRetryReporter(context.Background(), func(message string) {
+			retries = append(retries, message)
+		})
+
+		mock, _ := testutils.NewMockStream()
+		err := hubServer.CreateMirrorSegments(ctx, mock, gparray, mirrorSegs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []string{"PgBasebackup on host sdw1 failed, retrying in 0s (attempt 2 of 2): connection reset"}
+		if !reflect.DeepEqual(retries, expected) {
+			t.Fatalf("got %q, want %q", retries, expected)
+		}
+	})
+
 	t.Run("errors out when fails to run pg_basebackup", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		expectedErr := errors.New("error")
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
-		sdw1.EXPECT().PgBasebackup(
+		sdw1.EXPECT().PgBasebackupStream(
 			gomock.Any(),
 			gomock.Any(),
 		).Return(nil, expectedErr)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
-		sdw2.EXPECT().PgBasebackup(
+		sdw2.EXPECT().PgBasebackupStream(
 			gomock.Any(),
 			gomock.Any(),
-		).Return(&idl.PgBasebackupResponse{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 		sdw2.EXPECT().UpdatePgConf(
 			gomock.Any(),
 			gomock.Any(),
@@ -188,30 +288,30 @@ func TestCreateMirrorSegments(t *testing.T) {
 		hubServer.Conns = agentConns
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.CreateMirrorSegments(mock, gparray, mirrorSegs)
+		err := hubServer.CreateMirrorSegments(context.Background(), mock, gparray, mirrorSegs)
 		if !errors.Is(err, expectedErr) {
 			t.Fatalf("got %#v, want %#v", err, expectedErr)
 		}
 
-		expectedErrString := fmt.Sprintf("host: sdw1, %v", expectedErr)
+		expectedErrString := fmt.Sprintf("host: sdw1, segment: /data/mirror/gpseg1, %v", expectedErr)
 		if err.Error() != expectedErrString {
 			t.Fatalf("got %v, want %s", err, expectedErrString)
 		}
 
-		expectedStreamResponse := make([]*idl.HubReply, len(mirrorSegs))
-		for i := range expectedStreamResponse {
-			expectedStreamResponse[i] = &idl.HubReply{
-				Message: &idl.HubReply_ProgressMsg{
-					ProgressMsg: &idl.ProgressMessage{
-						Label: "Initializing mirror segments:",
-						Total: int32(len(mirrorSegs)),
-					},
-				},
-			}
+		expectedProgress := []*idl.ProgressMessage{
+			{Stage: hub.StageCreateMirrors, Label: "Initializing mirror segments:", Current: 0, Total: int32(len(mirrorSegs))},
+			{Stage: hub.StageCreateMirrors, Label: "Initializing mirror segments:", Current: 1, Total: int32(len(mirrorSegs))},
+		}
+		if !reflect.DeepEqual(progressMessages(stream.GetBuffer()), expectedProgress) {
+			t.Fatalf("got %+v, want %+v", progressMessages(stream.GetBuffer()), expectedProgress)
 		}
 
-		if !reflect.DeepEqual(stream.GetBuffer(), expectedStreamResponse) {
-			t.Fatalf("got %+v, want %+v", stream.GetBuffer(), expectedStreamResponse)
+		expectedStates := map[string][]idl.SegmentState{
+			mirror2.DataDir: {idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_FAILED},
+			mirror1.DataDir: {idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_CREATED},
+		}
+		if !reflect.DeepEqual(segmentStates(stream.GetBuffer()), expectedStates) {
+			t.Fatalf("got %+v, want %+v", segmentStates(stream.GetBuffer()), expectedStates)
 		}
 	})
 
@@ -221,20 +321,20 @@ func TestCreateMirrorSegments(t *testing.T) {
 
 		expectedErr := errors.New("error")
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
-		sdw1.EXPECT().PgBasebackup(
+		sdw1.EXPECT().PgBasebackupStream(
 			gomock.Any(),
 			gomock.Any(),
-		).Return(&idl.PgBasebackupResponse{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 		sdw1.EXPECT().UpdatePgConf(
 			gomock.Any(),
 			gomock.Any(),
 		).Return(&idl.UpdatePgConfRespoonse{}, nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
-		sdw2.EXPECT().PgBasebackup(
+		sdw2.EXPECT().PgBasebackupStream(
 			gomock.Any(),
 			gomock.Any(),
-		).Return(&idl.PgBasebackupResponse{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 		sdw2.EXPECT().UpdatePgConf(
 			gomock.Any(),
 			gomock.Any(),
@@ -247,30 +347,56 @@ func TestCreateMirrorSegments(t *testing.T) {
 		hubServer.Conns = agentConns
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.CreateMirrorSegments(mock, gparray, mirrorSegs)
+		err := hubServer.CreateMirrorSegments(context.Background(), mock, gparray, mirrorSegs)
 		if !errors.Is(err, expectedErr) {
 			t.Fatalf("got %#v, want %#v", err, expectedErr)
 		}
 
-		expectedErrString := fmt.Sprintf("host: sdw2, %v", expectedErr)
+		expectedErrString := fmt.Sprintf("host: sdw2, segment: /data/mirror/gpseg0, %v", expectedErr)
 		if err.Error() != expectedErrString {
 			t.Fatalf("got %v, want %s", err, expectedErrString)
 		}
 
-		expectedStreamResponse := make([]*idl.HubReply, len(mirrorSegs))
-		for i := range expectedStreamResponse {
-			expectedStreamResponse[i] = &idl.HubReply{
-				Message: &idl.HubReply_ProgressMsg{
-					ProgressMsg: &idl.ProgressMessage{
-						Label: "Initializing mirror segments:",
-						Total: int32(len(mirrorSegs)),
-					},
-				},
-			}
+		expectedProgress := []*idl.ProgressMessage{
+			{Stage: hub.StageCreateMirrors, Label: "Initializing mirror segments:", Current: 0, Total: int32(len(mirrorSegs))},
+			{Stage: hub.StageCreateMirrors, Label: "Initializing mirror segments:", Current: 1, Total: int32(len(mirrorSegs))},
+		}
+		if !reflect.DeepEqual(progressMessages(stream.GetBuffer()), expectedProgress) {
+			t.Fatalf("got %+v, want %+v", progressMessages(stream.GetBuffer()), expectedProgress)
+		}
+
+		expectedStates := map[string][]idl.SegmentState{
+			mirror1.DataDir: {idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_FAILED},
+			mirror2.DataDir: {idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_CREATED},
+		}
+		if !reflect.DeepEqual(segmentStates(stream.GetBuffer()), expectedStates) {
+			t.Fatalf("got %+v, want %+v", segmentStates(stream.GetBuffer()), expectedStates)
+		}
+	})
+
+	t.Run("formats the agent error when fails to update the postgresql.conf", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(nil), nil)
+		sdw1.EXPECT().UpdatePgConf(gomock.Any(), gomock.Any()).Return(&idl.UpdatePgConfRespoonse{}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(nil), nil)
+		sdw2.EXPECT().UpdatePgConf(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Internal, "could not open postgresql.conf"))
+
+		hubServer.Conns = []*hub.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		if !reflect.DeepEqual(stream.GetBuffer(), expectedStreamResponse) {
-			t.Fatalf("got %+v, want %+v", stream.GetBuffer(), expectedStreamResponse)
+		mock, _ := testutils.NewMockStream()
+		err := hubServer.CreateMirrorSegments(context.Background(), mock, gparray, mirrorSegs)
+
+		expectedErrString := "host: sdw2, segment: /data/mirror/gpseg0, could not open postgresql.conf"
+		if err == nil || err.Error() != expectedErrString {
+			t.Fatalf("got %v, want %s", err, expectedErrString)
 		}
 	})
 
@@ -278,7 +404,7 @@ func TestCreateMirrorSegments(t *testing.T) {
 		segs := []*idl.Segment{{Contentid: 1234}}
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.CreateMirrorSegments(mock, gparray, segs)
+		err := hubServer.CreateMirrorSegments(context.Background(), mock, gparray, segs)
 
 		expectedErrString := "could not find any segments with content 1234"
 		if err.Error() != expectedErrString {
@@ -342,10 +468,27 @@ func TestStartMirrorSegments(t *testing.T) {
 		}
 		hubServer.Conns = agentConns
 
-		err := hubServer.StartMirrorSegments(mirrorSegs)
+		mock, stream := testutils.NewMockStream()
+		err := hubServer.StartMirrorSegments(context.Background(), mock, gparray, mirrorSegs)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
+
+		expectedStates := map[string][]idl.SegmentState{
+			mirror1.DataDir: {idl.SegmentState_SEGMENT_STARTING, idl.SegmentState_SEGMENT_STARTED},
+			mirror2.DataDir: {idl.SegmentState_SEGMENT_STARTING, idl.SegmentState_SEGMENT_STARTED},
+		}
+		if !reflect.DeepEqual(segmentStates(stream.GetBuffer()), expectedStates) {
+			t.Fatalf("got %+v, want %+v", segmentStates(stream.GetBuffer()), expectedStates)
+		}
+
+		// the dbid of the mirrors is only known once they are registered
+		for _, reply := range stream.GetBuffer() {
+			event := reply.GetSegmentMsg()
+			if event.DataDirectory == mirror1.DataDir && event.Dbid != int32(mirror1.Dbid) {
+				t.Fatalf("got dbid %d, want %d", event.Dbid, mirror1.Dbid)
+			}
+		}
 	})
 
 	t.Run("errors out when not able to start the segment", func(t *testing.T) {
@@ -371,15 +514,24 @@ func TestStartMirrorSegments(t *testing.T) {
 		}
 		hubServer.Conns = agentConns
 
-		err := hubServer.StartMirrorSegments(mirrorSegs)
+		mock, stream := testutils.NewMockStream()
+		err := hubServer.StartMirrorSegments(context.Background(), mock, gparray, mirrorSegs)
 		if !errors.Is(err, expectedErr) {
 			t.Fatalf("got%#v, want %#v", err, expectedErr)
 		}
 
-		expectedErrString := fmt.Sprintf("host: sdw2, %v", expectedErr)
+		expectedErrString := fmt.Sprintf("host: sdw2, segment: /data/mirror/gpseg0, %v", expectedErr)
 		if err.Error() != expectedErrString {
 			t.Fatalf("got %v, want %s", err, expectedErrString)
 		}
+
+		expectedStates := map[string][]idl.SegmentState{
+			mirror1.DataDir: {idl.SegmentState_SEGMENT_STARTING, idl.SegmentState_SEGMENT_FAILED},
+			mirror2.DataDir: {idl.SegmentState_SEGMENT_STARTING, idl.SegmentState_SEGMENT_STARTED},
+		}
+		if !reflect.DeepEqual(segmentStates(stream.GetBuffer()), expectedStates) {
+			t.Fatalf("got %+v, want %+v", segmentStates(stream.GetBuffer()), expectedStates)
+		}
 	})
 }
 
@@ -472,6 +624,24 @@ func TestAddMirrors(t *testing.T) {
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
+
+		reply, err := hubServer.ListTopology(context.Background(), &idl.ListTopologyRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var phases []string
+		var segments []int32
+		for _, snapshot := range reply.Snapshots {
+			if snapshot.Method != "AddMirrors" {
+				t.Fatalf("got method %q, want AddMirrors", snapshot.Method)
+			}
+			phases = append(phases, snapshot.Phase)
+			segments = append(segments, snapshot.Segments)
+		}
+		if !reflect.DeepEqual(phases, []string{hub.TopologyBefore, hub.TopologyAfter}) || !reflect.DeepEqual(segments, []int32{3, 5}) {
+			t.Fatalf("got phases %v with %v segments, want the topology before and after adding the mirrors", phases, segments)
+		}
 	})
 
 	t.Run("when cluster already has mirrors", func(t *testing.T) {
@@ -517,6 +687,200 @@ func TestAddMirrors(t *testing.T) {
 		}
 	})
 
+	t.Run("when the primaries of the topology file are not the ones of the cluster", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetEnsureConnectionsAreReady(func(conns []*hub.Connection) error {
+			return nil
+		})
+		defer hub.ResetEnsureConnectionsAreReady()
+
+		utils.System.Open = func(name string) (*os.File, error) {
+			reader, writer, _ := os.Pipe()
+			defer writer.Close()
+
+			_, err := writer.WriteString("port=1234")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			return reader, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		greenplum.SetNewDBConnFromEnvironment(func(dbname string) *dbconn.DBConn {
+			conn, mock := testutils.CreateMockDBConnForUtilityMode(t)
+			testhelper.ExpectVersionQuery(mock, "7.0.0")
+
+			rows := sqlmock.NewRows([]string{"dbid", "content", "role", "preferredrole", "port", "hostname", "address", "datadir"})
+			addSegmentRows(t, rows, coordinator, primary1, primary2)
+			mock.ExpectQuery("SELECT").WillReturnRows(rows)
+			return conn
+		})
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		hubServer.Conns = createMockClients(t, ctrl, ErrorType{})
+
+		topology := greenplum.NewTopologyFile(gparray)
+		topology.Segments[1].Primary.Port = 7005
+		contents, err := topology.Marshal(greenplum.TopologyFormatYAML)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, stream := testutils.NewMockStream()
+		err = hubServer.AddMirrors(&idl.AddMirrorsRequest{HbaHostnames: true, Topology: contents}, stream)
+		expectedErrString := "the primary of content 1 in the topology file does not match the cluster, got dbid 4 on sdw2:/data/primary/gpseg1 port 7005, want dbid 4 on sdw2:/data/primary/gpseg1 port 7003"
+		if err == nil || err.Error() != expectedErrString {
+			t.Fatalf("got %v, want %s", err, expectedErrString)
+		}
+	})
+
+	t.Run("when a mirror is on the same host as its primary", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetEnsureConnectionsAreReady(func(conns []*hub.Connection) error {
+			return nil
+		})
+		defer hub.ResetEnsureConnectionsAreReady()
+
+		utils.System.Open = func(name string) (*os.File, error) {
+			reader, writer, _ := os.Pipe()
+			defer writer.Close()
+
+			_, err := writer.WriteString("port=1234")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			return reader, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		greenplum.SetNewDBConnFromEnvironment(func(dbname string) *dbconn.DBConn {
+			conn, mock := testutils.CreateMockDBConnForUtilityMode(t)
+			testhelper.ExpectVersionQuery(mock, "7.0.0")
+
+			rows := sqlmock.NewRows([]string{"dbid", "content", "role", "preferredrole", "port", "hostname", "address", "datadir"})
+			addSegmentRows(t, rows, coordinator, primary1, primary2)
+			mock.ExpectQuery("SELECT").WillReturnRows(rows)
+			return conn
+		})
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		hubServer.Conns = createMockClients(t, ctrl, ErrorType{})
+
+		sameHostMirrors := []*idl.Segment{
+			{Port: int32(mirror1.Port), HostName: primary1.Hostname, HostAddress: primary1.Address, DataDirectory: mirror1.DataDir, Contentid: int32(mirror1.Content)},
+			{Port: int32(mirror2.Port), HostName: mirror2.Hostname, HostAddress: mirror2.Address, DataDirectory: mirror2.DataDir, Contentid: int32(mirror2.Content)},
+		}
+
+		_, stream := testutils.NewMockStream()
+		err := hubServer.AddMirrors(&idl.AddMirrorsRequest{HbaHostnames: true, Mirrors: sameHostMirrors}, stream)
+		expectedErrString := "invalid topology:\nthe mirror of content 0 is on the same host sdw1 as its primary"
+		if err == nil || err.Error() != expectedErrString {
+			t.Fatalf("got %v, want %s", err, expectedErrString)
+		}
+	})
+
+	t.Run("when the mirrors placed with the mirroring type share the failure domain of their primary", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetEnsureConnectionsAreReady(func(conns []*hub.Connection) error {
+			return nil
+		})
+		defer hub.ResetEnsureConnectionsAreReady()
+
+		utils.System.Open = func(name string) (*os.File, error) {
+			reader, writer, _ := os.Pipe()
+			defer writer.Close()
+
+			_, err := writer.WriteString("port=1234")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			return reader, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		greenplum.SetNewDBConnFromEnvironment(func(dbname string) *dbconn.DBConn {
+			conn, mock := testutils.CreateMockDBConnForUtilityMode(t)
+			testhelper.ExpectVersionQuery(mock, "7.0.0")
+
+			rows := sqlmock.NewRows([]string{"dbid", "content", "role", "preferredrole", "port", "hostname", "address", "datadir"})
+			addSegmentRows(t, rows, coordinator, primary1, primary2)
+			mock.ExpectQuery("SELECT").WillReturnRows(rows)
+			return conn
+		})
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		hubServer.Conns = createMockClients(t, ctrl, ErrorType{})
+
+		_, stream := testutils.NewMockStream()
+		err := hubServer.AddMirrors(&idl.AddMirrorsRequest{
+			MirroringType:         constants.GroupMirroring,
+			MirrorBasePort:        7002,
+			MirrorDataDirectories: []string{"/data/mirror"},
+			FailureDomains:        map[string]string{"sdw1": "rack1", "sdw2": "rack1"},
+		}, stream)
+		expectedErrString := "invalid topology:\nthe mirror of content 0 is in the same failure domain rack1 as its primary\nthe mirror of content 1 is in the same failure domain rack1 as its primary"
+		if err == nil || err.Error() != expectedErrString {
+			t.Fatalf("got %v, want %s", err, expectedErrString)
+		}
+	})
+
+	t.Run("when the mirrors can not be placed with the mirroring type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetEnsureConnectionsAreReady(func(conns []*hub.Connection) error {
+			return nil
+		})
+		defer hub.ResetEnsureConnectionsAreReady()
+
+		utils.System.Open = func(name string) (*os.File, error) {
+			reader, writer, _ := os.Pipe()
+			defer writer.Close()
+
+			_, err := writer.WriteString("port=1234")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			return reader, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		greenplum.SetNewDBConnFromEnvironment(func(dbname string) *dbconn.DBConn {
+			conn, mock := testutils.CreateMockDBConnForUtilityMode(t)
+			testhelper.ExpectVersionQuery(mock, "7.0.0")
+
+			rows := sqlmock.NewRows([]string{"dbid", "content", "role", "preferredrole", "port", "hostname", "address", "datadir"})
+			addSegmentRows(t, rows, coordinator, primary1, primary2)
+			mock.ExpectQuery("SELECT").WillReturnRows(rows)
+			return conn
+		})
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		hubServer.Conns = createMockClients(t, ctrl, ErrorType{})
+
+		_, stream := testutils.NewMockStream()
+		err := hubServer.AddMirrors(&idl.AddMirrorsRequest{
+			MirroringType:         constants.BlockMirroring,
+			MirrorBasePort:        7002,
+			MirrorDataDirectories: []string{"/data/mirror1", "/data/mirror2"},
+			BlockSize:             2,
+		}, stream)
+		expectedErrString := "every host has to run one primary per mirror data directory, found 1 primaries on host sdw1 for 2 directories"
+		if err == nil || err.Error() != expectedErrString {
+			t.Fatalf("got %v, want %s", err, expectedErrString)
+		}
+	})
+
 	t.Run("when number of mirror segments are not equal to the primary segments", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -574,6 +938,9 @@ func TestAddMirrors(t *testing.T) {
 		{
 			StartSegment: expectedErr,
 		},
+		{
+			PrepareDirectories: expectedErr,
+		},
 	}
 	for _, tc := range cases {
 		t.Run("returns appropriate error during different RPC calls", func(t *testing.T) {
@@ -662,10 +1029,11 @@ func createSegment(t *testing.T, dbid int, content int, role string, preferredRo
 }
 
 type ErrorType struct {
-	PgBasebackup    error
-	UpdatePgConf    error
-	StartSegment    error
-	UpdatePgHbaConf error
+	PgBasebackup       error
+	UpdatePgConf       error
+	StartSegment       error
+	UpdatePgHbaConf    error
+	PrepareDirectories error
 }
 
 func createMockClients(t *testing.T, ctrl *gomock.Controller, errorType ErrorType) []*hub.Connection {
@@ -675,15 +1043,17 @@ func createMockClients(t *testing.T, ctrl *gomock.Controller, errorType ErrorTyp
 	sdw1 := mock_idl.NewMockAgentClient(ctrl)
 	sdw2 := mock_idl.NewMockAgentClient(ctrl)
 
-	sdw1.EXPECT().PgBasebackup(gomock.Any(), gomock.Any()).Return(nil, errorType.PgBasebackup).AnyTimes()
+	sdw1.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(errorType.PgBasebackup), nil).AnyTimes()
 	sdw1.EXPECT().UpdatePgConf(gomock.Any(), gomock.Any()).Return(nil, errorType.UpdatePgConf).AnyTimes()
 	sdw1.EXPECT().StartSegment(gomock.Any(), gomock.Any()).Return(nil, errorType.StartSegment).AnyTimes()
 	sdw1.EXPECT().UpdatePgHbaConfAndReload(gomock.Any(), gomock.Any()).Return(nil, errorType.UpdatePgHbaConf).AnyTimes()
+	sdw1.EXPECT().PrepareDirectories(gomock.Any(), gomock.Any()).Return(&idl.PrepareDirectoriesReply{}, errorType.PrepareDirectories).AnyTimes()
 
-	sdw2.EXPECT().PgBasebackup(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	sdw2.EXPECT().PgBasebackupStream(gomock.Any(), gomock.Any()).Return(testutils.NewMockSegmentCommandStream(nil), nil).AnyTimes()
 	sdw2.EXPECT().UpdatePgConf(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	sdw2.EXPECT().StartSegment(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	sdw2.EXPECT().UpdatePgHbaConfAndReload(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	sdw2.EXPECT().PrepareDirectories(gomock.Any(), gomock.Any()).Return(&idl.PrepareDirectoriesReply{}, nil).AnyTimes()
 
 	return []*hub.Connection{
 		{AgentClient: cdw, Hostname: "cdw"},
@@ -0,0 +1,110 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+/*
+AdoptCluster brings an existing cluster, e.g. one created using gpinitsystem,
+under the management of the hub. It reads the topology from the coordinator
+catalog, verifies the data directories of all the segments through the agents
+and records the topology in the hub cluster state.
+*/
+func (s *Server) AdoptCluster(ctx context.Context, req *idl.AdoptClusterRequest) (*idl.AdoptClusterReply, error) {
+	lock, err := s.acquireLock(ctx, "AdoptCluster", nil)
+	if err != nil {
+		return &idl.AdoptClusterReply{}, err
+	}
+	defer lock.Release()
+
+	gplog.Info("Starting to adopt the cluster with coordinator data directory %s", req.CoordinatorDataDir)
+
+	err = s.DialAllAgents()
+	if err != nil {
+		return &idl.AdoptClusterReply{}, utils.LogAndReturnError(err)
+	}
+
+	conn, err := greenplum.GetCoordinatorConn(req.CoordinatorDataDir, "", true)
+	if err != nil {
+		return &idl.AdoptClusterReply{}, utils.LogAndReturnError(fmt.Errorf("failed to connect to the coordinator: %w", err))
+	}
+	defer conn.Close()
+
+	gparray, err := greenplum.NewGpArrayFromCatalog(conn)
+	if err != nil {
+		return &idl.AdoptClusterReply{}, utils.LogAndReturnError(err)
+	}
+
+	hostnames := gparray.GetHostnames()
+	for _, host := range hostnames {
+		if !slices.Contains(s.Hostnames, host) {
+			return &idl.AdoptClusterReply{}, utils.LogAndReturnError(fmt.Errorf("host %s of the cluster is not configured with the hub", host))
+		}
+	}
+
+	err = s.VerifyDataDirectories(ctx, gparray)
+	if err != nil {
+		return &idl.AdoptClusterReply{}, utils.LogAndReturnError(err)
+	}
+
+	err = s.SaveClusterState(&ClusterState{
+		CoordinatorDataDir: req.CoordinatorDataDir,
+		Adopted:            true,
+		GpArray:            gparray,
+	})
+	if err != nil {
+		return &idl.AdoptClusterReply{}, utils.LogAndReturnError(err)
+	}
+	gplog.Info("Successfully adopted the cluster with coordinator data directory %s", req.CoordinatorDataDir)
+
+	return &idl.AdoptClusterReply{
+		Hostnames:   hostnames,
+		NumSegments: int32(len(gparray.GetAllSegments())),
+	}, nil
+}
+
+// VerifyDataDirectories checks the data directories of all the segments in the gparray on their respective hosts
+func (s *Server) VerifyDataDirectories(ctx context.Context, gparray *greenplum.GpArray) error {
+	segs := gparray.GetAllSegments()
+	if gparray.Coordinator != nil {
+		segs = append(segs, *gparray.Coordinator)
+	}
+	if gparray.Standby != nil {
+		segs = append(segs, *gparray.Standby)
+	}
+
+	hostDataDirMap := make(map[string][]string)
+	for _, seg := range segs {
+		hostDataDirMap[seg.Hostname] = append(hostDataDirMap[seg.Hostname], seg.DataDir)
+	}
+
+	request := func(ctx context.Context, conn *Connection) error {
+		dataDirs, ok := hostDataDirMap[conn.Hostname]
+		if !ok {
+			return nil
+		}
+
+		gplog.Debug("Verifying data directories %v on host %s", dataDirs, conn.Hostname)
+		_, err := conn.AgentClient.VerifyDataDirectories(ctx, &idl.VerifyDataDirectoriesRequest{
+			DataDirectories: dataDirs,
+		})
+		if err != nil {
+			return utils.NewHostError("VerifyDataDirectories", "", utils.FormatGrpcError(err))
+		}
+
+		return nil
+	}
+
+	conns, release := s.acquireConns()
+	defer release()
+
+	return ExecuteRPC(ctx, conns, request)
+}
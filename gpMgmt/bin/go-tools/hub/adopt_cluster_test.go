@@ -0,0 +1,205 @@
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/idl/mock_idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+func TestAdoptCluster(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	coordinator := createSegment(t, 1, -1, constants.RolePrimary, constants.RolePrimary, 7000, "cdw", "cdw", "/data/primary/gpseg-1")
+	primary1 := createSegment(t, 2, 0, constants.RolePrimary, constants.RolePrimary, 7001, "sdw1", "sdw1", "/data/primary/gpseg0")
+	primary2 := createSegment(t, 3, 1, constants.RolePrimary, constants.RolePrimary, 7002, "sdw2", "sdw2", "/data/primary/gpseg1")
+
+	setupCoordinatorConn := func(t *testing.T) {
+		t.Helper()
+
+		utils.System.Open = func(name string) (*os.File, error) {
+			reader, writer, _ := os.Pipe()
+			defer writer.Close()
+
+			_, err := writer.WriteString("port=7000")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			return reader, nil
+		}
+
+		greenplum.SetNewDBConnFromEnvironment(func(dbname string) *dbconn.DBConn {
+			conn, mock := testutils.CreateMockDBConnForUtilityMode(t)
+			testhelper.ExpectVersionQuery(mock, "7.0.0")
+
+			rows := sqlmock.NewRows([]string{"dbid", "content", "role", "preferredrole", "port", "hostname", "address", "datadir"})
+			addSegmentRows(t, rows, coordinator, primary1, primary2)
+			mock.ExpectQuery("SELECT").WillReturnRows(rows)
+
+			return conn
+		})
+	}
+
+	newHubServer := func(t *testing.T, hostnames []string) *hub.Server {
+		t.Helper()
+
+		return hub.New(&hub.Config{
+			Port:        1234,
+			AgentPort:   5678,
+			Hostnames:   hostnames,
+			LogDir:      t.TempDir(),
+			ServiceName: "gp",
+			GpHome:      "gpHome",
+			Credentials: &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()},
+		}, nil)
+	}
+
+	hub.SetEnsureConnectionsAreReady(func(conns []*hub.Connection) error {
+		return nil
+	})
+	defer hub.ResetEnsureConnectionsAreReady()
+
+	t.Run("verifies the data directories and records the cluster state", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		setupCoordinatorConn(t)
+		defer utils.ResetSystemFunctions()
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		cdw := mock_idl.NewMockAgentClient(ctrl)
+		cdw.EXPECT().VerifyDataDirectories(gomock.Any(), &idl.VerifyDataDirectoriesRequest{
+			DataDirectories: []string{coordinator.DataDir},
+		}).Return(&idl.VerifyDataDirectoriesReply{}, nil)
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().VerifyDataDirectories(gomock.Any(), &idl.VerifyDataDirectoriesRequest{
+			DataDirectories: []string{primary1.DataDir},
+		}).Return(&idl.VerifyDataDirectoriesReply{}, nil)
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().VerifyDataDirectories(gomock.Any(), &idl.VerifyDataDirectoriesRequest{
+			DataDirectories: []string{primary2.DataDir},
+		}).Return(&idl.VerifyDataDirectoriesReply{}, nil)
+
+		hubServer := newHubServer(t, []string{"cdw", "sdw1", "sdw2"})
+		hubServer.Conns = []*hub.Connection{
+			{AgentClient: cdw, Hostname: "cdw"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		reply, err := hubServer.AdoptCluster(context.Background(), &idl.AdoptClusterRequest{CoordinatorDataDir: coordinator.DataDir})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectedReply := &idl.AdoptClusterReply{Hostnames: []string{"cdw", "sdw1", "sdw2"}, NumSegments: 2}
+		if !reflect.DeepEqual(reply, expectedReply) {
+			t.Fatalf("got %+v, want %+v", reply, expectedReply)
+		}
+
+		state, err := hub.LoadClusterState(hubServer.LogDir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !state.Adopted {
+			t.Fatalf("expected the cluster state to be marked as adopted")
+		}
+
+		if state.CoordinatorDataDir != coordinator.DataDir {
+			t.Fatalf("got %s, want %s", state.CoordinatorDataDir, coordinator.DataDir)
+		}
+
+		expectedSegs := []greenplum.Segment{*primary1, *primary2}
+		if !reflect.DeepEqual(state.GpArray.GetAllSegments(), expectedSegs) {
+			t.Fatalf("got %+v, want %+v", state.GpArray.GetAllSegments(), expectedSegs)
+		}
+	})
+
+	t.Run("errors out when a host of the cluster is not configured with the hub", func(t *testing.T) {
+		setupCoordinatorConn(t)
+		defer utils.ResetSystemFunctions()
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		hubServer := newHubServer(t, []string{"cdw", "sdw1"})
+		hubServer.Conns = []*hub.Connection{{Hostname: "cdw"}, {Hostname: "sdw1"}}
+
+		_, err := hubServer.AdoptCluster(context.Background(), &idl.AdoptClusterRequest{CoordinatorDataDir: coordinator.DataDir})
+		expected := "host sdw2 of the cluster is not configured with the hub"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		_, err = os.Stat(hub.ClusterStateFilePath(hubServer.LogDir))
+		if !os.IsNotExist(err) {
+			t.Fatalf("expected the cluster state to not be recorded, got %v", err)
+		}
+	})
+
+	t.Run("errors out when not able to verify the data directories", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		setupCoordinatorConn(t)
+		defer utils.ResetSystemFunctions()
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		expectedErr := errors.New("error")
+		cdw := mock_idl.NewMockAgentClient(ctrl)
+		cdw.EXPECT().VerifyDataDirectories(gomock.Any(), gomock.Any()).Return(&idl.VerifyDataDirectoriesReply{}, nil)
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().VerifyDataDirectories(gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().VerifyDataDirectories(gomock.Any(), gomock.Any()).Return(&idl.VerifyDataDirectoriesReply{}, nil)
+
+		hubServer := newHubServer(t, []string{"cdw", "sdw1", "sdw2"})
+		hubServer.Conns = []*hub.Connection{
+			{AgentClient: cdw, Hostname: "cdw"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		_, err := hubServer.AdoptCluster(context.Background(), &idl.AdoptClusterRequest{CoordinatorDataDir: coordinator.DataDir})
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %#v, want %#v", err, expectedErr)
+		}
+
+		expected := "host: sdw1, error"
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when not able to connect to the coordinator", func(t *testing.T) {
+		expectedErr := os.ErrNotExist
+		utils.System.Open = func(name string) (*os.File, error) {
+			return nil, expectedErr
+		}
+		defer utils.ResetSystemFunctions()
+
+		hubServer := newHubServer(t, []string{"cdw"})
+		hubServer.Conns = []*hub.Connection{{Hostname: "cdw"}}
+
+		_, err := hubServer.AdoptCluster(context.Background(), &idl.AdoptClusterRequest{CoordinatorDataDir: coordinator.DataDir})
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %#v, want %#v", err, expectedErr)
+		}
+	})
+}
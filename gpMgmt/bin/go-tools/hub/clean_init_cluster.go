@@ -7,7 +7,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpdb/gp/constants"
@@ -38,9 +37,18 @@ func CreateHostDataDirMap(entries []string, file string) (map[string][]string, e
 /*
 rpc to cleanup the data directories in case gp init cluster fails.
 */
-func (s *Server) CleanInitCluster(context.Context, *idl.CleanInitClusterRequest) (*idl.CleanInitClusterReply, error) {
+func (s *Server) CleanInitCluster(ctx context.Context, req *idl.CleanInitClusterRequest) (*idl.CleanInitClusterReply, error) {
+	lock, err := s.acquireLock(ctx, "CleanInitCluster", nil)
+	if err != nil {
+		return &idl.CleanInitClusterReply{}, err
+	}
+	defer lock.Release()
+
+	// carry over the trace of the CLI, but do not abandon the cleanup halfway if the CLI goes away;
+	// the cleanup is still limited by its own timeout
+	ctx, cancel := s.withTimeout(context.WithoutCancel(ctx), "CleanInitCluster")
+	defer cancel()
 
-	var err error
 	fileName := filepath.Join(s.LogDir, constants.CleanFileName)
 
 	_, err = utils.System.Stat(fileName)
@@ -61,40 +69,22 @@ func (s *Server) CleanInitCluster(context.Context, *idl.CleanInitClusterRequest)
 		return &idl.CleanInitClusterReply{}, utils.LogAndReturnError(fmt.Errorf("invalid entries in cleanup file"))
 	}
 
-	request := func(conn *Connection) error {
-		var wg sync.WaitGroup
-
-		hostname := hostDataDirMap[conn.Hostname]
-		errs := make(chan error, len(hostname))
-
-		for _, dir := range hostname {
-			dir := dir
-			wg.Add(1)
-
-			go func(dirname string) {
-				defer wg.Done()
-
-				gplog.Debug("Removing Data Directories: %s", dir)
-				_, err := conn.AgentClient.RemoveDirectory(context.Background(), &idl.RemoveDirectoryRequest{
-					DataDirectory: dir,
-				})
-				if err != nil {
-					errs <- utils.FormatGrpcError(err)
-				}
-			}(dir)
+	request := func(ctx context.Context, conn *Connection, dir string) error {
+		gplog.Debug("Removing Data Directories: %s", dir)
+		_, err := conn.AgentClient.RemoveDirectory(ctx, &idl.RemoveDirectoryRequest{
+			DataDirectory: dir,
+		})
+		if err != nil {
+			return utils.NewHostError("RemoveDirectory", dir, utils.FormatGrpcError(err))
 		}
 
-		wg.Wait()
-		close(errs)
-
-		var err error
-		for e := range errs {
-			err = errors.Join(err, e)
-		}
-		return err
+		return nil
 	}
 
 	defer os.Remove(fileName)
 
-	return &idl.CleanInitClusterReply{}, ExecuteRPC(s.Conns, request)
+	conns, release := s.acquireConns()
+	defer release()
+
+	return &idl.CleanInitClusterReply{}, ExecuteRPCForEach(ctx, conns, hostDataDirMap, request)
 }
@@ -46,13 +46,13 @@ func TestCleanInitCluster(t *testing.T) {
 
 	credentials := &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()}
 	hubConfig := &hub.Config{
-		1234,
-		5678,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        1234,
+		AgentPort:   5678,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      "/tmp/logDir",
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 	hubServer := hub.New(hubConfig, nil)
 
@@ -0,0 +1,85 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+/*
+ClusterState is the hub's record of the cluster it manages. It is written once
+a cluster is created by the hub or an existing cluster is adopted, so that the
+lifecycle commands do not depend on how the cluster was originally built.
+*/
+type ClusterState struct {
+	CoordinatorDataDir string             `json:"coordinatorDataDir"`
+	Adopted            bool               `json:"adopted"`
+	UpdatedAt          time.Time          `json:"updatedAt"`
+	GpArray            *greenplum.GpArray `json:"gpArray"`
+}
+
+func ClusterStateFilePath(logDir string) string {
+	return filepath.Join(logDir, constants.ClusterStateFileName)
+}
+
+// LoadClusterState reads the cluster state recorded under the given hub log directory
+func LoadClusterState(logDir string) (*ClusterState, error) {
+	filename := ClusterStateFilePath(logDir)
+	contents, err := utils.System.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cluster state file %s: %w", filename, err)
+	}
+
+	state := &ClusterState{}
+	err = json.Unmarshal(contents, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the cluster state file %s: %w", filename, err)
+	}
+
+	return state, nil
+}
+
+// SaveClusterState records the given cluster state under the hub log directory
+func (s *Server) SaveClusterState(state *ClusterState) error {
+	state.UpdatedAt = time.Now().UTC()
+	contents, err := json.MarshalIndent(state, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the cluster state: %w", err)
+	}
+
+	filename := ClusterStateFilePath(s.LogDir)
+	err = utils.System.WriteFile(filename, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the cluster state file %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+/*
+RecordClusterState reads the current topology of the cluster from the
+coordinator catalog and saves it as the hub cluster state.
+*/
+func (s *Server) RecordClusterState(coordinatorDataDir string, adopted bool) error {
+	conn, err := greenplum.GetCoordinatorConn(coordinatorDataDir, "", true)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	gparray, err := greenplum.NewGpArrayFromCatalog(conn)
+	if err != nil {
+		return err
+	}
+
+	return s.SaveClusterState(&ClusterState{
+		CoordinatorDataDir: coordinatorDataDir,
+		Adopted:            adopted,
+		GpArray:            gparray,
+	})
+}
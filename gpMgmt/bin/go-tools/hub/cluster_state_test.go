@@ -0,0 +1,68 @@
+package hub_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+func TestClusterState(t *testing.T) {
+	t.Run("saves and loads the cluster state", func(t *testing.T) {
+		hubServer := hub.New(&hub.Config{LogDir: t.TempDir()}, nil)
+
+		state := &hub.ClusterState{
+			CoordinatorDataDir: "/data/primary/gpseg-1",
+			Adopted:            true,
+			GpArray: &greenplum.GpArray{
+				Coordinator: &greenplum.Segment{Dbid: 1, Content: -1, Hostname: "cdw", DataDir: "/data/primary/gpseg-1"},
+				SegmentPairs: []greenplum.SegmentPair{
+					{Primary: &greenplum.Segment{Dbid: 2, Content: 0, Hostname: "sdw1", DataDir: "/data/primary/gpseg0"}},
+				},
+			},
+		}
+		err := hubServer.SaveClusterState(state)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := hub.LoadClusterState(hubServer.LogDir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !result.UpdatedAt.Equal(state.UpdatedAt) {
+			t.Fatalf("got %v, want %v", result.UpdatedAt, state.UpdatedAt)
+		}
+		result.UpdatedAt = state.UpdatedAt
+
+		if !reflect.DeepEqual(result, state) {
+			t.Fatalf("got %+v, want %+v", result, state)
+		}
+	})
+
+	t.Run("errors out when the cluster state does not exist", func(t *testing.T) {
+		_, err := hub.LoadClusterState(t.TempDir())
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want %v", err, os.ErrNotExist)
+		}
+	})
+
+	t.Run("errors out when the cluster state is not valid", func(t *testing.T) {
+		logDir := t.TempDir()
+		err := os.WriteFile(hub.ClusterStateFilePath(logDir), []byte("invalid"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = hub.LoadClusterState(logDir)
+		expected := "failed to parse the cluster state file"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
@@ -0,0 +1,432 @@
+package hub
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+// Types of the events recorded for the operations, besides the types of the replies they stream
+const (
+	EventTypeOperation = "operation"
+	EventTypeLog       = "log"
+	EventTypeStdout    = "stdout"
+	EventTypeOutput    = "output"
+	EventTypeProgress  = "progress"
+	EventTypeStage     = "stage"
+	EventTypeSegment   = "segment"
+	EventTypeResult    = "result"
+)
+
+// EventRecord is an event of an operation kept in the event store, written as one line of JSON
+type EventRecord struct {
+	Time        time.Time `json:"time"`
+	OperationID string    `json:"operationId"`
+	Method      string    `json:"method"`
+	Type        string    `json:"type"`
+	Caller      string    `json:"caller,omitempty"`
+	State       string    `json:"state,omitempty"`
+	Level       string    `json:"level,omitempty"`
+	Stage       string    `json:"stage,omitempty"`
+	Host        string    `json:"host,omitempty"`
+	Segment     string    `json:"segment,omitempty"`
+	Dbid        int       `json:"dbid,omitempty"`
+	Message     string    `json:"message,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Current     int64     `json:"current,omitempty"`
+	Total       int64     `json:"total,omitempty"`
+	ClaimedBy   string    `json:"claimedBy,omitempty"`
+}
+
+func (r *EventRecord) toIdl() *idl.StoredEvent {
+	return &idl.StoredEvent{
+		Timestamp:   r.Time.UnixMilli(),
+		OperationId: r.OperationID,
+		Method:      r.Method,
+		Type:        r.Type,
+		Caller:      r.Caller,
+		State:       r.State,
+		Level:       r.Level,
+		Stage:       r.Stage,
+		Host:        r.Host,
+		Segment:     r.Segment,
+		Dbid:        int32(r.Dbid),
+		Message:     r.Message,
+		Error:       r.Error,
+		Current:     r.Current,
+		Total:       r.Total,
+		ClaimedBy:   r.ClaimedBy,
+	}
+}
+
+/*
+newEventRecord returns the record of a reply of an operation, or nil for the
+replies which are not worth keeping, like the ID of the operation which every
+record already carries.
+*/
+func newEventRecord(reply *idl.HubReply) *EventRecord {
+	record := &EventRecord{Time: time.Now().UTC()}
+
+	switch msg := reply.Message.(type) {
+	case *idl.HubReply_LogMsg:
+		record.Type = EventTypeLog
+		record.Level = strings.ToLower(msg.LogMsg.Level.String())
+		record.Message = msg.LogMsg.Message
+
+	case *idl.HubReply_StdoutMsg:
+		record.Type = EventTypeStdout
+		record.Message = strings.TrimRight(msg.StdoutMsg, "\n")
+
+	case *idl.HubReply_OutputMsg:
+		record.Type = EventTypeOutput
+		record.Time = time.UnixMilli(msg.OutputMsg.Timestamp).UTC()
+		record.State = strings.ToLower(msg.OutputMsg.Stream.String())
+		record.Host = msg.OutputMsg.Host
+		record.Segment = msg.OutputMsg.Segment
+		record.Dbid = int(msg.OutputMsg.Dbid)
+		record.Message = msg.OutputMsg.Line
+
+	case *idl.HubReply_ProgressMsg:
+		record.Type = EventTypeProgress
+		record.Stage = msg.ProgressMsg.Stage
+		record.Message = msg.ProgressMsg.Label
+		record.Current = int64(msg.ProgressMsg.Current)
+		record.Total = int64(msg.ProgressMsg.Total)
+
+	case *idl.HubReply_TransferMsg:
+		record.Type = EventTypeProgress
+		record.Message = msg.TransferMsg.Label
+		record.Current = msg.TransferMsg.Current
+		record.Total = msg.TransferMsg.Total
+
+	case *idl.HubReply_StageMsg:
+		record.Type = EventTypeStage
+		record.Time = time.UnixMilli(msg.StageMsg.Timestamp).UTC()
+		record.Stage = msg.StageMsg.Id
+		record.State = strings.ToLower(strings.TrimPrefix(msg.StageMsg.State.String(), "STAGE_"))
+		record.Message = msg.StageMsg.Name
+		record.Error = msg.StageMsg.Error
+
+	case *idl.HubReply_SegmentMsg:
+		record.Type = EventTypeSegment
+		record.Time = time.UnixMilli(msg.SegmentMsg.Timestamp).UTC()
+		record.State = strings.ToLower(strings.TrimPrefix(msg.SegmentMsg.State.String(), "SEGMENT_"))
+		record.Host = msg.SegmentMsg.Host
+		record.Segment = msg.SegmentMsg.DataDirectory
+		record.Dbid = int(msg.SegmentMsg.Dbid)
+		record.Error = msg.SegmentMsg.Error
+
+	case *idl.HubReply_ResultMsg:
+		record.Type = EventTypeResult
+		record.State = "success"
+		if !msg.ResultMsg.Success {
+			record.State = "failure"
+		}
+		record.Error = msg.ResultMsg.Error
+
+	default:
+		return nil
+	}
+
+	return record
+}
+
+// EventFilter selects the events returned by EventStore.Query, an empty field matching all of them
+type EventFilter struct {
+	Since       time.Time
+	OperationID string
+	Host        string
+
+	// only return the operation events of the operations with an event matching the filters
+	OperationsOnly bool
+}
+
+const (
+	DefaultEventsMaxSizeMB = 64
+	DefaultEventsMaxFiles  = 4
+
+	// DefaultEventsPageSize is the number of events returned by ListEvents at most, unless the request
+	// asks for less; the replies are also cut at maxEventsPageBytes to stay below the gRPC message limit
+	DefaultEventsPageSize = 1000
+	maxEventsPageBytes    = 1024 * 1024
+)
+
+/*
+EventStore keeps the events of all the operations of the hub in a file, so that
+they can be reviewed once the operations completed, even after the hub was
+restarted. The file is appended to and created on the first event. Once it
+reaches MaxSize, it is rotated to path.1, the older files being shifted up to
+path.MaxFiles and the oldest dropped.
+*/
+type EventStore struct {
+	path string
+
+	MaxSize  int64 // never rotated if 0
+	MaxFiles int   // number of rotated files kept
+
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+func NewEventStore(path string) *EventStore {
+	return &EventStore{
+		path:     path,
+		MaxSize:  DefaultEventsMaxSizeMB * 1024 * 1024,
+		MaxFiles: DefaultEventsMaxFiles,
+	}
+}
+
+/*
+Record appends the event to the store. If the store can not be written, the
+event is written to the hub log instead so that it is not lost. A nil store
+drops the events.
+*/
+func (s *EventStore) Record(record *EventRecord) {
+	if s == nil {
+		return
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		gplog.Error("failed to encode the event of operation %s: %v", record.OperationID, err)
+		return
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		s.file, err = utils.System.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			gplog.Error("failed to open the event store %s: %v, event: %s", s.path, err, line)
+			return
+		}
+
+		s.size = 0
+		if info, err := s.file.Stat(); err == nil {
+			s.size = info.Size()
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		gplog.Error("failed to write to the event store %s: %v, event: %s", s.path, err, line)
+		return
+	}
+
+	if s.MaxSize > 0 && s.size >= s.MaxSize {
+		s.rotate()
+	}
+}
+
+// rotate moves the file to the first rotated file, shifting the others; called with the mutex held
+func (s *EventStore) rotate() {
+	err := s.file.Close()
+	s.file = nil
+	if err != nil {
+		gplog.Warn("failed to close the event store %s before rotating it: %v", s.path, err)
+	}
+
+	if s.MaxFiles < 1 {
+		err = utils.System.RemoveAll(s.path)
+		if err != nil {
+			gplog.Error("failed to drop the events of the event store %s: %v", s.path, err)
+		}
+		return
+	}
+
+	for i := s.MaxFiles - 1; i >= 1; i-- {
+		err = utils.System.Rename(s.rotatedPath(i), s.rotatedPath(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			gplog.Warn("failed to rotate the event store %s: %v", s.rotatedPath(i), err)
+		}
+	}
+
+	err = utils.System.Rename(s.path, s.rotatedPath(1))
+	if err != nil {
+		gplog.Error("failed to rotate the event store %s: %v", s.path, err)
+	}
+}
+
+func (s *EventStore) rotatedPath(i int) string {
+	return fmt.Sprintf("%s.%d", s.path, i)
+}
+
+/*
+openFiles opens the rotated files, oldest first, and the current file of the
+store. They are opened under the mutex so that they are not rotated meanwhile,
+and are then read through their own handles without blocking Record.
+*/
+func (s *EventStore) openFiles() ([]*os.File, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var paths []string
+	for i := s.MaxFiles; i >= 1; i-- {
+		paths = append(paths, s.rotatedPath(i))
+	}
+	paths = append(paths, s.path)
+
+	var files []*os.File
+	for _, path := range paths {
+		file, err := utils.System.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			for _, file := range files {
+				file.Close()
+			}
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// Query returns the events matching the filter in the order they were recorded
+func (s *EventStore) Query(filter EventFilter) ([]*EventRecord, error) {
+	files, err := s.openFiles()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	var records []*EventRecord
+	hostOperations := make(map[string]bool)
+	for _, file := range files {
+		reader := bufio.NewReader(file)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if errors.Is(err, io.EOF) {
+				// a line without its newline is still being written
+				break
+			} else if err != nil {
+				return nil, err
+			}
+
+			record := &EventRecord{}
+			err = json.Unmarshal(line, record)
+			if err != nil {
+				gplog.Warn("ignoring the invalid event %q in the event store %s: %v", strings.TrimSuffix(string(line), "\n"), file.Name(), err)
+				continue
+			}
+
+			if record.Time.Before(filter.Since) || (filter.OperationID != "" && record.OperationID != filter.OperationID) {
+				continue
+			}
+
+			if filter.Host != "" && record.Host == filter.Host {
+				hostOperations[record.OperationID] = true
+			}
+
+			records = append(records, record)
+		}
+	}
+
+	var matching []*EventRecord
+	for _, record := range records {
+		if filter.OperationsOnly {
+			if record.Type == EventTypeOperation && (filter.Host == "" || hostOperations[record.OperationID]) {
+				matching = append(matching, record)
+			}
+		} else if filter.Host == "" || record.Host == filter.Host {
+			matching = append(matching, record)
+		}
+	}
+
+	return matching, nil
+}
+
+func (s *EventStore) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
+
+/*
+ListEvents returns a page of the events matching the filters of the request.
+The token of the next page is the number of matching events already returned,
+so a rotation of the store while paging may skip some of the oldest events.
+*/
+func (s *Server) ListEvents(ctx context.Context, request *idl.ListEventsRequest) (*idl.ListEventsReply, error) {
+	filter := EventFilter{
+		OperationID:    request.OperationId,
+		Host:           request.Host,
+		OperationsOnly: request.OperationsOnly,
+	}
+	if request.Since != 0 {
+		filter.Since = time.UnixMilli(request.Since)
+	}
+
+	offset := 0
+	if request.PageToken != "" {
+		var err error
+		offset, err = strconv.Atoi(request.PageToken)
+		if err != nil || offset < 0 {
+			return nil, utils.LogAndReturnError(fmt.Errorf("invalid page token %q", request.PageToken))
+		}
+	}
+
+	pageSize := DefaultEventsPageSize
+	if request.PageSize > 0 && int(request.PageSize) < pageSize {
+		pageSize = int(request.PageSize)
+	}
+
+	records, err := s.events.Query(filter)
+	if err != nil {
+		return nil, utils.LogAndReturnError(err)
+	}
+
+	var events []*idl.StoredEvent
+	size := 0
+	next := offset
+	for ; next < len(records) && len(events) < pageSize; next++ {
+		// sized on a copy, as computing the size caches it in the message
+		size += proto.Size(records[next].toIdl())
+		if len(events) > 0 && size > maxEventsPageBytes {
+			break
+		}
+		events = append(events, records[next].toIdl())
+	}
+
+	reply := &idl.ListEventsReply{Events: events}
+	if next < len(records) {
+		reply.NextPageToken = strconv.Itoa(next)
+	}
+
+	return reply, nil
+}
@@ -0,0 +1,318 @@
+package hub_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+)
+
+func TestEventStore(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	startTime := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	records := []*hub.EventRecord{
+		{Time: startTime, OperationID: "1234", Method: "MakeCluster", Type: hub.EventTypeOperation, Caller: "gpadmin", State: "running"},
+		{Time: startTime.Add(time.Second), OperationID: "1234", Method: "MakeCluster", Type: hub.EventTypeSegment, State: "created", Host: "sdw1", Segment: "/data/primary/gpseg0", Dbid: 2},
+		{Time: startTime.Add(time.Minute), OperationID: "1234", Method: "MakeCluster", Type: hub.EventTypeOperation, Caller: "gpadmin", State: "succeeded"},
+		{Time: startTime.Add(time.Hour), OperationID: "5678", Method: "AddMirrors", Type: hub.EventTypeOperation, Caller: "gpadmin", State: "running"},
+		{Time: startTime.Add(time.Hour + time.Second), OperationID: "5678", Method: "AddMirrors", Type: hub.EventTypeSegment, State: "failed", Host: "sdw2", Segment: "/data/mirror/gpseg0", Dbid: 3, Error: "error"},
+	}
+
+	newStore := func(t *testing.T) *hub.EventStore {
+		store := hub.NewEventStore(filepath.Join(t.TempDir(), "hub_events.log"))
+		for _, record := range records {
+			store.Record(record)
+		}
+		t.Cleanup(func() { store.Close() })
+
+		return store
+	}
+
+	cases := []struct {
+		name     string
+		filter   hub.EventFilter
+		expected []*hub.EventRecord
+	}{
+		{
+			name:     "returns all the events in the order they were recorded",
+			expected: records,
+		},
+		{
+			name:     "returns the events since the given time",
+			filter:   hub.EventFilter{Since: startTime.Add(time.Minute)},
+			expected: records[2:],
+		},
+		{
+			name:     "returns the events of the given operation",
+			filter:   hub.EventFilter{OperationID: "5678"},
+			expected: records[3:],
+		},
+		{
+			name:     "returns the events about the given host",
+			filter:   hub.EventFilter{Host: "sdw1"},
+			expected: records[1:2],
+		},
+		{
+			name:     "returns the operation events only",
+			filter:   hub.EventFilter{OperationsOnly: true},
+			expected: []*hub.EventRecord{records[0], records[2], records[3]},
+		},
+		{
+			name:     "returns the operation events of the operations about the given host",
+			filter:   hub.EventFilter{Host: "sdw2", OperationsOnly: true},
+			expected: records[3:4],
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := newStore(t).Query(tc.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("got %+v, want %+v", result, tc.expected)
+			}
+		})
+	}
+
+	t.Run("returns no events when none were recorded", func(t *testing.T) {
+		store := hub.NewEventStore(filepath.Join(t.TempDir(), "hub_events.log"))
+
+		result, err := store.Query(hub.EventFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result) != 0 {
+			t.Fatalf("got %+v, want no events", result)
+		}
+	})
+
+	t.Run("skips the invalid events", func(t *testing.T) {
+		_, _, logfile := testhelper.SetupTestLogger()
+		path := filepath.Join(t.TempDir(), "hub_events.log")
+		err := os.WriteFile(path, []byte("invalid\n"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		store := hub.NewEventStore(path)
+		defer store.Close()
+		store.Record(records[0])
+
+		result, err := store.Query(hub.EventFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, records[:1]) {
+			t.Fatalf("got %+v, want %+v", result, records[:1])
+		}
+
+		testutils.AssertLogMessage(t, logfile, `\[WARNING\]:-ignoring the invalid event "invalid"`)
+	})
+
+	t.Run("creates the store readable by its owner only", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hub_events.log")
+		store := hub.NewEventStore(path)
+		defer store.Close()
+		store.Record(records[0])
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("got mode %v, want %v", info.Mode().Perm(), os.FileMode(0600))
+		}
+	})
+
+	t.Run("records the events of the operations with their caller", func(t *testing.T) {
+		store := hub.NewEventStore(filepath.Join(t.TempDir(), "hub_events.log"))
+		defer store.Close()
+		registry := hub.NewOperationRegistry(store)
+
+		// the caller is identified by its certificate, whatever user@host it claims to be
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: "cdw"}}
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+		})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(hub.CallerMetadataKey, "gpadmin@sdw1"))
+		op := registry.Start(ctx, "MakeCluster", func(ctx context.Context, stream *hub.HubStream) error {
+			stream.StreamLogMsg("creating the cluster")
+			return nil
+		})
+		err := op.Attach(newAttachedStream(context.Background()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := store.Query(hub.EventFilter{OperationID: op.ID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var types, states []string
+		for _, record := range result {
+			if record.Method != "MakeCluster" {
+				t.Fatalf("got method %q, want MakeCluster", record.Method)
+			}
+			types = append(types, record.Type)
+			states = append(states, record.State)
+		}
+
+		expectedTypes := []string{hub.EventTypeOperation, hub.EventTypeLog, hub.EventTypeResult, hub.EventTypeOperation}
+		expectedStates := []string{"running", "", "success", "succeeded"}
+		if !reflect.DeepEqual(types, expectedTypes) || !reflect.DeepEqual(states, expectedStates) {
+			t.Fatalf("got types %v and states %v, want %v and %v", types, states, expectedTypes, expectedStates)
+		}
+		if result[0].Caller != "cdw" || result[0].ClaimedBy != "gpadmin@sdw1" || result[1].Message != "creating the cluster" {
+			t.Fatalf("unexpected events %+v", result)
+		}
+	})
+
+	t.Run("lists the events through the RPC", func(t *testing.T) {
+		hubServer := hub.New(&hub.Config{LogDir: t.TempDir()}, nil)
+		store := hub.NewEventStore(filepath.Join(hubServer.LogDir, "hub_events.log"))
+		for _, record := range records {
+			store.Record(record)
+		}
+		store.Close()
+
+		reply, err := hubServer.ListEvents(context.Background(), &idl.ListEventsRequest{
+			Since:          startTime.Add(time.Minute).UnixMilli(),
+			OperationsOnly: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []*idl.StoredEvent{
+			{Timestamp: records[2].Time.UnixMilli(), OperationId: "1234", Method: "MakeCluster", Type: hub.EventTypeOperation, Caller: "gpadmin", State: "succeeded"},
+			{Timestamp: records[3].Time.UnixMilli(), OperationId: "5678", Method: "AddMirrors", Type: hub.EventTypeOperation, Caller: "gpadmin", State: "running"},
+		}
+		if !reflect.DeepEqual(reply.Events, expected) {
+			t.Fatalf("got %+v, want %+v", reply.Events, expected)
+		}
+	})
+
+	t.Run("pages the events listed through the RPC", func(t *testing.T) {
+		hubServer := hub.New(&hub.Config{LogDir: t.TempDir()}, nil)
+		store := hub.NewEventStore(filepath.Join(hubServer.LogDir, "hub_events.log"))
+		for _, record := range records {
+			store.Record(record)
+		}
+		store.Close()
+
+		var operations []string
+		var tokens []string
+		request := &idl.ListEventsRequest{PageSize: 2}
+		for {
+			reply, err := hubServer.ListEvents(context.Background(), request)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(reply.Events) > 2 {
+				t.Fatalf("got %d events, want at most 2", len(reply.Events))
+			}
+
+			for _, event := range reply.Events {
+				operations = append(operations, event.OperationId)
+			}
+			tokens = append(tokens, reply.NextPageToken)
+			if reply.NextPageToken == "" {
+				break
+			}
+			request.PageToken = reply.NextPageToken
+		}
+
+		expectedOperations := []string{"1234", "1234", "1234", "5678", "5678"}
+		if !reflect.DeepEqual(operations, expectedOperations) {
+			t.Fatalf("got %v, want %v", operations, expectedOperations)
+		}
+		if !reflect.DeepEqual(tokens, []string{"2", "4", ""}) {
+			t.Fatalf("got page tokens %q, want %q", tokens, []string{"2", "4", ""})
+		}
+	})
+
+	t.Run("errors out on an invalid page token", func(t *testing.T) {
+		hubServer := hub.New(&hub.Config{LogDir: t.TempDir()}, nil)
+
+		_, err := hubServer.ListEvents(context.Background(), &idl.ListEventsRequest{PageToken: "next"})
+		expected := `invalid page token "next"`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("rotates the store once it reaches its maximum size", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hub_events.log")
+		store := hub.NewEventStore(path)
+		store.MaxSize = 1
+		store.MaxFiles = 3
+		defer store.Close()
+
+		for _, record := range records {
+			store.Record(record)
+		}
+
+		for _, rotated := range []string{path + ".1", path + ".2", path + ".3"} {
+			if _, err := os.Stat(rotated); err != nil {
+				t.Fatalf("expected the rotated file %s: %v", rotated, err)
+			}
+		}
+		for _, dropped := range []string{path, path + ".4"} {
+			if _, err := os.Stat(dropped); !os.IsNotExist(err) {
+				t.Fatalf("expected %s not to exist, got %v", dropped, err)
+			}
+		}
+
+		result, err := store.Query(hub.EventFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, records[2:]) {
+			t.Fatalf("got %+v, want the events of the files kept %+v", result, records[2:])
+		}
+	})
+
+	t.Run("ignores the event still being written", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hub_events.log")
+		store := hub.NewEventStore(path)
+		defer store.Close()
+		store.Record(records[0])
+
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = file.WriteString(`{"time":`)
+		file.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		result, err := store.Query(hub.EventFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(result, records[:1]) {
+			t.Fatalf("got %+v, want %+v", result, records[:1])
+		}
+	})
+}
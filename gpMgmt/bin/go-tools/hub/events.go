@@ -0,0 +1,200 @@
+package hub
+
+import (
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+// IDs of the stages of the operations, which stay the same across releases so that clients can rely on them
+const (
+	StageValidateHosts     = "validate-hosts"
+	StageCreateCoordinator = "create-coordinator"
+	StageRegisterPrimaries = "register-primaries"
+	StageCreatePrimaries   = "create-primaries"
+	StageStartCluster      = "start-cluster"
+	StageConfigureDatabase = "configure-database"
+	StageRegisterMirrors   = "register-mirrors"
+	StageUpdatePgHba       = "update-pg-hba"
+	StageCreateMirrors     = "create-mirrors"
+	StageStartMirrors      = "start-mirrors"
+	StageFtsProbe          = "fts-probe"
+	StageRestoreTopology   = "restore-topology"
+)
+
+/*
+startStage streams that the stage of the operation started. The returned
+function streams that the stage finished, or failed if given an error, and
+returns that error.
+*/
+func startStage(stream hubStreamer, id string, name string) func(err error) error {
+	stream.StreamStageMsg(id, name, idl.StageState_STAGE_STARTED, nil)
+
+	return func(err error) error {
+		if err != nil {
+			stream.StreamStageMsg(id, name, idl.StageState_STAGE_FAILED, err)
+		} else {
+			stream.StreamStageMsg(id, name, idl.StageState_STAGE_FINISHED, nil)
+		}
+
+		return err
+	}
+}
+
+// progress counts the steps of a stage done so far, like the segments created, and streams them to the CLI
+type progress struct {
+	mutex   sync.Mutex
+	stream  hubStreamer
+	stage   string
+	label   string
+	current int
+	total   int
+}
+
+func startProgress(stream hubStreamer, stage string, label string, total int) *progress {
+	stream.StreamProgressMsg(stage, label, 0, total)
+
+	return &progress{
+		stream: stream,
+		stage:  stage,
+		label:  label,
+		total:  total,
+	}
+}
+
+func (p *progress) increment() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.current++
+	p.stream.StreamProgressMsg(p.stage, p.label, p.current, p.total)
+}
+
+// eventRecorder keeps the last event of each stage and segment of an RPC, to summarise its result once it completes
+type eventRecorder struct {
+	mutex    sync.Mutex
+	stages   []*idl.StageEvent
+	segments []*idl.SegmentEvent
+}
+
+func (r *eventRecorder) recordStage(event *idl.StageEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, stage := range r.stages {
+		if stage.Id == event.Id {
+			r.stages[i] = event
+			return
+		}
+	}
+	r.stages = append(r.stages, event)
+}
+
+func (r *eventRecorder) recordSegment(event *idl.SegmentEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, segment := range r.segments {
+		if segment.Host == event.Host && segment.DataDirectory == event.DataDirectory {
+			r.segments[i] = event
+			return
+		}
+	}
+	r.segments = append(r.segments, event)
+}
+
+func (r *eventRecorder) summary() ([]*idl.StageEvent, []*idl.SegmentEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]*idl.StageEvent(nil), r.stages...), append([]*idl.SegmentEvent(nil), r.segments...)
+}
+
+/*
+StreamStageMsg streams from hub to the CLI that a stage of the operation
+started, finished or failed with the given error. A stage may be started
+again, e.g. when retried, in which case its progress starts over.
+*/
+func (h *HubStream) StreamStageMsg(id string, name string, state idl.StageState, err error) {
+	event := &idl.StageEvent{
+		Id:        id,
+		Name:      name,
+		State:     state,
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	h.events.recordStage(event)
+
+	message := &idl.HubReply{
+		Message: &idl.HubReply_StageMsg{
+			StageMsg: event,
+		},
+	}
+
+	err = h.handler.Send(message)
+	if err != nil {
+		gplog.Error("unable to stream message %q: %s", message, err)
+	}
+}
+
+// StreamSegmentMsg streams from hub to the CLI that the segment changed state, or failed with the given error
+func (h *HubStream) StreamSegmentMsg(seg *idl.Segment, state idl.SegmentState, err error) {
+	event := &idl.SegmentEvent{
+		Dbid:          seg.Dbid,
+		Contentid:     seg.Contentid,
+		Host:          seg.HostName,
+		DataDirectory: seg.DataDirectory,
+		State:         state,
+		Timestamp:     time.Now().UnixMilli(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	h.events.recordSegment(event)
+
+	message := &idl.HubReply{
+		Message: &idl.HubReply_SegmentMsg{
+			SegmentMsg: event,
+		},
+	}
+
+	err = h.handler.Send(message)
+	if err != nil {
+		gplog.Error("unable to stream message %q: %s", message, err)
+	}
+}
+
+/*
+StreamResultMsg streams the summary of the RPC from hub to the CLI once it
+completes with the given error, if any: the final state of its stages and
+segments and the failed requests to the agents.
+*/
+func (h *HubStream) StreamResultMsg(err error, duration time.Duration) {
+	stages, segments := h.events.summary()
+	summary := &idl.ResultSummary{
+		Success:        err == nil,
+		DurationMillis: duration.Milliseconds(),
+		Stages:         stages,
+		Segments:       segments,
+		Failures:       utils.HostErrorsOf(err),
+	}
+	if err != nil {
+		summary.Error = err.Error()
+	}
+
+	message := &idl.HubReply{
+		Message: &idl.HubReply_ResultMsg{
+			ResultMsg: summary,
+		},
+	}
+
+	err = h.handler.Send(message)
+	if err != nil {
+		gplog.Error("unable to stream message %q: %s", message, err)
+	}
+}
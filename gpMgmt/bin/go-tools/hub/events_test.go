@@ -0,0 +1,154 @@
+package hub_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+func TestStreamEvents(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("streams the stage events", func(t *testing.T) {
+		stream, res := testutils.NewMockStream()
+
+		stream.StreamStageMsg(hub.StageCreatePrimaries, "Creating the primary segments", idl.StageState_STAGE_STARTED, nil)
+		stream.StreamStageMsg(hub.StageCreatePrimaries, "Creating the primary segments", idl.StageState_STAGE_FAILED, errors.New("error"))
+
+		var events []*idl.StageEvent
+		for _, reply := range res.GetBuffer() {
+			event := reply.GetStageMsg()
+			if event.Timestamp == 0 {
+				t.Fatalf("expected the stage event %v to have a timestamp", event)
+			}
+			event.Timestamp = 0
+			events = append(events, event)
+		}
+
+		expected := []*idl.StageEvent{
+			{Id: hub.StageCreatePrimaries, Name: "Creating the primary segments", State: idl.StageState_STAGE_STARTED},
+			{Id: hub.StageCreatePrimaries, Name: "Creating the primary segments", State: idl.StageState_STAGE_FAILED, Error: "error"},
+		}
+		if !reflect.DeepEqual(events, expected) {
+			t.Fatalf("got %+v, want %+v", events, expected)
+		}
+	})
+
+	t.Run("streams the segment events", func(t *testing.T) {
+		stream, res := testutils.NewMockStream()
+
+		seg := &idl.Segment{Dbid: 2, Contentid: 0, HostName: "sdw1", DataDirectory: "/data/primary/gpseg0", Port: 7001}
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_CREATING, nil)
+
+		buffer := res.GetBuffer()
+		if len(buffer) != 1 {
+			t.Fatalf("got %d messages, want 1", len(buffer))
+		}
+
+		event := buffer[0].GetSegmentMsg()
+		event.Timestamp = 0
+		expected := &idl.SegmentEvent{Dbid: 2, Contentid: 0, Host: "sdw1", DataDirectory: "/data/primary/gpseg0", State: idl.SegmentState_SEGMENT_CREATING}
+		if !reflect.DeepEqual(event, expected) {
+			t.Fatalf("got %+v, want %+v", event, expected)
+		}
+	})
+
+	t.Run("summarises the last state of the stages and segments", func(t *testing.T) {
+		stream, res := testutils.NewMockStream()
+
+		primary := &idl.Segment{Dbid: 2, HostName: "sdw1", DataDirectory: "/data/primary/gpseg0"}
+		mirror := &idl.Segment{Dbid: 3, HostName: "sdw2", DataDirectory: "/data/mirror/gpseg0"}
+		stream.StreamStageMsg(hub.StageCreatePrimaries, "Creating the primary segments", idl.StageState_STAGE_STARTED, nil)
+		stream.StreamSegmentMsg(primary, idl.SegmentState_SEGMENT_CREATING, nil)
+		stream.StreamSegmentMsg(primary, idl.SegmentState_SEGMENT_CREATED, nil)
+		stream.StreamStageMsg(hub.StageCreatePrimaries, "Creating the primary segments", idl.StageState_STAGE_FINISHED, nil)
+		stream.StreamStageMsg(hub.StageCreateMirrors, "Creating the mirror segments", idl.StageState_STAGE_STARTED, nil)
+		stream.StreamSegmentMsg(mirror, idl.SegmentState_SEGMENT_CREATING, nil)
+
+		hostErr := &utils.HostError{Host: "sdw2", Segment: mirror.DataDirectory, RPC: "PgBasebackup", Err: errors.New("error")}
+		err := utils.HostErrors{hostErr}
+		stream.StreamSegmentMsg(mirror, idl.SegmentState_SEGMENT_FAILED, hostErr)
+		stream.StreamStageMsg(hub.StageCreateMirrors, "Creating the mirror segments", idl.StageState_STAGE_FAILED, err)
+		stream.StreamResultMsg(err, 2*time.Second)
+
+		buffer := res.GetBuffer()
+		summary := buffer[len(buffer)-1].GetResultMsg()
+		if summary == nil {
+			t.Fatalf("got %+v, want the summary to be sent last", buffer[len(buffer)-1])
+		}
+
+		if summary.Success || summary.Error != err.Error() || summary.DurationMillis != 2000 {
+			t.Fatalf("got %+v, want a failure with error %q taking 2000ms", summary, err)
+		}
+
+		var stages []idl.StageState
+		for _, stage := range summary.Stages {
+			stages = append(stages, stage.State)
+		}
+		expectedStages := []idl.StageState{idl.StageState_STAGE_FINISHED, idl.StageState_STAGE_FAILED}
+		if !reflect.DeepEqual(stages, expectedStages) {
+			t.Fatalf("got %v, want %v", stages, expectedStages)
+		}
+
+		var segments []idl.SegmentState
+		for _, segment := range summary.Segments {
+			segments = append(segments, segment.State)
+		}
+		expectedSegments := []idl.SegmentState{idl.SegmentState_SEGMENT_CREATED, idl.SegmentState_SEGMENT_FAILED}
+		if !reflect.DeepEqual(segments, expectedSegments) {
+			t.Fatalf("got %v, want %v", segments, expectedSegments)
+		}
+
+		if len(summary.Failures) != 1 || summary.Failures[0].Host != "sdw2" || summary.Failures[0].Segment != mirror.DataDirectory {
+			t.Fatalf("got %+v, want the failure of the mirror", summary.Failures)
+		}
+	})
+
+	t.Run("summarises a successful RPC", func(t *testing.T) {
+		stream, res := testutils.NewMockStream()
+
+		stream.StreamResultMsg(nil, time.Second)
+
+		expected := []*idl.HubReply{
+			{
+				Message: &idl.HubReply_ResultMsg{
+					ResultMsg: &idl.ResultSummary{Success: true, DurationMillis: 1000},
+				},
+			},
+		}
+		if !reflect.DeepEqual(res.GetBuffer(), expected) {
+			t.Fatalf("got %+v, want %+v", res.GetBuffer(), expected)
+		}
+	})
+}
+
+// progressMessages returns the progress messages among the replies
+func progressMessages(replies []*idl.HubReply) []*idl.ProgressMessage {
+	var messages []*idl.ProgressMessage
+	for _, reply := range replies {
+		if msg := reply.GetProgressMsg(); msg != nil {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages
+}
+
+// segmentStates returns the states the segments went through among the replies, by data directory
+func segmentStates(replies []*idl.HubReply) map[string][]idl.SegmentState {
+	states := make(map[string][]idl.SegmentState)
+	for _, reply := range replies {
+		if msg := reply.GetSegmentMsg(); msg != nil {
+			states[msg.DataDirectory] = append(states[msg.DataDirectory], msg.State)
+		}
+	}
+
+	return states
+}
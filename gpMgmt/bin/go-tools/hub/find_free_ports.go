@@ -0,0 +1,102 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+/*
+FindFreePorts returns the lowest base port of a range of consecutive ports free
+on all the hosts of the request, as reported by their agents, which does not
+contain any of the excluded ports. It is used to allocate the base ports of the
+segments set to auto in the init config.
+*/
+func (s *Server) FindFreePorts(ctx context.Context, req *idl.FindFreePortsRequest) (*idl.FindFreePortsReply, error) {
+	gplog.Debug("Starting with rpc FindFreePorts")
+	if req.Count < 1 {
+		return &idl.FindFreePortsReply{}, utils.LogAndReturnError(fmt.Errorf("invalid number of ports %d", req.Count))
+	}
+	if len(req.HostList) == 0 {
+		return &idl.FindFreePortsReply{}, utils.LogAndReturnError(fmt.Errorf("no hosts to find the free ports of"))
+	}
+
+	// the hosts of the init config may not be hosts of the hub yet, so they are dialed for the request
+	conns, err := s.dialHostList(req.HostList)
+	if err != nil {
+		return &idl.FindFreePortsReply{}, utils.LogAndReturnError(err)
+	}
+	defer closeConnections(conns)
+
+	var mutex sync.Mutex
+	var hostRanges [][]*idl.PortRange
+	request := func(ctx context.Context, conn *Connection) error {
+		reply, err := conn.AgentClient.GetFreePortRanges(ctx, &idl.GetFreePortRangesRequest{MinPort: req.MinPort, MaxPort: req.MaxPort})
+		if err != nil {
+			gplog.Error("getting the free ports of %s failed with error:%v", conn.Hostname, err)
+			return utils.NewHostError("GetFreePortRanges", "", utils.FormatGrpcError(err))
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		hostRanges = append(hostRanges, reply.Ranges)
+
+		return nil
+	}
+
+	err = ExecuteRPC(ctx, conns, request)
+	if err != nil {
+		return &idl.FindFreePortsReply{}, err
+	}
+
+	basePort, ok := LowestFreePortRange(hostRanges, req.MinPort, req.MaxPort, req.Count, req.ExcludedPorts)
+	if !ok {
+		return &idl.FindFreePortsReply{}, utils.LogAndReturnError(fmt.Errorf("no range of %d free ports found between %d and %d on all the hosts", req.Count, req.MinPort, req.MaxPort))
+	}
+
+	return &idl.FindFreePortsReply{BasePort: basePort}, nil
+}
+
+/*
+LowestFreePortRange returns the lowest base port between the min and max port
+of count consecutive ports which are within the free ranges of every host and
+are not excluded, or false when there is none.
+*/
+func LowestFreePortRange(hostRanges [][]*idl.PortRange, minPort int32, maxPort int32, count int32, excludedPorts []int32) (int32, bool) {
+	if minPort > maxPort {
+		return 0, false
+	}
+
+	freeOnHosts := make([]int, maxPort-minPort+1)
+	for _, ranges := range hostRanges {
+		for _, r := range ranges {
+			for port := max(r.Start, minPort); port <= min(r.End, maxPort); port++ {
+				freeOnHosts[port-minPort]++
+			}
+		}
+	}
+	for _, port := range excludedPorts {
+		if port >= minPort && port <= maxPort {
+			freeOnHosts[port-minPort] = -1
+		}
+	}
+
+	var length int32
+	for port := minPort; port <= maxPort; port++ {
+		if freeOnHosts[port-minPort] != len(hostRanges) {
+			length = 0
+			continue
+		}
+
+		length++
+		if length == count {
+			return port - count + 1, true
+		}
+	}
+
+	return 0, false
+}
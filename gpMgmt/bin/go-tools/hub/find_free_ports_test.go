@@ -0,0 +1,153 @@
+package hub_test
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// freePortsAgent reports the same free port ranges for every request
+type freePortsAgent struct {
+	idl.UnimplementedAgentServer
+	ranges []*idl.PortRange
+}
+
+func (a *freePortsAgent) GetFreePortRanges(ctx context.Context, request *idl.GetFreePortRangesRequest) (*idl.GetFreePortRangesReply, error) {
+	return &idl.GetFreePortRangesReply{Ranges: a.ranges}, nil
+}
+
+// trackedConn is done once the hub closed its connection to the agent
+type trackedConn struct {
+	net.Conn
+
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		close(c.closed)
+	})
+
+	return c.Conn.Close()
+}
+
+func TestFindFreePorts(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("errors out on an invalid number of ports", func(t *testing.T) {
+		hubServer := hub.New(&hub.Config{LogDir: t.TempDir()}, nil)
+
+		_, err := hubServer.FindFreePorts(context.Background(), &idl.FindFreePortsRequest{HostList: []string{"sdw1"}, MinPort: 7000, MaxPort: 8000})
+		expected := "invalid number of ports 0"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("finds the ports free on all the hosts and closes the connections to their agents", func(t *testing.T) {
+		agents := map[string]*freePortsAgent{
+			"sdw1": {ranges: []*idl.PortRange{{Start: 7000, End: 7010}}},
+			"sdw2": {ranges: []*idl.PortRange{{Start: 7005, End: 7020}}},
+		}
+
+		listeners := make(map[string]*bufconn.Listener)
+		for host, agent := range agents {
+			listener := bufconn.Listen(1024 * 1024)
+			agentServer := grpc.NewServer()
+			t.Cleanup(agentServer.Stop)
+
+			idl.RegisterAgentServer(agentServer, agent)
+			go func() {
+				if err := agentServer.Serve(listener); err != nil {
+					log.Fatalf("server exited with error: %v", err)
+				}
+			}()
+			listeners[host] = listener
+		}
+
+		var mutex sync.Mutex
+		var conns []*trackedConn
+		hubServer := hub.New(&hub.Config{
+			AgentPort:   5678,
+			LogDir:      t.TempDir(),
+			Credentials: &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()},
+		}, func(ctx context.Context, address string) (net.Conn, error) {
+			host, _, _ := net.SplitHostPort(address)
+			conn, err := listeners[host].DialContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			tracked := &trackedConn{Conn: conn, closed: make(chan struct{})}
+			conns = append(conns, tracked)
+
+			return tracked, nil
+		})
+
+		reply, err := hubServer.FindFreePorts(context.Background(), &idl.FindFreePortsRequest{HostList: []string{"sdw1", "sdw2", "sdw1"}, Count: 3, MinPort: 7000, MaxPort: 8000})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if reply.BasePort != 7005 {
+			t.Fatalf("got %d, want 7005", reply.BasePort)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if len(conns) != 2 {
+			t.Fatalf("got %d connections, want 2", len(conns))
+		}
+		for _, conn := range conns {
+			select {
+			case <-conn.closed:
+			case <-time.After(1 * time.Second):
+				t.Fatalf("got an open connection to an agent, want all of them closed")
+			}
+		}
+	})
+}
+
+func TestLowestFreePortRange(t *testing.T) {
+	hostRanges := [][]*idl.PortRange{
+		{{Start: 7000, End: 7003}, {Start: 7006, End: 7020}},
+		{{Start: 6990, End: 7001}, {Start: 7003, End: 7015}},
+	}
+
+	cases := []struct {
+		name     string
+		count    int32
+		excluded []int32
+		expected int32
+		found    bool
+	}{
+		{name: "a port free on all the hosts", count: 1, expected: 7000, found: true},
+		{name: "the consecutive ports free on all the hosts", count: 3, expected: 7006, found: true},
+		{name: "the ports outside of the excluded ones", count: 3, excluded: []int32{7007}, expected: 7008, found: true},
+		{name: "no range when the ports are not free on all the hosts", count: 11},
+	}
+
+	for _, tc := range cases {
+		t.Run("returns "+tc.name, func(t *testing.T) {
+			basePort, found := hub.LowestFreePortRange(hostRanges, 6995, 7015, tc.count, tc.excluded)
+			if basePort != tc.expected || found != tc.found {
+				t.Fatalf("got %d and %t, want %d and %t", basePort, found, tc.expected, tc.found)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package hub
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/greenplum-db/gpdb/gp/utils"
@@ -21,38 +22,68 @@ ConnectHostList connects to given hostlist on the agent port, returns map of age
 if fails, returns error.
 */
 func (s *Server) ConnectHostList(hostList []string) (map[string]idl.AgentClient, error) {
+	conns, err := s.dialHostList(hostList)
+	if err != nil {
+		return nil, err
+	}
+
 	addressConnectionMap := make(map[string]idl.AgentClient)
+	for _, conn := range conns {
+		addressConnectionMap[conn.Hostname] = conn.AgentClient
+	}
+
+	return addressConnectionMap, nil
+}
+
+/*
+dialHostList connects to the agents of the given host addresses, which need not
+be the hosts of the hub, with the Hostname of each connection set to its
+address. Unlike Conns, the connections are owned by the caller, which closes
+them with closeConnections once done.
+*/
+func (s *Server) dialHostList(hostList []string) ([]*Connection, error) {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), DialTimeout)
 	defer cancelFunc()
 
 	credentials, err := s.Credentials.LoadClientCredentials()
 	if err != nil {
-		cancelFunc()
 		return nil, err
 	}
 
+	var conns []*Connection
+	dialed := make(map[string]bool)
 	for _, address := range hostList {
-		// Dial to the address
-		if _, ok := addressConnectionMap[address]; !ok {
-			remoteAddress := fmt.Sprintf("%s:%d", address, s.AgentPort)
-			opts := []grpc.DialOption{
-				grpc.WithBlock(),
-				grpc.WithTransportCredentials(credentials),
-				grpc.WithReturnConnectionError(),
-			}
-			if s.grpcDialer != nil {
-				opts = append(opts, grpc.WithContextDialer(s.grpcDialer))
-			}
-			conn, err := grpc.DialContext(ctx, remoteAddress, opts...)
-			if err != nil {
-				cancelFunc()
-				return nil, fmt.Errorf("could not connect to agent on host %s: %w", address, err)
-			}
-			addressConnectionMap[address] = idl.NewAgentClient(conn)
+		if dialed[address] {
+			continue
+		}
+		dialed[address] = true
+
+		remoteAddress := fmt.Sprintf("%s:%d", address, s.AgentPort)
+		opts := []grpc.DialOption{
+			grpc.WithBlock(),
+			grpc.WithTransportCredentials(credentials),
+			grpc.WithReturnConnectionError(),
+			grpc.WithChainUnaryInterceptor(s.newRetryInterceptor()),
+		}
+		opts = append(opts, utils.TracingDialOptions()...)
+		if s.grpcDialer != nil {
+			opts = append(opts, grpc.WithContextDialer(s.grpcDialer))
 		}
+		conn, err := grpc.DialContext(ctx, remoteAddress, opts...)
+		if err != nil {
+			closeConnections(conns)
+			return nil, fmt.Errorf("could not connect to agent on host %s: %w", address, err)
+		}
+		conns = append(conns, &Connection{Conn: conn, AgentClient: idl.NewAgentClient(conn), Hostname: address})
 	}
 
-	return addressConnectionMap, nil
+	return conns, nil
+}
+
+func closeConnections(conns []*Connection) {
+	for _, conn := range conns {
+		conn.close()
+	}
 }
 func (s *Server) GetAllHostNames(ctx context.Context, request *idl.GetAllHostNamesRequest) (*idl.GetAllHostNamesReply, error) {
 	gplog.Debug("Starting with rpc GetAllHostNames")
@@ -62,7 +93,8 @@ func (s *Server) GetAllHostNames(ctx context.Context, request *idl.GetAllHostNam
 	}
 
 	var wg sync.WaitGroup
-	errs := make(chan error, len(addressConnectionMap))
+	var mutex sync.Mutex
+	var errs utils.HostErrors
 	replies := make(chan RpcReply, len(addressConnectionMap))
 	for address, conn := range addressConnectionMap {
 		wg.Add(1)
@@ -70,10 +102,12 @@ func (s *Server) GetAllHostNames(ctx context.Context, request *idl.GetAllHostNam
 		go func(addr string, connection idl.AgentClient) {
 			defer wg.Done()
 			request := idl.GetHostNameRequest{}
-			reply, err := connection.GetHostName(context.Background(), &request)
+			reply, err := connection.GetHostName(ctx, &request)
 			if err != nil {
-				errs <- fmt.Errorf("host: %s, %w", addr, err)
-				errs <- utils.LogAndReturnError(fmt.Errorf("getting hostname for %s failed with error:%v", addr, err))
+				gplog.Error("getting hostname for %s failed with error:%v", addr, err)
+				mutex.Lock()
+				errs = append(errs, &utils.HostError{Host: addr, RPC: "GetHostName", Err: utils.FormatGrpcError(err)})
+				mutex.Unlock()
 				return
 			}
 
@@ -85,15 +119,13 @@ func (s *Server) GetAllHostNames(ctx context.Context, request *idl.GetAllHostNam
 	}
 	wg.Wait()
 	close(replies)
-	close(errs)
 
-	// Check for errors
+	// Report the failures of all the hosts
 	if len(errs) > 0 {
-		for e := range errs {
-			err = e
-			break
-		}
-		return &idl.GetAllHostNamesReply{}, err
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Host < errs[j].Host
+		})
+		return &idl.GetAllHostNamesReply{}, errs
 	}
 
 	// Extract replies and populate reply
@@ -21,13 +21,13 @@ func TestServer_GetAllHostNames(t *testing.T) {
 	credentials := &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()}
 	listener := bufconn.Listen(1024 * 1024)
 	hubConfig := &hub.Config{
-		1234,
-		5678,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        1234,
+		AgentPort:   5678,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      "/tmp/logDir",
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 
 	t.Run("returns error when fails to load client credentials", func(t *testing.T) {
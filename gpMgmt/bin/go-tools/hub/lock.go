@@ -0,0 +1,309 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+const (
+	// metadata set by the CLI on the mutating calls to tell who is calling and whether to wait for the lock
+	CallerMetadataKey   = "gp-caller"
+	LockWaitMetadataKey = "gp-lock-wait"
+)
+
+/*
+LockCaller identifies the caller of an RPC by the identity of its certificate,
+along with the user@host the client claims to run as, which is only a label as
+any client can set it.
+*/
+type LockCaller struct {
+	Holder    string
+	ClaimedBy string
+}
+
+func (c LockCaller) String() string {
+	if c.ClaimedBy == "" {
+		return c.Holder
+	}
+
+	return fmt.Sprintf("%s (claiming to be %s)", c.Holder, c.ClaimedBy)
+}
+
+// LockInfo tells who holds the cluster lock and for which operation
+type LockInfo struct {
+	Holder      string    `json:"holder"`
+	ClaimedBy   string    `json:"claimedBy,omitempty"`
+	Operation   string    `json:"operation"`
+	OperationID string    `json:"operationId,omitempty"`
+	StartTime   time.Time `json:"startTime"`
+}
+
+func (i *LockInfo) String() string {
+	operation := i.Operation
+	if i.OperationID != "" {
+		operation = fmt.Sprintf("%s (operation %s)", i.Operation, i.OperationID)
+	}
+
+	return fmt.Sprintf("%s for %s since %s", LockCaller{Holder: i.Holder, ClaimedBy: i.ClaimedBy}, operation, i.StartTime.Format(time.RFC3339))
+}
+
+func (i *LockInfo) toIdl() *idl.ClusterLock {
+	if i == nil {
+		return nil
+	}
+
+	return &idl.ClusterLock{
+		Holder:      i.Holder,
+		Operation:   i.Operation,
+		OperationId: i.OperationID,
+		StartTime:   i.StartTime.Unix(),
+		ClaimedBy:   i.ClaimedBy,
+	}
+}
+
+/*
+ClusterLock serialises the operations which change the cluster, like creating
+it, adding mirrors or cleaning up after a failed creation. The lock is recorded
+in a file, so that it survives a restart of the hub: an operation which was
+interrupted by the restart may have left the cluster halfway, and the lock has
+to be broken explicitly once it is safe to carry on.
+*/
+type ClusterLock struct {
+	path string
+
+	mutex    sync.Mutex
+	info     *LockInfo
+	released chan struct{} // closed whenever the lock is released
+}
+
+// NewClusterLock restores the lock recorded in the file, if any
+func NewClusterLock(path string) *ClusterLock {
+	l := &ClusterLock{
+		path:     path,
+		released: make(chan struct{}),
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			gplog.Warn("failed to read the cluster lock file %s: %v", path, err)
+		}
+		return l
+	}
+
+	info := &LockInfo{}
+	err = json.Unmarshal(contents, info)
+	if err != nil {
+		gplog.Warn("ignoring the invalid cluster lock file %s: %v", path, err)
+		return l
+	}
+	l.info = info
+
+	return l
+}
+
+// LockHandle is the hold of a caller on the cluster lock
+type LockHandle struct {
+	lock *ClusterLock
+	info *LockInfo
+}
+
+/*
+Acquire takes the lock for the operation. If the lock is held and wait is set,
+it waits until the lock is released or the context is done, calling onWait
+once before waiting; otherwise it fails with FailedPrecondition.
+*/
+func (l *ClusterLock) Acquire(ctx context.Context, caller LockCaller, operation string, wait bool, onWait func(held *LockInfo)) (*LockHandle, error) {
+	waiting := false
+	for {
+		l.mutex.Lock()
+		if l.info == nil {
+			info := &LockInfo{Holder: caller.Holder, ClaimedBy: caller.ClaimedBy, Operation: operation, StartTime: time.Now().UTC()}
+			err := l.save(info)
+			if err != nil {
+				l.mutex.Unlock()
+				return nil, err
+			}
+
+			l.info = info
+			l.mutex.Unlock()
+			gplog.Info("Cluster lock acquired by %s", info)
+
+			return &LockHandle{lock: l, info: info}, nil
+		}
+		held, released := *l.info, l.released
+		l.mutex.Unlock()
+
+		if !wait {
+			return nil, grpcStatus.Errorf(codes.FailedPrecondition, "the cluster is locked by %s, use --wait to wait for the lock to be released or --break-lock to break it", &held)
+		}
+
+		if !waiting {
+			waiting = true
+			gplog.Info("%s by %s is waiting for the cluster lock held by %s", operation, caller, &held)
+			if onWait != nil {
+				onWait(&held)
+			}
+		}
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, grpcStatus.Errorf(grpcStatus.FromContextError(ctx.Err()).Code(), "gave up waiting for the cluster lock held by %s: %v", &held, ctx.Err())
+		}
+	}
+}
+
+// Status returns who holds the lock, or nil if it is not held
+func (l *ClusterLock) Status() *LockInfo {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.info == nil {
+		return nil
+	}
+	info := *l.info
+
+	return &info
+}
+
+// Break releases the lock whoever holds it, and returns who did
+func (l *ClusterLock) Break() (*LockInfo, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.info == nil {
+		return nil, nil
+	}
+
+	broken := l.info
+	err := l.release()
+	if err != nil {
+		return nil, err
+	}
+
+	return broken, nil
+}
+
+// save records the lock in the file, must be called with the mutex held
+func (l *ClusterLock) save(info *LockInfo) error {
+	contents, err := json.MarshalIndent(info, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the cluster lock: %w", err)
+	}
+
+	err = os.WriteFile(l.path, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the cluster lock file %s: %w", l.path, err)
+	}
+
+	return nil
+}
+
+// release removes the lock and wakes up the waiters, must be called with the mutex held
+func (l *ClusterLock) release() error {
+	err := os.Remove(l.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove the cluster lock file %s: %w", l.path, err)
+	}
+
+	l.info = nil
+	close(l.released)
+	l.released = make(chan struct{})
+
+	return nil
+}
+
+// SetOperationID records the ID of the operation holding the lock, unless the lock was broken meanwhile
+func (h *LockHandle) SetOperationID(id string) {
+	h.lock.mutex.Lock()
+	defer h.lock.mutex.Unlock()
+
+	if h.lock.info != h.info {
+		return
+	}
+
+	h.info.OperationID = id
+	err := h.lock.save(h.info)
+	if err != nil {
+		gplog.Warn(err.Error())
+	}
+}
+
+// Release gives the lock back, unless it was broken meanwhile
+func (h *LockHandle) Release() {
+	h.lock.mutex.Lock()
+	defer h.lock.mutex.Unlock()
+
+	if h.lock.info != h.info {
+		return
+	}
+
+	err := h.lock.release()
+	if err != nil {
+		gplog.Error(err.Error())
+		return
+	}
+	gplog.Info("Cluster lock released by %s", h.info)
+}
+
+/*
+lockCaller describes the caller of the RPC and whether it asked to wait for the
+lock. The caller is identified by the common name of its certificate, the
+user@host set by the CLI being kept only as a label.
+*/
+func lockCaller(ctx context.Context) (caller LockCaller, wait bool) {
+	caller.Holder = "unknown"
+	if cert := utils.GetPeerCertificate(ctx); cert != nil {
+		caller.Holder = cert.Subject.CommonName
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(CallerMetadataKey); len(values) > 0 {
+		caller.ClaimedBy = values[0]
+	}
+
+	values := md.Get(LockWaitMetadataKey)
+	wait = len(values) > 0 && values[0] == "true"
+
+	return caller, wait
+}
+
+// acquireLock takes the cluster lock for the RPC on behalf of its caller
+func (s *Server) acquireLock(ctx context.Context, method string, onWait func(held *LockInfo)) (*LockHandle, error) {
+	caller, wait := lockCaller(ctx)
+
+	return s.lock.Acquire(ctx, caller, method, wait, onWait)
+}
+
+func (s *Server) GetLockStatus(ctx context.Context, request *idl.GetLockStatusRequest) (*idl.GetLockStatusReply, error) {
+	return &idl.GetLockStatusReply{Lock: s.lock.Status().toIdl()}, nil
+}
+
+func (s *Server) BreakLock(ctx context.Context, request *idl.BreakLockRequest) (*idl.BreakLockReply, error) {
+	caller, _ := lockCaller(ctx)
+
+	broken, err := s.lock.Break()
+	if err != nil {
+		return nil, err
+	}
+
+	if broken != nil {
+		gplog.Warn("Cluster lock held by %s was broken by %s", broken, caller)
+	}
+
+	return &idl.BreakLockReply{Lock: broken.toIdl()}, nil
+}
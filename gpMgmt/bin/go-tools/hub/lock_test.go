@@ -0,0 +1,195 @@
+package hub_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+)
+
+func TestClusterLock(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("records the lock in the file until it is released", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), constants.ClusterLockFileName)
+		lock := hub.NewClusterLock(path)
+
+		handle, err := lock.Acquire(context.Background(), hub.LockCaller{Holder: "cdw", ClaimedBy: "gpadmin@cdw"}, "MakeCluster", false, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		handle.SetOperationID("1234")
+
+		restored := hub.NewClusterLock(path).Status()
+		if restored == nil || restored.Holder != "cdw" || restored.ClaimedBy != "gpadmin@cdw" || restored.Operation != "MakeCluster" || restored.OperationID != "1234" {
+			t.Fatalf("unexpected lock %+v", restored)
+		}
+
+		handle.Release()
+		if lock.Status() != nil {
+			t.Fatalf("got %+v, want the lock to be released", lock.Status())
+		}
+
+		_, err = os.Stat(path)
+		if !os.IsNotExist(err) {
+			t.Fatalf("got %v, want the lock file to be removed", err)
+		}
+	})
+
+	t.Run("fails when the lock is held and not waiting for it", func(t *testing.T) {
+		lock := hub.NewClusterLock(filepath.Join(t.TempDir(), constants.ClusterLockFileName))
+
+		handle, err := lock.Acquire(context.Background(), hub.LockCaller{Holder: "cdw", ClaimedBy: "gpadmin@cdw"}, "MakeCluster", false, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer handle.Release()
+
+		_, err = lock.Acquire(context.Background(), hub.LockCaller{Holder: "sdw1"}, "CleanInitCluster", false, nil)
+		if grpcStatus.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("got %v, want %v", err, codes.FailedPrecondition)
+		}
+	})
+
+	t.Run("waits for the lock to be released", func(t *testing.T) {
+		lock := hub.NewClusterLock(filepath.Join(t.TempDir(), constants.ClusterLockFileName))
+
+		handle, err := lock.Acquire(context.Background(), hub.LockCaller{Holder: "cdw", ClaimedBy: "gpadmin@cdw"}, "MakeCluster", false, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		waiting := make(chan *hub.LockInfo, 1)
+		acquired := make(chan error)
+		go func() {
+			next, err := lock.Acquire(context.Background(), hub.LockCaller{Holder: "sdw1"}, "CleanInitCluster", true, func(held *hub.LockInfo) {
+				waiting <- held
+			})
+			if err == nil {
+				next.Release()
+			}
+			acquired <- err
+		}()
+
+		held := <-waiting
+		if held.Holder != "cdw" {
+			t.Fatalf("got %s, want cdw", held.Holder)
+		}
+
+		handle.Release()
+		err = <-acquired
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		lock := hub.NewClusterLock(filepath.Join(t.TempDir(), constants.ClusterLockFileName))
+
+		handle, err := lock.Acquire(context.Background(), hub.LockCaller{Holder: "cdw", ClaimedBy: "gpadmin@cdw"}, "MakeCluster", false, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer handle.Release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err = lock.Acquire(ctx, hub.LockCaller{Holder: "sdw1"}, "CleanInitCluster", true, nil)
+		if grpcStatus.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("got %v, want %v", err, codes.DeadlineExceeded)
+		}
+	})
+
+	t.Run("does not release a lock which was broken", func(t *testing.T) {
+		lock := hub.NewClusterLock(filepath.Join(t.TempDir(), constants.ClusterLockFileName))
+
+		handle, err := lock.Acquire(context.Background(), hub.LockCaller{Holder: "cdw", ClaimedBy: "gpadmin@cdw"}, "MakeCluster", false, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		broken, err := lock.Break()
+		if err != nil || broken.Holder != "cdw" {
+			t.Fatalf("got %+v, %v, want the broken lock", broken, err)
+		}
+
+		next, err := lock.Acquire(context.Background(), hub.LockCaller{Holder: "sdw1"}, "CleanInitCluster", false, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer next.Release()
+
+		handle.Release()
+		if status := lock.Status(); status == nil || status.Holder != "sdw1" {
+			t.Fatalf("got %+v, want the lock of sdw1", status)
+		}
+	})
+}
+
+func TestLockRPCs(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	logDir := t.TempDir()
+	contents, err := json.Marshal(hub.LockInfo{Holder: "cdw", ClaimedBy: "gpadmin@cdw", Operation: "MakeCluster", StartTime: time.Now()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(logDir, constants.ClusterLockFileName), contents, 0600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the lock left over before a restart of the hub is still held
+	hubServer := hub.New(&hub.Config{
+		LogDir:      logDir,
+		Credentials: &testutils.MockCredentials{},
+	}, nil)
+
+	reply, err := hubServer.GetLockStatus(context.Background(), &idl.GetLockStatusRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reply.Lock == nil || reply.Lock.Holder != "cdw" || reply.Lock.ClaimedBy != "gpadmin@cdw" || reply.Lock.Operation != "MakeCluster" {
+		t.Fatalf("unexpected lock %+v", reply.Lock)
+	}
+
+	err = hubServer.AddMirrors(&idl.AddMirrorsRequest{}, newAttachedStream(context.Background()))
+	if grpcStatus.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("got %v, want %v", err, codes.FailedPrecondition)
+	}
+
+	_, err = hubServer.CleanInitCluster(context.Background(), &idl.CleanInitClusterRequest{})
+	if grpcStatus.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("got %v, want %v", err, codes.FailedPrecondition)
+	}
+
+	breakReply, err := hubServer.BreakLock(context.Background(), &idl.BreakLockRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if breakReply.Lock == nil || breakReply.Lock.Holder != "cdw" {
+		t.Fatalf("unexpected lock %+v", breakReply.Lock)
+	}
+
+	_, err = hubServer.CleanInitCluster(context.Background(), &idl.CleanInitClusterRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reply, err = hubServer.GetLockStatus(context.Background(), &idl.GetLockStatusRequest{})
+	if err != nil || reply.Lock != nil {
+		t.Fatalf("got %+v, %v, want the lock to be released", reply.Lock, err)
+	}
+}
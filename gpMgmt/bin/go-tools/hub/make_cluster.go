@@ -2,12 +2,13 @@ package hub
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/maps"
 
 	"github.com/greenplum-db/gp-common-go-libs/dbconn"
@@ -22,17 +23,25 @@ import (
 var execOnDatabaseFunc = ExecOnDatabase
 
 func (s *Server) MakeCluster(request *idl.MakeClusterRequest, stream idl.Hub_MakeClusterServer) error {
-	var err error
+	return s.runOperation(stream, "MakeCluster", func(ctx context.Context, hubStream *HubStream) error {
+		return s.makeCluster(ctx, hubStream, request)
+	})
+}
+
+func (s *Server) makeCluster(ctx context.Context, hubStream hubStreamer, request *idl.MakeClusterRequest) (err error) {
 	var shutdownCoordinator, mirrorless bool
 
 	mirrorless = len(request.GetMirrorSegments()) == 0
-	hubStream := NewHubStream(stream)
 
 	// shutdown the coordinator segment if any error occurs
 	defer func() {
+		if err != nil && ctx.Err() != nil {
+			hubStream.StreamLogMsg(fmt.Sprintf("Creating the cluster was interrupted: %v", ctx.Err()), idl.LogLevel_WARNING)
+		}
 		if err != nil && shutdownCoordinator {
 			hubStream.StreamLogMsg("Not able to create the the cluster, proceeding to shutdown the coordinator segment")
-			err := s.StopCoordinator(&hubStream, request.GpArray.Coordinator.DataDirectory)
+			// shut it down even if the operation was cancelled
+			err := s.StopCoordinator(context.WithoutCancel(ctx), hubStream, request.GpArray.Coordinator.DataDirectory)
 			if err != nil {
 				gplog.Error(err.Error())
 			}
@@ -46,13 +55,19 @@ func (s *Server) MakeCluster(request *idl.MakeClusterRequest, stream idl.Hub_Mak
 		return utils.LogAndReturnError(fmt.Errorf("gpinitsystem has failed previously. Run gp init cluster --clean before creating cluster again"))
 	}
 
+	err = validateTopology(hubStream, greenplum.NewGpArrayFromIdl(request.GpArray), greenplum.ValidateOptions{})
+	if err != nil {
+		return utils.LogAndReturnError(err)
+	}
+
 	err = s.DialAllAgents()
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
 
 	hubStream.StreamLogMsg("Starting to create the cluster")
-	err = s.ValidateEnvironment(&hubStream, request)
+	endStage := startStage(hubStream, StageValidateHosts, "Validating the hosts")
+	err = endStage(s.ValidateEnvironment(ctx, hubStream, request))
 	if err != nil {
 		return utils.LogAndReturnError(fmt.Errorf("validating hosts: %w", err))
 	}
@@ -70,7 +85,8 @@ func (s *Server) MakeCluster(request *idl.MakeClusterRequest, stream idl.Hub_Mak
 	}
 
 	hubStream.StreamLogMsg("Creating coordinator segment")
-	err = s.CreateAndStartCoordinator(request.GpArray.Coordinator, request.ClusterParams)
+	endStage = startStage(hubStream, StageCreateCoordinator, "Creating the coordinator segment")
+	err = endStage(s.CreateAndStartCoordinator(ctx, hubStream, request.GpArray.Coordinator, request.ClusterParams))
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
@@ -79,28 +95,13 @@ func (s *Server) MakeCluster(request *idl.MakeClusterRequest, stream idl.Hub_Mak
 	shutdownCoordinator = true
 
 	hubStream.StreamLogMsg("Starting to register primary segments with the coordinator")
-
-	conn, err := greenplum.GetCoordinatorConn(request.GpArray.Coordinator.DataDirectory, "template1", true)
-	if err != nil {
-		return utils.LogAndReturnError(err)
-	}
-
-	err = greenplum.RegisterCoordinator(request.GpArray.Coordinator, conn)
-	if err != nil {
-		return utils.LogAndReturnError(err)
-	}
-
-	err = greenplum.RegisterPrimarySegments(request.GetPrimarySegments(), conn)
-	if err != nil {
+	endStage = startStage(hubStream, StageRegisterPrimaries, "Registering the primary segments")
+	conn, gparray, err := registerPrimarySegments(ctx, request)
+	if endStage(err) != nil {
 		return utils.LogAndReturnError(err)
 	}
 	hubStream.StreamLogMsg("Successfully registered primary segments with the coordinator")
-
-	gparray, err := greenplum.NewGpArrayFromCatalog(conn)
-	if err != nil {
-		return utils.LogAndReturnError(err)
-	}
-	conn.Close()
+	s.snapshotTopology(ctx, hubStream, request.GpArray.Coordinator.DataDirectory, TopologyAfter, gparray)
 
 	primarySegs := gparray.GetPrimarySegments()
 
@@ -121,7 +122,8 @@ func (s *Server) MakeCluster(request *idl.MakeClusterRequest, stream idl.Hub_Mak
 		return utils.LogAndReturnError(err)
 	}
 	hubStream.StreamLogMsg("Creating primary segments")
-	err = s.CreateSegments(&hubStream, primarySegs, request.ClusterParams, coordinatorAddrs)
+	endStage = startStage(hubStream, StageCreatePrimaries, "Creating the primary segments")
+	err = endStage(s.CreateSegments(ctx, hubStream, primarySegs, request.ClusterParams, coordinatorAddrs))
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
@@ -130,79 +132,157 @@ func (s *Server) MakeCluster(request *idl.MakeClusterRequest, stream idl.Hub_Mak
 	shutdownCoordinator = false
 
 	hubStream.StreamLogMsg("Restarting the Greenplum cluster in production mode")
-	err = s.StopCoordinator(&hubStream, request.GpArray.Coordinator.DataDirectory)
+	endStage = startStage(hubStream, StageStartCluster, "Restarting the cluster in production mode")
+	err = endStage(s.restartCluster(ctx, hubStream, request, primarySegs))
+	if err != nil {
+		return utils.LogAndReturnError(err)
+	}
+	hubStream.StreamLogMsg("Completed restart of Greenplum cluster in production mode")
+
+	endStage = startStage(hubStream, StageConfigureDatabase, "Configuring the database")
+	err = endStage(configureDatabase(ctx, hubStream, conn, request.ClusterParams))
 	if err != nil {
 		return utils.LogAndReturnError(err)
 	}
 
+	if !mirrorless {
+		mirrorSegs, err := populateMirrorWithContentId(gparray, request.GpArray.SegmentArray)
+		if err != nil {
+			return err
+		}
+
+		addMirrosReq := &idl.AddMirrorsRequest{
+			CoordinatorDataDir: request.GpArray.Coordinator.DataDirectory,
+			Mirrors:            mirrorSegs,
+		}
+		err = s.addMirrors(ctx, hubStream, addMirrosReq)
+		if err != nil {
+			return err
+		}
+	}
+
+	// If we reach till here cluster is created successfully. So remove the entries file
+	os.Remove(filename)
+
+	err = s.RecordClusterState(request.GpArray.Coordinator.DataDirectory, false)
+	if err != nil {
+		gplog.Warn("failed to record the cluster state: %v", err)
+		hubStream.StreamLogMsg(fmt.Sprintf("Failed to record the cluster state: %v", err), idl.LogLevel_WARNING)
+	}
+
+	return nil
+}
+
+// restartCluster stops the coordinator started in utility mode and starts the whole cluster using gpstart
+func (s *Server) restartCluster(ctx context.Context, hubStream hubStreamer, request *idl.MakeClusterRequest, primarySegs []greenplum.Segment) error {
+	err := s.StopCoordinator(ctx, hubStream, request.GpArray.Coordinator.DataDirectory)
+	if err != nil {
+		return err
+	}
+
 	// TODO: Replace this with the new gp start once it is complete
 	gpstartOptions := &greenplum.GpStart{
 		DataDirectory: request.GpArray.Coordinator.DataDirectory,
 		Verbose:       request.Verbose,
 	}
 	cmd := utils.NewGpSourcedCommand(gpstartOptions, s.GpHome)
-	err = hubStream.StreamExecCommand(cmd, s.GpHome)
+	_, span := utils.StartSpan(ctx, "GpStart", attribute.String("command", cmd.String()))
+	err = hubStream.StreamExecCommand(ctx, OutputSource{Host: request.GpArray.Coordinator.HostName}, cmd)
+	utils.EndSpan(span, err)
 	if err != nil {
-		return utils.LogAndReturnError(fmt.Errorf("executing gpstart: %w", err))
+		return fmt.Errorf("executing gpstart: %w", err)
 	}
-	hubStream.StreamLogMsg("Completed restart of Greenplum cluster in production mode")
 
-	hubStream.StreamLogMsg("Creating core GPDB extensions")
-	err = CreateGpToolkitExt(conn)
+	for _, seg := range primarySegs {
+		hubStream.StreamSegmentMsg(&idl.Segment{
+			Dbid:          int32(seg.Dbid),
+			Contentid:     int32(seg.Content),
+			HostName:      seg.Hostname,
+			DataDirectory: seg.DataDir,
+		}, idl.SegmentState_SEGMENT_STARTED, nil)
+	}
+
+	return nil
+}
+
+// registerPrimarySegments registers the coordinator and the primary segments in the catalog and returns the resulting gparray
+func registerPrimarySegments(ctx context.Context, request *idl.MakeClusterRequest) (conn *dbconn.DBConn, gparray *greenplum.GpArray, err error) {
+	_, span := utils.StartSpan(ctx, "RegisterPrimarySegments")
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
+	conn, err = greenplum.GetCoordinatorConn(request.GpArray.Coordinator.DataDirectory, "template1", true)
 	if err != nil {
-		return utils.LogAndReturnError(err)
+		return nil, nil, err
 	}
-	hubStream.StreamLogMsg("Successfully created core GPDB extensions")
+	defer conn.Close()
 
-	hubStream.StreamLogMsg("Importing system collations")
-	err = ImportCollation(conn)
+	err = greenplum.RegisterCoordinator(request.GpArray.Coordinator, conn)
 	if err != nil {
-		return utils.LogAndReturnError(err)
+		return nil, nil, err
 	}
 
-	if request.ClusterParams.DbName != "" {
-		hubStream.StreamLogMsg(fmt.Sprintf("Creating database %q", request.ClusterParams.DbName))
-		err = CreateDatabase(conn, request.ClusterParams.DbName)
-		if err != nil {
-			return utils.LogAndReturnError(err)
-		}
+	err = greenplum.RegisterPrimarySegments(request.GetPrimarySegments(), conn)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	hubStream.StreamLogMsg("Setting Greenplum superuser password")
-	err = SetGpUserPasswd(conn, request.ClusterParams.SuPassword)
+	gparray, err = greenplum.NewGpArrayFromCatalog(conn)
 	if err != nil {
-		return utils.LogAndReturnError(err)
+		return nil, nil, err
 	}
 
-	if !mirrorless {
-		mirrorSegs, err := populateMirrorWithContentId(gparray, request.GpArray.SegmentArray)
-		if err != nil {
-			return err
-		}
+	return conn, gparray, nil
+}
 
-		addMirrosReq := &idl.AddMirrorsRequest{
-			CoordinatorDataDir: request.GpArray.Coordinator.DataDirectory,
-			Mirrors:            mirrorSegs,
-		}
-		err = s.AddMirrors(addMirrosReq, stream)
+// configureDatabase creates the extensions and the database, imports the collations and sets the superuser password
+func configureDatabase(ctx context.Context, stream hubStreamer, conn *dbconn.DBConn, clusterParams *idl.ClusterParams) (err error) {
+	_, span := utils.StartSpan(ctx, "ConfigureDatabase")
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
+	stream.StreamLogMsg("Creating core GPDB extensions")
+	err = CreateGpToolkitExt(conn)
+	if err != nil {
+		return err
+	}
+	stream.StreamLogMsg("Successfully created core GPDB extensions")
+
+	stream.StreamLogMsg("Importing system collations")
+	err = ImportCollation(conn)
+	if err != nil {
+		return err
+	}
+
+	if clusterParams.DbName != "" {
+		stream.StreamLogMsg(fmt.Sprintf("Creating database %q", clusterParams.DbName))
+		err = CreateDatabase(conn, clusterParams.DbName)
 		if err != nil {
 			return err
 		}
 	}
 
-	// If we reach till here cluster is created successfully. So remove the entries file
-	os.Remove(filename)
-
-	return nil
+	stream.StreamLogMsg("Setting Greenplum superuser password")
+	return SetGpUserPasswd(conn, clusterParams.SuPassword)
 }
 
-func (s *Server) ValidateEnvironment(stream hubStreamer, request *idl.MakeClusterRequest) error {
+func (s *Server) ValidateEnvironment(ctx context.Context, stream hubStreamer, request *idl.MakeClusterRequest) (err error) {
+	ctx, span := utils.StartSpan(ctx, "ValidateEnvironment")
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
 	var replies []*idl.LogMessage
+	var repliesMutex sync.Mutex
 
 	gparray := request.GpArray
 	hostDirMap := make(map[string][]string)
 	hostPortMap := make(map[string][]string)
 	hostAddressMap := make(map[string]map[string]bool)
+	hostPrimaryDirMap := make(map[string]map[string]bool)
+	hostMirrorDirMap := make(map[string][]string)
 
 	// Add coordinator to the map
 	hostDirMap[gparray.Coordinator.HostName] = append(hostDirMap[gparray.Coordinator.HostName], gparray.Coordinator.DataDirectory)
@@ -214,6 +294,10 @@ func (s *Server) ValidateEnvironment(stream hubStreamer, request *idl.MakeCluste
 	for _, seg := range request.GetPrimarySegments() {
 		hostDirMap[seg.HostName] = append(hostDirMap[seg.HostName], seg.DataDirectory)
 		hostPortMap[seg.HostName] = append(hostPortMap[seg.HostName], fmt.Sprintf("%d", seg.Port))
+		if hostPrimaryDirMap[seg.HostName] == nil {
+			hostPrimaryDirMap[seg.HostName] = make(map[string]bool)
+		}
+		hostPrimaryDirMap[seg.HostName][seg.DataDirectory] = true
 
 		if hostAddressMap[seg.HostName] == nil {
 			hostAddressMap[seg.HostName] = make(map[string]bool)
@@ -222,18 +306,21 @@ func (s *Server) ValidateEnvironment(stream hubStreamer, request *idl.MakeCluste
 	}
 	gplog.Debug("Host-Address-Map:[%v]", hostAddressMap)
 
+	// The mirrors are only created later on, but their data directories are prepared with the others
+	for _, seg := range request.GetMirrorSegments() {
+		hostMirrorDirMap[seg.HostName] = append(hostMirrorDirMap[seg.HostName], seg.DataDirectory)
+	}
+
 	// Get local gpVersion
 
-	localPgVersion, err := greenplum.GetPostgresGpVersion(s.GpHome)
+	localPgVersion, err := greenplum.GetPostgresGpVersion(ctx, s.GpHome)
 	if err != nil {
 		gplog.Error("fetching postgres gp-version:%v", err)
 		return err
 	}
 
-	progressLabel := "Validating Hosts:"
-	progressTotal := len(hostDirMap)
-	stream.StreamProgressMsg(progressLabel, progressTotal)
-	validateFn := func(conn *Connection) error {
+	progress := startProgress(stream, StageValidateHosts, "Validating Hosts:", len(hostDirMap))
+	validateFn := func(ctx context.Context, conn *Connection) error {
 		gplog.Debug(fmt.Sprintf("Starting to validate host: %s", conn.Hostname))
 
 		dirList := hostDirMap[conn.Hostname]
@@ -252,24 +339,39 @@ func (s *Server) ValidateEnvironment(stream hubStreamer, request *idl.MakeCluste
 			HostAddressList: addressList,
 			GpVersion:       localPgVersion,
 		}
-		reply, err := conn.AgentClient.ValidateHostEnv(context.Background(), &validateReq)
+		reply, err := conn.AgentClient.ValidateHostEnv(ctx, &validateReq)
 		if err != nil {
-			return utils.FormatGrpcError(err)
+			return utils.NewHostError("ValidateHostEnv", "", utils.FormatGrpcError(err))
 		}
 
-		stream.StreamProgressMsg(progressLabel, progressTotal)
+		prepareReply, err := conn.AgentClient.PrepareDirectories(ctx, &idl.PrepareDirectoriesRequest{
+			DataDirectories:    append(append([]string(nil), dirList...), hostMirrorDirMap[conn.Hostname]...),
+			RefusedFilesystems: request.RefusedFilesystems,
+		})
+		if err != nil {
+			return utils.NewHostError("PrepareDirectories", "", utils.FormatGrpcError(err))
+		}
+
+		progress.increment()
 		gplog.Debug(fmt.Sprintf("Successfully completed validation for host: %s", conn.Hostname))
 
 		// Add host-name to each reply message
-		for _, msg := range reply.Messages {
+		messages := append(reply.Messages, CheckSharedMountPoints(prepareReply.Directories, hostPrimaryDirMap[conn.Hostname])...)
+		for _, msg := range messages {
 			msg.Message = fmt.Sprintf("Host: %s %s", conn.Hostname, msg.Message)
-			replies = append(replies, msg)
 		}
 
+		repliesMutex.Lock()
+		defer repliesMutex.Unlock()
+		replies = append(replies, messages...)
+
 		return nil
 	}
 
-	err = ExecuteRPC(s.Conns, validateFn)
+	conns, release := s.acquireConns()
+	defer release()
+
+	err = ExecuteRPC(ctx, conns, validateFn)
 	if err != nil {
 		return err
 	}
@@ -281,7 +383,47 @@ func (s *Server) ValidateEnvironment(stream hubStreamer, request *idl.MakeCluste
 	return nil
 }
 
-func CreateSingleSegment(conn *Connection, seg *idl.Segment, clusterParams *idl.ClusterParams, coordinatorAddrs []string) error {
+/*
+CheckSharedMountPoints logs the mount point and filesystem of the data
+directories of a host, and warns when primaries share a mount point, as they
+then compete for the same disks.
+*/
+func CheckSharedMountPoints(directories []*idl.DirectoryInfo, primaryDirs map[string]bool) []*idl.LogMessage {
+	logMountPoints(directories)
+
+	var mountPoints []string
+	mountPointDirs := make(map[string][]string)
+	for _, dir := range directories {
+		if !primaryDirs[dir.DataDirectory] {
+			continue
+		}
+
+		if _, ok := mountPointDirs[dir.MountPoint]; !ok {
+			mountPoints = append(mountPoints, dir.MountPoint)
+		}
+		mountPointDirs[dir.MountPoint] = append(mountPointDirs[dir.MountPoint], dir.DataDirectory)
+	}
+
+	var warnings []*idl.LogMessage
+	for _, mountPoint := range mountPoints {
+		if len(mountPointDirs[mountPoint]) > 1 {
+			warnings = append(warnings, &idl.LogMessage{
+				Message: fmt.Sprintf("primary data directories %s share the mount point %s", strings.Join(mountPointDirs[mountPoint], ", "), mountPoint),
+				Level:   idl.LogLevel_WARNING,
+			})
+		}
+	}
+
+	return warnings
+}
+
+func logMountPoints(directories []*idl.DirectoryInfo) {
+	for _, dir := range directories {
+		gplog.Info("Data directory %s is on the %s filesystem mounted on %s", dir.DataDirectory, dir.Filesystem, dir.MountPoint)
+	}
+}
+
+func CreateSingleSegment(ctx context.Context, stream hubStreamer, conn *Connection, seg *idl.Segment, clusterParams *idl.ClusterParams, coordinatorAddrs []string) error {
 	pgConfig := make(map[string]string)
 	maps.Copy(pgConfig, clusterParams.CommonConfig)
 	if seg.Contentid == -1 {
@@ -300,45 +442,65 @@ func CreateSingleSegment(conn *Connection, seg *idl.Segment, clusterParams *idl.
 		DataChecksums:    clusterParams.DataChecksums,
 	}
 
-	_, err := conn.AgentClient.MakeSegment(context.Background(), makeSegmentReq)
+	segStream, err := conn.AgentClient.MakeSegmentStream(ctx, makeSegmentReq)
 	if err != nil {
 		return utils.FormatGrpcError(err)
 	}
 
-	return nil
+	source := OutputSource{Host: conn.Hostname, Segment: seg.DataDirectory, Dbid: int(seg.Dbid)}
+
+	return utils.FormatGrpcError(relaySegmentCommand(stream, source, segStream))
 }
 
-func (s *Server) CreateAndStartCoordinator(seg *idl.Segment, clusterParams *idl.ClusterParams) error {
-	coordinatorConn := getConnForHosts(s.Conns, []string{seg.HostName})
+func (s *Server) CreateAndStartCoordinator(ctx context.Context, stream hubStreamer, seg *idl.Segment, clusterParams *idl.ClusterParams) (err error) {
+	ctx, span := utils.StartSpan(ctx, "CreateAndStartCoordinator")
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
+	conns, release := s.acquireConns()
+	defer release()
+
+	coordinatorConn := getConnForHosts(conns, []string{seg.HostName})
 
 	seg.Contentid = -1
 	seg.Dbid = 1
-	request := func(conn *Connection) error {
-		err := CreateSingleSegment(conn, seg, clusterParams, []string{})
+	request := func(ctx context.Context, conn *Connection) error {
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_CREATING, nil)
+		err := CreateSingleSegment(ctx, stream, conn, seg, clusterParams, []string{})
 		if err != nil {
+			stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_FAILED, err)
 			return err
 		}
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_CREATED, nil)
 
 		startSegReq := &idl.StartSegmentRequest{
 			DataDir: seg.DataDirectory,
 			Wait:    true,
 			Options: "-c gp_role=utility",
 		}
-		_, err = conn.AgentClient.StartSegment(context.Background(), startSegReq)
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_STARTING, nil)
+		_, err = conn.AgentClient.StartSegment(ctx, startSegReq)
+		if err != nil {
+			err = utils.FormatGrpcError(err)
+			stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_FAILED, err)
+			return err
+		}
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_STARTED, nil)
 
-		return utils.FormatGrpcError(err)
+		return nil
 	}
 
-	return ExecuteRPC(coordinatorConn, request)
+	return ExecuteRPC(ctx, coordinatorConn, request)
 }
 
-func (s *Server) StopCoordinator(stream hubStreamer, pgdata string) error {
+func (s *Server) StopCoordinator(ctx context.Context, stream hubStreamer, pgdata string) error {
 	stream.StreamLogMsg("Shutting down coordinator segment")
 	pgCtlStopCmd := &postgres.PgCtlStop{
 		PgData: pgdata,
 	}
 
-	out, err := utils.RunGpCommand(pgCtlStopCmd, s.GpHome)
+	out, err := utils.RunGpCommand(ctx, pgCtlStopCmd, s.GpHome)
 	if err != nil {
 		return fmt.Errorf("executing pg_ctl stop: %s, %w", out, err)
 	}
@@ -347,7 +509,12 @@ func (s *Server) StopCoordinator(stream hubStreamer, pgdata string) error {
 	return nil
 }
 
-func (s *Server) CreateSegments(stream hubStreamer, segs []greenplum.Segment, clusterParams *idl.ClusterParams, coordinatorAddrs []string) error {
+func (s *Server) CreateSegments(ctx context.Context, stream hubStreamer, segs []greenplum.Segment, clusterParams *idl.ClusterParams, coordinatorAddrs []string) (err error) {
+	ctx, span := utils.StartSpan(ctx, "CreateSegments", attribute.Int("segments", len(segs)))
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
 	hostSegmentMap := map[string][]*idl.Segment{}
 	for _, seg := range segs {
 		segReq := &idl.Segment{
@@ -366,43 +533,28 @@ func (s *Server) CreateSegments(stream hubStreamer, segs []greenplum.Segment, cl
 		}
 	}
 
-	progressLabel := "Initializing primary segments:"
-	progressTotal := len(segs)
-	stream.StreamProgressMsg(progressLabel, progressTotal)
-
-	request := func(conn *Connection) error {
-		var wg sync.WaitGroup
-
-		segs := hostSegmentMap[conn.Hostname]
-		errs := make(chan error, len(segs))
-		for _, seg := range segs {
-			seg := seg
-			wg.Add(1)
-			go func(seg *idl.Segment) {
-				defer wg.Done()
-
-				gplog.Debug(fmt.Sprintf("Starting to create primary segment: %s", seg))
-				err := CreateSingleSegment(conn, seg, clusterParams, coordinatorAddrs)
-				if err != nil {
-					errs <- err
-				} else {
-					stream.StreamProgressMsg(progressLabel, progressTotal)
-					gplog.Debug(fmt.Sprintf("Successfully created primary segment: %s", seg))
-				}
-			}(seg)
+	progress := startProgress(stream, StageCreatePrimaries, "Initializing primary segments:", len(segs))
+
+	request := func(ctx context.Context, conn *Connection, seg *idl.Segment) error {
+		gplog.Debug(fmt.Sprintf("Starting to create primary segment: %s", seg))
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_CREATING, nil)
+		err := CreateSingleSegment(ctx, stream, conn, seg, clusterParams, coordinatorAddrs)
+		if err != nil {
+			stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_FAILED, err)
+			return utils.NewHostError("MakeSegment", seg.DataDirectory, err)
 		}
+		stream.StreamSegmentMsg(seg, idl.SegmentState_SEGMENT_CREATED, nil)
 
-		wg.Wait()
-		close(errs)
+		progress.increment()
+		gplog.Debug(fmt.Sprintf("Successfully created primary segment: %s", seg))
 
-		var err error
-		for e := range errs {
-			err = errors.Join(err, e)
-		}
-		return err
+		return nil
 	}
 
-	return ExecuteRPC(s.Conns, request)
+	conns, release := s.acquireConns()
+	defer release()
+
+	return ExecuteRPCForEach(ctx, conns, hostSegmentMap, request)
 }
 
 func ExecOnDatabase(conn *dbconn.DBConn, dbname string, query string) error {
@@ -1,6 +1,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"maps"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/jmoiron/sqlx"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/greenplum-db/gp-common-go-libs/dbconn"
@@ -35,13 +37,13 @@ func TestCreateSegments(t *testing.T) {
 
 	credentials := &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()}
 	hubConfig := &hub.Config{
-		1234,
-		5678,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        1234,
+		AgentPort:   5678,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      t.TempDir(),
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 	hubServer := hub.New(hubConfig, nil)
 
@@ -85,33 +87,33 @@ func TestCreateSegments(t *testing.T) {
 		maps.Copy(expectedSegConfig, segConfig)
 
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
-		sdw1.EXPECT().MakeSegment(
+		sdw1.EXPECT().MakeSegmentStream(
 			gomock.Any(),
 			&idl.MakeSegmentRequest{
 				Segment:          segmentToProto(segs[0]),
 				SegConfig:        expectedSegConfig,
 				CoordinatorAddrs: make([]string, 0),
 			},
-		).Return(&idl.MakeSegmentReply{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
-		sdw2.EXPECT().MakeSegment(
+		sdw2.EXPECT().MakeSegmentStream(
 			gomock.Any(),
 			&idl.MakeSegmentRequest{
 				Segment:          segmentToProto(segs[1]),
 				SegConfig:        expectedSegConfig,
 				CoordinatorAddrs: make([]string, 0),
 			},
-		).Return(&idl.MakeSegmentReply{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 
-		sdw2.EXPECT().MakeSegment(
+		sdw2.EXPECT().MakeSegmentStream(
 			gomock.Any(),
 			&idl.MakeSegmentRequest{
 				Segment:          segmentToProto(segs[2]),
 				SegConfig:        expectedSegConfig,
 				CoordinatorAddrs: make([]string, 0),
 			},
-		).Return(&idl.MakeSegmentReply{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 
 		agentConns := []*hub.Connection{
 			{AgentClient: sdw1, Hostname: "sdw1"},
@@ -126,25 +128,30 @@ func TestCreateSegments(t *testing.T) {
 		}
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.CreateSegments(mock, segs, clusterParams, []string{})
+		err := hubServer.CreateSegments(context.Background(), mock, segs, clusterParams, []string{})
 		if err != nil {
 			t.Fatalf("unexpected error: %#v", err)
 		}
 
-		expectedStreamResponse := make([]*idl.HubReply, len(segs)+1)
-		for i := range expectedStreamResponse {
-			expectedStreamResponse[i] = &idl.HubReply{
-				Message: &idl.HubReply_ProgressMsg{
-					ProgressMsg: &idl.ProgressMessage{
-						Label: "Initializing primary segments:",
-						Total: 3,
-					},
-				},
-			}
+		var expectedProgress []*idl.ProgressMessage
+		for i := 0; i <= len(segs); i++ {
+			expectedProgress = append(expectedProgress, &idl.ProgressMessage{
+				Stage:   hub.StageCreatePrimaries,
+				Label:   "Initializing primary segments:",
+				Current: int32(i),
+				Total:   3,
+			})
+		}
+		if !reflect.DeepEqual(progressMessages(stream.GetBuffer()), expectedProgress) {
+			t.Fatalf("got %+v, want %+v", progressMessages(stream.GetBuffer()), expectedProgress)
 		}
 
-		if !reflect.DeepEqual(stream.GetBuffer(), expectedStreamResponse) {
-			t.Fatalf("got %+v, want %+v", stream.GetBuffer(), expectedStreamResponse)
+		expectedStates := map[string][]idl.SegmentState{}
+		for _, seg := range segs {
+			expectedStates[seg.DataDir] = []idl.SegmentState{idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_CREATED}
+		}
+		if !reflect.DeepEqual(segmentStates(stream.GetBuffer()), expectedStates) {
+			t.Fatalf("got %+v, want %+v", segmentStates(stream.GetBuffer()), expectedStates)
 		}
 	})
 
@@ -155,21 +162,23 @@ func TestCreateSegments(t *testing.T) {
 		expectedErr := errors.New("error")
 
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
-		sdw1.EXPECT().MakeSegment(
+		sdw1.EXPECT().MakeSegmentStream(
 			gomock.Any(),
 			gomock.Any(),
-		).Return(&idl.MakeSegmentReply{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil), nil)
 
+		// the segments of sdw2 are created concurrently, so fail the one of segs[2] whichever comes first
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
-		sdw2.EXPECT().MakeSegment(
+		sdw2.EXPECT().MakeSegmentStream(
 			gomock.Any(),
 			gomock.Any(),
-		).Return(&idl.MakeSegmentReply{}, expectedErr)
+		).DoAndReturn(func(ctx context.Context, request *idl.MakeSegmentRequest, opts ...grpc.CallOption) (idl.Agent_MakeSegmentStreamClient, error) {
+			if request.Segment.DataDirectory == segs[2].DataDir {
+				return testutils.NewMockSegmentCommandStream(expectedErr), nil
+			}
 
-		sdw2.EXPECT().MakeSegment(
-			gomock.Any(),
-			gomock.Any(),
-		).Return(&idl.MakeSegmentReply{}, nil)
+			return testutils.NewMockSegmentCommandStream(nil), nil
+		}).Times(2)
 
 		agentConns := []*hub.Connection{
 			{AgentClient: sdw1, Hostname: "sdw1"},
@@ -184,25 +193,28 @@ func TestCreateSegments(t *testing.T) {
 		}
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.CreateSegments(mock, segs, clusterParams, []string{})
+		err := hubServer.CreateSegments(context.Background(), mock, segs, clusterParams, []string{})
 		if !errors.Is(err, expectedErr) {
 			t.Fatalf("got %#v, want %#V", err, expectedErr)
 		}
 
-		expectedStreamResponse := make([]*idl.HubReply, 3)
-		for i := range expectedStreamResponse {
-			expectedStreamResponse[i] = &idl.HubReply{
-				Message: &idl.HubReply_ProgressMsg{
-					ProgressMsg: &idl.ProgressMessage{
-						Label: "Initializing primary segments:",
-						Total: 3,
-					},
-				},
-			}
+		var expectedProgress []*idl.ProgressMessage
+		for i := 0; i < len(segs); i++ {
+			expectedProgress = append(expectedProgress, &idl.ProgressMessage{
+				Stage:   hub.StageCreatePrimaries,
+				Label:   "Initializing primary segments:",
+				Current: int32(i),
+				Total:   3,
+			})
+		}
+		if !reflect.DeepEqual(progressMessages(stream.GetBuffer()), expectedProgress) {
+			t.Fatalf("got %+v, want %+v", progressMessages(stream.GetBuffer()), expectedProgress)
 		}
 
-		if !reflect.DeepEqual(stream.GetBuffer(), expectedStreamResponse) {
-			t.Fatalf("got %+v, want %+v", stream.GetBuffer(), expectedStreamResponse)
+		states := segmentStates(stream.GetBuffer())
+		failed := []idl.SegmentState{idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_FAILED}
+		if !reflect.DeepEqual(states[segs[2].DataDir], failed) {
+			t.Fatalf("got %+v, want %+v", states[segs[2].DataDir], failed)
 		}
 	})
 
@@ -224,14 +236,18 @@ func TestCreateSegments(t *testing.T) {
 		}
 
 		cdw := mock_idl.NewMockAgentClient(ctrl)
-		cdw.EXPECT().MakeSegment(
+		cdw.EXPECT().MakeSegmentStream(
 			gomock.Any(),
 			&idl.MakeSegmentRequest{
 				Segment:          seg,
 				SegConfig:        expectedSegConfig,
 				CoordinatorAddrs: make([]string, 0),
 			},
-		).Return(&idl.MakeSegmentReply{}, nil)
+		).Return(testutils.NewMockSegmentCommandStream(nil, &idl.SegmentCommandReply{
+			Message: &idl.SegmentCommandReply_Output{
+				Output: &idl.OutputLine{Stream: idl.OutputStream_STDOUT, Line: "Success. You can now start the database server"},
+			},
+		}), nil)
 
 		cdw.EXPECT().StartSegment(
 			gomock.Any(),
@@ -253,10 +269,42 @@ func TestCreateSegments(t *testing.T) {
 			SegmentConfig:     segConfig,
 		}
 
-		err := hubServer.CreateAndStartCoordinator(seg, clusterParams)
+		mock, stream := testutils.NewMockStream()
+		err := hubServer.CreateAndStartCoordinator(context.Background(), mock, seg, clusterParams)
 		if err != nil {
 			t.Fatalf("unexpected error: %#v", err)
 		}
+
+		var outputMsgs []*idl.OutputMessage
+		for _, reply := range stream.GetBuffer() {
+			if msg := reply.GetOutputMsg(); msg != nil {
+				outputMsgs = append(outputMsgs, msg)
+			}
+		}
+		if len(outputMsgs) != 1 {
+			t.Fatalf("got %+v, want a single output message", outputMsgs)
+		}
+
+		outputMsg := outputMsgs[0]
+		outputMsg.Timestamp = 0
+		expectedOutputMsg := &idl.OutputMessage{
+			Host:     "cdw",
+			Segment:  "/gpseg-1",
+			Dbid:     1,
+			Stream:   idl.OutputStream_STDOUT,
+			Sequence: 1,
+			Line:     "Success. You can now start the database server",
+		}
+		if !reflect.DeepEqual(outputMsg, expectedOutputMsg) {
+			t.Fatalf("got %+v, want %+v", outputMsg, expectedOutputMsg)
+		}
+
+		expectedStates := map[string][]idl.SegmentState{
+			"/gpseg-1": {idl.SegmentState_SEGMENT_CREATING, idl.SegmentState_SEGMENT_CREATED, idl.SegmentState_SEGMENT_STARTING, idl.SegmentState_SEGMENT_STARTED},
+		}
+		if !reflect.DeepEqual(segmentStates(stream.GetBuffer()), expectedStates) {
+			t.Fatalf("got %+v, want %+v", segmentStates(stream.GetBuffer()), expectedStates)
+		}
 	})
 
 	t.Run("when fails to create the coordinator segment", func(t *testing.T) {
@@ -278,14 +326,14 @@ func TestCreateSegments(t *testing.T) {
 
 		expectedErr := errors.New("error")
 		cdw := mock_idl.NewMockAgentClient(ctrl)
-		cdw.EXPECT().MakeSegment(
+		cdw.EXPECT().MakeSegmentStream(
 			gomock.Any(),
 			&idl.MakeSegmentRequest{
 				Segment:          seg,
 				SegConfig:        expectedSegConfig,
 				CoordinatorAddrs: make([]string, 0),
 			},
-		).Return(&idl.MakeSegmentReply{}, expectedErr)
+		).Return(nil, expectedErr)
 
 		agentConns := []*hub.Connection{
 			{AgentClient: cdw, Hostname: "cdw"},
@@ -298,7 +346,8 @@ func TestCreateSegments(t *testing.T) {
 			SegmentConfig:     segConfig,
 		}
 
-		err := hubServer.CreateAndStartCoordinator(seg, clusterParams)
+		mock, _ := testutils.NewMockStream()
+		err := hubServer.CreateAndStartCoordinator(context.Background(), mock, seg, clusterParams)
 		if !errors.Is(err, expectedErr) {
 			t.Fatalf("got %#v, want %#v", err, expectedErr)
 		}
@@ -310,13 +359,13 @@ func TestStopCoordinator(t *testing.T) {
 
 	credentials := &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()}
 	hubConfig := &hub.Config{
-		1234,
-		5678,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        1234,
+		AgentPort:   5678,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      t.TempDir(),
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 	hubServer := hub.New(hubConfig, nil)
 
@@ -339,7 +388,7 @@ func TestStopCoordinator(t *testing.T) {
 		defer utils.ResetSystemFunctions()
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.StopCoordinator(mock, "gpseg-1")
+		err := hubServer.StopCoordinator(context.Background(), mock, "gpseg-1")
 		if err != nil {
 			t.Fatalf("unexpected error: %#v", err)
 		}
@@ -379,7 +428,7 @@ func TestStopCoordinator(t *testing.T) {
 		mock, stream := testutils.NewMockStream()
 		expectedErrPrefix := "executing pg_ctl stop:"
 
-		err := hubServer.StopCoordinator(mock, "gpseg-1")
+		err := hubServer.StopCoordinator(context.Background(), mock, "gpseg-1")
 		if !strings.HasPrefix(err.Error(), expectedErrPrefix) {
 			t.Fatalf("got %v, want prefix %v", err, expectedErrPrefix)
 		}
@@ -411,13 +460,13 @@ func TestValidateEnvironment(t *testing.T) {
 
 	credentials := &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()}
 	hubConfig := &hub.Config{
-		1234,
-		5678,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        1234,
+		AgentPort:   5678,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      t.TempDir(),
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 	hubServer := hub.New(hubConfig, nil)
 
@@ -483,6 +532,10 @@ func TestValidateEnvironment(t *testing.T) {
 				Forced:          false,
 			},
 		).Return(&idl.ValidateHostEnvReply{}, nil)
+		cdw.EXPECT().PrepareDirectories(
+			gomock.Any(),
+			&idl.PrepareDirectoriesRequest{DataDirectories: []string{segs[0].DataDir}},
+		).Return(&idl.PrepareDirectoriesReply{}, nil)
 
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
 		sdw1.EXPECT().ValidateHostEnv(
@@ -502,6 +555,10 @@ func TestValidateEnvironment(t *testing.T) {
 				},
 			},
 		}, nil)
+		sdw1.EXPECT().PrepareDirectories(
+			gomock.Any(),
+			&idl.PrepareDirectoriesRequest{DataDirectories: []string{segs[1].DataDir}},
+		).Return(&idl.PrepareDirectoriesReply{}, nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
 		sdw2.EXPECT().ValidateHostEnv(
@@ -514,6 +571,15 @@ func TestValidateEnvironment(t *testing.T) {
 				Forced:          false,
 			},
 		).Return(&idl.ValidateHostEnvReply{}, nil)
+		sdw2.EXPECT().PrepareDirectories(
+			gomock.Any(),
+			&idl.PrepareDirectoriesRequest{DataDirectories: []string{segs[2].DataDir, segs[3].DataDir}},
+		).Return(&idl.PrepareDirectoriesReply{
+			Directories: []*idl.DirectoryInfo{
+				{DataDirectory: segs[2].DataDir, MountPoint: "/data1", Filesystem: "xfs"},
+				{DataDirectory: segs[3].DataDir, MountPoint: "/data2", Filesystem: "xfs"},
+			},
+		}, nil)
 
 		agentConns := []*hub.Connection{
 			{AgentClient: cdw, Hostname: "cdw"},
@@ -539,7 +605,7 @@ func TestValidateEnvironment(t *testing.T) {
 		defer utils.ResetSystemFunctions()
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.ValidateEnvironment(mock, req)
+		err := hubServer.ValidateEnvironment(context.Background(), mock, req)
 		if err != nil {
 			t.Fatalf("unexpected error: %#v", err)
 		}
@@ -553,8 +619,10 @@ func TestValidateEnvironment(t *testing.T) {
 			expectedStreamResponse[i] = &idl.HubReply{
 				Message: &idl.HubReply_ProgressMsg{
 					ProgressMsg: &idl.ProgressMessage{
-						Label: "Validating Hosts:",
-						Total: 3,
+						Stage:   hub.StageValidateHosts,
+						Label:   "Validating Hosts:",
+						Current: int32(i),
+						Total:   3,
 					},
 				},
 			}
@@ -579,7 +647,7 @@ func TestValidateEnvironment(t *testing.T) {
 		defer utils.ResetSystemFunctions()
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.ValidateEnvironment(mock, req)
+		err := hubServer.ValidateEnvironment(context.Background(), mock, req)
 
 		expectedErrPrefix := "fetching postgres gp-version:"
 		if !strings.HasPrefix(err.Error(), expectedErrPrefix) {
@@ -607,6 +675,10 @@ func TestValidateEnvironment(t *testing.T) {
 			gomock.Any(),
 			gomock.Any(),
 		).Return(&idl.ValidateHostEnvReply{}, nil)
+		cdw.EXPECT().PrepareDirectories(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.PrepareDirectoriesReply{}, nil)
 
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
 		sdw1.EXPECT().ValidateHostEnv(
@@ -619,6 +691,10 @@ func TestValidateEnvironment(t *testing.T) {
 			gomock.Any(),
 			gomock.Any(),
 		).Return(&idl.ValidateHostEnvReply{}, nil)
+		sdw2.EXPECT().PrepareDirectories(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.PrepareDirectoriesReply{}, nil)
 
 		agentConns := []*hub.Connection{
 			{AgentClient: cdw, Hostname: "cdw"},
@@ -631,7 +707,7 @@ func TestValidateEnvironment(t *testing.T) {
 		defer utils.ResetSystemFunctions()
 
 		mock, stream := testutils.NewMockStream()
-		err := hubServer.ValidateEnvironment(mock, req)
+		err := hubServer.ValidateEnvironment(context.Background(), mock, req)
 
 		expectedErrPrefix := "host: sdw1"
 		if !strings.HasPrefix(err.Error(), expectedErrPrefix) {
@@ -647,8 +723,10 @@ func TestValidateEnvironment(t *testing.T) {
 			expectedStreamResponse[i] = &idl.HubReply{
 				Message: &idl.HubReply_ProgressMsg{
 					ProgressMsg: &idl.ProgressMessage{
-						Label: "Validating Hosts:",
-						Total: 3,
+						Stage:   hub.StageValidateHosts,
+						Label:   "Validating Hosts:",
+						Current: int32(i),
+						Total:   3,
 					},
 				},
 			}
@@ -658,6 +736,130 @@ func TestValidateEnvironment(t *testing.T) {
 			t.Fatalf("got %+v, want %+v", stream.GetBuffer(), expectedStreamResponse)
 		}
 	})
+
+	t.Run("prepares the data directories of the mirrors on their hosts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mirroredReq := &idl.MakeClusterRequest{
+			GpArray: &idl.GpArray{
+				Coordinator: segmentToProto(segs[0]),
+				SegmentArray: []*idl.SegmentPair{
+					{
+						Primary: segmentToProto(segs[1]),
+						Mirror:  &idl.Segment{Port: 5555, DataDirectory: "/mirror0", HostName: "sdw2", HostAddress: "sdw2-1"},
+					},
+				},
+			},
+			ClusterParams:      &idl.ClusterParams{Locale: &idl.Locale{}},
+			RefusedFilesystems: []string{"nfs"},
+		}
+
+		expected := map[string][]string{
+			"cdw":  {segs[0].DataDir},
+			"sdw1": {segs[1].DataDir},
+			"sdw2": {"/mirror0"},
+		}
+		var agentConns []*hub.Connection
+		for _, hostname := range []string{"cdw", "sdw1", "sdw2"} {
+			client := mock_idl.NewMockAgentClient(ctrl)
+			client.EXPECT().ValidateHostEnv(
+				gomock.Any(),
+				gomock.Any(),
+			).Return(&idl.ValidateHostEnvReply{}, nil)
+			client.EXPECT().PrepareDirectories(
+				gomock.Any(),
+				&idl.PrepareDirectoriesRequest{DataDirectories: expected[hostname], RefusedFilesystems: []string{"nfs"}},
+			).Return(&idl.PrepareDirectoriesReply{}, nil)
+
+			agentConns = append(agentConns, &hub.Connection{AgentClient: client, Hostname: hostname})
+		}
+		hubServer.Conns = agentConns
+
+		utils.System.ExecCommand = exectest.NewCommand(exectest.Success)
+		defer utils.ResetSystemFunctions()
+
+		mock, _ := testutils.NewMockStream()
+		err := hubServer.ValidateEnvironment(context.Background(), mock, mirroredReq)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("errors out when it fails to prepare the directories of one of the hosts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedErr := errors.New("error")
+
+		var agentConns []*hub.Connection
+		for _, hostname := range []string{"cdw", "sdw1", "sdw2"} {
+			client := mock_idl.NewMockAgentClient(ctrl)
+			client.EXPECT().ValidateHostEnv(
+				gomock.Any(),
+				gomock.Any(),
+			).Return(&idl.ValidateHostEnvReply{}, nil)
+
+			var err error
+			if hostname == "sdw2" {
+				err = expectedErr
+			}
+			client.EXPECT().PrepareDirectories(
+				gomock.Any(),
+				gomock.Any(),
+			).Return(&idl.PrepareDirectoriesReply{}, err)
+
+			agentConns = append(agentConns, &hub.Connection{AgentClient: client, Hostname: hostname})
+		}
+		hubServer.Conns = agentConns
+
+		utils.System.ExecCommand = exectest.NewCommand(exectest.Success)
+		defer utils.ResetSystemFunctions()
+
+		mock, _ := testutils.NewMockStream()
+		err := hubServer.ValidateEnvironment(context.Background(), mock, req)
+
+		expectedErrPrefix := "host: sdw2"
+		if !strings.HasPrefix(err.Error(), expectedErrPrefix) {
+			t.Fatalf("got %v, want prefix %v", err, expectedErrPrefix)
+		}
+
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %#v, want %#v", err, expectedErr)
+		}
+	})
+}
+
+func TestCheckSharedMountPoints(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	directories := []*idl.DirectoryInfo{
+		{DataDirectory: "/data1/coordinator", MountPoint: "/data1", Filesystem: "xfs"},
+		{DataDirectory: "/data1/primary1", MountPoint: "/data1", Filesystem: "xfs"},
+		{DataDirectory: "/data1/primary2", MountPoint: "/data1", Filesystem: "xfs"},
+		{DataDirectory: "/data2/primary3", MountPoint: "/data2", Filesystem: "ext4"},
+	}
+
+	t.Run("warns when primaries share a mount point", func(t *testing.T) {
+		primaryDirs := map[string]bool{"/data1/primary1": true, "/data1/primary2": true, "/data2/primary3": true}
+		warnings := hub.CheckSharedMountPoints(directories, primaryDirs)
+
+		expected := []*idl.LogMessage{{
+			Message: "primary data directories /data1/primary1, /data1/primary2 share the mount point /data1",
+			Level:   idl.LogLevel_WARNING,
+		}}
+		if !reflect.DeepEqual(warnings, expected) {
+			t.Fatalf("got %v, want %v", warnings, expected)
+		}
+	})
+
+	t.Run("does not warn when the primaries are on different mount points", func(t *testing.T) {
+		primaryDirs := map[string]bool{"/data1/primary1": true, "/data2/primary3": true}
+		warnings := hub.CheckSharedMountPoints(directories, primaryDirs)
+		if len(warnings) != 0 {
+			t.Fatalf("got %v, want no warnings", warnings)
+		}
+	})
 }
 
 func TestExecOnDatabase(t *testing.T) {
@@ -0,0 +1,125 @@
+package hub
+
+import (
+	"strconv"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+var connectivityStates = []connectivity.State{
+	connectivity.Idle,
+	connectivity.Connecting,
+	connectivity.Ready,
+	connectivity.TransientFailure,
+	connectivity.Shutdown,
+}
+
+/*
+metricsCollector reports the state of the hub connections to the agents and whether
+each segment of the managed cluster is up, as seen by the coordinator, at the
+time of the scrape.
+*/
+type metricsCollector struct {
+	server *Server
+
+	agentConnectionState *prometheus.Desc
+	segmentUp            *prometheus.Desc
+}
+
+// NewMetricsCollector returns the collector of the hub specific metrics
+func NewMetricsCollector(server *Server) prometheus.Collector {
+	return &metricsCollector{
+		server: server,
+		agentConnectionState: prometheus.NewDesc("gp_hub_agent_connection_state",
+			"State of the hub connection to the agent, 1 for the current state.",
+			[]string{"host", "state"}, nil),
+		segmentUp: prometheus.NewDesc("gp_segment_up",
+			"Whether the segment is up according to the coordinator, 1 if up and 0 if down.",
+			[]string{"content", "dbid", "role", "hostname", "datadir"}, nil),
+	}
+}
+
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.agentConnectionState
+	ch <- c.segmentUp
+}
+
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectAgentConnections(ch)
+	c.collectSegments(ch)
+}
+
+func (c *metricsCollector) collectAgentConnections(ch chan<- prometheus.Metric) {
+	// the scrapes run on the goroutines of the HTTP server, alongside the RPCs replacing the connections
+	conns, release := c.server.acquireConns()
+	defer release()
+
+	for _, conn := range conns {
+		if conn.Conn == nil {
+			continue
+		}
+
+		current := conn.Conn.GetState()
+		for _, state := range connectivityStates {
+			value := 0.0
+			if state == current {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(c.agentConnectionState, prometheus.GaugeValue, value, conn.Hostname, state.String())
+		}
+	}
+}
+
+// collectSegments reports the segments from the catalog, or the coordinator as down if the catalog can not be read
+func (c *metricsCollector) collectSegments(ch chan<- prometheus.Metric) {
+	state, err := LoadClusterState(c.server.LogDir)
+	if err != nil {
+		gplog.Verbose("not reporting the segment metrics: %v", err)
+		return
+	}
+
+	gparray, err := readGpArray(state.CoordinatorDataDir)
+	if err != nil {
+		gplog.Warn("failed to read the segment status for the metrics: %v", err)
+		if state.GpArray != nil && state.GpArray.Coordinator != nil {
+			c.reportSegment(ch, state.GpArray.Coordinator, false)
+		}
+		return
+	}
+
+	segs := gparray.GetAllSegments()
+	if gparray.Coordinator != nil {
+		segs = append(segs, *gparray.Coordinator)
+	}
+	if gparray.Standby != nil {
+		segs = append(segs, *gparray.Standby)
+	}
+
+	for i := range segs {
+		c.reportSegment(ch, &segs[i], segs[i].Status == "u")
+	}
+}
+
+func (c *metricsCollector) reportSegment(ch chan<- prometheus.Metric, seg *greenplum.Segment, up bool) {
+	value := 0.0
+	if up {
+		value = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.segmentUp, prometheus.GaugeValue, value,
+		strconv.Itoa(seg.Content), strconv.Itoa(seg.Dbid), seg.Role, seg.Hostname, seg.DataDir)
+}
+
+func readGpArray(coordinatorDataDir string) (*greenplum.GpArray, error) {
+	conn, err := greenplum.GetCoordinatorConn(coordinatorDataDir, "", true)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return greenplum.NewGpArrayFromCatalog(conn)
+}
@@ -0,0 +1,155 @@
+package hub_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	coordinator := createSegment(t, 1, -1, constants.RolePrimary, constants.RolePrimary, 7000, "cdw", "cdw", "/data/primary/gpseg-1")
+	primary1 := createSegment(t, 2, 0, constants.RolePrimary, constants.RolePrimary, 7001, "sdw1", "sdw1", "/data/primary/gpseg0")
+	primary2 := createSegment(t, 3, 1, constants.RolePrimary, constants.RolePrimary, 7002, "sdw2", "sdw2", "/data/primary/gpseg1")
+
+	newHubServer := func(t *testing.T) *hub.Server {
+		t.Helper()
+
+		return hub.New(&hub.Config{
+			LogDir:      t.TempDir(),
+			Credentials: &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()},
+		}, nil)
+	}
+
+	saveClusterState := func(t *testing.T, hubServer *hub.Server) {
+		t.Helper()
+
+		err := hubServer.SaveClusterState(&hub.ClusterState{
+			CoordinatorDataDir: coordinator.DataDir,
+			GpArray:            &greenplum.GpArray{Coordinator: coordinator},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("reports the state of the agent connections", func(t *testing.T) {
+		conn, err := grpc.Dial("localhost:0", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		conn.Close()
+
+		hubServer := newHubServer(t)
+		hubServer.Conns = []*hub.Connection{
+			{Conn: conn, Hostname: "sdw1"},
+			{Hostname: "sdw2"},
+		}
+
+		expected := `
+# HELP gp_hub_agent_connection_state State of the hub connection to the agent, 1 for the current state.
+# TYPE gp_hub_agent_connection_state gauge
+gp_hub_agent_connection_state{host="sdw1",state="CONNECTING"} 0
+gp_hub_agent_connection_state{host="sdw1",state="IDLE"} 0
+gp_hub_agent_connection_state{host="sdw1",state="READY"} 0
+gp_hub_agent_connection_state{host="sdw1",state="SHUTDOWN"} 1
+gp_hub_agent_connection_state{host="sdw1",state="TRANSIENT_FAILURE"} 0
+`
+		err = testutil.CollectAndCompare(hub.NewMetricsCollector(hubServer), strings.NewReader(expected), "gp_hub_agent_connection_state")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("reports the status of the segments from the coordinator catalog", func(t *testing.T) {
+		hubServer := newHubServer(t)
+		saveClusterState(t, hubServer)
+
+		utils.System.Open = func(name string) (*os.File, error) {
+			reader, writer, _ := os.Pipe()
+			defer writer.Close()
+
+			_, err := writer.WriteString("port=7000")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			return reader, nil
+		}
+		defer utils.ResetSystemFunctions()
+
+		greenplum.SetNewDBConnFromEnvironment(func(dbname string) *dbconn.DBConn {
+			conn, mock := testutils.CreateMockDBConnForUtilityMode(t)
+			testhelper.ExpectVersionQuery(mock, "7.0.0")
+
+			rows := sqlmock.NewRows([]string{"dbid", "content", "role", "preferredrole", "status", "port", "hostname", "address", "datadir"})
+			for _, seg := range []*greenplum.Segment{coordinator, primary1, primary2} {
+				status := "u"
+				if seg == primary2 {
+					status = "d"
+				}
+				rows.AddRow(seg.Dbid, seg.Content, seg.Role, seg.PreferredRole, status, seg.Port, seg.Hostname, seg.Address, seg.DataDir)
+			}
+			mock.ExpectQuery("SELECT").WillReturnRows(rows)
+
+			return conn
+		})
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		expected := `
+# HELP gp_segment_up Whether the segment is up according to the coordinator, 1 if up and 0 if down.
+# TYPE gp_segment_up gauge
+gp_segment_up{content="-1",datadir="/data/primary/gpseg-1",dbid="1",hostname="cdw",role="p"} 1
+gp_segment_up{content="0",datadir="/data/primary/gpseg0",dbid="2",hostname="sdw1",role="p"} 1
+gp_segment_up{content="1",datadir="/data/primary/gpseg1",dbid="3",hostname="sdw2",role="p"} 0
+`
+		err := testutil.CollectAndCompare(hub.NewMetricsCollector(hubServer), strings.NewReader(expected), "gp_segment_up")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("reports the coordinator as down when not able to read the catalog", func(t *testing.T) {
+		hubServer := newHubServer(t)
+		saveClusterState(t, hubServer)
+
+		utils.System.Open = func(name string) (*os.File, error) {
+			return nil, errors.New("error")
+		}
+		defer utils.ResetSystemFunctions()
+
+		expected := `
+# HELP gp_segment_up Whether the segment is up according to the coordinator, 1 if up and 0 if down.
+# TYPE gp_segment_up gauge
+gp_segment_up{content="-1",datadir="/data/primary/gpseg-1",dbid="1",hostname="cdw",role="p"} 0
+`
+		err := testutil.CollectAndCompare(hub.NewMetricsCollector(hubServer), strings.NewReader(expected), "gp_segment_up")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("does not report the segments when the hub does not manage a cluster", func(t *testing.T) {
+		hubServer := newHubServer(t)
+
+		count := testutil.CollectAndCount(hub.NewMetricsCollector(hubServer), "gp_segment_up")
+		if count != 0 {
+			t.Fatalf("got %d, want 0", count)
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+// number of completed operations kept around to be listed and attached to
+var MaxCompletedOperations = 20
+
+// Common interface for the streams the replies of an operation are sent on
+type operationStream interface {
+	Send(*idl.HubReply) error
+	Context() context.Context
+}
+
+/*
+Operation is a long-running streaming RPC of the hub. It runs independently of
+the stream which started it, so that it is neither abandoned halfway nor left
+without a way to follow it if the CLI goes away. Its replies are buffered, and
+any number of streams can be attached to it to replay and follow them.
+*/
+type Operation struct {
+	ID        string
+	Method    string
+	StartTime time.Time
+
+	cancel context.CancelFunc
+	caller LockCaller
+	events *EventStore
+
+	mutex   sync.Mutex
+	replies []*idl.HubReply
+	updated chan struct{} // closed whenever a reply is added or the operation completes
+	state   idl.OperationState
+	endTime time.Time
+	err     error
+}
+
+// Send buffers the reply of the operation for the attached streams, and records it in the event store
+func (o *Operation) Send(reply *idl.HubReply) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.replies = append(o.replies, reply)
+	o.record(newEventRecord(reply))
+	o.notify()
+
+	return nil
+}
+
+// record keeps the event of the operation in the event store, must be called with the mutex held
+func (o *Operation) record(record *EventRecord) {
+	if record == nil {
+		return
+	}
+
+	record.OperationID = o.ID
+	record.Method = o.Method
+	o.events.Record(record)
+}
+
+// notify wakes up the attached streams, must be called with the mutex held
+func (o *Operation) notify() {
+	close(o.updated)
+	o.updated = make(chan struct{})
+}
+
+func (o *Operation) complete(ctx context.Context, err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	switch {
+	case err == nil:
+		o.state = idl.OperationState_SUCCEEDED
+	case ctx.Err() != nil:
+		o.state = idl.OperationState_CANCELLED
+	default:
+		o.state = idl.OperationState_FAILED
+	}
+	o.endTime = time.Now()
+	o.err = err
+	gplog.Info("Operation %s (%s) completed with state %s", o.ID, o.Method, o.state)
+
+	record := &EventRecord{Time: o.endTime.UTC(), Type: EventTypeOperation, Caller: o.caller.Holder, ClaimedBy: o.caller.ClaimedBy, State: strings.ToLower(o.state.String())}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	o.record(record)
+	o.notify()
+}
+
+func (o *Operation) isRunning() bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.state == idl.OperationState_RUNNING
+}
+
+/*
+Attach sends the ID of the operation followed by all of its replies to the
+stream, until the operation completes or the stream goes away. It returns the
+error of the operation, if any.
+*/
+func (o *Operation) Attach(stream operationStream) error {
+	err := stream.Send(&idl.HubReply{Message: &idl.HubReply_OperationId{OperationId: o.ID}})
+	if err != nil {
+		return err
+	}
+
+	next := 0
+	for {
+		o.mutex.Lock()
+		replies := o.replies[next:]
+		running, opErr, updated := o.state == idl.OperationState_RUNNING, o.err, o.updated
+		o.mutex.Unlock()
+
+		for _, reply := range replies {
+			err := stream.Send(reply)
+			if err != nil {
+				return err
+			}
+		}
+		next += len(replies)
+
+		if !running {
+			return opErr
+		}
+
+		select {
+		case <-updated:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// Cancel stops the operation, cancelling the in-flight agent RPCs and commands
+func (o *Operation) Cancel() {
+	o.cancel()
+}
+
+func (o *Operation) Info() *idl.Operation {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	info := &idl.Operation{
+		Id:        o.ID,
+		Method:    o.Method,
+		State:     o.state,
+		StartTime: o.StartTime.Unix(),
+	}
+	if !o.endTime.IsZero() {
+		info.EndTime = o.endTime.Unix()
+	}
+	if o.err != nil {
+		info.Error = o.err.Error()
+	}
+
+	return info
+}
+
+// OperationRegistry keeps track of the running operations and of the recently completed ones
+type OperationRegistry struct {
+	events *EventStore // where the events of the operations are recorded, they are dropped if nil
+
+	mutex      sync.Mutex
+	operations []*Operation
+}
+
+func NewOperationRegistry(events *EventStore) *OperationRegistry {
+	return &OperationRegistry{events: events}
+}
+
+/*
+Start runs the function as a new operation in the background, whose last reply
+is the summary of its result. The operation inherits the values of the context,
+like the trace, the identity of the caller and the operation ID under which the
+call is audited, but is only cancelled through Cancel.
+*/
+func (r *OperationRegistry) Start(ctx context.Context, method string, fn func(ctx context.Context, stream *HubStream) error) *Operation {
+	caller, _ := lockCaller(ctx)
+	id := utils.GetOperationID(ctx)
+	if id == "" {
+		id = utils.NewOperationID()
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	op := &Operation{
+		ID:        id,
+		Method:    method,
+		StartTime: time.Now(),
+		cancel:    cancel,
+		caller:    caller,
+		events:    r.events,
+		updated:   make(chan struct{}),
+	}
+	op.record(&EventRecord{Time: op.StartTime.UTC(), Type: EventTypeOperation, Caller: caller.Holder, ClaimedBy: caller.ClaimedBy, State: strings.ToLower(idl.OperationState_RUNNING.String())})
+	r.add(op)
+
+	go func() {
+		defer cancel()
+
+		hubStream := NewHubStream(op)
+		err := fn(context.WithValue(ctx, operationKey{}, op), &hubStream)
+		hubStream.StreamResultMsg(err, time.Since(op.StartTime))
+		op.complete(ctx, err)
+	}()
+
+	return op
+}
+
+type operationKey struct{}
+
+// operationFrom returns the operation run with the context, if any
+func operationFrom(ctx context.Context) *Operation {
+	op, _ := ctx.Value(operationKey{}).(*Operation)
+
+	return op
+}
+
+// add registers the operation, forgetting the oldest completed operations beyond MaxCompletedOperations
+func (r *OperationRegistry) add(op *Operation) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	completed := 0
+	for i := len(r.operations) - 1; i >= 0; i-- {
+		if r.operations[i].isRunning() {
+			continue
+		}
+
+		completed++
+		if completed > MaxCompletedOperations {
+			r.operations = append(r.operations[:i], r.operations[i+1:]...)
+		}
+	}
+
+	r.operations = append(r.operations, op)
+}
+
+func (r *OperationRegistry) Get(id string) (*Operation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, op := range r.operations {
+		if op.ID == id {
+			return op, nil
+		}
+	}
+
+	return nil, grpcStatus.Errorf(codes.NotFound, "operation %s not found", id)
+}
+
+// List returns the operations in the order they were started
+func (r *OperationRegistry) List() []*Operation {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]*Operation(nil), r.operations...)
+}
+
+/*
+runOperation runs a streaming RPC as an operation holding the cluster lock,
+limited to the timeout of the RPC and to the worker pool of its caller, and
+follows it on the stream.
+*/
+func (s *Server) runOperation(stream operationStream, method string, fn func(ctx context.Context, stream *HubStream) error) error {
+	hubStream := NewHubStream(stream)
+	lock, err := s.acquireLock(stream.Context(), method, func(held *LockInfo) {
+		hubStream.StreamLogMsg(fmt.Sprintf("Waiting for the cluster lock held by %s", held))
+	})
+	if err != nil {
+		return err
+	}
+
+	op := s.operations.Start(stream.Context(), method, func(ctx context.Context, hubStream *HubStream) error {
+		// released before the operation completes, so that its caller can carry on right away
+		defer lock.Release()
+
+		ctx, cancel := s.withTimeout(ctx, method)
+		defer cancel()
+
+		ctx = WithRetryReporter(WithWorkerPool(ctx, s.workerPoolFor(ctx)), func(message string) {
+			hubStream.StreamLogMsg(message, idl.LogLevel_WARNING)
+		})
+
+		return fn(ctx, hubStream)
+	})
+	lock.SetOperationID(op.ID)
+	gplog.Info("Started operation %s (%s)", op.ID, method)
+
+	return op.Attach(stream)
+}
+
+func (s *Server) ListOperations(ctx context.Context, request *idl.ListOperationsRequest) (*idl.ListOperationsReply, error) {
+	var operations []*idl.Operation
+	for _, op := range s.operations.List() {
+		operations = append(operations, op.Info())
+	}
+
+	return &idl.ListOperationsReply{Operations: operations}, nil
+}
+
+func (s *Server) AttachOperation(request *idl.AttachOperationRequest, stream idl.Hub_AttachOperationServer) error {
+	op, err := s.operations.Get(request.Id)
+	if err != nil {
+		return err
+	}
+
+	return op.Attach(stream)
+}
+
+func (s *Server) CancelOperation(ctx context.Context, request *idl.CancelOperationRequest) (*idl.CancelOperationReply, error) {
+	op, err := s.operations.Get(request.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !op.isRunning() {
+		return nil, grpcStatus.Error(codes.FailedPrecondition, fmt.Sprintf("operation %s has already completed", op.ID))
+	}
+
+	gplog.Info("Cancelling operation %s (%s)", op.ID, op.Method)
+	op.Cancel()
+
+	return &idl.CancelOperationReply{}, nil
+}
@@ -0,0 +1,290 @@
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+type attachedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+
+	mutex   sync.Mutex
+	replies []*idl.HubReply
+}
+
+func newAttachedStream(ctx context.Context) *attachedStream {
+	return &attachedStream{ctx: ctx}
+}
+
+func (s *attachedStream) Send(reply *idl.HubReply) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.replies = append(s.replies, reply)
+	return nil
+}
+
+func (s *attachedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *attachedStream) messages() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var messages []string
+	for _, reply := range s.replies {
+		switch msg := reply.Message.(type) {
+		case *idl.HubReply_OperationId:
+			messages = append(messages, "operation")
+		case *idl.HubReply_LogMsg:
+			messages = append(messages, msg.LogMsg.Message)
+		}
+	}
+
+	return messages
+}
+
+func TestOperationRegistry(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("replays the replies of the operation to every attached stream", func(t *testing.T) {
+		registry := hub.NewOperationRegistry(nil)
+
+		release := make(chan struct{})
+		expectedErr := errors.New("error")
+		op := registry.Start(context.Background(), "MakeCluster", func(ctx context.Context, stream *hub.HubStream) error {
+			stream.StreamLogMsg("first")
+			<-release
+			stream.StreamLogMsg("second")
+			return expectedErr
+		})
+
+		if op.Info().State != idl.OperationState_RUNNING {
+			t.Fatalf("got %v, want %v", op.Info().State, idl.OperationState_RUNNING)
+		}
+
+		stream := newAttachedStream(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- op.Attach(stream)
+		}()
+		close(release)
+
+		err := <-done
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+
+		// attaching after the operation has completed replays all of its replies
+		lateStream := newAttachedStream(context.Background())
+		err = op.Attach(lateStream)
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+
+		expected := []string{"operation", "first", "second"}
+		for _, s := range []*attachedStream{stream, lateStream} {
+			if !reflect.DeepEqual(s.messages(), expected) {
+				t.Fatalf("got %v, want %v", s.messages(), expected)
+			}
+		}
+		if s := stream.replies[0].GetOperationId(); s != op.ID {
+			t.Fatalf("got operation %s, want %s", s, op.ID)
+		}
+
+		info := op.Info()
+		if info.State != idl.OperationState_FAILED || info.Method != "MakeCluster" || info.Error != expectedErr.Error() || info.EndTime == 0 {
+			t.Fatalf("unexpected operation %+v", info)
+		}
+	})
+
+	t.Run("uses the operation ID under which the call is audited", func(t *testing.T) {
+		registry := hub.NewOperationRegistry(nil)
+
+		ctx := utils.WithOperationID(context.Background(), "1234")
+		op := registry.Start(ctx, "MakeCluster", func(ctx context.Context, stream *hub.HubStream) error {
+			return nil
+		})
+
+		if op.ID != "1234" {
+			t.Fatalf("got operation %s, want 1234", op.ID)
+		}
+	})
+
+	t.Run("cancels the context of the operation", func(t *testing.T) {
+		registry := hub.NewOperationRegistry(nil)
+
+		op := registry.Start(context.Background(), "AddMirrors", func(ctx context.Context, stream *hub.HubStream) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		op.Cancel()
+
+		err := op.Attach(newAttachedStream(context.Background()))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want %v", err, context.Canceled)
+		}
+
+		if op.Info().State != idl.OperationState_CANCELLED {
+			t.Fatalf("got %v, want %v", op.Info().State, idl.OperationState_CANCELLED)
+		}
+	})
+
+	t.Run("keeps running the operation when the stream goes away", func(t *testing.T) {
+		registry := hub.NewOperationRegistry(nil)
+
+		startCtx, cancelStart := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		op := registry.Start(startCtx, "MakeCluster", func(ctx context.Context, stream *hub.HubStream) error {
+			<-release
+			return ctx.Err()
+		})
+		defer close(release)
+
+		cancelStart()
+		err := op.Attach(newAttachedStream(startCtx))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want %v", err, context.Canceled)
+		}
+
+		if op.Info().State != idl.OperationState_RUNNING {
+			t.Fatalf("got %v, want %v", op.Info().State, idl.OperationState_RUNNING)
+		}
+	})
+
+	t.Run("forgets the oldest completed operations", func(t *testing.T) {
+		hub.MaxCompletedOperations = 2
+		defer func() {
+			hub.MaxCompletedOperations = 20
+		}()
+
+		registry := hub.NewOperationRegistry(nil)
+
+		var ids []string
+		for i := 0; i < 4; i++ {
+			op := registry.Start(context.Background(), "AddMirrors", func(ctx context.Context, stream *hub.HubStream) error {
+				return nil
+			})
+			_ = op.Attach(newAttachedStream(context.Background()))
+			ids = append(ids, op.ID)
+		}
+
+		var listed []string
+		for _, op := range registry.List() {
+			listed = append(listed, op.ID)
+		}
+		if !reflect.DeepEqual(listed, ids[1:]) {
+			t.Fatalf("got %v, want %v", listed, ids[1:])
+		}
+
+		_, err := registry.Get(ids[0])
+		if grpcStatus.Code(err) != codes.NotFound {
+			t.Fatalf("got %v, want %v", err, codes.NotFound)
+		}
+	})
+}
+
+func TestOperationRPCs(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	expectedErr := errors.New("error")
+	hubServer := hub.New(&hub.Config{
+		Hostnames:   []string{"sdw1"},
+		LogDir:      t.TempDir(),
+		Credentials: &testutils.MockCredentials{Err: expectedErr},
+	}, nil)
+
+	t.Run("runs the streaming RPCs as operations", func(t *testing.T) {
+		stream := newAttachedStream(context.Background())
+		err := hubServer.AddMirrors(&idl.AddMirrorsRequest{}, stream)
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+
+		reply, err := hubServer.ListOperations(context.Background(), &idl.ListOperationsRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(reply.Operations) != 1 {
+			t.Fatalf("got %d operations, want 1", len(reply.Operations))
+		}
+		op := reply.Operations[0]
+		if op.Id != stream.replies[0].GetOperationId() || op.Method != "AddMirrors" || op.State != idl.OperationState_FAILED {
+			t.Fatalf("unexpected operation %+v", op)
+		}
+
+		followingStream := newAttachedStream(context.Background())
+		err = hubServer.AttachOperation(&idl.AttachOperationRequest{Id: op.Id}, followingStream)
+		if err == nil || err.Error() != expectedErr.Error() {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+		if !reflect.DeepEqual(followingStream.messages(), stream.messages()) {
+			t.Fatalf("got %v, want %v", followingStream.messages(), stream.messages())
+		}
+
+		_, err = hubServer.CancelOperation(context.Background(), &idl.CancelOperationRequest{Id: op.Id})
+		if grpcStatus.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("got %v, want %v", err, codes.FailedPrecondition)
+		}
+	})
+
+	t.Run("records the events of the operations", func(t *testing.T) {
+		reply, err := hubServer.ListEvents(context.Background(), &idl.ListEventsRequest{OperationsOnly: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var states []string
+		for _, event := range reply.Events {
+			if event.Method != "AddMirrors" || event.Caller != "unknown" {
+				t.Fatalf("unexpected event %+v", event)
+			}
+			states = append(states, event.State)
+		}
+		expected := []string{"running", "failed"}
+		if !reflect.DeepEqual(states, expected) {
+			t.Fatalf("got %v, want %v", states, expected)
+		}
+		if reply.Events[1].Error != expectedErr.Error() {
+			t.Fatalf("got error %q, want %q", reply.Events[1].Error, expectedErr)
+		}
+
+		reply, err = hubServer.ListEvents(context.Background(), &idl.ListEventsRequest{OperationId: reply.Events[0].OperationId})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		last := reply.Events[len(reply.Events)-1]
+		if last.Type != hub.EventTypeOperation || reply.Events[len(reply.Events)-2].Type != hub.EventTypeResult {
+			t.Fatalf("got %+v, want the result followed by the end of the operation", reply.Events)
+		}
+	})
+
+	t.Run("errors out when the operation does not exist", func(t *testing.T) {
+		err := hubServer.AttachOperation(&idl.AttachOperationRequest{Id: "unknown"}, newAttachedStream(context.Background()))
+		expected := "rpc error: code = NotFound desc = operation unknown not found"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		_, err = hubServer.CancelOperation(context.Background(), &idl.CancelOperationRequest{Id: "unknown"})
+		if grpcStatus.Code(err) != codes.NotFound {
+			t.Fatalf("got %v, want %v", err, codes.NotFound)
+		}
+	})
+}
@@ -2,18 +2,22 @@ package hub
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"sync"
 
 	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
 	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
 )
 
 // UpdatePgHbaConfWithMirrorEntries updates the pg_hba.conf file on the primary segments
 // with the details of its corresponding mirror segment pair. The hbaHostname parameter
 // determines whether to use hostnames or IP addresses in the pg_hba.conf file.
-func (s *Server) UpdatePgHbaConfWithMirrorEntries(gparray *greenplum.GpArray, mirrorSegs []*idl.Segment, hbaHostname bool) error {
+func (s *Server) UpdatePgHbaConfWithMirrorEntries(ctx context.Context, gparray *greenplum.GpArray, mirrorSegs []*idl.Segment, hbaHostname bool) (err error) {
+	ctx, span := utils.StartSpan(ctx, "UpdatePgHbaConfWithMirrorEntries")
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
 	primaryHostToSegPairMap := make(map[string][]*greenplum.SegmentPair)
 	for _, seg := range mirrorSegs {
 		pair, err := gparray.GetSegmentPairForContent(int(seg.Contentid))
@@ -24,80 +28,62 @@ func (s *Server) UpdatePgHbaConfWithMirrorEntries(gparray *greenplum.GpArray, mi
 		primaryHostToSegPairMap[pair.Primary.Hostname] = append(primaryHostToSegPairMap[pair.Primary.Hostname], pair)
 	}
 
-	request := func(conn *Connection) error {
-		var wg sync.WaitGroup
-
-		pairs := primaryHostToSegPairMap[conn.Hostname]
-		errs := make(chan error, len(pairs))
-		for _, pair := range pairs {
-			pair := pair
-			wg.Add(1)
-
-			go func(pair *greenplum.SegmentPair) {
-				var addrs []string
-				var err error
-				defer wg.Done()
-
-				if hbaHostname {
-					addrs = []string{pair.Primary.Address, pair.Mirror.Address}
-				} else {
-					primaryAddrs, err := s.GetInterfaceAddrs(pair.Primary.Hostname)
-					if err != nil {
-						errs <- err
-						return
-					}
-
-					mirrorAddrs, err := s.GetInterfaceAddrs(pair.Mirror.Hostname)
-					if err != nil {
-						errs <- err
-						return
-					}
-
-					addrs = append(primaryAddrs, mirrorAddrs...)
-				}
-
-				_, err = conn.AgentClient.UpdatePgHbaConfAndReload(context.Background(), &idl.UpdatePgHbaConfRequest{
-					Pgdata:      pair.Primary.DataDir,
-					Addrs:       addrs,
-					Replication: true,
-				})
-				if err != nil {
-					errs <- err
-				}
-			}(pair)
+	request := func(ctx context.Context, conn *Connection, pair *greenplum.SegmentPair) error {
+		var addrs []string
+		if hbaHostname {
+			addrs = []string{pair.Primary.Address, pair.Mirror.Address}
+		} else {
+			// the addresses are fetched from within a request of the worker pool, which must not wait for the pool again
+			lookupCtx := WithWorkerPool(ctx, unlimitedWorkerPool)
+
+			primaryAddrs, err := s.GetInterfaceAddrs(lookupCtx, pair.Primary.Hostname)
+			if err != nil {
+				return err
+			}
+
+			mirrorAddrs, err := s.GetInterfaceAddrs(lookupCtx, pair.Mirror.Hostname)
+			if err != nil {
+				return err
+			}
+
+			addrs = append(primaryAddrs, mirrorAddrs...)
 		}
 
-		wg.Wait()
-		close(errs)
-
-		var err error
-		for e := range errs {
-			err = errors.Join(err, e)
-		}
+		_, err := conn.AgentClient.UpdatePgHbaConfAndReload(ctx, &idl.UpdatePgHbaConfRequest{
+			Pgdata:      pair.Primary.DataDir,
+			Addrs:       addrs,
+			Replication: true,
+		})
 
-		return err
+		return utils.NewHostError("UpdatePgHbaConfAndReload", pair.Primary.DataDir, err)
 	}
 
-	return ExecuteRPC(s.Conns, request)
+	conns, release := s.acquireConns()
+	defer release()
+
+	return ExecuteRPCForEach(ctx, conns, primaryHostToSegPairMap, request)
 }
 
 // GetInterfaceAddrs returns the interface addresses for a given host.
 // It retrieves the interface addresses by executing an RPC call to the agent client.
-func (s *Server) GetInterfaceAddrs(host string) ([]string, error) {
-	conns := getConnForHosts(s.Conns, []string{host})
+func (s *Server) GetInterfaceAddrs(ctx context.Context, host string) ([]string, error) {
+	allConns, release := s.acquireConns()
+	defer release()
+
+	conns := getConnForHosts(allConns, []string{host})
 
 	var addrs []string
-	request := func(conn *Connection) error {
-		resp, err := conn.AgentClient.GetInterfaceAddrs(context.Background(), &idl.GetInterfaceAddrsRequest{})
+	request := func(ctx context.Context, conn *Connection) error {
+		resp, err := conn.AgentClient.GetInterfaceAddrs(ctx, &idl.GetInterfaceAddrsRequest{})
 		if err != nil {
-			return fmt.Errorf("failed to get interface addresses for host %s: %w", conn.Hostname, err)
+			return utils.NewHostError("GetInterfaceAddrs", "", fmt.Errorf("failed to get interface addresses for host %s: %w", conn.Hostname, err))
 		}
 		addrs = resp.Addrs
 
 		return nil
 	}
 
-	err := ExecuteRPC(conns, request)
+	err := ExecuteRPC(ctx, conns, request)
 
 	return addrs, err
 }
@@ -0,0 +1,127 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+// RetryPolicy tells how many times an agent RPC is attempted when it fails with a transient error
+type RetryPolicy struct {
+	Attempts      int `json:"attempts"`      // including the first one, 1 disables the retries
+	BackoffMillis int `json:"backoffMillis"` // delay before the first retry, doubled after each one
+}
+
+// retry policies of the agent RPCs which are retried unless configured otherwise
+var DefaultRetryPolicies = map[string]RetryPolicy{
+	"Status":            {Attempts: 3, BackoffMillis: 500},
+	"GetInterfaceAddrs": {Attempts: 3, BackoffMillis: 500},
+	"GetHostName":       {Attempts: 3, BackoffMillis: 500},
+	"UpdatePgConf":      {Attempts: 3, BackoffMillis: 500},
+	"PgBasebackup":      {Attempts: 2, BackoffMillis: 5000},
+}
+
+/*
+idempotentRequests tells which requests of the agent RPCs can be sent again as
+they are. PgBasebackup is not, as the failed attempt has to be cleaned up
+first, so it is retried by its caller instead.
+*/
+var idempotentRequests = map[string]func(request interface{}) bool{
+	"Status":            func(interface{}) bool { return true },
+	"GetInterfaceAddrs": func(interface{}) bool { return true },
+	"GetHostName":       func(interface{}) bool { return true },
+	"UpdatePgConf": func(request interface{}) bool {
+		req, ok := request.(*idl.UpdatePgConfRequest)
+		return ok && req.Overwrite
+	},
+}
+
+// isTransient tells whether the failure may go away by itself, e.g. when the connection to the agent dropped
+func isTransient(err error) bool {
+	switch grpcStatus.Code(err) {
+	case codes.Unavailable, codes.Aborted:
+		return true
+	}
+
+	return false
+}
+
+// RetryPolicy returns the retry policy of the agent RPC, the configured one taking precedence over DefaultRetryPolicies
+func (conf *Config) RetryPolicy(method string) RetryPolicy {
+	policy, ok := conf.Retries[method]
+	if !ok {
+		policy, ok = DefaultRetryPolicies[method]
+	}
+	if !ok || policy.Attempts < 1 {
+		policy.Attempts = 1
+	}
+
+	return policy
+}
+
+type retryReporterKey struct{}
+
+// WithRetryReporter makes the retries of the agent RPCs made with the context reported to the given function
+func WithRetryReporter(ctx context.Context, report func(message string)) context.Context {
+	return context.WithValue(ctx, retryReporterKey{}, report)
+}
+
+/*
+retry runs the request of the agent RPC to the host until it succeeds, fails
+with an error which is not transient or runs out of attempts, following the
+retry policy of the RPC. Every retry is logged, and reported to the caller of
+the hub RPC if it asked for it.
+*/
+func (conf *Config) retry(ctx context.Context, method string, host string, request func(attempt int) error) error {
+	policy := conf.RetryPolicy(method)
+	backoff := time.Duration(policy.BackoffMillis) * time.Millisecond
+
+	for attempt := 1; ; attempt++ {
+		err := request(attempt)
+		if err == nil || attempt >= policy.Attempts || !isTransient(err) {
+			return err
+		}
+
+		message := fmt.Sprintf("%s on host %s failed, retrying in %s (attempt %d of %d): %s", method, host, backoff, attempt+1, policy.Attempts, grpcStatus.Convert(err).Message())
+		gplog.Warn(message)
+		if report, ok := ctx.Value(retryReporterKey{}).(func(string)); ok {
+			report(message)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// newRetryInterceptor retries the idempotent requests to the agents following their retry policy
+func (conf *Config) newRetryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		name := path.Base(method)
+		idempotent, ok := idempotentRequests[name]
+		if !ok || !idempotent(req) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		host, _, err := net.SplitHostPort(cc.Target())
+		if err != nil {
+			host = cc.Target()
+		}
+
+		return conf.retry(ctx, name, host, func(int) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package hub_test
+
+import (
+	"context"
+	"log"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+)
+
+// flakyAgent fails the first calls of its RPCs with the given error
+type flakyAgent struct {
+	idl.UnimplementedAgentServer
+
+	mutex    sync.Mutex
+	failures int
+	err      error
+	calls    int
+}
+
+func (a *flakyAgent) call() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.calls++
+	if a.calls <= a.failures {
+		return a.err
+	}
+
+	return nil
+}
+
+func (a *flakyAgent) GetHostName(ctx context.Context, request *idl.GetHostNameRequest) (*idl.GetHostNameReply, error) {
+	if err := a.call(); err != nil {
+		return nil, err
+	}
+
+	return &idl.GetHostNameReply{Hostname: "sdw1"}, nil
+}
+
+func (a *flakyAgent) UpdatePgConf(ctx context.Context, request *idl.UpdatePgConfRequest) (*idl.UpdatePgConfRespoonse, error) {
+	if err := a.call(); err != nil {
+		return nil, err
+	}
+
+	return &idl.UpdatePgConfRespoonse{}, nil
+}
+
+func TestRetryPolicy(t *testing.T) {
+	conf := &hub.Config{
+		Retries: map[string]hub.RetryPolicy{
+			"PgBasebackup": {Attempts: 5, BackoffMillis: 100},
+		},
+	}
+
+	cases := []struct {
+		method   string
+		expected hub.RetryPolicy
+	}{
+		{"PgBasebackup", hub.RetryPolicy{Attempts: 5, BackoffMillis: 100}},
+		{"Status", hub.DefaultRetryPolicies["Status"]},
+		{"MakeSegment", hub.RetryPolicy{Attempts: 1}},
+	}
+
+	for _, c := range cases {
+		if policy := conf.RetryPolicy(c.method); policy != c.expected {
+			t.Fatalf("got %+v for %s, want %+v", policy, c.method, c.expected)
+		}
+	}
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	// connects the hub to the agent and returns the client of the agent
+	connect := func(t *testing.T, agent *flakyAgent) idl.AgentClient {
+		t.Helper()
+
+		listener := bufconn.Listen(1024 * 1024)
+		agentServer := grpc.NewServer()
+		t.Cleanup(agentServer.Stop)
+
+		idl.RegisterAgentServer(agentServer, agent)
+		go func() {
+			if err := agentServer.Serve(listener); err != nil {
+				log.Fatalf("server exited with error: %v", err)
+			}
+		}()
+
+		hubServer := hub.New(&hub.Config{
+			Port:        1234,
+			AgentPort:   5678,
+			Hostnames:   []string{"sdw1"},
+			LogDir:      t.TempDir(),
+			Credentials: &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()},
+			Retries: map[string]hub.RetryPolicy{
+				"GetHostName":  {Attempts: 3},
+				"UpdatePgConf": {Attempts: 3},
+			},
+		}, func(ctx context.Context, address string) (net.Conn, error) {
+			return listener.Dial()
+		})
+
+		err := hubServer.DialAllAgents()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return hubServer.Conns[0].AgentClient
+	}
+
+	t.Run("retries the idempotent requests which fail with a transient error and reports the retries", func(t *testing.T) {
+		agent := &flakyAgent{failures: 2, err: status.Error(codes.Unavailable, "agent restarting")}
+		client := connect(t, agent)
+
+		var retries []string
+		ctx := hub.WithRetryReporter(context.Background(), func(message string) {
+			retries = append(retries, message)
+		})
+
+		reply, err := client.GetHostName(ctx, &idl.GetHostNameRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if reply.Hostname != "sdw1" {
+			t.Fatalf("got %s, want %s", reply.Hostname, "sdw1")
+		}
+
+		expected := []string{
+			"GetHostName on host sdw1 failed, retrying in 0s (attempt 2 of 3): agent restarting",
+			"GetHostName on host sdw1 failed, retrying in 0s (attempt 3 of 3): agent restarting",
+		}
+		if !reflect.DeepEqual(retries, expected) {
+			t.Fatalf("got %q, want %q", retries, expected)
+		}
+	})
+
+	t.Run("gives up once the attempts are exhausted", func(t *testing.T) {
+		agent := &flakyAgent{failures: 3, err: status.Error(codes.Unavailable, "agent restarting")}
+		client := connect(t, agent)
+
+		_, err := client.GetHostName(context.Background(), &idl.GetHostNameRequest{})
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("got %v, want %s", err, codes.Unavailable)
+		}
+
+		if agent.calls != 3 {
+			t.Fatalf("got %d calls, want %d", agent.calls, 3)
+		}
+	})
+
+	t.Run("does not retry the errors which are not transient", func(t *testing.T) {
+		agent := &flakyAgent{failures: 1, err: status.Error(codes.Internal, "error")}
+		client := connect(t, agent)
+
+		_, err := client.GetHostName(context.Background(), &idl.GetHostNameRequest{})
+		if status.Code(err) != codes.Internal {
+			t.Fatalf("got %v, want %s", err, codes.Internal)
+		}
+
+		if agent.calls != 1 {
+			t.Fatalf("got %d calls, want %d", agent.calls, 1)
+		}
+	})
+
+	t.Run("retries the requests only when they are idempotent", func(t *testing.T) {
+		agent := &flakyAgent{failures: 1, err: status.Error(codes.Unavailable, "agent restarting")}
+		client := connect(t, agent)
+
+		_, err := client.UpdatePgConf(context.Background(), &idl.UpdatePgConfRequest{Overwrite: false})
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("got %v, want %s", err, codes.Unavailable)
+		}
+
+		_, err = client.UpdatePgConf(context.Background(), &idl.UpdatePgConfRequest{Overwrite: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
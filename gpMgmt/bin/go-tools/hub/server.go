@@ -3,6 +3,7 @@ package hub
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 	grpcStatus "google.golang.org/grpc/status"
 
@@ -29,8 +31,25 @@ import (
 var (
 	platform                      = utils.GetPlatform()
 	DialTimeout                   = 3 * time.Second
+	DialAttempts                  = 3                      // attempts to dial an agent before giving up on its host
+	DialBackoff                   = 500 * time.Millisecond // delay before dialing an agent again, doubled after each attempt
 	ensureConnectionsAreReadyFunc = ensureConnectionsAreReady
 	execCommand                   = exec.Command
+
+	// methods which do not change the state of the hosts or the cluster and are not audited
+	readOnlyMethods = []string{
+		"/idl.Hub/StatusAgents",
+		"/idl.Hub/GetAllHostNames",
+		"/idl.Hub/FindFreePorts",
+		"/idl.Hub/ListOperations",
+		"/idl.Hub/AttachOperation",
+		"/idl.Hub/GetLockStatus",
+		"/idl.Hub/ListEvents",
+		"/idl.Hub/ListTopology",
+		"/idl.Hub/DiffTopology",
+		"/idl.Hub/ExportTopology",
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+	}
 )
 
 type Dialer func(context.Context, string) (net.Conn, error)
@@ -43,6 +62,41 @@ type Config struct {
 	ServiceName string   `json:"serviceName"`
 	GpHome      string   `json:"gphome"`
 
+	// data directory of the coordinator when an existing cluster is adopted by the hub
+	CoordinatorDataDir string `json:"coordinatorDataDir,omitempty"`
+
+	// certificate common names or SANs of the clients allowed to call the hub and the agents respectively;
+	// any client with a certificate signed by the CA is allowed if empty
+	AllowedCliClients []string `json:"allowedCliClients,omitempty"`
+	AllowedHubClients []string `json:"allowedHubClients,omitempty"`
+
+	// JSON file listing the methods each client is allowed to call on the hub and the agents
+	AuthorizationPolicyFile string `json:"authorizationPolicyFile,omitempty"`
+
+	// ports on which the hub and the agents serve the Prometheus metrics; disabled if 0
+	MetricsPort      int `json:"metricsPort,omitempty"`
+	AgentMetricsPort int `json:"agentMetricsPort,omitempty"`
+
+	// where the CLI, the hub and the agents export their traces; disabled if nil
+	Tracing *utils.TracingConfig `json:"tracing,omitempty"`
+
+	// maximum duration of the hub RPCs in seconds by method name, like MakeCluster;
+	// overrides DefaultTimeouts, and 0 removes the limit
+	Timeouts map[string]int `json:"timeouts,omitempty"`
+
+	// maximum number of agent requests the hub runs at the same time in total, DefaultParallel if 0,
+	// and on each host, not limited if 0
+	Parallel        int `json:"parallel,omitempty"`
+	ParallelPerHost int `json:"parallelPerHost,omitempty"`
+
+	// retry policies of the agent RPCs by method name, like PgBasebackup; overrides DefaultRetryPolicies
+	Retries map[string]RetryPolicy `json:"retries,omitempty"`
+
+	// size in MB from which the event store is rotated, DefaultEventsMaxSizeMB if 0,
+	// and number of rotated files kept, DefaultEventsMaxFiles if 0
+	EventsMaxSizeMB int `json:"eventsMaxSizeMB,omitempty"`
+	EventsMaxFiles  int `json:"eventsMaxFiles,omitempty"`
+
 	Credentials utils.Credentials
 }
 
@@ -50,8 +104,14 @@ type Server struct {
 	*Config
 	Conns      []*Connection
 	grpcDialer Dialer
+	operations *OperationRegistry
+	events     *EventStore
+	topology   *TopologyStore
+	lock       *ClusterLock
+	workers    *WorkerPool
 
 	mutex      sync.Mutex
+	dialMutex  sync.Mutex // serializes the dialing and replacement of Conns
 	grpcServer *grpc.Server
 	listener   net.Listener
 	finish     chan struct{}
@@ -62,12 +122,29 @@ type Connection struct {
 	AgentClient   idl.AgentClient
 	Hostname      string
 	CancelContext func()
+
+	// users counts the snapshots of Conns holding the connection, which is
+	// only closed once it has been replaced and they all released it
+	users   int
+	retired bool
 }
 
 func New(conf *Config, grpcDialer Dialer) *Server {
+	events := NewEventStore(filepath.Join(conf.LogDir, constants.HubEventsFileName))
+	if conf.EventsMaxSizeMB > 0 {
+		events.MaxSize = int64(conf.EventsMaxSizeMB) * 1024 * 1024
+	}
+	if conf.EventsMaxFiles > 0 {
+		events.MaxFiles = conf.EventsMaxFiles
+	}
 	h := &Server{
 		Config:     conf,
 		grpcDialer: grpcDialer,
+		operations: NewOperationRegistry(events),
+		events:     events,
+		topology:   NewTopologyStore(filepath.Join(conf.LogDir, constants.TopologyDirName)),
+		lock:       NewClusterLock(filepath.Join(conf.LogDir, constants.ClusterLockFileName)),
+		workers:    NewWorkerPool(conf.parallel(), conf.ParallelPerHost),
 		finish:     make(chan struct{}, 1),
 	}
 	return h
@@ -82,18 +159,59 @@ func (s *Server) Start() error {
 		return fmt.Errorf("could not listen on port %d: %w", s.Port, err)
 	}
 
-	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-		// handle stuff here if needed
-		return handler(ctx, req)
+	var policy *utils.AuthorizationPolicy
+	if s.AuthorizationPolicyFile != "" {
+		policy, err = utils.LoadAuthorizationPolicy(s.AuthorizationPolicyFile)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+	}
+
+	if held := s.lock.Status(); held != nil {
+		gplog.Warn("The cluster is locked by %s, which may have been interrupted by a restart of the hub; break the lock with --break-lock once it is safe to carry on", held)
 	}
 
+	shutdownTracing, err := utils.InitTracing(s.Tracing, "hub", filepath.Join(s.LogDir, constants.HubTracesFileName))
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	defer shutdownTracing()
+
+	auditLogger := utils.NewAuditLogger(filepath.Join(s.LogDir, constants.HubAuditLogFileName))
+	defer auditLogger.Close()
+	defer s.events.Close()
+
+	if s.MetricsPort != 0 {
+		registry, err := utils.NewMetricsRegistry(NewMetricsCollector(s))
+		if err != nil {
+			listener.Close()
+			return err
+		}
+
+		metricsServer, err := utils.StartMetricsServer(s.MetricsPort, registry)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		defer metricsServer.Close()
+	}
+
+	unaryTracing, streamTracing := utils.NewTracingInterceptors()
+	unaryMetrics, streamMetrics := utils.NewMetricsInterceptors("hub")
+	unaryAuditor, streamAuditor := utils.NewAuditInterceptors("hub", auditLogger, readOnlyMethods)
+	unaryAuthorizer, streamAuthorizer := utils.NewClientAuthorizer("hub", s.AllowedCliClients)
+	unaryPolicy, streamPolicy := utils.NewPolicyAuthorizer("hub", policy)
+
 	credentials, err := s.Credentials.LoadServerCredentials()
 	if err != nil {
 		return err
 	}
 	grpcServer := grpc.NewServer(
 		grpc.Creds(credentials),
-		grpc.UnaryInterceptor(interceptor),
+		grpc.ChainUnaryInterceptor(unaryTracing, unaryMetrics, unaryAuditor, unaryAuthorizer, unaryPolicy, s.newTimeoutInterceptor(), s.newWorkerPoolInterceptor()),
+		grpc.ChainStreamInterceptor(streamTracing, streamMetrics, streamAuditor, streamAuthorizer, streamPolicy),
 	)
 
 	s.mutex.Lock()
@@ -173,61 +291,204 @@ func (s *Server) StartAllAgents() error {
 	return nil
 }
 
+/*
+DialAllAgents connects to the agents of all the hosts, and fails with the
+HostErrors of the hosts which could not be reached, if any.
+*/
 func (s *Server) DialAllAgents() error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
+	unreachable, err := s.dialAgents()
+	if err != nil {
+		return err
+	}
 
-	if s.Conns != nil {
-		err := ensureConnectionsAreReadyFunc(s.Conns)
-		if err != nil {
-			return err
+	if len(unreachable) > 0 {
+		return unreachable
+	}
+
+	return nil
+}
+
+/*
+DialReachableAgents connects to the agents like DialAllAgents, except that the
+hosts which could not be reached are returned instead of failing, and Conns has
+the connections to the others. It lets the read-only RPCs carry on with the
+reachable hosts, reporting the unreachable ones.
+*/
+func (s *Server) DialReachableAgents() (utils.HostErrors, error) {
+	return s.dialAgents()
+}
+
+/*
+dialAgents connects to the agents of all the hosts concurrently. The ready
+connections are reused, while the agents whose connection is not ready, e.g.
+because the agent was restarted, are dialed again. The server mutex is only
+held to read and swap Conns, so that the operations using the connections are
+not blocked by the dial retries.
+*/
+func (s *Server) dialAgents() (utils.HostErrors, error) {
+	s.dialMutex.Lock()
+	defer s.dialMutex.Unlock()
+
+	current, release := s.acquireConns()
+	defer release()
+
+	existing := make(map[string]*Connection, len(current))
+	for _, conn := range current {
+		existing[conn.Hostname] = conn
+	}
+
+	conns := make([]*Connection, len(s.Hostnames))
+	errs := make([]error, len(s.Hostnames))
+	var toDial []int
+	for i, host := range s.Hostnames {
+		conn, ok := existing[host]
+		if ok && ensureConnectionsAreReadyFunc([]*Connection{conn}) == nil {
+			conns[i] = conn
+			continue
 		}
 
-		return nil
+		if ok {
+			gplog.Debug("Connection to agent on host %s is not ready, dialing it again", host)
+		}
+		toDial = append(toDial, i)
 	}
 
-	for _, host := range s.Hostnames {
-		ctx, cancelFunc := context.WithTimeout(context.Background(), DialTimeout)
-
+	if len(toDial) > 0 {
 		credentials, err := s.Credentials.LoadClientCredentials()
 		if err != nil {
-			cancelFunc()
-			return err
+			return nil, err
 		}
 
-		address := fmt.Sprintf("%s:%d", host, s.AgentPort)
-		opts := []grpc.DialOption{
-			grpc.WithBlock(),
-			grpc.WithTransportCredentials(credentials),
-			grpc.WithReturnConnectionError(),
-		}
-		if s.grpcDialer != nil {
-			opts = append(opts, grpc.WithContextDialer(s.grpcDialer))
+		var wg sync.WaitGroup
+		for _, i := range toDial {
+			i := i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				conns[i], errs[i] = s.dialAgent(s.Hostnames[i], credentials)
+			}()
 		}
-		conn, err := grpc.DialContext(ctx, address, opts...)
-		if err != nil {
-			cancelFunc()
-			return fmt.Errorf("could not connect to agent on host %s: %w", host, err)
+		wg.Wait()
+	}
+
+	var reachable []*Connection
+	var unreachable utils.HostErrors
+	for i, host := range s.Hostnames {
+		if errs[i] != nil {
+			unreachable = append(unreachable, &utils.HostError{Host: host, Err: utils.NewGrpcError(codes.Unavailable, errs[i])})
+			continue
 		}
-		s.Conns = append(s.Conns, &Connection{
-			Conn:          conn,
-			AgentClient:   idl.NewAgentClient(conn),
-			Hostname:      host,
-			CancelContext: cancelFunc,
+		reachable = append(reachable, conns[i])
+	}
+	s.swapConns(reachable)
+
+	return unreachable, nil
+}
+
+/*
+acquireConns returns a snapshot of Conns. Its connections stay open until the
+returned function releases them, even when dialAgents replaces them meanwhile.
+*/
+func (s *Server) acquireConns() ([]*Connection, func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	conns := append([]*Connection(nil), s.Conns...)
+	for _, conn := range conns {
+		conn.users++
+	}
+
+	var once sync.Once
+	return conns, func() {
+		once.Do(func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			for _, conn := range conns {
+				conn.users--
+				if conn.retired && conn.users == 0 {
+					conn.close()
+				}
+			}
 		})
 	}
+}
 
-	err := ensureConnectionsAreReadyFunc(s.Conns)
-	if err != nil {
-		return err
+// swapConns replaces Conns, closing the replaced connections once they are not used anymore
+func (s *Server) swapConns(conns []*Connection) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kept := make(map[*Connection]bool, len(conns))
+	for _, conn := range conns {
+		kept[conn] = true
 	}
+	for _, conn := range s.Conns {
+		if kept[conn] {
+			continue
+		}
 
-	return nil
+		conn.retired = true
+		if conn.users == 0 {
+			conn.close()
+		}
+	}
+
+	s.Conns = conns
+}
+
+// dialAgent connects to the agent of the host, trying again after a growing delay if it cannot be reached
+func (s *Server) dialAgent(host string, credentials credentials.TransportCredentials) (*Connection, error) {
+	address := fmt.Sprintf("%s:%d", host, s.AgentPort)
+	opts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithTransportCredentials(credentials),
+		grpc.WithReturnConnectionError(),
+		grpc.FailOnNonTempDialError(true),
+		grpc.WithChainUnaryInterceptor(s.newRetryInterceptor()),
+	}
+	opts = append(opts, utils.TracingDialOptions()...)
+	if s.grpcDialer != nil {
+		opts = append(opts, grpc.WithContextDialer(s.grpcDialer))
+	}
+
+	backoff := DialBackoff
+	for attempt := 1; ; attempt++ {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), DialTimeout)
+		conn, err := grpc.DialContext(ctx, address, opts...)
+		if err == nil {
+			return &Connection{
+				Conn:          conn,
+				AgentClient:   idl.NewAgentClient(conn),
+				Hostname:      host,
+				CancelContext: cancelFunc,
+			}, nil
+		}
+		cancelFunc()
+
+		if attempt >= DialAttempts {
+			return nil, fmt.Errorf("could not connect to agent: %w", err)
+		}
+
+		gplog.Debug("Attempt %d to connect to agent on host %s failed, retrying in %s: %v", attempt, host, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// close releases the connection, which is not used anymore
+func (c *Connection) close() {
+	if c.Conn != nil {
+		c.Conn.Close()
+	}
+	if c.CancelContext != nil {
+		c.CancelContext()
+	}
 }
 
 func (s *Server) StopAgents(ctx context.Context, in *idl.StopAgentsRequest) (*idl.StopAgentsReply, error) {
-	request := func(conn *Connection) error {
-		_, err := conn.AgentClient.Stop(context.Background(), &idl.StopAgentRequest{})
+	request := func(ctx context.Context, conn *Connection) error {
+		_, err := conn.AgentClient.Stop(ctx, &idl.StopAgentRequest{})
 		if err == nil { // no error -> didn't stop
 			return fmt.Errorf("failed to stop agent on host %s", conn.Hostname)
 		}
@@ -245,37 +506,54 @@ func (s *Server) StopAgents(ctx context.Context, in *idl.StopAgentsRequest) (*id
 		return &idl.StopAgentsReply{}, err
 	}
 
-	err = ExecuteRPC(s.Conns, request)
-	s.Conns = nil
+	conns, release := s.acquireConns()
+	err = ExecuteRPC(ctx, conns, request)
+	release()
+
+	s.dialMutex.Lock()
+	s.swapConns(nil)
+	s.dialMutex.Unlock()
 
 	return &idl.StopAgentsReply{}, err
 }
 
+/*
+StatusAgents reports the status of the agents of the hosts which can be reached,
+and lists the others as unreachable instead of failing.
+*/
 func (s *Server) StatusAgents(ctx context.Context, in *idl.StatusAgentsRequest) (*idl.StatusAgentsReply, error) {
-	statusChan := make(chan *idl.ServiceStatus, len(s.Conns))
+	unreachable, err := s.DialReachableAgents()
+	if err != nil {
+		return &idl.StatusAgentsReply{}, err
+	}
 
-	request := func(conn *Connection) error {
-		status, err := conn.AgentClient.Status(context.Background(), &idl.StatusAgentRequest{})
+	conns, release := s.acquireConns()
+	defer release()
+
+	statusChan := make(chan *idl.ServiceStatus, len(conns))
+	request := func(ctx context.Context, conn *Connection) error {
+		status, err := conn.AgentClient.Status(ctx, &idl.StatusAgentRequest{})
 		if err != nil {
-			return fmt.Errorf("failed to get agent status on host %s", conn.Hostname)
+			return utils.NewHostError("Status", "", utils.FormatGrpcError(err))
 		}
 		s := idl.ServiceStatus{
 			Host:   conn.Hostname,
 			Status: status.Status,
 			Uptime: status.Uptime,
 			Pid:    status.Pid,
+
+			CertificateExpiry: status.CertificateExpiry,
 		}
 		statusChan <- &s
 
 		return nil
 	}
 
-	err := s.DialAllAgents()
-	if err != nil {
-		return &idl.StatusAgentsReply{}, err
-	}
-	err = ExecuteRPC(s.Conns, request)
-	if err != nil {
+	err = ExecuteRPC(ctx, conns, request)
+	var failed utils.HostErrors
+	if errors.As(err, &failed) && ctx.Err() == nil {
+		unreachable = append(unreachable, failed...)
+	} else if err != nil {
 		return &idl.StatusAgentsReply{}, err
 	}
 	close(statusChan)
@@ -284,8 +562,12 @@ func (s *Server) StatusAgents(ctx context.Context, in *idl.StatusAgentsRequest)
 	for status := range statusChan {
 		statuses = append(statuses, status)
 	}
+	for _, hostErr := range unreachable {
+		gplog.Warn("Could not get the status of the agent on host %s: %v", hostErr.Host, hostErr.Err)
+		statuses = append(statuses, &idl.ServiceStatus{Host: hostErr.Host, Status: "unreachable"})
+	}
 
-	return &idl.StatusAgentsReply{Statuses: statuses}, err
+	return &idl.StatusAgentsReply{Statuses: statuses, Unreachable: unreachable.ToIdl()}, nil
 }
 
 func ensureConnectionsAreReady(conns []*Connection) error {
@@ -303,32 +585,102 @@ func ensureConnectionsAreReady(conns []*Connection) error {
 	return nil
 }
 
-func ExecuteRPC(agentConns []*Connection, executeRequest func(conn *Connection) error) error {
+/*
+ExecuteRPC runs the request against every agent concurrently, within the limits
+of the worker pool of the RPC, and returns the errors of all the failed requests
+as HostErrors, if any. The requests are given the context so that they are
+cancelled along with the RPC of the hub they are part of; the agents are not
+even called if it is already done.
+*/
+func ExecuteRPC(ctx context.Context, agentConns []*Connection, executeRequest func(ctx context.Context, conn *Connection) error) error {
+	pool := workerPoolFrom(ctx)
+
 	var wg sync.WaitGroup
-	errs := make(chan error, len(agentConns))
+	errs := make([][]error, len(agentConns))
 
-	for _, conn := range agentConns {
-		conn := conn
+	for i, conn := range agentConns {
+		i, conn := i, conn
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			err := executeRequest(conn)
-			if err != nil {
-				errs <- fmt.Errorf("host: %s, %w", conn.Hostname, err)
-			}
+
+			errs[i] = []error{runRequest(ctx, pool, conn.Hostname, func() error {
+				return executeRequest(ctx, conn)
+			})}
 		}()
 	}
 
 	wg.Wait()
-	close(errs)
 
-	var err error
-	for e := range errs {
-		err = e
-		break
+	return collectHostErrors(agentConns, errs)
+}
+
+/*
+ExecuteRPCForEach runs the request for each of the items of every agent, e.g.
+for each of the segments to create on the host, within the limits of the worker
+pool of the RPC. The errors of all the failed requests are returned as
+HostErrors, ordered by host and item, if any.
+*/
+func ExecuteRPCForEach[T any](ctx context.Context, agentConns []*Connection, items map[string][]T, executeRequest func(ctx context.Context, conn *Connection, item T) error) error {
+	pool := workerPoolFrom(ctx)
+
+	var wg sync.WaitGroup
+	errs := make([][]error, len(agentConns))
+
+	for i, conn := range agentConns {
+		errs[i] = make([]error, len(items[conn.Hostname]))
+		for j, item := range items[conn.Hostname] {
+			i, j, conn, item := i, j, conn, item
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				errs[i][j] = runRequest(ctx, pool, conn.Hostname, func() error {
+					return executeRequest(ctx, conn, item)
+				})
+			}()
+		}
 	}
 
-	return err
+	wg.Wait()
+
+	return collectHostErrors(agentConns, errs)
+}
+
+// runRequest runs the request through the pool unless the context is already done
+func runRequest(ctx context.Context, pool *WorkerPool, host string, request func() error) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	return pool.Run(ctx, host, request)
+}
+
+// collectHostErrors aggregates the errors of the requests made to each of the agents, nil if none failed
+func collectHostErrors(agentConns []*Connection, errs [][]error) error {
+	var hostErrs utils.HostErrors
+	for i, conn := range agentConns {
+		for _, err := range errs[i] {
+			if err == nil {
+				continue
+			}
+
+			// the requests describe their failures with utils.NewHostError, which lacks the host
+			hostErr, ok := err.(*utils.HostError)
+			if !ok {
+				hostErr = &utils.HostError{Err: err}
+			}
+			hostErr.Host = conn.Hostname
+			hostErrs = append(hostErrs, hostErr)
+		}
+	}
+
+	if len(hostErrs) == 0 {
+		return nil
+	}
+
+	return hostErrs
 }
 
 func (conf *Config) Load(ConfigFilePath string) error {
@@ -344,6 +696,22 @@ func (conf *Config) Load(ConfigFilePath string) error {
 		return fmt.Errorf("could not parse config file: %w", err)
 	}
 
+	for method, timeout := range conf.Timeouts {
+		if timeout < 0 {
+			return fmt.Errorf("invalid timeout %d for %s, expected a number of seconds", timeout, method)
+		}
+	}
+
+	for method, policy := range conf.Retries {
+		if policy.Attempts < 1 || policy.BackoffMillis < 0 {
+			return fmt.Errorf("invalid retry policy for %s, expected at least 1 attempt and a non-negative backoff", method)
+		}
+	}
+
+	if conf.Parallel < 0 || conf.ParallelPerHost < 0 {
+		return fmt.Errorf("invalid parallel %d and parallel per host %d, expected non-negative numbers", conf.Parallel, conf.ParallelPerHost)
+	}
+
 	return nil
 }
 
@@ -391,6 +759,15 @@ func copyConfigFileToAgents(conf *Config, ConfigFilePath string) error {
 		return fmt.Errorf("could not copy gp.conf file to segment hosts: %w, Command Output: %s", err, string(output))
 	}
 
+	if conf.AuthorizationPolicyFile != "" {
+		remoteCmd = append(hostList, conf.AuthorizationPolicyFile, fmt.Sprintf("=:%s", conf.AuthorizationPolicyFile))
+		cmd = execCommand(constants.ShellPath, "-c", fmt.Sprintf("source %s && gpsync %s", greenplumPathSh, strings.Join(remoteCmd, " ")))
+		output, err = cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("could not copy the authorization policy file to segment hosts: %w, Command Output: %s", err, string(output))
+		}
+	}
+
 	return nil
 }
 
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net"
 	"os"
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -52,13 +54,13 @@ func TestStartServer(t *testing.T) {
 		credentials := &testutils.MockCredentials{}
 
 		hubConfig := &hub.Config{
-			1234,
-			8080,
-			[]string{host},
-			"/tmp/logDir",
-			"gp",
-			gpHome,
-			credentials,
+			Port:        1234,
+			AgentPort:   8080,
+			Hostnames:   []string{host},
+			LogDir:      "/tmp/logDir",
+			ServiceName: "gp",
+			GpHome:      gpHome,
+			Credentials: credentials,
 		}
 
 		hubServer := hub.New(hubConfig, nil)
@@ -88,13 +90,13 @@ func TestStartServer(t *testing.T) {
 		}
 
 		hubConfig := &hub.Config{
-			1235,
-			8080,
-			[]string{host},
-			"/tmp/logDir",
-			"gp",
-			gpHome,
-			credentials,
+			Port:        1235,
+			AgentPort:   8080,
+			Hostnames:   []string{host},
+			LogDir:      "/tmp/logDir",
+			ServiceName: "gp",
+			GpHome:      gpHome,
+			Credentials: credentials,
 		}
 		hubServer := hub.New(hubConfig, nil)
 
@@ -132,13 +134,13 @@ func TestStartAgents(t *testing.T) {
 	}()
 
 	hubConfig := &hub.Config{
-		constants.DefaultHubPort,
-		constants.DefaultAgentPort,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        constants.DefaultHubPort,
+		AgentPort:   constants.DefaultAgentPort,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      "/tmp/logDir",
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 
 	t.Run("successfully starts the agents from hub", func(t *testing.T) {
@@ -175,16 +177,21 @@ func TestDialAllAgents(t *testing.T) {
 	}()
 
 	hubConfig := &hub.Config{
-		constants.DefaultHubPort,
-		constants.DefaultAgentPort,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        constants.DefaultHubPort,
+		AgentPort:   constants.DefaultAgentPort,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      "/tmp/logDir",
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 
-	t.Run("successfully establishes connections to agent hosts and errors out when some of the connections are not ready", func(t *testing.T) {
+	hub.DialTimeout, hub.DialBackoff = 100*time.Millisecond, time.Millisecond
+	defer func() {
+		hub.DialTimeout, hub.DialBackoff = 3*time.Second, 500*time.Millisecond
+	}()
+
+	t.Run("successfully establishes connections to agent hosts and dials again the connections which are not ready", func(t *testing.T) {
 
 		dialer := func(ctx context.Context, address string) (net.Conn, error) {
 			return listener.Dial()
@@ -215,16 +222,46 @@ func TestDialAllAgents(t *testing.T) {
 		}
 
 		// close one of the connections
-		for _, conn := range hubServer.Conns {
-			if conn.Hostname == "sdw2" {
-				conn.Conn.Close()
+		sdw1, sdw2 := hubServer.Conns[0], hubServer.Conns[1]
+		sdw2.Conn.Close()
+
+		err = hubServer.DialAllAgents()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		if hubServer.Conns[0] != sdw1 {
+			t.Fatalf("expected the ready connection to sdw1 to be reused")
+		}
+
+		if hubServer.Conns[1] == sdw2 || hubServer.Conns[1].Conn.GetState() != expectedState {
+			t.Fatalf("expected the connection to sdw2 to be dialed again")
+		}
+	})
+
+	t.Run("retries to connect to the agents", func(t *testing.T) {
+		var mutex sync.Mutex
+		attempts := 0
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if strings.HasPrefix(address, "sdw2") && attempts < hub.DialAttempts-1 {
+				attempts++
+				return nil, errors.New("error")
 			}
+
+			return listener.Dial()
 		}
 
-		err = hubServer.DialAllAgents()
-		expectedErr := "could not ensure connections were ready: unready hosts: sdw2"
-		if err.Error() != expectedErr {
-			t.Fatalf("got %v, want %v", err.Error(), expectedErr)
+		hubServer := hub.New(hubConfig, dialer)
+		err := hubServer.DialAllAgents()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		if len(hubServer.Conns) != 2 {
+			t.Fatalf("got %d connections, want %d", len(hubServer.Conns), 2)
 		}
 	})
 
@@ -239,11 +276,112 @@ func TestDialAllAgents(t *testing.T) {
 
 		hubServer := hub.New(hubConfig, dialer)
 		err := hubServer.DialAllAgents()
-		expectedErr := "could not connect to agent on host sdw2:"
-		if !strings.HasPrefix(err.Error(), expectedErr) {
-			t.Fatalf("got %s, want %s", err.Error(), expectedErr)
+		expectedErr := "host: sdw2, could not connect to agent:"
+		if err == nil || !strings.HasPrefix(err.Error(), expectedErr) {
+			t.Fatalf("got %v, want %s", err, expectedErr)
+		}
+
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("got %s, want %s", status.Code(err), codes.Unavailable)
 		}
 	})
+
+	t.Run("connects to the reachable agents only", func(t *testing.T) {
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			if strings.HasPrefix(address, "sdw2") {
+				return nil, errors.New("error")
+			}
+
+			return listener.Dial()
+		}
+
+		hubServer := hub.New(hubConfig, dialer)
+		unreachable, err := hubServer.DialReachableAgents()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		if len(unreachable) != 1 || unreachable[0].Host != "sdw2" {
+			t.Fatalf("got %v, want sdw2 to be unreachable", unreachable)
+		}
+
+		if len(hubServer.Conns) != 1 || hubServer.Conns[0].Hostname != "sdw1" {
+			t.Fatalf("got %+v, want only the connection to sdw1", hubServer.Conns)
+		}
+	})
+
+	t.Run("keeps the replaced connections open until the operations using them are done", func(t *testing.T) {
+		blocking := &blockingAgent{called: make(chan struct{}), unblock: make(chan struct{})}
+		blockingListener := bufconn.Listen(1024 * 1024)
+		blockingServer := grpc.NewServer()
+		defer blockingServer.Stop()
+
+		idl.RegisterAgentServer(blockingServer, blocking)
+		go func() {
+			_ = blockingServer.Serve(blockingListener)
+		}()
+
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			return blockingListener.Dial()
+		}
+
+		hubServer := hub.New(hubConfig, dialer)
+		err := hubServer.DialAllAgents()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		replaced := append([]*hub.Connection(nil), hubServer.Conns...)
+
+		done := make(chan *idl.StatusAgentsReply)
+		go func() {
+			reply, _ := hubServer.StatusAgents(context.Background(), &idl.StatusAgentsRequest{})
+			done <- reply
+		}()
+		for range replaced {
+			<-blocking.called
+		}
+
+		hub.SetEnsureConnectionsAreReady(func(conns []*hub.Connection) error {
+			return errors.New("not ready")
+		})
+		err = hubServer.DialAllAgents()
+		hub.ResetEnsureConnectionsAreReady()
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		for _, conn := range replaced {
+			if conn.Conn.GetState() == connectivity.Shutdown {
+				t.Fatalf("expected the connection to %s to stay open while in use", conn.Hostname)
+			}
+		}
+
+		close(blocking.unblock)
+		reply := <-done
+		if len(reply.Unreachable) != 0 {
+			t.Fatalf("got %v, want all the agents to be reached", reply.Unreachable)
+		}
+
+		for _, conn := range replaced {
+			if conn.Conn.GetState() != connectivity.Shutdown {
+				t.Fatalf("expected the replaced connection to %s to be closed once released", conn.Hostname)
+			}
+		}
+	})
+}
+
+// blockingAgent answers the status requests once unblocked
+type blockingAgent struct {
+	idl.UnimplementedAgentServer
+	called  chan struct{}
+	unblock chan struct{}
+}
+
+func (a *blockingAgent) Status(ctx context.Context, req *idl.StatusAgentRequest) (*idl.StatusAgentReply, error) {
+	a.called <- struct{}{}
+	<-a.unblock
+
+	return &idl.StatusAgentReply{Status: "running"}, nil
 }
 
 func TestStatusAgents(t *testing.T) {
@@ -251,13 +389,13 @@ func TestStatusAgents(t *testing.T) {
 
 	credentials := &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()}
 	hubConfig := &hub.Config{
-		constants.DefaultHubPort,
-		constants.DefaultAgentPort,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        constants.DefaultHubPort,
+		AgentPort:   constants.DefaultAgentPort,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      "/tmp/logDir",
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 	hubServer := hub.New(hubConfig, nil)
 
@@ -279,6 +417,8 @@ func TestStatusAgents(t *testing.T) {
 			Status: "running",
 			Uptime: "5H",
 			Pid:    123,
+
+			CertificateExpiry: 1700000000,
 		}, nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
@@ -306,7 +446,7 @@ func TestStatusAgents(t *testing.T) {
 		expected := &idl.StatusAgentsReply{
 			Statuses: []*idl.ServiceStatus{
 				{Host: "sdw2", Status: "running", Uptime: "2H", Pid: 456},
-				{Host: "sdw1", Status: "running", Uptime: "5H", Pid: 123},
+				{Host: "sdw1", Status: "running", Uptime: "5H", Pid: 123, CertificateExpiry: 1700000000},
 			},
 		}
 		if !reflect.DeepEqual(result, expected) {
@@ -314,7 +454,7 @@ func TestStatusAgents(t *testing.T) {
 		}
 	})
 
-	t.Run("errors out when not able to get the status from one of the hosts", func(t *testing.T) {
+	t.Run("reports the hosts it is not able to get the status from", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -346,10 +486,22 @@ func TestStatusAgents(t *testing.T) {
 		}
 		hubServer.Conns = agentConns
 
-		_, err := hubServer.StatusAgents(context.Background(), &idl.StatusAgentsRequest{})
-		expectedErr := "failed to get agent status on host sdw2"
-		if err == nil || !strings.Contains(err.Error(), expectedErr) {
-			t.Fatalf("got %v, want %v", err, expectedErr)
+		reply, err := hubServer.StatusAgents(context.Background(), &idl.StatusAgentsRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := &idl.StatusAgentsReply{
+			Statuses: []*idl.ServiceStatus{
+				{Host: "sdw1", Status: "running", Uptime: "5H", Pid: 123},
+				{Host: "sdw2", Status: "unreachable"},
+			},
+			Unreachable: []*idl.HostError{
+				{Host: "sdw2", Rpc: "Status", Code: int32(codes.Unknown), Message: "error"},
+			},
+		}
+		if !reflect.DeepEqual(reply, expected) {
+			t.Fatalf("got %+v, want %+v", reply, expected)
 		}
 	})
 }
@@ -359,13 +511,13 @@ func TestStopAgents(t *testing.T) {
 
 	credentials := &testutils.MockCredentials{TlsConnection: insecure.NewCredentials()}
 	hubConfig := &hub.Config{
-		constants.DefaultHubPort,
-		constants.DefaultAgentPort,
-		[]string{"sdw1", "sdw2"},
-		"/tmp/logDir",
-		"gp",
-		"gpHome",
-		credentials,
+		Port:        constants.DefaultHubPort,
+		AgentPort:   constants.DefaultAgentPort,
+		Hostnames:   []string{"sdw1", "sdw2"},
+		LogDir:      "/tmp/logDir",
+		ServiceName: "gp",
+		GpHome:      "gpHome",
+		Credentials: credentials,
 	}
 	hubServer := hub.New(hubConfig, nil)
 
@@ -479,6 +631,38 @@ func TestConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("copies the authorization policy file along with the config to the hosts", func(t *testing.T) {
+		file, err := os.CreateTemp("", "test")
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		defer os.Remove(file.Name())
+
+		var commands []string
+		hub.SetExecCommand(exectest.NewCommandWithVerifier(exectest.Success, func(utility string, args ...string) {
+			commands = append(commands, strings.Join(args, " "))
+		}))
+		defer hub.ResetExecCommand()
+
+		config := hub.Config{
+			Hostnames:               []string{"sdw1", "sdw2"},
+			GpHome:                  "gpHome",
+			AuthorizationPolicyFile: "/path/to/policy.json",
+		}
+		err = config.Write(file.Name())
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := []string{
+			fmt.Sprintf("-c source gpHome/greenplum_path.sh && gpsync -h sdw1 -h sdw2 %[1]s =:%[1]s", file.Name()),
+			"-c source gpHome/greenplum_path.sh && gpsync -h sdw1 -h sdw2 /path/to/policy.json =:/path/to/policy.json",
+		}
+		if !reflect.DeepEqual(commands, expected) {
+			t.Fatalf("got %v, want %v", commands, expected)
+		}
+	})
+
 	t.Run("returns appropriate error when fails to write config", func(t *testing.T) {
 		file, err := os.CreateTemp("", "test")
 		if err != nil {
@@ -539,4 +723,67 @@ func TestConfig(t *testing.T) {
 			t.Fatalf("got %#v, want %#v", err, os.ErrPermission)
 		}
 	})
+
+	t.Run("errors out when a timeout is negative", func(t *testing.T) {
+		file, err := os.CreateTemp("", "test")
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		defer os.Remove(file.Name())
+
+		_, err = file.WriteString(`{"timeouts": {"MakeCluster": -1}}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		config := hub.Config{}
+		err = config.Load(file.Name())
+
+		expected := "invalid timeout -1 for MakeCluster, expected a number of seconds"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when a retry policy is invalid", func(t *testing.T) {
+		file, err := os.CreateTemp("", "test")
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		defer os.Remove(file.Name())
+
+		_, err = file.WriteString(`{"retries": {"PgBasebackup": {"attempts": 0, "backoffMillis": 1000}}}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		config := hub.Config{}
+		err = config.Load(file.Name())
+
+		expected := "invalid retry policy for PgBasebackup, expected at least 1 attempt and a non-negative backoff"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when the parallel is negative", func(t *testing.T) {
+		file, err := os.CreateTemp("", "test")
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+		defer os.Remove(file.Name())
+
+		_, err = file.WriteString(`{"parallel": 10, "parallelPerHost": -1}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		config := hub.Config{}
+		err = config.Load(file.Name())
+
+		expected := "invalid parallel 10 and parallel per host -1, expected non-negative numbers"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
 }
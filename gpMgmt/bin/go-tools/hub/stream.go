@@ -1,10 +1,17 @@
 package hub
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os/exec"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
 )
 
 // Common interface for all hub side streaming RPC servers
@@ -15,20 +22,49 @@ type streamSender interface {
 type hubStreamer interface {
 	StreamLogMsg(msg string, level ...idl.LogLevel)
 	StreamStdoutMsg(msg string)
-	StreamExecCommand(cmd *exec.Cmd, gpHome string) error
-	StreamProgressMsg(label string, total int)
+	StreamExecCommand(ctx context.Context, source OutputSource, cmd *exec.Cmd) error
+	StreamOutputMsg(source OutputSource, stream idl.OutputStream, line string)
+	StreamProgressMsg(stage string, label string, current int, total int)
+	StreamTransferMsg(label string, current int64, total int64, completed bool)
+	StreamStageMsg(id string, name string, state idl.StageState, err error)
+	StreamSegmentMsg(seg *idl.Segment, state idl.SegmentState, err error)
 }
 
 type HubStream struct {
 	handler streamSender
+	output  *outputSequencer
+	events  *eventRecorder
 }
 
 func NewHubStream(s streamSender) HubStream {
 	return HubStream{
 		handler: s,
+		output:  &outputSequencer{},
+		events:  &eventRecorder{},
 	}
 }
 
+// OutputSource tells where the output of a command streamed to the CLI comes from
+type OutputSource struct {
+	Host    string
+	Segment string // data directory of the segment, if the command runs for one
+	Dbid    int
+}
+
+func (s OutputSource) String() string {
+	if s.Segment != "" {
+		return fmt.Sprintf("%s:%s", s.Host, s.Segment)
+	}
+
+	return s.Host
+}
+
+// outputSequencer numbers the lines of output streamed to the CLI, and sends them in that order
+type outputSequencer struct {
+	mutex    sync.Mutex
+	sequence uint64
+}
+
 func (h *HubStream) GetHandler() streamSender {
 	return h.handler
 }
@@ -78,74 +114,76 @@ func (h *HubStream) StreamStdoutMsg(msg string) {
 }
 
 /*
-StreamExecCommand runs the given exec.Cmd and streams its
-stdout and stderr from hub to the CLI
+StreamOutputMsg streams a line of output of a command from hub to the CLI,
+tagged with its source and the stream it was written to. The lines are
+numbered in the order they are sent, across all the commands of the RPC, so
+that the CLI can tell their order whichever goroutine sent them.
 */
-func (h *HubStream) StreamExecCommand(cmd *exec.Cmd, gpHome string) error {
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return err
-	}
+func (h *HubStream) StreamOutputMsg(source OutputSource, stream idl.OutputStream, line string) {
+	h.output.mutex.Lock()
+	defer h.output.mutex.Unlock()
 
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return err
+	h.output.sequence++
+	message := &idl.HubReply{
+		Message: &idl.HubReply_OutputMsg{
+			OutputMsg: &idl.OutputMessage{
+				Host:      source.Host,
+				Segment:   source.Segment,
+				Dbid:      int32(source.Dbid),
+				Stream:    stream,
+				Sequence:  h.output.sequence,
+				Timestamp: time.Now().UnixMilli(),
+				Line:      line,
+			},
+		},
 	}
 
-	gplog.Verbose("Executing command: %s", cmd.String())
-	if err := cmd.Start(); err != nil {
-		return err
+	err := h.handler.Send(message)
+	if err != nil {
+		gplog.Error("unable to stream message %q: %s", message, err)
 	}
+}
 
-	// stream the stdout continuously
-	go func() {
-		buf := make([]byte, 1024)
-		for {
-			n, err := stdout.Read(buf)
-			if err != nil {
-				break
-			}
-
-			output := string(buf[:n])
-			h.StreamStdoutMsg(output)
-		}
-	}()
-
-	// stream the stderr continuously
-	go func() {
-		buf := make([]byte, 1024)
-		for {
-			n, err := stderr.Read(buf)
-			if err != nil {
-				break
-			}
+/*
+StreamExecCommand runs the given exec.Cmd and streams its
+stdout and stderr from hub to the CLI line by line, tagged
+with the given source. The command is stopped if the context
+is done before it completes.
+*/
+func (h *HubStream) StreamExecCommand(ctx context.Context, source OutputSource, cmd *exec.Cmd) error {
+	stdout := utils.NewLineWriter(func(line string) {
+		h.StreamOutputMsg(source, idl.OutputStream_STDOUT, line)
+	})
+	stderr := utils.NewLineWriter(func(line string) {
+		h.StreamOutputMsg(source, idl.OutputStream_STDERR, line)
+	})
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
-			output := string(buf[:n])
-			h.StreamStdoutMsg(output)
-		}
-	}()
+	gplog.Verbose("Executing command: %s", cmd.String())
 
-	if err := cmd.Wait(); err != nil {
-		return err
-	}
+	// the command completes once all its output is written, only the lines without a newline are left to send
+	err := utils.RunCommandWithContext(ctx, cmd)
+	stdout.Flush()
+	stderr.Flush()
 
-	return nil
+	return err
 }
 
 /*
 StreamProgressMsg is used to stream progress messages from hub to
 the CLI. On the CLI side a progress bar will be displayed on the stdout
-which will increment with each call to this function. First call to this
-will just create/initialise the progress bar and subsequent calls would be
-used to increment the progress
+for the stage, set to the number of steps done so far out of the total.
+The bar starts over when the stage is started again.
 */
-func (h *HubStream) StreamProgressMsg(label string, total int) {
-
+func (h *HubStream) StreamProgressMsg(stage string, label string, current int, total int) {
 	message := &idl.HubReply{
 		Message: &idl.HubReply_ProgressMsg{
 			ProgressMsg: &idl.ProgressMessage{
-				Label: label,
-				Total: int32(total),
+				Stage:   stage,
+				Label:   label,
+				Current: int32(current),
+				Total:   int32(total),
 			},
 		},
 	}
@@ -155,3 +193,72 @@ func (h *HubStream) StreamProgressMsg(label string, total int) {
 		gplog.Error("unable to stream message %q: %s", message, err)
 	}
 }
+
+/*
+StreamTransferMsg streams the progress in bytes of a transfer from hub to the
+CLI, which displays it as a progress bar of its own with the transfer rate and
+the estimated time left. The total may change as it is only estimated.
+*/
+func (h *HubStream) StreamTransferMsg(label string, current int64, total int64, completed bool) {
+	message := &idl.HubReply{
+		Message: &idl.HubReply_TransferMsg{
+			TransferMsg: &idl.TransferMessage{
+				Label:     label,
+				Current:   current,
+				Total:     total,
+				Completed: completed,
+			},
+		},
+	}
+
+	err := h.handler.Send(message)
+	if err != nil {
+		gplog.Error("unable to stream message %q: %s", message, err)
+	}
+}
+
+// interval between the transfer progress messages relayed for a segment, so that long transfers do not flood the stream
+var TransferProgressInterval = 5 * time.Second
+
+// Common interface for the streams of the agent RPCs running a command for a segment
+type segmentCommandReceiver interface {
+	Recv() (*idl.SegmentCommandReply, error)
+}
+
+/*
+relaySegmentCommand relays what the agent streams while running a command for
+the segment, until the command completes. The output is logged and sent to the
+CLI line by line, tagged with the segment. The progress of a transfer is sent
+as a progress bar labelled with the segment.
+*/
+func relaySegmentCommand(stream hubStreamer, source OutputSource, receiver segmentCommandReceiver) error {
+	label := source.String()
+
+	var progress *idl.TransferProgress
+	var lastSent time.Time
+	for {
+		reply, err := receiver.Recv()
+		if err == io.EOF {
+			if progress != nil {
+				stream.StreamTransferMsg(label, progress.Done, progress.Total, true)
+			}
+
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch msg := reply.Message.(type) {
+		case *idl.SegmentCommandReply_Output:
+			gplog.Debug("%s %s: %s", label, strings.ToLower(msg.Output.Stream.String()), msg.Output.Line)
+			stream.StreamOutputMsg(source, msg.Output.Stream, msg.Output.Line)
+
+		case *idl.SegmentCommandReply_Progress:
+			progress = msg.Progress
+			if time.Since(lastSent) >= TransferProgressInterval {
+				stream.StreamTransferMsg(label, progress.Done, progress.Total, false)
+				lastSent = time.Now()
+			}
+		}
+	}
+}
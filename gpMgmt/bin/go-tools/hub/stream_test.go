@@ -1,6 +1,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/hub"
 	"github.com/greenplum-db/gpdb/gp/idl"
 	"github.com/greenplum-db/gpdb/gp/testutils"
 	"github.com/greenplum-db/gpdb/gp/testutils/exectest"
@@ -86,23 +88,27 @@ func TestHubStream(t *testing.T) {
 
 		label := "label"
 		total := 2
-		stream.StreamProgressMsg(label, total)
-		stream.StreamProgressMsg(label, total)
+		stream.StreamProgressMsg(hub.StageCreatePrimaries, label, 0, total)
+		stream.StreamProgressMsg(hub.StageCreatePrimaries, label, 1, total)
 
 		expected := []*idl.HubReply{
 			{
 				Message: &idl.HubReply_ProgressMsg{
 					ProgressMsg: &idl.ProgressMessage{
-						Label: label,
-						Total: int32(total),
+						Stage:   hub.StageCreatePrimaries,
+						Label:   label,
+						Current: 0,
+						Total:   int32(total),
 					},
 				},
 			},
 			{
 				Message: &idl.HubReply_ProgressMsg{
 					ProgressMsg: &idl.ProgressMessage{
-						Label: label,
-						Total: int32(total),
+						Stage:   hub.StageCreatePrimaries,
+						Label:   label,
+						Current: 1,
+						Total:   int32(total),
 					},
 				},
 			},
@@ -113,24 +119,21 @@ func TestHubStream(t *testing.T) {
 		}
 	})
 
-	t.Run("succesfully streams exec commands", func(t *testing.T) {
+	t.Run("succesfully streams transfer messages", func(t *testing.T) {
 		stream, res := testutils.NewMockStream()
 
-		dummyCmd := exectest.NewCommand(DummyCommand)
-		err := stream.StreamExecCommand(dummyCmd(""), "gpHome")
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
+		stream.StreamTransferMsg("sdw1:/data/mirror/gpseg0", 1024, 4096, false)
+		stream.StreamTransferMsg("sdw1:/data/mirror/gpseg0", 4096, 4096, true)
 
 		expected := []*idl.HubReply{
 			{
-				Message: &idl.HubReply_StdoutMsg{
-					StdoutMsg: "stdout",
+				Message: &idl.HubReply_TransferMsg{
+					TransferMsg: &idl.TransferMessage{Label: "sdw1:/data/mirror/gpseg0", Current: 1024, Total: 4096},
 				},
 			},
 			{
-				Message: &idl.HubReply_StdoutMsg{
-					StdoutMsg: "stderr",
+				Message: &idl.HubReply_TransferMsg{
+					TransferMsg: &idl.TransferMessage{Label: "sdw1:/data/mirror/gpseg0", Current: 4096, Total: 4096, Completed: true},
 				},
 			},
 		}
@@ -140,11 +143,64 @@ func TestHubStream(t *testing.T) {
 		}
 	})
 
+	t.Run("succesfully streams exec commands line by line", func(t *testing.T) {
+		stream, res := testutils.NewMockStream()
+
+		source := hub.OutputSource{Host: "cdw"}
+		dummyCmd := exectest.NewCommand(DummyCommand)
+		err := stream.StreamExecCommand(context.Background(), source, dummyCmd(""))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// the lines without a newline are sent once the command completes, stdout first
+		expected := []*idl.OutputMessage{
+			{Host: "cdw", Stream: idl.OutputStream_STDOUT, Sequence: 1, Line: "first line"},
+			{Host: "cdw", Stream: idl.OutputStream_STDOUT, Sequence: 2, Line: "second line"},
+			{Host: "cdw", Stream: idl.OutputStream_STDERR, Sequence: 3, Line: "stderr"},
+		}
+		var messages []*idl.OutputMessage
+		for _, reply := range res.GetBuffer() {
+			msg := reply.GetOutputMsg()
+			if msg.Timestamp == 0 {
+				t.Fatalf("expected the output message %v to have a timestamp", msg)
+			}
+			msg.Timestamp = 0
+			messages = append(messages, msg)
+		}
+
+		if !reflect.DeepEqual(messages, expected) {
+			t.Fatalf("got %+v, want %+v", messages, expected)
+		}
+	})
+
+	t.Run("numbers the output across the commands and sources", func(t *testing.T) {
+		stream, res := testutils.NewMockStream()
+
+		primary := hub.OutputSource{Host: "sdw1", Segment: "/data/primary/gpseg0", Dbid: 2}
+		mirror := hub.OutputSource{Host: "sdw2", Segment: "/data/mirror/gpseg0", Dbid: 3}
+		stream.StreamOutputMsg(primary, idl.OutputStream_STDOUT, "primary")
+		stream.StreamOutputMsg(mirror, idl.OutputStream_STDERR, "mirror")
+
+		buffer := res.GetBuffer()
+		if len(buffer) != 2 {
+			t.Fatalf("got %d messages, want 2", len(buffer))
+		}
+
+		first, second := buffer[0].GetOutputMsg(), buffer[1].GetOutputMsg()
+		if first.Sequence != 1 || first.Host != "sdw1" || first.Segment != primary.Segment || first.Dbid != 2 {
+			t.Fatalf("got %+v, want the output of %v numbered 1", first, primary)
+		}
+		if second.Sequence != 2 || second.Host != "sdw2" || second.Segment != mirror.Segment || second.Dbid != 3 {
+			t.Fatalf("got %+v, want the output of %v numbered 2", second, mirror)
+		}
+	})
+
 	t.Run("returns error when not able to stream exec command", func(t *testing.T) {
 		stream, res := testutils.NewMockStream()
 
 		dummyCmd := exectest.NewCommand(exectest.Failure)
-		err := stream.StreamExecCommand(dummyCmd(""), "gpHome")
+		err := stream.StreamExecCommand(context.Background(), hub.OutputSource{Host: "cdw"}, dummyCmd(""))
 
 		var expectedErr *exec.ExitError
 		if !errors.As(err, &expectedErr) {
@@ -176,6 +232,7 @@ func TestHubStream(t *testing.T) {
 		progressMsg := &idl.HubReply{
 			Message: &idl.HubReply_ProgressMsg{
 				ProgressMsg: &idl.ProgressMessage{
+					Stage: hub.StageValidateHosts,
 					Label: "progress message",
 					Total: 0,
 				},
@@ -184,7 +241,7 @@ func TestHubStream(t *testing.T) {
 
 		stream.StreamLogMsg(logMsg.GetLogMsg().Message)
 		stream.StreamStdoutMsg(stdoutMsg.GetStdoutMsg())
-		stream.StreamProgressMsg(progressMsg.GetProgressMsg().Label, int(progressMsg.GetProgressMsg().Total))
+		stream.StreamProgressMsg(hub.StageValidateHosts, progressMsg.GetProgressMsg().Label, 0, int(progressMsg.GetProgressMsg().Total))
 
 		if len(res.GetBuffer()) != 0 {
 			t.Fatalf("got %d, want the buffer to be empty", len(res.GetBuffer()))
@@ -202,8 +259,9 @@ func TestHubStream(t *testing.T) {
 }
 
 func DummyCommand() {
-	os.Stdout.WriteString("stdout")
-	time.Sleep(1 * time.Millisecond) // add a delay so that stderr is streamed after stdout and we can make a reliable assertion
+	os.Stdout.WriteString("first li")
+	time.Sleep(1 * time.Millisecond) // add a delay so that the first line is written in two chunks
+	os.Stdout.WriteString("ne\nsecond line")
 	os.Stderr.WriteString("stderr")
 
 	os.Exit(0)
@@ -0,0 +1,56 @@
+package hub
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// timeouts in seconds of the hub RPCs which are limited unless configured otherwise
+var DefaultTimeouts = map[string]int{
+	"StatusAgents":     60,
+	"StopAgents":       60,
+	"AdoptCluster":     300,
+	"CleanInitCluster": 600,
+}
+
+/*
+Timeout returns how long the hub RPC is allowed to run, including the calls it
+makes to the agents and the commands they run. The configured timeouts take
+precedence over DefaultTimeouts, and 0 means that the RPC is not limited.
+*/
+func (conf *Config) Timeout(method string) time.Duration {
+	seconds, ok := conf.Timeouts[method]
+	if !ok {
+		seconds = DefaultTimeouts[method]
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// withTimeout limits the context to the timeout of the hub RPC, if any
+func (conf *Config) withTimeout(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	timeout := conf.Timeout(method)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+/*
+newTimeoutInterceptor limits the unary RPCs to their timeout. The deadline is
+propagated by gRPC to the agents, which stop the commands they run for the RPC
+once it is exceeded. The streaming RPCs run as operations, which apply their
+timeout themselves.
+*/
+func (conf *Config) newTimeoutInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := conf.withTimeout(ctx, path.Base(info.FullMethod))
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,95 @@
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+func TestTimeout(t *testing.T) {
+	conf := &hub.Config{
+		Timeouts: map[string]int{
+			"MakeCluster":  7200,
+			"StatusAgents": 0,
+		},
+	}
+
+	cases := []struct {
+		method   string
+		expected time.Duration
+	}{
+		{"MakeCluster", 2 * time.Hour},
+		{"StatusAgents", 0},
+		{"StopAgents", time.Duration(hub.DefaultTimeouts["StopAgents"]) * time.Second},
+		{"AddMirrors", 0},
+	}
+
+	for _, c := range cases {
+		if timeout := conf.Timeout(c.method); timeout != c.expected {
+			t.Fatalf("got %s for %s, want %s", timeout, c.method, c.expected)
+		}
+	}
+}
+
+func TestExecuteRPC(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	conns := []*hub.Connection{{Hostname: "sdw1"}, {Hostname: "sdw2"}}
+
+	t.Run("gives the context to the requests", func(t *testing.T) {
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "value")
+
+		err := hub.ExecuteRPC(ctx, conns, func(ctx context.Context, conn *hub.Connection) error {
+			if ctx.Value(key{}) != "value" {
+				return errors.New("unexpected context")
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("does not call the agents when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		called := make(chan string, len(conns))
+		err := hub.ExecuteRPC(ctx, conns, func(ctx context.Context, conn *hub.Connection) error {
+			called <- conn.Hostname
+			return nil
+		})
+		close(called)
+
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want %v", err, context.Canceled)
+		}
+		if host, ok := <-called; ok {
+			t.Fatalf("unexpected call to %s", host)
+		}
+	})
+	t.Run("returns the errors of all the failed hosts", func(t *testing.T) {
+		err := hub.ExecuteRPC(context.Background(), conns, func(ctx context.Context, conn *hub.Connection) error {
+			return fmt.Errorf("failed on %s", conn.Hostname)
+		})
+
+		var hostErrs utils.HostErrors
+		if !errors.As(err, &hostErrs) {
+			t.Fatalf("got %T, want %T", err, hostErrs)
+		}
+
+		expected := "host: sdw1, failed on sdw1\nhost: sdw2, failed on sdw2"
+		if err.Error() != expected {
+			t.Fatalf("got %q, want %q", err, expected)
+		}
+	})
+}
@@ -0,0 +1,482 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+// Phases of the operations the topology snapshots are taken at
+const (
+	TopologyBefore = "before"
+	TopologyAfter  = "after"
+)
+
+// Kinds of changes of a segment between two topology snapshots
+const (
+	TopologyAdded   = "added"
+	TopologyRemoved = "removed"
+	TopologyMoved   = "moved"
+	TopologyRole    = "role"
+	TopologyPort    = "port"
+)
+
+// TopologySnapshot is the content of gp_segment_configuration recorded before or after an operation changed it
+type TopologySnapshot struct {
+	Version            int                `json:"version"`
+	Time               time.Time          `json:"time"`
+	OperationID        string             `json:"operationId,omitempty"`
+	Method             string             `json:"method,omitempty"`
+	Phase              string             `json:"phase"`
+	CoordinatorDataDir string             `json:"coordinatorDataDir"`
+	GpArray            *greenplum.GpArray `json:"gpArray"`
+}
+
+func (s *TopologySnapshot) toIdl() *idl.TopologySnapshot {
+	segments := len(s.GpArray.GetAllSegments())
+	if s.GpArray.Coordinator != nil {
+		segments++
+	}
+	if s.GpArray.Standby != nil {
+		segments++
+	}
+
+	return &idl.TopologySnapshot{
+		Version:     int32(s.Version),
+		Timestamp:   s.Time.Unix(),
+		OperationId: s.OperationID,
+		Method:      s.Method,
+		Phase:       s.Phase,
+		Segments:    int32(segments),
+	}
+}
+
+/*
+TopologyStore keeps the snapshots of the topology of the cluster in a directory,
+one file per version, so that the changes made by the operations can be
+reviewed and a known-good configuration applied again.
+*/
+type TopologyStore struct {
+	dir string
+
+	mutex sync.Mutex
+}
+
+func NewTopologyStore(dir string) *TopologyStore {
+	return &TopologyStore{dir: dir}
+}
+
+func (s *TopologyStore) path(version int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("topology-%06d.json", version))
+}
+
+/*
+Save records the snapshot as the next version, which it sets. The snapshot is
+not recorded if the last one is of the same operation with the same topology,
+in which case Save returns false.
+*/
+func (s *TopologyStore) Save(snapshot *TopologySnapshot) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	versions, err := s.versions()
+	if err != nil {
+		return false, err
+	}
+
+	snapshot.Version = 1
+	if len(versions) > 0 {
+		last, err := s.read(versions[len(versions)-1])
+		if err != nil {
+			return false, err
+		}
+
+		if last.OperationID != "" && last.OperationID == snapshot.OperationID && reflect.DeepEqual(last.GpArray, snapshot.GpArray) {
+			return false, nil
+		}
+		snapshot.Version = last.Version + 1
+	}
+	snapshot.Time = time.Now().UTC()
+
+	contents, err := json.MarshalIndent(snapshot, "", "\t")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal the topology snapshot: %w", err)
+	}
+
+	err = os.MkdirAll(s.dir, 0700)
+	if err != nil {
+		return false, fmt.Errorf("failed to create the topology directory %s: %w", s.dir, err)
+	}
+
+	filename := s.path(snapshot.Version)
+	err = utils.System.WriteFile(filename, contents, 0600)
+	if err != nil {
+		return false, fmt.Errorf("failed to write the topology snapshot %s: %w", filename, err)
+	}
+
+	return true, nil
+}
+
+// Get returns the snapshot of the given version
+func (s *TopologyStore) Get(version int) (*TopologySnapshot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.read(version)
+}
+
+// List returns all the snapshots, the oldest first
+func (s *TopologyStore) List() ([]*TopologySnapshot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	versions, err := s.versions()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*TopologySnapshot
+	for _, version := range versions {
+		snapshot, err := s.read(version)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+func (s *TopologyStore) read(version int) (*TopologySnapshot, error) {
+	filename := s.path(version)
+	contents, err := utils.System.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, grpcStatus.Errorf(codes.NotFound, "topology version %d not found", version)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the topology snapshot %s: %w", filename, err)
+	}
+
+	snapshot := &TopologySnapshot{}
+	err = json.Unmarshal(contents, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the topology snapshot %s: %w", filename, err)
+	}
+	if snapshot.GpArray == nil {
+		snapshot.GpArray = &greenplum.GpArray{}
+	}
+
+	return snapshot, nil
+}
+
+// versions returns the versions of the recorded snapshots in ascending order
+func (s *TopologyStore) versions() ([]int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the topology directory %s: %w", s.dir, err)
+	}
+
+	var versions []int
+	for _, entry := range entries {
+		name := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), "topology-"), ".json")
+		version, err := strconv.Atoi(name)
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	return versions, nil
+}
+
+/*
+DiffTopology returns how the segments changed from one topology to the other,
+matching them by dbid: the segments added or removed, and those which moved to
+another host or data directory, changed role or changed port.
+*/
+func DiffTopology(from *greenplum.GpArray, to *greenplum.GpArray) []*idl.TopologyChange {
+	fromSegs := topologySegments(from)
+	toSegs := topologySegments(to)
+
+	dbids := make(map[int]bool)
+	for dbid := range fromSegs {
+		dbids[dbid] = true
+	}
+	for dbid := range toSegs {
+		dbids[dbid] = true
+	}
+
+	sorted := make([]int, 0, len(dbids))
+	for dbid := range dbids {
+		sorted = append(sorted, dbid)
+	}
+	sort.Ints(sorted)
+
+	var changes []*idl.TopologyChange
+	for _, dbid := range sorted {
+		before, after := fromSegs[dbid], toSegs[dbid]
+		change := func(seg greenplum.Segment, kind string, from string, to string) {
+			changes = append(changes, &idl.TopologyChange{
+				Dbid:      int32(dbid),
+				Contentid: int32(seg.Content),
+				Kind:      kind,
+				From:      from,
+				To:        to,
+			})
+		}
+
+		switch {
+		case before == nil:
+			change(*after, TopologyAdded, "", segmentLocation(after))
+		case after == nil:
+			change(*before, TopologyRemoved, segmentLocation(before), "")
+		default:
+			if segmentLocation(before) != segmentLocation(after) {
+				change(*after, TopologyMoved, segmentLocation(before), segmentLocation(after))
+			}
+			if before.Role != after.Role {
+				change(*after, TopologyRole, roleName(before.Role), roleName(after.Role))
+			}
+			if before.Port != after.Port {
+				change(*after, TopologyPort, strconv.Itoa(before.Port), strconv.Itoa(after.Port))
+			}
+		}
+	}
+
+	return changes
+}
+
+// topologySegments returns all the segments of the gparray, including the coordinator and the standby, by dbid
+func topologySegments(gparray *greenplum.GpArray) map[int]*greenplum.Segment {
+	segs := make(map[int]*greenplum.Segment)
+	for _, seg := range []*greenplum.Segment{gparray.Coordinator, gparray.Standby} {
+		if seg != nil {
+			segs[seg.Dbid] = seg
+		}
+	}
+	for _, seg := range gparray.GetAllSegments() {
+		seg := seg
+		segs[seg.Dbid] = &seg
+	}
+
+	return segs
+}
+
+func segmentLocation(seg *greenplum.Segment) string {
+	if seg.Address != "" && seg.Address != seg.Hostname {
+		return fmt.Sprintf("%s(%s):%s", seg.Hostname, seg.Address, seg.DataDir)
+	}
+
+	return fmt.Sprintf("%s:%s", seg.Hostname, seg.DataDir)
+}
+
+func roleName(role string) string {
+	switch role {
+	case constants.RolePrimary:
+		return "primary"
+	case constants.RoleMirror:
+		return "mirror"
+	}
+
+	return role
+}
+
+/*
+snapshotTopology records the topology of the cluster before or after the
+operation run with the context changes it, unless it is the same as the last
+one recorded for the operation, e.g. when creating the cluster adds the mirrors.
+Failing to record it does not fail the operation, but is reported.
+*/
+func (s *Server) snapshotTopology(ctx context.Context, stream hubStreamer, coordinatorDataDir string, phase string, gparray *greenplum.GpArray) {
+	snapshot := &TopologySnapshot{
+		Phase:              phase,
+		CoordinatorDataDir: coordinatorDataDir,
+		GpArray:            gparray,
+	}
+	if op := operationFrom(ctx); op != nil {
+		snapshot.OperationID = op.ID
+		snapshot.Method = op.Method
+	}
+
+	saved, err := s.topology.Save(snapshot)
+	if err != nil {
+		gplog.Warn("failed to record the topology of the cluster %s the operation: %v", phase, err)
+		stream.StreamLogMsg(fmt.Sprintf("Failed to record the topology of the cluster %s the operation: %v", phase, err), idl.LogLevel_WARNING)
+		return
+	}
+
+	if saved {
+		gplog.Info("Recorded the topology of the cluster %s the operation as version %d", phase, snapshot.Version)
+	}
+}
+
+/*
+validateTopology checks the layout of the segments an operation is about to
+apply, reporting the layouts which work but are not advised as warnings.
+*/
+func validateTopology(stream hubStreamer, gparray *greenplum.GpArray, opts greenplum.ValidateOptions) error {
+	warnings, err := gparray.Validate(opts)
+	for _, warning := range warnings {
+		gplog.Warn(warning)
+		stream.StreamLogMsg(fmt.Sprintf("Checking the topology: %s", warning), idl.LogLevel_WARNING)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid topology:\n%w", err)
+	}
+
+	return nil
+}
+
+func (s *Server) ListTopology(ctx context.Context, request *idl.ListTopologyRequest) (*idl.ListTopologyReply, error) {
+	snapshots, err := s.topology.List()
+	if err != nil {
+		return nil, utils.LogAndReturnError(err)
+	}
+
+	reply := &idl.ListTopologyReply{}
+	for _, snapshot := range snapshots {
+		reply.Snapshots = append(reply.Snapshots, snapshot.toIdl())
+	}
+
+	return reply, nil
+}
+
+func (s *Server) DiffTopology(ctx context.Context, request *idl.DiffTopologyRequest) (*idl.DiffTopologyReply, error) {
+	from, err := s.topology.Get(int(request.From))
+	if err != nil {
+		return nil, utils.LogAndReturnError(err)
+	}
+
+	to, err := s.topology.Get(int(request.To))
+	if err != nil {
+		return nil, utils.LogAndReturnError(err)
+	}
+
+	return &idl.DiffTopologyReply{Changes: DiffTopology(from.GpArray, to.GpArray)}, nil
+}
+
+func (s *Server) RestoreTopology(request *idl.RestoreTopologyRequest, stream idl.Hub_RestoreTopologyServer) error {
+	snapshot, err := s.topology.Get(int(request.Version))
+	if err != nil {
+		return utils.LogAndReturnError(err)
+	}
+
+	return s.runOperation(stream, "RestoreTopology", func(ctx context.Context, hubStream *HubStream) error {
+		return s.restoreTopology(ctx, hubStream, snapshot)
+	})
+}
+
+/*
+ExportTopology returns the topology file of a recorded version, or of the
+current segment configuration of the cluster when no version is given.
+*/
+func (s *Server) ExportTopology(ctx context.Context, request *idl.ExportTopologyRequest) (*idl.ExportTopologyReply, error) {
+	var gparray *greenplum.GpArray
+	if request.Version != 0 {
+		snapshot, err := s.topology.Get(int(request.Version))
+		if err != nil {
+			return nil, utils.LogAndReturnError(err)
+		}
+		gparray = snapshot.GpArray
+	} else {
+		state, err := LoadClusterState(s.LogDir)
+		if err != nil {
+			return nil, utils.LogAndReturnError(grpcStatus.Errorf(codes.FailedPrecondition, "no cluster is managed by the hub: %v", err))
+		}
+
+		gparray, err = readGpArray(state.CoordinatorDataDir)
+		if err != nil {
+			return nil, utils.LogAndReturnError(err)
+		}
+	}
+
+	contents, err := greenplum.NewTopologyFile(gparray).Marshal(greenplum.TopologyFormatJSON)
+	if err != nil {
+		return nil, utils.LogAndReturnError(err)
+	}
+
+	return &idl.ExportTopologyReply{Topology: contents}, nil
+}
+
+/*
+restoreTopology writes the segments of the snapshot to gp_segment_configuration,
+recording the topology read from the catalog before and after it.
+*/
+func (s *Server) restoreTopology(ctx context.Context, hubStream hubStreamer, snapshot *TopologySnapshot) error {
+	hubStream.StreamLogMsg(fmt.Sprintf("Restoring the topology of version %d, recorded %s %s", snapshot.Version, snapshot.Phase, snapshot.Method))
+
+	err := validateTopology(hubStream, snapshot.GpArray, greenplum.ValidateOptions{})
+	if err != nil {
+		return utils.LogAndReturnError(err)
+	}
+
+	conn, err := greenplum.GetCoordinatorConn(snapshot.CoordinatorDataDir, "", true)
+	if err != nil {
+		return utils.LogAndReturnError(err)
+	}
+	defer conn.Close()
+
+	gparray, err := greenplum.NewGpArrayFromCatalog(conn)
+	if err != nil {
+		return utils.LogAndReturnError(err)
+	}
+	s.snapshotTopology(ctx, hubStream, snapshot.CoordinatorDataDir, TopologyBefore, gparray)
+
+	endStage := startStage(hubStream, StageRestoreTopology, "Restoring the segment configuration")
+	restored, err := restoreSegmentConfiguration(ctx, conn, snapshot.GpArray)
+	err = endStage(err)
+	if err != nil {
+		return utils.LogAndReturnError(fmt.Errorf("restoring the topology of version %d: %w", snapshot.Version, err))
+	}
+	s.snapshotTopology(ctx, hubStream, snapshot.CoordinatorDataDir, TopologyAfter, restored)
+
+	state, err := LoadClusterState(s.LogDir)
+	if err == nil {
+		state.GpArray = restored
+		err = s.SaveClusterState(state)
+	}
+	if err != nil {
+		gplog.Warn("failed to record the cluster state: %v", err)
+		hubStream.StreamLogMsg(fmt.Sprintf("Failed to record the cluster state: %v", err), idl.LogLevel_WARNING)
+	}
+
+	hubStream.StreamLogMsg(fmt.Sprintf("Restored the topology of version %d, restart the cluster for the segments to use it", snapshot.Version))
+
+	return nil
+}
+
+// restoreSegmentConfiguration writes the segments to the catalog and returns the resulting gparray
+func restoreSegmentConfiguration(ctx context.Context, conn *dbconn.DBConn, gparray *greenplum.GpArray) (restored *greenplum.GpArray, err error) {
+	_, span := utils.StartSpan(ctx, "RestoreSegmentConfiguration")
+	defer func() {
+		utils.EndSpan(span, err)
+	}()
+
+	err = greenplum.RestoreSegmentConfiguration(gparray, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return greenplum.NewGpArrayFromCatalog(conn)
+}
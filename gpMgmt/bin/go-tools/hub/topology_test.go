@@ -0,0 +1,345 @@
+package hub_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/greenplum-db/gp-common-go-libs/dbconn"
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"google.golang.org/grpc/codes"
+	grpcStatus "google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+func newTopology() *greenplum.GpArray {
+	return &greenplum.GpArray{
+		Coordinator: &greenplum.Segment{Dbid: 1, Content: -1, Role: constants.RolePrimary, Port: 7000, Hostname: "cdw", Address: "cdw", DataDir: "/data/coordinator/gpseg-1"},
+		SegmentPairs: []greenplum.SegmentPair{
+			{
+				Primary: &greenplum.Segment{Dbid: 2, Content: 0, Role: constants.RolePrimary, Port: 7002, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/primary/gpseg0"},
+				Mirror:  &greenplum.Segment{Dbid: 4, Content: 0, Role: constants.RoleMirror, Port: 7003, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/mirror/gpseg0"},
+			},
+			{
+				Primary: &greenplum.Segment{Dbid: 3, Content: 1, Role: constants.RolePrimary, Port: 7002, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/primary/gpseg1"},
+			},
+		},
+	}
+}
+
+func TestTopologyStore(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("records the snapshots as new versions", func(t *testing.T) {
+		store := hub.NewTopologyStore(filepath.Join(t.TempDir(), constants.TopologyDirName))
+
+		for i, phase := range []string{hub.TopologyBefore, hub.TopologyAfter} {
+			snapshot := &hub.TopologySnapshot{OperationID: "1234", Method: "AddMirrors", Phase: phase, CoordinatorDataDir: "/data/coordinator/gpseg-1", GpArray: newTopology()}
+			if i == 1 {
+				snapshot.GpArray.SegmentPairs[1].Mirror = &greenplum.Segment{Dbid: 5, Content: 1, Role: constants.RoleMirror, Port: 7003, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/mirror/gpseg1"}
+			}
+
+			saved, err := store.Save(snapshot)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !saved || snapshot.Version != i+1 || snapshot.Time.IsZero() {
+				t.Fatalf("got snapshot %+v saved %t, want version %d to be saved", snapshot, saved, i+1)
+			}
+		}
+
+		snapshots, err := store.List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(snapshots) != 2 || snapshots[0].Phase != hub.TopologyBefore || snapshots[1].Phase != hub.TopologyAfter {
+			t.Fatalf("got %+v, want the snapshots before and after the operation", snapshots)
+		}
+		if !reflect.DeepEqual(snapshots[0].GpArray, newTopology()) {
+			t.Fatalf("got %+v, want %+v", snapshots[0].GpArray, newTopology())
+		}
+
+		snapshot, err := store.Get(2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(snapshot, snapshots[1]) {
+			t.Fatalf("got %+v, want %+v", snapshot, snapshots[1])
+		}
+	})
+
+	t.Run("does not record the same topology twice for an operation", func(t *testing.T) {
+		store := hub.NewTopologyStore(filepath.Join(t.TempDir(), constants.TopologyDirName))
+
+		snapshots := []*hub.TopologySnapshot{
+			{OperationID: "1234", Method: "MakeCluster", Phase: hub.TopologyAfter, GpArray: newTopology()},
+			{OperationID: "1234", Method: "MakeCluster", Phase: hub.TopologyBefore, GpArray: newTopology()},
+			{OperationID: "5678", Method: "RestoreTopology", Phase: hub.TopologyBefore, GpArray: newTopology()},
+		}
+		var saved []bool
+		for _, snapshot := range snapshots {
+			ok, err := store.Save(snapshot)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			saved = append(saved, ok)
+		}
+
+		expected := []bool{true, false, true}
+		if !reflect.DeepEqual(saved, expected) {
+			t.Fatalf("got %v, want %v", saved, expected)
+		}
+		if snapshots[2].Version != 2 {
+			t.Fatalf("got version %d, want 2", snapshots[2].Version)
+		}
+	})
+
+	t.Run("creates the snapshots readable by their owner only", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), constants.TopologyDirName)
+		store := hub.NewTopologyStore(dir)
+
+		_, err := store.Save(&hub.TopologySnapshot{Phase: hub.TopologyAfter, GpArray: newTopology()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		info, err := os.Stat(filepath.Join(dir, "topology-000001.json"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("got mode %v, want %v", info.Mode().Perm(), os.FileMode(0600))
+		}
+	})
+
+	t.Run("errors out when the version does not exist", func(t *testing.T) {
+		store := hub.NewTopologyStore(filepath.Join(t.TempDir(), constants.TopologyDirName))
+
+		snapshots, err := store.List()
+		if err != nil || len(snapshots) != 0 {
+			t.Fatalf("got %+v and %v, want no snapshots", snapshots, err)
+		}
+
+		_, err = store.Get(3)
+		if grpcStatus.Code(err) != codes.NotFound || grpcStatus.Convert(err).Message() != "topology version 3 not found" {
+			t.Fatalf("got %v, want topology version 3 not found", err)
+		}
+	})
+}
+
+func TestDiffTopology(t *testing.T) {
+	t.Run("returns no changes for the same topology", func(t *testing.T) {
+		changes := hub.DiffTopology(newTopology(), newTopology())
+		if len(changes) != 0 {
+			t.Fatalf("got %+v, want no changes", changes)
+		}
+	})
+
+	t.Run("returns the segments which were added, removed, moved or changed role or port", func(t *testing.T) {
+		to := newTopology()
+		to.SegmentPairs[0].Primary, to.SegmentPairs[0].Mirror = to.SegmentPairs[0].Mirror, to.SegmentPairs[0].Primary
+		to.SegmentPairs[0].Primary.Role = constants.RolePrimary
+		to.SegmentPairs[0].Mirror.Role = constants.RoleMirror
+		to.SegmentPairs[1].Primary.Hostname = "sdw3"
+		to.SegmentPairs[1].Primary.Address = "sdw3"
+		to.SegmentPairs[1].Primary.Port = 7004
+		to.SegmentPairs[1].Mirror = &greenplum.Segment{Dbid: 5, Content: 1, Role: constants.RoleMirror, Port: 7003, Hostname: "sdw1", Address: "sdw1-eth1", DataDir: "/data/mirror/gpseg1"}
+		to.Coordinator = nil
+
+		changes := hub.DiffTopology(newTopology(), to)
+
+		expected := []*idl.TopologyChange{
+			{Dbid: 1, Contentid: -1, Kind: hub.TopologyRemoved, From: "cdw:/data/coordinator/gpseg-1"},
+			{Dbid: 2, Contentid: 0, Kind: hub.TopologyRole, From: "primary", To: "mirror"},
+			{Dbid: 3, Contentid: 1, Kind: hub.TopologyMoved, From: "sdw2:/data/primary/gpseg1", To: "sdw3:/data/primary/gpseg1"},
+			{Dbid: 3, Contentid: 1, Kind: hub.TopologyPort, From: "7002", To: "7004"},
+			{Dbid: 4, Contentid: 0, Kind: hub.TopologyRole, From: "mirror", To: "primary"},
+			{Dbid: 5, Contentid: 1, Kind: hub.TopologyAdded, To: "sdw1(sdw1-eth1):/data/mirror/gpseg1"},
+		}
+		if !reflect.DeepEqual(changes, expected) {
+			t.Fatalf("got %+v, want %+v", changes, expected)
+		}
+	})
+}
+
+func TestTopologyRPCs(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	hubServer := hub.New(&hub.Config{LogDir: t.TempDir()}, nil)
+	store := hub.NewTopologyStore(filepath.Join(hubServer.LogDir, constants.TopologyDirName))
+
+	after := newTopology()
+	after.SegmentPairs[1].Primary.Port = 7004
+	for _, snapshot := range []*hub.TopologySnapshot{
+		{OperationID: "1234", Method: "AddMirrors", Phase: hub.TopologyBefore, GpArray: newTopology()},
+		{OperationID: "1234", Method: "AddMirrors", Phase: hub.TopologyAfter, GpArray: after},
+	} {
+		_, err := store.Save(snapshot)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("lists the snapshots", func(t *testing.T) {
+		reply, err := hubServer.ListTopology(context.Background(), &idl.ListTopologyRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(reply.Snapshots) != 2 {
+			t.Fatalf("got %d snapshots, want 2", len(reply.Snapshots))
+		}
+		for i, snapshot := range reply.Snapshots {
+			snapshot.Timestamp = 0
+			expected := &idl.TopologySnapshot{Version: int32(i + 1), OperationId: "1234", Method: "AddMirrors", Phase: []string{hub.TopologyBefore, hub.TopologyAfter}[i], Segments: 4}
+			if !reflect.DeepEqual(snapshot, expected) {
+				t.Fatalf("got %+v, want %+v", snapshot, expected)
+			}
+		}
+	})
+
+	t.Run("returns the changes between two versions", func(t *testing.T) {
+		reply, err := hubServer.DiffTopology(context.Background(), &idl.DiffTopologyRequest{From: 1, To: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []*idl.TopologyChange{{Dbid: 3, Contentid: 1, Kind: hub.TopologyPort, From: "7002", To: "7004"}}
+		if !reflect.DeepEqual(reply.Changes, expected) {
+			t.Fatalf("got %+v, want %+v", reply.Changes, expected)
+		}
+	})
+
+	t.Run("errors out when a version does not exist", func(t *testing.T) {
+		_, err := hubServer.DiffTopology(context.Background(), &idl.DiffTopologyRequest{From: 1, To: 3})
+		if grpcStatus.Code(err) != codes.NotFound {
+			t.Fatalf("got %v, want %v", err, codes.NotFound)
+		}
+
+		err = hubServer.RestoreTopology(&idl.RestoreTopologyRequest{Version: 3}, newAttachedStream(context.Background()))
+		if grpcStatus.Code(err) != codes.NotFound {
+			t.Fatalf("got %v, want %v", err, codes.NotFound)
+		}
+
+		_, err = hubServer.ExportTopology(context.Background(), &idl.ExportTopologyRequest{Version: 3})
+		if grpcStatus.Code(err) != codes.NotFound {
+			t.Fatalf("got %v, want %v", err, codes.NotFound)
+		}
+	})
+
+	t.Run("exports a version as a topology file", func(t *testing.T) {
+		reply, err := hubServer.ExportTopology(context.Background(), &idl.ExportTopologyRequest{Version: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		file, err := greenplum.ParseTopologyFile(reply.Topology)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(file, greenplum.NewTopologyFile(after)) {
+			t.Fatalf("got %+v, want %+v", file, greenplum.NewTopologyFile(after))
+		}
+	})
+
+	t.Run("errors out exporting the current topology when no cluster is managed", func(t *testing.T) {
+		_, err := hubServer.ExportTopology(context.Background(), &idl.ExportTopologyRequest{})
+		if grpcStatus.Code(err) != codes.FailedPrecondition {
+			t.Fatalf("got %v, want %v", err, codes.FailedPrecondition)
+		}
+	})
+
+	t.Run("records the topology read back from the catalog after restoring a version", func(t *testing.T) {
+		defer utils.ResetSystemFunctions()
+		utils.System.Open = func(name string) (*os.File, error) {
+			reader, writer, _ := os.Pipe()
+			defer writer.Close()
+
+			_, err := writer.WriteString("port=7000")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			return reader, nil
+		}
+
+		withMirrors := func() *greenplum.GpArray {
+			gparray := newTopology()
+			gparray.SegmentPairs[1].Mirror = &greenplum.Segment{Dbid: 5, Content: 1, Role: constants.RoleMirror, Port: 7003, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/mirror/gpseg1"}
+
+			return gparray
+		}
+		snapshot := &hub.TopologySnapshot{OperationID: "5678", Method: "AddMirrors", Phase: hub.TopologyAfter, GpArray: withMirrors()}
+		_, err := store.Save(snapshot)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// the catalog does not end up with exactly the segments which were written
+		catalog := withMirrors()
+		catalog.SegmentPairs[1].Primary.Port = 7005
+
+		greenplum.SetNewDBConnFromEnvironment(func(dbname string) *dbconn.DBConn {
+			conn, mock := testutils.CreateMockDBConnForUtilityMode(t)
+			testhelper.ExpectVersionQuery(mock, "7.0.0")
+
+			mock.ExpectQuery("SELECT").WillReturnRows(topologyRows(t, withMirrors()))
+			mock.ExpectBegin()
+			mock.ExpectExec("SET TRANSACTION").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(0, 4))
+			for i := 0; i < 5; i++ {
+				mock.ExpectExec("INSERT").WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+			mock.ExpectCommit()
+			mock.ExpectQuery("SELECT").WillReturnRows(topologyRows(t, catalog))
+
+			return conn
+		})
+		defer greenplum.ResetNewDBConnFromEnvironment()
+
+		err = hubServer.RestoreTopology(&idl.RestoreTopologyRequest{Version: int32(snapshot.Version)}, newAttachedStream(context.Background()))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		snapshots, err := store.List()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		restored := snapshots[len(snapshots)-1]
+		if restored.Method != "RestoreTopology" || restored.Phase != hub.TopologyAfter {
+			t.Fatalf("got %+v, want the topology after RestoreTopology", restored)
+		}
+
+		changes := hub.DiffTopology(catalog, restored.GpArray)
+		if len(changes) != 0 {
+			t.Fatalf("got %+v, want the topology of the catalog", changes)
+		}
+	})
+}
+
+// topologyRows returns the rows of gp_segment_configuration for the segments of the gparray
+func topologyRows(t *testing.T, gparray *greenplum.GpArray) *sqlmock.Rows {
+	t.Helper()
+
+	rows := sqlmock.NewRows([]string{"dbid", "content", "role", "preferredrole", "port", "hostname", "address", "datadir"})
+	addSegmentRows(t, rows, gparray.Coordinator)
+	for _, pair := range gparray.SegmentPairs {
+		addSegmentRows(t, rows, pair.Primary)
+		if pair.Mirror != nil {
+			addSegmentRows(t, rows, pair.Mirror)
+		}
+	}
+
+	return rows
+}
@@ -0,0 +1,193 @@
+package hub
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// maximum number of agent requests run at the same time unless configured otherwise, like the batch size of gpinitsystem
+const DefaultParallel = 60
+
+// metadata set by the CLI when the command is run with --parallel
+const ParallelMetadataKey = "gp-parallel"
+
+/*
+WorkerPool bounds how many agent requests the hub runs at the same time, both in
+total and on each host, so that creating a large cluster does not run an initdb
+or a pg_basebackup for every segment at once. All the fan-outs of all the RPCs
+share the pool of the hub, an RPC run with a lower limit drawing from it through
+a limited view.
+*/
+type WorkerPool struct {
+	global  chan struct{} // nil if not limited
+	perHost int           // 0 if not limited
+
+	parent *WorkerPool   // pool the slots are drawn from, nil if this is the shared pool
+	limit  chan struct{} // requests of the view running at the same time, nil if not a view
+
+	mutex sync.Mutex
+	hosts map[string]chan struct{}
+}
+
+// NewWorkerPool creates a pool running at most parallel requests in total and parallelPerHost on each host, 0 meaning no limit
+func NewWorkerPool(parallel int, parallelPerHost int) *WorkerPool {
+	p := &WorkerPool{
+		perHost: parallelPerHost,
+		hosts:   make(map[string]chan struct{}),
+	}
+	if parallel > 0 {
+		p.global = make(chan struct{}, parallel)
+	}
+
+	return p
+}
+
+/*
+Limit returns a view of the pool running at most parallel requests at the same
+time, which still draws from the slots of the pool, so that the requests of the
+view count against its limits and never go above them.
+*/
+func (p *WorkerPool) Limit(parallel int) *WorkerPool {
+	shared := p
+	if p.parent != nil {
+		shared = p.parent
+	}
+	if shared.global != nil && parallel > cap(shared.global) {
+		parallel = cap(shared.global)
+	}
+
+	return &WorkerPool{
+		parent: shared,
+		limit:  make(chan struct{}, parallel),
+	}
+}
+
+func (p *WorkerPool) hostSlots(host string) chan struct{} {
+	if p.perHost <= 0 {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	slots, ok := p.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, p.perHost)
+		p.hosts[host] = slots
+	}
+
+	return slots
+}
+
+/*
+Run runs the request against the host once the limits allow it, unless the
+context is done first. Requests run through the pool must not run other
+requests through it, as they could wait forever for each other.
+*/
+func (p *WorkerPool) Run(ctx context.Context, host string, request func() error) error {
+	shared := p
+	if p.parent != nil {
+		shared = p.parent
+	}
+
+	// take the slot of the host first, so that the requests waiting for a busy host do not hold the other slots
+	hostSlots := shared.hostSlots(host)
+	err := acquireSlot(ctx, hostSlots)
+	if err != nil {
+		return err
+	}
+	defer releaseSlot(hostSlots)
+
+	err = acquireSlot(ctx, p.limit)
+	if err != nil {
+		return err
+	}
+	defer releaseSlot(p.limit)
+
+	err = acquireSlot(ctx, shared.global)
+	if err != nil {
+		return err
+	}
+	defer releaseSlot(shared.global)
+
+	return request()
+}
+
+func acquireSlot(ctx context.Context, slots chan struct{}) error {
+	if slots == nil {
+		return nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func releaseSlot(slots chan struct{}) {
+	if slots != nil {
+		<-slots
+	}
+}
+
+type workerPoolKey struct{}
+
+// WithWorkerPool makes the agent requests of the RPC run through the pool
+func WithWorkerPool(ctx context.Context, pool *WorkerPool) context.Context {
+	return context.WithValue(ctx, workerPoolKey{}, pool)
+}
+
+// unlimited pool for the requests made outside of an RPC
+var unlimitedWorkerPool = NewWorkerPool(0, 0)
+
+func workerPoolFrom(ctx context.Context) *WorkerPool {
+	pool, ok := ctx.Value(workerPoolKey{}).(*WorkerPool)
+	if !ok {
+		return unlimitedWorkerPool
+	}
+
+	return pool
+}
+
+func (conf *Config) parallel() int {
+	if conf.Parallel == 0 {
+		return DefaultParallel
+	}
+
+	return conf.Parallel
+}
+
+/*
+workerPoolFor returns the pool the agent requests of the RPC run through: the
+one of the hub, limited for the RPC if the caller asked for a parallel limit.
+The limit of the caller can only lower the one of the hub.
+*/
+func (s *Server) workerPoolFor(ctx context.Context) *WorkerPool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(ParallelMetadataKey)
+	if len(values) == 0 {
+		return s.workers
+	}
+
+	parallel, err := strconv.Atoi(values[0])
+	if err != nil || parallel <= 0 {
+		gplog.Warn("ignoring the invalid parallel %q requested by the caller", values[0])
+		return s.workers
+	}
+
+	return s.workers.Limit(parallel)
+}
+
+// newWorkerPoolInterceptor runs the agent requests of the unary RPCs through their worker pool
+func (s *Server) newWorkerPoolInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(WithWorkerPool(ctx, s.workerPoolFor(ctx)), req)
+	}
+}
@@ -0,0 +1,173 @@
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+
+	"github.com/greenplum-db/gpdb/gp/hub"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+// maxConcurrency runs the requests on the hosts through each pool and returns how many ran at the same time at most
+func maxConcurrency(hosts []string, pools ...*hub.WorkerPool) int {
+	var running, max int32
+	var wg sync.WaitGroup
+	for _, pool := range pools {
+		pool := pool
+		for _, host := range hosts {
+			wg.Add(1)
+			go func(host string) {
+				defer wg.Done()
+				_ = pool.Run(context.Background(), host, func() error {
+					current := atomic.AddInt32(&running, 1)
+					for {
+						seen := atomic.LoadInt32(&max)
+						if current <= seen || atomic.CompareAndSwapInt32(&max, seen, current) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+
+					return nil
+				})
+			}(host)
+		}
+	}
+	wg.Wait()
+
+	return int(max)
+}
+
+func TestWorkerPool(t *testing.T) {
+	hosts := []string{"sdw1", "sdw1", "sdw1", "sdw1", "sdw2", "sdw2", "sdw2", "sdw2"}
+
+	t.Run("limits the number of requests running at the same time", func(t *testing.T) {
+		if max := maxConcurrency(hosts, hub.NewWorkerPool(3, 0)); max > 3 {
+			t.Fatalf("got %d requests at the same time, want at most %d", max, 3)
+		}
+	})
+
+	t.Run("limits the number of requests running at the same time on each host", func(t *testing.T) {
+		if max := maxConcurrency(hosts, hub.NewWorkerPool(0, 1)); max > 2 {
+			t.Fatalf("got %d requests at the same time, want at most %d", max, 2)
+		}
+	})
+
+	t.Run("limits the requests of a limited view of the pool", func(t *testing.T) {
+		if max := maxConcurrency(hosts, hub.NewWorkerPool(3, 0).Limit(2)); max > 2 {
+			t.Fatalf("got %d requests at the same time, want at most %d", max, 2)
+		}
+	})
+
+	t.Run("keeps the limited views of the pool within the limits of the pool", func(t *testing.T) {
+		pool := hub.NewWorkerPool(3, 0)
+		if max := maxConcurrency(hosts, pool.Limit(10), pool.Limit(10)); max > 3 {
+			t.Fatalf("got %d requests at the same time, want at most %d", max, 3)
+		}
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		pool := hub.NewWorkerPool(1, 0)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			_ = pool.Run(context.Background(), "sdw1", func() error {
+				close(started)
+				<-release
+				return nil
+			})
+		}()
+		<-started
+		defer close(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := pool.Run(ctx, "sdw2", func() error {
+			t.Fatalf("unexpected request")
+			return nil
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestExecuteRPCForEach(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	conns := []*hub.Connection{{Hostname: "sdw1"}, {Hostname: "sdw2"}}
+	items := map[string][]string{
+		"sdw1": {"/data/primary/gpseg0", "/data/primary/gpseg1"},
+		"sdw2": {"/data/primary/gpseg2", "/data/primary/gpseg3"},
+	}
+
+	t.Run("runs the request for every item of every host", func(t *testing.T) {
+		var mutex sync.Mutex
+		var called []string
+		err := hub.ExecuteRPCForEach(context.Background(), conns, items, func(ctx context.Context, conn *hub.Connection, item string) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			called = append(called, conn.Hostname+":"+item)
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(called) != 4 {
+			t.Fatalf("got %v, want a call for each item", called)
+		}
+	})
+
+	t.Run("runs the requests through the worker pool of the context", func(t *testing.T) {
+		ctx := hub.WithWorkerPool(context.Background(), hub.NewWorkerPool(1, 0))
+
+		var running, max int32
+		err := hub.ExecuteRPCForEach(ctx, conns, items, func(ctx context.Context, conn *hub.Connection, item string) error {
+			if current := atomic.AddInt32(&running, 1); current > atomic.LoadInt32(&max) {
+				atomic.StoreInt32(&max, current)
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if max != 1 {
+			t.Fatalf("got %d requests at the same time, want %d", max, 1)
+		}
+	})
+
+	t.Run("returns the errors of all the failed requests", func(t *testing.T) {
+		expected := errors.New("error")
+		err := hub.ExecuteRPCForEach(context.Background(), conns, items, func(ctx context.Context, conn *hub.Connection, item string) error {
+			if strings.HasSuffix(item, "gpseg1") || strings.HasSuffix(item, "gpseg2") {
+				return utils.NewHostError("RemoveDirectory", item, expected)
+			}
+
+			return nil
+		})
+		if !errors.Is(err, expected) {
+			t.Fatalf("got %v, want %v", err, expected)
+		}
+
+		expectedErr := "host: sdw1, segment: /data/primary/gpseg1, error\nhost: sdw2, segment: /data/primary/gpseg2, error"
+		if err.Error() != expectedErr {
+			t.Fatalf("got %q, want %q", err, expectedErr)
+		}
+	})
+}
@@ -285,6 +285,7 @@ type StatusAgentReply struct {
 	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	Uptime               string   `protobuf:"bytes,2,opt,name=uptime,proto3" json:"uptime,omitempty"`
 	Pid                  uint32   `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	CertificateExpiry    int64    `protobuf:"varint,4,opt,name=certificateExpiry,proto3" json:"certificateExpiry,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -336,6 +337,13 @@ func (m *StatusAgentReply) GetPid() uint32 {
 	return 0
 }
 
+func (m *StatusAgentReply) GetCertificateExpiry() int64 {
+	if m != nil {
+		return m.CertificateExpiry
+	}
+	return 0
+}
+
 type ValidateHostEnvRequest struct {
 	HostAddressList      []string `protobuf:"bytes,1,rep,name=hostAddressList,proto3" json:"hostAddressList,omitempty"`
 	DirectoryList        []string `protobuf:"bytes,2,rep,name=DirectoryList,proto3" json:"DirectoryList,omitempty"`
@@ -948,6 +956,182 @@ func (m *PgBasebackupResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PgBasebackupResponse proto.InternalMessageInfo
 
+// reply of the streaming variants of the RPCs running a command for a segment, like initdb or pg_basebackup
+type SegmentCommandReply struct {
+	// Types that are valid to be assigned to Message:
+	//	*SegmentCommandReply_Output
+	//	*SegmentCommandReply_Progress
+	Message              isSegmentCommandReply_Message `protobuf_oneof:"message"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *SegmentCommandReply) Reset()         { *m = SegmentCommandReply{} }
+func (m *SegmentCommandReply) String() string { return proto.CompactTextString(m) }
+func (*SegmentCommandReply) ProtoMessage()    {}
+func (*SegmentCommandReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{20}
+}
+
+func (m *SegmentCommandReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentCommandReply.Unmarshal(m, b)
+}
+func (m *SegmentCommandReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentCommandReply.Marshal(b, m, deterministic)
+}
+func (m *SegmentCommandReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentCommandReply.Merge(m, src)
+}
+func (m *SegmentCommandReply) XXX_Size() int {
+	return xxx_messageInfo_SegmentCommandReply.Size(m)
+}
+func (m *SegmentCommandReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentCommandReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentCommandReply proto.InternalMessageInfo
+
+type isSegmentCommandReply_Message interface {
+	isSegmentCommandReply_Message()
+}
+
+type SegmentCommandReply_Output struct {
+	Output *OutputLine `protobuf:"bytes,1,opt,name=output,proto3,oneof"`
+}
+
+type SegmentCommandReply_Progress struct {
+	Progress *TransferProgress `protobuf:"bytes,2,opt,name=progress,proto3,oneof"`
+}
+
+func (*SegmentCommandReply_Output) isSegmentCommandReply_Message() {}
+
+func (*SegmentCommandReply_Progress) isSegmentCommandReply_Message() {}
+
+func (m *SegmentCommandReply) GetMessage() isSegmentCommandReply_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (m *SegmentCommandReply) GetOutput() *OutputLine {
+	if x, ok := m.GetMessage().(*SegmentCommandReply_Output); ok {
+		return x.Output
+	}
+	return nil
+}
+
+func (m *SegmentCommandReply) GetProgress() *TransferProgress {
+	if x, ok := m.GetMessage().(*SegmentCommandReply_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*SegmentCommandReply) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*SegmentCommandReply_Output)(nil),
+		(*SegmentCommandReply_Progress)(nil),
+	}
+}
+
+type OutputLine struct {
+	Stream               OutputStream `protobuf:"varint,1,opt,name=stream,proto3,enum=idl.OutputStream" json:"stream,omitempty"`
+	Line                 string       `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *OutputLine) Reset()         { *m = OutputLine{} }
+func (m *OutputLine) String() string { return proto.CompactTextString(m) }
+func (*OutputLine) ProtoMessage()    {}
+func (*OutputLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{21}
+}
+
+func (m *OutputLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OutputLine.Unmarshal(m, b)
+}
+func (m *OutputLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OutputLine.Marshal(b, m, deterministic)
+}
+func (m *OutputLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutputLine.Merge(m, src)
+}
+func (m *OutputLine) XXX_Size() int {
+	return xxx_messageInfo_OutputLine.Size(m)
+}
+func (m *OutputLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutputLine.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutputLine proto.InternalMessageInfo
+
+func (m *OutputLine) GetStream() OutputStream {
+	if m != nil {
+		return m.Stream
+	}
+	return OutputStream_STDOUT
+}
+
+func (m *OutputLine) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
+// data copied so far and estimated total, in bytes
+type TransferProgress struct {
+	Done                 int64    `protobuf:"varint,1,opt,name=done,proto3" json:"done,omitempty"`
+	Total                int64    `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransferProgress) Reset()         { *m = TransferProgress{} }
+func (m *TransferProgress) String() string { return proto.CompactTextString(m) }
+func (*TransferProgress) ProtoMessage()    {}
+func (*TransferProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{22}
+}
+
+func (m *TransferProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferProgress.Unmarshal(m, b)
+}
+func (m *TransferProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferProgress.Marshal(b, m, deterministic)
+}
+func (m *TransferProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferProgress.Merge(m, src)
+}
+func (m *TransferProgress) XXX_Size() int {
+	return xxx_messageInfo_TransferProgress.Size(m)
+}
+func (m *TransferProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransferProgress proto.InternalMessageInfo
+
+func (m *TransferProgress) GetDone() int64 {
+	if m != nil {
+		return m.Done
+	}
+	return 0
+}
+
+func (m *TransferProgress) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
 type RemoveDirectoryRequest struct {
 	DataDirectory        string   `protobuf:"bytes,1,opt,name=dataDirectory,proto3" json:"dataDirectory,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -959,7 +1143,7 @@ func (m *RemoveDirectoryRequest) Reset()         { *m = RemoveDirectoryRequest{}
 func (m *RemoveDirectoryRequest) String() string { return proto.CompactTextString(m) }
 func (*RemoveDirectoryRequest) ProtoMessage()    {}
 func (*RemoveDirectoryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{20}
+	return fileDescriptor_56ede974c0020f77, []int{23}
 }
 
 func (m *RemoveDirectoryRequest) XXX_Unmarshal(b []byte) error {
@@ -997,7 +1181,7 @@ func (m *RemoveDirectoryReply) Reset()         { *m = RemoveDirectoryReply{} }
 func (m *RemoveDirectoryReply) String() string { return proto.CompactTextString(m) }
 func (*RemoveDirectoryReply) ProtoMessage()    {}
 func (*RemoveDirectoryReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_56ede974c0020f77, []int{21}
+	return fileDescriptor_56ede974c0020f77, []int{24}
 }
 
 func (m *RemoveDirectoryReply) XXX_Unmarshal(b []byte) error {
@@ -1018,6 +1202,350 @@ func (m *RemoveDirectoryReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_RemoveDirectoryReply proto.InternalMessageInfo
 
+type VerifyDataDirectoriesRequest struct {
+	DataDirectories      []string `protobuf:"bytes,1,rep,name=dataDirectories,proto3" json:"dataDirectories,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyDataDirectoriesRequest) Reset()         { *m = VerifyDataDirectoriesRequest{} }
+func (m *VerifyDataDirectoriesRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyDataDirectoriesRequest) ProtoMessage()    {}
+func (*VerifyDataDirectoriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{25}
+}
+
+func (m *VerifyDataDirectoriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyDataDirectoriesRequest.Unmarshal(m, b)
+}
+func (m *VerifyDataDirectoriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyDataDirectoriesRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyDataDirectoriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyDataDirectoriesRequest.Merge(m, src)
+}
+func (m *VerifyDataDirectoriesRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyDataDirectoriesRequest.Size(m)
+}
+func (m *VerifyDataDirectoriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyDataDirectoriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyDataDirectoriesRequest proto.InternalMessageInfo
+
+func (m *VerifyDataDirectoriesRequest) GetDataDirectories() []string {
+	if m != nil {
+		return m.DataDirectories
+	}
+	return nil
+}
+
+type VerifyDataDirectoriesReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyDataDirectoriesReply) Reset()         { *m = VerifyDataDirectoriesReply{} }
+func (m *VerifyDataDirectoriesReply) String() string { return proto.CompactTextString(m) }
+func (*VerifyDataDirectoriesReply) ProtoMessage()    {}
+func (*VerifyDataDirectoriesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{26}
+}
+
+func (m *VerifyDataDirectoriesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyDataDirectoriesReply.Unmarshal(m, b)
+}
+func (m *VerifyDataDirectoriesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyDataDirectoriesReply.Marshal(b, m, deterministic)
+}
+func (m *VerifyDataDirectoriesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyDataDirectoriesReply.Merge(m, src)
+}
+func (m *VerifyDataDirectoriesReply) XXX_Size() int {
+	return xxx_messageInfo_VerifyDataDirectoriesReply.Size(m)
+}
+func (m *VerifyDataDirectoriesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyDataDirectoriesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyDataDirectoriesReply proto.InternalMessageInfo
+
+type GetFreePortRangesRequest struct {
+	MinPort              int32    `protobuf:"varint,1,opt,name=minPort,proto3" json:"minPort,omitempty"`
+	MaxPort              int32    `protobuf:"varint,2,opt,name=maxPort,proto3" json:"maxPort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetFreePortRangesRequest) Reset()         { *m = GetFreePortRangesRequest{} }
+func (m *GetFreePortRangesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetFreePortRangesRequest) ProtoMessage()    {}
+func (*GetFreePortRangesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{27}
+}
+
+func (m *GetFreePortRangesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFreePortRangesRequest.Unmarshal(m, b)
+}
+func (m *GetFreePortRangesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFreePortRangesRequest.Marshal(b, m, deterministic)
+}
+func (m *GetFreePortRangesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFreePortRangesRequest.Merge(m, src)
+}
+func (m *GetFreePortRangesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetFreePortRangesRequest.Size(m)
+}
+func (m *GetFreePortRangesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFreePortRangesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFreePortRangesRequest proto.InternalMessageInfo
+
+func (m *GetFreePortRangesRequest) GetMinPort() int32 {
+	if m != nil {
+		return m.MinPort
+	}
+	return 0
+}
+
+func (m *GetFreePortRangesRequest) GetMaxPort() int32 {
+	if m != nil {
+		return m.MaxPort
+	}
+	return 0
+}
+
+type PortRange struct {
+	Start                int32    `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End                  int32    `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PortRange) Reset()         { *m = PortRange{} }
+func (m *PortRange) String() string { return proto.CompactTextString(m) }
+func (*PortRange) ProtoMessage()    {}
+func (*PortRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{28}
+}
+
+func (m *PortRange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PortRange.Unmarshal(m, b)
+}
+func (m *PortRange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PortRange.Marshal(b, m, deterministic)
+}
+func (m *PortRange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PortRange.Merge(m, src)
+}
+func (m *PortRange) XXX_Size() int {
+	return xxx_messageInfo_PortRange.Size(m)
+}
+func (m *PortRange) XXX_DiscardUnknown() {
+	xxx_messageInfo_PortRange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PortRange proto.InternalMessageInfo
+
+func (m *PortRange) GetStart() int32 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *PortRange) GetEnd() int32 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
+type GetFreePortRangesReply struct {
+	Ranges               []*PortRange `protobuf:"bytes,1,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetFreePortRangesReply) Reset()         { *m = GetFreePortRangesReply{} }
+func (m *GetFreePortRangesReply) String() string { return proto.CompactTextString(m) }
+func (*GetFreePortRangesReply) ProtoMessage()    {}
+func (*GetFreePortRangesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{29}
+}
+
+func (m *GetFreePortRangesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFreePortRangesReply.Unmarshal(m, b)
+}
+func (m *GetFreePortRangesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFreePortRangesReply.Marshal(b, m, deterministic)
+}
+func (m *GetFreePortRangesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFreePortRangesReply.Merge(m, src)
+}
+func (m *GetFreePortRangesReply) XXX_Size() int {
+	return xxx_messageInfo_GetFreePortRangesReply.Size(m)
+}
+func (m *GetFreePortRangesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFreePortRangesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFreePortRangesReply proto.InternalMessageInfo
+
+func (m *GetFreePortRangesReply) GetRanges() []*PortRange {
+	if m != nil {
+		return m.Ranges
+	}
+	return nil
+}
+
+type PrepareDirectoriesRequest struct {
+	DataDirectories      []string `protobuf:"bytes,1,rep,name=dataDirectories,proto3" json:"dataDirectories,omitempty"`
+	RefusedFilesystems   []string `protobuf:"bytes,2,rep,name=refusedFilesystems,proto3" json:"refusedFilesystems,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrepareDirectoriesRequest) Reset()         { *m = PrepareDirectoriesRequest{} }
+func (m *PrepareDirectoriesRequest) String() string { return proto.CompactTextString(m) }
+func (*PrepareDirectoriesRequest) ProtoMessage()    {}
+func (*PrepareDirectoriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{30}
+}
+
+func (m *PrepareDirectoriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareDirectoriesRequest.Unmarshal(m, b)
+}
+func (m *PrepareDirectoriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareDirectoriesRequest.Marshal(b, m, deterministic)
+}
+func (m *PrepareDirectoriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareDirectoriesRequest.Merge(m, src)
+}
+func (m *PrepareDirectoriesRequest) XXX_Size() int {
+	return xxx_messageInfo_PrepareDirectoriesRequest.Size(m)
+}
+func (m *PrepareDirectoriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareDirectoriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrepareDirectoriesRequest proto.InternalMessageInfo
+
+func (m *PrepareDirectoriesRequest) GetDataDirectories() []string {
+	if m != nil {
+		return m.DataDirectories
+	}
+	return nil
+}
+
+func (m *PrepareDirectoriesRequest) GetRefusedFilesystems() []string {
+	if m != nil {
+		return m.RefusedFilesystems
+	}
+	return nil
+}
+
+type DirectoryInfo struct {
+	DataDirectory        string   `protobuf:"bytes,1,opt,name=dataDirectory,proto3" json:"dataDirectory,omitempty"`
+	MountPoint           string   `protobuf:"bytes,2,opt,name=mountPoint,proto3" json:"mountPoint,omitempty"`
+	Filesystem           string   `protobuf:"bytes,3,opt,name=filesystem,proto3" json:"filesystem,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DirectoryInfo) Reset()         { *m = DirectoryInfo{} }
+func (m *DirectoryInfo) String() string { return proto.CompactTextString(m) }
+func (*DirectoryInfo) ProtoMessage()    {}
+func (*DirectoryInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{31}
+}
+
+func (m *DirectoryInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DirectoryInfo.Unmarshal(m, b)
+}
+func (m *DirectoryInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DirectoryInfo.Marshal(b, m, deterministic)
+}
+func (m *DirectoryInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DirectoryInfo.Merge(m, src)
+}
+func (m *DirectoryInfo) XXX_Size() int {
+	return xxx_messageInfo_DirectoryInfo.Size(m)
+}
+func (m *DirectoryInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_DirectoryInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DirectoryInfo proto.InternalMessageInfo
+
+func (m *DirectoryInfo) GetDataDirectory() string {
+	if m != nil {
+		return m.DataDirectory
+	}
+	return ""
+}
+
+func (m *DirectoryInfo) GetMountPoint() string {
+	if m != nil {
+		return m.MountPoint
+	}
+	return ""
+}
+
+func (m *DirectoryInfo) GetFilesystem() string {
+	if m != nil {
+		return m.Filesystem
+	}
+	return ""
+}
+
+type PrepareDirectoriesReply struct {
+	Directories          []*DirectoryInfo `protobuf:"bytes,1,rep,name=directories,proto3" json:"directories,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *PrepareDirectoriesReply) Reset()         { *m = PrepareDirectoriesReply{} }
+func (m *PrepareDirectoriesReply) String() string { return proto.CompactTextString(m) }
+func (*PrepareDirectoriesReply) ProtoMessage()    {}
+func (*PrepareDirectoriesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_56ede974c0020f77, []int{32}
+}
+
+func (m *PrepareDirectoriesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareDirectoriesReply.Unmarshal(m, b)
+}
+func (m *PrepareDirectoriesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareDirectoriesReply.Marshal(b, m, deterministic)
+}
+func (m *PrepareDirectoriesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareDirectoriesReply.Merge(m, src)
+}
+func (m *PrepareDirectoriesReply) XXX_Size() int {
+	return xxx_messageInfo_PrepareDirectoriesReply.Size(m)
+}
+func (m *PrepareDirectoriesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareDirectoriesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrepareDirectoriesReply proto.InternalMessageInfo
+
+func (m *PrepareDirectoriesReply) GetDirectories() []*DirectoryInfo {
+	if m != nil {
+		return m.Directories
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*GetHostNameReply)(nil), "idl.GetHostNameReply")
 	proto.RegisterType((*GetHostNameRequest)(nil), "idl.GetHostNameRequest")
@@ -1041,83 +1569,120 @@ func init() {
 	proto.RegisterType((*UpdatePgConfRespoonse)(nil), "idl.UpdatePgConfRespoonse")
 	proto.RegisterType((*PgBasebackupRequest)(nil), "idl.PgBasebackupRequest")
 	proto.RegisterType((*PgBasebackupResponse)(nil), "idl.PgBasebackupResponse")
+	proto.RegisterType((*SegmentCommandReply)(nil), "idl.SegmentCommandReply")
+	proto.RegisterType((*OutputLine)(nil), "idl.OutputLine")
+	proto.RegisterType((*TransferProgress)(nil), "idl.TransferProgress")
 	proto.RegisterType((*RemoveDirectoryRequest)(nil), "idl.RemoveDirectoryRequest")
 	proto.RegisterType((*RemoveDirectoryReply)(nil), "idl.RemoveDirectoryReply")
+	proto.RegisterType((*VerifyDataDirectoriesRequest)(nil), "idl.VerifyDataDirectoriesRequest")
+	proto.RegisterType((*VerifyDataDirectoriesReply)(nil), "idl.VerifyDataDirectoriesReply")
+	proto.RegisterType((*GetFreePortRangesRequest)(nil), "idl.GetFreePortRangesRequest")
+	proto.RegisterType((*PortRange)(nil), "idl.PortRange")
+	proto.RegisterType((*GetFreePortRangesReply)(nil), "idl.GetFreePortRangesReply")
+	proto.RegisterType((*PrepareDirectoriesRequest)(nil), "idl.PrepareDirectoriesRequest")
+	proto.RegisterType((*DirectoryInfo)(nil), "idl.DirectoryInfo")
+	proto.RegisterType((*PrepareDirectoriesReply)(nil), "idl.PrepareDirectoriesReply")
 }
 
 func init() { proto.RegisterFile("agent.proto", fileDescriptor_56ede974c0020f77) }
 
 var fileDescriptor_56ede974c0020f77 = []byte{
-	// 1090 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0x6d, 0x6f, 0xe3, 0x44,
-	0x10, 0x6e, 0xd2, 0x26, 0x4d, 0x26, 0xb9, 0x36, 0xdd, 0xa4, 0xa9, 0x6b, 0xca, 0x29, 0x32, 0xa7,
-	0x2a, 0x02, 0x14, 0x20, 0xf0, 0x01, 0x4e, 0x27, 0x50, 0xdf, 0xb8, 0x43, 0xdc, 0x41, 0xe4, 0x1c,
-	0x87, 0xc4, 0xb7, 0x8d, 0xbd, 0x75, 0xac, 0x3a, 0x5e, 0xb3, 0xbb, 0x6e, 0xc9, 0x2f, 0xe1, 0x0f,
-	0xf1, 0x3f, 0xee, 0x67, 0xf0, 0x15, 0xed, 0x7a, 0x9d, 0xf8, 0xad, 0x12, 0xf7, 0xcd, 0xf3, 0xcc,
-	0xec, 0xec, 0xbc, 0x3c, 0x33, 0x6b, 0xe8, 0x60, 0x8f, 0x84, 0x62, 0x12, 0x31, 0x2a, 0x28, 0xda,
-	0xf5, 0xdd, 0xc0, 0x6c, 0x2f, 0xe3, 0x45, 0x22, 0x5b, 0x13, 0xe8, 0xbd, 0x24, 0xe2, 0x15, 0xe5,
-	0xe2, 0x17, 0xbc, 0x22, 0x36, 0x89, 0x82, 0x35, 0x32, 0xa1, 0xb5, 0xa4, 0x5c, 0x84, 0x78, 0x45,
-	0x8c, 0xda, 0xa8, 0x36, 0x6e, 0xdb, 0x1b, 0xd9, 0x1a, 0x00, 0xca, 0xd9, 0xff, 0x19, 0x13, 0x2e,
-	0xac, 0x07, 0xe8, 0xcf, 0x05, 0x66, 0x62, 0x4e, 0xbc, 0x15, 0x09, 0x85, 0x86, 0x91, 0x01, 0xfb,
-	0x2e, 0x16, 0xf8, 0xda, 0x67, 0xda, 0x4f, 0x2a, 0x22, 0x04, 0x7b, 0x0f, 0xd8, 0x17, 0x46, 0x7d,
-	0x54, 0x1b, 0xb7, 0x6c, 0xf5, 0x2d, 0xad, 0x85, 0xbf, 0x22, 0x34, 0x16, 0xc6, 0xde, 0xa8, 0x36,
-	0x6e, 0xd8, 0xa9, 0x28, 0x35, 0x34, 0x12, 0x3e, 0x0d, 0xb9, 0xd1, 0x48, 0xfc, 0x68, 0xd1, 0xea,
-	0xc3, 0x51, 0xfe, 0xe2, 0x28, 0x58, 0x5b, 0x08, 0x7a, 0x73, 0x41, 0xa3, 0x0b, 0x6f, 0x1b, 0x8a,
-	0xd5, 0x83, 0x83, 0x0c, 0x26, 0xad, 0x06, 0x80, 0xe6, 0x02, 0x8b, 0x98, 0xe7, 0xec, 0xde, 0xca,
-	0xb3, 0x19, 0x54, 0xd6, 0x63, 0x08, 0x4d, 0xae, 0x30, 0x9d, 0x85, 0x96, 0x24, 0x1e, 0x47, 0x32,
-	0x46, 0x95, 0x46, 0xdb, 0xd6, 0x12, 0xea, 0xc1, 0x6e, 0xe4, 0xbb, 0xc6, 0xee, 0xa8, 0x36, 0x7e,
-	0x62, 0xcb, 0x4f, 0xeb, 0x7d, 0x0d, 0x86, 0xef, 0x70, 0xe0, 0xbb, 0x58, 0x10, 0x59, 0xbb, 0x9b,
-	0xf0, 0x3e, 0xad, 0xd1, 0x18, 0x0e, 0x65, 0x71, 0x2f, 0x5c, 0x97, 0x11, 0xce, 0x5f, 0xfb, 0x5c,
-	0x18, 0xb5, 0xd1, 0xee, 0xb8, 0x6d, 0x17, 0x61, 0xf4, 0x0c, 0x9e, 0x5c, 0xfb, 0x8c, 0x38, 0x82,
-	0xb2, 0xb5, 0xb2, 0xab, 0x2b, 0xbb, 0x3c, 0x28, 0x9b, 0x17, 0x51, 0x26, 0x94, 0xc1, 0xae, 0x32,
-	0xd8, 0xc8, 0xe8, 0x13, 0x68, 0x06, 0xd4, 0xc1, 0x01, 0x51, 0x05, 0xee, 0x4c, 0x3b, 0x13, 0xdf,
-	0x0d, 0x26, 0xaf, 0x15, 0x64, 0x6b, 0x15, 0x3a, 0x83, 0xb6, 0x17, 0xbd, 0x23, 0x8c, 0xfb, 0x34,
-	0xd4, 0xe5, 0xde, 0x02, 0x32, 0xe7, 0x5b, 0xca, 0x1c, 0xe2, 0x1a, 0x4d, 0xd5, 0x3a, 0x2d, 0x59,
-	0x57, 0x30, 0x28, 0x25, 0x28, 0x6b, 0xf7, 0x19, 0xb4, 0x56, 0x84, 0x73, 0xec, 0x11, 0xae, 0xf2,
-	0xea, 0x4c, 0x0f, 0xf5, 0xa5, 0xde, 0x9b, 0x04, 0xb7, 0x37, 0x06, 0xd6, 0xbf, 0x75, 0x40, 0x6f,
-	0xf0, 0x1d, 0x29, 0xd0, 0xe8, 0x1c, 0xf6, 0x79, 0x82, 0xa8, 0x06, 0x74, 0xa6, 0x5d, 0xe5, 0x22,
-	0xb5, 0x4a, 0x95, 0x99, 0xf4, 0xea, 0x8f, 0xa7, 0x67, 0x42, 0xeb, 0x26, 0x74, 0xa8, 0xeb, 0x87,
-	0x9e, 0xea, 0x50, 0xdb, 0xde, 0xc8, 0xe8, 0x1a, 0xda, 0x73, 0xe2, 0x5d, 0xd1, 0xf0, 0xd6, 0xf7,
-	0x8c, 0x3d, 0x15, 0xed, 0xb9, 0xf2, 0x51, 0x0e, 0x6a, 0xb2, 0x31, 0xbc, 0x09, 0x05, 0x5b, 0xdb,
-	0xdb, 0x83, 0xe8, 0x53, 0xe8, 0x39, 0x94, 0x32, 0xd7, 0x0f, 0xb1, 0xa0, 0x4c, 0x76, 0x50, 0xd2,
-	0x56, 0x76, 0xa2, 0x84, 0x23, 0x0b, 0xba, 0xcb, 0x05, 0x4e, 0xc7, 0x89, 0xeb, 0xa2, 0xe6, 0x30,
-	0xd9, 0x77, 0x39, 0x36, 0x57, 0x4b, 0xe2, 0xdc, 0xf1, 0x78, 0xc5, 0x8d, 0x7d, 0x65, 0x94, 0x07,
-	0xcd, 0x17, 0x70, 0x90, 0x0f, 0x49, 0xd2, 0xf0, 0x8e, 0xac, 0x35, 0x67, 0xe5, 0x27, 0x1a, 0x40,
-	0xe3, 0x1e, 0x07, 0x71, 0xca, 0xd7, 0x44, 0x78, 0x5e, 0xff, 0xb6, 0x26, 0x47, 0x26, 0x97, 0xa3,
-	0x1c, 0x10, 0x13, 0x8c, 0x97, 0x44, 0xfc, 0x14, 0x0a, 0xc2, 0x6e, 0xb1, 0x43, 0x54, 0xc0, 0xe9,
-	0x98, 0x7c, 0x05, 0xa7, 0x15, 0x3a, 0x1e, 0xd1, 0x90, 0x13, 0x79, 0x0d, 0x56, 0x59, 0x27, 0x44,
-	0x4e, 0x04, 0x6b, 0x09, 0xc3, 0xdf, 0x22, 0xc9, 0x8f, 0x99, 0xf7, 0x6a, 0x81, 0x65, 0xa0, 0x69,
-	0x7f, 0x87, 0xd0, 0x8c, 0x3c, 0x99, 0x4d, 0x3a, 0x5f, 0x89, 0xb4, 0xf5, 0x53, 0xcf, 0xf8, 0x41,
-	0x23, 0xe8, 0x30, 0x12, 0x05, 0xbe, 0x83, 0xe5, 0x0a, 0x50, 0x3d, 0x6c, 0xd9, 0x59, 0xc8, 0x3a,
-	0x85, 0x93, 0xd2, 0x4d, 0x49, 0x68, 0xd6, 0x3f, 0x35, 0xe8, 0xa7, 0xba, 0xff, 0x13, 0xc2, 0x0b,
-	0x68, 0x46, 0x98, 0xe1, 0x55, 0x12, 0x43, 0x67, 0xfa, 0x4c, 0xd1, 0xa1, 0xc2, 0xc3, 0x64, 0xa6,
-	0xcc, 0x12, 0x32, 0xe8, 0x33, 0x72, 0x94, 0xe8, 0x3d, 0x61, 0x0f, 0xcc, 0x17, 0x44, 0x07, 0xba,
-	0x05, 0xcc, 0xef, 0xa0, 0x93, 0x39, 0xf4, 0x41, 0xed, 0x3a, 0x81, 0xe3, 0x7c, 0x0c, 0x3c, 0xa2,
-	0x2a, 0xbf, 0xf7, 0x75, 0xe8, 0xcf, 0xbc, 0x4b, 0xcc, 0xc9, 0x02, 0x3b, 0x77, 0x71, 0x94, 0xe6,
-	0x77, 0x06, 0x6d, 0x81, 0x99, 0x47, 0xc4, 0x76, 0x17, 0x6f, 0x01, 0xf4, 0x14, 0x80, 0xd3, 0x98,
-	0x39, 0x6a, 0x74, 0xf5, 0x6d, 0x19, 0x64, 0xab, 0x9f, 0x51, 0x26, 0x54, 0x22, 0x0d, 0x3b, 0x83,
-	0x48, 0xbd, 0xc3, 0x08, 0x16, 0x64, 0x1e, 0xd0, 0x64, 0x79, 0xb7, 0xec, 0x0c, 0x82, 0xce, 0xe1,
-	0x40, 0xad, 0x89, 0x5f, 0x37, 0xc5, 0x68, 0x28, 0x9b, 0x02, 0x2a, 0xfd, 0xe8, 0xa0, 0x16, 0x7e,
-	0xb2, 0x60, 0x1a, 0x76, 0x06, 0x41, 0x9f, 0xc3, 0x91, 0x32, 0xb4, 0x89, 0x23, 0xcb, 0xb8, 0x96,
-	0xb9, 0xeb, 0x69, 0x28, 0x2b, 0xd0, 0x97, 0xd0, 0xcf, 0xb0, 0x42, 0x06, 0x22, 0xe7, 0xc9, 0x68,
-	0xa9, 0xf4, 0xaa, 0x54, 0x72, 0x1a, 0xc9, 0x5f, 0x4e, 0x10, 0xbb, 0x64, 0x86, 0xc5, 0x92, 0x1b,
-	0x6d, 0xc5, 0xbb, 0x1c, 0x66, 0x0d, 0x61, 0x90, 0x2f, 0xb0, 0x66, 0xd6, 0xf7, 0x30, 0xb4, 0xc9,
-	0x8a, 0xde, 0x93, 0xcd, 0x3a, 0x4e, 0x6b, 0xaf, 0xe7, 0x77, 0x83, 0xeb, 0xfa, 0xe7, 0x41, 0xe9,
-	0xb7, 0x74, 0x3e, 0x0a, 0xd6, 0xd3, 0xbf, 0x9b, 0xd0, 0x50, 0x6f, 0x11, 0xfa, 0x06, 0xf6, 0xe4,
-	0x13, 0x86, 0x8e, 0x93, 0xed, 0x57, 0x78, 0xe1, 0xcc, 0x7e, 0x11, 0x96, 0x33, 0xbc, 0x83, 0x9e,
-	0x43, 0x33, 0x79, 0xd0, 0xd0, 0x89, 0x36, 0x28, 0xbe, 0x79, 0xe6, 0x71, 0x59, 0x91, 0x9c, 0xfd,
-	0x01, 0x3a, 0x99, 0xad, 0xa0, 0x1d, 0x94, 0x77, 0xa1, 0x76, 0x50, 0x5a, 0x20, 0x3b, 0xe8, 0x12,
-	0xba, 0xd9, 0xe7, 0x19, 0x19, 0xe9, 0x4d, 0xc5, 0x5f, 0x05, 0x73, 0x58, 0xa1, 0x49, 0x7c, 0xfc,
-	0x0c, 0x87, 0x85, 0x97, 0x05, 0x7d, 0xa4, 0x8c, 0xab, 0x1f, 0x54, 0xf3, 0xb4, 0x5a, 0x99, 0x38,
-	0x7b, 0x0b, 0x47, 0xa5, 0xbd, 0x85, 0x3e, 0x56, 0x27, 0x1e, 0xdb, 0x75, 0xe6, 0xd3, 0xc7, 0xd4,
-	0xba, 0xf3, 0x3b, 0xe8, 0x77, 0x30, 0x0a, 0x0b, 0xe7, 0x22, 0x74, 0x6d, 0x12, 0x50, 0xec, 0xea,
-	0x58, 0xab, 0x37, 0x9f, 0x79, 0x56, 0xad, 0xdc, 0x38, 0xfe, 0x11, 0xba, 0xd9, 0x39, 0xd7, 0xf5,
-	0xab, 0x58, 0x3f, 0xa6, 0x59, 0xa1, 0x49, 0x97, 0xc2, 0x0e, 0xba, 0x81, 0x6e, 0x96, 0xb4, 0xda,
-	0x4f, 0xc5, 0xa2, 0xd0, 0xd5, 0xab, 0x64, 0xb8, 0xe2, 0x43, 0xe6, 0xe7, 0x4f, 0xf3, 0xa1, 0xfc,
-	0x3b, 0xa8, 0xf9, 0x50, 0xfc, 0xaf, 0x4c, 0x7a, 0x59, 0x20, 0xb9, 0xae, 0x4f, 0xf5, 0xe8, 0xe8,
-	0x68, 0xaa, 0xe6, 0xc2, 0xda, 0xb9, 0x6c, 0xfd, 0xd1, 0x9c, 0x4c, 0xbe, 0xf0, 0xdd, 0x60, 0xd1,
-	0x54, 0xff, 0xb2, 0x5f, 0xff, 0x17, 0x00, 0x00, 0xff, 0xff, 0x5b, 0x01, 0xff, 0x55, 0xea, 0x0a,
-	0x00, 0x00,
+	// 1510 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0x4d, 0x6f, 0xdb, 0x46,
+	0x13, 0xb6, 0x24, 0x5b, 0x96, 0x46, 0x8e, 0x63, 0xaf, 0x6d, 0x99, 0x66, 0xfc, 0xe6, 0x75, 0xd9,
+	0xc0, 0x70, 0x3f, 0xa0, 0xa6, 0x4e, 0x0e, 0x6d, 0x10, 0x34, 0x8d, 0x3f, 0x12, 0xa7, 0x71, 0x12,
+	0x95, 0x4a, 0x53, 0xa0, 0x40, 0x0f, 0x6b, 0x71, 0x45, 0x13, 0xa6, 0xb8, 0xec, 0xee, 0xd2, 0x89,
+	0x6e, 0x45, 0xff, 0x55, 0xef, 0xfd, 0x1f, 0xf9, 0x19, 0xbd, 0x16, 0xfb, 0x41, 0x8a, 0x14, 0xa9,
+	0x20, 0x45, 0x6f, 0x9c, 0xe7, 0x99, 0x9d, 0x9d, 0x9d, 0x9d, 0x99, 0x1d, 0x09, 0x3a, 0xd8, 0x27,
+	0x91, 0xe8, 0xc5, 0x8c, 0x0a, 0x8a, 0x1a, 0x81, 0x17, 0xda, 0xed, 0xcb, 0xe4, 0x42, 0xcb, 0x4e,
+	0x0f, 0xd6, 0x9e, 0x12, 0x71, 0x46, 0xb9, 0x78, 0x89, 0xc7, 0xc4, 0x25, 0x71, 0x38, 0x41, 0x36,
+	0xb4, 0x2e, 0x29, 0x17, 0x11, 0x1e, 0x13, 0xab, 0xb6, 0x57, 0x3b, 0x68, 0xbb, 0x99, 0xec, 0x6c,
+	0x02, 0x2a, 0xe8, 0xff, 0x96, 0x10, 0x2e, 0x9c, 0xb7, 0xb0, 0x31, 0x10, 0x98, 0x89, 0x01, 0xf1,
+	0xc7, 0x24, 0x12, 0x06, 0x46, 0x16, 0x2c, 0x7b, 0x58, 0xe0, 0x93, 0x80, 0x19, 0x3b, 0xa9, 0x88,
+	0x10, 0x2c, 0xbe, 0xc5, 0x81, 0xb0, 0xea, 0x7b, 0xb5, 0x83, 0x96, 0xab, 0xbe, 0xa5, 0xb6, 0x08,
+	0xc6, 0x84, 0x26, 0xc2, 0x5a, 0xdc, 0xab, 0x1d, 0x2c, 0xb9, 0xa9, 0x28, 0x19, 0x1a, 0x8b, 0x80,
+	0x46, 0xdc, 0x5a, 0xd2, 0x76, 0x8c, 0xe8, 0x6c, 0xc0, 0x7a, 0x71, 0xe3, 0x38, 0x9c, 0x38, 0x08,
+	0xd6, 0x06, 0x82, 0xc6, 0x8f, 0xfd, 0xa9, 0x2b, 0xce, 0x1a, 0xac, 0xe6, 0x30, 0xa9, 0xb5, 0x09,
+	0x68, 0x20, 0xb0, 0x48, 0x78, 0x41, 0xef, 0x8f, 0x1a, 0xac, 0x15, 0x60, 0x19, 0x90, 0x2e, 0x34,
+	0xb9, 0xc2, 0xcc, 0x31, 0x8c, 0x24, 0xf1, 0x24, 0x96, 0x4e, 0xaa, 0x73, 0xb4, 0x5d, 0x23, 0xa1,
+	0x35, 0x68, 0xc4, 0x81, 0x67, 0x35, 0xf6, 0x6a, 0x07, 0x37, 0x5c, 0xf9, 0x89, 0xbe, 0x84, 0xf5,
+	0x21, 0x61, 0x22, 0x18, 0x05, 0x43, 0x2c, 0xc8, 0xe9, 0xbb, 0x38, 0x60, 0x13, 0x75, 0xca, 0x86,
+	0x5b, 0x26, 0x9c, 0xf7, 0x35, 0xe8, 0xbe, 0xc1, 0x61, 0xe0, 0x61, 0x41, 0x64, 0xa8, 0x4f, 0xa3,
+	0xeb, 0x34, 0xa4, 0x07, 0x70, 0x53, 0xde, 0xc5, 0x63, 0xcf, 0x63, 0x84, 0xf3, 0xf3, 0x80, 0x0b,
+	0xab, 0xb6, 0xd7, 0x38, 0x68, 0xbb, 0xb3, 0x30, 0xba, 0x03, 0x37, 0x4e, 0x02, 0x46, 0x86, 0x82,
+	0xb2, 0x89, 0xd2, 0xab, 0x2b, 0xbd, 0x22, 0x28, 0xef, 0x3a, 0xa6, 0x4c, 0x28, 0x85, 0x86, 0x52,
+	0xc8, 0x64, 0xf4, 0x29, 0x34, 0x43, 0x3a, 0xc4, 0x21, 0x51, 0x9e, 0x76, 0x0e, 0x3b, 0xbd, 0xc0,
+	0x0b, 0x7b, 0xe7, 0x0a, 0x72, 0x0d, 0x85, 0x76, 0xa1, 0xed, 0xc7, 0x6f, 0x08, 0xe3, 0x01, 0x8d,
+	0xcc, 0xed, 0x4c, 0x01, 0x19, 0xa1, 0x11, 0x65, 0x43, 0xe2, 0x59, 0x4d, 0x75, 0xd3, 0x46, 0x72,
+	0x8e, 0x61, 0xb3, 0x74, 0x40, 0x19, 0xe9, 0x2f, 0xa0, 0x35, 0x26, 0x9c, 0x63, 0x9f, 0x70, 0x75,
+	0xae, 0xce, 0xe1, 0x4d, 0xb3, 0xa9, 0xff, 0x42, 0xe3, 0x6e, 0xa6, 0xe0, 0xfc, 0x5d, 0x07, 0xf4,
+	0x02, 0x5f, 0x91, 0x99, 0xac, 0xdb, 0x87, 0x65, 0xae, 0x11, 0x75, 0x5d, 0x9d, 0xc3, 0x15, 0x65,
+	0x22, 0xd5, 0x4a, 0xc9, 0xdc, 0xf1, 0xea, 0xf3, 0x8f, 0x67, 0x43, 0xeb, 0x34, 0x1a, 0x52, 0x2f,
+	0x88, 0x7c, 0x75, 0x9f, 0x6d, 0x37, 0x93, 0xd1, 0x09, 0xb4, 0x07, 0xc4, 0x3f, 0xa6, 0xd1, 0x28,
+	0xf0, 0xad, 0x45, 0xe5, 0xed, 0xbe, 0xb2, 0x51, 0x76, 0xaa, 0x97, 0x29, 0x9e, 0x46, 0x82, 0x4d,
+	0xdc, 0xe9, 0x42, 0xf4, 0x39, 0xac, 0x0d, 0x29, 0x65, 0x5e, 0x10, 0x61, 0x41, 0x99, 0xbc, 0x41,
+	0x99, 0xe5, 0xf2, 0x26, 0x4a, 0x38, 0x72, 0x60, 0xe5, 0xf2, 0x02, 0xa7, 0xd5, 0xc7, 0x4d, 0x50,
+	0x0b, 0x98, 0xbc, 0x77, 0x59, 0x65, 0xc7, 0x97, 0x64, 0x78, 0xc5, 0x93, 0x31, 0xb7, 0x96, 0x95,
+	0x52, 0x11, 0xb4, 0x1f, 0xc2, 0x6a, 0xd1, 0x25, 0x99, 0xb4, 0x57, 0x64, 0x62, 0x32, 0x5c, 0x7e,
+	0xa2, 0x4d, 0x58, 0xba, 0xc6, 0x61, 0x92, 0x66, 0xb7, 0x16, 0x1e, 0xd4, 0xbf, 0xa9, 0xc9, 0x0a,
+	0x2b, 0x9c, 0x51, 0xd6, 0x93, 0x0d, 0xd6, 0x53, 0x22, 0x9e, 0x45, 0x82, 0xb0, 0x11, 0x1e, 0x12,
+	0xe5, 0x70, 0x5a, 0x55, 0x5f, 0xc3, 0x4e, 0x05, 0xc7, 0x63, 0x1a, 0x71, 0x22, 0xb7, 0xc1, 0xea,
+	0xd4, 0x3a, 0x91, 0xb5, 0xe0, 0x5c, 0x42, 0xf7, 0xa7, 0x58, 0xe6, 0x47, 0xdf, 0x3f, 0xbb, 0xc0,
+	0xd2, 0xd1, 0xf4, 0x7e, 0xbb, 0xd0, 0x8c, 0x7d, 0x79, 0x9a, 0xb4, 0x1a, 0xb5, 0x34, 0xb5, 0x53,
+	0xcf, 0xd9, 0x41, 0x7b, 0xd0, 0x61, 0x24, 0x0e, 0x65, 0x79, 0xc9, 0x0c, 0x6d, 0xa8, 0x60, 0xe4,
+	0x21, 0x67, 0x07, 0xb6, 0x4b, 0x3b, 0x69, 0xd7, 0x9c, 0xbf, 0x6a, 0xb0, 0x91, 0x72, 0x1f, 0xe3,
+	0xc2, 0x43, 0x68, 0xc6, 0x98, 0xe1, 0xb1, 0xf6, 0xa1, 0x73, 0x78, 0x47, 0xa5, 0x43, 0x85, 0x85,
+	0x5e, 0x5f, 0xa9, 0xe9, 0x64, 0x30, 0x6b, 0x64, 0x29, 0xd1, 0x6b, 0xc2, 0xde, 0xb2, 0x40, 0x10,
+	0xe3, 0xe8, 0x14, 0xb0, 0xbf, 0x85, 0x4e, 0x6e, 0xd1, 0xbf, 0xba, 0xae, 0x6d, 0xd8, 0x2a, 0xfa,
+	0xc0, 0x63, 0xaa, 0xce, 0xf7, 0xbe, 0x0e, 0x1b, 0x7d, 0xff, 0x08, 0x73, 0x72, 0x81, 0x87, 0x57,
+	0x49, 0x9c, 0x9e, 0x6f, 0x17, 0xda, 0x02, 0x33, 0x9f, 0x88, 0x69, 0xeb, 0x9e, 0x02, 0xe8, 0x36,
+	0x00, 0xa7, 0x09, 0x1b, 0xaa, 0xd2, 0x35, 0xbb, 0xe5, 0x90, 0x29, 0xdf, 0xa7, 0x4c, 0xa8, 0x83,
+	0x2c, 0xb9, 0x39, 0x44, 0xf2, 0x43, 0x46, 0xb0, 0x20, 0x83, 0x90, 0xea, 0x5e, 0xdf, 0x72, 0x73,
+	0x08, 0xda, 0x87, 0x55, 0xd5, 0x26, 0x5e, 0x65, 0xc1, 0x58, 0x52, 0x3a, 0x33, 0xa8, 0xb4, 0x63,
+	0x9c, 0xba, 0x08, 0x74, 0x83, 0x59, 0x72, 0x73, 0x88, 0x6c, 0xba, 0x4a, 0xd1, 0x25, 0x43, 0x19,
+	0xc6, 0x89, 0x3c, 0xbb, 0xa9, 0x86, 0x32, 0x81, 0xee, 0xc2, 0x46, 0x2e, 0x2b, 0xa4, 0x23, 0xb2,
+	0x9e, 0xac, 0x96, 0x3a, 0x5e, 0x15, 0x25, 0xab, 0x91, 0xbc, 0x1b, 0x86, 0x89, 0x47, 0xfa, 0x58,
+	0x5c, 0x72, 0xab, 0xad, 0xf2, 0xae, 0x80, 0x39, 0x5d, 0xd8, 0x2c, 0x06, 0xd8, 0x64, 0xd6, 0xef,
+	0x35, 0xd8, 0x30, 0xe5, 0x73, 0x4c, 0xc7, 0x63, 0x1c, 0x79, 0xba, 0x01, 0x7e, 0x06, 0x4d, 0x9a,
+	0x88, 0x38, 0x49, 0x7b, 0x97, 0x6e, 0x7f, 0xaf, 0x14, 0x74, 0x1e, 0x44, 0xe4, 0x6c, 0xc1, 0x35,
+	0x0a, 0xe8, 0x1e, 0xb4, 0x62, 0x46, 0x7d, 0xd9, 0xf0, 0x4d, 0x07, 0xdb, 0x52, 0xca, 0xaf, 0x19,
+	0x8e, 0xf8, 0x88, 0xb0, 0xbe, 0x21, 0xcf, 0x16, 0xdc, 0x4c, 0xf1, 0xa8, 0x0d, 0xcb, 0xa6, 0x7f,
+	0x3a, 0xcf, 0x01, 0xa6, 0x76, 0xe5, 0xc6, 0x5c, 0x30, 0x82, 0xc7, 0x6a, 0xe3, 0xd5, 0xc3, 0xf5,
+	0xdc, 0xc6, 0x03, 0x45, 0xb8, 0x46, 0x41, 0x3e, 0xde, 0x61, 0x10, 0xa5, 0x79, 0xa6, 0xbe, 0x9d,
+	0x87, 0xb0, 0x36, 0xbb, 0xaf, 0xd4, 0xf3, 0x68, 0xa4, 0x67, 0x88, 0x86, 0xab, 0xbe, 0x65, 0x92,
+	0x0a, 0x2a, 0x70, 0xa8, 0x16, 0x37, 0x5c, 0x2d, 0x38, 0xdf, 0x41, 0xd7, 0x25, 0x63, 0x7a, 0x4d,
+	0xb2, 0xc7, 0x29, 0xcd, 0x44, 0xd3, 0xcd, 0x32, 0xdc, 0x64, 0x63, 0x11, 0x94, 0x51, 0x2e, 0xad,
+	0x97, 0x3d, 0xe9, 0x0c, 0x76, 0xdf, 0x10, 0x16, 0x8c, 0x26, 0x27, 0x39, 0xf5, 0x80, 0xf0, 0xdc,
+	0x6b, 0xea, 0x15, 0x99, 0xf4, 0x35, 0x9d, 0x81, 0x9d, 0x5d, 0xb0, 0xe7, 0x58, 0x92, 0xfb, 0xbc,
+	0x54, 0xbd, 0xef, 0x09, 0x23, 0x2a, 0xc1, 0x5d, 0x1c, 0xf9, 0xd3, 0x3d, 0x2c, 0x58, 0x1e, 0x07,
+	0x91, 0x2a, 0x85, 0x9a, 0x1e, 0x6b, 0x8c, 0xa8, 0x18, 0xfc, 0x4e, 0x31, 0x75, 0xc3, 0x68, 0xd1,
+	0xb9, 0x07, 0xed, 0xcc, 0x90, 0x0c, 0x19, 0x17, 0x38, 0x5b, 0xae, 0x05, 0x59, 0xff, 0x24, 0xf2,
+	0xcc, 0x42, 0xf9, 0xe9, 0x7c, 0x0f, 0xdd, 0x0a, 0x27, 0x64, 0x52, 0xed, 0x43, 0x93, 0x29, 0xd1,
+	0xbc, 0xa9, 0xab, 0xea, 0x6e, 0x33, 0x2d, 0xd7, 0xb0, 0x4e, 0x02, 0x3b, 0x7d, 0x46, 0x62, 0xcc,
+	0xc8, 0x7f, 0x89, 0x15, 0xea, 0x01, 0x62, 0x64, 0x94, 0x70, 0xe2, 0x3d, 0x09, 0x42, 0xc2, 0x27,
+	0x5c, 0x90, 0x71, 0xda, 0x95, 0x2b, 0x18, 0x27, 0xc9, 0x4d, 0x2a, 0xcf, 0xa2, 0x11, 0xfd, 0xb8,
+	0x4b, 0x97, 0xe5, 0x3f, 0xa6, 0x49, 0x24, 0xfa, 0x34, 0x88, 0xb2, 0x36, 0x34, 0x45, 0x24, 0x3f,
+	0xca, 0x76, 0x31, 0x8f, 0x77, 0x0e, 0x71, 0x5e, 0xc1, 0x76, 0xd5, 0x69, 0x65, 0xc0, 0xee, 0x43,
+	0xc7, 0x9b, 0x39, 0x67, 0xe7, 0x10, 0xa9, 0xa8, 0x15, 0x3c, 0x75, 0xf3, 0x6a, 0x87, 0x7f, 0xb6,
+	0x61, 0x49, 0x4d, 0x8d, 0xe8, 0x3e, 0x2c, 0xca, 0x69, 0x13, 0xe9, 0x82, 0x9c, 0x1d, 0x46, 0xed,
+	0x8d, 0x59, 0x58, 0xe6, 0xd0, 0x02, 0x7a, 0x00, 0x4d, 0x3d, 0x7a, 0xa2, 0x6d, 0xa3, 0x30, 0x3b,
+	0x9e, 0xda, 0x5b, 0x65, 0x42, 0xaf, 0x7d, 0x04, 0x9d, 0xdc, 0x8b, 0x6c, 0x0c, 0x94, 0xe7, 0x10,
+	0x7b, 0xab, 0x4c, 0x68, 0x03, 0x3f, 0xc0, 0x7a, 0x0e, 0xd5, 0x15, 0x3f, 0xdf, 0x8c, 0x95, 0x1f,
+	0xa9, 0xf2, 0x0d, 0xcc, 0x59, 0xb8, 0x5b, 0x43, 0x47, 0xb0, 0x92, 0x9f, 0xca, 0x91, 0x95, 0x7a,
+	0x3d, 0xfb, 0x0b, 0xc1, 0xee, 0x56, 0x30, 0xda, 0x9f, 0xe7, 0x70, 0x73, 0x66, 0x42, 0x44, 0xb7,
+	0x94, 0x72, 0xf5, 0x60, 0x6c, 0xef, 0x54, 0x93, 0xda, 0xd8, 0x6b, 0x58, 0x2f, 0xcd, 0x1f, 0xe8,
+	0x7f, 0x6a, 0xc5, 0xbc, 0x99, 0xc5, 0xbe, 0x3d, 0x8f, 0x36, 0x1d, 0x7c, 0x01, 0xfd, 0x0c, 0xd6,
+	0xcc, 0xe0, 0xf0, 0x58, 0x46, 0x21, 0xa4, 0xd8, 0x33, 0xbe, 0x56, 0x4f, 0x30, 0xf6, 0x6e, 0x35,
+	0x99, 0x19, 0x7e, 0x02, 0x2b, 0xf9, 0xf7, 0xda, 0xc4, 0xaf, 0x62, 0x8c, 0xb0, 0xed, 0x0a, 0x26,
+	0x7d, 0xdc, 0x17, 0xd0, 0x29, 0xac, 0xe4, 0x1f, 0x1f, 0x63, 0xa7, 0xe2, 0xc1, 0xb7, 0x77, 0x2a,
+	0x98, 0xcc, 0x9d, 0x73, 0x40, 0x79, 0xc6, 0xe4, 0xc6, 0x7c, 0x63, 0x1f, 0x4e, 0x8e, 0x47, 0xd0,
+	0xc9, 0xfd, 0x82, 0x34, 0x29, 0x56, 0xfe, 0x4d, 0x69, 0x6f, 0x95, 0x89, 0x2c, 0x33, 0x66, 0x9a,
+	0xbd, 0x89, 0x76, 0xf5, 0x13, 0x62, 0xef, 0x54, 0x93, 0xda, 0xd8, 0xaf, 0xb0, 0x55, 0xd9, 0xd7,
+	0xd1, 0x27, 0x3a, 0x9f, 0x3e, 0xf0, 0x7a, 0xd8, 0xff, 0xff, 0x90, 0x8a, 0x36, 0xff, 0x23, 0xac,
+	0x97, 0x7a, 0xf2, 0x34, 0xf1, 0x2a, 0x1f, 0x0c, 0xfb, 0xd6, 0x3c, 0x3a, 0xcd, 0x65, 0x54, 0x6e,
+	0x5b, 0x48, 0x67, 0xeb, 0xdc, 0xee, 0x6d, 0xef, 0xce, 0xe5, 0x95, 0xd5, 0xa3, 0xd6, 0x2f, 0xcd,
+	0x5e, 0xef, 0xab, 0xc0, 0x0b, 0x2f, 0x9a, 0xea, 0x8f, 0x81, 0x7b, 0xff, 0x0c, 0x00, 0x59, 0xbf,
+	0xdb, 0x70, 0x37, 0x10, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1135,14 +1700,19 @@ type AgentClient interface {
 	Stop(ctx context.Context, in *StopAgentRequest, opts ...grpc.CallOption) (*StopAgentReply, error)
 	Status(ctx context.Context, in *StatusAgentRequest, opts ...grpc.CallOption) (*StatusAgentReply, error)
 	MakeSegment(ctx context.Context, in *MakeSegmentRequest, opts ...grpc.CallOption) (*MakeSegmentReply, error)
+	MakeSegmentStream(ctx context.Context, in *MakeSegmentRequest, opts ...grpc.CallOption) (Agent_MakeSegmentStreamClient, error)
 	StartSegment(ctx context.Context, in *StartSegmentRequest, opts ...grpc.CallOption) (*StartSegmentReply, error)
 	ValidateHostEnv(ctx context.Context, in *ValidateHostEnvRequest, opts ...grpc.CallOption) (*ValidateHostEnvReply, error)
 	GetInterfaceAddrs(ctx context.Context, in *GetInterfaceAddrsRequest, opts ...grpc.CallOption) (*GetInterfaceAddrsResponse, error)
 	UpdatePgHbaConfAndReload(ctx context.Context, in *UpdatePgHbaConfRequest, opts ...grpc.CallOption) (*UpdatePgHbaConfResponse, error)
 	UpdatePgConf(ctx context.Context, in *UpdatePgConfRequest, opts ...grpc.CallOption) (*UpdatePgConfRespoonse, error)
 	PgBasebackup(ctx context.Context, in *PgBasebackupRequest, opts ...grpc.CallOption) (*PgBasebackupResponse, error)
+	PgBasebackupStream(ctx context.Context, in *PgBasebackupRequest, opts ...grpc.CallOption) (Agent_PgBasebackupStreamClient, error)
 	GetHostName(ctx context.Context, in *GetHostNameRequest, opts ...grpc.CallOption) (*GetHostNameReply, error)
 	RemoveDirectory(ctx context.Context, in *RemoveDirectoryRequest, opts ...grpc.CallOption) (*RemoveDirectoryReply, error)
+	VerifyDataDirectories(ctx context.Context, in *VerifyDataDirectoriesRequest, opts ...grpc.CallOption) (*VerifyDataDirectoriesReply, error)
+	GetFreePortRanges(ctx context.Context, in *GetFreePortRangesRequest, opts ...grpc.CallOption) (*GetFreePortRangesReply, error)
+	PrepareDirectories(ctx context.Context, in *PrepareDirectoriesRequest, opts ...grpc.CallOption) (*PrepareDirectoriesReply, error)
 }
 
 type agentClient struct {
@@ -1180,6 +1750,38 @@ func (c *agentClient) MakeSegment(ctx context.Context, in *MakeSegmentRequest, o
 	return out, nil
 }
 
+func (c *agentClient) MakeSegmentStream(ctx context.Context, in *MakeSegmentRequest, opts ...grpc.CallOption) (Agent_MakeSegmentStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[0], "/idl.Agent/MakeSegmentStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentMakeSegmentStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_MakeSegmentStreamClient interface {
+	Recv() (*SegmentCommandReply, error)
+	grpc.ClientStream
+}
+
+type agentMakeSegmentStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentMakeSegmentStreamClient) Recv() (*SegmentCommandReply, error) {
+	m := new(SegmentCommandReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *agentClient) StartSegment(ctx context.Context, in *StartSegmentRequest, opts ...grpc.CallOption) (*StartSegmentReply, error) {
 	out := new(StartSegmentReply)
 	err := c.cc.Invoke(ctx, "/idl.Agent/StartSegment", in, out, opts...)
@@ -1234,6 +1836,38 @@ func (c *agentClient) PgBasebackup(ctx context.Context, in *PgBasebackupRequest,
 	return out, nil
 }
 
+func (c *agentClient) PgBasebackupStream(ctx context.Context, in *PgBasebackupRequest, opts ...grpc.CallOption) (Agent_PgBasebackupStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Agent_serviceDesc.Streams[1], "/idl.Agent/PgBasebackupStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentPgBasebackupStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Agent_PgBasebackupStreamClient interface {
+	Recv() (*SegmentCommandReply, error)
+	grpc.ClientStream
+}
+
+type agentPgBasebackupStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentPgBasebackupStreamClient) Recv() (*SegmentCommandReply, error) {
+	m := new(SegmentCommandReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *agentClient) GetHostName(ctx context.Context, in *GetHostNameRequest, opts ...grpc.CallOption) (*GetHostNameReply, error) {
 	out := new(GetHostNameReply)
 	err := c.cc.Invoke(ctx, "/idl.Agent/GetHostName", in, out, opts...)
@@ -1252,19 +1886,51 @@ func (c *agentClient) RemoveDirectory(ctx context.Context, in *RemoveDirectoryRe
 	return out, nil
 }
 
+func (c *agentClient) VerifyDataDirectories(ctx context.Context, in *VerifyDataDirectoriesRequest, opts ...grpc.CallOption) (*VerifyDataDirectoriesReply, error) {
+	out := new(VerifyDataDirectoriesReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/VerifyDataDirectories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) GetFreePortRanges(ctx context.Context, in *GetFreePortRangesRequest, opts ...grpc.CallOption) (*GetFreePortRangesReply, error) {
+	out := new(GetFreePortRangesReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/GetFreePortRanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) PrepareDirectories(ctx context.Context, in *PrepareDirectoriesRequest, opts ...grpc.CallOption) (*PrepareDirectoriesReply, error) {
+	out := new(PrepareDirectoriesReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/PrepareDirectories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 type AgentServer interface {
 	Stop(context.Context, *StopAgentRequest) (*StopAgentReply, error)
 	Status(context.Context, *StatusAgentRequest) (*StatusAgentReply, error)
 	MakeSegment(context.Context, *MakeSegmentRequest) (*MakeSegmentReply, error)
+	MakeSegmentStream(*MakeSegmentRequest, Agent_MakeSegmentStreamServer) error
 	StartSegment(context.Context, *StartSegmentRequest) (*StartSegmentReply, error)
 	ValidateHostEnv(context.Context, *ValidateHostEnvRequest) (*ValidateHostEnvReply, error)
 	GetInterfaceAddrs(context.Context, *GetInterfaceAddrsRequest) (*GetInterfaceAddrsResponse, error)
 	UpdatePgHbaConfAndReload(context.Context, *UpdatePgHbaConfRequest) (*UpdatePgHbaConfResponse, error)
 	UpdatePgConf(context.Context, *UpdatePgConfRequest) (*UpdatePgConfRespoonse, error)
 	PgBasebackup(context.Context, *PgBasebackupRequest) (*PgBasebackupResponse, error)
+	PgBasebackupStream(*PgBasebackupRequest, Agent_PgBasebackupStreamServer) error
 	GetHostName(context.Context, *GetHostNameRequest) (*GetHostNameReply, error)
 	RemoveDirectory(context.Context, *RemoveDirectoryRequest) (*RemoveDirectoryReply, error)
+	VerifyDataDirectories(context.Context, *VerifyDataDirectoriesRequest) (*VerifyDataDirectoriesReply, error)
+	GetFreePortRanges(context.Context, *GetFreePortRangesRequest) (*GetFreePortRangesReply, error)
+	PrepareDirectories(context.Context, *PrepareDirectoriesRequest) (*PrepareDirectoriesReply, error)
 }
 
 // UnimplementedAgentServer can be embedded to have forward compatible implementations.
@@ -1280,6 +1946,9 @@ func (*UnimplementedAgentServer) Status(ctx context.Context, req *StatusAgentReq
 func (*UnimplementedAgentServer) MakeSegment(ctx context.Context, req *MakeSegmentRequest) (*MakeSegmentReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MakeSegment not implemented")
 }
+func (*UnimplementedAgentServer) MakeSegmentStream(req *MakeSegmentRequest, srv Agent_MakeSegmentStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method MakeSegmentStream not implemented")
+}
 func (*UnimplementedAgentServer) StartSegment(ctx context.Context, req *StartSegmentRequest) (*StartSegmentReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartSegment not implemented")
 }
@@ -1298,12 +1967,24 @@ func (*UnimplementedAgentServer) UpdatePgConf(ctx context.Context, req *UpdatePg
 func (*UnimplementedAgentServer) PgBasebackup(ctx context.Context, req *PgBasebackupRequest) (*PgBasebackupResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PgBasebackup not implemented")
 }
+func (*UnimplementedAgentServer) PgBasebackupStream(req *PgBasebackupRequest, srv Agent_PgBasebackupStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method PgBasebackupStream not implemented")
+}
 func (*UnimplementedAgentServer) GetHostName(ctx context.Context, req *GetHostNameRequest) (*GetHostNameReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetHostName not implemented")
 }
 func (*UnimplementedAgentServer) RemoveDirectory(ctx context.Context, req *RemoveDirectoryRequest) (*RemoveDirectoryReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveDirectory not implemented")
 }
+func (*UnimplementedAgentServer) VerifyDataDirectories(ctx context.Context, req *VerifyDataDirectoriesRequest) (*VerifyDataDirectoriesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyDataDirectories not implemented")
+}
+func (*UnimplementedAgentServer) GetFreePortRanges(ctx context.Context, req *GetFreePortRangesRequest) (*GetFreePortRangesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFreePortRanges not implemented")
+}
+func (*UnimplementedAgentServer) PrepareDirectories(ctx context.Context, req *PrepareDirectoriesRequest) (*PrepareDirectoriesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareDirectories not implemented")
+}
 
 func RegisterAgentServer(s *grpc.Server, srv AgentServer) {
 	s.RegisterService(&_Agent_serviceDesc, srv)
@@ -1363,6 +2044,27 @@ func _Agent_MakeSegment_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_MakeSegmentStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MakeSegmentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).MakeSegmentStream(m, &agentMakeSegmentStreamServer{stream})
+}
+
+type Agent_MakeSegmentStreamServer interface {
+	Send(*SegmentCommandReply) error
+	grpc.ServerStream
+}
+
+type agentMakeSegmentStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentMakeSegmentStreamServer) Send(m *SegmentCommandReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Agent_StartSegment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StartSegmentRequest)
 	if err := dec(in); err != nil {
@@ -1471,6 +2173,27 @@ func _Agent_PgBasebackup_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_PgBasebackupStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PgBasebackupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).PgBasebackupStream(m, &agentPgBasebackupStreamServer{stream})
+}
+
+type Agent_PgBasebackupStreamServer interface {
+	Send(*SegmentCommandReply) error
+	grpc.ServerStream
+}
+
+type agentPgBasebackupStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentPgBasebackupStreamServer) Send(m *SegmentCommandReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Agent_GetHostName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetHostNameRequest)
 	if err := dec(in); err != nil {
@@ -1507,6 +2230,60 @@ func _Agent_RemoveDirectory_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_VerifyDataDirectories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyDataDirectoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).VerifyDataDirectories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/VerifyDataDirectories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).VerifyDataDirectories(ctx, req.(*VerifyDataDirectoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_GetFreePortRanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFreePortRangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetFreePortRanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/GetFreePortRanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetFreePortRanges(ctx, req.(*GetFreePortRangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_PrepareDirectories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareDirectoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).PrepareDirectories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/PrepareDirectories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).PrepareDirectories(ctx, req.(*PrepareDirectoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Agent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.Agent",
 	HandlerType: (*AgentServer)(nil),
@@ -1555,7 +2332,30 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveDirectory",
 			Handler:    _Agent_RemoveDirectory_Handler,
 		},
+		{
+			MethodName: "VerifyDataDirectories",
+			Handler:    _Agent_VerifyDataDirectories_Handler,
+		},
+		{
+			MethodName: "GetFreePortRanges",
+			Handler:    _Agent_GetFreePortRanges_Handler,
+		},
+		{
+			MethodName: "PrepareDirectories",
+			Handler:    _Agent_PrepareDirectories_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "MakeSegmentStream",
+			Handler:       _Agent_MakeSegmentStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PgBasebackupStream",
+			Handler:       _Agent_PgBasebackupStream_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "agent.proto",
 }
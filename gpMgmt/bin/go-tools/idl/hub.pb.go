@@ -24,6 +24,31 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type OutputStream int32
+
+const (
+	OutputStream_STDOUT OutputStream = 0
+	OutputStream_STDERR OutputStream = 1
+)
+
+var OutputStream_name = map[int32]string{
+	0: "STDOUT",
+	1: "STDERR",
+}
+
+var OutputStream_value = map[string]int32{
+	"STDOUT": 0,
+	"STDERR": 1,
+}
+
+func (x OutputStream) String() string {
+	return proto.EnumName(OutputStream_name, int32(x))
+}
+
+func (OutputStream) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{0}
+}
+
 type LogLevel int32
 
 const (
@@ -55,23 +80,210 @@ func (x LogLevel) String() string {
 }
 
 func (LogLevel) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{1}
+}
+
+type OperationState int32
+
+const (
+	OperationState_RUNNING   OperationState = 0
+	OperationState_SUCCEEDED OperationState = 1
+	OperationState_FAILED    OperationState = 2
+	OperationState_CANCELLED OperationState = 3
+)
+
+var OperationState_name = map[int32]string{
+	0: "RUNNING",
+	1: "SUCCEEDED",
+	2: "FAILED",
+	3: "CANCELLED",
+}
+
+var OperationState_value = map[string]int32{
+	"RUNNING":   0,
+	"SUCCEEDED": 1,
+	"FAILED":    2,
+	"CANCELLED": 3,
+}
+
+func (x OperationState) String() string {
+	return proto.EnumName(OperationState_name, int32(x))
+}
+
+func (OperationState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{2}
+}
+
+type StageState int32
+
+const (
+	StageState_STAGE_STARTED  StageState = 0
+	StageState_STAGE_FINISHED StageState = 1
+	StageState_STAGE_FAILED   StageState = 2
+)
+
+var StageState_name = map[int32]string{
+	0: "STAGE_STARTED",
+	1: "STAGE_FINISHED",
+	2: "STAGE_FAILED",
+}
+
+var StageState_value = map[string]int32{
+	"STAGE_STARTED":  0,
+	"STAGE_FINISHED": 1,
+	"STAGE_FAILED":   2,
+}
+
+func (x StageState) String() string {
+	return proto.EnumName(StageState_name, int32(x))
+}
+
+func (StageState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{3}
+}
+
+type SegmentState int32
+
+const (
+	SegmentState_SEGMENT_CREATING SegmentState = 0
+	SegmentState_SEGMENT_CREATED  SegmentState = 1
+	SegmentState_SEGMENT_STARTING SegmentState = 2
+	SegmentState_SEGMENT_STARTED  SegmentState = 3
+	SegmentState_SEGMENT_FAILED   SegmentState = 4
+)
+
+var SegmentState_name = map[int32]string{
+	0: "SEGMENT_CREATING",
+	1: "SEGMENT_CREATED",
+	2: "SEGMENT_STARTING",
+	3: "SEGMENT_STARTED",
+	4: "SEGMENT_FAILED",
+}
+
+var SegmentState_value = map[string]int32{
+	"SEGMENT_CREATING": 0,
+	"SEGMENT_CREATED":  1,
+	"SEGMENT_STARTING": 2,
+	"SEGMENT_STARTED":  3,
+	"SEGMENT_FAILED":   4,
+}
+
+func (x SegmentState) String() string {
+	return proto.EnumName(SegmentState_name, int32(x))
+}
+
+func (SegmentState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{4}
+}
+
+type AdoptClusterRequest struct {
+	CoordinatorDataDir   string   `protobuf:"bytes,1,opt,name=CoordinatorDataDir,proto3" json:"CoordinatorDataDir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdoptClusterRequest) Reset()         { *m = AdoptClusterRequest{} }
+func (m *AdoptClusterRequest) String() string { return proto.CompactTextString(m) }
+func (*AdoptClusterRequest) ProtoMessage()    {}
+func (*AdoptClusterRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_b3103f8d3056b01c, []int{0}
 }
 
+func (m *AdoptClusterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdoptClusterRequest.Unmarshal(m, b)
+}
+func (m *AdoptClusterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdoptClusterRequest.Marshal(b, m, deterministic)
+}
+func (m *AdoptClusterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdoptClusterRequest.Merge(m, src)
+}
+func (m *AdoptClusterRequest) XXX_Size() int {
+	return xxx_messageInfo_AdoptClusterRequest.Size(m)
+}
+func (m *AdoptClusterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdoptClusterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdoptClusterRequest proto.InternalMessageInfo
+
+func (m *AdoptClusterRequest) GetCoordinatorDataDir() string {
+	if m != nil {
+		return m.CoordinatorDataDir
+	}
+	return ""
+}
+
+type AdoptClusterReply struct {
+	Hostnames            []string `protobuf:"bytes,1,rep,name=hostnames,proto3" json:"hostnames,omitempty"`
+	NumSegments          int32    `protobuf:"varint,2,opt,name=numSegments,proto3" json:"numSegments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdoptClusterReply) Reset()         { *m = AdoptClusterReply{} }
+func (m *AdoptClusterReply) String() string { return proto.CompactTextString(m) }
+func (*AdoptClusterReply) ProtoMessage()    {}
+func (*AdoptClusterReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{1}
+}
+
+func (m *AdoptClusterReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdoptClusterReply.Unmarshal(m, b)
+}
+func (m *AdoptClusterReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdoptClusterReply.Marshal(b, m, deterministic)
+}
+func (m *AdoptClusterReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdoptClusterReply.Merge(m, src)
+}
+func (m *AdoptClusterReply) XXX_Size() int {
+	return xxx_messageInfo_AdoptClusterReply.Size(m)
+}
+func (m *AdoptClusterReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdoptClusterReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdoptClusterReply proto.InternalMessageInfo
+
+func (m *AdoptClusterReply) GetHostnames() []string {
+	if m != nil {
+		return m.Hostnames
+	}
+	return nil
+}
+
+func (m *AdoptClusterReply) GetNumSegments() int32 {
+	if m != nil {
+		return m.NumSegments
+	}
+	return 0
+}
+
 type AddMirrorsRequest struct {
-	CoordinatorDataDir   string     `protobuf:"bytes,1,opt,name=CoordinatorDataDir,proto3" json:"CoordinatorDataDir,omitempty"`
-	HbaHostnames         bool       `protobuf:"varint,2,opt,name=HbaHostnames,proto3" json:"HbaHostnames,omitempty"`
-	Mirrors              []*Segment `protobuf:"bytes,3,rep,name=mirrors,proto3" json:"mirrors,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	CoordinatorDataDir string     `protobuf:"bytes,1,opt,name=CoordinatorDataDir,proto3" json:"CoordinatorDataDir,omitempty"`
+	HbaHostnames       bool       `protobuf:"varint,2,opt,name=HbaHostnames,proto3" json:"HbaHostnames,omitempty"`
+	Mirrors            []*Segment `protobuf:"bytes,3,rep,name=mirrors,proto3" json:"mirrors,omitempty"`
+	Topology           []byte     `protobuf:"bytes,4,opt,name=topology,proto3" json:"topology,omitempty"`
+	// places the mirrors of the primaries with the mirroring type, instead of mirrors or topology
+	MirroringType         string            `protobuf:"bytes,5,opt,name=mirroringType,proto3" json:"mirroringType,omitempty"`
+	MirrorBasePort        int32             `protobuf:"varint,6,opt,name=mirrorBasePort,proto3" json:"mirrorBasePort,omitempty"`
+	MirrorDataDirectories []string          `protobuf:"bytes,7,rep,name=mirrorDataDirectories,proto3" json:"mirrorDataDirectories,omitempty"`
+	BlockSize             int32             `protobuf:"varint,8,opt,name=blockSize,proto3" json:"blockSize,omitempty"`
+	FailureDomains        map[string]string `protobuf:"bytes,9,rep,name=failureDomains,proto3" json:"failureDomains,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RefusedFilesystems    []string          `protobuf:"bytes,10,rep,name=refusedFilesystems,proto3" json:"refusedFilesystems,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{}          `json:"-"`
+	XXX_unrecognized      []byte            `json:"-"`
+	XXX_sizecache         int32             `json:"-"`
 }
 
 func (m *AddMirrorsRequest) Reset()         { *m = AddMirrorsRequest{} }
 func (m *AddMirrorsRequest) String() string { return proto.CompactTextString(m) }
 func (*AddMirrorsRequest) ProtoMessage()    {}
 func (*AddMirrorsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{0}
+	return fileDescriptor_b3103f8d3056b01c, []int{2}
 }
 
 func (m *AddMirrorsRequest) XXX_Unmarshal(b []byte) error {
@@ -113,6 +325,55 @@ func (m *AddMirrorsRequest) GetMirrors() []*Segment {
 	return nil
 }
 
+func (m *AddMirrorsRequest) GetTopology() []byte {
+	if m != nil {
+		return m.Topology
+	}
+	return nil
+}
+
+func (m *AddMirrorsRequest) GetMirroringType() string {
+	if m != nil {
+		return m.MirroringType
+	}
+	return ""
+}
+
+func (m *AddMirrorsRequest) GetMirrorBasePort() int32 {
+	if m != nil {
+		return m.MirrorBasePort
+	}
+	return 0
+}
+
+func (m *AddMirrorsRequest) GetMirrorDataDirectories() []string {
+	if m != nil {
+		return m.MirrorDataDirectories
+	}
+	return nil
+}
+
+func (m *AddMirrorsRequest) GetBlockSize() int32 {
+	if m != nil {
+		return m.BlockSize
+	}
+	return 0
+}
+
+func (m *AddMirrorsRequest) GetFailureDomains() map[string]string {
+	if m != nil {
+		return m.FailureDomains
+	}
+	return nil
+}
+
+func (m *AddMirrorsRequest) GetRefusedFilesystems() []string {
+	if m != nil {
+		return m.RefusedFilesystems
+	}
+	return nil
+}
+
 type GetAllHostNamesRequest struct {
 	HostList             []string `protobuf:"bytes,1,rep,name=hostList,proto3" json:"hostList,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -124,7 +385,7 @@ func (m *GetAllHostNamesRequest) Reset()         { *m = GetAllHostNamesRequest{}
 func (m *GetAllHostNamesRequest) String() string { return proto.CompactTextString(m) }
 func (*GetAllHostNamesRequest) ProtoMessage()    {}
 func (*GetAllHostNamesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{1}
+	return fileDescriptor_b3103f8d3056b01c, []int{3}
 }
 
 func (m *GetAllHostNamesRequest) XXX_Unmarshal(b []byte) error {
@@ -163,7 +424,7 @@ func (m *GetAllHostNamesReply) Reset()         { *m = GetAllHostNamesReply{} }
 func (m *GetAllHostNamesReply) String() string { return proto.CompactTextString(m) }
 func (*GetAllHostNamesReply) ProtoMessage()    {}
 func (*GetAllHostNamesReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{2}
+	return fileDescriptor_b3103f8d3056b01c, []int{4}
 }
 
 func (m *GetAllHostNamesReply) XXX_Unmarshal(b []byte) error {
@@ -191,6 +452,116 @@ func (m *GetAllHostNamesReply) GetHostNameMap() map[string]string {
 	return nil
 }
 
+type FindFreePortsRequest struct {
+	HostList             []string `protobuf:"bytes,1,rep,name=hostList,proto3" json:"hostList,omitempty"`
+	Count                int32    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	MinPort              int32    `protobuf:"varint,3,opt,name=minPort,proto3" json:"minPort,omitempty"`
+	MaxPort              int32    `protobuf:"varint,4,opt,name=maxPort,proto3" json:"maxPort,omitempty"`
+	ExcludedPorts        []int32  `protobuf:"varint,5,rep,packed,name=excludedPorts,proto3" json:"excludedPorts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindFreePortsRequest) Reset()         { *m = FindFreePortsRequest{} }
+func (m *FindFreePortsRequest) String() string { return proto.CompactTextString(m) }
+func (*FindFreePortsRequest) ProtoMessage()    {}
+func (*FindFreePortsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{5}
+}
+
+func (m *FindFreePortsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindFreePortsRequest.Unmarshal(m, b)
+}
+func (m *FindFreePortsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindFreePortsRequest.Marshal(b, m, deterministic)
+}
+func (m *FindFreePortsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindFreePortsRequest.Merge(m, src)
+}
+func (m *FindFreePortsRequest) XXX_Size() int {
+	return xxx_messageInfo_FindFreePortsRequest.Size(m)
+}
+func (m *FindFreePortsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindFreePortsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindFreePortsRequest proto.InternalMessageInfo
+
+func (m *FindFreePortsRequest) GetHostList() []string {
+	if m != nil {
+		return m.HostList
+	}
+	return nil
+}
+
+func (m *FindFreePortsRequest) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *FindFreePortsRequest) GetMinPort() int32 {
+	if m != nil {
+		return m.MinPort
+	}
+	return 0
+}
+
+func (m *FindFreePortsRequest) GetMaxPort() int32 {
+	if m != nil {
+		return m.MaxPort
+	}
+	return 0
+}
+
+func (m *FindFreePortsRequest) GetExcludedPorts() []int32 {
+	if m != nil {
+		return m.ExcludedPorts
+	}
+	return nil
+}
+
+type FindFreePortsReply struct {
+	BasePort             int32    `protobuf:"varint,1,opt,name=basePort,proto3" json:"basePort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindFreePortsReply) Reset()         { *m = FindFreePortsReply{} }
+func (m *FindFreePortsReply) String() string { return proto.CompactTextString(m) }
+func (*FindFreePortsReply) ProtoMessage()    {}
+func (*FindFreePortsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{6}
+}
+
+func (m *FindFreePortsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindFreePortsReply.Unmarshal(m, b)
+}
+func (m *FindFreePortsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindFreePortsReply.Marshal(b, m, deterministic)
+}
+func (m *FindFreePortsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindFreePortsReply.Merge(m, src)
+}
+func (m *FindFreePortsReply) XXX_Size() int {
+	return xxx_messageInfo_FindFreePortsReply.Size(m)
+}
+func (m *FindFreePortsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindFreePortsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindFreePortsReply proto.InternalMessageInfo
+
+func (m *FindFreePortsReply) GetBasePort() int32 {
+	if m != nil {
+		return m.BasePort
+	}
+	return 0
+}
+
 type StopHubRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -201,7 +572,7 @@ func (m *StopHubRequest) Reset()         { *m = StopHubRequest{} }
 func (m *StopHubRequest) String() string { return proto.CompactTextString(m) }
 func (*StopHubRequest) ProtoMessage()    {}
 func (*StopHubRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{3}
+	return fileDescriptor_b3103f8d3056b01c, []int{7}
 }
 
 func (m *StopHubRequest) XXX_Unmarshal(b []byte) error {
@@ -232,7 +603,7 @@ func (m *StopHubReply) Reset()         { *m = StopHubReply{} }
 func (m *StopHubReply) String() string { return proto.CompactTextString(m) }
 func (*StopHubReply) ProtoMessage()    {}
 func (*StopHubReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{4}
+	return fileDescriptor_b3103f8d3056b01c, []int{8}
 }
 
 func (m *StopHubReply) XXX_Unmarshal(b []byte) error {
@@ -263,7 +634,7 @@ func (m *StartAgentsRequest) Reset()         { *m = StartAgentsRequest{} }
 func (m *StartAgentsRequest) String() string { return proto.CompactTextString(m) }
 func (*StartAgentsRequest) ProtoMessage()    {}
 func (*StartAgentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{5}
+	return fileDescriptor_b3103f8d3056b01c, []int{9}
 }
 
 func (m *StartAgentsRequest) XXX_Unmarshal(b []byte) error {
@@ -294,7 +665,7 @@ func (m *StartAgentsReply) Reset()         { *m = StartAgentsReply{} }
 func (m *StartAgentsReply) String() string { return proto.CompactTextString(m) }
 func (*StartAgentsReply) ProtoMessage()    {}
 func (*StartAgentsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{6}
+	return fileDescriptor_b3103f8d3056b01c, []int{10}
 }
 
 func (m *StartAgentsReply) XXX_Unmarshal(b []byte) error {
@@ -325,7 +696,7 @@ func (m *StatusAgentsRequest) Reset()         { *m = StatusAgentsRequest{} }
 func (m *StatusAgentsRequest) String() string { return proto.CompactTextString(m) }
 func (*StatusAgentsRequest) ProtoMessage()    {}
 func (*StatusAgentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{7}
+	return fileDescriptor_b3103f8d3056b01c, []int{11}
 }
 
 func (m *StatusAgentsRequest) XXX_Unmarshal(b []byte) error {
@@ -356,7 +727,7 @@ func (m *CleanInitClusterRequest) Reset()         { *m = CleanInitClusterRequest
 func (m *CleanInitClusterRequest) String() string { return proto.CompactTextString(m) }
 func (*CleanInitClusterRequest) ProtoMessage()    {}
 func (*CleanInitClusterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{8}
+	return fileDescriptor_b3103f8d3056b01c, []int{12}
 }
 
 func (m *CleanInitClusterRequest) XXX_Unmarshal(b []byte) error {
@@ -387,7 +758,7 @@ func (m *CleanInitClusterReply) Reset()         { *m = CleanInitClusterReply{} }
 func (m *CleanInitClusterReply) String() string { return proto.CompactTextString(m) }
 func (*CleanInitClusterReply) ProtoMessage()    {}
 func (*CleanInitClusterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{9}
+	return fileDescriptor_b3103f8d3056b01c, []int{13}
 }
 
 func (m *CleanInitClusterReply) XXX_Unmarshal(b []byte) error {
@@ -413,6 +784,7 @@ type ServiceStatus struct {
 	Status               string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
 	Uptime               string   `protobuf:"bytes,3,opt,name=uptime,proto3" json:"uptime,omitempty"`
 	Pid                  uint32   `protobuf:"varint,4,opt,name=pid,proto3" json:"pid,omitempty"`
+	CertificateExpiry    int64    `protobuf:"varint,5,opt,name=certificateExpiry,proto3" json:"certificateExpiry,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -422,7 +794,7 @@ func (m *ServiceStatus) Reset()         { *m = ServiceStatus{} }
 func (m *ServiceStatus) String() string { return proto.CompactTextString(m) }
 func (*ServiceStatus) ProtoMessage()    {}
 func (*ServiceStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{10}
+	return fileDescriptor_b3103f8d3056b01c, []int{14}
 }
 
 func (m *ServiceStatus) XXX_Unmarshal(b []byte) error {
@@ -471,8 +843,16 @@ func (m *ServiceStatus) GetPid() uint32 {
 	return 0
 }
 
+func (m *ServiceStatus) GetCertificateExpiry() int64 {
+	if m != nil {
+		return m.CertificateExpiry
+	}
+	return 0
+}
+
 type StatusAgentsReply struct {
 	Statuses             []*ServiceStatus `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	Unreachable          []*HostError     `protobuf:"bytes,2,rep,name=unreachable,proto3" json:"unreachable,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -482,7 +862,7 @@ func (m *StatusAgentsReply) Reset()         { *m = StatusAgentsReply{} }
 func (m *StatusAgentsReply) String() string { return proto.CompactTextString(m) }
 func (*StatusAgentsReply) ProtoMessage()    {}
 func (*StatusAgentsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{11}
+	return fileDescriptor_b3103f8d3056b01c, []int{15}
 }
 
 func (m *StatusAgentsReply) XXX_Unmarshal(b []byte) error {
@@ -510,6 +890,13 @@ func (m *StatusAgentsReply) GetStatuses() []*ServiceStatus {
 	return nil
 }
 
+func (m *StatusAgentsReply) GetUnreachable() []*HostError {
+	if m != nil {
+		return m.Unreachable
+	}
+	return nil
+}
+
 type StopAgentsRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -520,7 +907,7 @@ func (m *StopAgentsRequest) Reset()         { *m = StopAgentsRequest{} }
 func (m *StopAgentsRequest) String() string { return proto.CompactTextString(m) }
 func (*StopAgentsRequest) ProtoMessage()    {}
 func (*StopAgentsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{12}
+	return fileDescriptor_b3103f8d3056b01c, []int{16}
 }
 
 func (m *StopAgentsRequest) XXX_Unmarshal(b []byte) error {
@@ -551,7 +938,7 @@ func (m *StopAgentsReply) Reset()         { *m = StopAgentsReply{} }
 func (m *StopAgentsReply) String() string { return proto.CompactTextString(m) }
 func (*StopAgentsReply) ProtoMessage()    {}
 func (*StopAgentsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{13}
+	return fileDescriptor_b3103f8d3056b01c, []int{17}
 }
 
 func (m *StopAgentsReply) XXX_Unmarshal(b []byte) error {
@@ -577,6 +964,7 @@ type MakeClusterRequest struct {
 	ClusterParams        *ClusterParams `protobuf:"bytes,2,opt,name=clusterParams,proto3" json:"clusterParams,omitempty"`
 	ForceFlag            bool           `protobuf:"varint,3,opt,name=forceFlag,proto3" json:"forceFlag,omitempty"`
 	Verbose              bool           `protobuf:"varint,4,opt,name=verbose,proto3" json:"verbose,omitempty"`
+	RefusedFilesystems   []string       `protobuf:"bytes,5,rep,name=refusedFilesystems,proto3" json:"refusedFilesystems,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -586,7 +974,7 @@ func (m *MakeClusterRequest) Reset()         { *m = MakeClusterRequest{} }
 func (m *MakeClusterRequest) String() string { return proto.CompactTextString(m) }
 func (*MakeClusterRequest) ProtoMessage()    {}
 func (*MakeClusterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{14}
+	return fileDescriptor_b3103f8d3056b01c, []int{18}
 }
 
 func (m *MakeClusterRequest) XXX_Unmarshal(b []byte) error {
@@ -635,12 +1023,24 @@ func (m *MakeClusterRequest) GetVerbose() bool {
 	return false
 }
 
+func (m *MakeClusterRequest) GetRefusedFilesystems() []string {
+	if m != nil {
+		return m.RefusedFilesystems
+	}
+	return nil
+}
+
 type HubReply struct {
 	// Types that are valid to be assigned to Message:
-	//
 	//	*HubReply_LogMsg
 	//	*HubReply_StdoutMsg
 	//	*HubReply_ProgressMsg
+	//	*HubReply_OperationId
+	//	*HubReply_TransferMsg
+	//	*HubReply_OutputMsg
+	//	*HubReply_StageMsg
+	//	*HubReply_SegmentMsg
+	//	*HubReply_ResultMsg
 	Message              isHubReply_Message `protobuf_oneof:"message"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
@@ -651,7 +1051,7 @@ func (m *HubReply) Reset()         { *m = HubReply{} }
 func (m *HubReply) String() string { return proto.CompactTextString(m) }
 func (*HubReply) ProtoMessage()    {}
 func (*HubReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{15}
+	return fileDescriptor_b3103f8d3056b01c, []int{19}
 }
 
 func (m *HubReply) XXX_Unmarshal(b []byte) error {
@@ -688,143 +1088,1926 @@ type HubReply_ProgressMsg struct {
 	ProgressMsg *ProgressMessage `protobuf:"bytes,3,opt,name=progressMsg,proto3,oneof"`
 }
 
-func (*HubReply_LogMsg) isHubReply_Message() {}
+type HubReply_OperationId struct {
+	OperationId string `protobuf:"bytes,4,opt,name=operationId,proto3,oneof"`
+}
 
-func (*HubReply_StdoutMsg) isHubReply_Message() {}
+type HubReply_TransferMsg struct {
+	TransferMsg *TransferMessage `protobuf:"bytes,5,opt,name=transferMsg,proto3,oneof"`
+}
 
-func (*HubReply_ProgressMsg) isHubReply_Message() {}
+type HubReply_OutputMsg struct {
+	OutputMsg *OutputMessage `protobuf:"bytes,6,opt,name=outputMsg,proto3,oneof"`
+}
 
-func (m *HubReply) GetMessage() isHubReply_Message {
-	if m != nil {
-		return m.Message
-	}
-	return nil
+type HubReply_StageMsg struct {
+	StageMsg *StageEvent `protobuf:"bytes,7,opt,name=stageMsg,proto3,oneof"`
+}
+
+type HubReply_SegmentMsg struct {
+	SegmentMsg *SegmentEvent `protobuf:"bytes,8,opt,name=segmentMsg,proto3,oneof"`
+}
+
+type HubReply_ResultMsg struct {
+	ResultMsg *ResultSummary `protobuf:"bytes,9,opt,name=resultMsg,proto3,oneof"`
+}
+
+func (*HubReply_LogMsg) isHubReply_Message() {}
+
+func (*HubReply_StdoutMsg) isHubReply_Message() {}
+
+func (*HubReply_ProgressMsg) isHubReply_Message() {}
+
+func (*HubReply_OperationId) isHubReply_Message() {}
+
+func (*HubReply_TransferMsg) isHubReply_Message() {}
+
+func (*HubReply_OutputMsg) isHubReply_Message() {}
+
+func (*HubReply_StageMsg) isHubReply_Message() {}
+
+func (*HubReply_SegmentMsg) isHubReply_Message() {}
+
+func (*HubReply_ResultMsg) isHubReply_Message() {}
+
+func (m *HubReply) GetMessage() isHubReply_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
 }
 
 func (m *HubReply) GetLogMsg() *LogMessage {
 	if x, ok := m.GetMessage().(*HubReply_LogMsg); ok {
 		return x.LogMsg
 	}
-	return nil
+	return nil
+}
+
+func (m *HubReply) GetStdoutMsg() string {
+	if x, ok := m.GetMessage().(*HubReply_StdoutMsg); ok {
+		return x.StdoutMsg
+	}
+	return ""
+}
+
+func (m *HubReply) GetProgressMsg() *ProgressMessage {
+	if x, ok := m.GetMessage().(*HubReply_ProgressMsg); ok {
+		return x.ProgressMsg
+	}
+	return nil
+}
+
+func (m *HubReply) GetOperationId() string {
+	if x, ok := m.GetMessage().(*HubReply_OperationId); ok {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (m *HubReply) GetTransferMsg() *TransferMessage {
+	if x, ok := m.GetMessage().(*HubReply_TransferMsg); ok {
+		return x.TransferMsg
+	}
+	return nil
+}
+
+func (m *HubReply) GetOutputMsg() *OutputMessage {
+	if x, ok := m.GetMessage().(*HubReply_OutputMsg); ok {
+		return x.OutputMsg
+	}
+	return nil
+}
+
+func (m *HubReply) GetStageMsg() *StageEvent {
+	if x, ok := m.GetMessage().(*HubReply_StageMsg); ok {
+		return x.StageMsg
+	}
+	return nil
+}
+
+func (m *HubReply) GetSegmentMsg() *SegmentEvent {
+	if x, ok := m.GetMessage().(*HubReply_SegmentMsg); ok {
+		return x.SegmentMsg
+	}
+	return nil
+}
+
+func (m *HubReply) GetResultMsg() *ResultSummary {
+	if x, ok := m.GetMessage().(*HubReply_ResultMsg); ok {
+		return x.ResultMsg
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*HubReply) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*HubReply_LogMsg)(nil),
+		(*HubReply_StdoutMsg)(nil),
+		(*HubReply_ProgressMsg)(nil),
+		(*HubReply_OperationId)(nil),
+		(*HubReply_TransferMsg)(nil),
+		(*HubReply_OutputMsg)(nil),
+		(*HubReply_StageMsg)(nil),
+		(*HubReply_SegmentMsg)(nil),
+		(*HubReply_ResultMsg)(nil),
+	}
+}
+
+// a line of output of a command, tagged with where it comes from
+type OutputMessage struct {
+	Host                 string       `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Segment              string       `protobuf:"bytes,2,opt,name=segment,proto3" json:"segment,omitempty"`
+	Dbid                 int32        `protobuf:"varint,3,opt,name=dbid,proto3" json:"dbid,omitempty"`
+	Stream               OutputStream `protobuf:"varint,4,opt,name=stream,proto3,enum=idl.OutputStream" json:"stream,omitempty"`
+	Sequence             uint64       `protobuf:"varint,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Timestamp            int64        `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Line                 string       `protobuf:"bytes,7,opt,name=line,proto3" json:"line,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *OutputMessage) Reset()         { *m = OutputMessage{} }
+func (m *OutputMessage) String() string { return proto.CompactTextString(m) }
+func (*OutputMessage) ProtoMessage()    {}
+func (*OutputMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{20}
+}
+
+func (m *OutputMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OutputMessage.Unmarshal(m, b)
+}
+func (m *OutputMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OutputMessage.Marshal(b, m, deterministic)
+}
+func (m *OutputMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutputMessage.Merge(m, src)
+}
+func (m *OutputMessage) XXX_Size() int {
+	return xxx_messageInfo_OutputMessage.Size(m)
+}
+func (m *OutputMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutputMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutputMessage proto.InternalMessageInfo
+
+func (m *OutputMessage) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *OutputMessage) GetSegment() string {
+	if m != nil {
+		return m.Segment
+	}
+	return ""
+}
+
+func (m *OutputMessage) GetDbid() int32 {
+	if m != nil {
+		return m.Dbid
+	}
+	return 0
+}
+
+func (m *OutputMessage) GetStream() OutputStream {
+	if m != nil {
+		return m.Stream
+	}
+	return OutputStream_STDOUT
+}
+
+func (m *OutputMessage) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *OutputMessage) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *OutputMessage) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
+type LogMessage struct {
+	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Level                LogLevel `protobuf:"varint,2,opt,name=level,proto3,enum=idl.LogLevel" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogMessage) Reset()         { *m = LogMessage{} }
+func (m *LogMessage) String() string { return proto.CompactTextString(m) }
+func (*LogMessage) ProtoMessage()    {}
+func (*LogMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{21}
+}
+
+func (m *LogMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogMessage.Unmarshal(m, b)
+}
+func (m *LogMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogMessage.Marshal(b, m, deterministic)
+}
+func (m *LogMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogMessage.Merge(m, src)
+}
+func (m *LogMessage) XXX_Size() int {
+	return xxx_messageInfo_LogMessage.Size(m)
+}
+func (m *LogMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogMessage proto.InternalMessageInfo
+
+func (m *LogMessage) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *LogMessage) GetLevel() LogLevel {
+	if m != nil {
+		return m.Level
+	}
+	return LogLevel_FATAL
+}
+
+type ListOperationsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOperationsRequest) Reset()         { *m = ListOperationsRequest{} }
+func (m *ListOperationsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListOperationsRequest) ProtoMessage()    {}
+func (*ListOperationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{22}
+}
+
+func (m *ListOperationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOperationsRequest.Unmarshal(m, b)
+}
+func (m *ListOperationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOperationsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListOperationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOperationsRequest.Merge(m, src)
+}
+func (m *ListOperationsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListOperationsRequest.Size(m)
+}
+func (m *ListOperationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOperationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOperationsRequest proto.InternalMessageInfo
+
+type ListOperationsReply struct {
+	Operations           []*Operation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListOperationsReply) Reset()         { *m = ListOperationsReply{} }
+func (m *ListOperationsReply) String() string { return proto.CompactTextString(m) }
+func (*ListOperationsReply) ProtoMessage()    {}
+func (*ListOperationsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{23}
+}
+
+func (m *ListOperationsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOperationsReply.Unmarshal(m, b)
+}
+func (m *ListOperationsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOperationsReply.Marshal(b, m, deterministic)
+}
+func (m *ListOperationsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOperationsReply.Merge(m, src)
+}
+func (m *ListOperationsReply) XXX_Size() int {
+	return xxx_messageInfo_ListOperationsReply.Size(m)
+}
+func (m *ListOperationsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOperationsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOperationsReply proto.InternalMessageInfo
+
+func (m *ListOperationsReply) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+type AttachOperationRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AttachOperationRequest) Reset()         { *m = AttachOperationRequest{} }
+func (m *AttachOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*AttachOperationRequest) ProtoMessage()    {}
+func (*AttachOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{24}
+}
+
+func (m *AttachOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttachOperationRequest.Unmarshal(m, b)
+}
+func (m *AttachOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttachOperationRequest.Marshal(b, m, deterministic)
+}
+func (m *AttachOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttachOperationRequest.Merge(m, src)
+}
+func (m *AttachOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_AttachOperationRequest.Size(m)
+}
+func (m *AttachOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttachOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttachOperationRequest proto.InternalMessageInfo
+
+func (m *AttachOperationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type CancelOperationRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelOperationRequest) Reset()         { *m = CancelOperationRequest{} }
+func (m *CancelOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelOperationRequest) ProtoMessage()    {}
+func (*CancelOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{25}
+}
+
+func (m *CancelOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelOperationRequest.Unmarshal(m, b)
+}
+func (m *CancelOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelOperationRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelOperationRequest.Merge(m, src)
+}
+func (m *CancelOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelOperationRequest.Size(m)
+}
+func (m *CancelOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelOperationRequest proto.InternalMessageInfo
+
+func (m *CancelOperationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type CancelOperationReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelOperationReply) Reset()         { *m = CancelOperationReply{} }
+func (m *CancelOperationReply) String() string { return proto.CompactTextString(m) }
+func (*CancelOperationReply) ProtoMessage()    {}
+func (*CancelOperationReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{26}
+}
+
+func (m *CancelOperationReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelOperationReply.Unmarshal(m, b)
+}
+func (m *CancelOperationReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelOperationReply.Marshal(b, m, deterministic)
+}
+func (m *CancelOperationReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelOperationReply.Merge(m, src)
+}
+func (m *CancelOperationReply) XXX_Size() int {
+	return xxx_messageInfo_CancelOperationReply.Size(m)
+}
+func (m *CancelOperationReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelOperationReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelOperationReply proto.InternalMessageInfo
+
+type ListEventsRequest struct {
+	Since                int64    `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	OperationId          string   `protobuf:"bytes,2,opt,name=operationId,proto3" json:"operationId,omitempty"`
+	Host                 string   `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	OperationsOnly       bool     `protobuf:"varint,4,opt,name=operationsOnly,proto3" json:"operationsOnly,omitempty"`
+	PageSize             int32    `protobuf:"varint,5,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken            string   `protobuf:"bytes,6,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListEventsRequest) Reset()         { *m = ListEventsRequest{} }
+func (m *ListEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListEventsRequest) ProtoMessage()    {}
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{27}
+}
+
+func (m *ListEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListEventsRequest.Unmarshal(m, b)
+}
+func (m *ListEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListEventsRequest.Merge(m, src)
+}
+func (m *ListEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListEventsRequest.Size(m)
+}
+func (m *ListEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListEventsRequest proto.InternalMessageInfo
+
+func (m *ListEventsRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *ListEventsRequest) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *ListEventsRequest) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *ListEventsRequest) GetOperationsOnly() bool {
+	if m != nil {
+		return m.OperationsOnly
+	}
+	return false
+}
+
+func (m *ListEventsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListEventsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+type ListEventsReply struct {
+	Events               []*StoredEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken        string         `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListEventsReply) Reset()         { *m = ListEventsReply{} }
+func (m *ListEventsReply) String() string { return proto.CompactTextString(m) }
+func (*ListEventsReply) ProtoMessage()    {}
+func (*ListEventsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{28}
+}
+
+func (m *ListEventsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListEventsReply.Unmarshal(m, b)
+}
+func (m *ListEventsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListEventsReply.Marshal(b, m, deterministic)
+}
+func (m *ListEventsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListEventsReply.Merge(m, src)
+}
+func (m *ListEventsReply) XXX_Size() int {
+	return xxx_messageInfo_ListEventsReply.Size(m)
+}
+func (m *ListEventsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListEventsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListEventsReply proto.InternalMessageInfo
+
+func (m *ListEventsReply) GetEvents() []*StoredEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *ListEventsReply) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// an event of an operation recorded by the hub, like a log message or a stage which started
+type StoredEvent struct {
+	Timestamp            int64    `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	OperationId          string   `protobuf:"bytes,2,opt,name=operationId,proto3" json:"operationId,omitempty"`
+	Method               string   `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Type                 string   `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Caller               string   `protobuf:"bytes,5,opt,name=caller,proto3" json:"caller,omitempty"`
+	State                string   `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	Level                string   `protobuf:"bytes,7,opt,name=level,proto3" json:"level,omitempty"`
+	Stage                string   `protobuf:"bytes,8,opt,name=stage,proto3" json:"stage,omitempty"`
+	Host                 string   `protobuf:"bytes,9,opt,name=host,proto3" json:"host,omitempty"`
+	Segment              string   `protobuf:"bytes,10,opt,name=segment,proto3" json:"segment,omitempty"`
+	Dbid                 int32    `protobuf:"varint,11,opt,name=dbid,proto3" json:"dbid,omitempty"`
+	Message              string   `protobuf:"bytes,12,opt,name=message,proto3" json:"message,omitempty"`
+	Error                string   `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
+	Current              int64    `protobuf:"varint,14,opt,name=current,proto3" json:"current,omitempty"`
+	Total                int64    `protobuf:"varint,15,opt,name=total,proto3" json:"total,omitempty"`
+	ClaimedBy            string   `protobuf:"bytes,16,opt,name=claimedBy,proto3" json:"claimedBy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoredEvent) Reset()         { *m = StoredEvent{} }
+func (m *StoredEvent) String() string { return proto.CompactTextString(m) }
+func (*StoredEvent) ProtoMessage()    {}
+func (*StoredEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{29}
+}
+
+func (m *StoredEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoredEvent.Unmarshal(m, b)
+}
+func (m *StoredEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoredEvent.Marshal(b, m, deterministic)
+}
+func (m *StoredEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoredEvent.Merge(m, src)
+}
+func (m *StoredEvent) XXX_Size() int {
+	return xxx_messageInfo_StoredEvent.Size(m)
+}
+func (m *StoredEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoredEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoredEvent proto.InternalMessageInfo
+
+func (m *StoredEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *StoredEvent) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetCaller() string {
+	if m != nil {
+		return m.Caller
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetStage() string {
+	if m != nil {
+		return m.Stage
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetSegment() string {
+	if m != nil {
+		return m.Segment
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetDbid() int32 {
+	if m != nil {
+		return m.Dbid
+	}
+	return 0
+}
+
+func (m *StoredEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetCurrent() int64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+func (m *StoredEvent) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *StoredEvent) GetClaimedBy() string {
+	if m != nil {
+		return m.ClaimedBy
+	}
+	return ""
+}
+
+type ListTopologyRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTopologyRequest) Reset()         { *m = ListTopologyRequest{} }
+func (m *ListTopologyRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTopologyRequest) ProtoMessage()    {}
+func (*ListTopologyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{30}
+}
+
+func (m *ListTopologyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTopologyRequest.Unmarshal(m, b)
+}
+func (m *ListTopologyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTopologyRequest.Marshal(b, m, deterministic)
+}
+func (m *ListTopologyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTopologyRequest.Merge(m, src)
+}
+func (m *ListTopologyRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTopologyRequest.Size(m)
+}
+func (m *ListTopologyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTopologyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTopologyRequest proto.InternalMessageInfo
+
+type ListTopologyReply struct {
+	Snapshots            []*TopologySnapshot `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ListTopologyReply) Reset()         { *m = ListTopologyReply{} }
+func (m *ListTopologyReply) String() string { return proto.CompactTextString(m) }
+func (*ListTopologyReply) ProtoMessage()    {}
+func (*ListTopologyReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{31}
+}
+
+func (m *ListTopologyReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTopologyReply.Unmarshal(m, b)
+}
+func (m *ListTopologyReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTopologyReply.Marshal(b, m, deterministic)
+}
+func (m *ListTopologyReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTopologyReply.Merge(m, src)
+}
+func (m *ListTopologyReply) XXX_Size() int {
+	return xxx_messageInfo_ListTopologyReply.Size(m)
+}
+func (m *ListTopologyReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTopologyReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTopologyReply proto.InternalMessageInfo
+
+func (m *ListTopologyReply) GetSnapshots() []*TopologySnapshot {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+// a snapshot of gp_segment_configuration recorded by the hub around an operation changing the topology
+type TopologySnapshot struct {
+	Version              int32    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Timestamp            int64    `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	OperationId          string   `protobuf:"bytes,3,opt,name=operationId,proto3" json:"operationId,omitempty"`
+	Method               string   `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	Phase                string   `protobuf:"bytes,5,opt,name=phase,proto3" json:"phase,omitempty"`
+	Segments             int32    `protobuf:"varint,6,opt,name=segments,proto3" json:"segments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopologySnapshot) Reset()         { *m = TopologySnapshot{} }
+func (m *TopologySnapshot) String() string { return proto.CompactTextString(m) }
+func (*TopologySnapshot) ProtoMessage()    {}
+func (*TopologySnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{32}
+}
+
+func (m *TopologySnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopologySnapshot.Unmarshal(m, b)
+}
+func (m *TopologySnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopologySnapshot.Marshal(b, m, deterministic)
+}
+func (m *TopologySnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopologySnapshot.Merge(m, src)
+}
+func (m *TopologySnapshot) XXX_Size() int {
+	return xxx_messageInfo_TopologySnapshot.Size(m)
+}
+func (m *TopologySnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopologySnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopologySnapshot proto.InternalMessageInfo
+
+func (m *TopologySnapshot) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *TopologySnapshot) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *TopologySnapshot) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *TopologySnapshot) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *TopologySnapshot) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *TopologySnapshot) GetSegments() int32 {
+	if m != nil {
+		return m.Segments
+	}
+	return 0
+}
+
+type DiffTopologyRequest struct {
+	From                 int32    `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   int32    `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffTopologyRequest) Reset()         { *m = DiffTopologyRequest{} }
+func (m *DiffTopologyRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffTopologyRequest) ProtoMessage()    {}
+func (*DiffTopologyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{33}
+}
+
+func (m *DiffTopologyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffTopologyRequest.Unmarshal(m, b)
+}
+func (m *DiffTopologyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffTopologyRequest.Marshal(b, m, deterministic)
+}
+func (m *DiffTopologyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffTopologyRequest.Merge(m, src)
+}
+func (m *DiffTopologyRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffTopologyRequest.Size(m)
+}
+func (m *DiffTopologyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffTopologyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffTopologyRequest proto.InternalMessageInfo
+
+func (m *DiffTopologyRequest) GetFrom() int32 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *DiffTopologyRequest) GetTo() int32 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+type DiffTopologyReply struct {
+	Changes              []*TopologyChange `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DiffTopologyReply) Reset()         { *m = DiffTopologyReply{} }
+func (m *DiffTopologyReply) String() string { return proto.CompactTextString(m) }
+func (*DiffTopologyReply) ProtoMessage()    {}
+func (*DiffTopologyReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{34}
+}
+
+func (m *DiffTopologyReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffTopologyReply.Unmarshal(m, b)
+}
+func (m *DiffTopologyReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffTopologyReply.Marshal(b, m, deterministic)
+}
+func (m *DiffTopologyReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffTopologyReply.Merge(m, src)
+}
+func (m *DiffTopologyReply) XXX_Size() int {
+	return xxx_messageInfo_DiffTopologyReply.Size(m)
+}
+func (m *DiffTopologyReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffTopologyReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffTopologyReply proto.InternalMessageInfo
+
+func (m *DiffTopologyReply) GetChanges() []*TopologyChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+// a change of a segment between two topology snapshots
+type TopologyChange struct {
+	Dbid                 int32    `protobuf:"varint,1,opt,name=dbid,proto3" json:"dbid,omitempty"`
+	Contentid            int32    `protobuf:"varint,2,opt,name=contentid,proto3" json:"contentid,omitempty"`
+	Kind                 string   `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	From                 string   `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopologyChange) Reset()         { *m = TopologyChange{} }
+func (m *TopologyChange) String() string { return proto.CompactTextString(m) }
+func (*TopologyChange) ProtoMessage()    {}
+func (*TopologyChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{35}
+}
+
+func (m *TopologyChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopologyChange.Unmarshal(m, b)
+}
+func (m *TopologyChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopologyChange.Marshal(b, m, deterministic)
+}
+func (m *TopologyChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopologyChange.Merge(m, src)
+}
+func (m *TopologyChange) XXX_Size() int {
+	return xxx_messageInfo_TopologyChange.Size(m)
+}
+func (m *TopologyChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopologyChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopologyChange proto.InternalMessageInfo
+
+func (m *TopologyChange) GetDbid() int32 {
+	if m != nil {
+		return m.Dbid
+	}
+	return 0
+}
+
+func (m *TopologyChange) GetContentid() int32 {
+	if m != nil {
+		return m.Contentid
+	}
+	return 0
+}
+
+func (m *TopologyChange) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *TopologyChange) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *TopologyChange) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type RestoreTopologyRequest struct {
+	Version              int32    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreTopologyRequest) Reset()         { *m = RestoreTopologyRequest{} }
+func (m *RestoreTopologyRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreTopologyRequest) ProtoMessage()    {}
+func (*RestoreTopologyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{36}
+}
+
+func (m *RestoreTopologyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreTopologyRequest.Unmarshal(m, b)
+}
+func (m *RestoreTopologyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreTopologyRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreTopologyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreTopologyRequest.Merge(m, src)
+}
+func (m *RestoreTopologyRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreTopologyRequest.Size(m)
+}
+func (m *RestoreTopologyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreTopologyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreTopologyRequest proto.InternalMessageInfo
+
+func (m *RestoreTopologyRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ExportTopologyRequest struct {
+	Version              int32    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportTopologyRequest) Reset()         { *m = ExportTopologyRequest{} }
+func (m *ExportTopologyRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportTopologyRequest) ProtoMessage()    {}
+func (*ExportTopologyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{37}
+}
+
+func (m *ExportTopologyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportTopologyRequest.Unmarshal(m, b)
+}
+func (m *ExportTopologyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportTopologyRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportTopologyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportTopologyRequest.Merge(m, src)
+}
+func (m *ExportTopologyRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportTopologyRequest.Size(m)
+}
+func (m *ExportTopologyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportTopologyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportTopologyRequest proto.InternalMessageInfo
+
+func (m *ExportTopologyRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ExportTopologyReply struct {
+	Topology             []byte   `protobuf:"bytes,1,opt,name=topology,proto3" json:"topology,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportTopologyReply) Reset()         { *m = ExportTopologyReply{} }
+func (m *ExportTopologyReply) String() string { return proto.CompactTextString(m) }
+func (*ExportTopologyReply) ProtoMessage()    {}
+func (*ExportTopologyReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{38}
+}
+
+func (m *ExportTopologyReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportTopologyReply.Unmarshal(m, b)
+}
+func (m *ExportTopologyReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportTopologyReply.Marshal(b, m, deterministic)
+}
+func (m *ExportTopologyReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportTopologyReply.Merge(m, src)
+}
+func (m *ExportTopologyReply) XXX_Size() int {
+	return xxx_messageInfo_ExportTopologyReply.Size(m)
+}
+func (m *ExportTopologyReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportTopologyReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportTopologyReply proto.InternalMessageInfo
+
+func (m *ExportTopologyReply) GetTopology() []byte {
+	if m != nil {
+		return m.Topology
+	}
+	return nil
+}
+
+type GetLockStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLockStatusRequest) Reset()         { *m = GetLockStatusRequest{} }
+func (m *GetLockStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLockStatusRequest) ProtoMessage()    {}
+func (*GetLockStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{39}
+}
+
+func (m *GetLockStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLockStatusRequest.Unmarshal(m, b)
+}
+func (m *GetLockStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLockStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLockStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLockStatusRequest.Merge(m, src)
+}
+func (m *GetLockStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLockStatusRequest.Size(m)
+}
+func (m *GetLockStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLockStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLockStatusRequest proto.InternalMessageInfo
+
+type GetLockStatusReply struct {
+	Lock                 *ClusterLock `protobuf:"bytes,1,opt,name=lock,proto3" json:"lock,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetLockStatusReply) Reset()         { *m = GetLockStatusReply{} }
+func (m *GetLockStatusReply) String() string { return proto.CompactTextString(m) }
+func (*GetLockStatusReply) ProtoMessage()    {}
+func (*GetLockStatusReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{40}
+}
+
+func (m *GetLockStatusReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLockStatusReply.Unmarshal(m, b)
+}
+func (m *GetLockStatusReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLockStatusReply.Marshal(b, m, deterministic)
+}
+func (m *GetLockStatusReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLockStatusReply.Merge(m, src)
+}
+func (m *GetLockStatusReply) XXX_Size() int {
+	return xxx_messageInfo_GetLockStatusReply.Size(m)
+}
+func (m *GetLockStatusReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLockStatusReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLockStatusReply proto.InternalMessageInfo
+
+func (m *GetLockStatusReply) GetLock() *ClusterLock {
+	if m != nil {
+		return m.Lock
+	}
+	return nil
+}
+
+type BreakLockRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BreakLockRequest) Reset()         { *m = BreakLockRequest{} }
+func (m *BreakLockRequest) String() string { return proto.CompactTextString(m) }
+func (*BreakLockRequest) ProtoMessage()    {}
+func (*BreakLockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{41}
+}
+
+func (m *BreakLockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BreakLockRequest.Unmarshal(m, b)
+}
+func (m *BreakLockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BreakLockRequest.Marshal(b, m, deterministic)
+}
+func (m *BreakLockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BreakLockRequest.Merge(m, src)
+}
+func (m *BreakLockRequest) XXX_Size() int {
+	return xxx_messageInfo_BreakLockRequest.Size(m)
+}
+func (m *BreakLockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BreakLockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BreakLockRequest proto.InternalMessageInfo
+
+type BreakLockReply struct {
+	Lock                 *ClusterLock `protobuf:"bytes,1,opt,name=lock,proto3" json:"lock,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BreakLockReply) Reset()         { *m = BreakLockReply{} }
+func (m *BreakLockReply) String() string { return proto.CompactTextString(m) }
+func (*BreakLockReply) ProtoMessage()    {}
+func (*BreakLockReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{42}
+}
+
+func (m *BreakLockReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BreakLockReply.Unmarshal(m, b)
+}
+func (m *BreakLockReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BreakLockReply.Marshal(b, m, deterministic)
+}
+func (m *BreakLockReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BreakLockReply.Merge(m, src)
+}
+func (m *BreakLockReply) XXX_Size() int {
+	return xxx_messageInfo_BreakLockReply.Size(m)
+}
+func (m *BreakLockReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_BreakLockReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BreakLockReply proto.InternalMessageInfo
+
+func (m *BreakLockReply) GetLock() *ClusterLock {
+	if m != nil {
+		return m.Lock
+	}
+	return nil
+}
+
+type ClusterLock struct {
+	Holder               string   `protobuf:"bytes,1,opt,name=holder,proto3" json:"holder,omitempty"`
+	Operation            string   `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	OperationId          string   `protobuf:"bytes,3,opt,name=operationId,proto3" json:"operationId,omitempty"`
+	StartTime            int64    `protobuf:"varint,4,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	ClaimedBy            string   `protobuf:"bytes,5,opt,name=claimedBy,proto3" json:"claimedBy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClusterLock) Reset()         { *m = ClusterLock{} }
+func (m *ClusterLock) String() string { return proto.CompactTextString(m) }
+func (*ClusterLock) ProtoMessage()    {}
+func (*ClusterLock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{43}
+}
+
+func (m *ClusterLock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterLock.Unmarshal(m, b)
+}
+func (m *ClusterLock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterLock.Marshal(b, m, deterministic)
+}
+func (m *ClusterLock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterLock.Merge(m, src)
+}
+func (m *ClusterLock) XXX_Size() int {
+	return xxx_messageInfo_ClusterLock.Size(m)
+}
+func (m *ClusterLock) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterLock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterLock proto.InternalMessageInfo
+
+func (m *ClusterLock) GetHolder() string {
+	if m != nil {
+		return m.Holder
+	}
+	return ""
+}
+
+func (m *ClusterLock) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *ClusterLock) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *ClusterLock) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *ClusterLock) GetClaimedBy() string {
+	if m != nil {
+		return m.ClaimedBy
+	}
+	return ""
+}
+
+// failures of the requests the hub made to the agents, attached to the status of the failed RPCs
+type HostErrors struct {
+	Errors               []*HostError `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *HostErrors) Reset()         { *m = HostErrors{} }
+func (m *HostErrors) String() string { return proto.CompactTextString(m) }
+func (*HostErrors) ProtoMessage()    {}
+func (*HostErrors) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{44}
+}
+
+func (m *HostErrors) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HostErrors.Unmarshal(m, b)
+}
+func (m *HostErrors) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HostErrors.Marshal(b, m, deterministic)
+}
+func (m *HostErrors) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostErrors.Merge(m, src)
+}
+func (m *HostErrors) XXX_Size() int {
+	return xxx_messageInfo_HostErrors.Size(m)
+}
+func (m *HostErrors) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostErrors.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HostErrors proto.InternalMessageInfo
+
+func (m *HostErrors) GetErrors() []*HostError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+type HostError struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Segment              string   `protobuf:"bytes,2,opt,name=segment,proto3" json:"segment,omitempty"`
+	Rpc                  string   `protobuf:"bytes,3,opt,name=rpc,proto3" json:"rpc,omitempty"`
+	Code                 int32    `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	Message              string   `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HostError) Reset()         { *m = HostError{} }
+func (m *HostError) String() string { return proto.CompactTextString(m) }
+func (*HostError) ProtoMessage()    {}
+func (*HostError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{45}
+}
+
+func (m *HostError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HostError.Unmarshal(m, b)
+}
+func (m *HostError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HostError.Marshal(b, m, deterministic)
+}
+func (m *HostError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HostError.Merge(m, src)
+}
+func (m *HostError) XXX_Size() int {
+	return xxx_messageInfo_HostError.Size(m)
+}
+func (m *HostError) XXX_DiscardUnknown() {
+	xxx_messageInfo_HostError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HostError proto.InternalMessageInfo
+
+func (m *HostError) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *HostError) GetSegment() string {
+	if m != nil {
+		return m.Segment
+	}
+	return ""
+}
+
+func (m *HostError) GetRpc() string {
+	if m != nil {
+		return m.Rpc
+	}
+	return ""
+}
+
+func (m *HostError) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *HostError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type Operation struct {
+	Id                   string         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Method               string         `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	State                OperationState `protobuf:"varint,3,opt,name=state,proto3,enum=idl.OperationState" json:"state,omitempty"`
+	StartTime            int64          `protobuf:"varint,4,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime              int64          `protobuf:"varint,5,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	Error                string         `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Operation) Reset()         { *m = Operation{} }
+func (m *Operation) String() string { return proto.CompactTextString(m) }
+func (*Operation) ProtoMessage()    {}
+func (*Operation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{46}
+}
+
+func (m *Operation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Operation.Unmarshal(m, b)
+}
+func (m *Operation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Operation.Marshal(b, m, deterministic)
+}
+func (m *Operation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Operation.Merge(m, src)
+}
+func (m *Operation) XXX_Size() int {
+	return xxx_messageInfo_Operation.Size(m)
+}
+func (m *Operation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Operation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Operation proto.InternalMessageInfo
+
+func (m *Operation) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Operation) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *Operation) GetState() OperationState {
+	if m != nil {
+		return m.State
+	}
+	return OperationState_RUNNING
+}
+
+func (m *Operation) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *Operation) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+func (m *Operation) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ProgressMessage struct {
+	Label                string   `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Total                int32    `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Stage                string   `protobuf:"bytes,5,opt,name=stage,proto3" json:"stage,omitempty"`
+	Current              int32    `protobuf:"varint,6,opt,name=current,proto3" json:"current,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProgressMessage) Reset()         { *m = ProgressMessage{} }
+func (m *ProgressMessage) String() string { return proto.CompactTextString(m) }
+func (*ProgressMessage) ProtoMessage()    {}
+func (*ProgressMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{47}
+}
+
+func (m *ProgressMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProgressMessage.Unmarshal(m, b)
+}
+func (m *ProgressMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProgressMessage.Marshal(b, m, deterministic)
+}
+func (m *ProgressMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProgressMessage.Merge(m, src)
+}
+func (m *ProgressMessage) XXX_Size() int {
+	return xxx_messageInfo_ProgressMessage.Size(m)
+}
+func (m *ProgressMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProgressMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProgressMessage proto.InternalMessageInfo
+
+func (m *ProgressMessage) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *ProgressMessage) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *ProgressMessage) GetStage() string {
+	if m != nil {
+		return m.Stage
+	}
+	return ""
+}
+
+func (m *ProgressMessage) GetCurrent() int32 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+// a stage of an operation, like creating the primary segments, which started or ended
+type StageEvent struct {
+	Id                   string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	State                StageState `protobuf:"varint,3,opt,name=state,proto3,enum=idl.StageState" json:"state,omitempty"`
+	Error                string     `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Timestamp            int64      `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *StageEvent) Reset()         { *m = StageEvent{} }
+func (m *StageEvent) String() string { return proto.CompactTextString(m) }
+func (*StageEvent) ProtoMessage()    {}
+func (*StageEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{48}
+}
+
+func (m *StageEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StageEvent.Unmarshal(m, b)
+}
+func (m *StageEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StageEvent.Marshal(b, m, deterministic)
+}
+func (m *StageEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StageEvent.Merge(m, src)
+}
+func (m *StageEvent) XXX_Size() int {
+	return xxx_messageInfo_StageEvent.Size(m)
+}
+func (m *StageEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_StageEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StageEvent proto.InternalMessageInfo
+
+func (m *StageEvent) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *StageEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StageEvent) GetState() StageState {
+	if m != nil {
+		return m.State
+	}
+	return StageState_STAGE_STARTED
 }
 
-func (m *HubReply) GetStdoutMsg() string {
-	if x, ok := m.GetMessage().(*HubReply_StdoutMsg); ok {
-		return x.StdoutMsg
+func (m *StageEvent) GetError() string {
+	if m != nil {
+		return m.Error
 	}
 	return ""
 }
 
-func (m *HubReply) GetProgressMsg() *ProgressMessage {
-	if x, ok := m.GetMessage().(*HubReply_ProgressMsg); ok {
-		return x.ProgressMsg
+func (m *StageEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
 	}
-	return nil
+	return 0
 }
 
-// XXX_OneofWrappers is for the internal use of the proto package.
-func (*HubReply) XXX_OneofWrappers() []interface{} {
-	return []interface{}{
-		(*HubReply_LogMsg)(nil),
-		(*HubReply_StdoutMsg)(nil),
-		(*HubReply_ProgressMsg)(nil),
+// a segment of the cluster which changed state during an operation
+type SegmentEvent struct {
+	Dbid                 int32        `protobuf:"varint,1,opt,name=dbid,proto3" json:"dbid,omitempty"`
+	Contentid            int32        `protobuf:"varint,2,opt,name=contentid,proto3" json:"contentid,omitempty"`
+	Host                 string       `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+	DataDirectory        string       `protobuf:"bytes,4,opt,name=dataDirectory,proto3" json:"dataDirectory,omitempty"`
+	State                SegmentState `protobuf:"varint,5,opt,name=state,proto3,enum=idl.SegmentState" json:"state,omitempty"`
+	Error                string       `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Timestamp            int64        `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SegmentEvent) Reset()         { *m = SegmentEvent{} }
+func (m *SegmentEvent) String() string { return proto.CompactTextString(m) }
+func (*SegmentEvent) ProtoMessage()    {}
+func (*SegmentEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{49}
+}
+
+func (m *SegmentEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentEvent.Unmarshal(m, b)
+}
+func (m *SegmentEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentEvent.Marshal(b, m, deterministic)
+}
+func (m *SegmentEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentEvent.Merge(m, src)
+}
+func (m *SegmentEvent) XXX_Size() int {
+	return xxx_messageInfo_SegmentEvent.Size(m)
+}
+func (m *SegmentEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentEvent proto.InternalMessageInfo
+
+func (m *SegmentEvent) GetDbid() int32 {
+	if m != nil {
+		return m.Dbid
 	}
+	return 0
 }
 
-type LogMessage struct {
-	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	Level                LogLevel `protobuf:"varint,2,opt,name=level,proto3,enum=idl.LogLevel" json:"level,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *SegmentEvent) GetContentid() int32 {
+	if m != nil {
+		return m.Contentid
+	}
+	return 0
 }
 
-func (m *LogMessage) Reset()         { *m = LogMessage{} }
-func (m *LogMessage) String() string { return proto.CompactTextString(m) }
-func (*LogMessage) ProtoMessage()    {}
-func (*LogMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{16}
+func (m *SegmentEvent) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
 }
 
-func (m *LogMessage) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LogMessage.Unmarshal(m, b)
+func (m *SegmentEvent) GetDataDirectory() string {
+	if m != nil {
+		return m.DataDirectory
+	}
+	return ""
 }
-func (m *LogMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LogMessage.Marshal(b, m, deterministic)
+
+func (m *SegmentEvent) GetState() SegmentState {
+	if m != nil {
+		return m.State
+	}
+	return SegmentState_SEGMENT_CREATING
 }
-func (m *LogMessage) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LogMessage.Merge(m, src)
+
+func (m *SegmentEvent) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
 }
-func (m *LogMessage) XXX_Size() int {
-	return xxx_messageInfo_LogMessage.Size(m)
+
+func (m *SegmentEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
 }
-func (m *LogMessage) XXX_DiscardUnknown() {
-	xxx_messageInfo_LogMessage.DiscardUnknown(m)
+
+// final state of an operation, with the last event of each of its stages and segments
+type ResultSummary struct {
+	Success              bool            `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error                string          `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	DurationMillis       int64           `protobuf:"varint,3,opt,name=durationMillis,proto3" json:"durationMillis,omitempty"`
+	Stages               []*StageEvent   `protobuf:"bytes,4,rep,name=stages,proto3" json:"stages,omitempty"`
+	Segments             []*SegmentEvent `protobuf:"bytes,5,rep,name=segments,proto3" json:"segments,omitempty"`
+	Failures             []*HostError    `protobuf:"bytes,6,rep,name=failures,proto3" json:"failures,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-var xxx_messageInfo_LogMessage proto.InternalMessageInfo
+func (m *ResultSummary) Reset()         { *m = ResultSummary{} }
+func (m *ResultSummary) String() string { return proto.CompactTextString(m) }
+func (*ResultSummary) ProtoMessage()    {}
+func (*ResultSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{50}
+}
 
-func (m *LogMessage) GetMessage() string {
+func (m *ResultSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResultSummary.Unmarshal(m, b)
+}
+func (m *ResultSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResultSummary.Marshal(b, m, deterministic)
+}
+func (m *ResultSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResultSummary.Merge(m, src)
+}
+func (m *ResultSummary) XXX_Size() int {
+	return xxx_messageInfo_ResultSummary.Size(m)
+}
+func (m *ResultSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResultSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResultSummary proto.InternalMessageInfo
+
+func (m *ResultSummary) GetSuccess() bool {
 	if m != nil {
-		return m.Message
+		return m.Success
+	}
+	return false
+}
+
+func (m *ResultSummary) GetError() string {
+	if m != nil {
+		return m.Error
 	}
 	return ""
 }
 
-func (m *LogMessage) GetLevel() LogLevel {
+func (m *ResultSummary) GetDurationMillis() int64 {
 	if m != nil {
-		return m.Level
+		return m.DurationMillis
 	}
-	return LogLevel_FATAL
+	return 0
 }
 
-type ProgressMessage struct {
-	Label                string   `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
-	Total                int32    `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+func (m *ResultSummary) GetStages() []*StageEvent {
+	if m != nil {
+		return m.Stages
+	}
+	return nil
+}
+
+func (m *ResultSummary) GetSegments() []*SegmentEvent {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+func (m *ResultSummary) GetFailures() []*HostError {
+	if m != nil {
+		return m.Failures
+	}
+	return nil
+}
+
+// progress in bytes of a transfer, like the copy of a primary to its mirror, reported as it goes
+type TransferMessage struct {
+	Label                string   `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Current              int64    `protobuf:"varint,2,opt,name=current,proto3" json:"current,omitempty"`
+	Total                int64    `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Completed            bool     `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ProgressMessage) Reset()         { *m = ProgressMessage{} }
-func (m *ProgressMessage) String() string { return proto.CompactTextString(m) }
-func (*ProgressMessage) ProtoMessage()    {}
-func (*ProgressMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{17}
+func (m *TransferMessage) Reset()         { *m = TransferMessage{} }
+func (m *TransferMessage) String() string { return proto.CompactTextString(m) }
+func (*TransferMessage) ProtoMessage()    {}
+func (*TransferMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{51}
 }
 
-func (m *ProgressMessage) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ProgressMessage.Unmarshal(m, b)
+func (m *TransferMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransferMessage.Unmarshal(m, b)
 }
-func (m *ProgressMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ProgressMessage.Marshal(b, m, deterministic)
+func (m *TransferMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransferMessage.Marshal(b, m, deterministic)
 }
-func (m *ProgressMessage) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ProgressMessage.Merge(m, src)
+func (m *TransferMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransferMessage.Merge(m, src)
 }
-func (m *ProgressMessage) XXX_Size() int {
-	return xxx_messageInfo_ProgressMessage.Size(m)
+func (m *TransferMessage) XXX_Size() int {
+	return xxx_messageInfo_TransferMessage.Size(m)
 }
-func (m *ProgressMessage) XXX_DiscardUnknown() {
-	xxx_messageInfo_ProgressMessage.DiscardUnknown(m)
+func (m *TransferMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransferMessage.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ProgressMessage proto.InternalMessageInfo
+var xxx_messageInfo_TransferMessage proto.InternalMessageInfo
 
-func (m *ProgressMessage) GetLabel() string {
+func (m *TransferMessage) GetLabel() string {
 	if m != nil {
 		return m.Label
 	}
 	return ""
 }
 
-func (m *ProgressMessage) GetTotal() int32 {
+func (m *TransferMessage) GetCurrent() int64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+func (m *TransferMessage) GetTotal() int64 {
 	if m != nil {
 		return m.Total
 	}
 	return 0
 }
 
+func (m *TransferMessage) GetCompleted() bool {
+	if m != nil {
+		return m.Completed
+	}
+	return false
+}
+
 type GpArray struct {
 	Coordinator          *Segment       `protobuf:"bytes,1,opt,name=Coordinator,proto3" json:"Coordinator,omitempty"`
 	SegmentArray         []*SegmentPair `protobuf:"bytes,2,rep,name=SegmentArray,proto3" json:"SegmentArray,omitempty"`
@@ -837,7 +3020,7 @@ func (m *GpArray) Reset()         { *m = GpArray{} }
 func (m *GpArray) String() string { return proto.CompactTextString(m) }
 func (*GpArray) ProtoMessage()    {}
 func (*GpArray) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{18}
+	return fileDescriptor_b3103f8d3056b01c, []int{52}
 }
 
 func (m *GpArray) XXX_Unmarshal(b []byte) error {
@@ -888,7 +3071,7 @@ func (m *Segment) Reset()         { *m = Segment{} }
 func (m *Segment) String() string { return proto.CompactTextString(m) }
 func (*Segment) ProtoMessage()    {}
 func (*Segment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{19}
+	return fileDescriptor_b3103f8d3056b01c, []int{53}
 }
 
 func (m *Segment) XXX_Unmarshal(b []byte) error {
@@ -963,7 +3146,7 @@ func (m *SegmentPair) Reset()         { *m = SegmentPair{} }
 func (m *SegmentPair) String() string { return proto.CompactTextString(m) }
 func (*SegmentPair) ProtoMessage()    {}
 func (*SegmentPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{20}
+	return fileDescriptor_b3103f8d3056b01c, []int{54}
 }
 
 func (m *SegmentPair) XXX_Unmarshal(b []byte) error {
@@ -1017,7 +3200,7 @@ func (m *ClusterParams) Reset()         { *m = ClusterParams{} }
 func (m *ClusterParams) String() string { return proto.CompactTextString(m) }
 func (*ClusterParams) ProtoMessage()    {}
 func (*ClusterParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{21}
+	return fileDescriptor_b3103f8d3056b01c, []int{55}
 }
 
 func (m *ClusterParams) XXX_Unmarshal(b []byte) error {
@@ -1118,7 +3301,7 @@ func (m *Locale) Reset()         { *m = Locale{} }
 func (m *Locale) String() string { return proto.CompactTextString(m) }
 func (*Locale) ProtoMessage()    {}
 func (*Locale) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b3103f8d3056b01c, []int{22}
+	return fileDescriptor_b3103f8d3056b01c, []int{56}
 }
 
 func (m *Locale) XXX_Unmarshal(b []byte) error {
@@ -1189,11 +3372,20 @@ func (m *Locale) GetLcTime() string {
 }
 
 func init() {
+	proto.RegisterEnum("idl.OutputStream", OutputStream_name, OutputStream_value)
 	proto.RegisterEnum("idl.LogLevel", LogLevel_name, LogLevel_value)
+	proto.RegisterEnum("idl.OperationState", OperationState_name, OperationState_value)
+	proto.RegisterEnum("idl.StageState", StageState_name, StageState_value)
+	proto.RegisterEnum("idl.SegmentState", SegmentState_name, SegmentState_value)
+	proto.RegisterType((*AdoptClusterRequest)(nil), "idl.AdoptClusterRequest")
+	proto.RegisterType((*AdoptClusterReply)(nil), "idl.AdoptClusterReply")
 	proto.RegisterType((*AddMirrorsRequest)(nil), "idl.AddMirrorsRequest")
+	proto.RegisterMapType((map[string]string)(nil), "idl.AddMirrorsRequest.FailureDomainsEntry")
 	proto.RegisterType((*GetAllHostNamesRequest)(nil), "idl.GetAllHostNamesRequest")
 	proto.RegisterType((*GetAllHostNamesReply)(nil), "idl.GetAllHostNamesReply")
 	proto.RegisterMapType((map[string]string)(nil), "idl.GetAllHostNamesReply.HostNameMapEntry")
+	proto.RegisterType((*FindFreePortsRequest)(nil), "idl.FindFreePortsRequest")
+	proto.RegisterType((*FindFreePortsReply)(nil), "idl.FindFreePortsReply")
 	proto.RegisterType((*StopHubRequest)(nil), "idl.StopHubRequest")
 	proto.RegisterType((*StopHubReply)(nil), "idl.StopHubReply")
 	proto.RegisterType((*StartAgentsRequest)(nil), "idl.StartAgentsRequest")
@@ -1207,8 +3399,38 @@ func init() {
 	proto.RegisterType((*StopAgentsReply)(nil), "idl.StopAgentsReply")
 	proto.RegisterType((*MakeClusterRequest)(nil), "idl.MakeClusterRequest")
 	proto.RegisterType((*HubReply)(nil), "idl.HubReply")
+	proto.RegisterType((*OutputMessage)(nil), "idl.OutputMessage")
 	proto.RegisterType((*LogMessage)(nil), "idl.LogMessage")
+	proto.RegisterType((*ListOperationsRequest)(nil), "idl.ListOperationsRequest")
+	proto.RegisterType((*ListOperationsReply)(nil), "idl.ListOperationsReply")
+	proto.RegisterType((*AttachOperationRequest)(nil), "idl.AttachOperationRequest")
+	proto.RegisterType((*CancelOperationRequest)(nil), "idl.CancelOperationRequest")
+	proto.RegisterType((*CancelOperationReply)(nil), "idl.CancelOperationReply")
+	proto.RegisterType((*ListEventsRequest)(nil), "idl.ListEventsRequest")
+	proto.RegisterType((*ListEventsReply)(nil), "idl.ListEventsReply")
+	proto.RegisterType((*StoredEvent)(nil), "idl.StoredEvent")
+	proto.RegisterType((*ListTopologyRequest)(nil), "idl.ListTopologyRequest")
+	proto.RegisterType((*ListTopologyReply)(nil), "idl.ListTopologyReply")
+	proto.RegisterType((*TopologySnapshot)(nil), "idl.TopologySnapshot")
+	proto.RegisterType((*DiffTopologyRequest)(nil), "idl.DiffTopologyRequest")
+	proto.RegisterType((*DiffTopologyReply)(nil), "idl.DiffTopologyReply")
+	proto.RegisterType((*TopologyChange)(nil), "idl.TopologyChange")
+	proto.RegisterType((*RestoreTopologyRequest)(nil), "idl.RestoreTopologyRequest")
+	proto.RegisterType((*ExportTopologyRequest)(nil), "idl.ExportTopologyRequest")
+	proto.RegisterType((*ExportTopologyReply)(nil), "idl.ExportTopologyReply")
+	proto.RegisterType((*GetLockStatusRequest)(nil), "idl.GetLockStatusRequest")
+	proto.RegisterType((*GetLockStatusReply)(nil), "idl.GetLockStatusReply")
+	proto.RegisterType((*BreakLockRequest)(nil), "idl.BreakLockRequest")
+	proto.RegisterType((*BreakLockReply)(nil), "idl.BreakLockReply")
+	proto.RegisterType((*ClusterLock)(nil), "idl.ClusterLock")
+	proto.RegisterType((*HostErrors)(nil), "idl.HostErrors")
+	proto.RegisterType((*HostError)(nil), "idl.HostError")
+	proto.RegisterType((*Operation)(nil), "idl.Operation")
 	proto.RegisterType((*ProgressMessage)(nil), "idl.ProgressMessage")
+	proto.RegisterType((*StageEvent)(nil), "idl.StageEvent")
+	proto.RegisterType((*SegmentEvent)(nil), "idl.SegmentEvent")
+	proto.RegisterType((*ResultSummary)(nil), "idl.ResultSummary")
+	proto.RegisterType((*TransferMessage)(nil), "idl.TransferMessage")
 	proto.RegisterType((*GpArray)(nil), "idl.gpArray")
 	proto.RegisterType((*Segment)(nil), "idl.Segment")
 	proto.RegisterType((*SegmentPair)(nil), "idl.SegmentPair")
@@ -1222,86 +3444,194 @@ func init() {
 func init() { proto.RegisterFile("hub.proto", fileDescriptor_b3103f8d3056b01c) }
 
 var fileDescriptor_b3103f8d3056b01c = []byte{
-	// 1264 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0x4b, 0x6f, 0xdb, 0x46,
-	0x10, 0x36, 0x2d, 0xeb, 0xc1, 0x91, 0x1d, 0xcb, 0x13, 0x3f, 0x14, 0x35, 0x4d, 0x0d, 0x26, 0x2d,
-	0x9c, 0x1c, 0xd4, 0xc0, 0x0d, 0xd0, 0xb4, 0x68, 0x9b, 0xca, 0x72, 0x12, 0x05, 0xb1, 0x1d, 0x63,
-	0x9d, 0x22, 0x40, 0x7b, 0x30, 0x28, 0x72, 0x23, 0x13, 0x59, 0x71, 0xd9, 0xe5, 0xd2, 0x85, 0x7e,
-	0x43, 0x0e, 0xbd, 0xf7, 0xdc, 0x73, 0x2f, 0xfd, 0x29, 0xfd, 0x43, 0xc5, 0x3e, 0x28, 0x91, 0x92,
-	0x72, 0xf0, 0x8d, 0xfb, 0xcd, 0x63, 0x67, 0x66, 0xe7, 0x45, 0x70, 0xaf, 0xb2, 0x61, 0x37, 0x11,
-	0x5c, 0x72, 0xac, 0x44, 0x21, 0xf3, 0xfe, 0x74, 0x60, 0xab, 0x17, 0x86, 0xa7, 0x91, 0x10, 0x5c,
-	0xa4, 0x84, 0xfe, 0x9e, 0xd1, 0x54, 0x62, 0x17, 0xb0, 0xcf, 0xb9, 0x08, 0xa3, 0xd8, 0x97, 0x5c,
-	0x1c, 0xfb, 0xd2, 0x3f, 0x8e, 0x44, 0xdb, 0xd9, 0x77, 0x0e, 0x5c, 0xb2, 0x84, 0x82, 0x1e, 0xac,
-	0x0f, 0x86, 0xfe, 0x80, 0xa7, 0x32, 0xf6, 0xc7, 0x34, 0x6d, 0xaf, 0xee, 0x3b, 0x07, 0x0d, 0x52,
-	0xc2, 0xf0, 0x2b, 0xa8, 0x8f, 0xcd, 0x2d, 0xed, 0xca, 0x7e, 0xe5, 0xa0, 0x79, 0xb8, 0xde, 0x8d,
-	0x42, 0xd6, 0xbd, 0xa0, 0xa3, 0x31, 0x8d, 0x25, 0xc9, 0x89, 0xde, 0x13, 0xd8, 0x7d, 0x49, 0x65,
-	0x8f, 0x31, 0x25, 0x7a, 0xa6, 0x44, 0x73, 0xab, 0x3a, 0xd0, 0xb8, 0xe2, 0xa9, 0x3c, 0x89, 0x52,
-	0xd9, 0x76, 0xf6, 0x2b, 0x07, 0x2e, 0x99, 0x9e, 0xbd, 0xbf, 0x1d, 0xd8, 0x5e, 0x10, 0x4b, 0xd8,
-	0x04, 0x4f, 0xa0, 0x79, 0x65, 0x91, 0x53, 0x3f, 0xd1, 0x72, 0xcd, 0xc3, 0x47, 0xfa, 0xea, 0x65,
-	0xfc, 0xdd, 0xc1, 0x8c, 0xf9, 0x79, 0x2c, 0xc5, 0x84, 0x14, 0xc5, 0x3b, 0x3f, 0x41, 0x6b, 0x9e,
-	0x01, 0x5b, 0x50, 0xf9, 0x40, 0x27, 0x36, 0x3a, 0xea, 0x13, 0xb7, 0xa1, 0x7a, 0xed, 0xb3, 0x8c,
-	0xea, 0x38, 0xb8, 0xc4, 0x1c, 0xbe, 0x5f, 0x7d, 0xea, 0x78, 0x2d, 0xb8, 0x75, 0x21, 0x79, 0x32,
-	0xc8, 0x86, 0xd6, 0x29, 0xef, 0x16, 0xac, 0x4f, 0x91, 0x84, 0x4d, 0xbc, 0x6d, 0xc0, 0x0b, 0xe9,
-	0x0b, 0xd9, 0x1b, 0xd1, 0x58, 0xe6, 0xae, 0x7b, 0x08, 0xad, 0x12, 0xaa, 0x38, 0x77, 0xe0, 0xf6,
-	0x85, 0xf4, 0x65, 0x96, 0x96, 0x59, 0xef, 0xc0, 0x5e, 0x9f, 0x51, 0x3f, 0x7e, 0x15, 0x47, 0xb2,
-	0xcf, 0xb2, 0x54, 0x52, 0x91, 0x93, 0xf6, 0x60, 0x67, 0x91, 0xa4, 0x54, 0x51, 0xd8, 0xb8, 0xa0,
-	0xe2, 0x3a, 0x0a, 0xa8, 0xd1, 0x88, 0x08, 0x6b, 0xca, 0x6d, 0xeb, 0x94, 0xfe, 0xc6, 0x5d, 0xa8,
-	0xa5, 0x9a, 0x6a, 0xdd, 0xb2, 0x27, 0x85, 0x67, 0x89, 0x8c, 0xc6, 0xb4, 0x5d, 0x31, 0xb8, 0x39,
-	0xa9, 0xb8, 0x24, 0x51, 0xd8, 0x5e, 0xdb, 0x77, 0x0e, 0x36, 0x88, 0xfa, 0xf4, 0xfa, 0xb0, 0x55,
-	0xb6, 0x58, 0x3d, 0x50, 0x17, 0x1a, 0x46, 0x11, 0x4d, 0xed, 0xeb, 0xa0, 0x4d, 0x8c, 0x82, 0x41,
-	0x64, 0xca, 0xe3, 0xdd, 0x56, 0x4a, 0x78, 0x52, 0x76, 0x7a, 0x0b, 0x36, 0x8b, 0xa0, 0xf2, 0xe9,
-	0x1f, 0x07, 0xf0, 0xd4, 0xff, 0x40, 0xcb, 0x31, 0x50, 0x69, 0x38, 0x4a, 0x7a, 0x42, 0xf8, 0xe6,
-	0xc5, 0xf2, 0x34, 0xb4, 0x18, 0xc9, 0x89, 0xf8, 0x14, 0x36, 0x02, 0x23, 0x79, 0xee, 0x0b, 0x7f,
-	0x6c, 0x9c, 0xce, 0x6d, 0xeb, 0x17, 0x29, 0xa4, 0xcc, 0x88, 0x77, 0xc1, 0x7d, 0xcf, 0x45, 0x40,
-	0x5f, 0x30, 0x7f, 0xa4, 0x43, 0xd2, 0x20, 0x33, 0x00, 0xdb, 0x50, 0xbf, 0xa6, 0x62, 0xc8, 0x53,
-	0xaa, 0x23, 0xd3, 0x20, 0xf9, 0xd1, 0xfb, 0xcb, 0x81, 0x46, 0x9e, 0x06, 0xf8, 0x10, 0x6a, 0x8c,
-	0x8f, 0x4e, 0xd3, 0x91, 0xb5, 0x72, 0x53, 0xdf, 0x7b, 0xc2, 0x47, 0xa7, 0x34, 0x4d, 0xfd, 0x11,
-	0x1d, 0xac, 0x10, 0xcb, 0x80, 0xf7, 0xc0, 0x4d, 0x65, 0xc8, 0x33, 0xa9, 0xb8, 0xf5, 0xd3, 0x0c,
-	0x56, 0xc8, 0x0c, 0xc2, 0xa7, 0xd0, 0x4c, 0x04, 0x1f, 0x09, 0x9a, 0xa6, 0x8a, 0xa3, 0xa2, 0xf5,
-	0x6d, 0x6b, 0x7d, 0xe7, 0x39, 0x3e, 0x55, 0x5a, 0x64, 0x3d, 0x72, 0xa1, 0x3e, 0x36, 0x14, 0xef,
-	0x35, 0xc0, 0xec, 0x72, 0xe5, 0x84, 0x25, 0xd8, 0x0c, 0xc9, 0x8f, 0x78, 0x1f, 0xaa, 0x8c, 0x5e,
-	0x53, 0xa6, 0x0d, 0xb9, 0x75, 0xb8, 0xa1, 0xaf, 0x61, 0x7c, 0x74, 0xa2, 0x40, 0x62, 0x68, 0xde,
-	0x8f, 0xb0, 0x39, 0x77, 0xb3, 0x2a, 0x19, 0xe6, 0x0f, 0xad, 0x9c, 0x4b, 0xcc, 0x41, 0xa1, 0x92,
-	0x4b, 0x9f, 0xe9, 0x50, 0x55, 0x89, 0x39, 0x78, 0x7c, 0xfa, 0x84, 0xd8, 0x85, 0x66, 0xa1, 0x1d,
-	0x95, 0x5e, 0x34, 0x6f, 0x2c, 0x45, 0x06, 0x7c, 0x02, 0xeb, 0x16, 0x37, 0x29, 0xb0, 0xaa, 0x13,
-	0xae, 0x55, 0x14, 0x38, 0xf7, 0x23, 0x41, 0x4a, 0x5c, 0xde, 0xbf, 0x0e, 0xd4, 0x2d, 0xa0, 0x2a,
-	0x23, 0xe1, 0xc2, 0x54, 0x46, 0x95, 0xe8, 0x6f, 0x7c, 0x00, 0x1b, 0xa1, 0xe9, 0x84, 0x34, 0x90,
-	0x5c, 0x4c, 0xac, 0x13, 0x65, 0x30, 0x6f, 0x5f, 0xaa, 0x77, 0xd8, 0x4a, 0x99, 0x9e, 0x71, 0xdf,
-	0x74, 0xa9, 0x5e, 0x18, 0xaa, 0xa0, 0x68, 0x77, 0x5d, 0x52, 0x84, 0x54, 0x56, 0x05, 0x3c, 0x96,
-	0x34, 0x96, 0x51, 0xd8, 0xae, 0xea, 0xcb, 0x67, 0x80, 0xb2, 0x2a, 0x1c, 0x46, 0x61, 0xbb, 0x66,
-	0xac, 0x52, 0xdf, 0xde, 0x6f, 0xd0, 0x2c, 0xb8, 0xa4, 0x12, 0x3f, 0x11, 0xd1, 0xd8, 0x17, 0x93,
-	0xa5, 0x61, 0xca, 0x89, 0xf8, 0x00, 0x6a, 0xa6, 0x15, 0xdb, 0x8c, 0x2f, 0xb3, 0x59, 0x9a, 0xf7,
-	0xb1, 0x0a, 0x1b, 0xa5, 0x2a, 0xc0, 0x77, 0xb0, 0x55, 0x88, 0x74, 0x9f, 0xc7, 0xef, 0xa3, 0x91,
-	0x2d, 0xe8, 0x87, 0x8b, 0x45, 0xd3, 0x5d, 0xe0, 0x35, 0xdd, 0x76, 0x51, 0x07, 0xbe, 0x56, 0xcd,
-	0x49, 0xdf, 0x6e, 0x95, 0x9a, 0x47, 0xfb, 0x72, 0x89, 0xd2, 0x12, 0x9f, 0x51, 0x58, 0x96, 0xc5,
-	0x01, 0xac, 0xf7, 0xf9, 0x78, 0xcc, 0x63, 0xab, 0xcb, 0x8c, 0xa2, 0x07, 0x4b, 0x0d, 0x9c, 0xb1,
-	0x19, 0x55, 0x25, 0x49, 0xbc, 0xaf, 0x2a, 0x34, 0xf0, 0x99, 0xa9, 0xe3, 0xe6, 0x61, 0xd3, 0x56,
-	0xa8, 0x82, 0x88, 0x25, 0xa9, 0xc1, 0x78, 0x55, 0x1c, 0x8c, 0x55, 0x33, 0x18, 0x8b, 0x98, 0xca,
-	0x0b, 0x1a, 0x07, 0x3c, 0x8c, 0xe2, 0x91, 0x7e, 0x3f, 0x97, 0x4c, 0xcf, 0x78, 0x0f, 0x20, 0xcd,
-	0xce, 0xfd, 0x34, 0xfd, 0x83, 0x8b, 0xb0, 0x5d, 0xd7, 0xd4, 0x02, 0xa2, 0x7a, 0x6f, 0x38, 0xd4,
-	0x19, 0xd5, 0x30, 0xbd, 0xd7, 0x9c, 0xf2, 0x8c, 0xec, 0x5f, 0xd1, 0xe0, 0x43, 0x9a, 0x8d, 0xd3,
-	0xb6, 0xab, 0x2f, 0x2e, 0x83, 0x9d, 0x63, 0xd8, 0x5d, 0xfe, 0x0c, 0x37, 0x99, 0x69, 0x9d, 0x9f,
-	0x01, 0x17, 0xe3, 0x7e, 0x23, 0x0d, 0xcf, 0x54, 0xea, 0xcc, 0x45, 0xfb, 0x46, 0x63, 0xf5, 0x3f,
-	0x07, 0x6a, 0x26, 0xf2, 0xb8, 0x03, 0x35, 0x16, 0x5c, 0xfa, 0x8c, 0x59, 0xc9, 0x2a, 0x0b, 0x7a,
-	0x8c, 0xe1, 0xe7, 0x00, 0x2c, 0xb8, 0x0c, 0x38, 0x63, 0xbe, 0xcc, 0x15, 0xb8, 0x2c, 0xe8, 0x1b,
-	0x00, 0xef, 0x40, 0x43, 0x91, 0xe5, 0x24, 0xc9, 0x6b, 0xb3, 0xce, 0x82, 0xbe, 0x3a, 0xe2, 0x17,
-	0xd0, 0x64, 0xc1, 0xa5, 0xed, 0x6f, 0x79, 0x69, 0x02, 0x0b, 0x6c, 0xe7, 0x4a, 0x73, 0x06, 0x1e,
-	0x53, 0x5d, 0xfb, 0xd5, 0x29, 0x83, 0x45, 0xec, 0xdd, 0x71, 0x36, 0xa6, 0x22, 0x0a, 0xec, 0x13,
-	0xbb, 0x2c, 0x38, 0x33, 0x00, 0xee, 0x41, 0x9d, 0x05, 0x97, 0x7a, 0x80, 0x9a, 0x07, 0xae, 0xb1,
-	0xe0, 0x6d, 0x34, 0xa6, 0x8f, 0x8e, 0xa0, 0x91, 0x77, 0x4e, 0x74, 0xa1, 0xfa, 0xa2, 0xf7, 0xb6,
-	0x77, 0xd2, 0x5a, 0x51, 0x9f, 0xcf, 0x09, 0x79, 0x43, 0x5a, 0x0e, 0x36, 0xa1, 0xfe, 0xae, 0x47,
-	0xce, 0x5e, 0x9d, 0xbd, 0x6c, 0xad, 0x62, 0x03, 0xd6, 0x5e, 0x9d, 0xbd, 0x78, 0xd3, 0xaa, 0x28,
-	0x8e, 0xe3, 0xe7, 0x47, 0xbf, 0xbc, 0x6c, 0xad, 0x1d, 0x7e, 0x5c, 0x83, 0xca, 0x20, 0x1b, 0xe2,
-	0x63, 0x58, 0x53, 0x03, 0x12, 0x6f, 0x9b, 0x6a, 0x2e, 0xed, 0x20, 0x9d, 0xad, 0x32, 0xa8, 0xa6,
-	0xe7, 0x0a, 0x3e, 0x83, 0x66, 0x61, 0xe5, 0xc0, 0x3d, 0xcb, 0x33, 0xbf, 0x9a, 0x74, 0x76, 0x16,
-	0x09, 0x46, 0xc1, 0x91, 0xda, 0x6c, 0x66, 0xd3, 0x1e, 0xdb, 0x39, 0xe3, 0xfc, 0xca, 0xd2, 0xd9,
-	0x5d, 0x42, 0x31, 0x3a, 0x7e, 0x00, 0x98, 0xcd, 0x75, 0xdc, 0x9d, 0xda, 0x59, 0x96, 0xdf, 0x5e,
-	0xc0, 0x8d, 0xf4, 0x77, 0xd0, 0x2c, 0x6c, 0x00, 0xd6, 0x85, 0xc5, 0x9d, 0xa0, 0x63, 0xa6, 0xd4,
-	0xcc, 0xf7, 0xc7, 0x0e, 0x9e, 0x41, 0x6b, 0x7e, 0x55, 0xc2, 0xbb, 0xb6, 0x4b, 0x2c, 0x5d, 0xae,
-	0x3a, 0x9d, 0x4f, 0x50, 0x8d, 0x29, 0xdf, 0x02, 0xcc, 0xd6, 0x6c, 0xeb, 0xc8, 0xc2, 0xde, 0xbd,
-	0xcc, 0x90, 0xd7, 0xb0, 0x39, 0xb7, 0xa7, 0xe2, 0x67, 0xcb, 0xb7, 0x57, 0xa3, 0xe2, 0xce, 0x27,
-	0x57, 0x5b, 0x6f, 0xe5, 0xa8, 0xf1, 0x6b, 0xad, 0xdb, 0xfd, 0x3a, 0x0a, 0xd9, 0xb0, 0xa6, 0xff,
-	0x01, 0xbe, 0xf9, 0x3f, 0x00, 0x00, 0xff, 0xff, 0x72, 0xf7, 0xa6, 0x4e, 0x10, 0x0c, 0x00, 0x00,
+	// 2985 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x3a, 0xcd, 0x72, 0xe3, 0xc6,
+	0xd1, 0x02, 0xff, 0xd9, 0x14, 0x29, 0x6a, 0xf4, 0xb3, 0x5c, 0x7e, 0xfe, 0x1c, 0x15, 0xbc, 0x5e,
+	0xcb, 0xaa, 0x58, 0xd9, 0xc8, 0x5b, 0x89, 0xed, 0x4a, 0xc5, 0xa1, 0x28, 0x4a, 0x54, 0x59, 0xd2,
+	0x6e, 0x0d, 0xb9, 0xe5, 0xaa, 0xe4, 0xb0, 0x05, 0x01, 0x23, 0x0a, 0x25, 0x10, 0x60, 0xf0, 0xb3,
+	0x59, 0xfa, 0x15, 0x7c, 0x74, 0x5e, 0x20, 0xa9, 0x1c, 0x73, 0x4b, 0x55, 0xee, 0xa9, 0x4a, 0x2e,
+	0xb9, 0xe6, 0x90, 0x5b, 0x5e, 0x20, 0x2f, 0x91, 0xea, 0x99, 0x01, 0x30, 0x00, 0x21, 0xdb, 0xf2,
+	0x6d, 0xfa, 0x67, 0x7a, 0xa6, 0x7b, 0xba, 0x7b, 0x7a, 0x1a, 0x80, 0xe6, 0x6d, 0x74, 0x7d, 0xb8,
+	0xf0, 0xbd, 0xd0, 0x23, 0x65, 0xdb, 0x72, 0xf4, 0x11, 0x6c, 0x0d, 0x2c, 0x6f, 0x11, 0x0e, 0x9d,
+	0x28, 0x08, 0x99, 0x4f, 0xd9, 0x6f, 0x23, 0x16, 0x84, 0xe4, 0x10, 0xc8, 0xd0, 0xf3, 0x7c, 0xcb,
+	0x76, 0x8d, 0xd0, 0xf3, 0x4f, 0x8c, 0xd0, 0x38, 0xb1, 0xfd, 0x9e, 0xb6, 0xa7, 0xed, 0x37, 0x69,
+	0x01, 0x45, 0x9f, 0xc0, 0x66, 0x56, 0xcc, 0xc2, 0x59, 0x92, 0x77, 0xa0, 0x79, 0xeb, 0x05, 0xa1,
+	0x6b, 0xcc, 0x59, 0xd0, 0xd3, 0xf6, 0xca, 0xfb, 0x4d, 0x9a, 0x22, 0xc8, 0x1e, 0xb4, 0xdc, 0x68,
+	0x3e, 0x61, 0xb3, 0x39, 0x73, 0xc3, 0xa0, 0x57, 0xda, 0xd3, 0xf6, 0xab, 0x54, 0x45, 0xe9, 0xbf,
+	0xaf, 0xa0, 0x54, 0xeb, 0xd2, 0xf6, 0x7d, 0xcf, 0x0f, 0x7e, 0xe0, 0xd6, 0x88, 0x0e, 0xeb, 0xe3,
+	0x6b, 0x63, 0x9c, 0x6c, 0x04, 0x17, 0x6a, 0xd0, 0x0c, 0x8e, 0x3c, 0x85, 0xfa, 0x5c, 0xac, 0xd2,
+	0x2b, 0xef, 0x95, 0xf7, 0x5b, 0x47, 0xeb, 0x87, 0xb6, 0xe5, 0x1c, 0xca, 0x9d, 0xd0, 0x98, 0x48,
+	0xfa, 0xd0, 0x08, 0xbd, 0x85, 0xe7, 0x78, 0xb3, 0x65, 0xaf, 0xb2, 0xa7, 0xed, 0xaf, 0xd3, 0x04,
+	0x26, 0x4f, 0xa0, 0x2d, 0xd8, 0x6c, 0x77, 0x36, 0x5d, 0x2e, 0x58, 0xaf, 0xca, 0xb7, 0x94, 0x45,
+	0x92, 0xa7, 0xd0, 0x11, 0x88, 0x63, 0x23, 0x60, 0x2f, 0x3d, 0x3f, 0xec, 0xd5, 0xb8, 0xe2, 0x39,
+	0x2c, 0x79, 0x0e, 0x3b, 0x02, 0x23, 0xd5, 0x60, 0x66, 0xe8, 0xf9, 0x36, 0x0b, 0x7a, 0x75, 0x6e,
+	0xc7, 0x62, 0x22, 0x5a, 0xfc, 0xda, 0xf1, 0xcc, 0xbb, 0x89, 0xfd, 0x15, 0xeb, 0x35, 0xb8, 0xe0,
+	0x14, 0x41, 0x28, 0x74, 0x6e, 0x0c, 0xdb, 0x89, 0x7c, 0x76, 0xe2, 0xcd, 0x0d, 0xdb, 0x0d, 0x7a,
+	0x4d, 0xae, 0xec, 0x01, 0x57, 0x76, 0xc5, 0xd2, 0x87, 0xa7, 0x19, 0xe6, 0x91, 0x1b, 0xfa, 0x4b,
+	0x9a, 0x93, 0x80, 0xa7, 0xe1, 0xb3, 0x9b, 0x28, 0x60, 0xd6, 0xa9, 0xed, 0xb0, 0x60, 0x19, 0x84,
+	0x6c, 0x1e, 0xf4, 0x80, 0x6f, 0xb2, 0x80, 0xd2, 0x1f, 0xc0, 0x56, 0x81, 0x58, 0xd2, 0x85, 0xf2,
+	0x1d, 0x5b, 0xca, 0x53, 0xc4, 0x21, 0xd9, 0x86, 0xea, 0x1b, 0xc3, 0x89, 0x18, 0x3f, 0xaf, 0x26,
+	0x15, 0xc0, 0x67, 0xa5, 0x4f, 0x34, 0xfd, 0x39, 0xec, 0x9e, 0xb1, 0x70, 0xe0, 0x38, 0x78, 0x7e,
+	0x57, 0x78, 0x7e, 0xb1, 0x6b, 0xf4, 0xa1, 0x81, 0xfe, 0x75, 0x61, 0x07, 0xa1, 0xf4, 0xb7, 0x04,
+	0xd6, 0xff, 0xa4, 0xc1, 0xf6, 0xca, 0x34, 0xf4, 0xd2, 0x0b, 0x68, 0xdd, 0x4a, 0xcc, 0xa5, 0xb1,
+	0xe8, 0x69, 0x8a, 0x49, 0x8a, 0xf8, 0x0f, 0xc7, 0x29, 0xb3, 0x30, 0x89, 0x3a, 0xbd, 0xff, 0x4b,
+	0xe8, 0xe6, 0x19, 0x1e, 0xa4, 0xdc, 0x1f, 0x35, 0xd8, 0x3e, 0xb5, 0x5d, 0xeb, 0xd4, 0x67, 0xdc,
+	0x11, 0xbe, 0x8f, 0x6e, 0x28, 0xce, 0xf4, 0x22, 0x37, 0x94, 0x41, 0x24, 0x00, 0xd2, 0x43, 0xa7,
+	0x76, 0xb9, 0x8f, 0x95, 0x39, 0x3e, 0x06, 0x39, 0xc5, 0x78, 0xcb, 0x29, 0x15, 0x49, 0x11, 0x20,
+	0x3a, 0x31, 0x7b, 0x6b, 0x3a, 0x91, 0xc5, 0x2c, 0xbe, 0x7a, 0xaf, 0xba, 0x57, 0xde, 0xaf, 0xd2,
+	0x2c, 0x52, 0x7f, 0x06, 0x24, 0xb7, 0x47, 0x34, 0x64, 0x1f, 0x1a, 0xd7, 0xb1, 0x53, 0x6b, 0x5c,
+	0x6c, 0x02, 0xeb, 0x5d, 0xe8, 0x4c, 0x42, 0x6f, 0x31, 0x8e, 0xae, 0xa5, 0x3e, 0x7a, 0x07, 0xd6,
+	0x13, 0xcc, 0xc2, 0x59, 0xea, 0xdb, 0x40, 0x26, 0xa1, 0xe1, 0x87, 0x83, 0x19, 0xc6, 0x7e, 0xcc,
+	0x45, 0xa0, 0x9b, 0xc1, 0x22, 0xe7, 0x0e, 0x6c, 0x4d, 0x42, 0x23, 0x8c, 0x82, 0x2c, 0xeb, 0x63,
+	0x78, 0x34, 0x74, 0x98, 0xe1, 0x9e, 0xbb, 0x76, 0x2e, 0x9b, 0xe9, 0x8f, 0x60, 0x67, 0x95, 0x84,
+	0xa2, 0xbe, 0xd1, 0xa0, 0x3d, 0x61, 0xfe, 0x1b, 0xdb, 0x64, 0x42, 0x24, 0x21, 0x50, 0x41, 0xb3,
+	0xca, 0xc3, 0xe2, 0x63, 0xb2, 0x0b, 0xb5, 0x80, 0x53, 0xe5, 0x71, 0x49, 0x08, 0xf1, 0xd1, 0x22,
+	0xb4, 0xe7, 0x8c, 0xdb, 0xb7, 0x49, 0x25, 0x84, 0xe7, 0xbd, 0xb0, 0x2d, 0x6e, 0xda, 0x36, 0xc5,
+	0x21, 0xf9, 0x31, 0x6c, 0x9a, 0xcc, 0x0f, 0xed, 0x1b, 0xdb, 0x34, 0x42, 0x36, 0x7a, 0xbb, 0xb0,
+	0xfd, 0x25, 0xcf, 0x0f, 0x65, 0xba, 0x4a, 0xd0, 0x23, 0xd8, 0xcc, 0x2a, 0x88, 0xd6, 0x3d, 0x84,
+	0x86, 0x58, 0x56, 0xe6, 0xd2, 0xd6, 0x11, 0x91, 0x39, 0x4a, 0xd9, 0x3e, 0x4d, 0x78, 0xc8, 0x33,
+	0x68, 0x45, 0xae, 0xcf, 0x0c, 0xf3, 0xd6, 0xb8, 0x76, 0xd0, 0xd1, 0x70, 0x4a, 0x87, 0x4f, 0x41,
+	0x07, 0x1d, 0x61, 0xa4, 0x53, 0x95, 0x45, 0xdf, 0xc2, 0x65, 0xbd, 0x45, 0xd6, 0xaa, 0x9b, 0xb0,
+	0xa1, 0x22, 0xd1, 0x68, 0xff, 0xd1, 0x80, 0x5c, 0x1a, 0x77, 0x2c, 0x77, 0x65, 0x3c, 0x85, 0xfa,
+	0x6c, 0x31, 0xf0, 0x7d, 0x43, 0x78, 0x7a, 0x9c, 0x43, 0x25, 0x8e, 0xc6, 0x44, 0xf2, 0x09, 0xb4,
+	0x4d, 0x31, 0xf3, 0xa5, 0xe1, 0x1b, 0x73, 0x61, 0xd4, 0x58, 0x9b, 0xa1, 0x4a, 0xa1, 0x59, 0x46,
+	0xcc, 0x6e, 0x37, 0x9e, 0x6f, 0xb2, 0x53, 0xc7, 0x98, 0x71, 0x93, 0x37, 0x68, 0x8a, 0x40, 0xa7,
+	0x7e, 0xc3, 0xfc, 0x6b, 0x2f, 0x60, 0xdc, 0xf2, 0x0d, 0x1a, 0x83, 0xf7, 0xe4, 0xa8, 0xea, 0x7d,
+	0x39, 0x4a, 0xff, 0x7b, 0x19, 0x1a, 0xb1, 0x5f, 0x92, 0x0f, 0xa1, 0xe6, 0x78, 0xb3, 0xcb, 0x60,
+	0x26, 0xb5, 0xda, 0xe0, 0xfb, 0xbc, 0xf0, 0x66, 0x97, 0x2c, 0x08, 0x8c, 0x19, 0x1b, 0xaf, 0x51,
+	0xc9, 0x40, 0xde, 0x85, 0x66, 0x10, 0x5a, 0x5e, 0x14, 0x22, 0x37, 0x77, 0x95, 0xf1, 0x1a, 0x4d,
+	0x51, 0xe4, 0x13, 0x68, 0x2d, 0x7c, 0x6f, 0xe6, 0xb3, 0x20, 0xb8, 0x0c, 0x84, 0x06, 0xad, 0xa3,
+	0x6d, 0x2e, 0xef, 0x65, 0x8c, 0x4f, 0x84, 0xaa, 0xac, 0x44, 0x87, 0x96, 0xb7, 0x60, 0xbe, 0x11,
+	0xda, 0x9e, 0x7b, 0x2e, 0x3c, 0x0b, 0x65, 0xab, 0x48, 0x94, 0x1e, 0xfa, 0x86, 0x1b, 0xdc, 0x30,
+	0x1f, 0xa5, 0x57, 0x15, 0xe9, 0xd3, 0x18, 0x9f, 0x4a, 0x57, 0x58, 0xc9, 0x11, 0x34, 0xbd, 0x28,
+	0x5c, 0x88, 0x7d, 0xd7, 0x94, 0xd3, 0x78, 0x21, 0xb0, 0xc9, 0xac, 0x94, 0x8d, 0x7c, 0xc4, 0xdd,
+	0x71, 0xc6, 0x70, 0x4a, 0x5d, 0x31, 0xcc, 0x04, 0x91, 0xa3, 0x37, 0xcc, 0x0d, 0xc7, 0x6b, 0x34,
+	0x61, 0x21, 0x1f, 0x03, 0x04, 0xe2, 0x32, 0xc5, 0x09, 0x0d, 0x3e, 0x61, 0x53, 0xbd, 0x63, 0xe3,
+	0x29, 0x0a, 0x1b, 0xee, 0xcb, 0x67, 0x41, 0xe4, 0xf0, 0x39, 0x4d, 0x65, 0x5f, 0x94, 0x63, 0x27,
+	0xd1, 0x7c, 0x6e, 0xf8, 0x4b, 0xdc, 0x57, 0xc2, 0x76, 0xdc, 0x84, 0xfa, 0x5c, 0xec, 0x57, 0xff,
+	0xa7, 0x06, 0xed, 0x8c, 0x06, 0x85, 0xc1, 0xdd, 0x83, 0xba, 0x5c, 0x52, 0x46, 0x77, 0x0c, 0x22,
+	0xb7, 0x75, 0x6d, 0x5b, 0x32, 0x79, 0xf2, 0x31, 0x7a, 0x43, 0x10, 0xfa, 0xcc, 0x98, 0xf3, 0x33,
+	0xe8, 0x48, 0x1d, 0xc4, 0x2a, 0x13, 0x4e, 0xa0, 0x92, 0x01, 0xd3, 0x61, 0x80, 0xa1, 0xe1, 0x9a,
+	0xa2, 0x14, 0xa8, 0xd0, 0x04, 0x46, 0x4f, 0xc6, 0x4c, 0x11, 0x84, 0xc6, 0x7c, 0xc1, 0x2d, 0x5e,
+	0xa6, 0x29, 0x02, 0x17, 0x76, 0x6c, 0x97, 0x71, 0xbb, 0x36, 0x29, 0x1f, 0xeb, 0x5f, 0x00, 0xa4,
+	0x3e, 0xc7, 0x13, 0xb8, 0x18, 0x4a, 0x5d, 0x62, 0x90, 0xbc, 0x07, 0x55, 0x87, 0xbd, 0x61, 0x0e,
+	0x57, 0xa6, 0x73, 0xd4, 0xe6, 0xfb, 0x73, 0xbc, 0xd9, 0x05, 0x22, 0xa9, 0xa0, 0x61, 0x3e, 0xc4,
+	0x7b, 0xe3, 0x45, 0xec, 0x3d, 0x49, 0xb4, 0x8f, 0x60, 0x2b, 0x4f, 0x10, 0xb9, 0x07, 0x12, 0x4f,
+	0x8b, 0xb3, 0x8f, 0x48, 0x25, 0x09, 0x27, 0x55, 0x38, 0xf4, 0x7d, 0xd8, 0x1d, 0x84, 0xa1, 0x61,
+	0xde, 0xa6, 0x64, 0x99, 0x24, 0x3a, 0x50, 0xb2, 0x2d, 0xb9, 0xe7, 0x92, 0x6d, 0x21, 0xe7, 0xd0,
+	0x70, 0x4d, 0xe6, 0x7c, 0x27, 0xe7, 0x2e, 0x6c, 0xaf, 0x70, 0x62, 0x36, 0xfa, 0x9b, 0x06, 0x9b,
+	0xb8, 0x67, 0xee, 0x40, 0xc9, 0x6d, 0xb9, 0x0d, 0xd5, 0xc0, 0x46, 0xcb, 0x6b, 0xdc, 0xb8, 0x02,
+	0xc0, 0x92, 0x53, 0x0d, 0x23, 0x71, 0xde, 0x2a, 0x2a, 0xf1, 0x90, 0xb2, 0xe2, 0x21, 0x4f, 0xa1,
+	0x93, 0xea, 0xf6, 0xc2, 0x75, 0x96, 0x32, 0xbf, 0xe4, 0xb0, 0x78, 0xe0, 0x0b, 0x63, 0xc6, 0x78,
+	0xed, 0x55, 0x15, 0xf7, 0x5f, 0x0c, 0xe3, 0x81, 0xe3, 0x78, 0xea, 0xdd, 0x31, 0x97, 0x1f, 0x78,
+	0x93, 0xa6, 0x08, 0xdd, 0x80, 0x0d, 0x55, 0x05, 0x34, 0xf9, 0x3e, 0xd4, 0x18, 0x07, 0xa5, 0xb9,
+	0xbb, 0x32, 0xba, 0x3c, 0x9f, 0x59, 0x9c, 0x8f, 0x4a, 0x3a, 0x5e, 0xd9, 0x2e, 0x7b, 0x1b, 0xbe,
+	0x4c, 0xc4, 0x0b, 0xb5, 0xb2, 0x48, 0xfd, 0x9b, 0x32, 0xb4, 0x94, 0xd9, 0x59, 0x0f, 0xd4, 0xf2,
+	0x1e, 0xf8, 0xdd, 0x86, 0xda, 0x85, 0xda, 0x9c, 0x85, 0xb7, 0x9e, 0x15, 0xdf, 0x7d, 0x02, 0x42,
+	0x03, 0x86, 0x58, 0xfc, 0x56, 0x84, 0x01, 0x71, 0x8c, 0xbc, 0xa6, 0xe1, 0x38, 0xcc, 0x97, 0x25,
+	0xb1, 0x84, 0xf8, 0x21, 0x85, 0x46, 0xc8, 0xa4, 0x41, 0x04, 0x80, 0x58, 0xe1, 0xc1, 0xc2, 0xfd,
+	0x05, 0x20, 0x79, 0x67, 0xa2, 0xaa, 0x15, 0xbc, 0x4a, 0x40, 0x37, 0x8b, 0x03, 0x1a, 0x8a, 0x03,
+	0xba, 0xa5, 0x04, 0xb4, 0x12, 0x49, 0xeb, 0xd9, 0x48, 0xda, 0x86, 0x2a, 0xc3, 0x4b, 0xb2, 0xd7,
+	0x16, 0x2b, 0x72, 0x00, 0xf9, 0xcd, 0xc8, 0xf7, 0x51, 0x7a, 0x87, 0x5b, 0x2d, 0x06, 0x91, 0x3f,
+	0xf4, 0x42, 0xc3, 0xe9, 0x6d, 0x08, 0x97, 0xe3, 0x00, 0xda, 0xd9, 0x74, 0x0c, 0x7b, 0xce, 0xac,
+	0xe3, 0x65, 0xaf, 0x2b, 0x0e, 0x3e, 0x41, 0x60, 0x29, 0x83, 0x07, 0x3f, 0x95, 0x6f, 0x88, 0x38,
+	0x0c, 0xc7, 0xb0, 0x99, 0x45, 0xa3, 0x47, 0x7c, 0x0c, 0xcd, 0xc0, 0x35, 0x16, 0xc1, 0xad, 0x97,
+	0x38, 0xc5, 0x8e, 0xc8, 0xee, 0x92, 0x6d, 0x22, 0xa9, 0x34, 0xe5, 0xd3, 0xff, 0xaa, 0x41, 0x37,
+	0x4f, 0x97, 0x37, 0x65, 0x60, 0x7b, 0xae, 0xac, 0xd3, 0x62, 0x30, 0xeb, 0x15, 0xa5, 0xef, 0xf0,
+	0x8a, 0xf2, 0xb7, 0x79, 0x45, 0x25, 0xe3, 0x15, 0xdb, 0x50, 0x5d, 0xdc, 0x1a, 0x41, 0xfc, 0x26,
+	0x12, 0x80, 0xc8, 0x90, 0xf2, 0xf9, 0x27, 0x5e, 0x41, 0x09, 0xac, 0x7f, 0x0a, 0x5b, 0x27, 0xf6,
+	0xcd, 0x4d, 0xce, 0x32, 0x78, 0x84, 0x37, 0xbe, 0x37, 0x97, 0xfb, 0xe6, 0x63, 0xcc, 0x14, 0xa1,
+	0x27, 0x4b, 0xdf, 0x52, 0xe8, 0xe9, 0xc7, 0xb0, 0x99, 0x9d, 0x8a, 0xd6, 0xfb, 0x08, 0xea, 0xe6,
+	0xad, 0xe1, 0xce, 0x92, 0xea, 0x69, 0x2b, 0x63, 0xbb, 0x21, 0xa7, 0xd1, 0x98, 0x47, 0xff, 0x0a,
+	0x3a, 0x59, 0x52, 0xe2, 0x3c, 0x9a, 0xe2, 0x3c, 0x78, 0xb8, 0x9e, 0x1b, 0x32, 0x37, 0xb4, 0x2d,
+	0xb9, 0x81, 0x14, 0x81, 0x33, 0xee, 0x6c, 0x37, 0xb6, 0x13, 0x1f, 0x27, 0xfb, 0x97, 0xe1, 0xa1,
+	0xec, 0x5f, 0x58, 0x06, 0xf7, 0x7f, 0x04, 0xbb, 0x94, 0x05, 0x18, 0xab, 0x79, 0xed, 0xef, 0x3d,
+	0x38, 0xfd, 0xa7, 0xb0, 0x33, 0x7a, 0xbb, 0xf0, 0xfc, 0xf0, 0x21, 0x53, 0xb6, 0xf2, 0x53, 0x64,
+	0x15, 0x9f, 0x3c, 0x71, 0xb5, 0xec, 0x13, 0x17, 0x73, 0xf0, 0x19, 0x0b, 0x2f, 0xf0, 0x3d, 0x29,
+	0xca, 0x4d, 0xe9, 0xaf, 0x9f, 0x01, 0xc9, 0xe1, 0x51, 0xd2, 0x13, 0xa8, 0xe0, 0xd3, 0x53, 0xd6,
+	0x4d, 0x5d, 0xb5, 0xbe, 0x43, 0x56, 0xca, 0xa9, 0x58, 0xe1, 0x1f, 0xfb, 0xcc, 0xb8, 0xe3, 0x28,
+	0x29, 0xef, 0x67, 0xd0, 0x51, 0x70, 0xdf, 0x5f, 0xd6, 0x1f, 0x34, 0x68, 0x29, 0x58, 0x74, 0xc7,
+	0x5b, 0xcf, 0xb1, 0x58, 0xdc, 0x1e, 0x90, 0x10, 0x9e, 0x5b, 0xe2, 0xb5, 0x32, 0xb9, 0xa5, 0x88,
+	0xef, 0xe1, 0xe6, 0xef, 0x60, 0xa1, 0x67, 0xf8, 0xe1, 0x14, 0x6b, 0xff, 0x8a, 0x08, 0x93, 0x04,
+	0x91, 0x0d, 0xf9, 0x6a, 0x3e, 0xe4, 0x9f, 0x03, 0x24, 0xf5, 0x37, 0x36, 0x1e, 0x6a, 0x3c, 0xaf,
+	0x64, 0x6f, 0xd5, 0x84, 0x81, 0x4a, 0xaa, 0xbe, 0x84, 0x66, 0x82, 0x7c, 0x60, 0x19, 0xd3, 0x85,
+	0xb2, 0xbf, 0x30, 0xa5, 0x1a, 0x38, 0xc4, 0xf9, 0xa6, 0x67, 0x31, 0xf9, 0xf6, 0xe3, 0x63, 0x35,
+	0x0f, 0x56, 0x33, 0x79, 0x50, 0xff, 0xb3, 0x06, 0xcd, 0xe4, 0xce, 0xcd, 0x5f, 0xcb, 0x4a, 0xc4,
+	0x97, 0x32, 0x11, 0xff, 0x61, 0x9c, 0xdb, 0xcb, 0xbc, 0x0e, 0x11, 0xd1, 0x96, 0xd8, 0x10, 0xdd,
+	0x84, 0xc5, 0x09, 0xff, 0xdb, 0xad, 0xd9, 0x83, 0x3a, 0x73, 0x2d, 0x4e, 0x13, 0x0f, 0xa6, 0x18,
+	0x4c, 0x13, 0x74, 0x4d, 0x49, 0xd0, 0xfa, 0x1c, 0x36, 0x72, 0xc5, 0x34, 0x32, 0x3a, 0xc6, 0xb5,
+	0xac, 0x89, 0x9a, 0x54, 0x00, 0x69, 0xbe, 0x16, 0x66, 0x10, 0x40, 0x7a, 0xcf, 0x54, 0xd5, 0x7b,
+	0x46, 0xc9, 0xfa, 0x22, 0x51, 0xc5, 0xa0, 0xfe, 0xb5, 0x06, 0x90, 0xd6, 0xbc, 0x2b, 0xe6, 0x21,
+	0x50, 0xc1, 0x0e, 0x93, 0x5c, 0x99, 0x8f, 0xc9, 0xfb, 0x59, 0xd3, 0x88, 0xba, 0x99, 0xaf, 0x93,
+	0x31, 0x4b, 0xa2, 0x5e, 0x45, 0xbd, 0x7f, 0x32, 0x19, 0xba, 0x9a, 0xcb, 0xd0, 0xfa, 0xbf, 0x35,
+	0x58, 0x57, 0x0b, 0xea, 0x1f, 0x96, 0xb5, 0x56, 0x2a, 0xa0, 0x27, 0xd0, 0xb6, 0x94, 0x4e, 0xd3,
+	0x52, 0x6e, 0x29, 0x8b, 0x24, 0x1f, 0xc4, 0x7a, 0x55, 0x95, 0xd2, 0x58, 0x7a, 0x61, 0xb1, 0x66,
+	0xb5, 0x7b, 0x35, 0xab, 0xe7, 0x35, 0xfb, 0xaf, 0x06, 0xed, 0x4c, 0xd9, 0xcf, 0x3d, 0x3e, 0x32,
+	0x4d, 0x16, 0x04, 0x5c, 0xbb, 0x06, 0x8d, 0xc1, 0x54, 0x7e, 0x49, 0x95, 0xff, 0x14, 0x3a, 0x56,
+	0x24, 0xdc, 0xef, 0xd2, 0x76, 0x1c, 0x3b, 0xe0, 0x2a, 0x96, 0x69, 0x0e, 0x4b, 0x3e, 0xe0, 0xaf,
+	0x7d, 0xbc, 0x28, 0x2a, 0x3c, 0x24, 0xf3, 0xef, 0x1a, 0x2a, 0xc9, 0xfc, 0x09, 0x14, 0x5f, 0x5f,
+	0x55, 0xce, 0xba, 0xfa, 0xa2, 0x49, 0x6f, 0x34, 0x72, 0x00, 0x0d, 0xd9, 0x3b, 0xc3, 0xdb, 0xae,
+	0x28, 0xd8, 0x13, 0xba, 0x1e, 0xc1, 0x46, 0xee, 0xcd, 0x96, 0x3a, 0xb1, 0xa6, 0x3a, 0xb1, 0xe2,
+	0x98, 0xa5, 0x7b, 0xca, 0x91, 0x72, 0xbe, 0x1c, 0xf1, 0xe6, 0x0b, 0x87, 0x85, 0xcc, 0x92, 0x65,
+	0x6c, 0x8a, 0xd0, 0xbd, 0xe4, 0x09, 0x4f, 0x0e, 0xa1, 0xa5, 0xf4, 0x52, 0x33, 0x2f, 0x7a, 0xa9,
+	0x1f, 0x55, 0x19, 0xc8, 0xf3, 0xc4, 0xf1, 0xf8, 0x7c, 0xd9, 0x6f, 0xe8, 0xaa, 0x13, 0x5e, 0x1a,
+	0xb6, 0x4f, 0x33, 0x5c, 0xfa, 0x5f, 0x34, 0xa8, 0x4f, 0xd2, 0xea, 0x6c, 0x91, 0xb6, 0x8e, 0xf8,
+	0x78, 0xd5, 0xf1, 0x4a, 0x45, 0x8e, 0x27, 0x5b, 0x63, 0xd8, 0x73, 0x93, 0x6e, 0x9b, 0xc0, 0x98,
+	0xcc, 0x71, 0x3c, 0xb0, 0x2c, 0xcc, 0x08, 0xd2, 0x71, 0x55, 0x54, 0x36, 0x1c, 0xaa, 0x05, 0xe1,
+	0xc0, 0x03, 0xa8, 0x96, 0x06, 0x90, 0xfe, 0x1b, 0x68, 0x29, 0x2a, 0x61, 0xe3, 0x63, 0xe1, 0xdb,
+	0xe8, 0x93, 0x85, 0x66, 0x8a, 0x89, 0xe4, 0x09, 0xd4, 0x44, 0xd7, 0xb6, 0x57, 0x2a, 0x60, 0x93,
+	0x34, 0xfd, 0xeb, 0x2a, 0xb4, 0x33, 0x5d, 0x10, 0xf2, 0x25, 0x6c, 0x2a, 0x96, 0x1e, 0x7a, 0xee,
+	0x8d, 0x3d, 0x93, 0xd7, 0xc5, 0x87, 0xab, 0x4d, 0x93, 0xc3, 0x15, 0x5e, 0xd1, 0xa5, 0x5c, 0x95,
+	0x41, 0xbe, 0x80, 0xb6, 0x5c, 0x5d, 0x0a, 0x15, 0x87, 0xf6, 0x7e, 0x81, 0xd0, 0x0c, 0x9f, 0x10,
+	0x98, 0x9d, 0x4b, 0xc6, 0xb0, 0x3e, 0xf4, 0xe6, 0x73, 0xcf, 0x95, 0xb2, 0x44, 0x1f, 0xfd, 0x49,
+	0xe1, 0x06, 0x53, 0x36, 0x21, 0x2a, 0x33, 0x93, 0xbc, 0x87, 0x1d, 0x17, 0xd3, 0x70, 0xc4, 0x65,
+	0xd0, 0x3a, 0x6a, 0xc9, 0x8e, 0x0b, 0xa2, 0xa8, 0x24, 0x61, 0x57, 0xff, 0x56, 0xed, 0xea, 0x57,
+	0x45, 0x57, 0x5f, 0xc5, 0xa1, 0x5f, 0x30, 0xd7, 0xf4, 0x2c, 0xdb, 0x9d, 0xc9, 0x54, 0x93, 0xc0,
+	0xe4, 0x5d, 0x80, 0x20, 0x7a, 0x69, 0x04, 0xc1, 0xef, 0x3c, 0xdf, 0x92, 0x4f, 0x0d, 0x05, 0x83,
+	0xf7, 0x9a, 0x75, 0xcd, 0x3d, 0x4a, 0x3c, 0x38, 0x24, 0x14, 0x7b, 0xe4, 0xf0, 0x96, 0x99, 0x77,
+	0x41, 0x34, 0x0f, 0xf8, 0xd3, 0xa3, 0x41, 0xb3, 0xc8, 0xfe, 0x09, 0xec, 0x16, 0x1f, 0xc3, 0x43,
+	0x7a, 0xc1, 0xfd, 0x5f, 0x01, 0x59, 0xb5, 0xfb, 0x83, 0x24, 0x7c, 0x0e, 0x9b, 0xaa, 0x69, 0x1f,
+	0xde, 0x8e, 0xfe, 0x97, 0x06, 0x35, 0x61, 0x79, 0xb2, 0x03, 0x35, 0xc7, 0x7c, 0x6d, 0x38, 0x69,
+	0x06, 0x32, 0x07, 0x8e, 0x43, 0xfe, 0x1f, 0xc0, 0x31, 0x5f, 0x9b, 0x9e, 0xe3, 0x18, 0x61, 0x2c,
+	0xa0, 0xe9, 0x98, 0x43, 0x81, 0x20, 0x8f, 0xa1, 0x81, 0x64, 0xfe, 0x26, 0x14, 0xb1, 0x59, 0x77,
+	0xcc, 0x21, 0x82, 0xe4, 0x47, 0xd0, 0x72, 0xcc, 0xd7, 0xb2, 0xcc, 0x88, 0x43, 0x13, 0x1c, 0x53,
+	0x66, 0xbc, 0x20, 0x66, 0xf0, 0x5c, 0xc6, 0x63, 0xbf, 0x9a, 0x30, 0x48, 0x8c, 0x5c, 0xdb, 0x8d,
+	0xe6, 0xcc, 0xb7, 0xcd, 0xf8, 0x59, 0xed, 0x98, 0x57, 0x02, 0x41, 0x1e, 0x41, 0xdd, 0x31, 0x5f,
+	0xf3, 0x06, 0xad, 0x38, 0xe0, 0x9a, 0x63, 0x62, 0xe5, 0x70, 0xf0, 0x14, 0xd6, 0xd5, 0x96, 0x0d,
+	0x01, 0xa8, 0x4d, 0xa6, 0x27, 0x2f, 0x5e, 0x4d, 0xbb, 0x6b, 0x72, 0x3c, 0xa2, 0xb4, 0xab, 0x1d,
+	0x1c, 0x43, 0x23, 0x6e, 0x9d, 0x90, 0x26, 0x54, 0x4f, 0x07, 0xd3, 0xc1, 0x45, 0x77, 0x0d, 0x87,
+	0x23, 0x4a, 0x5f, 0xd0, 0xae, 0x46, 0x5a, 0x50, 0xff, 0x72, 0x40, 0xaf, 0xce, 0xaf, 0xce, 0xba,
+	0x25, 0xd2, 0x80, 0xca, 0xf9, 0xd5, 0xe9, 0x8b, 0x6e, 0x19, 0x39, 0x4e, 0x46, 0xc7, 0xaf, 0xce,
+	0xba, 0x95, 0x83, 0x33, 0xa5, 0x7b, 0xc0, 0x6f, 0x41, 0x9c, 0x43, 0x5f, 0x5d, 0xf1, 0x39, 0x6b,
+	0xa4, 0x0d, 0xcd, 0xc9, 0xab, 0xe1, 0x70, 0x34, 0x3a, 0x19, 0x9d, 0x74, 0x35, 0x5c, 0xfd, 0x74,
+	0x70, 0x7e, 0x31, 0x3a, 0xe9, 0x96, 0x90, 0x34, 0x1c, 0x5c, 0x0d, 0x47, 0x17, 0x08, 0x96, 0x0f,
+	0x46, 0x00, 0x69, 0x91, 0x40, 0x36, 0xa1, 0x3d, 0x99, 0x0e, 0xce, 0x46, 0xaf, 0x27, 0xd3, 0x01,
+	0x9d, 0x8e, 0x4e, 0xba, 0x6b, 0x84, 0x40, 0x47, 0xa0, 0x4e, 0xcf, 0xaf, 0xce, 0x27, 0x63, 0x2e,
+	0xaf, 0x0b, 0xeb, 0x12, 0x27, 0xa5, 0x1e, 0xbc, 0x85, 0x75, 0xf5, 0x4e, 0x26, 0xdb, 0xd0, 0x9d,
+	0x8c, 0xce, 0x2e, 0x47, 0x57, 0xd3, 0xd7, 0x43, 0x3a, 0x1a, 0x4c, 0xc5, 0xb6, 0xb6, 0x60, 0x23,
+	0x83, 0xe5, 0xc2, 0x14, 0x56, 0xbe, 0xaa, 0xd0, 0x5a, 0x61, 0x8d, 0xf7, 0x52, 0xe6, 0x7b, 0x91,
+	0x48, 0xb9, 0x72, 0xe5, 0xe8, 0x1f, 0x00, 0xe5, 0x71, 0x74, 0x4d, 0x9e, 0x41, 0x05, 0x5b, 0xca,
+	0x64, 0x2b, 0x6e, 0x69, 0x28, 0x9f, 0x05, 0xfa, 0x9b, 0x59, 0x24, 0x76, 0x78, 0xd6, 0xc8, 0xe7,
+	0xd0, 0x52, 0xbe, 0x02, 0x90, 0x47, 0x92, 0x27, 0xff, 0xb5, 0xa0, 0xbf, 0xb3, 0x4a, 0x10, 0x02,
+	0x8e, 0x61, 0x5d, 0xbc, 0x4c, 0xa4, 0x84, 0x5e, 0xcc, 0x98, 0xff, 0x8a, 0xd0, 0xdf, 0x2d, 0xa0,
+	0x08, 0x19, 0xbf, 0x00, 0x48, 0x3b, 0xe1, 0x64, 0x37, 0xd9, 0x67, 0x76, 0xfe, 0xf6, 0x0a, 0x5e,
+	0xcc, 0xfe, 0x14, 0x5a, 0x4a, 0xcf, 0x5c, 0xaa, 0xb0, 0xda, 0x45, 0xef, 0x8b, 0x86, 0x5d, 0xaa,
+	0xfb, 0x33, 0x8d, 0x5c, 0x41, 0x37, 0xff, 0xf5, 0x82, 0xbc, 0x23, 0xf3, 0x6a, 0xe1, 0xf7, 0x8e,
+	0x7e, 0xff, 0x1e, 0xaa, 0xd8, 0xca, 0xcf, 0x01, 0xd2, 0x6f, 0x7d, 0x52, 0x91, 0x95, 0x8f, 0x7f,
+	0x45, 0x1b, 0xf9, 0x02, 0x36, 0x72, 0x5f, 0xc4, 0xc8, 0xff, 0x15, 0x7f, 0x27, 0x13, 0x22, 0x1e,
+	0xdf, 0xfb, 0x11, 0x4d, 0x5f, 0x23, 0x23, 0x68, 0x67, 0xbe, 0x21, 0x11, 0xc1, 0x5d, 0xf4, 0xed,
+	0xab, 0xff, 0xa8, 0x88, 0x94, 0x9c, 0xac, 0xfa, 0xe1, 0x59, 0x9e, 0x6c, 0xc1, 0x27, 0xed, 0xfe,
+	0x6e, 0x01, 0x45, 0xc8, 0x18, 0x43, 0x27, 0xdb, 0xf5, 0x24, 0xc2, 0x80, 0x85, 0x3d, 0xd2, 0x7e,
+	0xaf, 0x90, 0x26, 0x24, 0x0d, 0x60, 0x23, 0xd7, 0xf8, 0x94, 0x16, 0x2a, 0x6e, 0x87, 0xde, 0x63,
+	0xe4, 0x5c, 0x9f, 0x53, 0x8a, 0x28, 0xee, 0x93, 0xf6, 0x1f, 0x17, 0x13, 0x13, 0x23, 0x67, 0x1e,
+	0xe6, 0x24, 0x39, 0x92, 0x95, 0x47, 0x7c, 0xff, 0x51, 0x11, 0x29, 0x76, 0xde, 0x66, 0xf2, 0x1e,
+	0x27, 0x22, 0xc8, 0xf2, 0x6f, 0xf6, 0xfe, 0x56, 0x1e, 0x9d, 0x44, 0x4d, 0xda, 0xda, 0x94, 0xce,
+	0xb6, 0xd2, 0xae, 0xed, 0x6f, 0xaf, 0xe0, 0x93, 0xd3, 0x55, 0x1b, 0x61, 0x24, 0xb5, 0x7d, 0xae,
+	0xcf, 0xd1, 0xdf, 0x2d, 0xa0, 0x24, 0x32, 0xd4, 0x76, 0x90, 0x94, 0x51, 0xd0, 0x5c, 0xea, 0xef,
+	0x16, 0x50, 0x92, 0x73, 0xcd, 0xb5, 0x64, 0xe4, 0xa1, 0x14, 0x37, 0x6a, 0x8a, 0xce, 0x75, 0x0c,
+	0x9d, 0x6c, 0xbb, 0x45, 0x3a, 0x59, 0x61, 0xdb, 0xa6, 0xdf, 0x2b, 0xa4, 0x71, 0x59, 0xc7, 0x8d,
+	0x5f, 0xd7, 0x0e, 0x0f, 0x7f, 0x62, 0x5b, 0xce, 0x75, 0x8d, 0xff, 0xc8, 0xf1, 0xf1, 0xff, 0x06,
+	0x00, 0x3a, 0x09, 0xed, 0x84, 0xd5, 0x21, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1324,6 +3654,18 @@ type HubClient interface {
 	CleanInitCluster(ctx context.Context, in *CleanInitClusterRequest, opts ...grpc.CallOption) (*CleanInitClusterReply, error)
 	AddMirrors(ctx context.Context, in *AddMirrorsRequest, opts ...grpc.CallOption) (Hub_AddMirrorsClient, error)
 	GetAllHostNames(ctx context.Context, in *GetAllHostNamesRequest, opts ...grpc.CallOption) (*GetAllHostNamesReply, error)
+	FindFreePorts(ctx context.Context, in *FindFreePortsRequest, opts ...grpc.CallOption) (*FindFreePortsReply, error)
+	AdoptCluster(ctx context.Context, in *AdoptClusterRequest, opts ...grpc.CallOption) (*AdoptClusterReply, error)
+	ListOperations(ctx context.Context, in *ListOperationsRequest, opts ...grpc.CallOption) (*ListOperationsReply, error)
+	AttachOperation(ctx context.Context, in *AttachOperationRequest, opts ...grpc.CallOption) (Hub_AttachOperationClient, error)
+	CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*CancelOperationReply, error)
+	GetLockStatus(ctx context.Context, in *GetLockStatusRequest, opts ...grpc.CallOption) (*GetLockStatusReply, error)
+	BreakLock(ctx context.Context, in *BreakLockRequest, opts ...grpc.CallOption) (*BreakLockReply, error)
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsReply, error)
+	ListTopology(ctx context.Context, in *ListTopologyRequest, opts ...grpc.CallOption) (*ListTopologyReply, error)
+	DiffTopology(ctx context.Context, in *DiffTopologyRequest, opts ...grpc.CallOption) (*DiffTopologyReply, error)
+	RestoreTopology(ctx context.Context, in *RestoreTopologyRequest, opts ...grpc.CallOption) (Hub_RestoreTopologyClient, error)
+	ExportTopology(ctx context.Context, in *ExportTopologyRequest, opts ...grpc.CallOption) (*ExportTopologyReply, error)
 }
 
 type hubClient struct {
@@ -1343,39 +3685,148 @@ func (c *hubClient) Stop(ctx context.Context, in *StopHubRequest, opts ...grpc.C
 	return out, nil
 }
 
-func (c *hubClient) StartAgents(ctx context.Context, in *StartAgentsRequest, opts ...grpc.CallOption) (*StartAgentsReply, error) {
-	out := new(StartAgentsReply)
-	err := c.cc.Invoke(ctx, "/idl.Hub/StartAgents", in, out, opts...)
+func (c *hubClient) StartAgents(ctx context.Context, in *StartAgentsRequest, opts ...grpc.CallOption) (*StartAgentsReply, error) {
+	out := new(StartAgentsReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/StartAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) StatusAgents(ctx context.Context, in *StatusAgentsRequest, opts ...grpc.CallOption) (*StatusAgentsReply, error) {
+	out := new(StatusAgentsReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/StatusAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) StopAgents(ctx context.Context, in *StopAgentsRequest, opts ...grpc.CallOption) (*StopAgentsReply, error) {
+	out := new(StopAgentsReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/StopAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) MakeCluster(ctx context.Context, in *MakeClusterRequest, opts ...grpc.CallOption) (Hub_MakeClusterClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Hub_serviceDesc.Streams[0], "/idl.Hub/MakeCluster", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hubMakeClusterClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Hub_MakeClusterClient interface {
+	Recv() (*HubReply, error)
+	grpc.ClientStream
+}
+
+type hubMakeClusterClient struct {
+	grpc.ClientStream
+}
+
+func (x *hubMakeClusterClient) Recv() (*HubReply, error) {
+	m := new(HubReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *hubClient) CleanInitCluster(ctx context.Context, in *CleanInitClusterRequest, opts ...grpc.CallOption) (*CleanInitClusterReply, error) {
+	out := new(CleanInitClusterReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/CleanInitCluster", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) AddMirrors(ctx context.Context, in *AddMirrorsRequest, opts ...grpc.CallOption) (Hub_AddMirrorsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Hub_serviceDesc.Streams[1], "/idl.Hub/AddMirrors", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hubAddMirrorsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Hub_AddMirrorsClient interface {
+	Recv() (*HubReply, error)
+	grpc.ClientStream
+}
+
+type hubAddMirrorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *hubAddMirrorsClient) Recv() (*HubReply, error) {
+	m := new(HubReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *hubClient) GetAllHostNames(ctx context.Context, in *GetAllHostNamesRequest, opts ...grpc.CallOption) (*GetAllHostNamesReply, error) {
+	out := new(GetAllHostNamesReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/GetAllHostNames", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) FindFreePorts(ctx context.Context, in *FindFreePortsRequest, opts ...grpc.CallOption) (*FindFreePortsReply, error) {
+	out := new(FindFreePortsReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/FindFreePorts", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *hubClient) StatusAgents(ctx context.Context, in *StatusAgentsRequest, opts ...grpc.CallOption) (*StatusAgentsReply, error) {
-	out := new(StatusAgentsReply)
-	err := c.cc.Invoke(ctx, "/idl.Hub/StatusAgents", in, out, opts...)
+func (c *hubClient) AdoptCluster(ctx context.Context, in *AdoptClusterRequest, opts ...grpc.CallOption) (*AdoptClusterReply, error) {
+	out := new(AdoptClusterReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/AdoptCluster", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *hubClient) StopAgents(ctx context.Context, in *StopAgentsRequest, opts ...grpc.CallOption) (*StopAgentsReply, error) {
-	out := new(StopAgentsReply)
-	err := c.cc.Invoke(ctx, "/idl.Hub/StopAgents", in, out, opts...)
+func (c *hubClient) ListOperations(ctx context.Context, in *ListOperationsRequest, opts ...grpc.CallOption) (*ListOperationsReply, error) {
+	out := new(ListOperationsReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/ListOperations", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *hubClient) MakeCluster(ctx context.Context, in *MakeClusterRequest, opts ...grpc.CallOption) (Hub_MakeClusterClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Hub_serviceDesc.Streams[0], "/idl.Hub/MakeCluster", opts...)
+func (c *hubClient) AttachOperation(ctx context.Context, in *AttachOperationRequest, opts ...grpc.CallOption) (Hub_AttachOperationClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Hub_serviceDesc.Streams[2], "/idl.Hub/AttachOperation", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &hubMakeClusterClient{stream}
+	x := &hubAttachOperationClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1385,16 +3836,16 @@ func (c *hubClient) MakeCluster(ctx context.Context, in *MakeClusterRequest, opt
 	return x, nil
 }
 
-type Hub_MakeClusterClient interface {
+type Hub_AttachOperationClient interface {
 	Recv() (*HubReply, error)
 	grpc.ClientStream
 }
 
-type hubMakeClusterClient struct {
+type hubAttachOperationClient struct {
 	grpc.ClientStream
 }
 
-func (x *hubMakeClusterClient) Recv() (*HubReply, error) {
+func (x *hubAttachOperationClient) Recv() (*HubReply, error) {
 	m := new(HubReply)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -1402,21 +3853,66 @@ func (x *hubMakeClusterClient) Recv() (*HubReply, error) {
 	return m, nil
 }
 
-func (c *hubClient) CleanInitCluster(ctx context.Context, in *CleanInitClusterRequest, opts ...grpc.CallOption) (*CleanInitClusterReply, error) {
-	out := new(CleanInitClusterReply)
-	err := c.cc.Invoke(ctx, "/idl.Hub/CleanInitCluster", in, out, opts...)
+func (c *hubClient) CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*CancelOperationReply, error) {
+	out := new(CancelOperationReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/CancelOperation", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *hubClient) AddMirrors(ctx context.Context, in *AddMirrorsRequest, opts ...grpc.CallOption) (Hub_AddMirrorsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Hub_serviceDesc.Streams[1], "/idl.Hub/AddMirrors", opts...)
+func (c *hubClient) GetLockStatus(ctx context.Context, in *GetLockStatusRequest, opts ...grpc.CallOption) (*GetLockStatusReply, error) {
+	out := new(GetLockStatusReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/GetLockStatus", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &hubAddMirrorsClient{stream}
+	return out, nil
+}
+
+func (c *hubClient) BreakLock(ctx context.Context, in *BreakLockRequest, opts ...grpc.CallOption) (*BreakLockReply, error) {
+	out := new(BreakLockReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/BreakLock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsReply, error) {
+	out := new(ListEventsReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/ListEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) ListTopology(ctx context.Context, in *ListTopologyRequest, opts ...grpc.CallOption) (*ListTopologyReply, error) {
+	out := new(ListTopologyReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/ListTopology", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) DiffTopology(ctx context.Context, in *DiffTopologyRequest, opts ...grpc.CallOption) (*DiffTopologyReply, error) {
+	out := new(DiffTopologyReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/DiffTopology", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) RestoreTopology(ctx context.Context, in *RestoreTopologyRequest, opts ...grpc.CallOption) (Hub_RestoreTopologyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Hub_serviceDesc.Streams[3], "/idl.Hub/RestoreTopology", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hubRestoreTopologyClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1426,16 +3922,16 @@ func (c *hubClient) AddMirrors(ctx context.Context, in *AddMirrorsRequest, opts
 	return x, nil
 }
 
-type Hub_AddMirrorsClient interface {
+type Hub_RestoreTopologyClient interface {
 	Recv() (*HubReply, error)
 	grpc.ClientStream
 }
 
-type hubAddMirrorsClient struct {
+type hubRestoreTopologyClient struct {
 	grpc.ClientStream
 }
 
-func (x *hubAddMirrorsClient) Recv() (*HubReply, error) {
+func (x *hubRestoreTopologyClient) Recv() (*HubReply, error) {
 	m := new(HubReply)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
@@ -1443,9 +3939,9 @@ func (x *hubAddMirrorsClient) Recv() (*HubReply, error) {
 	return m, nil
 }
 
-func (c *hubClient) GetAllHostNames(ctx context.Context, in *GetAllHostNamesRequest, opts ...grpc.CallOption) (*GetAllHostNamesReply, error) {
-	out := new(GetAllHostNamesReply)
-	err := c.cc.Invoke(ctx, "/idl.Hub/GetAllHostNames", in, out, opts...)
+func (c *hubClient) ExportTopology(ctx context.Context, in *ExportTopologyRequest, opts ...grpc.CallOption) (*ExportTopologyReply, error) {
+	out := new(ExportTopologyReply)
+	err := c.cc.Invoke(ctx, "/idl.Hub/ExportTopology", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1462,6 +3958,18 @@ type HubServer interface {
 	CleanInitCluster(context.Context, *CleanInitClusterRequest) (*CleanInitClusterReply, error)
 	AddMirrors(*AddMirrorsRequest, Hub_AddMirrorsServer) error
 	GetAllHostNames(context.Context, *GetAllHostNamesRequest) (*GetAllHostNamesReply, error)
+	FindFreePorts(context.Context, *FindFreePortsRequest) (*FindFreePortsReply, error)
+	AdoptCluster(context.Context, *AdoptClusterRequest) (*AdoptClusterReply, error)
+	ListOperations(context.Context, *ListOperationsRequest) (*ListOperationsReply, error)
+	AttachOperation(*AttachOperationRequest, Hub_AttachOperationServer) error
+	CancelOperation(context.Context, *CancelOperationRequest) (*CancelOperationReply, error)
+	GetLockStatus(context.Context, *GetLockStatusRequest) (*GetLockStatusReply, error)
+	BreakLock(context.Context, *BreakLockRequest) (*BreakLockReply, error)
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsReply, error)
+	ListTopology(context.Context, *ListTopologyRequest) (*ListTopologyReply, error)
+	DiffTopology(context.Context, *DiffTopologyRequest) (*DiffTopologyReply, error)
+	RestoreTopology(*RestoreTopologyRequest, Hub_RestoreTopologyServer) error
+	ExportTopology(context.Context, *ExportTopologyRequest) (*ExportTopologyReply, error)
 }
 
 // UnimplementedHubServer can be embedded to have forward compatible implementations.
@@ -1492,6 +4000,42 @@ func (*UnimplementedHubServer) AddMirrors(req *AddMirrorsRequest, srv Hub_AddMir
 func (*UnimplementedHubServer) GetAllHostNames(ctx context.Context, req *GetAllHostNamesRequest) (*GetAllHostNamesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllHostNames not implemented")
 }
+func (*UnimplementedHubServer) FindFreePorts(ctx context.Context, req *FindFreePortsRequest) (*FindFreePortsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindFreePorts not implemented")
+}
+func (*UnimplementedHubServer) AdoptCluster(ctx context.Context, req *AdoptClusterRequest) (*AdoptClusterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdoptCluster not implemented")
+}
+func (*UnimplementedHubServer) ListOperations(ctx context.Context, req *ListOperationsRequest) (*ListOperationsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOperations not implemented")
+}
+func (*UnimplementedHubServer) AttachOperation(req *AttachOperationRequest, srv Hub_AttachOperationServer) error {
+	return status.Errorf(codes.Unimplemented, "method AttachOperation not implemented")
+}
+func (*UnimplementedHubServer) CancelOperation(ctx context.Context, req *CancelOperationRequest) (*CancelOperationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOperation not implemented")
+}
+func (*UnimplementedHubServer) GetLockStatus(ctx context.Context, req *GetLockStatusRequest) (*GetLockStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLockStatus not implemented")
+}
+func (*UnimplementedHubServer) BreakLock(ctx context.Context, req *BreakLockRequest) (*BreakLockReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BreakLock not implemented")
+}
+func (*UnimplementedHubServer) ListEvents(ctx context.Context, req *ListEventsRequest) (*ListEventsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (*UnimplementedHubServer) ListTopology(ctx context.Context, req *ListTopologyRequest) (*ListTopologyReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTopology not implemented")
+}
+func (*UnimplementedHubServer) DiffTopology(ctx context.Context, req *DiffTopologyRequest) (*DiffTopologyReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffTopology not implemented")
+}
+func (*UnimplementedHubServer) RestoreTopology(req *RestoreTopologyRequest, srv Hub_RestoreTopologyServer) error {
+	return status.Errorf(codes.Unimplemented, "method RestoreTopology not implemented")
+}
+func (*UnimplementedHubServer) ExportTopology(ctx context.Context, req *ExportTopologyRequest) (*ExportTopologyReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportTopology not implemented")
+}
 
 func RegisterHubServer(s *grpc.Server, srv HubServer) {
 	s.RegisterService(&_Hub_serviceDesc, srv)
@@ -1647,6 +4191,228 @@ func _Hub_GetAllHostNames_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Hub_FindFreePorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindFreePortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).FindFreePorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/FindFreePorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).FindFreePorts(ctx, req.(*FindFreePortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_AdoptCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdoptClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).AdoptCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/AdoptCluster",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).AdoptCluster(ctx, req.(*AdoptClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_ListOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).ListOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/ListOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).ListOperations(ctx, req.(*ListOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_AttachOperation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AttachOperationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HubServer).AttachOperation(m, &hubAttachOperationServer{stream})
+}
+
+type Hub_AttachOperationServer interface {
+	Send(*HubReply) error
+	grpc.ServerStream
+}
+
+type hubAttachOperationServer struct {
+	grpc.ServerStream
+}
+
+func (x *hubAttachOperationServer) Send(m *HubReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Hub_CancelOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).CancelOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/CancelOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).CancelOperation(ctx, req.(*CancelOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_GetLockStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLockStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).GetLockStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/GetLockStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).GetLockStatus(ctx, req.(*GetLockStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_BreakLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BreakLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).BreakLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/BreakLock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).BreakLock(ctx, req.(*BreakLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/ListEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_ListTopology_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTopologyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).ListTopology(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/ListTopology",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).ListTopology(ctx, req.(*ListTopologyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_DiffTopology_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffTopologyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).DiffTopology(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/DiffTopology",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).DiffTopology(ctx, req.(*DiffTopologyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_RestoreTopology_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RestoreTopologyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HubServer).RestoreTopology(m, &hubRestoreTopologyServer{stream})
+}
+
+type Hub_RestoreTopologyServer interface {
+	Send(*HubReply) error
+	grpc.ServerStream
+}
+
+type hubRestoreTopologyServer struct {
+	grpc.ServerStream
+}
+
+func (x *hubRestoreTopologyServer) Send(m *HubReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Hub_ExportTopology_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportTopologyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).ExportTopology(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Hub/ExportTopology",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).ExportTopology(ctx, req.(*ExportTopologyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Hub_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.Hub",
 	HandlerType: (*HubServer)(nil),
@@ -1675,6 +4441,46 @@ var _Hub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetAllHostNames",
 			Handler:    _Hub_GetAllHostNames_Handler,
 		},
+		{
+			MethodName: "FindFreePorts",
+			Handler:    _Hub_FindFreePorts_Handler,
+		},
+		{
+			MethodName: "AdoptCluster",
+			Handler:    _Hub_AdoptCluster_Handler,
+		},
+		{
+			MethodName: "ListOperations",
+			Handler:    _Hub_ListOperations_Handler,
+		},
+		{
+			MethodName: "CancelOperation",
+			Handler:    _Hub_CancelOperation_Handler,
+		},
+		{
+			MethodName: "GetLockStatus",
+			Handler:    _Hub_GetLockStatus_Handler,
+		},
+		{
+			MethodName: "BreakLock",
+			Handler:    _Hub_BreakLock_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _Hub_ListEvents_Handler,
+		},
+		{
+			MethodName: "ListTopology",
+			Handler:    _Hub_ListTopology_Handler,
+		},
+		{
+			MethodName: "DiffTopology",
+			Handler:    _Hub_DiffTopology_Handler,
+		},
+		{
+			MethodName: "ExportTopology",
+			Handler:    _Hub_ExportTopology_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1687,6 +4493,16 @@ var _Hub_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Hub_AddMirrors_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "AttachOperation",
+			Handler:       _Hub_AttachOperation_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RestoreTopology",
+			Handler:       _Hub_RestoreTopology_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "hub.proto",
 }
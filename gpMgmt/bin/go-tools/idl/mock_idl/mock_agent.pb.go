@@ -11,8 +11,44 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	idl "github.com/greenplum-db/gpdb/gp/idl"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
+// MockisSegmentCommandReply_Message is a mock of isSegmentCommandReply_Message interface.
+type MockisSegmentCommandReply_Message struct {
+	ctrl     *gomock.Controller
+	recorder *MockisSegmentCommandReply_MessageMockRecorder
+}
+
+// MockisSegmentCommandReply_MessageMockRecorder is the mock recorder for MockisSegmentCommandReply_Message.
+type MockisSegmentCommandReply_MessageMockRecorder struct {
+	mock *MockisSegmentCommandReply_Message
+}
+
+// NewMockisSegmentCommandReply_Message creates a new mock instance.
+func NewMockisSegmentCommandReply_Message(ctrl *gomock.Controller) *MockisSegmentCommandReply_Message {
+	mock := &MockisSegmentCommandReply_Message{ctrl: ctrl}
+	mock.recorder = &MockisSegmentCommandReply_MessageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockisSegmentCommandReply_Message) EXPECT() *MockisSegmentCommandReply_MessageMockRecorder {
+	return m.recorder
+}
+
+// isSegmentCommandReply_Message mocks base method.
+func (m *MockisSegmentCommandReply_Message) isSegmentCommandReply_Message() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "isSegmentCommandReply_Message")
+}
+
+// isSegmentCommandReply_Message indicates an expected call of isSegmentCommandReply_Message.
+func (mr *MockisSegmentCommandReply_MessageMockRecorder) isSegmentCommandReply_Message() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "isSegmentCommandReply_Message", reflect.TypeOf((*MockisSegmentCommandReply_Message)(nil).isSegmentCommandReply_Message))
+}
+
 // MockAgentClient is a mock of AgentClient interface.
 type MockAgentClient struct {
 	ctrl     *gomock.Controller
@@ -36,6 +72,26 @@ func (m *MockAgentClient) EXPECT() *MockAgentClientMockRecorder {
 	return m.recorder
 }
 
+// GetFreePortRanges mocks base method.
+func (m *MockAgentClient) GetFreePortRanges(ctx context.Context, in *idl.GetFreePortRangesRequest, opts ...grpc.CallOption) (*idl.GetFreePortRangesReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFreePortRanges", varargs...)
+	ret0, _ := ret[0].(*idl.GetFreePortRangesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFreePortRanges indicates an expected call of GetFreePortRanges.
+func (mr *MockAgentClientMockRecorder) GetFreePortRanges(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreePortRanges", reflect.TypeOf((*MockAgentClient)(nil).GetFreePortRanges), varargs...)
+}
+
 // GetHostName mocks base method.
 func (m *MockAgentClient) GetHostName(ctx context.Context, in *idl.GetHostNameRequest, opts ...grpc.CallOption) (*idl.GetHostNameReply, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +152,26 @@ func (mr *MockAgentClientMockRecorder) MakeSegment(ctx, in interface{}, opts ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeSegment", reflect.TypeOf((*MockAgentClient)(nil).MakeSegment), varargs...)
 }
 
+// MakeSegmentStream mocks base method.
+func (m *MockAgentClient) MakeSegmentStream(ctx context.Context, in *idl.MakeSegmentRequest, opts ...grpc.CallOption) (idl.Agent_MakeSegmentStreamClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MakeSegmentStream", varargs...)
+	ret0, _ := ret[0].(idl.Agent_MakeSegmentStreamClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeSegmentStream indicates an expected call of MakeSegmentStream.
+func (mr *MockAgentClientMockRecorder) MakeSegmentStream(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeSegmentStream", reflect.TypeOf((*MockAgentClient)(nil).MakeSegmentStream), varargs...)
+}
+
 // PgBasebackup mocks base method.
 func (m *MockAgentClient) PgBasebackup(ctx context.Context, in *idl.PgBasebackupRequest, opts ...grpc.CallOption) (*idl.PgBasebackupResponse, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +192,46 @@ func (mr *MockAgentClientMockRecorder) PgBasebackup(ctx, in interface{}, opts ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PgBasebackup", reflect.TypeOf((*MockAgentClient)(nil).PgBasebackup), varargs...)
 }
 
+// PgBasebackupStream mocks base method.
+func (m *MockAgentClient) PgBasebackupStream(ctx context.Context, in *idl.PgBasebackupRequest, opts ...grpc.CallOption) (idl.Agent_PgBasebackupStreamClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PgBasebackupStream", varargs...)
+	ret0, _ := ret[0].(idl.Agent_PgBasebackupStreamClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PgBasebackupStream indicates an expected call of PgBasebackupStream.
+func (mr *MockAgentClientMockRecorder) PgBasebackupStream(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PgBasebackupStream", reflect.TypeOf((*MockAgentClient)(nil).PgBasebackupStream), varargs...)
+}
+
+// PrepareDirectories mocks base method.
+func (m *MockAgentClient) PrepareDirectories(ctx context.Context, in *idl.PrepareDirectoriesRequest, opts ...grpc.CallOption) (*idl.PrepareDirectoriesReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PrepareDirectories", varargs...)
+	ret0, _ := ret[0].(*idl.PrepareDirectoriesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrepareDirectories indicates an expected call of PrepareDirectories.
+func (mr *MockAgentClientMockRecorder) PrepareDirectories(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareDirectories", reflect.TypeOf((*MockAgentClient)(nil).PrepareDirectories), varargs...)
+}
+
 // RemoveDirectory mocks base method.
 func (m *MockAgentClient) RemoveDirectory(ctx context.Context, in *idl.RemoveDirectoryRequest, opts ...grpc.CallOption) (*idl.RemoveDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -256,6 +372,272 @@ func (mr *MockAgentClientMockRecorder) ValidateHostEnv(ctx, in interface{}, opts
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateHostEnv", reflect.TypeOf((*MockAgentClient)(nil).ValidateHostEnv), varargs...)
 }
 
+// VerifyDataDirectories mocks base method.
+func (m *MockAgentClient) VerifyDataDirectories(ctx context.Context, in *idl.VerifyDataDirectoriesRequest, opts ...grpc.CallOption) (*idl.VerifyDataDirectoriesReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VerifyDataDirectories", varargs...)
+	ret0, _ := ret[0].(*idl.VerifyDataDirectoriesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyDataDirectories indicates an expected call of VerifyDataDirectories.
+func (mr *MockAgentClientMockRecorder) VerifyDataDirectories(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyDataDirectories", reflect.TypeOf((*MockAgentClient)(nil).VerifyDataDirectories), varargs...)
+}
+
+// MockAgent_MakeSegmentStreamClient is a mock of Agent_MakeSegmentStreamClient interface.
+type MockAgent_MakeSegmentStreamClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_MakeSegmentStreamClientMockRecorder
+}
+
+// MockAgent_MakeSegmentStreamClientMockRecorder is the mock recorder for MockAgent_MakeSegmentStreamClient.
+type MockAgent_MakeSegmentStreamClientMockRecorder struct {
+	mock *MockAgent_MakeSegmentStreamClient
+}
+
+// NewMockAgent_MakeSegmentStreamClient creates a new mock instance.
+func NewMockAgent_MakeSegmentStreamClient(ctrl *gomock.Controller) *MockAgent_MakeSegmentStreamClient {
+	mock := &MockAgent_MakeSegmentStreamClient{ctrl: ctrl}
+	mock.recorder = &MockAgent_MakeSegmentStreamClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgent_MakeSegmentStreamClient) EXPECT() *MockAgent_MakeSegmentStreamClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockAgent_MakeSegmentStreamClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockAgent_MakeSegmentStreamClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAgent_MakeSegmentStreamClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockAgent_MakeSegmentStreamClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAgent_MakeSegmentStreamClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_MakeSegmentStreamClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockAgent_MakeSegmentStreamClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockAgent_MakeSegmentStreamClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAgent_MakeSegmentStreamClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockAgent_MakeSegmentStreamClient) Recv() (*idl.SegmentCommandReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.SegmentCommandReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockAgent_MakeSegmentStreamClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAgent_MakeSegmentStreamClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAgent_MakeSegmentStreamClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAgent_MakeSegmentStreamClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_MakeSegmentStreamClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAgent_MakeSegmentStreamClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAgent_MakeSegmentStreamClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_MakeSegmentStreamClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockAgent_MakeSegmentStreamClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockAgent_MakeSegmentStreamClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAgent_MakeSegmentStreamClient)(nil).Trailer))
+}
+
+// MockAgent_PgBasebackupStreamClient is a mock of Agent_PgBasebackupStreamClient interface.
+type MockAgent_PgBasebackupStreamClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_PgBasebackupStreamClientMockRecorder
+}
+
+// MockAgent_PgBasebackupStreamClientMockRecorder is the mock recorder for MockAgent_PgBasebackupStreamClient.
+type MockAgent_PgBasebackupStreamClientMockRecorder struct {
+	mock *MockAgent_PgBasebackupStreamClient
+}
+
+// NewMockAgent_PgBasebackupStreamClient creates a new mock instance.
+func NewMockAgent_PgBasebackupStreamClient(ctrl *gomock.Controller) *MockAgent_PgBasebackupStreamClient {
+	mock := &MockAgent_PgBasebackupStreamClient{ctrl: ctrl}
+	mock.recorder = &MockAgent_PgBasebackupStreamClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgent_PgBasebackupStreamClient) EXPECT() *MockAgent_PgBasebackupStreamClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockAgent_PgBasebackupStreamClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockAgent_PgBasebackupStreamClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockAgent_PgBasebackupStreamClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockAgent_PgBasebackupStreamClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAgent_PgBasebackupStreamClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_PgBasebackupStreamClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockAgent_PgBasebackupStreamClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockAgent_PgBasebackupStreamClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockAgent_PgBasebackupStreamClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockAgent_PgBasebackupStreamClient) Recv() (*idl.SegmentCommandReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*idl.SegmentCommandReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockAgent_PgBasebackupStreamClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockAgent_PgBasebackupStreamClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAgent_PgBasebackupStreamClient) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAgent_PgBasebackupStreamClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_PgBasebackupStreamClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAgent_PgBasebackupStreamClient) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAgent_PgBasebackupStreamClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_PgBasebackupStreamClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockAgent_PgBasebackupStreamClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockAgent_PgBasebackupStreamClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockAgent_PgBasebackupStreamClient)(nil).Trailer))
+}
+
 // MockAgentServer is a mock of AgentServer interface.
 type MockAgentServer struct {
 	ctrl     *gomock.Controller
@@ -279,6 +661,21 @@ func (m *MockAgentServer) EXPECT() *MockAgentServerMockRecorder {
 	return m.recorder
 }
 
+// GetFreePortRanges mocks base method.
+func (m *MockAgentServer) GetFreePortRanges(arg0 context.Context, arg1 *idl.GetFreePortRangesRequest) (*idl.GetFreePortRangesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFreePortRanges", arg0, arg1)
+	ret0, _ := ret[0].(*idl.GetFreePortRangesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFreePortRanges indicates an expected call of GetFreePortRanges.
+func (mr *MockAgentServerMockRecorder) GetFreePortRanges(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFreePortRanges", reflect.TypeOf((*MockAgentServer)(nil).GetFreePortRanges), arg0, arg1)
+}
+
 // GetHostName mocks base method.
 func (m *MockAgentServer) GetHostName(arg0 context.Context, arg1 *idl.GetHostNameRequest) (*idl.GetHostNameReply, error) {
 	m.ctrl.T.Helper()
@@ -324,6 +721,20 @@ func (mr *MockAgentServerMockRecorder) MakeSegment(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeSegment", reflect.TypeOf((*MockAgentServer)(nil).MakeSegment), arg0, arg1)
 }
 
+// MakeSegmentStream mocks base method.
+func (m *MockAgentServer) MakeSegmentStream(arg0 *idl.MakeSegmentRequest, arg1 idl.Agent_MakeSegmentStreamServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MakeSegmentStream", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MakeSegmentStream indicates an expected call of MakeSegmentStream.
+func (mr *MockAgentServerMockRecorder) MakeSegmentStream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeSegmentStream", reflect.TypeOf((*MockAgentServer)(nil).MakeSegmentStream), arg0, arg1)
+}
+
 // PgBasebackup mocks base method.
 func (m *MockAgentServer) PgBasebackup(arg0 context.Context, arg1 *idl.PgBasebackupRequest) (*idl.PgBasebackupResponse, error) {
 	m.ctrl.T.Helper()
@@ -339,6 +750,35 @@ func (mr *MockAgentServerMockRecorder) PgBasebackup(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PgBasebackup", reflect.TypeOf((*MockAgentServer)(nil).PgBasebackup), arg0, arg1)
 }
 
+// PgBasebackupStream mocks base method.
+func (m *MockAgentServer) PgBasebackupStream(arg0 *idl.PgBasebackupRequest, arg1 idl.Agent_PgBasebackupStreamServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PgBasebackupStream", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PgBasebackupStream indicates an expected call of PgBasebackupStream.
+func (mr *MockAgentServerMockRecorder) PgBasebackupStream(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PgBasebackupStream", reflect.TypeOf((*MockAgentServer)(nil).PgBasebackupStream), arg0, arg1)
+}
+
+// PrepareDirectories mocks base method.
+func (m *MockAgentServer) PrepareDirectories(arg0 context.Context, arg1 *idl.PrepareDirectoriesRequest) (*idl.PrepareDirectoriesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrepareDirectories", arg0, arg1)
+	ret0, _ := ret[0].(*idl.PrepareDirectoriesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PrepareDirectories indicates an expected call of PrepareDirectories.
+func (mr *MockAgentServerMockRecorder) PrepareDirectories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareDirectories", reflect.TypeOf((*MockAgentServer)(nil).PrepareDirectories), arg0, arg1)
+}
+
 // RemoveDirectory mocks base method.
 func (m *MockAgentServer) RemoveDirectory(arg0 context.Context, arg1 *idl.RemoveDirectoryRequest) (*idl.RemoveDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -443,3 +883,256 @@ func (mr *MockAgentServerMockRecorder) ValidateHostEnv(arg0, arg1 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateHostEnv", reflect.TypeOf((*MockAgentServer)(nil).ValidateHostEnv), arg0, arg1)
 }
+
+// VerifyDataDirectories mocks base method.
+func (m *MockAgentServer) VerifyDataDirectories(arg0 context.Context, arg1 *idl.VerifyDataDirectoriesRequest) (*idl.VerifyDataDirectoriesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyDataDirectories", arg0, arg1)
+	ret0, _ := ret[0].(*idl.VerifyDataDirectoriesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyDataDirectories indicates an expected call of VerifyDataDirectories.
+func (mr *MockAgentServerMockRecorder) VerifyDataDirectories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyDataDirectories", reflect.TypeOf((*MockAgentServer)(nil).VerifyDataDirectories), arg0, arg1)
+}
+
+// MockAgent_MakeSegmentStreamServer is a mock of Agent_MakeSegmentStreamServer interface.
+type MockAgent_MakeSegmentStreamServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_MakeSegmentStreamServerMockRecorder
+}
+
+// MockAgent_MakeSegmentStreamServerMockRecorder is the mock recorder for MockAgent_MakeSegmentStreamServer.
+type MockAgent_MakeSegmentStreamServerMockRecorder struct {
+	mock *MockAgent_MakeSegmentStreamServer
+}
+
+// NewMockAgent_MakeSegmentStreamServer creates a new mock instance.
+func NewMockAgent_MakeSegmentStreamServer(ctrl *gomock.Controller) *MockAgent_MakeSegmentStreamServer {
+	mock := &MockAgent_MakeSegmentStreamServer{ctrl: ctrl}
+	mock.recorder = &MockAgent_MakeSegmentStreamServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgent_MakeSegmentStreamServer) EXPECT() *MockAgent_MakeSegmentStreamServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockAgent_MakeSegmentStreamServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAgent_MakeSegmentStreamServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_MakeSegmentStreamServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAgent_MakeSegmentStreamServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAgent_MakeSegmentStreamServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_MakeSegmentStreamServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockAgent_MakeSegmentStreamServer) Send(arg0 *idl.SegmentCommandReply) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockAgent_MakeSegmentStreamServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAgent_MakeSegmentStreamServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockAgent_MakeSegmentStreamServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockAgent_MakeSegmentStreamServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAgent_MakeSegmentStreamServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAgent_MakeSegmentStreamServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAgent_MakeSegmentStreamServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_MakeSegmentStreamServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockAgent_MakeSegmentStreamServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockAgent_MakeSegmentStreamServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAgent_MakeSegmentStreamServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockAgent_MakeSegmentStreamServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockAgent_MakeSegmentStreamServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAgent_MakeSegmentStreamServer)(nil).SetTrailer), arg0)
+}
+
+// MockAgent_PgBasebackupStreamServer is a mock of Agent_PgBasebackupStreamServer interface.
+type MockAgent_PgBasebackupStreamServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAgent_PgBasebackupStreamServerMockRecorder
+}
+
+// MockAgent_PgBasebackupStreamServerMockRecorder is the mock recorder for MockAgent_PgBasebackupStreamServer.
+type MockAgent_PgBasebackupStreamServerMockRecorder struct {
+	mock *MockAgent_PgBasebackupStreamServer
+}
+
+// NewMockAgent_PgBasebackupStreamServer creates a new mock instance.
+func NewMockAgent_PgBasebackupStreamServer(ctrl *gomock.Controller) *MockAgent_PgBasebackupStreamServer {
+	mock := &MockAgent_PgBasebackupStreamServer{ctrl: ctrl}
+	mock.recorder = &MockAgent_PgBasebackupStreamServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAgent_PgBasebackupStreamServer) EXPECT() *MockAgent_PgBasebackupStreamServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockAgent_PgBasebackupStreamServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockAgent_PgBasebackupStreamServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockAgent_PgBasebackupStreamServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockAgent_PgBasebackupStreamServer) RecvMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockAgent_PgBasebackupStreamServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockAgent_PgBasebackupStreamServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockAgent_PgBasebackupStreamServer) Send(arg0 *idl.SegmentCommandReply) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockAgent_PgBasebackupStreamServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockAgent_PgBasebackupStreamServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockAgent_PgBasebackupStreamServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockAgent_PgBasebackupStreamServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockAgent_PgBasebackupStreamServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockAgent_PgBasebackupStreamServer) SendMsg(m interface{}) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockAgent_PgBasebackupStreamServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockAgent_PgBasebackupStreamServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockAgent_PgBasebackupStreamServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockAgent_PgBasebackupStreamServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockAgent_PgBasebackupStreamServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockAgent_PgBasebackupStreamServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockAgent_PgBasebackupStreamServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockAgent_PgBasebackupStreamServer)(nil).SetTrailer), arg0)
+}
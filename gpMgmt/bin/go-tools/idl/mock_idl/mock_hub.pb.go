@@ -56,6 +56,86 @@ func (mr *MockHubClientMockRecorder) AddMirrors(arg0, arg1 interface{}, arg2 ...
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMirrors", reflect.TypeOf((*MockHubClient)(nil).AddMirrors), varargs...)
 }
 
+// AdoptCluster mocks base method.
+func (m *MockHubClient) AdoptCluster(arg0 context.Context, arg1 *idl.AdoptClusterRequest, arg2 ...grpc.CallOption) (*idl.AdoptClusterReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AdoptCluster", varargs...)
+	ret0, _ := ret[0].(*idl.AdoptClusterReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdoptCluster indicates an expected call of AdoptCluster.
+func (mr *MockHubClientMockRecorder) AdoptCluster(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdoptCluster", reflect.TypeOf((*MockHubClient)(nil).AdoptCluster), varargs...)
+}
+
+// AttachOperation mocks base method.
+func (m *MockHubClient) AttachOperation(arg0 context.Context, arg1 *idl.AttachOperationRequest, arg2 ...grpc.CallOption) (idl.Hub_AttachOperationClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AttachOperation", varargs...)
+	ret0, _ := ret[0].(idl.Hub_AttachOperationClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachOperation indicates an expected call of AttachOperation.
+func (mr *MockHubClientMockRecorder) AttachOperation(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachOperation", reflect.TypeOf((*MockHubClient)(nil).AttachOperation), varargs...)
+}
+
+// BreakLock mocks base method.
+func (m *MockHubClient) BreakLock(arg0 context.Context, arg1 *idl.BreakLockRequest, arg2 ...grpc.CallOption) (*idl.BreakLockReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BreakLock", varargs...)
+	ret0, _ := ret[0].(*idl.BreakLockReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BreakLock indicates an expected call of BreakLock.
+func (mr *MockHubClientMockRecorder) BreakLock(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BreakLock", reflect.TypeOf((*MockHubClient)(nil).BreakLock), varargs...)
+}
+
+// CancelOperation mocks base method.
+func (m *MockHubClient) CancelOperation(arg0 context.Context, arg1 *idl.CancelOperationRequest, arg2 ...grpc.CallOption) (*idl.CancelOperationReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelOperation", varargs...)
+	ret0, _ := ret[0].(*idl.CancelOperationReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOperation indicates an expected call of CancelOperation.
+func (mr *MockHubClientMockRecorder) CancelOperation(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOperation", reflect.TypeOf((*MockHubClient)(nil).CancelOperation), varargs...)
+}
+
 // CleanInitCluster mocks base method.
 func (m *MockHubClient) CleanInitCluster(arg0 context.Context, arg1 *idl.CleanInitClusterRequest, arg2 ...grpc.CallOption) (*idl.CleanInitClusterReply, error) {
 	m.ctrl.T.Helper()
@@ -76,6 +156,66 @@ func (mr *MockHubClientMockRecorder) CleanInitCluster(arg0, arg1 interface{}, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanInitCluster", reflect.TypeOf((*MockHubClient)(nil).CleanInitCluster), varargs...)
 }
 
+// DiffTopology mocks base method.
+func (m *MockHubClient) DiffTopology(arg0 context.Context, arg1 *idl.DiffTopologyRequest, arg2 ...grpc.CallOption) (*idl.DiffTopologyReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DiffTopology", varargs...)
+	ret0, _ := ret[0].(*idl.DiffTopologyReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffTopology indicates an expected call of DiffTopology.
+func (mr *MockHubClientMockRecorder) DiffTopology(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffTopology", reflect.TypeOf((*MockHubClient)(nil).DiffTopology), varargs...)
+}
+
+// ExportTopology mocks base method.
+func (m *MockHubClient) ExportTopology(arg0 context.Context, arg1 *idl.ExportTopologyRequest, arg2 ...grpc.CallOption) (*idl.ExportTopologyReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportTopology", varargs...)
+	ret0, _ := ret[0].(*idl.ExportTopologyReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportTopology indicates an expected call of ExportTopology.
+func (mr *MockHubClientMockRecorder) ExportTopology(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTopology", reflect.TypeOf((*MockHubClient)(nil).ExportTopology), varargs...)
+}
+
+// FindFreePorts mocks base method.
+func (m *MockHubClient) FindFreePorts(arg0 context.Context, arg1 *idl.FindFreePortsRequest, arg2 ...grpc.CallOption) (*idl.FindFreePortsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindFreePorts", varargs...)
+	ret0, _ := ret[0].(*idl.FindFreePortsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFreePorts indicates an expected call of FindFreePorts.
+func (mr *MockHubClientMockRecorder) FindFreePorts(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFreePorts", reflect.TypeOf((*MockHubClient)(nil).FindFreePorts), varargs...)
+}
+
 // GetAllHostNames mocks base method.
 func (m *MockHubClient) GetAllHostNames(arg0 context.Context, arg1 *idl.GetAllHostNamesRequest, arg2 ...grpc.CallOption) (*idl.GetAllHostNamesReply, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +236,86 @@ func (mr *MockHubClientMockRecorder) GetAllHostNames(arg0, arg1 interface{}, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllHostNames", reflect.TypeOf((*MockHubClient)(nil).GetAllHostNames), varargs...)
 }
 
+// GetLockStatus mocks base method.
+func (m *MockHubClient) GetLockStatus(arg0 context.Context, arg1 *idl.GetLockStatusRequest, arg2 ...grpc.CallOption) (*idl.GetLockStatusReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetLockStatus", varargs...)
+	ret0, _ := ret[0].(*idl.GetLockStatusReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLockStatus indicates an expected call of GetLockStatus.
+func (mr *MockHubClientMockRecorder) GetLockStatus(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockStatus", reflect.TypeOf((*MockHubClient)(nil).GetLockStatus), varargs...)
+}
+
+// ListEvents mocks base method.
+func (m *MockHubClient) ListEvents(arg0 context.Context, arg1 *idl.ListEventsRequest, arg2 ...grpc.CallOption) (*idl.ListEventsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEvents", varargs...)
+	ret0, _ := ret[0].(*idl.ListEventsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockHubClientMockRecorder) ListEvents(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockHubClient)(nil).ListEvents), varargs...)
+}
+
+// ListOperations mocks base method.
+func (m *MockHubClient) ListOperations(arg0 context.Context, arg1 *idl.ListOperationsRequest, arg2 ...grpc.CallOption) (*idl.ListOperationsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListOperations", varargs...)
+	ret0, _ := ret[0].(*idl.ListOperationsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOperations indicates an expected call of ListOperations.
+func (mr *MockHubClientMockRecorder) ListOperations(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOperations", reflect.TypeOf((*MockHubClient)(nil).ListOperations), varargs...)
+}
+
+// ListTopology mocks base method.
+func (m *MockHubClient) ListTopology(arg0 context.Context, arg1 *idl.ListTopologyRequest, arg2 ...grpc.CallOption) (*idl.ListTopologyReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTopology", varargs...)
+	ret0, _ := ret[0].(*idl.ListTopologyReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTopology indicates an expected call of ListTopology.
+func (mr *MockHubClientMockRecorder) ListTopology(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTopology", reflect.TypeOf((*MockHubClient)(nil).ListTopology), varargs...)
+}
+
 // MakeCluster mocks base method.
 func (m *MockHubClient) MakeCluster(arg0 context.Context, arg1 *idl.MakeClusterRequest, arg2 ...grpc.CallOption) (idl.Hub_MakeClusterClient, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +336,26 @@ func (mr *MockHubClientMockRecorder) MakeCluster(arg0, arg1 interface{}, arg2 ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeCluster", reflect.TypeOf((*MockHubClient)(nil).MakeCluster), varargs...)
 }
 
+// RestoreTopology mocks base method.
+func (m *MockHubClient) RestoreTopology(arg0 context.Context, arg1 *idl.RestoreTopologyRequest, arg2 ...grpc.CallOption) (idl.Hub_RestoreTopologyClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreTopology", varargs...)
+	ret0, _ := ret[0].(idl.Hub_RestoreTopologyClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTopology indicates an expected call of RestoreTopology.
+func (mr *MockHubClientMockRecorder) RestoreTopology(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTopology", reflect.TypeOf((*MockHubClient)(nil).RestoreTopology), varargs...)
+}
+
 // StartAgents mocks base method.
 func (m *MockHubClient) StartAgents(arg0 context.Context, arg1 *idl.StartAgentsRequest, arg2 ...grpc.CallOption) (*idl.StartAgentsReply, error) {
 	m.ctrl.T.Helper()
@@ -233,6 +473,65 @@ func (mr *MockHubServerMockRecorder) AddMirrors(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMirrors", reflect.TypeOf((*MockHubServer)(nil).AddMirrors), arg0, arg1)
 }
 
+// AdoptCluster mocks base method.
+func (m *MockHubServer) AdoptCluster(arg0 context.Context, arg1 *idl.AdoptClusterRequest) (*idl.AdoptClusterReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdoptCluster", arg0, arg1)
+	ret0, _ := ret[0].(*idl.AdoptClusterReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdoptCluster indicates an expected call of AdoptCluster.
+func (mr *MockHubServerMockRecorder) AdoptCluster(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdoptCluster", reflect.TypeOf((*MockHubServer)(nil).AdoptCluster), arg0, arg1)
+}
+
+// AttachOperation mocks base method.
+func (m *MockHubServer) AttachOperation(arg0 *idl.AttachOperationRequest, arg1 idl.Hub_AttachOperationServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachOperation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachOperation indicates an expected call of AttachOperation.
+func (mr *MockHubServerMockRecorder) AttachOperation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachOperation", reflect.TypeOf((*MockHubServer)(nil).AttachOperation), arg0, arg1)
+}
+
+// BreakLock mocks base method.
+func (m *MockHubServer) BreakLock(arg0 context.Context, arg1 *idl.BreakLockRequest) (*idl.BreakLockReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BreakLock", arg0, arg1)
+	ret0, _ := ret[0].(*idl.BreakLockReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BreakLock indicates an expected call of BreakLock.
+func (mr *MockHubServerMockRecorder) BreakLock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BreakLock", reflect.TypeOf((*MockHubServer)(nil).BreakLock), arg0, arg1)
+}
+
+// CancelOperation mocks base method.
+func (m *MockHubServer) CancelOperation(arg0 context.Context, arg1 *idl.CancelOperationRequest) (*idl.CancelOperationReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOperation", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CancelOperationReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOperation indicates an expected call of CancelOperation.
+func (mr *MockHubServerMockRecorder) CancelOperation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOperation", reflect.TypeOf((*MockHubServer)(nil).CancelOperation), arg0, arg1)
+}
+
 // CleanInitCluster mocks base method.
 func (m *MockHubServer) CleanInitCluster(arg0 context.Context, arg1 *idl.CleanInitClusterRequest) (*idl.CleanInitClusterReply, error) {
 	m.ctrl.T.Helper()
@@ -248,6 +547,51 @@ func (mr *MockHubServerMockRecorder) CleanInitCluster(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanInitCluster", reflect.TypeOf((*MockHubServer)(nil).CleanInitCluster), arg0, arg1)
 }
 
+// DiffTopology mocks base method.
+func (m *MockHubServer) DiffTopology(arg0 context.Context, arg1 *idl.DiffTopologyRequest) (*idl.DiffTopologyReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffTopology", arg0, arg1)
+	ret0, _ := ret[0].(*idl.DiffTopologyReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffTopology indicates an expected call of DiffTopology.
+func (mr *MockHubServerMockRecorder) DiffTopology(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffTopology", reflect.TypeOf((*MockHubServer)(nil).DiffTopology), arg0, arg1)
+}
+
+// ExportTopology mocks base method.
+func (m *MockHubServer) ExportTopology(arg0 context.Context, arg1 *idl.ExportTopologyRequest) (*idl.ExportTopologyReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTopology", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ExportTopologyReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportTopology indicates an expected call of ExportTopology.
+func (mr *MockHubServerMockRecorder) ExportTopology(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTopology", reflect.TypeOf((*MockHubServer)(nil).ExportTopology), arg0, arg1)
+}
+
+// FindFreePorts mocks base method.
+func (m *MockHubServer) FindFreePorts(arg0 context.Context, arg1 *idl.FindFreePortsRequest) (*idl.FindFreePortsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFreePorts", arg0, arg1)
+	ret0, _ := ret[0].(*idl.FindFreePortsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFreePorts indicates an expected call of FindFreePorts.
+func (mr *MockHubServerMockRecorder) FindFreePorts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFreePorts", reflect.TypeOf((*MockHubServer)(nil).FindFreePorts), arg0, arg1)
+}
+
 // GetAllHostNames mocks base method.
 func (m *MockHubServer) GetAllHostNames(arg0 context.Context, arg1 *idl.GetAllHostNamesRequest) (*idl.GetAllHostNamesReply, error) {
 	m.ctrl.T.Helper()
@@ -263,6 +607,66 @@ func (mr *MockHubServerMockRecorder) GetAllHostNames(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllHostNames", reflect.TypeOf((*MockHubServer)(nil).GetAllHostNames), arg0, arg1)
 }
 
+// GetLockStatus mocks base method.
+func (m *MockHubServer) GetLockStatus(arg0 context.Context, arg1 *idl.GetLockStatusRequest) (*idl.GetLockStatusReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLockStatus", arg0, arg1)
+	ret0, _ := ret[0].(*idl.GetLockStatusReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLockStatus indicates an expected call of GetLockStatus.
+func (mr *MockHubServerMockRecorder) GetLockStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockStatus", reflect.TypeOf((*MockHubServer)(nil).GetLockStatus), arg0, arg1)
+}
+
+// ListEvents mocks base method.
+func (m *MockHubServer) ListEvents(arg0 context.Context, arg1 *idl.ListEventsRequest) (*idl.ListEventsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ListEventsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockHubServerMockRecorder) ListEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockHubServer)(nil).ListEvents), arg0, arg1)
+}
+
+// ListOperations mocks base method.
+func (m *MockHubServer) ListOperations(arg0 context.Context, arg1 *idl.ListOperationsRequest) (*idl.ListOperationsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOperations", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ListOperationsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOperations indicates an expected call of ListOperations.
+func (mr *MockHubServerMockRecorder) ListOperations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOperations", reflect.TypeOf((*MockHubServer)(nil).ListOperations), arg0, arg1)
+}
+
+// ListTopology mocks base method.
+func (m *MockHubServer) ListTopology(arg0 context.Context, arg1 *idl.ListTopologyRequest) (*idl.ListTopologyReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTopology", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ListTopologyReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTopology indicates an expected call of ListTopology.
+func (mr *MockHubServerMockRecorder) ListTopology(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTopology", reflect.TypeOf((*MockHubServer)(nil).ListTopology), arg0, arg1)
+}
+
 // MakeCluster mocks base method.
 func (m *MockHubServer) MakeCluster(arg0 *idl.MakeClusterRequest, arg1 idl.Hub_MakeClusterServer) error {
 	m.ctrl.T.Helper()
@@ -277,6 +681,20 @@ func (mr *MockHubServerMockRecorder) MakeCluster(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeCluster", reflect.TypeOf((*MockHubServer)(nil).MakeCluster), arg0, arg1)
 }
 
+// RestoreTopology mocks base method.
+func (m *MockHubServer) RestoreTopology(arg0 *idl.RestoreTopologyRequest, arg1 idl.Hub_RestoreTopologyServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTopology", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTopology indicates an expected call of RestoreTopology.
+func (mr *MockHubServerMockRecorder) RestoreTopology(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTopology", reflect.TypeOf((*MockHubServer)(nil).RestoreTopology), arg0, arg1)
+}
+
 // StartAgents mocks base method.
 func (m *MockHubServer) StartAgents(arg0 context.Context, arg1 *idl.StartAgentsRequest) (*idl.StartAgentsReply, error) {
 	m.ctrl.T.Helper()
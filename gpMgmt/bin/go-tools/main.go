@@ -14,18 +14,27 @@ func main() {
 	root.SilenceErrors = true
 
 	err := root.Execute()
+	cli.FinishCommand(err)
+	if cli.IsMachineOutput() {
+		// the result event carries the error, so that it is not reported twice
+		cli.ReportResult(err)
+		os.Exit(cli.ExitCode(err))
+	}
 	if err != nil {
+		exitCode := cli.ExitCode(err)
+
 		// gplog is initialised in the PreRun function in cobra and sometimes when the
 		// error is due to the input flags, the cobra pkg would not run the PreRun function.
 		// In those cases directly print to the stdout instead of using gplog
 		if gplog.GetLogger() != nil {
 			gplog.Error(err.Error())
+			cli.DisplayFailures(os.Stdout, err)
 		} else {
 			fmt.Println(err)
 			err = root.Help(); if err != nil {
 				fmt.Println(err.Error())
 			}
 		}
-		os.Exit(1)
+		os.Exit(exitCode)
 	}
 }
@@ -891,7 +891,7 @@ func TestInputFileValidation(t *testing.T) {
 			t.Fatalf("got %v, want exit status 1", err)
 		}
 
-		expectedOut := "[ERROR]:-invalid mirroring-Type: test_mirror. Valid options are 'group' and 'spread'"
+		expectedOut := "[ERROR]:-invalid mirroring-Type: test_mirror. Valid options are 'group', 'spread', 'block' and 'rack-aware'"
 		if !strings.Contains(result.OutputMsg, expectedOut) {
 			t.Errorf("got %q, want %q", result.OutputMsg, expectedOut)
 		}
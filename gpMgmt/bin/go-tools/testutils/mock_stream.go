@@ -1,12 +1,17 @@
 package testutils
 
 import (
+	"context"
+	"io"
+	"sync"
+
 	"github.com/greenplum-db/gpdb/gp/hub"
 	"github.com/greenplum-db/gpdb/gp/idl"
 	"google.golang.org/grpc"
 )
 
 type MockStream struct {
+	mu  sync.Mutex
 	buf []*idl.HubReply
 	grpc.ServerStream
 	err error
@@ -30,6 +35,9 @@ func NewMockStream(err ...error) (*hub.HubStream, *MockStream) {
 }
 
 func (m *MockStream) Send(reply *idl.HubReply) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if m.err == nil {
 		m.buf = append(m.buf, reply)
 		return nil
@@ -38,6 +46,42 @@ func (m *MockStream) Send(reply *idl.HubReply) error {
 	}
 }
 
+func (m *MockStream) Context() context.Context {
+	return context.Background()
+}
+
 func (m *MockStream) GetBuffer() []*idl.HubReply {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	return m.buf
 }
+
+// MockSegmentCommandStream replays the replies of an agent RPC running a command for a segment, followed by the error or io.EOF
+type MockSegmentCommandStream struct {
+	grpc.ClientStream
+	Replies []*idl.SegmentCommandReply
+	Err     error
+}
+
+func NewMockSegmentCommandStream(err error, replies ...*idl.SegmentCommandReply) *MockSegmentCommandStream {
+	return &MockSegmentCommandStream{
+		Replies: replies,
+		Err:     err,
+	}
+}
+
+func (m *MockSegmentCommandStream) Recv() (*idl.SegmentCommandReply, error) {
+	if len(m.Replies) > 0 {
+		reply := m.Replies[0]
+		m.Replies = m.Replies[1:]
+
+		return reply, nil
+	}
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+
+	return nil, io.EOF
+}
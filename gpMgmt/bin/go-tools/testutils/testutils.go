@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -111,6 +112,17 @@ func (s *MockCredentials) LoadClientCredentials() (credentials.TransportCredenti
 	return s.TlsConnection, s.Err
 }
 
+// MockExpiringCredentials additionally reports the expiry of the served certificate
+type MockExpiringCredentials struct {
+	MockCredentials
+	Expiry    time.Time
+	ExpiryErr error
+}
+
+func (s *MockExpiringCredentials) GetCertificateExpiry() (time.Time, error) {
+	return s.Expiry, s.ExpiryErr
+}
+
 func (s *MockCredentials) SetCredsError(errMsg string) {
 	s.Err = errors.New(errMsg)
 }
@@ -0,0 +1,428 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var sensitiveFieldPattern = regexp.MustCompile(`(?i)password|secret|token`)
+
+const redacted = "REDACTED"
+
+const (
+	AuditEventStarted   = "started"
+	AuditEventCompleted = "completed"
+)
+
+/*
+AuditRecord is a single entry of the audit log, written as one line of JSON.
+Every audited call writes a started record with the request before it is
+handled, and a completed record with the duration and result afterwards, so
+that the calls which never complete are audited as well. Both records carry the
+same operation ID.
+*/
+type AuditRecord struct {
+	Time             time.Time       `json:"time"`
+	Event            string          `json:"event"`
+	OperationID      string          `json:"operationId"`
+	Server           string          `json:"server"`
+	Method           string          `json:"method"`
+	Caller           string          `json:"caller"`
+	CallerIdentities []string        `json:"callerIdentities,omitempty"`
+	Address          string          `json:"address,omitempty"`
+	Request          json.RawMessage `json:"request,omitempty"`
+	DurationMs       int64           `json:"durationMs,omitempty"`
+	Result           string          `json:"result,omitempty"`
+	Code             string          `json:"code,omitempty"`
+	Error            string          `json:"error,omitempty"`
+	TraceID          string          `json:"traceId,omitempty"`
+}
+
+// NewOperationID returns a random ID for an operation
+func NewOperationID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+type operationIDKey struct{}
+
+// WithOperationID returns a context carrying the ID of the operation, under which it is audited
+func WithOperationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, operationIDKey{}, id)
+}
+
+// GetOperationID returns the ID of the operation in the context, or an empty string if there is none
+func GetOperationID(ctx context.Context) string {
+	id, _ := ctx.Value(operationIDKey{}).(string)
+
+	return id
+}
+
+/*
+AuditLogger appends the audit records to a file. The file is only ever opened in
+append mode and is created on the first record, so that services which do not
+receive any audited call do not create it.
+*/
+type AuditLogger struct {
+	path string
+
+	mutex sync.Mutex
+	file  *os.File
+}
+
+func NewAuditLogger(path string) *AuditLogger {
+	return &AuditLogger{path: path}
+}
+
+/*
+Log appends the record to the audit log. If the audit log can not be written,
+the record is written to the service log instead so that it is not lost.
+*/
+func (a *AuditLogger) Log(record *AuditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		gplog.Error("failed to encode the audit record for %s: %v", record.Method, err)
+		return
+	}
+	line = append(line, '\n')
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.file == nil {
+		a.file, err = System.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			gplog.Error("failed to open the audit log %s: %v, audit record: %s", a.path, err, line)
+			return
+		}
+	}
+
+	_, err = a.file.Write(line)
+	if err != nil {
+		gplog.Error("failed to write to the audit log %s: %v, audit record: %s", a.path, err, line)
+	}
+}
+
+func (a *AuditLogger) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.file == nil {
+		return nil
+	}
+
+	err := a.file.Close()
+	a.file = nil
+
+	return err
+}
+
+/*
+NewAuditInterceptors returns the server interceptors which write the audit
+records of every call, except for the given read only methods. Each call is
+given an operation ID in its context. They are expected to be the first in the
+chain so that the calls rejected by the other interceptors are audited as well.
+*/
+func NewAuditInterceptors(server string, logger *AuditLogger, readOnlyMethods []string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if slices.Contains(readOnlyMethods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		ctx = WithOperationID(ctx, NewOperationID())
+		logger.Log(newAuditRecord(ctx, server, info.FullMethod, req))
+
+		reply, err := handler(ctx, req)
+		logger.Log(newAuditCompletedRecord(ctx, server, info.FullMethod, start, err))
+
+		return reply, err
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if slices.Contains(readOnlyMethods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		start := time.Now()
+		audited := &auditedStream{
+			ServerStream: ss,
+			ctx:          WithOperationID(ss.Context(), NewOperationID()),
+			server:       server,
+			method:       info.FullMethod,
+			logger:       logger,
+		}
+		err := handler(srv, audited)
+		audited.logStarted(nil)
+		logger.Log(newAuditCompletedRecord(audited.ctx, server, info.FullMethod, start, err))
+
+		return err
+	}
+
+	return unary, stream
+}
+
+/*
+auditedStream writes the started record once the first message is received from
+the client, which is the request of a server streaming call, and carries the
+operation ID of the call in its context.
+*/
+type auditedStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	server string
+	method string
+	logger *AuditLogger
+
+	started sync.Once
+}
+
+func (s *auditedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *auditedStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.logStarted(m)
+	}
+
+	return err
+}
+
+// logStarted writes the started record with the request, unless it was already written
+func (s *auditedStream) logStarted(req interface{}) {
+	s.started.Do(func() {
+		s.logger.Log(newAuditRecord(s.ctx, s.server, s.method, req))
+	})
+}
+
+// newAuditRecord returns the started record of the call
+func newAuditRecord(ctx context.Context, server string, method string, req interface{}) *AuditRecord {
+	record := &AuditRecord{
+		Time:        time.Now().UTC(),
+		Event:       AuditEventStarted,
+		OperationID: GetOperationID(ctx),
+		Server:      server,
+		Method:      method,
+		TraceID:     GetTraceID(ctx),
+	}
+
+	if cert := GetPeerCertificate(ctx); cert != nil {
+		record.Caller = cert.Subject.CommonName
+		record.CallerIdentities = GetCertificateIdentities(cert)
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		record.Address = p.Addr.String()
+	}
+
+	if req != nil {
+		request, err := SanitizeRequest(req)
+		if err != nil {
+			gplog.Warn("failed to sanitize the request of %s for the audit log: %v", method, err)
+		} else {
+			record.Request = request
+		}
+	}
+
+	return record
+}
+
+// newAuditCompletedRecord returns the completed record of the call, with its duration and result
+func newAuditCompletedRecord(ctx context.Context, server string, method string, start time.Time, err error) *AuditRecord {
+	record := newAuditRecord(ctx, server, method, nil)
+	record.Event = AuditEventCompleted
+	record.DurationMs = time.Since(start).Milliseconds()
+	record.Result = "success"
+	record.Code = codes.OK.String()
+
+	if err != nil {
+		record.Result = "failure"
+		record.Code = status.Code(err).String()
+		record.Error = err.Error()
+	}
+
+	return record
+}
+
+// SanitizeRequest encodes the request as JSON with the values of the password, secret and token fields redacted
+func SanitizeRequest(req interface{}) (json.RawMessage, error) {
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields interface{}
+	err = json.Unmarshal(encoded, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(redactSensitiveFields(fields))
+}
+
+func redactSensitiveFields(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveFieldPattern.MatchString(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactSensitiveFields(field)
+			}
+		}
+	case []interface{}:
+		for i, field := range v {
+			v[i] = redactSensitiveFields(field)
+		}
+	}
+
+	return value
+}
+
+/*
+AuthorizationPolicy decides which clients may call which methods. A call is
+allowed if any of the rules matches both one of the identities of the client
+certificate and the method; all other calls are denied.
+*/
+type AuthorizationPolicy struct {
+	Rules []AuthorizationRule `json:"rules"`
+}
+
+/*
+AuthorizationRule allows the clients to call the methods. Clients are matched
+against the certificate common name and subject alternative names, methods
+against either the method name (e.g. "RemoveDirectory") or the full gRPC method
+name (e.g. "/idl.Agent/RemoveDirectory"). Both accept shell patterns like "*".
+*/
+type AuthorizationRule struct {
+	Clients []string `json:"clients"`
+	Methods []string `json:"methods"`
+}
+
+// LoadAuthorizationPolicy reads the policy from a JSON file
+func LoadAuthorizationPolicy(filename string) (*AuthorizationPolicy, error) {
+	contents, err := System.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the authorization policy: %w", err)
+	}
+
+	policy := &AuthorizationPolicy{}
+	err = json.Unmarshal(contents, policy)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the authorization policy %s: %w", filename, err)
+	}
+
+	err = policy.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization policy %s: %w", filename, err)
+	}
+
+	return policy, nil
+}
+
+func (p *AuthorizationPolicy) Validate() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("no rules found")
+	}
+
+	for i, rule := range p.Rules {
+		if len(rule.Clients) == 0 || len(rule.Methods) == 0 {
+			return fmt.Errorf("rule %d must have at least one client and one method", i+1)
+		}
+
+		for _, pattern := range append(slices.Clone(rule.Clients), rule.Methods...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("rule %d has an invalid pattern %q: %w", i+1, pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Allows returns true if a client with any of the identities may call the method
+func (p *AuthorizationPolicy) Allows(identities []string, fullMethod string) bool {
+	method := path.Base(fullMethod)
+	for _, rule := range p.Rules {
+		if matchesAny(rule.Methods, fullMethod, method) && matchesAny(rule.Clients, identities...) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAny(patterns []string, values ...string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if matched, _ := path.Match(pattern, value); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+/*
+NewPolicyAuthorizer returns the server interceptors which only allow the calls
+permitted by the policy. A nil policy allows all the calls.
+*/
+func NewPolicyAuthorizer(server string, policy *AuthorizationPolicy) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		err := authorizeMethod(ctx, server, policy, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := authorizeMethod(ss.Context(), server, policy, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
+
+func authorizeMethod(ctx context.Context, server string, policy *AuthorizationPolicy, fullMethod string) error {
+	if policy == nil {
+		return nil
+	}
+
+	cert := GetPeerCertificate(ctx)
+	if cert == nil {
+		return LogAndReturnError(status.Errorf(codes.Unauthenticated, "client did not present a certificate to the %s", server))
+	}
+
+	if !policy.Allows(GetCertificateIdentities(cert), fullMethod) {
+		return LogAndReturnError(status.Errorf(codes.PermissionDenied, "client certificate %q is not allowed to call %s on the %s",
+			cert.Subject.CommonName, fullMethod, server))
+	}
+
+	return nil
+}
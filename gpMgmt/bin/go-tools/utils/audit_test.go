@@ -0,0 +1,456 @@
+package utils_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/testutils"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+type requestStream struct {
+	contextStream
+	request *idl.MakeClusterRequest
+}
+
+func (s *requestStream) RecvMsg(m interface{}) error {
+	m.(*idl.MakeClusterRequest).ClusterParams = s.request.ClusterParams
+	return nil
+}
+
+func readAuditLog(t *testing.T, path string) []utils.AuditRecord {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer file.Close()
+
+	var records []utils.AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record utils.AuditRecord
+		err = json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestAuditLogger(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("appends the records to the audit log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		err := os.WriteFile(path, []byte(`{"method":"existing"}`+"\n"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		logger := utils.NewAuditLogger(path)
+		logger.Log(&utils.AuditRecord{Method: "first"})
+		logger.Log(&utils.AuditRecord{Method: "second"})
+		err = logger.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var methods []string
+		for _, record := range readAuditLog(t, path) {
+			methods = append(methods, record.Method)
+		}
+
+		expected := []string{"existing", "first", "second"}
+		if !reflect.DeepEqual(methods, expected) {
+			t.Fatalf("got %v, want %v", methods, expected)
+		}
+	})
+
+	t.Run("creates the audit log readable only by the owner", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+
+		logger := utils.NewAuditLogger(path)
+		defer logger.Close()
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want the audit log to be created on the first record", err)
+		}
+
+		logger.Log(&utils.AuditRecord{Method: "first"})
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("got %v, want %v", info.Mode().Perm(), os.FileMode(0600))
+		}
+	})
+
+	t.Run("writes the record to the service log when the audit log can not be opened", func(t *testing.T) {
+		_, _, logfile := testhelper.SetupTestLogger()
+
+		logger := utils.NewAuditLogger(filepath.Join(t.TempDir(), "missing", "audit.log"))
+		logger.Log(&utils.AuditRecord{Method: "/idl.Agent/RemoveDirectory"})
+
+		testutils.AssertLogMessage(t, logfile, `\[ERROR\]:-failed to open the audit log .*, audit record: .*"method":"/idl.Agent/RemoveDirectory"`)
+	})
+}
+
+func TestSanitizeRequest(t *testing.T) {
+	t.Run("redacts the sensitive fields", func(t *testing.T) {
+		request := &idl.MakeClusterRequest{
+			ClusterParams: &idl.ClusterParams{
+				SuPassword: "changeme",
+				DbName:     "gpadmin",
+			},
+		}
+
+		result, err := utils.SanitizeRequest(request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if strings.Contains(string(result), "changeme") {
+			t.Fatalf("got %s, want the password to be redacted", result)
+		}
+
+		expected := `{"clusterParams":{"dbName":"gpadmin","suPassword":"REDACTED"}}`
+		if string(result) != expected {
+			t.Fatalf("got %s, want %s", result, expected)
+		}
+	})
+}
+
+func TestAuditInterceptors(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "cdw"},
+		DNSNames: []string{"cdw.example.com"},
+	}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
+
+	t.Run("records the caller, method, request and result of the calls", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		logger := utils.NewAuditLogger(path)
+		defer logger.Close()
+		unary, _ := utils.NewAuditInterceptors("agent", logger, nil)
+
+		request := &idl.RemoveDirectoryRequest{DataDirectory: "/data/primary/gpseg0"}
+		var operationID string
+		reply, err := unary(ctx, request, &grpc.UnaryServerInfo{FullMethod: "/idl.Agent/RemoveDirectory"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			operationID = utils.GetOperationID(ctx)
+			return "reply", nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reply != "reply" {
+			t.Fatalf("got %v, want reply", reply)
+		}
+
+		expectedErr := status.Error(codes.Internal, "failed to remove the directory")
+		_, err = unary(ctx, request, &grpc.UnaryServerInfo{FullMethod: "/idl.Agent/RemoveDirectory"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, expectedErr
+		})
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+
+		records := readAuditLog(t, path)
+		if len(records) != 4 {
+			t.Fatalf("got %d records, want 4", len(records))
+		}
+
+		for _, record := range records {
+			if record.Server != "agent" || record.Method != "/idl.Agent/RemoveDirectory" {
+				t.Fatalf("got %s %s, want agent /idl.Agent/RemoveDirectory", record.Server, record.Method)
+			}
+			if record.Caller != "cdw" || !reflect.DeepEqual(record.CallerIdentities, []string{"cdw", "cdw.example.com"}) {
+				t.Fatalf("got %s %v, want cdw [cdw cdw.example.com]", record.Caller, record.CallerIdentities)
+			}
+			if record.Address != "10.0.0.1:1234" {
+				t.Fatalf("got %s, want 10.0.0.1:1234", record.Address)
+			}
+
+		}
+
+		for _, record := range []utils.AuditRecord{records[0], records[2]} {
+			expectedRequest := `{"dataDirectory":"/data/primary/gpseg0"}`
+			if record.Event != utils.AuditEventStarted || string(record.Request) != expectedRequest || record.Result != "" {
+				t.Fatalf("got %+v, want a started record with the request %s", record, expectedRequest)
+			}
+		}
+
+		if records[1].Event != utils.AuditEventCompleted || records[1].Result != "success" || records[1].Code != "OK" || records[1].Error != "" {
+			t.Fatalf("got %+v, want a successful record", records[1])
+		}
+		if records[3].Event != utils.AuditEventCompleted || records[3].Result != "failure" || records[3].Code != "Internal" || !strings.Contains(records[3].Error, "failed to remove the directory") {
+			t.Fatalf("got %+v, want a failed record", records[3])
+		}
+
+		if operationID == "" || records[0].OperationID != operationID || records[1].OperationID != operationID {
+			t.Fatalf("got %s and %s, want the operation ID of the call %q", records[0].OperationID, records[1].OperationID, operationID)
+		}
+		if records[2].OperationID == operationID || records[2].OperationID != records[3].OperationID {
+			t.Fatalf("got %s and %s, want the same new operation ID", records[2].OperationID, records[3].OperationID)
+		}
+	})
+
+	t.Run("records the start of the calls before handling them", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		logger := utils.NewAuditLogger(path)
+		defer logger.Close()
+		unary, _ := utils.NewAuditInterceptors("agent", logger, nil)
+
+		var started []utils.AuditRecord
+		_, err := unary(ctx, &idl.RemoveDirectoryRequest{DataDirectory: "/data/primary/gpseg0"}, &grpc.UnaryServerInfo{FullMethod: "/idl.Agent/RemoveDirectory"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			started = readAuditLog(t, path)
+			return "reply", nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(started) != 1 || started[0].Event != utils.AuditEventStarted || started[0].Caller != "cdw" {
+			t.Fatalf("got %+v, want the started record of the call", started)
+		}
+	})
+
+	t.Run("does not record the read only calls", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		logger := utils.NewAuditLogger(path)
+		defer logger.Close()
+		unary, _ := utils.NewAuditInterceptors("agent", logger, []string{"/idl.Agent/Status"})
+
+		_, err := unary(ctx, &idl.StatusAgentRequest{}, &grpc.UnaryServerInfo{FullMethod: "/idl.Agent/Status"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "reply", nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want no audit log", err)
+		}
+	})
+
+	t.Run("records the request of the streaming calls", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		logger := utils.NewAuditLogger(path)
+		defer logger.Close()
+		_, stream := utils.NewAuditInterceptors("hub", logger, nil)
+
+		ss := &requestStream{
+			contextStream: contextStream{ctx: ctx},
+			request:       &idl.MakeClusterRequest{ClusterParams: &idl.ClusterParams{SuPassword: "changeme"}},
+		}
+		var operationID string
+		var started []utils.AuditRecord
+		err := stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/idl.Hub/MakeCluster"}, func(srv interface{}, stream grpc.ServerStream) error {
+			operationID = utils.GetOperationID(stream.Context())
+			err := stream.RecvMsg(&idl.MakeClusterRequest{})
+			started = readAuditLog(t, path)
+
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(started) != 1 {
+			t.Fatalf("got %d records, want the started record once the request is received", len(started))
+		}
+
+		records := readAuditLog(t, path)
+		if len(records) != 2 {
+			t.Fatalf("got %d records, want 2", len(records))
+		}
+
+		expectedRequest := `{"clusterParams":{"suPassword":"REDACTED"}}`
+		if records[0].Event != utils.AuditEventStarted || records[0].Method != "/idl.Hub/MakeCluster" || string(records[0].Request) != expectedRequest {
+			t.Fatalf("got %s %s %s, want started /idl.Hub/MakeCluster %s", records[0].Event, records[0].Method, records[0].Request, expectedRequest)
+		}
+		if records[1].Event != utils.AuditEventCompleted || records[1].Result != "success" {
+			t.Fatalf("got %+v, want a successful record", records[1])
+		}
+		if operationID == "" || records[0].OperationID != operationID || records[1].OperationID != operationID {
+			t.Fatalf("got %s and %s, want the operation ID of the call %q", records[0].OperationID, records[1].OperationID, operationID)
+		}
+	})
+
+	t.Run("records the start of the streaming calls rejected before receiving the request", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		logger := utils.NewAuditLogger(path)
+		defer logger.Close()
+		_, stream := utils.NewAuditInterceptors("hub", logger, nil)
+
+		expectedErr := status.Error(codes.PermissionDenied, "not allowed")
+		err := stream(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/idl.Hub/MakeCluster"}, func(srv interface{}, stream grpc.ServerStream) error {
+			return expectedErr
+		})
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %v, want %v", err, expectedErr)
+		}
+
+		records := readAuditLog(t, path)
+		if len(records) != 2 {
+			t.Fatalf("got %d records, want 2", len(records))
+		}
+
+		if records[0].Event != utils.AuditEventStarted || records[0].Request != nil {
+			t.Fatalf("got %+v, want a started record without the request", records[0])
+		}
+		if records[1].Event != utils.AuditEventCompleted || records[1].Code != "PermissionDenied" {
+			t.Fatalf("got %+v, want a rejected record", records[1])
+		}
+	})
+}
+
+func TestLoadAuthorizationPolicy(t *testing.T) {
+	writePolicy := func(t *testing.T, contents string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "policy.json")
+		err := os.WriteFile(path, []byte(contents), 0644)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return path
+	}
+
+	t.Run("loads the policy", func(t *testing.T) {
+		path := writePolicy(t, `{"rules": [{"clients": ["cdw"], "methods": ["*"]}, {"clients": ["*"], "methods": ["Status*"]}]}`)
+
+		policy, err := utils.LoadAuthorizationPolicy(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := &utils.AuthorizationPolicy{
+			Rules: []utils.AuthorizationRule{
+				{Clients: []string{"cdw"}, Methods: []string{"*"}},
+				{Clients: []string{"*"}, Methods: []string{"Status*"}},
+			},
+		}
+		if !reflect.DeepEqual(policy, expected) {
+			t.Fatalf("got %+v, want %+v", policy, expected)
+		}
+	})
+
+	cases := []struct {
+		name     string
+		contents string
+		expected string
+	}{
+		{"errors out when the policy is not valid JSON", `{"rules": [`, "could not parse the authorization policy"},
+		{"errors out when the policy has no rules", `{"rules": []}`, "no rules found"},
+		{"errors out when a rule has no methods", `{"rules": [{"clients": ["cdw"]}]}`, "rule 1 must have at least one client and one method"},
+		{"errors out when a rule has an invalid pattern", `{"rules": [{"clients": ["cdw"], "methods": ["["]}]}`, `rule 1 has an invalid pattern "["`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := utils.LoadAuthorizationPolicy(writePolicy(t, tc.contents))
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Fatalf("got %v, want %s", err, tc.expected)
+			}
+		})
+	}
+
+	t.Run("errors out when the policy can not be read", func(t *testing.T) {
+		_, err := utils.LoadAuthorizationPolicy(filepath.Join(t.TempDir(), "policy.json"))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func TestPolicyAuthorizer(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	ctxWithCert := func(cn string) context.Context {
+		state := tls.ConnectionState{}
+		if cn != "" {
+			state.PeerCertificates = []*x509.Certificate{{Subject: pkix.Name{CommonName: cn}, DNSNames: []string{cn + ".example.com"}}}
+		}
+
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "reply", nil
+	}
+	policy := &utils.AuthorizationPolicy{
+		Rules: []utils.AuthorizationRule{
+			{Clients: []string{"cdw"}, Methods: []string{"*"}},
+			{Clients: []string{"*.example.com"}, Methods: []string{"Status", "/idl.Agent/GetHostName"}},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		client   string
+		method   string
+		expected codes.Code
+	}{
+		{"allows all the methods to the client matching the common name", "cdw", "/idl.Agent/RemoveDirectory", codes.OK},
+		{"allows the methods matching the method name", "sdw1", "/idl.Agent/Status", codes.OK},
+		{"allows the methods matching the full method name", "sdw1", "/idl.Agent/GetHostName", codes.OK},
+		{"denies the methods not allowed to the client", "sdw1", "/idl.Agent/RemoveDirectory", codes.PermissionDenied},
+		{"denies the clients without a certificate", "", "/idl.Agent/Status", codes.Unauthenticated},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			unary, stream := utils.NewPolicyAuthorizer("agent", policy)
+
+			_, err := unary(ctxWithCert(tc.client), nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			if status.Code(err) != tc.expected {
+				t.Fatalf("got %v, want %v", err, tc.expected)
+			}
+
+			err = stream(nil, contextStream{ctx: ctxWithCert(tc.client)}, &grpc.StreamServerInfo{FullMethod: tc.method}, func(srv interface{}, stream grpc.ServerStream) error {
+				return nil
+			})
+			if status.Code(err) != tc.expected {
+				t.Fatalf("got %v, want %v", err, tc.expected)
+			}
+		})
+	}
+
+	t.Run("allows all the calls when there is no policy", func(t *testing.T) {
+		unary, _ := utils.NewPolicyAuthorizer("agent", nil)
+
+		_, err := unary(ctxWithCert(""), nil, &grpc.UnaryServerInfo{FullMethod: "/idl.Agent/RemoveDirectory"}, handler)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
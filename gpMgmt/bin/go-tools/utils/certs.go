@@ -0,0 +1,447 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gpdb/gp/constants"
+)
+
+var LookupIP = net.LookupIP
+
+const certOrganization = "Greenplum"
+
+type CertificateAuthority struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+type CertificateInfo struct {
+	Path        string
+	Subject     string
+	DNSNames    []string
+	IPAddresses []net.IP
+	NotBefore   time.Time
+	NotAfter    time.Time
+}
+
+/*
+CreateCertificateAuthority generates a new self-signed CA which is
+used to sign the certificates of the hub and the agents.
+*/
+func CreateCertificateAuthority(commonName string, validityDays int) (*CertificateAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA private key: %w", err)
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{certOrganization},
+			CommonName:   commonName,
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(0, 0, validityDays),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	return &CertificateAuthority{Cert: cert, Key: key}, nil
+}
+
+// LoadCertificateAuthority reads the CA certificate and its private key from the given PEM files
+func LoadCertificateAuthority(certPath, keyPath string) (*CertificateAuthority, error) {
+	cert, err := ReadCertificate(certPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate %s is not a CA certificate", certPath)
+	}
+
+	contents, err := System.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA private key %s: %w", keyPath, err)
+	}
+
+	block, _ := pem.Decode(contents)
+	if block == nil {
+		return nil, fmt.Errorf("failed to find any PEM data in %s", keyPath)
+	}
+
+	key, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA private key %s: %w", keyPath, err)
+	}
+
+	return &CertificateAuthority{Cert: cert, Key: key}, nil
+}
+
+// Write stores the CA certificate and private key in the given directory
+func (ca *CertificateAuthority) Write(dir string) error {
+	keyDer, err := x509.MarshalPKCS8PrivateKey(ca.Key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal CA private key: %w", err)
+	}
+
+	return writeKeyPair(dir, constants.CACertFileName, constants.CAKeyFileName, ca.Cert.Raw, keyDer)
+}
+
+/*
+IssueHostCertificates issues a server and a client certificate for the given
+host and writes them along with their private keys in the given directory.
+The certificates carry the hostname and its resolved addresses as subject
+alternative names, so that the host can be reached by any of them. Those of the
+hub host carry localhost as well, as the CLI connects to the hub on localhost.
+*/
+func (ca *CertificateAuthority) IssueHostCertificates(hostname string, hub bool, validityDays int, dir string) error {
+	dnsNames, ipAddresses := GetSubjectAltNames(hostname, hub)
+
+	certDer, keyDer, err := ca.issueCertificate(hostname, dnsNames, ipAddresses, validityDays,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth})
+	if err != nil {
+		return fmt.Errorf("failed to issue server certificate for host %s: %w", hostname, err)
+	}
+
+	err = writeKeyPair(dir, constants.ServerCertFileName, constants.ServerKeyFileName, certDer, keyDer)
+	if err != nil {
+		return err
+	}
+
+	certDer, keyDer, err = ca.issueCertificate(hostname, dnsNames, ipAddresses, validityDays,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})
+	if err != nil {
+		return fmt.Errorf("failed to issue client certificate for host %s: %w", hostname, err)
+	}
+
+	return writeKeyPair(dir, constants.ClientCertFileName, constants.ClientKeyFileName, certDer, keyDer)
+}
+
+func (ca *CertificateAuthority) issueCertificate(commonName string, dnsNames []string, ipAddresses []net.IP, validityDays int, usage []x509.ExtKeyUsage) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{certOrganization},
+			CommonName:   commonName,
+		},
+		DNSNames:    dnsNames,
+		IPAddresses: ipAddresses,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.AddDate(0, 0, validityDays),
+		KeyUsage:    x509.KeyUsageDigitalSignature, // the keys are ECDSA, which only sign
+		ExtKeyUsage: usage,
+	}
+	if template.NotAfter.After(ca.Cert.NotAfter) {
+		template.NotAfter = ca.Cert.NotAfter
+	}
+
+	certDer, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return certDer, keyDer, nil
+}
+
+/*
+GetSubjectAltNames returns the DNS names and IP addresses under which a host is
+reachable. An entry of the host list can either be a hostname or an address.
+Only the hub host is reachable on localhost and the loopback addresses, so that
+the certificate of another host can not be used to impersonate the hub.
+*/
+func GetSubjectAltNames(host string, hub bool) ([]string, []net.IP) {
+	dnsNames := []string{}
+	ipAddresses := []net.IP{}
+
+	if ip := net.ParseIP(host); ip != nil {
+		ipAddresses = append(ipAddresses, ip)
+	} else {
+		dnsNames = append(dnsNames, host)
+
+		addrs, err := LookupIP(host)
+		if err != nil {
+			gplog.Warn("could not resolve the addresses of host %s, the certificate will only be valid for its hostname: %v", host, err)
+		}
+		ipAddresses = append(ipAddresses, addrs...)
+	}
+
+	if !hub {
+		return dnsNames, ipAddresses
+	}
+
+	if host != "localhost" {
+		dnsNames = append(dnsNames, "localhost")
+	}
+	for _, loopback := range []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback} {
+		if !containsIP(ipAddresses, loopback) {
+			ipAddresses = append(ipAddresses, loopback)
+		}
+	}
+
+	return dnsNames, ipAddresses
+}
+
+// ReadCertificate parses the first certificate found in the given PEM file
+func ReadCertificate(certPath string) (*x509.Certificate, error) {
+	contents, err := System.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate %s: %w", certPath, err)
+	}
+
+	block, _ := pem.Decode(contents)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("failed to find any PEM certificate data in %s", certPath)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate %s: %w", certPath, err)
+	}
+
+	return cert, nil
+}
+
+// GetCertificateInfo returns the details of the certificate in the given PEM file
+func GetCertificateInfo(certPath string) (*CertificateInfo, error) {
+	cert, err := ReadCertificate(certPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CertificateInfo{
+		Path:        certPath,
+		Subject:     cert.Subject.CommonName,
+		DNSNames:    cert.DNSNames,
+		IPAddresses: cert.IPAddresses,
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+	}, nil
+}
+
+// ExpiryStatus describes whether the certificate is valid, about to expire or expired at the given time
+func (c *CertificateInfo) ExpiryStatus(now time.Time) string {
+	return GetExpiryStatus(c.NotAfter, now)
+}
+
+// GetExpiryStatus describes whether a certificate expiring at notAfter is valid, about to expire or expired at the given time
+func GetExpiryStatus(notAfter time.Time, now time.Time) string {
+	switch {
+	case now.After(notAfter):
+		return "expired"
+	case now.AddDate(0, 0, constants.CertExpiryWarningDays).After(notAfter):
+		return "expiring soon"
+	default:
+		return "valid"
+	}
+}
+
+// DisplayCertificateExpiry prints the subject, expiry date and status of each of the given certificates
+func DisplayCertificateExpiry(outfile io.Writer, certs []*CertificateInfo, now time.Time) {
+	w := new(tabwriter.Writer)
+	w.Init(outfile, 0, 8, 2, '\t', 0)
+
+	fmt.Fprintln(w, "CERTIFICATE\tSUBJECT\tEXPIRES\tSTATUS")
+	for _, c := range certs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Path, c.Subject, c.NotAfter.Format(time.RFC3339), c.ExpiryStatus(now))
+	}
+	w.Flush()
+}
+
+// gpSSH runs the command on the host
+type gpSSH struct {
+	Host    string
+	Command []string
+}
+
+func (cmd *gpSSH) BuildExecCommand(gpHome string) *exec.Cmd {
+	args := append([]string{"-h", cmd.Host}, cmd.Command...)
+
+	return System.ExecCommand(GetGpUtilityPath(gpHome, constants.GpSSH), args...)
+}
+
+// gpSync copies the local files to the directory of the host
+type gpSync struct {
+	Host    string
+	Sources []string
+	DestDir string
+}
+
+func (cmd *gpSync) BuildExecCommand(gpHome string) *exec.Cmd {
+	args := append([]string{"-h", cmd.Host}, cmd.Sources...)
+	args = append(args, fmt.Sprintf("=:%s/", cmd.DestDir))
+
+	return System.ExecCommand(GetGpUtilityPath(gpHome, constants.GpSync), args...)
+}
+
+/*
+DistributeCertificates copies the CA certificate and the certificates issued for
+each host from the local certificate directory to the same directory on the
+respective hosts. The CA private key is never copied. Uses gpssh and gpsync.
+*/
+func DistributeCertificates(gpHome string, certsDir string, hostnames []string) error {
+	var wg sync.WaitGroup
+	errs := make(chan error, len(hostnames))
+
+	for _, host := range hostnames {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+
+			hostDir := filepath.Join(certsDir, host)
+			sources := []string{filepath.Join(certsDir, constants.CACertFileName)}
+			for _, name := range []string{constants.ServerCertFileName, constants.ServerKeyFileName, constants.ClientCertFileName, constants.ClientKeyFileName} {
+				sources = append(sources, filepath.Join(hostDir, name))
+			}
+
+			for _, source := range sources {
+				if _, err := System.Stat(source); err != nil {
+					errs <- fmt.Errorf("missing certificate for host %s: %w", host, err)
+					return
+				}
+			}
+
+			mkdir := &gpSSH{Host: host, Command: []string{"mkdir", "-p", "-m", "700", certsDir}}
+			output, err := RunGpSourcedCommand(context.Background(), mkdir, gpHome)
+			if err != nil {
+				errs <- fmt.Errorf("could not create certificate directory %s on host %s: %w, Command Output: %s", certsDir, host, err, output)
+				return
+			}
+
+			output, err = RunGpSourcedCommand(context.Background(), &gpSync{Host: host, Sources: sources, DestDir: certsDir}, gpHome)
+			if err != nil {
+				errs <- fmt.Errorf("could not copy certificates to host %s: %w, Command Output: %s", host, err, output)
+				return
+			}
+
+			gplog.Verbose("Copied certificates to %s on host %s", certsDir, host)
+		}(host)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var err error
+	for e := range errs {
+		err = errors.Join(err, e)
+	}
+
+	return err
+}
+
+func writeKeyPair(dir, certFileName, keyFileName string, certDer, keyDer []byte) error {
+	err := System.MkdirAll(dir, 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate directory %s: %w", dir, err)
+	}
+
+	certPath := filepath.Join(dir, certFileName)
+	err = System.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDer}), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write certificate %s: %w", certPath, err)
+	}
+
+	keyPath := filepath.Join(dir, keyFileName)
+	err = System.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write private key %s: %w", keyPath, err)
+	}
+
+	return nil
+}
+
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unsupported private key format")
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial number: %w", err)
+	}
+
+	return serial, nil
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, i := range ips {
+		if i.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPrivateKeyFile reports whether the given PEM file name holds a private key rather than a certificate
+func IsPrivateKeyFile(name string) bool {
+	return strings.HasSuffix(name, "-key.pem")
+}
@@ -0,0 +1,418 @@
+package utils_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/testutils/exectest"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+func TestCertificateAuthority(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	utils.LookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("192.168.0.10")}, nil
+	}
+	defer func() { utils.LookupIP = net.LookupIP }()
+
+	t.Run("creates, writes and loads the CA", func(t *testing.T) {
+		dir := t.TempDir()
+
+		ca, err := utils.CreateCertificateAuthority("test CA", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = ca.Write(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		info, err := os.Stat(filepath.Join(dir, constants.CAKeyFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("got %o, want %o", info.Mode().Perm(), 0600)
+		}
+
+		loaded, err := utils.LoadCertificateAuthority(filepath.Join(dir, constants.CACertFileName), filepath.Join(dir, constants.CAKeyFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !loaded.Cert.Equal(ca.Cert) {
+			t.Fatalf("got %v, want %v", loaded.Cert.Subject, ca.Cert.Subject)
+		}
+
+		if loaded.Cert.Subject.CommonName != "test CA" || !loaded.Cert.IsCA {
+			t.Fatalf("unexpected CA certificate %+v", loaded.Cert.Subject)
+		}
+	})
+
+	t.Run("issues host certificates signed by the CA", func(t *testing.T) {
+		dir := t.TempDir()
+
+		ca, err := utils.CreateCertificateAuthority("test CA", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = ca.IssueHostCertificates("sdw1", false, 5, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.Cert)
+
+		cases := []struct {
+			certFile string
+			keyFile  string
+			usage    x509.ExtKeyUsage
+		}{
+			{constants.ServerCertFileName, constants.ServerKeyFileName, x509.ExtKeyUsageServerAuth},
+			{constants.ClientCertFileName, constants.ClientKeyFileName, x509.ExtKeyUsageClientAuth},
+		}
+		for _, tc := range cases {
+			_, err = tls.LoadX509KeyPair(filepath.Join(dir, tc.certFile), filepath.Join(dir, tc.keyFile))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			cert, err := utils.ReadCertificate(filepath.Join(dir, tc.certFile))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, err = cert.Verify(x509.VerifyOptions{
+				DNSName:   "sdw1",
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{tc.usage},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error verifying %s: %v", tc.certFile, err)
+			}
+
+			if cert.Subject.CommonName != "sdw1" {
+				t.Fatalf("got %s, want sdw1", cert.Subject.CommonName)
+			}
+
+			if cert.KeyUsage != x509.KeyUsageDigitalSignature {
+				t.Fatalf("got key usage %v, want %v", cert.KeyUsage, x509.KeyUsageDigitalSignature)
+			}
+
+			// only the certificates of the hub host are valid for localhost
+			_, err = cert.Verify(x509.VerifyOptions{
+				DNSName:   "localhost",
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{tc.usage},
+			})
+			if err == nil {
+				t.Fatalf("expected %s of a segment host not to be valid for localhost", tc.certFile)
+			}
+
+			if cert.NotAfter.After(time.Now().AddDate(0, 0, 6)) {
+				t.Fatalf("certificate %s is valid until %s, want less than 6 days", tc.certFile, cert.NotAfter)
+			}
+		}
+	})
+
+	t.Run("does not issue certificates which outlive the CA", func(t *testing.T) {
+		dir := t.TempDir()
+
+		ca, err := utils.CreateCertificateAuthority("test CA", 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = ca.IssueHostCertificates("sdw1", false, 365, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cert, err := utils.ReadCertificate(filepath.Join(dir, constants.ServerCertFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !cert.NotAfter.Equal(ca.Cert.NotAfter) {
+			t.Fatalf("got %s, want %s", cert.NotAfter, ca.Cert.NotAfter)
+		}
+	})
+
+	t.Run("errors out when loading a certificate which is not a CA", func(t *testing.T) {
+		dir := t.TempDir()
+
+		ca, err := utils.CreateCertificateAuthority("test CA", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = ca.IssueHostCertificates("sdw1", false, 5, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		certPath := filepath.Join(dir, constants.ServerCertFileName)
+		_, err = utils.LoadCertificateAuthority(certPath, filepath.Join(dir, constants.ServerKeyFileName))
+		expected := "certificate " + certPath + " is not a CA certificate"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when the certificate is not a valid PEM file", func(t *testing.T) {
+		_, err := utils.ReadCertificate("/dev/null")
+		expected := "failed to find any PEM certificate data in /dev/null"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
+
+func TestGetSubjectAltNames(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("includes the hostname, its addresses and localhost for the hub host", func(t *testing.T) {
+		utils.LookupIP = func(host string) ([]net.IP, error) {
+			return []net.IP{net.ParseIP("192.168.0.10")}, nil
+		}
+		defer func() { utils.LookupIP = net.LookupIP }()
+
+		dnsNames, ips := utils.GetSubjectAltNames("sdw1", true)
+
+		expectedDNSNames := []string{"sdw1", "localhost"}
+		if !reflect.DeepEqual(dnsNames, expectedDNSNames) {
+			t.Fatalf("got %v, want %v", dnsNames, expectedDNSNames)
+		}
+
+		expectedIPs := []net.IP{net.ParseIP("192.168.0.10"), net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+		if !reflect.DeepEqual(ips, expectedIPs) {
+			t.Fatalf("got %v, want %v", ips, expectedIPs)
+		}
+	})
+
+	t.Run("does not include localhost for the other hosts", func(t *testing.T) {
+		utils.LookupIP = func(host string) ([]net.IP, error) {
+			return []net.IP{net.ParseIP("192.168.0.10")}, nil
+		}
+		defer func() { utils.LookupIP = net.LookupIP }()
+
+		dnsNames, ips := utils.GetSubjectAltNames("sdw1", false)
+
+		expectedDNSNames := []string{"sdw1"}
+		if !reflect.DeepEqual(dnsNames, expectedDNSNames) {
+			t.Fatalf("got %v, want %v", dnsNames, expectedDNSNames)
+		}
+
+		expectedIPs := []net.IP{net.ParseIP("192.168.0.10")}
+		if !reflect.DeepEqual(ips, expectedIPs) {
+			t.Fatalf("got %v, want %v", ips, expectedIPs)
+		}
+	})
+
+	t.Run("uses the address as is when an address is provided", func(t *testing.T) {
+		utils.LookupIP = func(host string) ([]net.IP, error) {
+			t.Fatalf("unexpected lookup for %s", host)
+			return nil, nil
+		}
+		defer func() { utils.LookupIP = net.LookupIP }()
+
+		dnsNames, ips := utils.GetSubjectAltNames("10.0.0.1", true)
+
+		expectedDNSNames := []string{"localhost"}
+		if !reflect.DeepEqual(dnsNames, expectedDNSNames) {
+			t.Fatalf("got %v, want %v", dnsNames, expectedDNSNames)
+		}
+
+		expectedIPs := []net.IP{net.ParseIP("10.0.0.1"), net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+		if !reflect.DeepEqual(ips, expectedIPs) {
+			t.Fatalf("got %v, want %v", ips, expectedIPs)
+		}
+	})
+
+	t.Run("only uses the hostname when it can not be resolved", func(t *testing.T) {
+		utils.LookupIP = func(host string) ([]net.IP, error) {
+			return nil, errors.New("error")
+		}
+		defer func() { utils.LookupIP = net.LookupIP }()
+
+		dnsNames, ips := utils.GetSubjectAltNames("sdw1", true)
+
+		expectedDNSNames := []string{"sdw1", "localhost"}
+		if !reflect.DeepEqual(dnsNames, expectedDNSNames) {
+			t.Fatalf("got %v, want %v", dnsNames, expectedDNSNames)
+		}
+
+		expectedIPs := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+		if !reflect.DeepEqual(ips, expectedIPs) {
+			t.Fatalf("got %v, want %v", ips, expectedIPs)
+		}
+	})
+}
+
+func TestCertificateExpiry(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		notAfter time.Time
+		expected string
+	}{
+		{now.AddDate(0, 0, 100), "valid"},
+		{now.AddDate(0, 0, 10), "expiring soon"},
+		{now.AddDate(0, 0, -1), "expired"},
+	}
+	for _, tc := range cases {
+		t.Run("reports the expiry status as "+tc.expected, func(t *testing.T) {
+			info := &utils.CertificateInfo{NotAfter: tc.notAfter}
+			if result := info.ExpiryStatus(now); result != tc.expected {
+				t.Fatalf("got %s, want %s", result, tc.expected)
+			}
+		})
+	}
+
+	t.Run("displays the certificate expiry", func(t *testing.T) {
+		notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		certs := []*utils.CertificateInfo{
+			{Path: "/certs/ca-cert.pem", Subject: "gpca", NotAfter: notAfter},
+		}
+
+		var buf bytes.Buffer
+		utils.DisplayCertificateExpiry(&buf, certs, notAfter.AddDate(-1, 0, 0))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("got %d lines, want 2: %s", len(lines), buf.String())
+		}
+
+		expected := []string{"/certs/ca-cert.pem", "gpca", "2030-01-02T03:04:05Z", "valid"}
+		if !reflect.DeepEqual(strings.Fields(lines[1]), expected) {
+			t.Fatalf("got %v, want %v", strings.Fields(lines[1]), expected)
+		}
+	})
+}
+
+func TestDistributeCertificates(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	createHostCerts := func(t *testing.T, hosts ...string) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		ca, err := utils.CreateCertificateAuthority("test CA", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = ca.Write(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, host := range hosts {
+			err = ca.IssueHostCertificates(host, false, 5, filepath.Join(dir, host))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		return dir
+	}
+
+	t.Run("copies the CA certificate and the host certificates to each host", func(t *testing.T) {
+		dir := createHostCerts(t, "sdw1")
+
+		var commands []string
+		utils.System.ExecCommand = exectest.NewCommandWithVerifier(exectest.Success, func(utility string, args ...string) {
+			commands = append(commands, strings.Join(append([]string{utility}, args...), " "))
+		})
+		defer utils.ResetSystemFunctions()
+
+		err := utils.DistributeCertificates("gpHome", dir, []string{"sdw1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		sources := strings.Join([]string{
+			filepath.Join(dir, constants.CACertFileName),
+			filepath.Join(dir, "sdw1", constants.ServerCertFileName),
+			filepath.Join(dir, "sdw1", constants.ServerKeyFileName),
+			filepath.Join(dir, "sdw1", constants.ClientCertFileName),
+			filepath.Join(dir, "sdw1", constants.ClientKeyFileName),
+		}, " ")
+		expected := []string{
+			"gpHome/bin/gpssh -h sdw1 mkdir -p -m 700 " + dir,
+			"gpHome/bin/gpsync -h sdw1 " + sources + " =:" + dir + "/",
+		}
+		if len(commands) != 2*len(expected) {
+			t.Fatalf("got %d commands, want %d", len(commands), 2*len(expected))
+		}
+		for i, command := range expected {
+			if commands[2*i] != command {
+				t.Fatalf("got %q, want %q", commands[2*i], command)
+			}
+
+			expectedPrefix := "bash -c source gpHome/greenplum_path.sh && "
+			if !strings.HasPrefix(commands[2*i+1], expectedPrefix) {
+				t.Fatalf("got %q, want prefix %q", commands[2*i+1], expectedPrefix)
+			}
+		}
+	})
+
+	t.Run("errors out when the certificates are not issued for a host", func(t *testing.T) {
+		dir := createHostCerts(t, "sdw1")
+
+		utils.System.ExecCommand = exectest.NewCommand(exectest.Success)
+		defer utils.ResetSystemFunctions()
+
+		err := utils.DistributeCertificates("gpHome", dir, []string{"sdw1", "sdw2"})
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want %v", err, os.ErrNotExist)
+		}
+
+		expected := "missing certificate for host sdw2"
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when not able to copy the certificates", func(t *testing.T) {
+		dir := createHostCerts(t, "sdw1")
+
+		var syncing bool
+		utils.System.ExecCommand = func(name string, args ...string) *exec.Cmd {
+			if name == "bash" && syncing {
+				return exectest.NewCommand(exectest.Failure)(name, args...)
+			}
+			syncing = strings.HasSuffix(name, "gpsync")
+
+			return exectest.NewCommand(exectest.Success)(name, args...)
+		}
+		defer utils.ResetSystemFunctions()
+
+		err := utils.DistributeCertificates("gpHome", dir, []string{"sdw1"})
+		var expectedErr *exec.ExitError
+		if !errors.As(err, &expectedErr) {
+			t.Fatalf("got %T, want %T", err, expectedErr)
+		}
+
+		expected := "could not copy certificates to host sdw1"
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -9,8 +10,11 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"syscall"
+	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type CommandBuilder interface {
@@ -28,7 +32,7 @@ func NewGpSourcedCommand(cmdBuilder CommandBuilder, gpHome string) *exec.Cmd {
 	return System.ExecCommand("bash", "-c", fmt.Sprintf("source %s && %s", gpSourceFilePath, cmd.String()))
 }
 
-func runCommand(cmd *exec.Cmd, filename ...string) (*bytes.Buffer, error) {
+func runCommand(ctx context.Context, cmd *exec.Cmd, filename ...string) (*bytes.Buffer, error) {
 	var outfile *os.File
 	var err error
 
@@ -47,16 +51,24 @@ func runCommand(cmd *exec.Cmd, filename ...string) (*bytes.Buffer, error) {
 	stdout := new(bytes.Buffer)
 	stderr := new(bytes.Buffer)
 
+	// the writers already set on the command, if any, get the output as well
+	stdoutWriters := []io.Writer{stdout}
+	stderrWriters := []io.Writer{stderr}
+	if cmd.Stdout != nil {
+		stdoutWriters = append(stdoutWriters, cmd.Stdout)
+	}
+	if cmd.Stderr != nil {
+		stderrWriters = append(stderrWriters, cmd.Stderr)
+	}
 	if outfile != nil {
-		cmd.Stdout = io.MultiWriter(stdout, outfile)
-		cmd.Stderr = io.MultiWriter(stderr, outfile)
-	} else {
-		cmd.Stdout = stdout
-		cmd.Stderr = stderr
+		stdoutWriters = append(stdoutWriters, outfile)
+		stderrWriters = append(stderrWriters, outfile)
 	}
+	cmd.Stdout = io.MultiWriter(stdoutWriters...)
+	cmd.Stderr = io.MultiWriter(stderrWriters...)
 
 	gplog.Verbose("Executing command: %s", cmd.String())
-	err = cmd.Run()
+	err = RunCommandWithContext(ctx, cmd)
 
 	if err != nil {
 		return stderr, err
@@ -65,21 +77,93 @@ func runCommand(cmd *exec.Cmd, filename ...string) (*bytes.Buffer, error) {
 	return stdout, err
 }
 
+// time given to a command to exit after being asked to terminate, before it is killed
+var CommandGracePeriod = 10 * time.Second
+
+/*
+CommandWithContext returns a copy of the command bound to the context through
+exec.CommandContext, as the command builders create the commands without one.
+When the context is done before the command completes, the command is asked to
+terminate with SIGTERM so that utilities like pg_ctl, initdb and pg_basebackup
+get a chance to clean up, and it is killed if it is still running after
+CommandGracePeriod.
+*/
+func CommandWithContext(ctx context.Context, cmd *exec.Cmd) *exec.Cmd {
+	ctxCmd := exec.CommandContext(ctx, cmd.Path)
+	ctxCmd.Args = cmd.Args
+	ctxCmd.Env = cmd.Env
+	ctxCmd.Dir = cmd.Dir
+	ctxCmd.Stdin = cmd.Stdin
+	ctxCmd.Stdout = cmd.Stdout
+	ctxCmd.Stderr = cmd.Stderr
+	ctxCmd.ExtraFiles = cmd.ExtraFiles
+	ctxCmd.SysProcAttr = cmd.SysProcAttr
+	ctxCmd.Err = cmd.Err
+	ctxCmd.Cancel = func() error {
+		return ctxCmd.Process.Signal(syscall.SIGTERM)
+	}
+	ctxCmd.WaitDelay = CommandGracePeriod
+
+	return ctxCmd
+}
+
+// RunCommandWithContext runs the command bound to the context and waits for it to complete
+func RunCommandWithContext(ctx context.Context, cmd *exec.Cmd) error {
+	err := CommandWithContext(ctx, cmd).Run()
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("command %q was stopped: %w", cmd.String(), ctx.Err())
+	}
+
+	return err
+}
+
+// runTracedCommand runs the command built by the command builder in a span of its own and measures its duration
+func runTracedCommand(ctx context.Context, cmdBuilder CommandBuilder, cmd *exec.Cmd, filename ...string) (*bytes.Buffer, error) {
+	name := commandName(cmdBuilder)
+	_, span := StartSpan(ctx, name, attribute.String("command", cmd.String()))
+
+	start := time.Now()
+	out, err := runCommand(ctx, cmd, filename...)
+	observeCommand(name, start, err)
+	EndSpan(span, err)
+
+	return out, err
+}
+
 // RunGpCommandAndRedirectOutput executes the command and redirects the stdout and stderr to the given filename
-func RunGpCommandAndRedirectOutput(cmdBuilder CommandBuilder, gpHome string, filename string) (*bytes.Buffer, error) {
-	return runCommand(NewGpCommand(cmdBuilder, gpHome), filename)
+func RunGpCommandAndRedirectOutput(ctx context.Context, cmdBuilder CommandBuilder, gpHome string, filename string) (*bytes.Buffer, error) {
+	return runTracedCommand(ctx, cmdBuilder, NewGpCommand(cmdBuilder, gpHome), filename)
 }
 
-// RunGpCommand executes the given command
-func RunGpCommand(cmdBuilder CommandBuilder, gpHome string) (*bytes.Buffer, error) {
-	out, err := runCommand(NewGpCommand(cmdBuilder, gpHome))
+/*
+RunGpCommandAndStreamOutput executes the command and passes every line it
+writes to stdout and stderr to the output function as soon as it is written,
+so that the progress of long-running commands can be followed. The output is
+also redirected to the given filename, if any.
+*/
+func RunGpCommandAndStreamOutput(ctx context.Context, cmdBuilder CommandBuilder, gpHome string, output func(stderr bool, line string), filename ...string) (*bytes.Buffer, error) {
+	stdout := NewLineWriter(func(line string) { output(false, line) })
+	stderr := NewLineWriter(func(line string) { output(true, line) })
+
+	cmd := NewGpCommand(cmdBuilder, gpHome)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	out, err := runTracedCommand(ctx, cmdBuilder, cmd, filename...)
+	stdout.Flush()
+	stderr.Flush()
+
 	return out, err
 }
 
+// RunGpCommand executes the given command
+func RunGpCommand(ctx context.Context, cmdBuilder CommandBuilder, gpHome string) (*bytes.Buffer, error) {
+	return runTracedCommand(ctx, cmdBuilder, NewGpCommand(cmdBuilder, gpHome))
+}
+
 // RunGpSourcedCommand sources the greenplum_path.sh before executing the given command
-func RunGpSourcedCommand(cmdBuilder CommandBuilder, gpHome string) (*bytes.Buffer, error) {
-	out, err := runCommand(NewGpSourcedCommand(cmdBuilder, gpHome))
-	return out, err
+func RunGpSourcedCommand(ctx context.Context, cmdBuilder CommandBuilder, gpHome string) (*bytes.Buffer, error) {
+	return runTracedCommand(ctx, cmdBuilder, NewGpSourcedCommand(cmdBuilder, gpHome))
 }
 
 func GetGpUtilityPath(gpHome, utility string) string {
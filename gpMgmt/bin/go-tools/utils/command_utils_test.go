@@ -1,12 +1,15 @@
 package utils_test
 
 import (
+	"context"
 	"errors"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -22,6 +25,8 @@ func init() {
 		CommandSuccess,
 		CommandFailure,
 		DummyCommand,
+		CommandHang,
+		CommandIgnoreTerm,
 	)
 }
 
@@ -38,7 +43,7 @@ func TestRunExecCommand(t *testing.T) {
 		utils.System.ExecCommand = exectest.NewCommand(CommandSuccess)
 		defer utils.ResetSystemFunctions()
 
-		out, err := utils.RunGpCommand(cmd, "gpHome")
+		out, err := utils.RunGpCommand(context.Background(), cmd, "gpHome")
 		if err != nil {
 			t.Fatalf("unexpected error: %#v", err)
 		}
@@ -57,7 +62,7 @@ func TestRunExecCommand(t *testing.T) {
 		})
 		defer utils.ResetSystemFunctions()
 
-		out, err := utils.RunGpSourcedCommand(cmd, "gpHome")
+		out, err := utils.RunGpSourcedCommand(context.Background(), cmd, "gpHome")
 		if err != nil {
 			t.Fatalf("unexpected error: %#v", err)
 		}
@@ -85,7 +90,7 @@ func TestRunExecCommand(t *testing.T) {
 		filename := filepath.Join(os.TempDir(), "testfile")
 		defer os.Remove(filename)
 
-		out, err := utils.RunGpCommandAndRedirectOutput(cmd, "gpHome", filename)
+		out, err := utils.RunGpCommandAndRedirectOutput(context.Background(), cmd, "gpHome", filename)
 		var expectedErr *exec.ExitError
 		if !errors.As(err, &expectedErr) {
 			t.Errorf("got %T, want %T", err, expectedErr)
@@ -110,17 +115,61 @@ func TestRunExecCommand(t *testing.T) {
 		filename := filepath.Join(os.TempDir(), "testfile")
 		defer os.Remove(filename)
 
-		_, err := utils.RunGpCommandAndRedirectOutput(cmd, "gpHome", filename)
+		_, err := utils.RunGpCommandAndRedirectOutput(context.Background(), cmd, "gpHome", filename)
 		if !errors.Is(err, expectedErr) {
 			t.Errorf("got %#v, want %#v", err, expectedErr)
 		}
 	})
 
+	t.Run("kills the command when the context is cancelled", func(t *testing.T) {
+		utils.System.ExecCommand = exectest.NewCommand(CommandHang)
+		defer utils.ResetSystemFunctions()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := utils.RunGpCommand(ctx, cmd, "gpHome")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+		}
+
+		if time.Since(start) > 30*time.Second {
+			t.Fatalf("expected the command to be killed, took %s", time.Since(start))
+		}
+	})
+
+	t.Run("kills the command when it does not terminate within the grace period", func(t *testing.T) {
+		utils.System.ExecCommand = exectest.NewCommand(CommandIgnoreTerm)
+		defer utils.ResetSystemFunctions()
+
+		utils.CommandGracePeriod = 100 * time.Millisecond
+		defer func() {
+			utils.CommandGracePeriod = 10 * time.Second
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(time.Second)
+			cancel()
+		}()
+
+		start := time.Now()
+		_, err := utils.RunGpCommand(ctx, cmd, "gpHome")
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want %v", err, context.Canceled)
+		}
+
+		if time.Since(start) > 30*time.Second {
+			t.Fatalf("expected the command to be killed, took %s", time.Since(start))
+		}
+	})
+
 	t.Run("when command fails to execute", func(t *testing.T) {
 		utils.System.ExecCommand = exectest.NewCommand(CommandFailure)
 		defer utils.ResetSystemFunctions()
 
-		out, err := utils.RunGpCommand(cmd, "gpHome")
+		out, err := utils.RunGpCommand(context.Background(), cmd, "gpHome")
 		if err == nil {
 			t.Fatalf("expected error")
 		}
@@ -226,6 +275,17 @@ func CommandFailure() {
 	os.Exit(1)
 }
 
+func CommandHang() {
+	time.Sleep(time.Minute)
+	os.Exit(0)
+}
+
+func CommandIgnoreTerm() {
+	signal.Ignore(syscall.SIGTERM)
+	time.Sleep(time.Minute)
+	os.Exit(0)
+}
+
 func DummyCommand() {
 	os.Stdout.WriteString("line 1\n")
 	os.Stdout.WriteString("line 2\n")
@@ -1,13 +1,56 @@
 package utils
 
 import (
-	"fmt"
+	"errors"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 )
 
+// grpcError keeps the status code of an error along with its message, so that
+// the callers can still tell what kind of failure it was
+type grpcError struct {
+	code   codes.Code
+	err    error
+	source *status.Status // status the error was made from, if any, whose details are kept
+}
+
+func (e *grpcError) Error() string {
+	return e.err.Error()
+}
+
+func (e *grpcError) Unwrap() error {
+	return e.err
+}
+
+func (e *grpcError) GRPCStatus() *status.Status {
+	if e.source == nil {
+		return status.New(e.code, e.err.Error())
+	}
+
+	p := e.source.Proto()
+	p.Code = int32(e.code)
+	p.Message = e.err.Error()
+
+	return status.FromProto(p)
+}
+
+// NewGrpcError annotates the error with the given status code
+func NewGrpcError(code codes.Code, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &grpcError{code: code, err: err}
+}
+
+/*
+FormatGrpcError strips the status details from the error message of a failed
+RPC, while keeping the status code and details available through status.Code
+and status.FromError.
+*/
 func FormatGrpcError(err error) error {
 	if err == nil {
 		return nil
@@ -15,8 +58,7 @@ func FormatGrpcError(err error) error {
 
 	grpcErr, ok := status.FromError(err)
 	if ok {
-		errorDescription := grpcErr.Message()
-		return fmt.Errorf(errorDescription)
+		return &grpcError{code: grpcErr.Code(), err: errors.New(grpcErr.Message()), source: grpcErr}
 	}
 
 	return err
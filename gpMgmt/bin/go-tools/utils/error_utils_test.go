@@ -2,6 +2,7 @@ package utils_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"google.golang.org/grpc/codes"
@@ -24,6 +25,20 @@ func TestFormatGrpcError(t *testing.T) {
 		}
 	})
 
+	t.Run("keeps the status code of the GRPC error", func(t *testing.T) {
+		grpcErr := status.Error(codes.Unavailable, "connection refused")
+		result := fmt.Errorf("host: sdw1, %w", utils.FormatGrpcError(grpcErr))
+
+		if status.Code(result) != codes.Unavailable {
+			t.Fatalf("got %v, want %v", status.Code(result), codes.Unavailable)
+		}
+
+		expected := "host: sdw1, connection refused"
+		if result.Error() != expected {
+			t.Fatalf("got %v, want %v", result, expected)
+		}
+	})
+
 	t.Run("returns the original error if not a GRPC error", func(t *testing.T) {
 		expected := errors.New("error")
 		result := utils.FormatGrpcError(expected)
@@ -40,3 +55,24 @@ func TestFormatGrpcError(t *testing.T) {
 		}
 	})
 }
+
+func TestNewGrpcError(t *testing.T) {
+	t.Run("annotates the error with the status code", func(t *testing.T) {
+		expected := errors.New("error")
+		result := utils.NewGrpcError(codes.PermissionDenied, expected)
+
+		if status.Code(result) != codes.PermissionDenied {
+			t.Fatalf("got %v, want %v", status.Code(result), codes.PermissionDenied)
+		}
+		if !errors.Is(result, expected) {
+			t.Fatalf("got %#v, want %#v", result, expected)
+		}
+	})
+
+	t.Run("returns a nil on no error", func(t *testing.T) {
+		result := utils.NewGrpcError(codes.Internal, nil)
+		if result != nil {
+			t.Fatalf("unexpected error: %#v", result)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package greenplum_test
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"testing"
@@ -55,7 +56,7 @@ func TestGpCommand(t *testing.T) {
 		gpCmdOptions := &greenplum.GpStart{
 			DataDirectory: "gpseg",
 		}
-		out, err := utils.RunGpCommand(gpCmdOptions, "gpHome")
+		out, err := utils.RunGpCommand(context.Background(), gpCmdOptions, "gpHome")
 		if err != nil {
 			t.Fatalf("unexpected error: %#v", err)
 		}
@@ -75,7 +76,7 @@ func TestGpCommand(t *testing.T) {
 		gpCmdOptions := &greenplum.GpStart{
 			DataDirectory: "gpseg",
 		}
-		out, err := utils.RunGpCommand(gpCmdOptions, "gpHome")
+		out, err := utils.RunGpCommand(context.Background(), gpCmdOptions, "gpHome")
 		if status, ok := err.(*exec.ExitError); !ok || status.ExitCode() != 1 {
 			t.Fatalf("unexpected error: %+v", err)
 		}
@@ -155,6 +155,30 @@ func (g *GpArray) GetSegmentsByHost() map[string][]Segment {
 	return result
 }
 
+// GetHostnames returns the sorted list of unique hostnames of all the
+// segments in the cluster including the coordinator and the standby
+func (g *GpArray) GetHostnames() []string {
+	segs := g.GetAllSegments()
+	if g.Coordinator != nil {
+		segs = append(segs, *g.Coordinator)
+	}
+	if g.Standby != nil {
+		segs = append(segs, *g.Standby)
+	}
+
+	hostnameMap := make(map[string]bool)
+	var hostnames []string
+	for _, seg := range segs {
+		if !hostnameMap[seg.Hostname] {
+			hostnameMap[seg.Hostname] = true
+			hostnames = append(hostnames, seg.Hostname)
+		}
+	}
+	sort.Strings(hostnames)
+
+	return hostnames
+}
+
 func RegisterCoordinator(seg *idl.Segment, conn *dbconn.DBConn) error {
 	addCoordinatorQuery := "SELECT pg_catalog.gp_add_segment(1::int2, -1::int2, 'p', 'p', 's', 'u', '%d', '%s', '%s', '%s')"
 	_, err := conn.Exec(fmt.Sprintf(addCoordinatorQuery, seg.Port, seg.HostName, seg.HostAddress, seg.DataDirectory))
@@ -201,6 +225,52 @@ func RegisterMirrorSegments(segs []*idl.Segment, conn *dbconn.DBConn) error {
 	return nil
 }
 
+/*
+RestoreSegmentConfiguration replaces the content of gp_segment_configuration
+with the segments of the gparray, as a single transaction. The catalog is not
+checked against the segments actually running, so the cluster should be
+restarted afterwards.
+*/
+func RestoreSegmentConfiguration(gparray *GpArray, conn *dbconn.DBConn) (err error) {
+	var segs []Segment
+	if gparray.Coordinator != nil {
+		segs = append(segs, *gparray.Coordinator)
+	}
+	if gparray.Standby != nil {
+		segs = append(segs, *gparray.Standby)
+	}
+	segs = append(segs, gparray.GetAllSegments()...)
+	if len(segs) == 0 {
+		return fmt.Errorf("invalid configuration, no segments found")
+	}
+
+	err = conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			conn.Rollback()
+		}
+	}()
+
+	_, err = conn.Exec(fmt.Sprintf("SET allow_system_table_mods=true; DELETE FROM pg_catalog.%s", constants.GpSegmentConfiguration))
+	if err != nil {
+		return err
+	}
+
+	insertQuery := "INSERT INTO pg_catalog.%s (dbid, content, role, preferred_role, mode, status, port, hostname, address, datadir) VALUES (%d, %d, '%s', '%s', '%s', '%s', %d, '%s', '%s', '%s')"
+	for _, seg := range segs {
+		_, err = conn.Exec(fmt.Sprintf(insertQuery, constants.GpSegmentConfiguration, seg.Dbid, seg.Content, seg.Role, seg.PreferredRole,
+			seg.Mode, seg.Status, seg.Port, seg.Hostname, seg.Address, seg.DataDir))
+		if err != nil {
+			return err
+		}
+	}
+
+	return conn.Commit()
+}
+
 func getSegmentPairsFromContentMap(contentMap map[int][]Segment) ([]SegmentPair, error) {
 	var pairs []SegmentPair
 	segsPerContent := 0
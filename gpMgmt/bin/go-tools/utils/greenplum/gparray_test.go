@@ -389,6 +389,62 @@ func TestRegisterSegments(t *testing.T) {
 	})
 }
 
+func TestRestoreSegmentConfiguration(t *testing.T) {
+	initializeGpArray(t)
+
+	t.Run("replaces the segment configuration in a transaction", func(t *testing.T) {
+		conn, mock := testutils.CreateAndConnectMockDB(t, 1)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SET TRANSACTION ISOLATION LEVEL SERIALIZABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("SET allow_system_table_mods=true; DELETE FROM pg_catalog.gp_segment_configuration")).WillReturnResult(sqlmock.NewResult(0, 6))
+		for _, seg := range []*greenplum.Segment{coordinator, standby, primary1, primary2, mirror1, mirror2} {
+			mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf("VALUES (%d, %d, '%s', '%s', '%s', '%s', %d, '%s', '%s', '%s')",
+				seg.Dbid, seg.Content, seg.Role, seg.PreferredRole, seg.Mode, seg.Status, seg.Port, seg.Hostname, seg.Address, seg.DataDir))).WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+		mock.ExpectCommit()
+
+		err := greenplum.RestoreSegmentConfiguration(&gparray, conn)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rolls back when not able to insert a segment", func(t *testing.T) {
+		conn, mock := testutils.CreateAndConnectMockDB(t, 1)
+
+		expectedErr := errors.New("error")
+		mock.ExpectBegin()
+		mock.ExpectExec("SET TRANSACTION ISOLATION LEVEL SERIALIZABLE").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE").WillReturnResult(sqlmock.NewResult(0, 6))
+		mock.ExpectExec("INSERT").WillReturnError(expectedErr)
+		mock.ExpectRollback()
+
+		err := greenplum.RestoreSegmentConfiguration(&gparray, conn)
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("got %#v, want %#v", err, expectedErr)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("errors out when there are no segments", func(t *testing.T) {
+		conn, _ := testutils.CreateAndConnectMockDB(t, 1)
+
+		err := greenplum.RestoreSegmentConfiguration(&greenplum.GpArray{}, conn)
+		expected := "invalid configuration, no segments found"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
+
 func TestGpArray(t *testing.T) {
 	initializeGpArray(t)
 
@@ -467,6 +523,24 @@ func TestGpArray(t *testing.T) {
 			t.Fatalf("got %+v, want %+v", result, expected)
 		}
 	})
+
+	t.Run("get the unique hostnames in the cluster", func(t *testing.T) {
+		expected := []string{"cdw", "scdw", "sdw1", "sdw2"}
+
+		result := gparray.GetHostnames()
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("got %+v, want %+v", result, expected)
+		}
+
+		gparray.Standby = nil
+		defer initializeGpArray(t)
+
+		expected = []string{"cdw", "sdw1", "sdw2"}
+		result = gparray.GetHostnames()
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("got %+v, want %+v", result, expected)
+		}
+	})
 }
 
 func createSegment(t *testing.T, dbid int, content int, role string, preferredRole string, port int, hostname string, address string, dataDir string) *greenplum.Segment {
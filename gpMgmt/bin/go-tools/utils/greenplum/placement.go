@@ -0,0 +1,202 @@
+package greenplum
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+)
+
+// PlacementHost is a host of the cluster as seen by the mirror placement strategies
+type PlacementHost struct {
+	Hostname      string
+	Addresses     []string
+	FailureDomain string
+}
+
+// MirrorPlacement tells where the mirror of a primary goes
+type MirrorPlacement struct {
+	Host    int // index of the host of the mirror
+	Slot    int // index of the mirror on its host, picking its port and data directory
+	Address int // index of the address of the host to use, modulo the number of addresses
+}
+
+/*
+PlacementStrategy places the mirrors of the primaries of each host on the other
+hosts. Every host runs the same number of primaries, and receives the same
+number of mirrors in distinct slots, so that the mirrors of a host can use the
+ports and data directories of the slots without colliding.
+*/
+type PlacementStrategy interface {
+	// Check returns why the mirrors of the hosts can not be placed with the strategy
+	Check(hosts []PlacementHost, primariesPerHost int) error
+	// PlaceMirrors returns where the mirror of the k-th primary of each host goes, once checked
+	PlaceMirrors(hosts []PlacementHost, primariesPerHost int) [][]MirrorPlacement
+}
+
+// NewPlacementStrategy returns the strategy of the mirroring type, group by default
+func NewPlacementStrategy(mirroringType string, blockSize int) (PlacementStrategy, error) {
+	switch mirroringType {
+	case constants.GroupMirroring, "":
+		return GroupPlacement{}, nil
+	case constants.SpreadMirroring:
+		return SpreadPlacement{}, nil
+	case constants.BlockMirroring:
+		return BlockPlacement{Size: blockSize}, nil
+	case constants.RackAwareMirroring:
+		return RackAwarePlacement{}, nil
+	}
+
+	return nil, fmt.Errorf("invalid mirroring type %q, expected %s, %s, %s or %s", mirroringType,
+		constants.GroupMirroring, constants.SpreadMirroring, constants.BlockMirroring, constants.RackAwareMirroring)
+}
+
+// GroupPlacement places all the mirrors of a host on the next host
+type GroupPlacement struct{}
+
+func (GroupPlacement) Check(hosts []PlacementHost, primariesPerHost int) error {
+	return nil
+}
+
+func (GroupPlacement) PlaceMirrors(hosts []PlacementHost, primariesPerHost int) [][]MirrorPlacement {
+	return placeByShift(hosts, primariesPerHost, func(host int, k int) int {
+		return (host + 1) % len(hosts)
+	}, func(host int, k int) int {
+		return k
+	})
+}
+
+// SpreadPlacement places each mirror of a host on a different host, starting from the next one
+type SpreadPlacement struct{}
+
+func (SpreadPlacement) Check(hosts []PlacementHost, primariesPerHost int) error {
+	if primariesPerHost >= len(hosts) {
+		return fmt.Errorf("spread mirroring needs more hosts than primaries per host, got %d hosts and %d primaries per host", len(hosts), primariesPerHost)
+	}
+
+	return nil
+}
+
+func (SpreadPlacement) PlaceMirrors(hosts []PlacementHost, primariesPerHost int) [][]MirrorPlacement {
+	return placeByShift(hosts, primariesPerHost, func(host int, k int) int {
+		return (host + k + 1) % len(hosts)
+	}, func(host int, k int) int {
+		return host + k
+	})
+}
+
+/*
+BlockPlacement splits the hosts into blocks of Size hosts in their order, and
+spreads the mirrors of a host across the other hosts of its block, so that
+losing a host only loads the hosts of its block.
+*/
+type BlockPlacement struct {
+	Size int
+}
+
+func (p BlockPlacement) Check(hosts []PlacementHost, primariesPerHost int) error {
+	if p.Size < 2 {
+		return fmt.Errorf("invalid block size %d for block mirroring, expected at least 2 hosts per block", p.Size)
+	}
+	if len(hosts)%p.Size != 0 {
+		return fmt.Errorf("block mirroring needs a number of hosts multiple of the block size %d, got %d hosts", p.Size, len(hosts))
+	}
+	if primariesPerHost >= p.Size {
+		return fmt.Errorf("block mirroring needs more hosts per block than primaries per host, got %d hosts per block and %d primaries per host", p.Size, primariesPerHost)
+	}
+
+	return nil
+}
+
+func (p BlockPlacement) PlaceMirrors(hosts []PlacementHost, primariesPerHost int) [][]MirrorPlacement {
+	return placeByShift(hosts, primariesPerHost, func(host int, k int) int {
+		block := host - host%p.Size
+		return block + (host%p.Size+k+1)%p.Size
+	}, func(host int, k int) int {
+		return k
+	})
+}
+
+/*
+RackAwarePlacement places the mirrors in another failure domain than their
+primary. The hosts are ordered by failure domain, and the mirror of the k-th
+primary of a host goes to the host a fixed shift further in that order. The
+shifts are at least the size of the largest domain and at most the number of
+hosts minus it, so that they always cross into another domain, and differ per
+k to spread the mirrors of a host as much as the domains allow.
+*/
+type RackAwarePlacement struct{}
+
+func (RackAwarePlacement) Check(hosts []PlacementHost, primariesPerHost int) error {
+	domains := make(map[string]int)
+	for _, host := range hosts {
+		if host.FailureDomain == "" {
+			return fmt.Errorf("rack-aware mirroring needs the failure domain of every host, none found for host %s", host.Hostname)
+		}
+		domains[host.FailureDomain]++
+	}
+
+	for _, domain := range sortedKeys(domains) {
+		if 2*domains[domain] > len(hosts) {
+			return fmt.Errorf("rack-aware mirroring needs every failure domain to hold at most half of the hosts, domain %s holds %d of the %d hosts", domain, domains[domain], len(hosts))
+		}
+	}
+
+	return nil
+}
+
+func (RackAwarePlacement) PlaceMirrors(hosts []PlacementHost, primariesPerHost int) [][]MirrorPlacement {
+	domains := make(map[string]int)
+	for _, host := range hosts {
+		domains[host.FailureDomain]++
+	}
+	largest := 0
+	for _, count := range domains {
+		largest = max(largest, count)
+	}
+
+	order := make([]int, len(hosts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return hosts[order[i]].FailureDomain < hosts[order[j]].FailureDomain
+	})
+	position := make([]int, len(hosts))
+	for pos, host := range order {
+		position[host] = pos
+	}
+
+	shifts := len(hosts) - 2*largest + 1
+	return placeByShift(hosts, primariesPerHost, func(host int, k int) int {
+		return order[(position[host]+largest+k%shifts)%len(hosts)]
+	}, func(host int, k int) int {
+		return k
+	})
+}
+
+// placeByShift places the mirror of the k-th primary of each host in slot k of the host returned by target
+func placeByShift(hosts []PlacementHost, primariesPerHost int, target func(host int, k int) int, address func(host int, k int) int) [][]MirrorPlacement {
+	placements := make([][]MirrorPlacement, len(hosts))
+	for host := range hosts {
+		for k := 0; k < primariesPerHost; k++ {
+			placements[host] = append(placements[host], MirrorPlacement{
+				Host:    target(host, k),
+				Slot:    k,
+				Address: address(host, k),
+			})
+		}
+	}
+
+	return placements
+}
+
+func sortedKeys(m map[string]int) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,191 @@
+package greenplum_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+func newPlacementHosts(domains ...string) []greenplum.PlacementHost {
+	var hosts []greenplum.PlacementHost
+	for i, domain := range domains {
+		hostname := "sdw" + string(rune('1'+i))
+		hosts = append(hosts, greenplum.PlacementHost{Hostname: hostname, Addresses: []string{hostname}, FailureDomain: domain})
+	}
+
+	return hosts
+}
+
+// mirrorHosts returns the indexes of the hosts of the mirrors of each host
+func mirrorHosts(placements [][]greenplum.MirrorPlacement) [][]int {
+	var result [][]int
+	for _, hostPlacements := range placements {
+		var hosts []int
+		for _, placement := range hostPlacements {
+			hosts = append(hosts, placement.Host)
+		}
+		result = append(result, hosts)
+	}
+
+	return result
+}
+
+// checkSlots fails when two mirrors are placed in the same slot of a host
+func checkSlots(t *testing.T, placements [][]greenplum.MirrorPlacement) {
+	t.Helper()
+
+	used := make(map[greenplum.MirrorPlacement]bool)
+	for _, hostPlacements := range placements {
+		for _, placement := range hostPlacements {
+			slot := greenplum.MirrorPlacement{Host: placement.Host, Slot: placement.Slot}
+			if used[slot] {
+				t.Fatalf("got two mirrors in slot %d of host %d", placement.Slot, placement.Host)
+			}
+			used[slot] = true
+		}
+	}
+}
+
+func TestNewPlacementStrategy(t *testing.T) {
+	t.Run("returns the strategy of the mirroring type", func(t *testing.T) {
+		cases := map[string]greenplum.PlacementStrategy{
+			"":                           greenplum.GroupPlacement{},
+			constants.GroupMirroring:     greenplum.GroupPlacement{},
+			constants.SpreadMirroring:    greenplum.SpreadPlacement{},
+			constants.BlockMirroring:     greenplum.BlockPlacement{Size: 3},
+			constants.RackAwareMirroring: greenplum.RackAwarePlacement{},
+		}
+		for mirroringType, expected := range cases {
+			strategy, err := greenplum.NewPlacementStrategy(mirroringType, 3)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(strategy, expected) {
+				t.Fatalf("got %#v, want %#v", strategy, expected)
+			}
+		}
+	})
+
+	t.Run("errors out on an unknown mirroring type", func(t *testing.T) {
+		_, err := greenplum.NewPlacementStrategy("ring", 0)
+		expected := `invalid mirroring type "ring", expected group, spread, block or rack-aware`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
+
+func TestPlaceMirrors(t *testing.T) {
+	t.Run("places the mirrors of a host on the next host with group mirroring", func(t *testing.T) {
+		placements := greenplum.GroupPlacement{}.PlaceMirrors(newPlacementHosts("", "", ""), 2)
+
+		expected := [][]int{{1, 1}, {2, 2}, {0, 0}}
+		if !reflect.DeepEqual(mirrorHosts(placements), expected) {
+			t.Fatalf("got %v, want %v", mirrorHosts(placements), expected)
+		}
+		checkSlots(t, placements)
+	})
+
+	t.Run("places the mirrors of a host on the next hosts with spread mirroring", func(t *testing.T) {
+		placements := greenplum.SpreadPlacement{}.PlaceMirrors(newPlacementHosts("", "", ""), 2)
+
+		expected := [][]int{{1, 2}, {2, 0}, {0, 1}}
+		if !reflect.DeepEqual(mirrorHosts(placements), expected) {
+			t.Fatalf("got %v, want %v", mirrorHosts(placements), expected)
+		}
+		checkSlots(t, placements)
+	})
+
+	t.Run("places the mirrors of a host within its block with block mirroring", func(t *testing.T) {
+		placements := greenplum.BlockPlacement{Size: 3}.PlaceMirrors(newPlacementHosts("", "", "", "", "", ""), 2)
+
+		expected := [][]int{{1, 2}, {2, 0}, {0, 1}, {4, 5}, {5, 3}, {3, 4}}
+		if !reflect.DeepEqual(mirrorHosts(placements), expected) {
+			t.Fatalf("got %v, want %v", mirrorHosts(placements), expected)
+		}
+		checkSlots(t, placements)
+	})
+
+	t.Run("places the mirrors in another failure domain with rack-aware mirroring", func(t *testing.T) {
+		hosts := newPlacementHosts("rack1", "rack2", "rack1", "rack3", "rack2", "rack3")
+		placements := greenplum.RackAwarePlacement{}.PlaceMirrors(hosts, 3)
+
+		for i, hostPlacements := range placements {
+			targets := make(map[int]bool)
+			for _, placement := range hostPlacements {
+				if hosts[placement.Host].FailureDomain == hosts[i].FailureDomain {
+					t.Fatalf("got the mirror of a primary of host %s on host %s in the same failure domain", hosts[i].Hostname, hosts[placement.Host].Hostname)
+				}
+				targets[placement.Host] = true
+			}
+			if len(targets) != 3 {
+				t.Fatalf("got the mirrors of host %s on %d hosts, want them on 3 hosts", hosts[i].Hostname, len(targets))
+			}
+		}
+		checkSlots(t, placements)
+	})
+}
+
+func TestPlacementCheck(t *testing.T) {
+	cases := []struct {
+		name     string
+		strategy greenplum.PlacementStrategy
+		hosts    []greenplum.PlacementHost
+		expected string
+	}{
+		{
+			name:     "spread mirroring with as many primaries as hosts",
+			strategy: greenplum.SpreadPlacement{},
+			hosts:    newPlacementHosts("", ""),
+			expected: "spread mirroring needs more hosts than primaries per host, got 2 hosts and 2 primaries per host",
+		},
+		{
+			name:     "block mirroring without a block size",
+			strategy: greenplum.BlockPlacement{},
+			hosts:    newPlacementHosts("", "", ""),
+			expected: "invalid block size 0 for block mirroring, expected at least 2 hosts per block",
+		},
+		{
+			name:     "block mirroring with a partial block",
+			strategy: greenplum.BlockPlacement{Size: 3},
+			hosts:    newPlacementHosts("", "", "", ""),
+			expected: "block mirroring needs a number of hosts multiple of the block size 3, got 4 hosts",
+		},
+		{
+			name:     "block mirroring with as many primaries as hosts per block",
+			strategy: greenplum.BlockPlacement{Size: 2},
+			hosts:    newPlacementHosts("", "", "", ""),
+			expected: "block mirroring needs more hosts per block than primaries per host, got 2 hosts per block and 2 primaries per host",
+		},
+		{
+			name:     "rack-aware mirroring without the failure domain of a host",
+			strategy: greenplum.RackAwarePlacement{},
+			hosts:    newPlacementHosts("rack1", "", "rack2"),
+			expected: "rack-aware mirroring needs the failure domain of every host, none found for host sdw2",
+		},
+		{
+			name:     "rack-aware mirroring with a domain holding most of the hosts",
+			strategy: greenplum.RackAwarePlacement{},
+			hosts:    newPlacementHosts("rack1", "rack1", "rack2"),
+			expected: "rack-aware mirroring needs every failure domain to hold at most half of the hosts, domain rack1 holds 2 of the 3 hosts",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run("errors out for "+tc.name, func(t *testing.T) {
+			err := tc.strategy.Check(tc.hosts, 2)
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("got %v, want %s", err, tc.expected)
+			}
+		})
+	}
+
+	t.Run("succeeds when the mirrors can be placed", func(t *testing.T) {
+		err := greenplum.RackAwarePlacement{}.Check(newPlacementHosts("rack1", "rack2", "rack1", "rack2"), 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
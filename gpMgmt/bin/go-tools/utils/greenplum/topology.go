@@ -0,0 +1,336 @@
+package greenplum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+const (
+	// TopologyFileVersion is the version of the topology file format written by NewTopologyFile
+	TopologyFileVersion = 1
+
+	TopologyFormatJSON = "json"
+	TopologyFormatYAML = "yaml"
+
+	topologyRolePrimary = "primary"
+	topologyRoleMirror  = "mirror"
+)
+
+/*
+TopologyFile is the portable form of a GpArray, written as YAML or JSON so
+that a layout can be reviewed before it is applied. It only holds the layout
+of the segments: their mode and status are the state of the running cluster
+and are not part of it.
+
+	version: 1
+	coordinator:
+	  dbid: 1
+	  content: -1
+	  role: primary
+	  preferred-role: primary
+	  hostname: cdw
+	  address: cdw
+	  port: 5432
+	  data-directory: /data/coordinator/gpseg-1
+	segments:
+	  - primary:
+	      dbid: 2
+	      content: 0
+	      ...
+	    mirror:
+	      dbid: 4
+	      content: 0
+	      ...
+*/
+type TopologyFile struct {
+	Version     int                   `json:"version" yaml:"version"`
+	Coordinator *TopologySegment      `json:"coordinator" yaml:"coordinator"`
+	Standby     *TopologySegment      `json:"standby,omitempty" yaml:"standby,omitempty"`
+	Segments    []TopologySegmentPair `json:"segments" yaml:"segments"`
+}
+
+type TopologySegmentPair struct {
+	Primary *TopologySegment `json:"primary" yaml:"primary"`
+	Mirror  *TopologySegment `json:"mirror,omitempty" yaml:"mirror,omitempty"`
+}
+
+// TopologySegment is a segment of the topology file, with the role written as primary or mirror
+type TopologySegment struct {
+	Dbid          int    `json:"dbid" yaml:"dbid"`
+	Content       int    `json:"content" yaml:"content"`
+	Role          string `json:"role" yaml:"role"`
+	PreferredRole string `json:"preferred-role,omitempty" yaml:"preferred-role,omitempty"`
+	Hostname      string `json:"hostname" yaml:"hostname"`
+	Address       string `json:"address,omitempty" yaml:"address,omitempty"`
+	Port          int    `json:"port" yaml:"port"`
+	DataDirectory string `json:"data-directory" yaml:"data-directory"`
+}
+
+// NewTopologyFile returns the topology file describing the layout of the gparray
+func NewTopologyFile(gparray *GpArray) *TopologyFile {
+	file := &TopologyFile{
+		Version:     TopologyFileVersion,
+		Coordinator: newTopologySegment(gparray.Coordinator),
+		Standby:     newTopologySegment(gparray.Standby),
+		Segments:    []TopologySegmentPair{},
+	}
+	for _, pair := range gparray.SegmentPairs {
+		file.Segments = append(file.Segments, TopologySegmentPair{
+			Primary: newTopologySegment(pair.Primary),
+			Mirror:  newTopologySegment(pair.Mirror),
+		})
+	}
+
+	return file
+}
+
+func newTopologySegment(seg *Segment) *TopologySegment {
+	if seg == nil {
+		return nil
+	}
+
+	return &TopologySegment{
+		Dbid:          seg.Dbid,
+		Content:       seg.Content,
+		Role:          topologyRoleName(seg.Role),
+		PreferredRole: topologyRoleName(seg.PreferredRole),
+		Hostname:      seg.Hostname,
+		Address:       seg.Address,
+		Port:          seg.Port,
+		DataDirectory: seg.DataDir,
+	}
+}
+
+func topologyRoleName(role string) string {
+	switch role {
+	case constants.RolePrimary:
+		return topologyRolePrimary
+	case constants.RoleMirror:
+		return topologyRoleMirror
+	}
+
+	return role
+}
+
+// ParseTopologyFile reads a topology file written as YAML or JSON, rejecting unknown fields
+func ParseTopologyFile(contents []byte) (*TopologyFile, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+
+	file := &TopologyFile{}
+	err := decoder.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the topology file: %w", err)
+	}
+
+	return file, nil
+}
+
+// ReadTopologyFile reads and validates the topology file at the given path
+func ReadTopologyFile(path string) (*GpArray, error) {
+	contents, err := utils.System.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the topology file %s: %w", path, err)
+	}
+
+	file, err := ParseTopologyFile(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	gparray, err := file.GpArray()
+	if err != nil {
+		return nil, fmt.Errorf("invalid topology file %s:\n%w", path, err)
+	}
+
+	return gparray, nil
+}
+
+// Marshal writes the topology file in the given format, json or yaml
+func (f *TopologyFile) Marshal(format string) ([]byte, error) {
+	switch format {
+	case TopologyFormatJSON:
+		contents, err := json.MarshalIndent(f, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(contents, '\n'), nil
+
+	case TopologyFormatYAML:
+		var buffer bytes.Buffer
+		encoder := yaml.NewEncoder(&buffer)
+		encoder.SetIndent(2)
+		err := encoder.Encode(f)
+		if err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), encoder.Close()
+	}
+
+	return nil, fmt.Errorf("invalid topology format %q, expected %s or %s", format, TopologyFormatJSON, TopologyFormatYAML)
+}
+
+// TopologyFormatFromPath returns yaml or json depending on the extension of the path, yaml by default
+func TopologyFormatFromPath(path string) string {
+	if filepath.Ext(path) == ".json" {
+		return TopologyFormatJSON
+	}
+
+	return TopologyFormatYAML
+}
+
+/*
+Validate checks the fields of the segments of the topology file, and that the
+layout they describe could exist, returning every problem found, one per line.
+*/
+func (f *TopologyFile) Validate() error {
+	var errs []error
+	if f.Version != TopologyFileVersion {
+		errs = append(errs, fmt.Errorf("unsupported version %d, expected %d", f.Version, TopologyFileVersion))
+	}
+
+	if f.Coordinator != nil {
+		errs = append(errs, f.Coordinator.validate("coordinator", topologyRolePrimary)...)
+	}
+	if f.Standby != nil {
+		errs = append(errs, f.Standby.validate("standby", topologyRoleMirror)...)
+	}
+	for i, pair := range f.Segments {
+		if pair.Primary != nil {
+			errs = append(errs, pair.Primary.validate(fmt.Sprintf("primary of segment pair %d", i+1), topologyRolePrimary)...)
+		}
+		if pair.Mirror != nil {
+			errs = append(errs, pair.Mirror.validate(fmt.Sprintf("mirror of segment pair %d", i+1), topologyRoleMirror)...)
+		}
+	}
+
+	_, err := f.gpArray().Validate(ValidateOptions{})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (seg *TopologySegment) validate(name string, role string) []error {
+	var errs []error
+	addError := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{name}, args...)...))
+	}
+
+	if seg.Dbid < 1 {
+		addError("invalid dbid %d, expected a positive number", seg.Dbid)
+	}
+	if seg.Role != role {
+		addError("got role %q, expected %q", seg.Role, role)
+	}
+	if seg.PreferredRole != "" && seg.PreferredRole != topologyRolePrimary && seg.PreferredRole != topologyRoleMirror {
+		addError("invalid preferred role %q, expected %q or %q", seg.PreferredRole, topologyRolePrimary, topologyRoleMirror)
+	}
+	if seg.Hostname == "" {
+		addError("the hostname is missing")
+	}
+	if seg.Port < 1 || seg.Port > 65535 {
+		addError("invalid port %d, expected a number between 1 and 65535", seg.Port)
+	}
+	if seg.DataDirectory == "" {
+		addError("the data directory is missing")
+	} else if !filepath.IsAbs(seg.DataDirectory) {
+		addError("data directory %s is not an absolute path", seg.DataDirectory)
+	}
+
+	return errs
+}
+
+/*
+GpArray validates the topology file and returns the gparray it describes. The
+address defaults to the hostname and the preferred role to the role.
+*/
+func (f *TopologyFile) GpArray() (*GpArray, error) {
+	err := f.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	gparray := f.gpArray()
+	sort.Slice(gparray.SegmentPairs, func(i, j int) bool {
+		return gparray.SegmentPairs[i].Primary.Content < gparray.SegmentPairs[j].Primary.Content
+	})
+
+	return gparray, nil
+}
+
+func (f *TopologyFile) gpArray() *GpArray {
+	gparray := &GpArray{
+		Coordinator: f.Coordinator.segment(),
+		Standby:     f.Standby.segment(),
+	}
+	for _, pair := range f.Segments {
+		gparray.SegmentPairs = append(gparray.SegmentPairs, SegmentPair{
+			Primary: pair.Primary.segment(),
+			Mirror:  pair.Mirror.segment(),
+		})
+	}
+
+	return gparray
+}
+
+func (seg *TopologySegment) segment() *Segment {
+	if seg == nil {
+		return nil
+	}
+
+	role := constants.RolePrimary
+	if seg.Role == topologyRoleMirror {
+		role = constants.RoleMirror
+	}
+	preferredRole := role
+	switch seg.PreferredRole {
+	case topologyRolePrimary:
+		preferredRole = constants.RolePrimary
+	case topologyRoleMirror:
+		preferredRole = constants.RoleMirror
+	}
+	address := seg.Address
+	if address == "" {
+		address = seg.Hostname
+	}
+
+	return &Segment{
+		Dbid:          seg.Dbid,
+		Content:       seg.Content,
+		Role:          role,
+		PreferredRole: preferredRole,
+		Port:          seg.Port,
+		Hostname:      seg.Hostname,
+		Address:       address,
+		DataDir:       seg.DataDirectory,
+	}
+}
+
+// MirrorsToIdl returns the mirrors of the gparray as segments to add, e.g. with the AddMirrors RPC
+func (g *GpArray) MirrorsToIdl() []*idl.Segment {
+	var mirrors []*idl.Segment
+	for _, mirror := range g.GetMirrorSegments() {
+		mirrors = append(mirrors, &idl.Segment{
+			Port:          int32(mirror.Port),
+			DataDirectory: mirror.DataDir,
+			HostName:      mirror.Hostname,
+			HostAddress:   mirror.Address,
+			Contentid:     int32(mirror.Content),
+			Dbid:          int32(mirror.Dbid),
+		})
+	}
+
+	return mirrors
+}
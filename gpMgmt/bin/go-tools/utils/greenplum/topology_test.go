@@ -0,0 +1,195 @@
+package greenplum_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+func newTopologyGpArray() *greenplum.GpArray {
+	return &greenplum.GpArray{
+		Coordinator: &greenplum.Segment{Dbid: 1, Content: -1, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7000, Hostname: "cdw", Address: "cdw", DataDir: "/data/coordinator/gpseg-1"},
+		Standby:     &greenplum.Segment{Dbid: 6, Content: -1, Role: constants.RoleMirror, PreferredRole: constants.RoleMirror, Port: 7000, Hostname: "scdw", Address: "scdw", DataDir: "/data/coordinator/gpseg-1"},
+		SegmentPairs: []greenplum.SegmentPair{
+			{
+				Primary: &greenplum.Segment{Dbid: 2, Content: 0, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7002, Hostname: "sdw1", Address: "sdw1-eth1", DataDir: "/data/primary/gpseg0"},
+				Mirror:  &greenplum.Segment{Dbid: 4, Content: 0, Role: constants.RoleMirror, PreferredRole: constants.RoleMirror, Port: 7003, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/mirror/gpseg0"},
+			},
+			{
+				Primary: &greenplum.Segment{Dbid: 3, Content: 1, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7002, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/primary/gpseg1"},
+				Mirror:  &greenplum.Segment{Dbid: 5, Content: 1, Role: constants.RoleMirror, PreferredRole: constants.RoleMirror, Port: 7003, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/mirror/gpseg1"},
+			},
+		},
+	}
+}
+
+func TestTopologyFile(t *testing.T) {
+	for _, format := range []string{greenplum.TopologyFormatYAML, greenplum.TopologyFormatJSON} {
+		t.Run("reads back the gparray written as "+format, func(t *testing.T) {
+			contents, err := greenplum.NewTopologyFile(newTopologyGpArray()).Marshal(format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			file, err := greenplum.ParseTopologyFile(contents)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gparray, err := file.GpArray()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(gparray, newTopologyGpArray()) {
+				t.Fatalf("got %+v, want %+v", gparray, newTopologyGpArray())
+			}
+		})
+	}
+
+	t.Run("writes the roles by name", func(t *testing.T) {
+		contents, err := greenplum.NewTopologyFile(newTopologyGpArray()).Marshal(greenplum.TopologyFormatYAML)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "  - primary:\n      dbid: 2\n      content: 0\n      role: primary\n      preferred-role: primary\n      hostname: sdw1\n      address: sdw1-eth1\n      port: 7002\n      data-directory: /data/primary/gpseg0\n"
+		if !strings.Contains(string(contents), expected) {
+			t.Fatalf("got %s, want it to contain %s", contents, expected)
+		}
+	})
+
+	t.Run("defaults the address to the hostname and the preferred role to the role", func(t *testing.T) {
+		file, err := greenplum.ParseTopologyFile([]byte(`
+version: 1
+coordinator: {dbid: 1, content: -1, role: primary, hostname: cdw, port: 7000, data-directory: /data/coordinator/gpseg-1}
+segments:
+  - primary: {dbid: 3, content: 1, role: primary, hostname: sdw2, port: 7002, data-directory: /data/primary/gpseg1}
+  - primary: {dbid: 2, content: 0, role: primary, hostname: sdw1, port: 7002, data-directory: /data/primary/gpseg0}
+`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		gparray, err := file.GpArray()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := &greenplum.Segment{Dbid: 2, Content: 0, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7002, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/primary/gpseg0"}
+		if !reflect.DeepEqual(gparray.SegmentPairs[0].Primary, expected) {
+			t.Fatalf("got %+v, want %+v", gparray.SegmentPairs[0].Primary, expected)
+		}
+		if gparray.HasMirrors() || gparray.Standby != nil {
+			t.Fatalf("got %+v, want no mirrors and no standby", gparray)
+		}
+	})
+
+	t.Run("reports every problem of the topology", func(t *testing.T) {
+		file := greenplum.NewTopologyFile(newTopologyGpArray())
+		file.Version = 2
+		file.Standby.Role = "primary"
+		file.Segments[0].Primary.Port = 0
+		file.Segments[0].Mirror.DataDirectory = "data/mirror/gpseg0"
+		file.Segments[1].Primary.Content = 0
+		file.Segments[1].Primary.Dbid = 2
+		file.Segments[1].Mirror = nil
+
+		err := file.Validate()
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+
+		expected := []string{
+			"unsupported version 2, expected 1",
+			`standby: got role "primary", expected "mirror"`,
+			"primary of segment pair 1: invalid port 0, expected a number between 1 and 65535",
+			"mirror of segment pair 1: data directory data/mirror/gpseg0 is not an absolute path",
+			"content 0 is used by more than one segment pair",
+			"content 1 is missing, the contents have to go from 0 to 1",
+			"number of primary segments 2 and number of mirror segments 1 must be equal",
+			"duplicate dbid 2 found",
+		}
+		if err.Error() != strings.Join(expected, "\n") {
+			t.Fatalf("got %v, want %s", err, strings.Join(expected, "\n"))
+		}
+	})
+
+	t.Run("reports the ports and data directories used twice on a host", func(t *testing.T) {
+		file := greenplum.NewTopologyFile(newTopologyGpArray())
+		file.Segments[1].Mirror.Port = 7002
+		file.Segments[1].Mirror.DataDirectory = "/data/primary/gpseg0"
+
+		err := file.Validate()
+		expected := "duplicate data directory entry /data/primary/gpseg0 found for host sdw1\nduplicate port entry 7002 found for host sdw1"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out on unknown fields", func(t *testing.T) {
+		_, err := greenplum.ParseTopologyFile([]byte("version: 1\nmaster: {dbid: 1}\n"))
+		if err == nil || !strings.Contains(err.Error(), "field master not found") {
+			t.Fatalf("got %v, want an error for the unknown field", err)
+		}
+	})
+
+	t.Run("errors out on an unknown format", func(t *testing.T) {
+		_, err := greenplum.NewTopologyFile(newTopologyGpArray()).Marshal("toml")
+		expected := `invalid topology format "toml", expected json or yaml`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
+
+func TestReadTopologyFile(t *testing.T) {
+	t.Run("reads the gparray from the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "topology.json")
+		contents, err := greenplum.NewTopologyFile(newTopologyGpArray()).Marshal(greenplum.TopologyFormatFromPath(path))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = os.WriteFile(path, contents, 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		gparray, err := greenplum.ReadTopologyFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(gparray, newTopologyGpArray()) {
+			t.Fatalf("got %+v, want %+v", gparray, newTopologyGpArray())
+		}
+	})
+
+	t.Run("errors out with the path of an invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "topology.yaml")
+		err := os.WriteFile(path, []byte("version: 1\nsegments: []\n"), 0600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = greenplum.ReadTopologyFile(path)
+		expected := "invalid topology file " + path + ":\nthe coordinator is missing\nno segments found"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when the file can not be read", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing.yaml")
+
+		_, err := greenplum.ReadTopologyFile(path)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want a not exist error", err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package greenplum
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strconv"
@@ -15,9 +16,9 @@ import (
 
 var newDBConnFromEnvironment = dbconn.NewDBConnFromEnvironment
 
-func GetPostgresGpVersion(gpHome string) (string, error) {
+func GetPostgresGpVersion(ctx context.Context, gpHome string) (string, error) {
 	pgGpVersionCmd := &postgres.Postgres{GpVersion: true}
-	out, err := utils.RunGpCommand(pgGpVersionCmd, gpHome)
+	out, err := utils.RunGpCommand(ctx, pgGpVersionCmd, gpHome)
 	if err != nil {
 		return "", fmt.Errorf("fetching postgres gp-version: %w", err)
 	}
@@ -1,6 +1,7 @@
 package greenplum_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -31,7 +32,7 @@ func TestGetPostgresGpVersion(t *testing.T) {
 		expectedStr := "fetching postgres gp-version:"
 		utils.System.ExecCommand = exectest.NewCommand(exectest.Failure)
 		defer utils.ResetSystemFunctions()
-		_, err := greenplum.GetPostgresGpVersion("/gpHome")
+		_, err := greenplum.GetPostgresGpVersion(context.Background(), "/gpHome")
 		if err == nil || !strings.Contains(err.Error(), expectedStr) {
 			t.Fatalf("expected errror: `%s`, got error:`%v`", expectedStr, err)
 		}
@@ -40,7 +41,7 @@ func TestGetPostgresGpVersion(t *testing.T) {
 	t.Run("returns no error when command is successful", func(t *testing.T) {
 		utils.System.ExecCommand = exectest.NewCommand(exectest.Success)
 		defer utils.ResetSystemFunctions()
-		_, err := greenplum.GetPostgresGpVersion("/gpHome")
+		_, err := greenplum.GetPostgresGpVersion(context.Background(), "/gpHome")
 		if err != nil {
 			t.Fatalf("expected no errror, got error:`%v`", err)
 		}
@@ -50,7 +51,7 @@ func TestGetPostgresGpVersion(t *testing.T) {
 		expectedStr := "test-version-1234"
 		utils.System.ExecCommand = exectest.NewCommand(PgVersionCmd)
 		defer utils.ResetSystemFunctions()
-		version, err := greenplum.GetPostgresGpVersion("/gpHome")
+		version, err := greenplum.GetPostgresGpVersion(context.Background(), "/gpHome")
 		if err != nil || !strings.Contains(version, expectedStr) {
 			t.Fatalf("expected version: `%s`, got version:`%v`", expectedStr, version)
 		}
@@ -0,0 +1,377 @@
+package greenplum
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+type ValidateOptions struct {
+	// MirroringType checks that the mirrors are placed with group, spread, block or rack-aware mirroring, when set
+	MirroringType string
+	// BlockSize is the number of hosts of a block with block mirroring
+	BlockSize int
+	// FailureDomains maps the hostnames to their failure domain, checking that no mirror shares the domain of its primary
+	FailureDomains map[string]string
+}
+
+/*
+Validate checks the layout of the segments of the gparray, as created by the
+catalog, the init config or a topology file. It returns the warnings about a
+layout which works but is not advised, and an error with every problem which
+prevents it from working, one per line.
+
+The mirrors replicate through the port of the segment, so there are no
+replication ports to check besides the segment ports.
+*/
+func (g *GpArray) Validate(opts ValidateOptions) (warnings []string, err error) {
+	var errs []error
+	addError := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	var segs []*Segment
+	if g.Coordinator == nil {
+		addError("the coordinator is missing")
+	} else {
+		if g.Coordinator.Content != -1 {
+			addError("the coordinator has content %d, expected -1", g.Coordinator.Content)
+		}
+		segs = append(segs, g.Coordinator)
+	}
+	if g.Standby != nil {
+		if g.Standby.Content != -1 {
+			addError("the standby has content %d, expected -1", g.Standby.Content)
+		}
+		segs = append(segs, g.Standby)
+	}
+
+	if len(g.SegmentPairs) == 0 {
+		addError("no segments found")
+	}
+	var pairs []SegmentPair
+	mirrors := 0
+	contents := make(map[int]bool)
+	for i, pair := range g.SegmentPairs {
+		if pair.Primary == nil {
+			addError("no primary segment found for segment pair %d", i+1)
+			continue
+		}
+
+		content := pair.Primary.Content
+		if content < 0 {
+			addError("invalid content %d for the primary with dbid %d", content, pair.Primary.Dbid)
+		} else if contents[content] {
+			addError("content %d is used by more than one segment pair", content)
+		}
+		contents[content] = true
+
+		segs = append(segs, pair.Primary)
+		if pair.Mirror != nil {
+			mirrors++
+			if pair.Mirror.Content != content {
+				addError("the mirror with dbid %d has content %d, expected the content %d of its primary", pair.Mirror.Dbid, pair.Mirror.Content, content)
+			}
+			segs = append(segs, pair.Mirror)
+		}
+		pairs = append(pairs, pair)
+	}
+	for content := 0; content < len(g.SegmentPairs); content++ {
+		if !contents[content] {
+			addError("content %d is missing, the contents have to go from 0 to %d", content, len(g.SegmentPairs)-1)
+		}
+	}
+	if mirrors != 0 && mirrors != len(pairs) {
+		addError("number of primary segments %d and number of mirror segments %d must be equal", len(pairs), mirrors)
+	}
+
+	dbids := make(map[int]bool)
+	for _, seg := range segs {
+		// the dbids of the segments of the init config are only assigned when registering them
+		if seg.Dbid != 0 && dbids[seg.Dbid] {
+			addError("duplicate dbid %d found", seg.Dbid)
+		}
+		dbids[seg.Dbid] = true
+	}
+	errs = append(errs, duplicatePortAndDataDirectory(segs)...)
+
+	singleHost := len(g.GetHostnames()) == 1
+	for _, pair := range pairs {
+		if pair.Mirror != nil && pair.Mirror.Hostname == pair.Primary.Hostname {
+			if singleHost {
+				warnings = append(warnings, fmt.Sprintf("the mirror of content %d is on the same host %s as its primary", pair.Primary.Content, pair.Primary.Hostname))
+			} else {
+				addError("the mirror of content %d is on the same host %s as its primary", pair.Primary.Content, pair.Primary.Hostname)
+			}
+		}
+	}
+
+	if mirrors != 0 && opts.MirroringType != "" {
+		errs = append(errs, checkMirroring(pairs, opts)...)
+	}
+	if mirrors != 0 && len(opts.FailureDomains) != 0 {
+		errs = append(errs, checkFailureDomains(pairs, opts.FailureDomains)...)
+	}
+
+	warnings = append(warnings, unevenPrimaries(pairs)...)
+	warnings = append(warnings, overlappingPorts(g, pairs)...)
+
+	return warnings, errors.Join(errs...)
+}
+
+/*
+CheckForDuplicatePortAndDataDirectory checks that no two segments of a host use
+the same data directory or the same port.
+*/
+func CheckForDuplicatePortAndDataDirectory(segs []Segment) error {
+	var segPtrs []*Segment
+	for i := range segs {
+		segPtrs = append(segPtrs, &segs[i])
+	}
+
+	return errors.Join(duplicatePortAndDataDirectory(segPtrs)...)
+}
+
+func duplicatePortAndDataDirectory(segs []*Segment) []error {
+	var errs []error
+	dataDirs := make(map[string]bool)
+	ports := make(map[string]bool)
+	for _, seg := range segs {
+		dataDir := fmt.Sprintf("%s:%s", seg.Hostname, seg.DataDir)
+		if dataDirs[dataDir] {
+			errs = append(errs, fmt.Errorf("duplicate data directory entry %v found for host %v", seg.DataDir, seg.Hostname))
+		}
+		dataDirs[dataDir] = true
+
+		port := fmt.Sprintf("%s:%d", seg.Hostname, seg.Port)
+		if ports[port] {
+			errs = append(errs, fmt.Errorf("duplicate port entry %v found for host %v", seg.Port, seg.Hostname))
+		}
+		ports[port] = true
+	}
+
+	return errs
+}
+
+/*
+checkMirroring checks that the mirrors of the primaries of each host are all
+on another single host with group mirroring, each on a different host with
+spread mirroring, each on a different host of its block with block mirroring,
+or on hosts with a declared failure domain with rack-aware mirroring.
+*/
+func checkMirroring(pairs []SegmentPair, opts ValidateOptions) []error {
+	mirroringType := opts.MirroringType
+	if mirroringType == constants.BlockMirroring && opts.BlockSize < 2 {
+		return []error{fmt.Errorf("invalid block size %d for block mirroring, expected at least 2 hosts per block", opts.BlockSize)}
+	}
+
+	var hosts []string
+	mirrorHosts := make(map[string][]string)
+	for _, pair := range pairs {
+		host := pair.Primary.Hostname
+		if _, ok := mirrorHosts[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		mirrorHosts[host] = append(mirrorHosts[host], pair.Mirror.Hostname)
+	}
+
+	var errs []error
+	missingDomains := make(map[string]bool)
+	for _, host := range hosts {
+		counts := make(map[string]int)
+		var distinct []string
+		for _, mirrorHost := range mirrorHosts[host] {
+			if counts[mirrorHost] == 0 {
+				distinct = append(distinct, mirrorHost)
+			}
+			counts[mirrorHost]++
+		}
+
+		switch mirroringType {
+		case constants.GroupMirroring:
+			if len(distinct) > 1 {
+				errs = append(errs, fmt.Errorf("group mirroring places the mirrors of the primaries of host %s on a single host, found them on %s", host, strings.Join(distinct, ", ")))
+			}
+
+		case constants.SpreadMirroring, constants.BlockMirroring:
+			for _, mirrorHost := range distinct {
+				if counts[mirrorHost] > 1 {
+					errs = append(errs, fmt.Errorf("%s mirroring places the mirrors of the primaries of host %s on different hosts, found %d of them on host %s", mirroringType, host, counts[mirrorHost], mirrorHost))
+				}
+			}
+			if mirroringType == constants.BlockMirroring && len(distinct) >= opts.BlockSize {
+				errs = append(errs, fmt.Errorf("block mirroring places the mirrors of the primaries of host %s within its block of %d hosts, found them on %s", host, opts.BlockSize, strings.Join(distinct, ", ")))
+			}
+
+		case constants.RackAwareMirroring:
+			for _, name := range append([]string{host}, distinct...) {
+				if opts.FailureDomains[name] == "" && !missingDomains[name] {
+					errs = append(errs, fmt.Errorf("rack-aware mirroring needs the failure domain of every host, none found for host %s", name))
+					missingDomains[name] = true
+				}
+			}
+
+		default:
+			return []error{fmt.Errorf("invalid mirroring type %q, expected %s, %s, %s or %s", mirroringType,
+				constants.GroupMirroring, constants.SpreadMirroring, constants.BlockMirroring, constants.RackAwareMirroring)}
+		}
+	}
+
+	return errs
+}
+
+// checkFailureDomains checks that no mirror is in the same failure domain as its primary, for the hosts with a declared domain
+func checkFailureDomains(pairs []SegmentPair, failureDomains map[string]string) []error {
+	var errs []error
+	for _, pair := range pairs {
+		domain := failureDomains[pair.Primary.Hostname]
+		if domain != "" && failureDomains[pair.Mirror.Hostname] == domain {
+			errs = append(errs, fmt.Errorf("the mirror of content %d is in the same failure domain %s as its primary", pair.Primary.Content, domain))
+		}
+	}
+
+	return errs
+}
+
+// unevenPrimaries warns when the hosts do not run the same number of primaries, as the most loaded one limits the cluster
+func unevenPrimaries(pairs []SegmentPair) []string {
+	counts := make(map[string]int)
+	for _, pair := range pairs {
+		counts[pair.Primary.Hostname]++
+	}
+
+	var hosts []string
+	for host := range counts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	var details []string
+	uneven := false
+	for _, host := range hosts {
+		details = append(details, fmt.Sprintf("%s has %d", host, counts[host]))
+		uneven = uneven || counts[host] != counts[hosts[0]]
+	}
+	if !uneven {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("the hosts do not have the same number of primaries: %s", strings.Join(details, ", "))}
+}
+
+type portRange struct {
+	min, max int
+}
+
+func (r *portRange) add(port int) *portRange {
+	if r == nil {
+		return &portRange{min: port, max: port}
+	}
+	r.min = min(r.min, port)
+	r.max = max(r.max, port)
+
+	return r
+}
+
+func (r *portRange) contains(port int) bool {
+	return r != nil && port >= r.min && port <= r.max
+}
+
+func (r *portRange) overlaps(other *portRange) bool {
+	return r != nil && other != nil && r.min <= other.max && other.min <= r.max
+}
+
+/*
+overlappingPorts warns when the ports of the coordinator or the standby fall
+within the ports of the primaries or the mirrors of their host, or when the
+ports of the primaries and of the mirrors of a host overlap, as allocating the
+ports of new segments from a base port would collide with them.
+*/
+func overlappingPorts(g *GpArray, pairs []SegmentPair) []string {
+	primaryPorts := make(map[string]*portRange)
+	mirrorPorts := make(map[string]*portRange)
+	for _, pair := range pairs {
+		primaryPorts[pair.Primary.Hostname] = primaryPorts[pair.Primary.Hostname].add(pair.Primary.Port)
+		if pair.Mirror != nil {
+			mirrorPorts[pair.Mirror.Hostname] = mirrorPorts[pair.Mirror.Hostname].add(pair.Mirror.Port)
+		}
+	}
+
+	var warnings []string
+	for _, qd := range []struct {
+		name string
+		seg  *Segment
+	}{{"coordinator", g.Coordinator}, {"standby", g.Standby}} {
+		if qd.seg == nil {
+			continue
+		}
+		if r := primaryPorts[qd.seg.Hostname]; r.contains(qd.seg.Port) {
+			warnings = append(warnings, fmt.Sprintf("the %s port %d on host %s is within the ports %d-%d of the primaries", qd.name, qd.seg.Port, qd.seg.Hostname, r.min, r.max))
+		}
+		if r := mirrorPorts[qd.seg.Hostname]; r.contains(qd.seg.Port) {
+			warnings = append(warnings, fmt.Sprintf("the %s port %d on host %s is within the ports %d-%d of the mirrors", qd.name, qd.seg.Port, qd.seg.Hostname, r.min, r.max))
+		}
+	}
+
+	var hosts []string
+	for host := range primaryPorts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		primaries, mirrors := primaryPorts[host], mirrorPorts[host]
+		if primaries.overlaps(mirrors) {
+			warnings = append(warnings, fmt.Sprintf("the ports %d-%d of the primaries and %d-%d of the mirrors on host %s overlap", primaries.min, primaries.max, mirrors.min, mirrors.max, host))
+		}
+	}
+
+	return warnings
+}
+
+/*
+NewGpArrayFromIdl returns the gparray of the segments of a MakeCluster request,
+with the contents and dbids the catalog assigns when registering them: the
+contents in the order of the segment pairs, the coordinator as dbid 1, then the
+primaries and then the mirrors.
+*/
+func NewGpArrayFromIdl(gparray *idl.GpArray) *GpArray {
+	result := &GpArray{}
+	if gparray.Coordinator != nil {
+		result.Coordinator = segmentFromIdl(gparray.Coordinator, 1, -1, constants.RolePrimary)
+	}
+
+	dbid := 2
+	for content, pair := range gparray.SegmentArray {
+		var primary *Segment
+		if pair.Primary != nil {
+			primary = segmentFromIdl(pair.Primary, dbid, content, constants.RolePrimary)
+			dbid++
+		}
+		result.SegmentPairs = append(result.SegmentPairs, SegmentPair{Primary: primary})
+	}
+	for content, pair := range gparray.SegmentArray {
+		if pair.Mirror != nil {
+			result.SegmentPairs[content].Mirror = segmentFromIdl(pair.Mirror, dbid, content, constants.RoleMirror)
+			dbid++
+		}
+	}
+
+	return result
+}
+
+func segmentFromIdl(seg *idl.Segment, dbid int, content int, role string) *Segment {
+	return &Segment{
+		Dbid:          dbid,
+		Content:       content,
+		Role:          role,
+		PreferredRole: role,
+		Port:          int(seg.Port),
+		Hostname:      seg.HostName,
+		Address:       seg.HostAddress,
+		DataDir:       seg.DataDirectory,
+	}
+}
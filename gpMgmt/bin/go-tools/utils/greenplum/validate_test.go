@@ -0,0 +1,229 @@
+package greenplum_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils/greenplum"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("succeeds for a valid layout", func(t *testing.T) {
+		for _, mirroringType := range []string{"", constants.GroupMirroring, constants.SpreadMirroring} {
+			warnings, err := newTopologyGpArray().Validate(greenplum.ValidateOptions{MirroringType: mirroringType})
+			if err != nil || len(warnings) != 0 {
+				t.Fatalf("got warnings %v and error %v, want none", warnings, err)
+			}
+		}
+	})
+
+	t.Run("reports missing contents and mismatched mirrors", func(t *testing.T) {
+		gparray := newTopologyGpArray()
+		gparray.Coordinator = nil
+		gparray.SegmentPairs[1].Primary.Content = 2
+		gparray.SegmentPairs[1].Mirror.Content = 1
+		gparray.SegmentPairs[0].Mirror.Dbid = 5
+
+		_, err := gparray.Validate(greenplum.ValidateOptions{})
+
+		expected := []string{
+			"the coordinator is missing",
+			"the mirror with dbid 5 has content 1, expected the content 2 of its primary",
+			"content 1 is missing, the contents have to go from 0 to 1",
+			"duplicate dbid 5 found",
+		}
+		if err == nil || err.Error() != strings.Join(expected, "\n") {
+			t.Fatalf("got %v, want %s", err, strings.Join(expected, "\n"))
+		}
+	})
+
+	t.Run("reports the mirrors on the same host as their primary", func(t *testing.T) {
+		gparray := newTopologyGpArray()
+		gparray.SegmentPairs[0].Mirror.Hostname = "sdw1"
+		gparray.SegmentPairs[0].Mirror.Address = "sdw1"
+		gparray.SegmentPairs[0].Mirror.Port = 7004
+
+		_, err := gparray.Validate(greenplum.ValidateOptions{})
+
+		expected := "the mirror of content 0 is on the same host sdw1 as its primary"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("only warns about the mirrors on the same host as their primary for a single host", func(t *testing.T) {
+		gparray := newTopologyGpArray()
+		gparray.Standby = nil
+		for _, seg := range []*greenplum.Segment{gparray.Coordinator, gparray.SegmentPairs[0].Primary, gparray.SegmentPairs[0].Mirror, gparray.SegmentPairs[1].Primary, gparray.SegmentPairs[1].Mirror} {
+			seg.Hostname = "localhost"
+			seg.Address = "localhost"
+		}
+		gparray.SegmentPairs[1].Primary.Port = 7004
+		gparray.SegmentPairs[1].Mirror.Port = 7005
+
+		warnings, err := gparray.Validate(greenplum.ValidateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []string{
+			"the mirror of content 0 is on the same host localhost as its primary",
+			"the mirror of content 1 is on the same host localhost as its primary",
+			"the ports 7002-7004 of the primaries and 7003-7005 of the mirrors on host localhost overlap",
+		}
+		if !reflect.DeepEqual(warnings, expected) {
+			t.Fatalf("got %v, want %v", warnings, expected)
+		}
+	})
+
+	t.Run("checks the placement of the mirrors", func(t *testing.T) {
+		gparray := newTopologyGpArray()
+		gparray.SegmentPairs = append(gparray.SegmentPairs,
+			greenplum.SegmentPair{
+				Primary: &greenplum.Segment{Dbid: 7, Content: 2, Role: constants.RolePrimary, Port: 7004, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/primary/gpseg2"},
+				Mirror:  &greenplum.Segment{Dbid: 9, Content: 2, Role: constants.RoleMirror, Port: 7005, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/mirror/gpseg2"},
+			},
+			greenplum.SegmentPair{
+				Primary: &greenplum.Segment{Dbid: 8, Content: 3, Role: constants.RolePrimary, Port: 7004, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/primary/gpseg3"},
+				Mirror:  &greenplum.Segment{Dbid: 10, Content: 3, Role: constants.RoleMirror, Port: 7005, Hostname: "sdw3", Address: "sdw3", DataDir: "/data/mirror/gpseg3"},
+			},
+		)
+
+		_, err := gparray.Validate(greenplum.ValidateOptions{MirroringType: constants.GroupMirroring})
+		expected := "group mirroring places the mirrors of the primaries of host sdw2 on a single host, found them on sdw1, sdw3"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		_, err = gparray.Validate(greenplum.ValidateOptions{MirroringType: constants.SpreadMirroring})
+		expected = "spread mirroring places the mirrors of the primaries of host sdw1 on different hosts, found 2 of them on host sdw2"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		_, err = gparray.Validate(greenplum.ValidateOptions{MirroringType: constants.BlockMirroring, BlockSize: 3})
+		expected = "block mirroring places the mirrors of the primaries of host sdw1 on different hosts, found 2 of them on host sdw2"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		_, err = gparray.Validate(greenplum.ValidateOptions{MirroringType: "ring"})
+		expected = `invalid mirroring type "ring", expected group, spread, block or rack-aware`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("checks the blocks and the failure domains of the mirrors", func(t *testing.T) {
+		gparray := newTopologyGpArray()
+
+		_, err := gparray.Validate(greenplum.ValidateOptions{MirroringType: constants.BlockMirroring, BlockSize: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = gparray.Validate(greenplum.ValidateOptions{MirroringType: constants.BlockMirroring})
+		expected := "invalid block size 0 for block mirroring, expected at least 2 hosts per block"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		_, err = gparray.Validate(greenplum.ValidateOptions{MirroringType: constants.RackAwareMirroring, FailureDomains: map[string]string{"sdw1": "rack1"}})
+		expected = "rack-aware mirroring needs the failure domain of every host, none found for host sdw2"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		_, err = gparray.Validate(greenplum.ValidateOptions{FailureDomains: map[string]string{"sdw1": "rack1", "sdw2": "rack1"}})
+		expected = "the mirror of content 0 is in the same failure domain rack1 as its primary\nthe mirror of content 1 is in the same failure domain rack1 as its primary"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		_, err = gparray.Validate(greenplum.ValidateOptions{MirroringType: constants.RackAwareMirroring, FailureDomains: map[string]string{"sdw1": "rack1", "sdw2": "rack2"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("warns about uneven primaries and the coordinator port within the segment ports", func(t *testing.T) {
+		gparray := newTopologyGpArray()
+		gparray.Standby = nil
+		gparray.Coordinator.Hostname = "sdw1"
+		gparray.Coordinator.Port = 7003
+		gparray.SegmentPairs[1].Mirror.Port = 7004
+		gparray.SegmentPairs = append(gparray.SegmentPairs, greenplum.SegmentPair{
+			Primary: &greenplum.Segment{Dbid: 7, Content: 2, Role: constants.RolePrimary, Port: 7005, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/primary/gpseg2"},
+			Mirror:  &greenplum.Segment{Dbid: 8, Content: 2, Role: constants.RoleMirror, Port: 7005, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/mirror/gpseg2"},
+		})
+
+		warnings, err := gparray.Validate(greenplum.ValidateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []string{
+			"the hosts do not have the same number of primaries: sdw1 has 2, sdw2 has 1",
+			"the coordinator port 7003 on host sdw1 is within the ports 7002-7005 of the primaries",
+			"the ports 7002-7005 of the primaries and 7004-7004 of the mirrors on host sdw1 overlap",
+		}
+		if !reflect.DeepEqual(warnings, expected) {
+			t.Fatalf("got %v, want %v", warnings, expected)
+		}
+	})
+}
+
+func TestCheckForDuplicatePortAndDataDirectory(t *testing.T) {
+	t.Run("reports the ports and data directories used twice on a host", func(t *testing.T) {
+		segs := []greenplum.Segment{
+			{Port: 7002, Hostname: "sdw1", DataDir: "/data/primary/gpseg0"},
+			{Port: 7002, Hostname: "sdw2", DataDir: "/data/primary/gpseg0"},
+			{Port: 7002, Hostname: "sdw1", DataDir: "/data/primary/gpseg1"},
+			{Port: 7003, Hostname: "sdw2", DataDir: "/data/primary/gpseg0"},
+		}
+
+		err := greenplum.CheckForDuplicatePortAndDataDirectory(segs)
+		expected := "duplicate port entry 7002 found for host sdw1\nduplicate data directory entry /data/primary/gpseg0 found for host sdw2"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
+
+func TestNewGpArrayFromIdl(t *testing.T) {
+	t.Run("assigns the contents and dbids of the catalog", func(t *testing.T) {
+		gparray := greenplum.NewGpArrayFromIdl(&idl.GpArray{
+			Coordinator: &idl.Segment{Port: 7000, HostName: "cdw", HostAddress: "cdw", DataDirectory: "/data/coordinator/gpseg-1"},
+			SegmentArray: []*idl.SegmentPair{
+				{
+					Primary: &idl.Segment{Port: 7002, HostName: "sdw1", HostAddress: "sdw1", DataDirectory: "/data/primary/gpseg0"},
+					Mirror:  &idl.Segment{Port: 7003, HostName: "sdw2", HostAddress: "sdw2", DataDirectory: "/data/mirror/gpseg0"},
+				},
+				{
+					Primary: &idl.Segment{Port: 7002, HostName: "sdw2", HostAddress: "sdw2", DataDirectory: "/data/primary/gpseg1"},
+					Mirror:  &idl.Segment{Port: 7003, HostName: "sdw1", HostAddress: "sdw1", DataDirectory: "/data/mirror/gpseg1"},
+				},
+			},
+		})
+
+		expected := &greenplum.GpArray{
+			Coordinator: &greenplum.Segment{Dbid: 1, Content: -1, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7000, Hostname: "cdw", Address: "cdw", DataDir: "/data/coordinator/gpseg-1"},
+			SegmentPairs: []greenplum.SegmentPair{
+				{
+					Primary: &greenplum.Segment{Dbid: 2, Content: 0, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7002, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/primary/gpseg0"},
+					Mirror:  &greenplum.Segment{Dbid: 4, Content: 0, Role: constants.RoleMirror, PreferredRole: constants.RoleMirror, Port: 7003, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/mirror/gpseg0"},
+				},
+				{
+					Primary: &greenplum.Segment{Dbid: 3, Content: 1, Role: constants.RolePrimary, PreferredRole: constants.RolePrimary, Port: 7002, Hostname: "sdw2", Address: "sdw2", DataDir: "/data/primary/gpseg1"},
+					Mirror:  &greenplum.Segment{Dbid: 5, Content: 1, Role: constants.RoleMirror, PreferredRole: constants.RoleMirror, Port: 7003, Hostname: "sdw1", Address: "sdw1", DataDir: "/data/mirror/gpseg1"},
+				},
+			},
+		}
+		if !reflect.DeepEqual(gparray, expected) {
+			t.Fatalf("got %+v, want %+v", gparray, expected)
+		}
+	})
+}
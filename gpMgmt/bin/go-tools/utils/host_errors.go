@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+)
+
+// HostError is the failure of a request the hub made to the agent of a host
+type HostError struct {
+	Host    string
+	Segment string // data directory of the segment, if the request was about one
+	RPC     string // agent RPC which failed, if known
+	Err     error
+}
+
+/*
+NewHostError describes the failure of the agent RPC made for the segment, which
+may be empty. The host is filled in by the fan-out running the request.
+*/
+func NewHostError(rpc string, segment string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &HostError{RPC: rpc, Segment: segment, Err: err}
+}
+
+func (e *HostError) Error() string {
+	if e.Segment != "" {
+		return fmt.Sprintf("host: %s, segment: %s, %v", e.Host, e.Segment, e.Err)
+	}
+
+	return fmt.Sprintf("host: %s, %v", e.Host, e.Err)
+}
+
+func (e *HostError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the gRPC status code of the failure
+func (e *HostError) Code() codes.Code {
+	if errors.Is(e.Err, context.Canceled) || errors.Is(e.Err, context.DeadlineExceeded) {
+		return status.FromContextError(e.Err).Code()
+	}
+
+	return status.Code(e.Err)
+}
+
+func (e *HostError) toIdl() *idl.HostError {
+	message := e.Err.Error()
+	if s, ok := status.FromError(e.Err); ok {
+		message = s.Message()
+	}
+
+	return &idl.HostError{
+		Host:    e.Host,
+		Segment: e.Segment,
+		Rpc:     e.RPC,
+		Code:    int32(e.Code()),
+		Message: message,
+	}
+}
+
+/*
+HostErrors aggregates the failures of the requests the hub made to the agents,
+so that all of them are reported at once instead of only the first one. They
+are carried to the CLI as a detail of the status of the failed RPC.
+*/
+type HostErrors []*HostError
+
+func (e HostErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e HostErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// ToIdl converts the failures to be sent in a reply
+func (e HostErrors) ToIdl() []*idl.HostError {
+	if len(e) == 0 {
+		return nil
+	}
+
+	result := make([]*idl.HostError, len(e))
+	for i, err := range e {
+		result[i] = err.toIdl()
+	}
+
+	return result
+}
+
+// HostErrorsFromIdl converts the failures received in a reply back to errors
+func HostErrorsFromIdl(errs []*idl.HostError) HostErrors {
+	result := make(HostErrors, len(errs))
+	for i, err := range errs {
+		result[i] = &HostError{
+			Host:    err.Host,
+			Segment: err.Segment,
+			RPC:     err.Rpc,
+			Err:     NewGrpcError(codes.Code(err.Code), errors.New(err.Message)),
+		}
+	}
+
+	return result
+}
+
+// GRPCStatus has the code shared by all the failures, or Unknown if they differ
+func (e HostErrors) GRPCStatus() *status.Status {
+	code := codes.Unknown
+	for i, err := range e {
+		if i == 0 {
+			code = err.Code()
+		} else if err.Code() != code {
+			code = codes.Unknown
+		}
+	}
+	details := &idl.HostErrors{Errors: e.ToIdl()}
+
+	s := status.New(code, e.Error())
+	withDetails, err := s.WithDetails(details)
+	if err != nil {
+		return s
+	}
+
+	return withDetails
+}
+
+/*
+HostErrorsOf returns the failures of the requests to the agents which made the
+RPC fail, whether the error comes from the hub or from a fan-out in the same
+process. It returns nil if the error was not caused by any such failure.
+*/
+func HostErrorsOf(err error) []*idl.HostError {
+	if err == nil {
+		return nil
+	}
+
+	var hostErrs HostErrors
+	if errors.As(err, &hostErrs) {
+		return hostErrs.ToIdl()
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+
+	for _, detail := range s.Details() {
+		if details, ok := detail.(*idl.HostErrors); ok {
+			return details.Errors
+		}
+	}
+
+	return nil
+}
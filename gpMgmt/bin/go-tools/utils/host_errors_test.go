@@ -0,0 +1,77 @@
+package utils_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpdb/gp/idl"
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+func TestHostErrors(t *testing.T) {
+	errs := utils.HostErrors{
+		{Host: "sdw1", Segment: "/data/primary/gpseg0", RPC: "MakeSegment", Err: status.Error(codes.Internal, "initdb failed")},
+		{Host: "sdw2", RPC: "ValidateHostEnv", Err: context.DeadlineExceeded},
+	}
+
+	expected := []*idl.HostError{
+		{Host: "sdw1", Segment: "/data/primary/gpseg0", Rpc: "MakeSegment", Code: int32(codes.Internal), Message: "initdb failed"},
+		{Host: "sdw2", Rpc: "ValidateHostEnv", Code: int32(codes.DeadlineExceeded), Message: "context deadline exceeded"},
+	}
+
+	t.Run("reports all the failures", func(t *testing.T) {
+		expected := "host: sdw1, segment: /data/primary/gpseg0, rpc error: code = Internal desc = initdb failed\nhost: sdw2, context deadline exceeded"
+		if errs.Error() != expected {
+			t.Fatalf("got %q, want %q", errs.Error(), expected)
+		}
+
+		if !errors.Is(errs, context.DeadlineExceeded) {
+			t.Fatalf("got %v, want it to wrap %v", errs, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("has the code shared by all the failures", func(t *testing.T) {
+		if code := status.Code(errs); code != codes.Unknown {
+			t.Fatalf("got %s, want %s", code, codes.Unknown)
+		}
+
+		if code := status.Code(errs[:1]); code != codes.Internal {
+			t.Fatalf("got %s, want %s", code, codes.Internal)
+		}
+	})
+
+	t.Run("returns the failures of the errors of the same process", func(t *testing.T) {
+		result := utils.HostErrorsOf(fmt.Errorf("wrapped: %w", errs))
+		if !reflect.DeepEqual(result, expected) {
+			t.Fatalf("got %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("returns the failures carried by the status of the RPC", func(t *testing.T) {
+		// as received by the CLI
+		rpcErr := status.ErrorProto(status.Convert(fmt.Errorf("wrapped: %w", errs)).Proto())
+
+		result := utils.HostErrorsOf(fmt.Errorf("wrapped: %w", utils.FormatGrpcError(rpcErr)))
+		if len(result) != len(expected) {
+			t.Fatalf("got %v, want %v", result, expected)
+		}
+		for i := range result {
+			if !proto.Equal(result[i], expected[i]) {
+				t.Fatalf("got %v, want %v", result[i], expected[i])
+			}
+		}
+	})
+
+	t.Run("returns nothing for the other errors", func(t *testing.T) {
+		if result := utils.HostErrorsOf(status.Error(codes.Internal, "error")); result != nil {
+			t.Fatalf("got %v, want nil", result)
+		}
+	})
+}
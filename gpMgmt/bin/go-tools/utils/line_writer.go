@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"bytes"
+	"sync"
+)
+
+/*
+LineWriter passes everything written to it to a function one line at a time.
+Both newlines and carriage returns end a line, as utilities reporting their
+progress on a terminal, like pg_basebackup, overwrite the same line with
+carriage returns. Empty lines are dropped.
+*/
+type LineWriter struct {
+	mutex sync.Mutex
+	line  func(string)
+	buf   []byte
+}
+
+func NewLineWriter(line func(string)) *LineWriter {
+	return &LineWriter{line: line}
+}
+
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush passes the last line to the function if it was not terminated
+func (w *LineWriter) Flush() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.emit(w.buf)
+	w.buf = nil
+}
+
+func (w *LineWriter) emit(line []byte) {
+	if len(line) > 0 {
+		w.line(string(line))
+	}
+}
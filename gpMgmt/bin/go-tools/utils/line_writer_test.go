@@ -0,0 +1,35 @@
+package utils_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpdb/gp/utils"
+)
+
+func TestLineWriter(t *testing.T) {
+	t.Run("passes the lines written to it one at a time", func(t *testing.T) {
+		var lines []string
+		writer := utils.NewLineWriter(func(line string) {
+			lines = append(lines, line)
+		})
+
+		for _, chunk := range []string{"first li", "ne\nsecond line\n\n", "  10/20 kB (50%)\r  20/20 kB (100%)\n", "last line"} {
+			_, err := writer.Write([]byte(chunk))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		expected := []string{"first line", "second line", "  10/20 kB (50%)", "  20/20 kB (100%)"}
+		if !reflect.DeepEqual(lines, expected) {
+			t.Fatalf("got %q, want %q", lines, expected)
+		}
+
+		writer.Flush()
+		expected = append(expected, "last line")
+		if !reflect.DeepEqual(lines, expected) {
+			t.Fatalf("got %q, want %q", lines, expected)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const metricsNamespace = "gp"
+
+var (
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "rpc_requests_total",
+		Help:      "Number of RPCs handled by the server, by method and status code.",
+	}, []string{"server", "method", "code"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Time taken by the server to handle the RPCs, by method.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 4, 10), // 5ms to ~20m, as creating a cluster takes minutes
+	}, []string{"server", "method"})
+
+	commandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "command_duration_seconds",
+		Help:      "Time taken by the Greenplum utilities like initdb, pg_ctl and pg_basebackup, by command and result.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 3, 10), // 100ms to ~30m
+	}, []string{"command", "result"})
+)
+
+/*
+NewMetricsInterceptors returns the server interceptors which count the RPCs and
+measure their duration. They are expected to be the first in the chain so that
+the calls rejected by the other interceptors are counted as well.
+*/
+func NewMetricsInterceptors(server string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		reply, err := handler(ctx, req)
+		observeRPC(server, info.FullMethod, start, err)
+
+		return reply, err
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeRPC(server, info.FullMethod, start, err)
+
+		return err
+	}
+
+	return unary, stream
+}
+
+func observeRPC(server string, method string, start time.Time, err error) {
+	rpcRequests.WithLabelValues(server, method, status.Code(err).String()).Inc()
+	rpcDuration.WithLabelValues(server, method).Observe(time.Since(start).Seconds())
+}
+
+// commandName returns the name of the command built by the given command builder, e.g. "Initdb" or "PgCtlStart"
+func commandName(cmdBuilder CommandBuilder) string {
+	builderType := reflect.TypeOf(cmdBuilder)
+	if builderType.Kind() == reflect.Pointer {
+		builderType = builderType.Elem()
+	}
+
+	return builderType.Name()
+}
+
+func observeCommand(name string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	commandDuration.WithLabelValues(name, result).Observe(time.Since(start).Seconds())
+}
+
+/*
+NewMetricsRegistry returns a registry with the RPC and command metrics, the Go
+runtime and process metrics, along with the given service specific collectors.
+*/
+func NewMetricsRegistry(serviceCollectors ...prometheus.Collector) (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+
+	all := []prometheus.Collector{
+		rpcRequests,
+		rpcDuration,
+		commandDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	}
+	for _, collector := range append(all, serviceCollectors...) {
+		err := registry.Register(collector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register the metrics: %w", err)
+		}
+	}
+
+	return registry, nil
+}
+
+/*
+StartMetricsServer serves the metrics of the registry over HTTP on the given
+port at /metrics, until the returned server is closed.
+*/
+func StartMetricsServer(port int, registry *prometheus.Registry) (*http.Server, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on metrics port %d: %w", port, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{
+		Addr:              listener.Addr().String(),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			gplog.Error("metrics server on port %d stopped: %v", port, err)
+		}
+	}()
+	gplog.Info("Serving metrics on port %d", port)
+
+	return server, nil
+}
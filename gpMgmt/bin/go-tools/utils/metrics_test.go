@@ -0,0 +1,144 @@
+package utils_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/testutils/exectest"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/postgres"
+)
+
+func TestMetrics(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	scrape := func(t *testing.T, collectors ...prometheus.Collector) string {
+		t.Helper()
+
+		registry, err := utils.NewMetricsRegistry(collectors...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		server, err := utils.StartMetricsServer(0, registry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer server.Close()
+
+		resp, err := http.Get("http://" + server.Addr + "/metrics")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return string(body)
+	}
+
+	assertContains := func(t *testing.T, metrics string, expected ...string) {
+		t.Helper()
+
+		for _, line := range expected {
+			if !strings.Contains(metrics, line) {
+				t.Fatalf("expected %q in metrics:\n%s", line, metrics)
+			}
+		}
+	}
+
+	t.Run("counts the RPCs by method and status code", func(t *testing.T) {
+		unary, stream := utils.NewMetricsInterceptors("agent")
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		}
+		failingHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.PermissionDenied, "denied")
+		}
+		info := &grpc.UnaryServerInfo{FullMethod: "/idl.Agent/MetricsTest"}
+		for _, h := range []grpc.UnaryHandler{handler, handler, failingHandler} {
+			_, _ = unary(context.Background(), nil, info, h)
+		}
+
+		err := stream(nil, contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/idl.Hub/MetricsTestStream"}, func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertContains(t, scrape(t),
+			`gp_rpc_requests_total{code="OK",method="/idl.Agent/MetricsTest",server="agent"} 2`,
+			`gp_rpc_requests_total{code="PermissionDenied",method="/idl.Agent/MetricsTest",server="agent"} 1`,
+			`gp_rpc_requests_total{code="OK",method="/idl.Hub/MetricsTestStream",server="agent"} 1`,
+			`gp_rpc_duration_seconds_count{method="/idl.Agent/MetricsTest",server="agent"} 3`,
+		)
+	})
+
+	t.Run("measures the duration of the commands", func(t *testing.T) {
+		utils.System.ExecCommand = exectest.NewCommand(CommandSuccess)
+		_, err := utils.RunGpCommand(context.Background(), &postgres.PgCtlReload{PgData: "pgdata"}, "gpHome")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		utils.System.ExecCommand = exectest.NewCommand(CommandFailure)
+		_, _ = utils.RunGpCommand(context.Background(), &postgres.PgCtlReload{PgData: "pgdata"}, "gpHome")
+		utils.ResetSystemFunctions()
+
+		assertContains(t, scrape(t),
+			`gp_command_duration_seconds_count{command="PgCtlReload",result="success"} 1`,
+			`gp_command_duration_seconds_count{command="PgCtlReload",result="failure"} 1`,
+		)
+	})
+
+	t.Run("serves the service specific metrics", func(t *testing.T) {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "gp_test_metric", Help: "Test metric."})
+		gauge.Set(42)
+
+		assertContains(t, scrape(t, gauge), "gp_test_metric 42")
+	})
+
+	t.Run("errors out when not able to listen on the metrics port", func(t *testing.T) {
+		registry, err := utils.NewMetricsRegistry()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		server, err := utils.StartMetricsServer(0, registry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer server.Close()
+
+		_, port, err := net.SplitHostPort(server.Addr)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		portNum, err := strconv.Atoi(port)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = utils.StartMetricsServer(portNum, registry)
+		expected := "could not listen on metrics port " + port
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+}
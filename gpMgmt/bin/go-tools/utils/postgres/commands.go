@@ -3,6 +3,8 @@ package postgres
 import (
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 
 	"github.com/greenplum-db/gpdb/gp/constants"
 	"github.com/greenplum-db/gpdb/gp/utils"
@@ -145,6 +147,32 @@ func (cmd *PgBasebackup) BuildExecCommand(gphome string) *exec.Cmd {
 	return utils.System.ExecCommand(utility, args...)
 }
 
+// matches the progress reported by pg_basebackup --progress, like "  1234/56789 kB (2%), 0/1 tablespace"
+var pgBasebackupProgressRegex = regexp.MustCompile(`^\s*(\d+)/(\d+) kB \(\d+%\)`)
+
+/*
+ParsePgBasebackupProgress returns the amount of data copied so far and the
+estimated total, in bytes, if the line of output of pg_basebackup reports its
+progress.
+*/
+func ParsePgBasebackupProgress(line string) (done int64, total int64, ok bool) {
+	match := pgBasebackupProgressRegex.FindStringSubmatch(line)
+	if match == nil {
+		return 0, 0, false
+	}
+
+	doneKB, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	totalKB, err := strconv.ParseInt(match[2], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return doneKB * 1024, totalKB * 1024, true
+}
+
 type PgControlData struct {
 	PgData string `flag:"--pgdata"`
 }
@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -132,7 +133,7 @@ func TestPgCommand(t *testing.T) {
 			PgData:   "pgdata",
 			Encoding: "encoding",
 		}
-		out, err := utils.RunGpCommand(pgCmdOptions, "gpHome")
+		out, err := utils.RunGpCommand(context.Background(), pgCmdOptions, "gpHome")
 		if err != nil {
 			t.Fatalf("unexpected error: %#v", err)
 		}
@@ -153,7 +154,7 @@ func TestPgCommand(t *testing.T) {
 			PgData:   "pgdata",
 			Encoding: "encoding",
 		}
-		out, err := utils.RunGpCommand(pgCmdOptions, "gpHome")
+		out, err := utils.RunGpCommand(context.Background(), pgCmdOptions, "gpHome")
 		if status, ok := err.(*exec.ExitError); !ok || status.ExitCode() != 1 {
 			t.Fatalf("unexpected error: %+v", err)
 		}
@@ -167,6 +168,29 @@ func TestPgCommand(t *testing.T) {
 	})
 }
 
+func TestParsePgBasebackupProgress(t *testing.T) {
+	cases := []struct {
+		line  string
+		done  int64
+		total int64
+		ok    bool
+	}{
+		{"  1024/4096 kB (25%), 0/1 tablespace (/data/mirror/gpseg0/base/1/1249   )", 1024 * 1024, 4096 * 1024, true},
+		{"4096/4096 kB (100%), 1/1 tablespace", 4096 * 1024, 4096 * 1024, true},
+		{"pg_basebackup: write-ahead log start point: 0/2000028 on timeline 1", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("parses %q", tc.line), func(t *testing.T) {
+			done, total, ok := postgres.ParsePgBasebackupProgress(tc.line)
+			if done != tc.done || total != tc.total || ok != tc.ok {
+				t.Fatalf("got (%d, %d, %t), want (%d, %d, %t)", done, total, ok, tc.done, tc.total, tc.ok)
+			}
+		})
+	}
+}
+
 func PgCommandSuccess() {
 	os.Stdout.WriteString("success")
 	os.Exit(0)
@@ -42,3 +42,29 @@ func NewProgressBar(instance *mpb.Progress, label string, size int) *mpb.Bar {
 
 	return bar
 }
+
+/*
+NewTransferBar creates a progress bar for a transfer whose size in bytes is
+only estimated, showing the transfer rate and the estimated time left. Its
+total is set along with its progress, and it is completed explicitly with
+SetTotal(-1, true) as the transfer may go beyond the estimate.
+*/
+func NewTransferBar(instance *mpb.Progress, label string) *mpb.Bar {
+	bar := instance.AddBar(0,
+		mpb.PrependDecorators(
+			decor.Name(label, decor.WC{W: len(label) + 1, C: decor.DidentRight}),
+			decor.Counters(decor.SizeB1024(0), "% .1f / % .1f"),
+		),
+		mpb.AppendDecorators(
+			decor.AverageSpeed(decor.SizeB1024(0), "% .1f", decor.WC{W: 12}),
+			decor.OnAbort(
+				decor.OnComplete(
+					decor.AverageETA(decor.ET_STYLE_GO, decor.WC{W: 6}), fmt.Sprintf("%sdone%s", green, reset),
+				),
+				fmt.Sprintf("%serror%s", red, reset),
+			),
+		),
+	)
+
+	return bar
+}
@@ -89,3 +89,24 @@ func TestProgressBar(t *testing.T) {
 		}
 	})
 }
+
+func TestTransferBar(t *testing.T) {
+	t.Run("shows the amount transferred and completes beyond the estimated total", func(t *testing.T) {
+		var buf bytes.Buffer
+		instance := utils.NewProgressInstance(&buf)
+		bar := utils.NewTransferBar(instance, "sdw1:/data/mirror/gpseg0")
+
+		bar.SetTotal(4096, false)
+		bar.SetCurrent(2048)
+		bar.SetTotal(4096, false)
+		bar.SetCurrent(5120)
+		bar.SetTotal(-1, true)
+		instance.Wait()
+
+		for _, expected := range []string{"sdw1:/data/mirror/gpseg0", "5.0 KiB / 5.0 KiB", "done"} {
+			if !bytes.Contains(buf.Bytes(), []byte(expected)) {
+				t.Fatalf("expected string %q not present in progress bar %q", expected, buf.String())
+			}
+		}
+	})
+}
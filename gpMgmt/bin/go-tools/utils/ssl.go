@@ -1,12 +1,22 @@
 package utils
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/exp/slices"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 type Credentials interface {
@@ -14,46 +24,363 @@ type Credentials interface {
 	LoadClientCredentials() (credentials.TransportCredentials, error)
 }
 
+// CertificateExpiryReporter is implemented by the credentials which can report the expiry of the served certificate
+type CertificateExpiryReporter interface {
+	GetCertificateExpiry() (time.Time, error)
+}
+
 type GpCredentials struct {
 	CACertPath     string `json:"caCert"`
 	CAKeyPath      string `json:"caKey"`
 	ServerCertPath string `json:"serverCert"`
 	ServerKeyPath  string `json:"serverKey"`
+
+	// Key pair presented when connecting to the hub or the agents. Falls back
+	// to the server key pair if not provided.
+	ClientCertPath string `json:"clientCert,omitempty"`
+	ClientKeyPath  string `json:"clientKey,omitempty"`
 }
 
+/*
+LoadServerCredentials returns the credentials used by the hub and the agents to
+serve requests. Clients are required to present a certificate signed by the CA.
+The certificate files are watched and reloaded on change, so that they can be
+rotated without restarting the services.
+*/
 func (c GpCredentials) LoadServerCredentials() (credentials.TransportCredentials, error) {
-	serverCert, err := tls.LoadX509KeyPair(c.ServerCertPath, c.ServerKeyPath)
+	certPool, err := newReloadable(c.loadCACertPool, c.CACertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	serverCert, err := newReloadable(func() (*tls.Certificate, error) {
+		serverCert, err := tls.LoadX509KeyPair(c.ServerCertPath, c.ServerKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not load server credentials: %w", err)
+		}
+
+		err = verifyCertificate(serverCert, certPool.Get(), x509.ExtKeyUsageServerAuth)
+		if err != nil {
+			return nil, fmt.Errorf("server certificate %s is not valid for the CA %s: %w", c.ServerCertPath, c.CACertPath, err)
+		}
+
+		return &serverCert, nil
+	}, c.ServerCertPath, c.ServerKeyPath)
 	if err != nil {
-		return nil, fmt.Errorf("could not load server credentials: %w", err)
+		return nil, err
 	}
 
 	config := &tls.Config{
-		Certificates: []tls.Certificate{serverCert},
-		ClientAuth:   tls.RequireAnyClientCert,
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				Certificates: []tls.Certificate{*serverCert.Get()},
+				ClientCAs:    certPool.Get(),
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"h2"}, // the returned config replaces the one prepared by gRPC
+			}, nil
+		},
 	}
 	return credentials.NewTLS(config), nil
 
 }
 
+/*
+LoadClientCredentials returns the credentials used by the CLI and the hub to
+connect to the hub and the agents respectively. The CA certificate and the
+client certificate are reloaded on change for the connections which are kept
+open by the hub, so that a new CA is trusted without restarting it.
+*/
 func (c GpCredentials) LoadClientCredentials() (credentials.TransportCredentials, error) {
+	certPool, err := newReloadable(c.loadCACertPool, c.CACertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	certPath, keyPath := c.ClientCertPath, c.ClientKeyPath
+	if certPath == "" && keyPath == "" {
+		certPath, keyPath = c.ServerCertPath, c.ServerKeyPath
+	}
+
+	clientCert, err := newReloadable(func() (*tls.Certificate, error) {
+		clientCert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("error while loading client certificate: %v", err)
+		}
+
+		err = verifyCertificate(clientCert, certPool.Get(), x509.ExtKeyUsageClientAuth)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate %s is not valid for the CA %s: %w", certPath, c.CACertPath, err)
+		}
+
+		return &clientCert, nil
+	}, certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return clientCert.Get(), nil
+		},
+		// RootCAs can not be reloaded, the server certificate is verified
+		// against the current CA by the handshake of clientCredentials instead
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	return &clientCredentials{TransportCredentials: credentials.NewTLS(config), config: config, certPool: certPool}, nil
+}
+
+/*
+clientCredentials verifies the certificate of the server against the current CA
+and the host dialed, which the TLS handshake does not give to VerifyConnection
+when it is an IP address.
+*/
+type clientCredentials struct {
+	credentials.TransportCredentials
+
+	config   *tls.Config
+	certPool *reloadable[*x509.CertPool]
+}
+
+func (c *clientCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	host := c.config.ServerName
+	if host == "" {
+		host = authority
+		if h, _, err := net.SplitHostPort(authority); err == nil {
+			host = h
+		}
+	}
+
+	config := c.config.Clone()
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		return verifyServerCertificate(state, c.certPool.Get(), host)
+	}
+
+	return credentials.NewTLS(config).ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *clientCredentials) Clone() credentials.TransportCredentials {
+	config := c.config.Clone()
+
+	return &clientCredentials{TransportCredentials: credentials.NewTLS(config), config: config, certPool: c.certPool}
+}
+
+func (c *clientCredentials) OverrideServerName(serverName string) error {
+	c.config.ServerName = serverName
+
+	return nil
+}
+
+// GetCertificateExpiry returns the expiry of the server certificate currently on disk
+func (c GpCredentials) GetCertificateExpiry() (time.Time, error) {
+	cert, err := ReadCertificate(c.ServerCertPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return cert.NotAfter, nil
+}
+
+func (c GpCredentials) loadCACertPool() (*x509.CertPool, error) {
 	caCert, err := os.ReadFile(c.CACertPath)
 	if err != nil {
-		return nil, fmt.Errorf("error while loading server certificate: %v", err)
+		return nil, fmt.Errorf("error while loading CA certificate: %v", err)
 	}
+
 	certPool := x509.NewCertPool()
 	if !certPool.AppendCertsFromPEM(caCert) {
 		return nil, fmt.Errorf("failed to add server CA's certificate")
 	}
 
-	clientCert, err := tls.LoadX509KeyPair(c.ServerCertPath, c.ServerKeyPath)
+	return certPool, nil
+}
+
+/*
+reloadable holds a value loaded from a set of files and reloads it whenever
+any of the files is modified. If the reload fails, e.g. while the files are
+being replaced, the previous value is kept and the reload is retried on the
+next access.
+*/
+type reloadable[T any] struct {
+	load  func() (T, error)
+	paths []string
+
+	mutex   sync.Mutex
+	value   T
+	modTime time.Time
+}
+
+func newReloadable[T any](load func() (T, error), paths ...string) (*reloadable[T], error) {
+	r := &reloadable[T]{load: load, paths: paths}
+
+	modTime := r.latestModTime()
+	value, err := load()
 	if err != nil {
-		return nil, fmt.Errorf("error while loading server certificate: %v", err)
+		return nil, err
 	}
+	r.value, r.modTime = value, modTime
 
-	config := &tls.Config{
-		Certificates: []tls.Certificate{clientCert},
-		RootCAs:      certPool,
+	return r, nil
+}
+
+func (r *reloadable[T]) Get() T {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	modTime := r.latestModTime()
+	if !modTime.After(r.modTime) {
+		return r.value
 	}
 
-	return credentials.NewTLS(config), nil
+	value, err := r.load()
+	if err != nil {
+		gplog.Warn("failed to reload %v, continuing to use the previous version: %v", r.paths, err)
+		return r.value
+	}
+	gplog.Info("Reloaded %v", r.paths)
+	r.value, r.modTime = value, modTime
+
+	return r.value
+}
+
+func (r *reloadable[T]) latestModTime() time.Time {
+	var latest time.Time
+	for _, path := range r.paths {
+		info, err := System.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest
+}
+
+// verifyCertificate checks that the certificate is signed by the CA and can be used for the given purpose
+func verifyCertificate(cert tls.Certificate, certPool *x509.CertPool, usage x509.ExtKeyUsage) error {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, der := range cert.Certificate[1:] {
+		intermediate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return err
+		}
+		intermediates.AddCert(intermediate)
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         certPool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+
+	return err
+}
+
+// verifyServerCertificate does the verification of the server certificate skipped by the TLS handshake
+func verifyServerCertificate(state tls.ConnectionState, certPool *x509.CertPool, host string) error {
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("server did not present a certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, intermediate := range state.PeerCertificates[1:] {
+		intermediates.AddCert(intermediate)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         certPool,
+		Intermediates: intermediates,
+		DNSName:       host,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+
+	return err
+}
+
+/*
+NewClientAuthorizer returns the server interceptors which only allow the
+clients whose certificate common name or one of the subject alternative names
+is in the allowed list. An empty list allows any client with a certificate
+signed by the CA.
+*/
+func NewClientAuthorizer(server string, allowed []string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		err := authorizeClient(ctx, server, allowed)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := authorizeClient(ss.Context(), server, allowed)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
+
+func authorizeClient(ctx context.Context, server string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	cert := GetPeerCertificate(ctx)
+	if cert == nil {
+		return LogAndReturnError(status.Errorf(codes.Unauthenticated, "client did not present a certificate to the %s", server))
+	}
+
+	for _, identity := range GetCertificateIdentities(cert) {
+		if slices.Contains(allowed, identity) {
+			return nil
+		}
+	}
+
+	return LogAndReturnError(status.Errorf(codes.PermissionDenied, "client certificate %q is not allowed to access the %s, allowed clients are %v",
+		cert.Subject.CommonName, server, allowed))
+}
+
+// GetPeerCertificate returns the certificate presented by the peer of the gRPC call, if any
+func GetPeerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil
+	}
+
+	return tlsInfo.State.PeerCertificates[0]
+}
+
+// GetCertificateIdentities returns the common name and subject alternative names of the certificate
+func GetCertificateIdentities(cert *x509.Certificate) []string {
+	identities := []string{cert.Subject.CommonName}
+	identities = append(identities, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		identities = append(identities, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+
+	return identities
 }
@@ -1,15 +1,41 @@
 package utils_test
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
 	"github.com/greenplum-db/gpdb/gp/constants"
+	"github.com/greenplum-db/gpdb/gp/testutils"
 
 	"github.com/greenplum-db/gpdb/gp/utils"
 )
 
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
 func TestLoadServerCredentials(t *testing.T) {
 	err := exec.Command(constants.ShellPath, "-c", "../generate_test_tls_certificates.sh `hostname`").Run()
 	if err != nil {
@@ -93,3 +119,464 @@ func TestLoadClientCredentials(t *testing.T) {
 		t.Fatalf("Cannot remove test certificates: %v", err)
 	}
 }
+
+func TestMutualTLS(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	utils.LookupIP = func(host string) ([]net.IP, error) {
+		return nil, nil
+	}
+	defer func() { utils.LookupIP = net.LookupIP }()
+
+	createCerts := func(t *testing.T, host string) (string, *utils.CertificateAuthority) {
+		t.Helper()
+
+		dir := t.TempDir()
+		ca, err := utils.CreateCertificateAuthority("test CA", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = ca.Write(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = ca.IssueHostCertificates(host, true, 5, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return dir, ca
+	}
+
+	credsFor := func(dir string, caDir string) utils.GpCredentials {
+		return utils.GpCredentials{
+			CACertPath:     filepath.Join(caDir, constants.CACertFileName),
+			CAKeyPath:      filepath.Join(caDir, constants.CAKeyFileName),
+			ServerCertPath: filepath.Join(dir, constants.ServerCertFileName),
+			ServerKeyPath:  filepath.Join(dir, constants.ServerKeyFileName),
+			ClientCertPath: filepath.Join(dir, constants.ClientCertFileName),
+			ClientKeyPath:  filepath.Join(dir, constants.ClientKeyFileName),
+		}
+	}
+
+	// connectTo dials the server as the authority, and returns the handshake errors along with the common names of the certificates presented by the peers
+	connectTo := func(t *testing.T, server, client credentials.TransportCredentials, authority string) (serverErr error, clientErr error, serverCN string, clientCN string) {
+		t.Helper()
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer listener.Close()
+
+		serverErrChan := make(chan error, 1)
+		go func() {
+			serverConn, err := listener.Accept()
+			if err != nil {
+				serverErrChan <- err
+				return
+			}
+
+			conn, authInfo, err := server.ServerHandshake(serverConn)
+			if err == nil {
+				clientCN = authInfo.(credentials.TLSInfo).State.PeerCertificates[0].Subject.CommonName
+				_, err = conn.Write([]byte("x"))
+			}
+			serverConn.Close()
+			serverErrChan <- err
+		}()
+
+		clientConn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, authInfo, clientErr := client.ClientHandshake(ctx, authority, clientConn)
+		if clientErr == nil {
+			serverCN = authInfo.(credentials.TLSInfo).State.PeerCertificates[0].Subject.CommonName
+			// the server verifies the client certificate after the client side of the handshake completes in TLS 1.3
+			_, clientErr = conn.Read(make([]byte, 1))
+		}
+		clientConn.Close()
+
+		return <-serverErrChan, clientErr, serverCN, clientCN
+	}
+
+	connect := func(t *testing.T, server, client credentials.TransportCredentials) (serverErr error, clientErr error, serverCN string, clientCN string) {
+		t.Helper()
+
+		return connectTo(t, server, client, "localhost")
+	}
+
+	load := func(t *testing.T, serverCreds, clientCreds utils.GpCredentials) (credentials.TransportCredentials, credentials.TransportCredentials) {
+		t.Helper()
+
+		server, err := serverCreds.LoadServerCredentials()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		client, err := clientCreds.LoadClientCredentials()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return server, client
+	}
+
+	handshake := func(t *testing.T, serverCreds, clientCreds utils.GpCredentials) (error, error) {
+		t.Helper()
+
+		server, client := load(t, serverCreds, clientCreds)
+		serverErr, clientErr, _, _ := connect(t, server, client)
+
+		return serverErr, clientErr
+	}
+
+	// touch marks the files as modified so that the change is detected regardless of the timestamp granularity
+	touch := func(t *testing.T, paths ...string) {
+		t.Helper()
+
+		modTime := time.Now().Add(time.Minute)
+		for _, path := range paths {
+			err := os.Chtimes(path, modTime, modTime)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+
+	t.Run("accepts clients with a certificate signed by the CA", func(t *testing.T) {
+		dir, _ := createCerts(t, "cdw")
+		creds := credsFor(dir, dir)
+
+		serverErr, clientErr := handshake(t, creds, creds)
+		if serverErr != nil {
+			t.Fatalf("unexpected error: %v", serverErr)
+		}
+		if clientErr != nil {
+			t.Fatalf("unexpected error: %v", clientErr)
+		}
+	})
+
+	t.Run("rejects clients with a certificate signed by another CA", func(t *testing.T) {
+		serverDir, _ := createCerts(t, "cdw")
+		clientDir, _ := createCerts(t, "rogue")
+
+		serverCreds := credsFor(serverDir, serverDir)
+		// the client trusts the server CA but presents a certificate signed by its own CA
+		clientCreds := credsFor(clientDir, clientDir)
+		clientCreds.CACertPath = serverCreds.CACertPath
+
+		_, err := clientCreds.LoadClientCredentials()
+		expected := "client certificate " + clientCreds.ClientCertPath + " is not valid for the CA " + serverCreds.CACertPath
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+
+		// trust both the CAs on the client to check that the server rejects the certificate
+		serverCA, err := os.ReadFile(serverCreds.CACertPath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clientCA, err := os.ReadFile(filepath.Join(clientDir, constants.CACertFileName))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bothCAs := filepath.Join(t.TempDir(), "cas.pem")
+		err = os.WriteFile(bothCAs, append(serverCA, clientCA...), 0644)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		clientCreds.CACertPath = bothCAs
+
+		serverErr, clientErr := handshake(t, serverCreds, clientCreds)
+		if serverErr == nil || !strings.Contains(serverErr.Error(), "certificate signed by unknown authority") {
+			t.Fatalf("got %v, want certificate signed by unknown authority", serverErr)
+		}
+		if clientErr == nil || !strings.Contains(clientErr.Error(), "tls: unknown certificate authority") {
+			t.Fatalf("got %v, want tls: unknown certificate authority", clientErr)
+		}
+	})
+
+	t.Run("uses the rotated certificates without reloading the credentials", func(t *testing.T) {
+		dir, ca := createCerts(t, "cdw")
+		creds := credsFor(dir, dir)
+		server, client := load(t, creds, creds)
+
+		serverErr, clientErr, serverCN, clientCN := connect(t, server, client)
+		if serverErr != nil || clientErr != nil {
+			t.Fatalf("unexpected error: %v, %v", serverErr, clientErr)
+		}
+		if serverCN != "cdw" || clientCN != "cdw" {
+			t.Fatalf("got %s and %s, want cdw", serverCN, clientCN)
+		}
+
+		err := ca.IssueHostCertificates("cdw-rotated", true, 5, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		touch(t, creds.ServerCertPath, creds.ServerKeyPath, creds.ClientCertPath, creds.ClientKeyPath)
+
+		serverErr, clientErr, serverCN, clientCN = connect(t, server, client)
+		if serverErr != nil || clientErr != nil {
+			t.Fatalf("unexpected error: %v, %v", serverErr, clientErr)
+		}
+		if serverCN != "cdw-rotated" || clientCN != "cdw-rotated" {
+			t.Fatalf("got %s and %s, want cdw-rotated", serverCN, clientCN)
+		}
+	})
+
+	t.Run("trusts the rotated CA without reloading the credentials", func(t *testing.T) {
+		dir, _ := createCerts(t, "cdw")
+		creds := credsFor(dir, dir)
+		server, client := load(t, creds, creds)
+
+		ca, err := utils.CreateCertificateAuthority("rotated CA", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = ca.Write(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = ca.IssueHostCertificates("cdw-rotated", true, 5, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		touch(t, creds.CACertPath, creds.ServerCertPath, creds.ServerKeyPath, creds.ClientCertPath, creds.ClientKeyPath)
+
+		serverErr, clientErr, serverCN, clientCN := connect(t, server, client)
+		if serverErr != nil || clientErr != nil {
+			t.Fatalf("unexpected error: %v, %v", serverErr, clientErr)
+		}
+		if serverCN != "cdw-rotated" || clientCN != "cdw-rotated" {
+			t.Fatalf("got %s and %s, want cdw-rotated", serverCN, clientCN)
+		}
+	})
+
+	t.Run("rejects servers whose certificate is not valid for the host", func(t *testing.T) {
+		dir, _ := createCerts(t, "cdw")
+		creds := credsFor(dir, dir)
+		server, client := load(t, creds, creds)
+
+		_, clientErr, _, _ := connectTo(t, server, client, "sdw1:8000")
+		expected := "certificate is valid for cdw, localhost, not sdw1"
+		if clientErr == nil || !strings.Contains(clientErr.Error(), expected) {
+			t.Fatalf("got %v, want %s", clientErr, expected)
+		}
+	})
+
+	t.Run("rejects servers whose certificate is not valid for the IP address dialed", func(t *testing.T) {
+		utils.LookupIP = func(host string) ([]net.IP, error) {
+			return []net.IP{net.ParseIP("10.0.0.2")}, nil
+		}
+		dir, _ := createCerts(t, "sdw2")
+		utils.LookupIP = func(host string) ([]net.IP, error) {
+			return nil, nil
+		}
+
+		creds := credsFor(dir, dir)
+		server, client := load(t, creds, creds)
+
+		_, clientErr, _, _ := connectTo(t, server, client, "10.0.0.1:8000")
+		expected := "certificate is valid for 10.0.0.2"
+		if clientErr == nil || !strings.Contains(clientErr.Error(), expected) || !strings.Contains(clientErr.Error(), "not 10.0.0.1") {
+			t.Fatalf("got %v, want %s, not 10.0.0.1", clientErr, expected)
+		}
+
+		serverErr, clientErr, serverCN, _ := connectTo(t, server, client, "10.0.0.2:8000")
+		if serverErr != nil || clientErr != nil {
+			t.Fatalf("unexpected error: %v, %v", serverErr, clientErr)
+		}
+		if serverCN != "sdw2" {
+			t.Fatalf("got %s, want sdw2", serverCN)
+		}
+	})
+
+	t.Run("keeps using the previous certificate when the rotated certificate is not valid", func(t *testing.T) {
+		_, _, logfile := testhelper.SetupTestLogger()
+
+		dir, _ := createCerts(t, "cdw")
+		otherDir, _ := createCerts(t, "other")
+		creds := credsFor(dir, dir)
+		server, client := load(t, creds, creds)
+
+		// replace the server certificate with one signed by another CA
+		for _, name := range []string{constants.ServerCertFileName, constants.ServerKeyFileName} {
+			contents, err := os.ReadFile(filepath.Join(otherDir, name))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = os.WriteFile(filepath.Join(dir, name), contents, 0600)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		touch(t, creds.ServerCertPath, creds.ServerKeyPath)
+
+		serverErr, clientErr, serverCN, _ := connect(t, server, client)
+		if serverErr != nil || clientErr != nil {
+			t.Fatalf("unexpected error: %v, %v", serverErr, clientErr)
+		}
+		if serverCN != "cdw" {
+			t.Fatalf("got %s, want cdw", serverCN)
+		}
+		testutils.AssertLogMessage(t, logfile, "failed to reload .*, continuing to use the previous version: server certificate .* is not valid for the CA")
+	})
+
+	t.Run("uses the server key pair as the client key pair when not provided", func(t *testing.T) {
+		dir, _ := createCerts(t, "cdw")
+		creds := credsFor(dir, dir)
+		creds.ClientCertPath = ""
+		creds.ClientKeyPath = ""
+
+		_, err := creds.LoadClientCredentials()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("errors out when the server certificate is not signed by the CA", func(t *testing.T) {
+		dir, _ := createCerts(t, "cdw")
+		otherDir, _ := createCerts(t, "cdw")
+		creds := credsFor(dir, otherDir)
+
+		_, err := creds.LoadServerCredentials()
+		expected := "server certificate " + creds.ServerCertPath + " is not valid for the CA " + creds.CACertPath
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when the client certificate can not be used as a server certificate", func(t *testing.T) {
+		dir, _ := createCerts(t, "cdw")
+		creds := credsFor(dir, dir)
+		creds.ServerCertPath = creds.ClientCertPath
+		creds.ServerKeyPath = creds.ClientKeyPath
+
+		_, err := creds.LoadServerCredentials()
+		var expectedErr x509.CertificateInvalidError
+		if !errors.As(err, &expectedErr) || expectedErr.Reason != x509.IncompatibleUsage {
+			t.Fatalf("got %v, want %v", err, x509.IncompatibleUsage)
+		}
+	})
+}
+
+func TestClientAuthorizer(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	cert := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "cdw"},
+		DNSNames:    []string{"cdw.example.com", "localhost"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	}
+	ctxWithCert := func(cert *x509.Certificate) context.Context {
+		state := tls.ConnectionState{}
+		if cert != nil {
+			state.PeerCertificates = []*x509.Certificate{cert}
+		}
+
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "reply", nil
+	}
+
+	cases := []struct {
+		name    string
+		allowed []string
+	}{
+		{"allows any client when no clients are pinned", nil},
+		{"allows the client matching the common name", []string{"cdw"}},
+		{"allows the client matching a DNS name", []string{"sdw1", "cdw.example.com"}},
+		{"allows the client matching an IP address", []string{"10.0.0.1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			unary, _ := utils.NewClientAuthorizer("agent", tc.allowed)
+
+			reply, err := unary(ctxWithCert(cert), nil, &grpc.UnaryServerInfo{}, handler)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if reply != "reply" {
+				t.Fatalf("got %v, want reply", reply)
+			}
+		})
+	}
+
+	t.Run("denies the clients which are not pinned", func(t *testing.T) {
+		unary, stream := utils.NewClientAuthorizer("agent", []string{"sdw1"})
+
+		_, err := unary(ctxWithCert(cert), nil, &grpc.UnaryServerInfo{}, handler)
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("got %v, want %v", status.Code(err), codes.PermissionDenied)
+		}
+
+		expected := `client certificate "cdw" is not allowed to access the agent, allowed clients are [sdw1]`
+		if status.Convert(err).Message() != expected {
+			t.Fatalf("got %q, want %q", status.Convert(err).Message(), expected)
+		}
+
+		err = stream(nil, contextStream{ctx: ctxWithCert(cert)}, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			t.Fatalf("unexpected call to the handler")
+			return nil
+		})
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("got %v, want %v", status.Code(err), codes.PermissionDenied)
+		}
+	})
+
+	t.Run("denies the clients without a certificate when clients are pinned", func(t *testing.T) {
+		unary, _ := utils.NewClientAuthorizer("hub", []string{"cdw"})
+
+		_, err := unary(ctxWithCert(nil), nil, &grpc.UnaryServerInfo{}, handler)
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("got %v, want %v", status.Code(err), codes.Unauthenticated)
+		}
+	})
+}
+
+func TestGetCertificateExpiry(t *testing.T) {
+	t.Run("returns the expiry of the server certificate", func(t *testing.T) {
+		dir := t.TempDir()
+		ca, err := utils.CreateCertificateAuthority("test CA", 10)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err = ca.IssueHostCertificates("localhost", true, 5, dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		creds := utils.GpCredentials{ServerCertPath: filepath.Join(dir, constants.ServerCertFileName)}
+		expiry, err := creds.GetCertificateExpiry()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cert, err := utils.ReadCertificate(creds.ServerCertPath)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !expiry.Equal(cert.NotAfter) {
+			t.Fatalf("got %s, want %s", expiry, cert.NotAfter)
+		}
+	})
+
+	t.Run("errors out when the certificate can not be read", func(t *testing.T) {
+		creds := utils.GpCredentials{ServerCertPath: filepath.Join(t.TempDir(), constants.ServerCertFileName)}
+		_, err := creds.GetCertificateExpiry()
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("got %v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
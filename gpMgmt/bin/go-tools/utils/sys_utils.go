@@ -31,6 +31,10 @@ type SystemFunctions struct {
 	RemoveAll      func(path string) error
 	ReadFile       func(name string) ([]byte, error)
 	GetHostName    func() (name string, err error)
+	MkdirAll       func(path string, perm os.FileMode) error
+	MkdirTemp      func(dir string, pattern string) (string, error)
+	Chmod          func(name string, mode os.FileMode) error
+	Rename         func(oldpath string, newpath string) error
 }
 
 func InitializeSystemFunctions() *SystemFunctions {
@@ -48,6 +52,10 @@ func InitializeSystemFunctions() *SystemFunctions {
 		RemoveAll:      os.RemoveAll,
 		ReadFile:       os.ReadFile,
 		GetHostName:    os.Hostname,
+		MkdirAll:       os.MkdirAll,
+		MkdirTemp:      os.MkdirTemp,
+		Chmod:          os.Chmod,
+		Rename:         os.Rename,
 	}
 }
 
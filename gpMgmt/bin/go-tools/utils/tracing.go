@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+const (
+	TracingExporterOTLP = "otlp"
+	TracingExporterFile = "file"
+
+	tracerName = "github.com/greenplum-db/gpdb/gp"
+)
+
+// TracingConfig configures where the traces of the CLI, the hub and the agents are exported
+type TracingConfig struct {
+	Exporter string `json:"exporter"`           // "otlp" or "file"
+	Endpoint string `json:"endpoint,omitempty"` // host:port of the OTLP gRPC collector
+	Insecure bool   `json:"insecure,omitempty"` // connect to the collector without TLS
+}
+
+func (c *TracingConfig) Validate() error {
+	switch c.Exporter {
+	case TracingExporterOTLP:
+		if c.Endpoint == "" {
+			return fmt.Errorf("an endpoint is required to export the traces using %s", TracingExporterOTLP)
+		}
+	case TracingExporterFile:
+		if c.Endpoint != "" {
+			return fmt.Errorf("an endpoint can not be used to export the traces to a file")
+		}
+	default:
+		return fmt.Errorf("invalid trace exporter %q, expected %q or %q", c.Exporter, TracingExporterOTLP, TracingExporterFile)
+	}
+
+	return nil
+}
+
+/*
+InitTracing sets up the tracer provider of the service. The spans are exported
+to the OTLP collector or appended as JSON to the given file, depending on the
+configuration. The trace context is propagated through the gRPC metadata even
+when the service does not export any spans itself, so that the trace is not
+broken between the CLI, the hub and the agents. The returned function flushes
+the pending spans and is expected to be called before the service exits.
+*/
+func InitTracing(conf *TracingConfig, service string, filename string) (func(), error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if conf == nil || conf.Exporter == "" {
+		return func() {}, nil
+	}
+
+	err := conf.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	switch conf.Exporter {
+	case TracingExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(conf.Endpoint)}
+		if conf.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		// does not block, the connection to the collector is established when exporting
+		exporter, err = otlptracegrpc.New(context.Background(), opts...)
+	case TracingExporterFile:
+		file, err = System.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("could not open the traces file %s: %w", filename, err)
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	}
+	if err != nil {
+		if file != nil {
+			file.Close()
+		}
+		return nil, fmt.Errorf("could not create the trace exporter: %w", err)
+	}
+
+	hostname, _ := System.GetHostName()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(fmt.Sprintf("gp-%s", service)),
+			semconv.HostName(hostname),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	shutdown := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := provider.Shutdown(ctx)
+		if err != nil {
+			gplog.Warn("failed to export the pending traces: %v", err)
+		}
+
+		if file != nil {
+			file.Close()
+		}
+	}
+
+	return shutdown, nil
+}
+
+// StartSpan starts a span as a child of the span in the context, if any
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan marks the span as failed if there is an error and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// GetTraceID returns the ID of the trace in the context, or an empty string if there is none
+func GetTraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+
+	return spanContext.TraceID().String()
+}
+
+/*
+NewTracingInterceptors returns the server interceptors which continue the trace
+of the caller and create a span for each RPC. They are expected to be the first
+in the chain so that the other interceptors can refer to the trace.
+*/
+func NewTracingInterceptors() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	return otelgrpc.UnaryServerInterceptor(), otelgrpc.StreamServerInterceptor()
+}
+
+// TracingDialOptions returns the dial options which create a span for each RPC and propagate the trace to the server
+func TracingDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+	}
+}
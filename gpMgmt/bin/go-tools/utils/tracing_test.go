@@ -0,0 +1,232 @@
+package utils_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/greenplum-db/gp-common-go-libs/testhelper"
+	"github.com/greenplum-db/gpdb/gp/testutils/exectest"
+	"github.com/greenplum-db/gpdb/gp/utils"
+	"github.com/greenplum-db/gpdb/gp/utils/postgres"
+)
+
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		SpanID string
+	}
+	Status struct {
+		Code string
+	}
+}
+
+func readExportedSpans(t *testing.T, filename string) map[string]exportedSpan {
+	t.Helper()
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer file.Close()
+
+	spans := make(map[string]exportedSpan)
+	decoder := json.NewDecoder(file)
+	for {
+		var span exportedSpan
+		err := decoder.Decode(&span)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		spans[span.Name] = span
+	}
+
+	return spans
+}
+
+func TestTracingConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		conf     utils.TracingConfig
+		expected string
+	}{
+		{
+			name: "otlp exporter with an endpoint",
+			conf: utils.TracingConfig{Exporter: utils.TracingExporterOTLP, Endpoint: "collector:4317"},
+		},
+		{
+			name: "file exporter",
+			conf: utils.TracingConfig{Exporter: utils.TracingExporterFile},
+		},
+		{
+			name:     "otlp exporter without an endpoint",
+			conf:     utils.TracingConfig{Exporter: utils.TracingExporterOTLP},
+			expected: "an endpoint is required to export the traces using otlp",
+		},
+		{
+			name:     "file exporter with an endpoint",
+			conf:     utils.TracingConfig{Exporter: utils.TracingExporterFile, Endpoint: "collector:4317"},
+			expected: "an endpoint can not be used to export the traces to a file",
+		},
+		{
+			name:     "unknown exporter",
+			conf:     utils.TracingConfig{Exporter: "jaeger"},
+			expected: `invalid trace exporter "jaeger", expected "otlp" or "file"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.conf.Validate()
+			if tc.expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("got %v, want %s", err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestInitTracing(t *testing.T) {
+	testhelper.SetupTestLogger()
+
+	t.Run("does not export the spans when tracing is not configured", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "traces.json")
+
+		shutdown, err := utils.InitTracing(nil, "test", filename)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, span := utils.StartSpan(context.Background(), "span")
+		utils.EndSpan(span, nil)
+		shutdown()
+
+		_, err = os.Stat(filename)
+		if !os.IsNotExist(err) {
+			t.Fatalf("expected %s to not exist, got %v", filename, err)
+		}
+	})
+
+	t.Run("errors out when the configuration is not valid", func(t *testing.T) {
+		_, err := utils.InitTracing(&utils.TracingConfig{Exporter: "jaeger"}, "test", "")
+		expected := `invalid trace exporter "jaeger"`
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("errors out when not able to open the traces file", func(t *testing.T) {
+		utils.System.OpenFile = func(name string, flag int, perm os.FileMode) (*os.File, error) {
+			return nil, os.ErrPermission
+		}
+		defer utils.ResetSystemFunctions()
+
+		_, err := utils.InitTracing(&utils.TracingConfig{Exporter: utils.TracingExporterFile}, "test", "traces.json")
+		expected := "could not open the traces file traces.json: permission denied"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("got %v, want %s", err, expected)
+		}
+	})
+
+	t.Run("exports the spans of the commands as children of the caller span", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "traces.json")
+
+		shutdown, err := utils.InitTracing(&utils.TracingConfig{Exporter: utils.TracingExporterFile}, "test", filename)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ctx, span := utils.StartSpan(context.Background(), "Stage")
+		utils.System.ExecCommand = exectest.NewCommand(CommandFailure)
+		_, _ = utils.RunGpCommand(ctx, &postgres.PgCtlStatus{PgData: "pgdata"}, "gpHome")
+		utils.ResetSystemFunctions()
+		utils.EndSpan(span, nil)
+		shutdown()
+
+		spans := readExportedSpans(t, filename)
+		stage, command := spans["Stage"], spans["PgCtlStatus"]
+		if command.SpanContext.TraceID != stage.SpanContext.TraceID {
+			t.Fatalf("got trace %s, want %s", command.SpanContext.TraceID, stage.SpanContext.TraceID)
+		}
+		if command.Parent.SpanID != stage.SpanContext.SpanID {
+			t.Fatalf("got parent %s, want %s", command.Parent.SpanID, stage.SpanContext.SpanID)
+		}
+		if command.Status.Code != "Error" {
+			t.Fatalf("got status %s, want Error", command.Status.Code)
+		}
+		if stage.Status.Code != "Unset" {
+			t.Fatalf("got status %s, want Unset", stage.Status.Code)
+		}
+	})
+
+	t.Run("propagates the trace through the gRPC calls", func(t *testing.T) {
+		shutdown, err := utils.InitTracing(&utils.TracingConfig{Exporter: utils.TracingExporterFile}, "test", filepath.Join(t.TempDir(), "traces.json"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer shutdown()
+
+		var serverTraceID string
+		unaryTracing, streamTracing := utils.NewTracingInterceptors()
+		server := grpc.NewServer(
+			grpc.ChainUnaryInterceptor(unaryTracing, func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				serverTraceID = utils.GetTraceID(ctx)
+				return handler(ctx, req)
+			}),
+			grpc.ChainStreamInterceptor(streamTracing),
+		)
+		grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		go server.Serve(listener) // nolint
+		defer server.Stop()
+
+		opts := append(utils.TracingDialOptions(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.Dial(listener.Addr().String(), opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer conn.Close()
+
+		ctx, span := utils.StartSpan(context.Background(), "Caller")
+		defer utils.EndSpan(span, nil)
+
+		_, err = grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := utils.GetTraceID(ctx)
+		if expected == "" || serverTraceID != expected {
+			t.Fatalf("got trace %q, want %q", serverTraceID, expected)
+		}
+	})
+}